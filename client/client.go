@@ -0,0 +1,277 @@
+// Package client is a typed Go client for the TODO service's REST API.
+//
+// Every method takes a context and retries requests the server did not act
+// on: rate-limited and read-only (503) responses always, and for methods
+// that are safe to repeat, also network errors and 502 and 504 responses.
+// Retries wait for the Retry-After header when there is one, and back off
+// exponentially with jitter otherwise. Error responses are returned as
+// *Error.
+//
+//	c := client.New("http://localhost:8080")
+//	todo, err := c.CreateTodo(ctx, client.CreateTodoRequest{Title: "Buy milk"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for New.
+const (
+	DefaultRetries    = 5
+	DefaultMinBackoff = 250 * time.Millisecond
+	DefaultMaxBackoff = 8 * time.Second
+	DefaultTimeout    = 30 * time.Second
+)
+
+// actorHeader names who makes changes in the audit log; the server's
+// middleware.ActorHeader.
+const actorHeader = "X-Actor"
+
+// Client calls the TODO service's REST API. It is safe for concurrent use.
+type Client struct {
+	base       string
+	http       *http.Client
+	actor      string
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of a client with
+// DefaultTimeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithActor records actor as who made changes in the server's audit log.
+func WithActor(actor string) Option {
+	return func(c *Client) { c.actor = actor }
+}
+
+// WithRetries sets how many times a request is retried; 0 disables
+// retrying.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithBackoff sets the wait before the first retry without a Retry-After
+// header, doubled for each retry after it up to max.
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) { c.minBackoff, c.maxBackoff = min, max }
+}
+
+// New creates a Client for the server at baseURL, such as
+// http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		base:       strings.TrimSuffix(baseURL, "/"),
+		http:       &http.Client{Timeout: DefaultTimeout},
+		retries:    DefaultRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL of the server c calls.
+func (c *Client) BaseURL() string {
+	return c.base
+}
+
+// Error is an error response from the server.
+type Error struct {
+	Status int
+	// Code is the error's code from the API's catalog, or empty if the
+	// response did not come from the service, such as one from a proxy.
+	Code   ErrorCode
+	Detail string
+	// Errors lists each problem with the request, for validation failures.
+	Errors []ErrorDetail
+}
+
+// ErrorDetail is one problem with a request.
+type ErrorDetail struct {
+	Message  string `json:"message"`
+	Location string `json:"location"`
+	Value    any    `json:"value,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// IsCode reports whether err is an error response with code.
+func IsCode(err error, code ErrorCode) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == code
+}
+
+// Request is a request for Send.
+type Request struct {
+	Method string
+	// Path is the path and query after the base URL, such as
+	// /api/v1/todos?limit=10.
+	Path        string
+	ContentType string
+	Body        []byte
+	Header      http.Header
+}
+
+// Do sends a request with body, if it is not nil, encoded as JSON, and
+// decodes the JSON response into out, if it is not nil.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	return c.do(ctx, method, path, nil, body, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, header http.Header, body, out any) error {
+	req := Request{Method: method, Path: path, Header: header}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		req.Body, req.ContentType = data, "application/json"
+	}
+	resp, err := c.Send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// Send sends req, retrying it as described in the package documentation.
+// Error responses are returned as *Error; otherwise the caller must close
+// the response body.
+func (c *Client) Send(ctx context.Context, req Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if attempt >= c.retries || ctx.Err() != nil {
+			return checkResponse(resp, err)
+		}
+
+		wait := c.backoff(attempt)
+		switch {
+		case err != nil:
+			if !idempotent(req.Method) {
+				return nil, err
+			}
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+			if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+				wait = time.Duration(s) * time.Second
+			}
+			resp.Body.Close()
+		case (resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusGatewayTimeout) && idempotent(req.Method):
+			resp.Body.Close()
+		default:
+			return checkResponse(resp, nil)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req Request) (*http.Response, error) {
+	r, err := http.NewRequestWithContext(ctx, req.Method, c.base+req.Path, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	if req.ContentType != "" {
+		r.Header.Set("Content-Type", req.ContentType)
+	}
+	r.Header.Set("Accept", "application/json")
+	r.Header.Set("User-Agent", "todo-service-client")
+	if c.actor != "" {
+		r.Header.Set(actorHeader, c.actor)
+	}
+
+	resp, err := c.http.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("contact server: %w", err)
+	}
+	return resp, nil
+}
+
+// backoff returns how long to wait before retry attempt+1 when the server
+// does not say: minBackoff doubled attempt times, up to maxBackoff, less a
+// random amount of up to half.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.minBackoff << min(attempt, 30)
+	if d > c.maxBackoff || d <= 0 {
+		d = c.maxBackoff
+	}
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+// idempotent reports whether a request with method can safely be repeated
+// if it is unknown whether the server acted on it.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// checkResponse returns resp, or an *Error if it is an error response.
+func checkResponse(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 400 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var problem struct {
+		Detail  string        `json:"detail"`
+		Message string        `json:"message"`
+		Code    ErrorCode     `json:"code"`
+		Errors  []ErrorDetail `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	json.Unmarshal(data, &problem)
+	e := &Error{Status: resp.StatusCode, Code: problem.Code, Detail: problem.Detail, Errors: problem.Errors}
+	if e.Detail == "" {
+		e.Detail = problem.Message
+	}
+	if e.Detail == "" {
+		e.Detail = resp.Status
+	}
+	return nil, e
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListTodosOptions filters, sorts, and paginates ListTodos. Zero fields
+// are left to the server's defaults.
+type ListTodosOptions struct {
+	Status    Status
+	Category  Category
+	ProjectID int64
+	// Assignee lists TODOs assigned to someone; me lists the caller's, as
+	// named by WithActor.
+	Assignee string
+	// Archived lists archived TODOs instead of active ones.
+	Archived bool
+	// Pinned lists only pinned TODOs.
+	Pinned bool
+	// Snoozed lists snoozed TODOs instead of awake ones.
+	Snoozed bool
+	// CustomFields lists TODOs whose custom fields hold these values, keyed
+	// by field name.
+	CustomFields map[string]string
+	// Near lists TODOs located within RadiusKm of its latitude and
+	// longitude, or the server's default radius if RadiusKm is 0.
+	Near          *Location
+	RadiusKm      float64
+	CompletedFrom time.Time
+	CompletedTo   time.Time
+	// Limit of 0 lists every match, if there are at most 5000.
+	Limit  int
+	Offset int
+	// Sort is comma-separated fields, each prefixed with - for descending
+	// order, such as -created_at,id.
+	Sort string
+}
+
+func (o ListTodosOptions) query() url.Values {
+	q := url.Values{}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.Category != "" {
+		q.Set("category", string(o.Category))
+	}
+	if o.ProjectID != 0 {
+		q.Set("project_id", strconv.FormatInt(o.ProjectID, 10))
+	}
+	if o.Assignee != "" {
+		q.Set("assignee", o.Assignee)
+	}
+	if o.Archived {
+		q.Set("archived", "true")
+	}
+	if o.Pinned {
+		q.Set("pinned", "true")
+	}
+	if o.Snoozed {
+		q.Set("snoozed", "true")
+	}
+	for name, v := range o.CustomFields {
+		q.Set("custom_fields["+name+"]", v)
+	}
+	if o.Near != nil {
+		q.Set("near", strconv.FormatFloat(o.Near.Latitude, 'f', -1, 64)+","+strconv.FormatFloat(o.Near.Longitude, 'f', -1, 64))
+	}
+	if o.RadiusKm > 0 {
+		q.Set("radius_km", strconv.FormatFloat(o.RadiusKm, 'f', -1, 64))
+	}
+	if !o.CompletedFrom.IsZero() {
+		q.Set("completed_from", o.CompletedFrom.Format(time.RFC3339))
+	}
+	if !o.CompletedTo.IsZero() {
+		q.Set("completed_to", o.CompletedTo.Format(time.RFC3339))
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	return q
+}
+
+// ListTodos lists a page of TODOs.
+func (c *Client) ListTodos(ctx context.Context, opts ListTodosOptions) (TodoListResponse, error) {
+	var resp TodoListResponse
+	err := c.Do(ctx, http.MethodGet, "/api/v1/todos?"+opts.query().Encode(), nil, &resp)
+	return resp, err
+}
+
+// GetTodo retrieves a TODO by ID.
+func (c *Client) GetTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodGet, todoPath(id, ""), nil, nil)
+}
+
+// CreateTodo creates a TODO.
+func (c *Client) CreateTodo(ctx context.Context, req CreateTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, "/api/v1/todos", nil, req)
+}
+
+// UpdateTodo changes the fields of a TODO that req sets. If version is not
+// 0, the update fails with CodeConflictStale unless the TODO is still at
+// that version; 0 updates it unconditionally.
+func (c *Client) UpdateTodo(ctx context.Context, id, version int64, req UpdateTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPut, todoPath(id, ""), ifMatch(version), req)
+}
+
+// DeleteTodo deletes a TODO. version works as in UpdateTodo.
+func (c *Client) DeleteTodo(ctx context.Context, id, version int64) error {
+	return c.do(ctx, http.MethodDelete, todoPath(id, ""), ifMatch(version), nil, nil)
+}
+
+// RestoreTodo takes a deleted TODO out of the trash.
+func (c *Client) RestoreTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, "/api/v1/trash/"+strconv.FormatInt(id, 10)+"/restore", nil, nil)
+}
+
+// CompleteTodo marks a TODO done.
+func (c *Client) CompleteTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "complete"), nil, nil)
+}
+
+// ReopenTodo marks a done TODO pending again.
+func (c *Client) ReopenTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "reopen"), nil, nil)
+}
+
+// ArchiveTodo hides a TODO from default listings.
+func (c *Client) ArchiveTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "archive"), nil, nil)
+}
+
+// UnarchiveTodo returns an archived TODO to default listings.
+func (c *Client) UnarchiveTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unarchive"), nil, nil)
+}
+
+// ScheduleTodo plans a TODO for a day, formatted with DateLayout.
+func (c *Client) ScheduleTodo(ctx context.Context, id int64, date string) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "schedule"), nil, ScheduleTodoRequest{Date: date})
+}
+
+// UnscheduleTodo removes a TODO's planned day.
+func (c *Client) UnscheduleTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unschedule"), nil, nil)
+}
+
+// AssignTodo assigns a TODO to assignee, or with me, to the caller.
+func (c *Client) AssignTodo(ctx context.Context, id int64, assignee string) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "assign"), nil, AssignTodoRequest{Assignee: assignee})
+}
+
+// UnassignTodo removes a TODO's assignee.
+func (c *Client) UnassignTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unassign"), nil, nil)
+}
+
+// SnoozeTodo hides a TODO from default listings until it wakes up.
+func (c *Client) SnoozeTodo(ctx context.Context, id int64, req SnoozeTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "snooze"), nil, req)
+}
+
+// UnsnoozeTodo wakes a snoozed TODO now.
+func (c *Client) UnsnoozeTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unsnooze"), nil, nil)
+}
+
+// LocateTodo ties a TODO to a place.
+func (c *Client) LocateTodo(ctx context.Context, id int64, loc Location) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "locate"), nil, loc)
+}
+
+// UnlocateTodo removes a TODO's location.
+func (c *Client) UnlocateTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unlocate"), nil, nil)
+}
+
+// PinTodo pins a TODO so that it is listed before all unpinned ones.
+func (c *Client) PinTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "pin"), nil, nil)
+}
+
+// UnpinTodo unpins a TODO.
+func (c *Client) UnpinTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "unpin"), nil, nil)
+}
+
+// MoveTodo changes a TODO's place in the manual order.
+func (c *Client) MoveTodo(ctx context.Context, id int64, req MoveTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "move"), nil, req)
+}
+
+// DuplicateTodo creates a pending copy of a TODO.
+func (c *Client) DuplicateTodo(ctx context.Context, id int64) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "duplicate"), nil, nil)
+}
+
+// CaptureTodo adds a TODO to the inbox to be triaged later.
+func (c *Client) CaptureTodo(ctx context.Context, req CaptureTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, "/api/v1/inbox", nil, req)
+}
+
+// TriageTodo sorts a TODO out of the inbox.
+func (c *Client) TriageTodo(ctx context.Context, id int64, req TriageTodoRequest) (Todo, error) {
+	return c.todo(ctx, http.MethodPost, todoPath(id, "triage"), nil, req)
+}
+
+// Undo reverses the most recent change the client made to TODOs, as
+// identified by the X-Actor header or else its address. Each call undoes one
+// more change.
+func (c *Client) Undo(ctx context.Context) (UndoResponse, error) {
+	var resp UndoResponse
+	err := c.Do(ctx, http.MethodPost, "/api/v1/undo", nil, &resp)
+	return resp, err
+}
+
+// ListProjects lists a page of projects, sorted by sort if it is not
+// empty. A limit of 0 lists every project, if there are at most 5000.
+func (c *Client) ListProjects(ctx context.Context, limit, offset int, sort string) (ProjectListResponse, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	if sort != "" {
+		q.Set("sort", sort)
+	}
+	var resp ProjectListResponse
+	err := c.Do(ctx, http.MethodGet, "/api/v1/projects?"+q.Encode(), nil, &resp)
+	return resp, err
+}
+
+// ExportConfig returns the server's categories, projects, views, and other
+// configuration as a YAML manifest.
+func (c *Client) ExportConfig(ctx context.Context) ([]byte, error) {
+	resp, err := c.Send(ctx, Request{Method: http.MethodGet, Path: "/api/v1/admin/config"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return data, nil
+}
+
+// ApplyConfig makes the server's configuration match a YAML manifest, or
+// with dryRun, reports the changes it would make.
+func (c *Client) ApplyConfig(ctx context.Context, manifest []byte, dryRun bool) (ApplyConfigResponse, error) {
+	path := "/api/v1/admin/config"
+	if dryRun {
+		path += "?dry_run=true"
+	}
+
+	var result ApplyConfigResponse
+	resp, err := c.Send(ctx, Request{Method: http.MethodPut, Path: path, ContentType: "application/yaml", Body: manifest})
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("decode response: %w", err)
+	}
+	return result, nil
+}
+
+// todo sends a request that responds with a single TODO.
+func (c *Client) todo(ctx context.Context, method, path string, header http.Header, body any) (Todo, error) {
+	var todo Todo
+	err := c.do(ctx, method, path, header, body, &todo)
+	return todo, err
+}
+
+func todoPath(id int64, action string) string {
+	path := "/api/v1/todos/" + strconv.FormatInt(id, 10)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// ifMatch returns the If-Match header requiring version, or any version if
+// it is 0.
+func ifMatch(version int64) http.Header {
+	if version == 0 {
+		return http.Header{"If-Match": {"*"}}
+	}
+	return http.Header{"If-Match": {fmt.Sprintf(`"%d"`, version)}}
+}
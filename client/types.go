@@ -0,0 +1,58 @@
+package client
+
+import "todo-service/internal/model"
+
+// The API's payloads, shared with the server so that they cannot drift
+// apart.
+type (
+	Todo                = model.Todo
+	TodoListResponse    = model.TodoListResponse
+	CreateTodoRequest   = model.CreateTodoRequest
+	UpdateTodoRequest   = model.UpdateTodoRequest
+	ScheduleTodoRequest = model.ScheduleTodoRequest
+	AssignTodoRequest   = model.AssignTodoRequest
+	SnoozeTodoRequest   = model.SnoozeTodoRequest
+	Location            = model.Location
+	MoveTodoRequest     = model.MoveTodoRequest
+	CaptureTodoRequest  = model.CaptureTodoRequest
+	TriageTodoRequest   = model.TriageTodoRequest
+	UndoResponse        = model.UndoResponse
+	Status              = model.Status
+	Category            = model.Category
+	Triage              = model.Triage
+
+	Project             = model.Project
+	ProjectProgress     = model.ProjectProgress
+	ProjectListResponse = model.ProjectListResponse
+
+	ApplyConfigResponse = model.ApplyConfigResponse
+
+	ErrorCode = model.ErrorCode
+)
+
+// TODO statuses.
+const (
+	StatusPending    = model.StatusPending
+	StatusInProgress = model.StatusInProgress
+	StatusDone       = model.StatusDone
+)
+
+// Error codes; see the API description for what each means.
+const (
+	CodeValidationFailed     = model.CodeValidationFailed
+	CodeTodoNotFound         = model.CodeTodoNotFound
+	CodeNotFound             = model.CodeNotFound
+	CodeConflict             = model.CodeConflict
+	CodeConflictStale        = model.CodeConflictStale
+	CodePreconditionRequired = model.CodePreconditionRequired
+	CodeBodyTooLarge         = model.CodeBodyTooLarge
+	CodeRateLimited          = model.CodeRateLimited
+	CodeBadRequest           = model.CodeBadRequest
+	CodeReadOnly             = model.CodeReadOnly
+	CodeUpstreamFailed       = model.CodeUpstreamFailed
+	CodeInternal             = model.CodeInternal
+)
+
+// DateLayout is the format of calendar dates, such as a TODO's scheduled
+// day.
+const DateLayout = model.DateLayout
@@ -0,0 +1,159 @@
+// Package agenda renders a day's scheduled TODOs as plain text for
+// terminals, screen readers, and small displays. The output is ASCII apart
+// from localized words and TODO titles, and wraps at a fixed width.
+package agenda
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-service/internal/model"
+)
+
+// Width limits, in characters.
+const (
+	DefaultWidth = 80
+	MinWidth     = 20
+	MaxWidth     = 200
+)
+
+// Agenda is what is due on Date, a day in UTC.
+type Agenda struct {
+	Date time.Time
+	// Overdue holds open TODOs scheduled before Date.
+	Overdue []model.Todo
+	// Today holds the TODOs scheduled for Date, done or not.
+	Today []model.Todo
+	// Upcoming holds TODOs scheduled after Date.
+	Upcoming []model.Todo
+}
+
+// Render writes a as plain text wrapped at width characters, with dates and
+// headings in locale, which must be one of Locales.
+func Render(w io.Writer, a Agenda, width int, locale string) error {
+	lines, err := Lines(a, width, locale)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// Lines returns the lines Render writes, without line endings.
+func Lines(a Agenda, width int, locale string) ([]string, error) {
+	l, ok := locales[locale]
+	if !ok {
+		return nil, fmt.Errorf("unknown locale %q", locale)
+	}
+	r := renderer{width: width, l: l}
+
+	r.wrap("", l.title(a.Date))
+	underline := 0
+	for _, line := range r.lines {
+		underline = max(underline, utf8.RuneCountInString(line))
+	}
+	r.line(strings.Repeat("=", underline))
+	r.section(l.overdue, a.Overdue, true)
+	r.section(l.today, a.Today, false)
+	r.section(l.upcoming, a.Upcoming, true)
+
+	return r.lines, nil
+}
+
+type renderer struct {
+	width int
+	l     locale
+	lines []string
+}
+
+func (r *renderer) line(s string) {
+	r.lines = append(r.lines, s)
+}
+
+// section writes a heading and one entry per TODO, prefixed with its
+// scheduled day if dated is set.
+func (r *renderer) section(heading string, todos []model.Todo, dated bool) {
+	r.line("")
+	r.line(fmt.Sprintf("%s (%d)", strings.ToUpper(heading), len(todos)))
+	if len(todos) == 0 {
+		r.wrap("  ", r.l.nothing)
+		return
+	}
+
+	days := make([]string, len(todos))
+	dayWidth := 0
+	if dated {
+		for i, t := range todos {
+			if d, err := time.Parse(model.DateLayout, *t.ScheduledFor); err == nil {
+				days[i] = r.l.short(d)
+			}
+			dayWidth = max(dayWidth, utf8.RuneCountInString(days[i]))
+		}
+	}
+
+	for i, t := range todos {
+		prefix := "  " + mark(t.Status) + " "
+		if dated {
+			prefix += pad(days[i], dayWidth) + "  "
+		}
+		r.wrap(prefix, t.Title)
+	}
+}
+
+// wrap writes text after prefix, wrapping it at word boundaries and
+// indenting continuation lines to line up with the first. Words longer than
+// a line are broken. If prefix takes up more than half the line, text starts
+// on the next line instead.
+func (r *renderer) wrap(prefix, text string) {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	if len(indent) > r.width/2 {
+		r.line(strings.TrimRight(prefix, " "))
+		prefix, indent = "    ", "    "
+	}
+	avail := r.width - len(indent)
+
+	var line []rune
+	emit := func() {
+		r.line(prefix + string(line))
+		prefix = indent
+		line = line[:0]
+	}
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) > avail {
+			emit()
+		}
+		for len(w) > avail {
+			line = append(line, w[:avail]...)
+			w = w[avail:]
+			emit()
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, w...)
+	}
+	if len(line) > 0 || prefix != indent {
+		emit()
+	}
+}
+
+// mark shows a TODO's status in ASCII: [ ] pending, [~] in progress, and
+// [x] done.
+func mark(s model.Status) string {
+	switch s {
+	case model.StatusInProgress:
+		return "[~]"
+	case model.StatusDone:
+		return "[x]"
+	default:
+		return "[ ]"
+	}
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
@@ -0,0 +1,81 @@
+package agenda
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultLocale is used when no locale is requested.
+const DefaultLocale = "en"
+
+// Locales lists the supported locales.
+var Locales = func() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}()
+
+// locale holds the words and date formats of one language.
+type locale struct {
+	overdue, today, upcoming, nothing string
+
+	days        [7]string // full weekday names, from Sunday
+	shortDays   [7]string
+	months      [12]string // full month names, from January
+	shortMonths [12]string
+
+	// titleFormat and shortFormat are fmt formats taking the weekday, day
+	// of month, month, and year, in that order; short formats omit the year.
+	titleFormat, shortFormat string
+}
+
+func (l locale) title(d time.Time) string {
+	return fmt.Sprintf(l.titleFormat, l.days[d.Weekday()], d.Day(), l.months[d.Month()-1], d.Year())
+}
+
+func (l locale) short(d time.Time) string {
+	return fmt.Sprintf(l.shortFormat, l.shortDays[d.Weekday()], d.Day(), l.shortMonths[d.Month()-1])
+}
+
+var locales = map[string]locale{
+	"en": {
+		overdue: "Overdue", today: "Today", upcoming: "Upcoming", nothing: "Nothing scheduled.",
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		titleFormat: "Agenda for %s, %d %s %d",
+		shortFormat: "%s %d %s",
+	},
+	"de": {
+		overdue: "Überfällig", today: "Heute", upcoming: "Demnächst", nothing: "Nichts geplant.",
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sep.", "Okt.", "Nov.", "Dez."},
+		titleFormat: "Agenda für %s, %d. %s %d",
+		shortFormat: "%s %d. %s",
+	},
+	"es": {
+		overdue: "Atrasadas", today: "Hoy", upcoming: "Próximas", nothing: "Nada programado.",
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		titleFormat: "Agenda del %s, %d de %s de %d",
+		shortFormat: "%s %d %s",
+	},
+	"fr": {
+		overdue: "En retard", today: "Aujourd'hui", upcoming: "À venir", nothing: "Rien de prévu.",
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		titleFormat: "Agenda du %s %d %s %d",
+		shortFormat: "%s %d %s",
+	},
+}
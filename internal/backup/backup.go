@@ -0,0 +1,202 @@
+// Package backup keeps snapshots of the database in a directory.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+// DefaultDir is where backups are kept unless told otherwise.
+const DefaultDir = "./data/backups"
+
+// ErrNotFound is returned for a backup name that is not in the directory.
+var ErrNotFound = errors.New("backup not found")
+
+// Backup file names embed their UTC creation time, so they sort by age.
+const (
+	prefix     = "todos-"
+	suffix     = ".db"
+	timeLayout = "20060102T150405.000Z"
+)
+
+// Store writes snapshots of a repository to a directory and manages the
+// ones already there.
+type Store struct {
+	repo db.Repository
+	dir  string
+	mu   sync.Mutex // serializes Create, Restore, and Prune
+}
+
+// NewStore creates a Store that keeps backups of repo in dir, which is
+// created on the first backup.
+func NewStore(repo db.Repository, dir string) *Store {
+	return &Store{repo: repo, dir: dir}
+}
+
+// Create writes a snapshot of the database, named after the current time.
+// The snapshot is written to a temporary file first, so a failed or
+// interrupted backup is never listed.
+func (s *Store) Create() (model.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create()
+}
+
+func (s *Store) create() (model.Backup, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return model.Backup{}, fmt.Errorf("create backup directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	name := prefix + now.Format(timeLayout) + suffix
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+
+	if err := s.repo.Snapshot(tmp); err != nil {
+		os.Remove(tmp)
+		return model.Backup{}, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return model.Backup{}, fmt.Errorf("rename backup: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return model.Backup{}, fmt.Errorf("stat backup: %w", err)
+	}
+	return model.Backup{Name: name, SizeBytes: info.Size(), CreatedAt: now.Truncate(time.Millisecond)}, nil
+}
+
+// List returns the backups in the directory, newest first. A missing
+// directory has no backups.
+func (s *Store) List() ([]model.Backup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []model.Backup{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read backup directory: %w", err)
+	}
+
+	backups := []model.Backup{}
+	for _, e := range entries {
+		createdAt, ok := parseName(e.Name())
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue // pruned since the directory was read
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat backup %s: %w", e.Name(), err)
+		}
+		backups = append(backups, model.Backup{Name: e.Name(), SizeBytes: info.Size(), CreatedAt: createdAt})
+	}
+
+	slices.SortFunc(backups, func(a, b model.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// Path returns the path of the named backup, or ErrNotFound if name is not a
+// backup in the directory. Names that could refer to other files are
+// rejected as not found.
+func (s *Store) Path(name string) (string, error) {
+	if _, ok := parseName(name); !ok {
+		return "", ErrNotFound
+	}
+	path := filepath.Join(s.dir, name)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("stat backup: %w", err)
+	}
+	return path, nil
+}
+
+// Restore replaces the database with the named backup, or only reports what
+// that would change if dryRun is set. Unless it is a dry run, the current
+// database is backed up first so the restore can be undone. It returns
+// ErrNotFound for an unknown backup and an error wrapping
+// db.ErrInvalidSnapshot for one that cannot be restored.
+func (s *Store) Restore(name string, dryRun bool) (model.RestoreResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.Path(name)
+	if err != nil {
+		return model.RestoreResponse{}, err
+	}
+	version, tables, err := s.repo.CompareSnapshot(path)
+	if err != nil {
+		return model.RestoreResponse{}, err
+	}
+	res := model.RestoreResponse{Backup: name, DryRun: dryRun, SchemaVersion: version, Tables: tables}
+	if dryRun {
+		return res, nil
+	}
+
+	safety, err := s.create()
+	if err != nil {
+		return model.RestoreResponse{}, fmt.Errorf("back up current database: %w", err)
+	}
+	res.SafetyBackup = safety.Name
+	if err := s.repo.Restore(path); err != nil {
+		return model.RestoreResponse{}, err
+	}
+	return res, nil
+}
+
+// Prune deletes all but the newest keep backups and returns the names of
+// the deleted ones.
+func (s *Store) Prune(keep int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backups, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, b := range backups[min(keep, len(backups)):] {
+		if err := os.Remove(filepath.Join(s.dir, b.Name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, fmt.Errorf("delete backup %s: %w", b.Name, err)
+		}
+		deleted = append(deleted, b.Name)
+	}
+	return deleted, nil
+}
+
+// parseName reports whether name is a backup file name, and when the backup
+// was created.
+func parseName(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, suffix)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
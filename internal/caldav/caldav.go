@@ -0,0 +1,634 @@
+// Package caldav serves TODOs as the VTODOs of a single CalDAV calendar, so
+// native task apps can read and change them.
+package caldav
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/validate"
+)
+
+const (
+	// Prefix is the path the CalDAV server is served under. It is also the
+	// URL of the user's principal and calendar home.
+	Prefix = "/caldav/"
+
+	// calendarPath is the URL of the one calendar, which holds every
+	// unarchived TODO.
+	calendarPath = Prefix + "todos/"
+
+	// syncTokenPrefix starts sync tokens, which end with the ID of the
+	// newest audit log entry when they were issued.
+	syncTokenPrefix = "urn:todo-service:sync:"
+
+	calendarContentType = "text/calendar; charset=utf-8"
+)
+
+// defaultName matches the resource names of TODOs not created over CalDAV.
+var defaultName = regexp.MustCompile(`^todo-([0-9]+)\.ics$`)
+
+// Handler serves the CalDAV API to a single user, authenticated with HTTP
+// basic auth.
+type Handler struct {
+	repo     db.Repository
+	logger   *slog.Logger
+	user     string
+	password string
+}
+
+// New creates a Handler for the user with the given name and password.
+func New(repo db.Repository, logger *slog.Logger, user, password string) *Handler {
+	return &Handler{repo: repo, logger: logger, user: user, password: password}
+}
+
+// RegisterRoutes serves the CalDAV API under Prefix, and redirects the
+// well-known CalDAV URL clients discover it by to Prefix.
+func (h *Handler) RegisterRoutes(router chi.Router) {
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+	router.Handle(Prefix+"*", h)
+	router.Handle(strings.TrimSuffix(Prefix, "/"), h)
+	router.Handle("/.well-known/caldav", http.RedirectHandler(Prefix, http.StatusMovedPermanently))
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+	if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(h.user)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(h.password)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="todo-service", charset="UTF-8"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("DAV", "1, 3, calendar-access")
+
+	path := r.URL.Path
+	switch {
+	case path == Prefix || path+"/" == Prefix:
+		h.serveHome(w, r)
+	case path == calendarPath || path+"/" == calendarPath:
+		h.serveCalendar(w, r)
+	case strings.HasPrefix(path, calendarPath) && !strings.Contains(path[len(calendarPath):], "/"):
+		h.serveObject(w, r, path[len(calendarPath):])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// allow answers OPTIONS requests and rejects methods a resource does not
+// support, reporting whether the request was handled.
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	w.Header().Set("Allow", "OPTIONS, "+strings.Join(methods, ", "))
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return true
+	}
+	for _, m := range methods {
+		if r.Method == m {
+			return false
+		}
+	}
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return true
+}
+
+// depth returns the Depth header of a request, 0 or 1; infinity is served
+// as 1, which here reaches every resource anyway.
+func depth(r *http.Request) int {
+	if r.Header.Get("Depth") == "0" {
+		return 0
+	}
+	return 1
+}
+
+func (h *Handler) serveHome(w http.ResponseWriter, r *http.Request) {
+	if allow(w, r, "PROPFIND") {
+		return
+	}
+	var req propfind
+	if err := decodeBody(r, &req); err != nil {
+		http.Error(w, "invalid PROPFIND body", http.StatusBadRequest)
+		return
+	}
+
+	responses := []response{{href: Prefix, props: homeProps()}}
+	if depth(r) == 1 {
+		props, err := h.calendarProps()
+		if err != nil {
+			h.fail(w, r, err, "failed to read calendar")
+			return
+		}
+		responses = append(responses, response{href: calendarPath, props: props})
+	}
+	writeMultistatus(w, responses, req.Prop.names(), "")
+}
+
+func (h *Handler) serveCalendar(w http.ResponseWriter, r *http.Request) {
+	if allow(w, r, http.MethodGet, http.MethodHead, "PROPFIND", "REPORT") {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		objects, err := h.objects()
+		if err != nil {
+			h.fail(w, r, err, "failed to list todos")
+			return
+		}
+		todos := make([]model.Todo, len(objects))
+		uids := make(map[int64]string, len(objects))
+		for i, o := range objects {
+			todos[i], uids[o.todo.ID] = o.todo, o.uid
+		}
+		w.Header().Set("Content-Type", calendarContentType)
+		writeCalendar(w, todos, uids)
+
+	case "PROPFIND":
+		var req propfind
+		if err := decodeBody(r, &req); err != nil {
+			http.Error(w, "invalid PROPFIND body", http.StatusBadRequest)
+			return
+		}
+		props, err := h.calendarProps()
+		if err != nil {
+			h.fail(w, r, err, "failed to read calendar")
+			return
+		}
+		responses := []response{{href: calendarPath, props: props}}
+		if depth(r) == 1 {
+			objects, err := h.objects()
+			if err != nil {
+				h.fail(w, r, err, "failed to list todos")
+				return
+			}
+			for _, o := range objects {
+				responses = append(responses, response{href: o.href(), props: o.props(false)})
+			}
+		}
+		writeMultistatus(w, responses, req.Prop.names(), "")
+
+	case "REPORT":
+		var req report
+		if err := decodeBody(r, &req); err != nil {
+			http.Error(w, "invalid REPORT body", http.StatusBadRequest)
+			return
+		}
+		switch req.XMLName {
+		case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+			h.calendarQuery(w, r, req)
+		case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+			h.calendarMultiget(w, r, req)
+		case xml.Name{Space: nsDAV, Local: "sync-collection"}:
+			h.syncCollection(w, r, req)
+		default:
+			writeError(w, http.StatusForbidden, "supported-report")
+		}
+	}
+}
+
+func (h *Handler) calendarQuery(w http.ResponseWriter, r *http.Request, req report) {
+	responses := []response{}
+	if req.matchesVTODO() {
+		objects, err := h.objects()
+		if err != nil {
+			h.fail(w, r, err, "failed to list todos")
+			return
+		}
+		for _, o := range objects {
+			responses = append(responses, response{href: o.href(), props: o.props(true)})
+		}
+	}
+	writeMultistatus(w, responses, req.Prop.names(), "")
+}
+
+func (h *Handler) calendarMultiget(w http.ResponseWriter, r *http.Request, req report) {
+	responses := []response{}
+	for _, ref := range req.Hrefs {
+		// Hrefs outside the calendar name no object.
+		var name string
+		if u, err := url.Parse(strings.TrimSpace(ref)); err == nil && strings.HasPrefix(u.Path, calendarPath) {
+			name = u.Path[len(calendarPath):]
+		}
+		o, err := h.object(name)
+		if errors.Is(err, db.ErrNotFound) {
+			responses = append(responses, response{href: ref, status: http.StatusNotFound})
+			continue
+		}
+		if err != nil {
+			h.fail(w, r, err, "failed to read todo")
+			return
+		}
+		responses = append(responses, response{href: o.href(), props: o.props(true)})
+	}
+	writeMultistatus(w, responses, req.Prop.names(), "")
+}
+
+// syncCollection reports the TODOs changed since the request's sync token,
+// or every TODO if it has none. TODOs deleted or archived since are
+// reported as missing.
+func (h *Handler) syncCollection(w http.ResponseWriter, r *http.Request, req report) {
+	latest, err := h.repo.LatestAuditID()
+	if err != nil {
+		h.fail(w, r, err, "failed to read sync token")
+		return
+	}
+
+	responses := []response{}
+	if req.SyncToken == "" {
+		objects, err := h.objects()
+		if err != nil {
+			h.fail(w, r, err, "failed to list todos")
+			return
+		}
+		for _, o := range objects {
+			responses = append(responses, response{href: o.href(), props: o.props(true)})
+		}
+		writeMultistatus(w, responses, req.Prop.names(), syncToken(latest))
+		return
+	}
+
+	since, err := strconv.ParseInt(strings.TrimPrefix(req.SyncToken, syncTokenPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(req.SyncToken, syncTokenPrefix) || since < 0 || since > latest {
+		writeError(w, http.StatusForbidden, "valid-sync-token")
+		return
+	}
+	ids, err := h.repo.TodosChangedSince(since)
+	if err != nil {
+		h.fail(w, r, err, "failed to list changed todos")
+		return
+	}
+	names, err := h.repo.CalDAVObjects()
+	if err != nil {
+		h.fail(w, r, err, "failed to list todos")
+		return
+	}
+	for _, id := range ids {
+		todo, err := h.repo.GetTodo(id)
+		switch {
+		case errors.Is(err, db.ErrNotFound), err == nil && todo.Archived:
+			o := newObject(model.Todo{ID: id}, names[id])
+			responses = append(responses, response{href: o.href(), status: http.StatusNotFound})
+		case err != nil:
+			h.fail(w, r, err, "failed to read todo")
+			return
+		default:
+			o := newObject(todo, names[id])
+			responses = append(responses, response{href: o.href(), props: o.props(true)})
+		}
+	}
+	writeMultistatus(w, responses, req.Prop.names(), syncToken(latest))
+}
+
+func (h *Handler) serveObject(w http.ResponseWriter, r *http.Request, name string) {
+	if allow(w, r, http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, "PROPFIND") {
+		return
+	}
+
+	o, err := h.object(name)
+	exists := err == nil
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		h.fail(w, r, err, "failed to read todo")
+		return
+	}
+	if r.Method != http.MethodPut && !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		w.Header().Set("Content-Type", calendarContentType)
+		w.Header().Set("ETag", o.etag())
+		w.Header().Set("Last-Modified", o.todo.UpdatedAt.UTC().Format(http.TimeFormat))
+		writeCalendar(w, []model.Todo{o.todo}, map[int64]string{o.todo.ID: o.uid})
+
+	case "PROPFIND":
+		var req propfind
+		if err := decodeBody(r, &req); err != nil {
+			http.Error(w, "invalid PROPFIND body", http.StatusBadRequest)
+			return
+		}
+		writeMultistatus(w, []response{{href: o.href(), props: o.props(false)}}, req.Prop.names(), "")
+
+	case http.MethodPut:
+		h.put(w, r, name, o, exists)
+
+	case http.MethodDelete:
+		version, ok := ifMatch(w, r, o, exists)
+		if !ok {
+			return
+		}
+		if err := h.repo.DeleteTodo(o.todo.ID, version, h.auditInfo(r)); err != nil {
+			h.fail(w, r, err, "failed to delete todo")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// put creates or replaces the TODO at name from the VTODO in the request
+// body. Completing, reopening, and rescheduling follow from its status and
+// start date.
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, name string, o object, exists bool) {
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		http.Error(w, "the resource already exists", http.StatusPreconditionFailed)
+		return
+	}
+	version, ok := ifMatch(w, r, o, exists)
+	if !ok {
+		return
+	}
+
+	v, err := parseVTODO(r.Body)
+	if err == nil && v.Summary == "" {
+		err = errors.New("SUMMARY is required")
+	}
+	if err != nil {
+		http.Error(w, "invalid calendar object: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	info := h.auditInfo(r)
+
+	if !exists {
+		if defaultName.MatchString(name) || !strings.HasSuffix(name, ".ics") {
+			http.Error(w, "new calendar objects must be named *.ics, but not todo-<id>.ics", http.StatusForbidden)
+			return
+		}
+		req := model.CreateTodoRequest{
+			Title:           v.Summary,
+			Description:     v.Description,
+			Status:          v.Status,
+			ProgressPercent: v.Progress,
+			Priority:        v.Priority,
+			DueDate:         v.DueDate,
+		}
+		if err := validate.CreateTodo(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		uid := v.UID
+		if uid == "" {
+			uid = strings.TrimSuffix(name, ".ics")
+		}
+		todo, err := h.repo.CreateCalDAVTodo(db.CalDAVObject{Name: name, UID: uid}, req, v.ScheduledFor, info)
+		if err != nil {
+			h.fail(w, r, err, "failed to create todo")
+			return
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, todo.Version))
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	// A replaced VTODO without a due date clears the TODO's.
+	due := deref(v.DueDate)
+	req := model.UpdateTodoRequest{
+		Title:           &v.Summary,
+		Description:     &v.Description,
+		ProgressPercent: v.Progress,
+		Priority:        &v.Priority,
+		DueDate:         &due,
+	}
+	if err := validate.UpdateTodo(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id := o.todo.ID
+	todo, err := h.repo.UpdateTodo(id, version, req, info)
+	switch {
+	case err != nil:
+	case v.Status == model.StatusDone && todo.Status != model.StatusDone:
+		todo, err = h.repo.CompleteTodo(id, info)
+	case v.Status != model.StatusDone && todo.Status == model.StatusDone:
+		todo, err = h.repo.ReopenTodo(id, info)
+	}
+	if err == nil && v.Status != todo.Status {
+		todo, err = h.repo.UpdateTodo(id, 0, model.UpdateTodoRequest{Status: &v.Status}, info)
+	}
+	if err == nil && deref(v.ScheduledFor) != deref(todo.ScheduledFor) {
+		todo, err = h.repo.SetSchedule(id, v.ScheduledFor, info)
+	}
+	if err != nil {
+		h.fail(w, r, err, "failed to update todo")
+		return
+	}
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, todo.Version))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ifMatch checks a request's If-Match header against o and returns the
+// version it requires, 0 for none. It responds 412 and returns false if the
+// header does not match.
+func ifMatch(w http.ResponseWriter, r *http.Request, o object, exists bool) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch {
+	case header == "":
+		return 0, true
+	case header == "*" && exists:
+		return 0, true
+	case exists && header == o.etag():
+		return o.todo.Version, true
+	}
+	http.Error(w, "the resource has changed", http.StatusPreconditionFailed)
+	return 0, false
+}
+
+// fail responds to a repository error.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	var transition *db.TransitionError
+	var rules *db.RuleError
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		http.NotFound(w, r)
+	case errors.Is(err, db.ErrVersionMismatch):
+		http.Error(w, "the resource has changed", http.StatusPreconditionFailed)
+	case errors.As(err, &transition), errors.As(err, &rules):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		h.logger.ErrorContext(r.Context(), msg, slog.String("error", err.Error()))
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
+
+// auditInfo attributes the changes of a request to the CalDAV user.
+func (h *Handler) auditInfo(r *http.Request) db.AuditInfo {
+	return db.AuditInfo{
+		Actor:       "caldav:" + h.user,
+		RequestID:   chimw.GetReqID(r.Context()),
+		OperationID: db.NewOperationID(),
+	}
+}
+
+// object is a TODO as a calendar object resource.
+type object struct {
+	todo model.Todo
+	name string
+	uid  string
+}
+
+// newObject returns todo as a calendar object, at the name and with the UID
+// of obj if it was created over CalDAV.
+func newObject(todo model.Todo, obj db.CalDAVObject) object {
+	if obj.Name != "" {
+		return object{todo: todo, name: obj.Name, uid: obj.UID}
+	}
+	return object{
+		todo: todo,
+		name: fmt.Sprintf("todo-%d.ics", todo.ID),
+		uid:  fmt.Sprintf("todo-%d@todo-service", todo.ID),
+	}
+}
+
+func (o object) href() string {
+	return calendarPath + url.PathEscape(o.name)
+}
+
+func (o object) etag() string {
+	return fmt.Sprintf(`"%d"`, o.todo.Version)
+}
+
+// props returns the properties of o, including its calendar data if
+// withData is set.
+func (o object) props(withData bool) map[xml.Name]property {
+	props := map[xml.Name]property{
+		{Space: nsDAV, Local: "getetag"}:         func() string { return escape(o.etag()) },
+		{Space: nsDAV, Local: "getcontenttype"}:  func() string { return escape(calendarContentType + "; component=vtodo") },
+		{Space: nsDAV, Local: "getlastmodified"}: func() string { return o.todo.UpdatedAt.UTC().Format(http.TimeFormat) },
+		{Space: nsDAV, Local: "resourcetype"}:    func() string { return "" },
+	}
+	if withData {
+		props[xml.Name{Space: nsCalDAV, Local: "calendar-data"}] = func() string {
+			var b bytes.Buffer
+			writeCalendar(&b, []model.Todo{o.todo}, map[int64]string{o.todo.ID: o.uid})
+			return escape(b.String())
+		}
+	}
+	return props
+}
+
+// objects returns every unarchived TODO as a calendar object.
+func (h *Handler) objects() ([]object, error) {
+	archived := false
+	opts, err := query.Params{}.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := h.repo.ListTodos(db.TodoFilter{Archived: &archived}, opts)
+	if err != nil {
+		return nil, err
+	}
+	names, err := h.repo.CalDAVObjects()
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]object, len(todos))
+	for i, t := range todos {
+		objects[i] = newObject(t, names[t.ID])
+	}
+	return objects, nil
+}
+
+// object returns the unarchived TODO at name, or db.ErrNotFound.
+func (h *Handler) object(name string) (object, error) {
+	obj, err := h.repo.CalDAVObject(name)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		m := defaultName.FindStringSubmatch(name)
+		if m == nil {
+			return object{}, err
+		}
+		obj.TodoID, _ = strconv.ParseInt(m[1], 10, 64)
+		obj.Name = ""
+	case err != nil:
+		return object{}, err
+	}
+
+	todo, err := h.repo.GetTodo(obj.TodoID)
+	if err != nil {
+		return object{}, err
+	}
+	if todo.Archived {
+		return object{}, db.ErrNotFound
+	}
+	if obj.Name == "" {
+		// A TODO created over CalDAV is only served at the name its client
+		// gave it.
+		names, err := h.repo.CalDAVObjects()
+		if err != nil {
+			return object{}, err
+		}
+		if _, ok := names[todo.ID]; ok {
+			return object{}, db.ErrNotFound
+		}
+	}
+	return newObject(todo, obj), nil
+}
+
+func homeProps() map[xml.Name]property {
+	principal := func() string { return href(Prefix) }
+	return map[xml.Name]property{
+		{Space: nsDAV, Local: "resourcetype"}:           func() string { return "<D:collection/><D:principal/>" },
+		{Space: nsDAV, Local: "displayname"}:            func() string { return "todo-service" },
+		{Space: nsDAV, Local: "current-user-principal"}: principal,
+		{Space: nsDAV, Local: "principal-URL"}:          principal,
+		{Space: nsCalDAV, Local: "calendar-home-set"}:   principal,
+		{Space: nsDAV, Local: "current-user-privilege-set"}: func() string {
+			return "<D:privilege><D:read/></D:privilege>"
+		},
+	}
+}
+
+func (h *Handler) calendarProps() (map[xml.Name]property, error) {
+	latest, err := h.repo.LatestAuditID()
+	if err != nil {
+		return nil, err
+	}
+	token := func() string { return escape(syncToken(latest)) }
+	return map[xml.Name]property{
+		{Space: nsDAV, Local: "resourcetype"}:           func() string { return "<D:collection/><C:calendar/>" },
+		{Space: nsDAV, Local: "displayname"}:            func() string { return "TODOs" },
+		{Space: nsDAV, Local: "current-user-principal"}: func() string { return href(Prefix) },
+		{Space: nsDAV, Local: "owner"}:                  func() string { return href(Prefix) },
+		{Space: nsCalDAV, Local: "supported-calendar-component-set"}: func() string {
+			return `<C:comp name="VTODO"/>`
+		},
+		{Space: nsDAV, Local: "supported-report-set"}: func() string {
+			return "<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>" +
+				"<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>" +
+				"<D:supported-report><D:report><D:sync-collection/></D:report></D:supported-report>"
+		},
+		{Space: nsDAV, Local: "current-user-privilege-set"}: func() string {
+			return "<D:privilege><D:read/></D:privilege><D:privilege><D:write/></D:privilege>" +
+				"<D:privilege><D:write-content/></D:privilege><D:privilege><D:bind/></D:privilege>" +
+				"<D:privilege><D:unbind/></D:privilege>"
+		},
+		{Space: nsCS, Local: "getctag"}:     token,
+		{Space: nsDAV, Local: "sync-token"}: token,
+	}, nil
+}
+
+func syncToken(auditID int64) string {
+	return syncTokenPrefix + strconv.FormatInt(auditID, 10)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,273 @@
+package caldav
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-service/internal/model"
+)
+
+// icalTime is the iCalendar UTC date-time format.
+const icalTime = "20060102T150405Z"
+
+// icalDate is the iCalendar date format.
+const icalDate = "20060102"
+
+// vtodo is the part of a VTODO component that maps onto a TODO.
+type vtodo struct {
+	UID          string
+	Summary      string
+	Description  string
+	Status       model.Status
+	Progress     *int
+	Priority     model.Priority
+	DueDate      *string
+	ScheduledFor *string
+}
+
+// writeCalendar writes todos as the VTODOs of one VCALENDAR, using uids for
+// their UIDs.
+func writeCalendar(w io.Writer, todos []model.Todo, uids map[int64]string) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//todo-service//CalDAV//EN")
+	for _, t := range todos {
+		line("BEGIN", "VTODO")
+		line("UID", escapeText(uids[t.ID]))
+		line("DTSTAMP", t.UpdatedAt.UTC().Format(icalTime))
+		line("CREATED", t.CreatedAt.UTC().Format(icalTime))
+		line("LAST-MODIFIED", t.UpdatedAt.UTC().Format(icalTime))
+		line("SUMMARY", escapeText(t.Title))
+		if t.Description != "" {
+			line("DESCRIPTION", escapeText(t.Description))
+		}
+		line("STATUS", icalStatus(t.Status))
+		line("PERCENT-COMPLETE", strconv.Itoa(t.ProgressPercent))
+		if t.CompletedAt != nil {
+			line("COMPLETED", t.CompletedAt.UTC().Format(icalTime))
+		}
+		if p := icalPriority(t.Priority); p != 0 {
+			line("PRIORITY", strconv.Itoa(p))
+		}
+		// A start after the due date is invalid, so such a scheduled day is
+		// left out.
+		if t.ScheduledFor != nil && (t.DueDate == nil || *t.ScheduledFor <= *t.DueDate) {
+			if day, err := time.Parse(model.DateLayout, *t.ScheduledFor); err == nil {
+				line("DTSTART;VALUE=DATE", day.Format(icalDate))
+			}
+		}
+		if t.DueDate != nil {
+			if day, err := time.Parse(model.DateLayout, *t.DueDate); err == nil {
+				line("DUE;VALUE=DATE", day.Format(icalDate))
+			}
+		}
+		line("CATEGORIES", escapeText(string(t.Category)))
+		line("END", "VTODO")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeLine writes a content line, folded to lines of at most 75 octets
+// without splitting a character.
+func writeLine(w *bufio.Writer, s string) {
+	for len(s) > 75 {
+		n := 75
+		for !utf8.RuneStart(s[n]) {
+			n--
+		}
+		w.WriteString(s[:n])
+		w.WriteString("\r\n ")
+		s = s[n:]
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func icalStatus(s model.Status) string {
+	switch s {
+	case model.StatusInProgress:
+		return "IN-PROCESS"
+	case model.StatusDone:
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icalPriority maps a priority to the iCalendar PRIORITY scale, where 1 is
+// the highest, 9 the lowest, and 0 undefined.
+func icalPriority(p model.Priority) int {
+	switch p {
+	case model.PriorityUrgent:
+		return 1
+	case model.PriorityHigh:
+		return 3
+	case model.PriorityMedium:
+		return 5
+	case model.PriorityLow:
+		return 7
+	}
+	return 0
+}
+
+// parsePriority maps an iCalendar PRIORITY to a priority: 1 is urgent, 2 to
+// 4 high, 5 medium, 6 to 9 low, and 0 none.
+func parsePriority(n int) model.Priority {
+	switch {
+	case n == 1:
+		return model.PriorityUrgent
+	case n >= 2 && n <= 4:
+		return model.PriorityHigh
+	case n == 5:
+		return model.PriorityMedium
+	case n >= 6:
+		return model.PriorityLow
+	}
+	return model.PriorityNone
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// parseVTODO reads the first VTODO of an iCalendar object. Properties of
+// components nested in it, such as alarms, are ignored, as are properties
+// with no TODO counterpart.
+func parseVTODO(r io.Reader) (vtodo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return vtodo{}, err
+	}
+	// Unfold continuation lines, which start with a space or tab.
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	v := vtodo{Priority: model.PriorityNone}
+	var completed bool
+	found, depth := false, 0
+	for l := range strings.SplitSeq(text, "\n") {
+		name, value, ok := splitLine(l)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && found:
+			depth++
+			continue
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO"):
+			found, depth = true, 1
+			continue
+		case name == "END" && found:
+			depth--
+			if depth == 0 {
+				if v.Status == "" {
+					v.Status = model.StatusPending
+					if completed {
+						v.Status = model.StatusDone
+					}
+				}
+				return v, nil
+			}
+			continue
+		}
+		if !found || depth != 1 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			v.UID = value
+		case "SUMMARY":
+			v.Summary = unescapeText(value)
+		case "DESCRIPTION":
+			v.Description = unescapeText(value)
+		case "STATUS":
+			switch strings.ToUpper(value) {
+			case "IN-PROCESS":
+				v.Status = model.StatusInProgress
+			case "COMPLETED", "CANCELLED":
+				v.Status = model.StatusDone
+			default:
+				v.Status = model.StatusPending
+			}
+		case "COMPLETED":
+			completed = true
+		case "PERCENT-COMPLETE":
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 0 || p > 100 {
+				return vtodo{}, fmt.Errorf("invalid PERCENT-COMPLETE %q", value)
+			}
+			v.Progress = &p
+		case "PRIORITY":
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 0 || p > 9 {
+				return vtodo{}, fmt.Errorf("invalid PRIORITY %q", value)
+			}
+			v.Priority = parsePriority(p)
+		case "DUE", "DTSTART":
+			day, err := time.Parse(icalDate, value[:min(len(value), len(icalDate))])
+			if err != nil {
+				return vtodo{}, fmt.Errorf("invalid %s %q", name, value)
+			}
+			// A date-time is taken on its day in the time zone it is given in.
+			s := day.Format(model.DateLayout)
+			if name == "DUE" {
+				v.DueDate = &s
+			} else {
+				v.ScheduledFor = &s
+			}
+		}
+	}
+	return vtodo{}, errors.New("no VTODO component")
+}
+
+// splitLine splits a content line into its upper-cased name and its value,
+// dropping any parameters. A colon inside a quoted parameter value does not
+// end the parameters.
+func splitLine(l string) (name, value string, ok bool) {
+	quoted := false
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if quoted {
+				continue
+			}
+			name, _, _ = strings.Cut(l[:i], ";")
+			return strings.ToUpper(name), l[i+1:], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,201 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// XML namespaces of the properties served.
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// prefixes are the namespace prefixes used in responses.
+var prefixes = map[string]string{nsDAV: "D", nsCalDAV: "C", nsCS: "CS"}
+
+// propNames lists the properties a request asks for.
+type propNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func (p *propNames) names() []xml.Name {
+	if p == nil {
+		return nil
+	}
+	names := make([]xml.Name, len(p.Names))
+	for i, n := range p.Names {
+		names[i] = n.XMLName
+	}
+	return names
+}
+
+// propfind is a PROPFIND request body. An empty body, or allprop, asks for
+// every property.
+type propfind struct {
+	Prop *propNames `xml:"DAV: prop"`
+}
+
+// compFilter is a CalDAV calendar-query component filter.
+type compFilter struct {
+	Name  string       `xml:"name,attr"`
+	Comps []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// report is a REPORT request body: a calendar-query, calendar-multiget, or
+// sync-collection.
+type report struct {
+	XMLName   xml.Name
+	Prop      *propNames `xml:"DAV: prop"`
+	Hrefs     []string   `xml:"DAV: href"`
+	SyncToken string     `xml:"DAV: sync-token"`
+	Filter    *struct {
+		Comps []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// matchesVTODO reports whether the query's filter, if any, can match VTODO
+// components. Filters on properties and time ranges are not applied; the
+// client is sent every TODO.
+func (r report) matchesVTODO() bool {
+	if r.Filter == nil {
+		return true
+	}
+	for _, cal := range r.Filter.Comps {
+		if !strings.EqualFold(cal.Name, "VCALENDAR") {
+			continue
+		}
+		if len(cal.Comps) == 0 {
+			return true
+		}
+		for _, c := range cal.Comps {
+			if strings.EqualFold(c.Name, "VTODO") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeBody decodes an XML request body into v, leaving v as it is if the
+// body is empty.
+func decodeBody(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// property renders a property's value as the XML inside its element.
+type property func() string
+
+// response is one resource of a multistatus response: either its
+// properties or, for a missing resource, a status.
+type response struct {
+	href   string
+	props  map[xml.Name]property
+	status int
+}
+
+// writeMultistatus writes a 207 Multi-Status response. Each resource lists
+// the properties named in names, or all of its own if names is nil. A
+// non-empty syncToken is written as the new sync token.
+func writeMultistatus(w http.ResponseWriter, responses []response, names []xml.Name, syncToken string) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<D:multistatus xmlns:D="%s" xmlns:C="%s" xmlns:CS="%s">`, nsDAV, nsCalDAV, nsCS)
+	for _, resp := range responses {
+		b.WriteString("<D:response><D:href>")
+		xml.EscapeText(&b, []byte(resp.href))
+		b.WriteString("</D:href>")
+		if resp.status != 0 {
+			fmt.Fprintf(&b, "<D:status>%s</D:status></D:response>", statusLine(resp.status))
+			continue
+		}
+
+		requested := names
+		if requested == nil {
+			for name := range resp.props {
+				requested = append(requested, name)
+			}
+			slices.SortFunc(requested, func(a, b xml.Name) int { return strings.Compare(a.Local, b.Local) })
+		}
+		var found, missing strings.Builder
+		for _, name := range requested {
+			if prop, ok := resp.props[name]; ok {
+				writeElement(&found, name, prop())
+			} else {
+				writeElement(&missing, name, "")
+			}
+		}
+		if found.Len() > 0 {
+			fmt.Fprintf(&b, "<D:propstat><D:prop>%s</D:prop><D:status>%s</D:status></D:propstat>", found.String(), statusLine(http.StatusOK))
+		}
+		if missing.Len() > 0 {
+			fmt.Fprintf(&b, "<D:propstat><D:prop>%s</D:prop><D:status>%s</D:status></D:propstat>", missing.String(), statusLine(http.StatusNotFound))
+		}
+		b.WriteString("</D:response>")
+	}
+	if syncToken != "" {
+		b.WriteString("<D:sync-token>")
+		xml.EscapeText(&b, []byte(syncToken))
+		b.WriteString("</D:sync-token>")
+	}
+	b.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+// writeElement writes an element named name containing inner, prefixed if
+// its namespace is one of ours and declaring its namespace otherwise.
+func writeElement(b *strings.Builder, name xml.Name, inner string) {
+	tag, decl := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		decl = fmt.Sprintf(` xmlns="%s"`, escape(name.Space))
+	}
+	if inner == "" {
+		fmt.Fprintf(b, "<%s%s/>", tag, decl)
+		return
+	}
+	fmt.Fprintf(b, "<%s%s>%s</%s>", tag, decl, inner, tag)
+}
+
+// writeError writes a WebDAV error response with a precondition element in
+// the DAV namespace.
+func writeError(w http.ResponseWriter, status int, precondition string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `%s<D:error xmlns:D="%s"><D:%s/></D:error>`, xml.Header, nsDAV, precondition)
+}
+
+func statusLine(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// escape returns s escaped for XML character data.
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// href returns an href element for path.
+func href(path string) string {
+	return "<D:href>" + escape(path) + "</D:href>"
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"todo-service/client"
+	"todo-service/internal/db"
+	"todo-service/internal/manifest"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// backend performs todo operations for the client subcommands.
+type backend interface {
+	create(req model.CreateTodoRequest) (model.Todo, error)
+	list(filter db.TodoFilter, limit int) ([]model.Todo, error)
+	update(id int64, req model.UpdateTodoRequest) (model.Todo, error)
+	delete(id int64) error
+	exportConfig() ([]byte, error)
+	applyConfig(data []byte, dryRun bool) (model.ApplyConfigResponse, error)
+	close() error
+}
+
+// httpBackend calls a running server's REST API.
+type httpBackend struct {
+	base string
+	c    *client.Client
+}
+
+// newHTTPBackend creates an httpBackend for the server at base, making
+// changes as actor. Updates and deletes are unconditional.
+func newHTTPBackend(base, actor string, opts ...client.Option) *httpBackend {
+	opts = append([]client.Option{client.WithActor(actor)}, opts...)
+	return &httpBackend{base: base, c: client.New(base, opts...)}
+}
+
+func (b *httpBackend) create(req model.CreateTodoRequest) (model.Todo, error) {
+	return b.c.CreateTodo(context.Background(), req)
+}
+
+func (b *httpBackend) list(filter db.TodoFilter, limit int) ([]model.Todo, error) {
+	opts := client.ListTodosOptions{Limit: limit}
+	if filter.Status != nil {
+		opts.Status = *filter.Status
+	}
+	if filter.Category != nil {
+		opts.Category = *filter.Category
+	}
+	if filter.ProjectID != nil {
+		opts.ProjectID = *filter.ProjectID
+	}
+	if filter.Archived != nil {
+		opts.Archived = *filter.Archived
+	}
+
+	resp, err := b.c.ListTodos(context.Background(), opts)
+	return resp.Todos, err
+}
+
+func (b *httpBackend) update(id int64, req model.UpdateTodoRequest) (model.Todo, error) {
+	return b.c.UpdateTodo(context.Background(), id, 0, req)
+}
+
+func (b *httpBackend) delete(id int64) error {
+	return b.c.DeleteTodo(context.Background(), id, 0)
+}
+
+func (b *httpBackend) exportConfig() ([]byte, error) {
+	return b.c.ExportConfig(context.Background())
+}
+
+func (b *httpBackend) applyConfig(data []byte, dryRun bool) (model.ApplyConfigResponse, error) {
+	return b.c.ApplyConfig(context.Background(), data, dryRun)
+}
+
+func (b *httpBackend) close() error {
+	return nil
+}
+
+// dbBackend uses the SQLite database directly.
+type dbBackend struct {
+	repo db.Repository
+	info db.AuditInfo
+}
+
+func openDBBackend(path, actor string) (*dbBackend, error) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo, err := db.New(path, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &dbBackend{repo: repo, info: db.AuditInfo{Actor: actor, RequestID: "cli"}}, nil
+}
+
+func (b *dbBackend) create(req model.CreateTodoRequest) (model.Todo, error) {
+	return b.repo.CreateTodo(req, b.auditInfo())
+}
+
+func (b *dbBackend) list(filter db.TodoFilter, limit int) ([]model.Todo, error) {
+	opts, err := query.Params{Limit: limit}.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+	return b.repo.ListTodos(filter, opts)
+}
+
+func (b *dbBackend) update(id int64, req model.UpdateTodoRequest) (model.Todo, error) {
+	todo, err := b.repo.UpdateTodo(id, 0, req, b.auditInfo())
+	return todo, notFound(err, id)
+}
+
+func (b *dbBackend) delete(id int64) error {
+	return notFound(b.repo.DeleteTodo(id, 0, b.auditInfo()), id)
+}
+
+func (b *dbBackend) exportConfig() ([]byte, error) {
+	m, err := manifest.Export(b.repo)
+	if err != nil {
+		return nil, err
+	}
+	return m.Marshal()
+}
+
+func (b *dbBackend) applyConfig(data []byte, dryRun bool) (model.ApplyConfigResponse, error) {
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return model.ApplyConfigResponse{}, err
+	}
+	return manifest.Apply(b.repo, m, dryRun)
+}
+
+func (b *dbBackend) close() error {
+	return b.repo.Close()
+}
+
+func (b *dbBackend) auditInfo() db.AuditInfo {
+	info := b.info
+	info.OperationID = db.NewOperationID()
+	return info
+}
+
+func notFound(err error, id int64) error {
+	if errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("todo with id %d not found", id)
+	}
+	return err
+}
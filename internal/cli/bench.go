@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"todo-service/client"
+	"todo-service/internal/model"
+	"todo-service/internal/synth"
+)
+
+// benchConfig are the settings of a bench run.
+type benchConfig struct {
+	todos       int
+	requests    int
+	concurrency int
+	seed        uint64
+}
+
+// benchOps are the operations of bench's mixed workload, with their
+// relative weights: mostly reads, as clients make.
+var benchOps = []struct {
+	name   string
+	weight int
+}{
+	{"list", 40},
+	{"get", 30},
+	{"update", 20},
+	{"create", 10},
+}
+
+// benchResult is the outcome of a bench run.
+type benchResult struct {
+	Populated       int           `json:"populated"`
+	PopulateSeconds float64       `json:"populate_seconds"`
+	Requests        int           `json:"requests"`
+	Concurrency     int           `json:"concurrency"`
+	Seconds         float64       `json:"seconds"`
+	Operations      []benchOpStat `json:"operations"`
+}
+
+// benchOpStat summarizes the latencies of one operation, in milliseconds.
+type benchOpStat struct {
+	Name       string  `json:"name"`
+	Count      int     `json:"count"`
+	Errors     int     `json:"errors"`
+	FirstError string  `json:"first_error,omitempty"`
+	P50        float64 `json:"p50_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+	Max        float64 `json:"max_ms"`
+}
+
+// bencher runs a benchmark against a server, recording how long each
+// request takes from sending it to decoding its response.
+type bencher struct {
+	b   *httpBackend
+	cfg benchConfig
+
+	mu        sync.Mutex
+	ids       []int64
+	latencies map[string][]time.Duration
+	errs      map[string]int
+	firstErr  map[string]string
+}
+
+// bench fills the server at base with cfg.todos synthetic TODOs, created
+// as actor, then runs cfg.requests requests of a mixed workload over
+// cfg.concurrency connections. Rate-limited requests count as errors rather
+// than being retried, so the server should run with -rate-limit 0.
+func bench(base, actor string, cfg benchConfig) (benchResult, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = cfg.concurrency
+	b := newHTTPBackend(base, actor,
+		client.WithRetries(0),
+		client.WithHTTPClient(&http.Client{Transport: transport, Timeout: client.DefaultTimeout}),
+	)
+
+	r := &bencher{b: b, cfg: cfg}
+	result := benchResult{Requests: cfg.requests, Concurrency: cfg.concurrency}
+
+	start := time.Now()
+	if err := r.populate(); err != nil {
+		return result, err
+	}
+	result.Populated = len(r.ids)
+	result.PopulateSeconds = time.Since(start).Seconds()
+
+	if len(r.ids) == 0 {
+		page, err := b.todoPage(false, 0)
+		if err != nil {
+			return result, err
+		}
+		for _, t := range page.Todos {
+			r.ids = append(r.ids, t.ID)
+		}
+		if len(r.ids) == 0 {
+			return result, errors.New("the server has no todos to benchmark; set -todos")
+		}
+	}
+
+	r.latencies = map[string][]time.Duration{}
+	r.errs = map[string]int{}
+	r.firstErr = map[string]string{}
+	start = time.Now()
+	r.workers(cfg.requests, func(g *synth.Generator, rng *rand.Rand) {
+		r.op(g, rng)
+	})
+	result.Seconds = time.Since(start).Seconds()
+
+	for _, op := range benchOps {
+		result.Operations = append(result.Operations, r.stat(op.name))
+	}
+	return result, nil
+}
+
+// populate creates cfg.todos synthetic TODOs, scheduling those that are
+// planned, and records their IDs.
+func (r *bencher) populate() error {
+	var mu sync.Mutex
+	var firstErr error
+	r.workers(r.cfg.todos, func(g *synth.Generator, _ *rand.Rand) {
+		t := g.Todo()
+		todo, err := r.b.create(t.Request)
+		if err == nil && t.ScheduledFor != "" {
+			_, err = r.b.c.ScheduleTodo(context.Background(), todo.ID, t.ScheduledFor)
+		}
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("populate: %w", err)
+			}
+			mu.Unlock()
+			return
+		}
+		r.mu.Lock()
+		r.ids = append(r.ids, todo.ID)
+		r.mu.Unlock()
+	})
+	return firstErr
+}
+
+// workers calls fn n times over cfg.concurrency goroutines, each with its
+// own generator and random source derived from the seed.
+func (r *bencher) workers(n int, fn func(g *synth.Generator, rng *rand.Rand)) {
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := range r.cfg.concurrency {
+		seed := r.cfg.seed + uint64(w)*1_000_003
+		g := synth.New(seed, time.Now())
+		rng := rand.New(rand.NewPCG(seed, 0))
+		wg.Go(func() {
+			for range jobs {
+				fn(g, rng)
+			}
+		})
+	}
+	for range n {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// op runs one request of the workload, chosen by benchOps' weights.
+func (r *bencher) op(g *synth.Generator, rng *rand.Rand) {
+	total := 0
+	for _, op := range benchOps {
+		total += op.weight
+	}
+	n := rng.IntN(total)
+	name := benchOps[len(benchOps)-1].name
+	for _, op := range benchOps {
+		if n < op.weight {
+			name = op.name
+			break
+		}
+		n -= op.weight
+	}
+
+	r.mu.Lock()
+	id := r.ids[rng.IntN(len(r.ids))]
+	r.mu.Unlock()
+
+	start := time.Now()
+	var created model.Todo
+	var err error
+	switch name {
+	case "list":
+		opts := client.ListTodosOptions{Limit: 50}
+		if rng.IntN(2) == 0 {
+			opts.Status = []model.Status{model.StatusPending, model.StatusInProgress, model.StatusDone}[rng.IntN(3)]
+		}
+		_, err = r.b.c.ListTodos(context.Background(), opts)
+	case "get":
+		_, err = r.b.c.GetTodo(context.Background(), id)
+	case "update":
+		_, err = r.b.update(id, g.Update())
+	case "create":
+		created, err = r.b.create(g.Todo().Request)
+	}
+	elapsed := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errs[name]++
+		if r.firstErr[name] == "" {
+			r.firstErr[name] = err.Error()
+		}
+		return
+	}
+	r.latencies[name] = append(r.latencies[name], elapsed)
+	if created.ID != 0 {
+		r.ids = append(r.ids, created.ID)
+	}
+}
+
+// stat summarizes the requests made for the operation name.
+func (r *bencher) stat(name string) benchOpStat {
+	l := r.latencies[name]
+	slices.Sort(l)
+	s := benchOpStat{Name: name, Count: len(l) + r.errs[name], Errors: r.errs[name], FirstError: r.firstErr[name]}
+	if len(l) > 0 {
+		s.P50, s.P95, s.P99 = percentile(l, 0.50), percentile(l, 0.95), percentile(l, 0.99)
+		s.Max = ms(l[len(l)-1])
+	}
+	return s
+}
+
+// percentile returns the p-th percentile of sorted, in milliseconds.
+func percentile(sorted []time.Duration, p float64) float64 {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return ms(sorted[max(i, 0)])
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
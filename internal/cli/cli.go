@@ -0,0 +1,266 @@
+// Package cli implements the client subcommands of the todo-service binary.
+// They talk to a running server over HTTP, or with -offline open the SQLite
+// database directly.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/validate"
+)
+
+// Commands lists the client subcommands Run accepts, with a short summary.
+var Commands = []struct{ Name, Summary string }{
+	{"add", "create a todo"},
+	{"list", "list todos"},
+	{"done", "mark a todo as done"},
+	{"rm", "delete a todo"},
+	{"export-config", "print the configuration as YAML"},
+	{"apply-config", "apply a YAML configuration file"},
+	{"migrate", "copy all data from one server to another"},
+	{"bench", "fill a server with synthetic todos and measure its latencies"},
+}
+
+// IsCommand reports whether name is a client subcommand.
+func IsCommand(name string) bool {
+	for _, c := range Commands {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the client subcommand name with args, writing results to out.
+func Run(name string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	var opts options
+	opts.register(fs)
+
+	// Commands that do not use the -server or -offline backend set local
+	// instead of run.
+	var run func(b backend, args []string) error
+	var local func(args []string) error
+	switch name {
+	case "add":
+		var req model.CreateTodoRequest
+		var project int64
+		var progress int
+		fs.StringVar(&req.Description, "description", "", "todo description")
+		fs.StringVar((*string)(&req.Status), "status", "", "initial status: pending, in_progress, or done")
+		fs.StringVar((*string)(&req.Category), "category", "", "category name (defaults to the first category)")
+		fs.Int64Var(&project, "project", 0, "project ID to add the todo to")
+		fs.IntVar(&progress, "progress", -1, "initial progress percent")
+		run = func(b backend, args []string) error {
+			req.Title = strings.Join(args, " ")
+			if project != 0 {
+				req.ProjectID = &project
+			}
+			if progress >= 0 {
+				req.ProgressPercent = &progress
+			}
+			if err := validate.CreateTodo(req); err != nil {
+				return err
+			}
+			todo, err := b.create(req)
+			if err != nil {
+				return err
+			}
+			return opts.printTodos(out, []model.Todo{todo})
+		}
+
+	case "list":
+		var filter db.TodoFilter
+		var status, category string
+		var project int64
+		var archived bool
+		var limit int
+		fs.StringVar(&status, "status", "", "only list todos with this status")
+		fs.StringVar(&category, "category", "", "only list todos in this category")
+		fs.Int64Var(&project, "project", 0, "only list todos in this project")
+		fs.BoolVar(&archived, "archived", false, "list archived todos instead of active ones")
+		fs.IntVar(&limit, "limit", 0, "maximum number of todos to list (0 for all)")
+		run = func(b backend, args []string) error {
+			if err := validate.Filter(model.Status(status)); err != nil {
+				return err
+			}
+			if err := validate.Page(limit, 0); err != nil {
+				return err
+			}
+			filter.Archived = &archived
+			if status != "" {
+				s := model.Status(status)
+				filter.Status = &s
+			}
+			if category != "" {
+				c := model.Category(category)
+				filter.Category = &c
+			}
+			if project != 0 {
+				filter.ProjectID = &project
+			}
+			todos, err := b.list(filter, limit)
+			if err != nil {
+				return err
+			}
+			return opts.printTodos(out, todos)
+		}
+
+	case "done":
+		run = func(b backend, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			done, progress := model.StatusDone, 100
+			todo, err := b.update(id, model.UpdateTodoRequest{Status: &done, ProgressPercent: &progress})
+			if err != nil {
+				return err
+			}
+			return opts.printTodos(out, []model.Todo{todo})
+		}
+
+	case "rm":
+		run = func(b backend, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			if err := b.delete(id); err != nil {
+				return err
+			}
+			if opts.format == "table" {
+				fmt.Fprintf(out, "deleted todo %d\n", id)
+			}
+			return nil
+		}
+
+	case "export-config":
+		run = func(b backend, args []string) error {
+			data, err := b.exportConfig()
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(data)
+			return err
+		}
+
+	case "apply-config":
+		var dryRun bool
+		fs.BoolVar(&dryRun, "dry-run", false, "report the changes without writing them")
+		run = func(b backend, args []string) error {
+			if len(args) != 1 {
+				return errors.New("expected a single configuration file")
+			}
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			result, err := b.applyConfig(data, dryRun)
+			if err != nil {
+				return err
+			}
+			return opts.printConfigResult(out, result)
+		}
+
+	case "migrate":
+		var from, to, cp string
+		fs.StringVar(&from, "from", "", "base URL of the server to copy from")
+		fs.StringVar(&to, "to", "", "base URL of the server to copy to")
+		fs.StringVar(&cp, "checkpoint", "migrate.checkpoint.json", "file recording progress, so that an interrupted migration can be resumed by running it again")
+		local = func(args []string) error {
+			if from == "" || to == "" {
+				return errors.New("-from and -to are required")
+			}
+			src := newHTTPBackend(strings.TrimSuffix(from, "/"), opts.actor)
+			dst := newHTTPBackend(strings.TrimSuffix(to, "/"), opts.actor)
+			return migrate(src, dst, cp, out)
+		}
+
+	case "bench":
+		var cfg benchConfig
+		fs.IntVar(&cfg.todos, "todos", 1000, "synthetic todos to create before measuring (0 to use the server's existing todos)")
+		fs.IntVar(&cfg.requests, "requests", 5000, "requests of the mixed list, get, update, and create workload to measure")
+		fs.IntVar(&cfg.concurrency, "concurrency", 8, "requests to make at once")
+		fs.Uint64Var(&cfg.seed, "seed", 1, "seed for the synthetic data and the workload")
+		local = func(args []string) error {
+			if cfg.todos < 0 || cfg.requests <= 0 || cfg.concurrency <= 0 {
+				return errors.New("-todos must not be negative, and -requests and -concurrency must be positive")
+			}
+			result, err := bench(strings.TrimSuffix(opts.server, "/"), opts.actor, cfg)
+			if err != nil {
+				return err
+			}
+			return opts.printBenchResult(out, result)
+		}
+
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.format != "table" && opts.format != "json" {
+		return fmt.Errorf("-o must be table or json, not %q", opts.format)
+	}
+
+	if local != nil {
+		return local(fs.Args())
+	}
+
+	b, err := opts.backend()
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	return run(b, fs.Args())
+}
+
+// options are the flags shared by every client subcommand.
+type options struct {
+	server  string
+	offline bool
+	dbPath  string
+	actor   string
+	format  string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	server := os.Getenv("TODO_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	fs.StringVar(&o.server, "server", server, "base URL of a running server (default $TODO_SERVER)")
+	fs.BoolVar(&o.offline, "offline", false, "use the SQLite database directly instead of a server")
+	fs.StringVar(&o.dbPath, "db", db.DefaultPath, "database file for -offline")
+	fs.StringVar(&o.actor, "actor", os.Getenv("USER"), "name recorded in the audit log")
+	fs.StringVar(&o.format, "o", "table", "output format: table or json")
+}
+
+func (o *options) backend() (backend, error) {
+	if o.offline {
+		return openDBBackend(o.dbPath, o.actor)
+	}
+	return newHTTPBackend(o.server, o.actor), nil
+}
+
+func parseID(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected a single todo ID")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid todo ID %q", args[0])
+	}
+	return id, nil
+}
@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"todo-service/client"
+	"todo-service/internal/model"
+)
+
+// migratePage is how many TODOs or projects migrate reads per request.
+const migratePage = 100
+
+// checkpoint records how far a migration got, so that an interrupted one can
+// resume without copying TODOs twice.
+type checkpoint struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Todos maps the IDs of fully copied source TODOs to their copies.
+	Todos map[int64]int64 `json:"todos"`
+	// InFlight is a TODO that has been created on the target but may still
+	// need to be scheduled, archived, or updated.
+	InFlight *copiedTodo `json:"in_flight,omitempty"`
+}
+
+type copiedTodo struct {
+	Source model.Todo `json:"source"`
+	Target int64      `json:"target"`
+}
+
+// migrator copies the configuration and TODOs of one server to another.
+type migrator struct {
+	from, to *httpBackend
+	path     string
+	cp       checkpoint
+	projects map[int64]int64
+	out      io.Writer
+}
+
+// migrate copies everything from one server to another through their APIs.
+// Progress is saved to the checkpoint file at path after every TODO.
+func migrate(from, to *httpBackend, path string, out io.Writer) error {
+	if from.base == to.base {
+		return errors.New("-from and -to must be different servers")
+	}
+
+	m := &migrator{from: from, to: to, path: path, out: out}
+	if err := m.load(); err != nil {
+		return err
+	}
+
+	if err := m.copyConfig(); err != nil {
+		return err
+	}
+	if err := m.mapProjects(); err != nil {
+		return err
+	}
+
+	skipped := len(m.cp.Todos)
+	if m.cp.InFlight != nil {
+		if err := m.finish(m.cp.InFlight.Source, m.cp.InFlight.Target); err != nil {
+			return err
+		}
+	}
+	copied := 0
+	for _, archived := range []bool{false, true} {
+		n, err := m.copyTodos(archived)
+		if err != nil {
+			return err
+		}
+		copied += n
+	}
+
+	fmt.Fprintf(out, "copied %d todos", copied)
+	if skipped > 0 {
+		fmt.Fprintf(out, " (%d already copied)", skipped)
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// load reads the checkpoint file, or starts a new one if there is none.
+func (m *migrator) load() error {
+	m.cp = checkpoint{From: m.from.base, To: m.to.base, Todos: map[int64]int64{}}
+
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("decode checkpoint %s: %w", m.path, err)
+	}
+	if cp.From != m.from.base || cp.To != m.to.base {
+		return fmt.Errorf("checkpoint %s is for a migration from %s to %s", m.path, cp.From, cp.To)
+	}
+	if cp.Todos == nil {
+		cp.Todos = map[int64]int64{}
+	}
+	m.cp = cp
+	fmt.Fprintf(m.out, "resuming from %s\n", m.path)
+	return nil
+}
+
+// save writes the checkpoint file, replacing it atomically.
+func (m *migrator) save() error {
+	data, err := json.Marshal(m.cp)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// copyConfig applies the source's categories and projects to the target.
+// Applying is idempotent, so it is repeated on every run.
+func (m *migrator) copyConfig() error {
+	data, err := m.from.exportConfig()
+	if err != nil {
+		return fmt.Errorf("export config from %s: %w", m.from.base, err)
+	}
+	result, err := m.to.applyConfig(data, false)
+	if err != nil {
+		return fmt.Errorf("apply config to %s: %w", m.to.base, err)
+	}
+	fmt.Fprintf(m.out, "config: %d categories and %d projects created\n",
+		len(result.Categories.Created), len(result.Projects.Created))
+	return nil
+}
+
+// mapProjects matches source project IDs to target ones by name.
+func (m *migrator) mapProjects() error {
+	src, err := m.from.allProjects()
+	if err != nil {
+		return err
+	}
+	dst, err := m.to.allProjects()
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]int64, len(dst))
+	for _, p := range dst {
+		ids[p.Name] = p.ID
+	}
+	m.projects = make(map[int64]int64, len(src))
+	for _, p := range src {
+		id, ok := ids[p.Name]
+		if !ok {
+			return fmt.Errorf("project %q is missing from %s", p.Name, m.to.base)
+		}
+		m.projects[p.ID] = id
+	}
+	return nil
+}
+
+// copyTodos copies the active or archived TODOs not copied yet, oldest
+// first, and returns how many it copied.
+func (m *migrator) copyTodos(archived bool) (int, error) {
+	copied := 0
+	for offset := 0; ; offset += migratePage {
+		page, err := m.from.todoPage(archived, offset)
+		if err != nil {
+			return copied, err
+		}
+
+		for _, todo := range page.Todos {
+			if _, ok := m.cp.Todos[todo.ID]; ok {
+				continue
+			}
+			if err := m.copyTodo(todo); err != nil {
+				return copied, err
+			}
+			copied++
+		}
+
+		if offset+migratePage >= page.Total {
+			return copied, nil
+		}
+	}
+}
+
+// copyTodo creates a copy of todo on the target, records it in the
+// checkpoint, and then finishes it.
+func (m *migrator) copyTodo(todo model.Todo) error {
+	var project *int64
+	if todo.ProjectID != nil {
+		id := m.projects[*todo.ProjectID]
+		project = &id
+	}
+
+	var created model.Todo
+	var err error
+	if todo.Triage == model.TriagePending {
+		// Only captures land in the inbox; the rest of the TODO is set by
+		// finish.
+		created, err = m.to.capture(model.CaptureTodoRequest{Title: todo.Title, Description: todo.Description})
+	} else {
+		created, err = m.to.create(model.CreateTodoRequest{
+			Title:           todo.Title,
+			Description:     todo.Description,
+			Status:          todo.Status,
+			Category:        todo.Category,
+			ProjectID:       project,
+			ProgressPercent: &todo.ProgressPercent,
+			Priority:        todo.Priority,
+			DueDate:         todo.DueDate,
+			EstimateMinutes: &todo.EstimateMinutes,
+			Color:           todo.Color,
+			Icon:            todo.Icon,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("copy todo %d: %w", todo.ID, err)
+	}
+
+	m.cp.InFlight = &copiedTodo{Source: todo, Target: created.ID}
+	if err := m.save(); err != nil {
+		return err
+	}
+	return m.finish(todo, created.ID)
+}
+
+// finish sets what creating a TODO cannot on its copy, marks it copied, and
+// saves the checkpoint. Every step is idempotent, so an interrupted finish
+// is simply repeated.
+func (m *migrator) finish(todo model.Todo, target int64) error {
+	if todo.Triage == model.TriagePending {
+		update := model.UpdateTodoRequest{
+			Category:        &todo.Category,
+			ProgressPercent: &todo.ProgressPercent,
+			Priority:        &todo.Priority,
+			DueDate:         todo.DueDate,
+			EstimateMinutes: &todo.EstimateMinutes,
+			Color:           &todo.Color,
+			Icon:            &todo.Icon,
+		}
+		if todo.Status != model.StatusPending {
+			update.Status = &todo.Status
+		}
+		if todo.ProjectID != nil {
+			id := m.projects[*todo.ProjectID]
+			update.ProjectID = &id
+		}
+		if _, err := m.to.update(target, update); err != nil {
+			return fmt.Errorf("copy todo %d: %w", todo.ID, err)
+		}
+	}
+	if todo.ScheduledFor != nil {
+		if _, err := m.to.c.ScheduleTodo(context.Background(), target, *todo.ScheduledFor); err != nil {
+			return fmt.Errorf("schedule todo %d: %w", todo.ID, err)
+		}
+	}
+	if todo.Archived {
+		if _, err := m.to.c.ArchiveTodo(context.Background(), target); err != nil {
+			return fmt.Errorf("archive todo %d: %w", todo.ID, err)
+		}
+	}
+
+	m.cp.Todos[todo.ID] = target
+	m.cp.InFlight = nil
+	if err := m.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.out, "copied todo %d to %d\n", todo.ID, target)
+	return nil
+}
+
+func (b *httpBackend) capture(req model.CaptureTodoRequest) (model.Todo, error) {
+	return b.c.CaptureTodo(context.Background(), req)
+}
+
+// todoPage lists a page of active or archived TODOs, oldest first.
+func (b *httpBackend) todoPage(archived bool, offset int) (model.TodoListResponse, error) {
+	return b.c.ListTodos(context.Background(), client.ListTodosOptions{Archived: archived, Limit: migratePage, Offset: offset, Sort: "id"})
+}
+
+// allProjects lists every project, a page at a time.
+func (b *httpBackend) allProjects() ([]model.Project, error) {
+	var projects []model.Project
+	for offset := 0; ; offset += migratePage {
+		resp, err := b.c.ListProjects(context.Background(), migratePage, offset, "")
+		if err != nil {
+			return nil, fmt.Errorf("list projects on %s: %w", b.base, err)
+		}
+		projects = append(projects, resp.Projects...)
+		if offset+migratePage >= resp.Total {
+			return projects, nil
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"todo-service/internal/model"
+)
+
+// printTodos writes todos as an aligned table or as a JSON array.
+func (o *options) printTodos(out io.Writer, todos []model.Todo) error {
+	if o.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(todos)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tPROGRESS\tCATEGORY\tPROJECT\tTITLE")
+	for _, t := range todos {
+		project := "-"
+		if t.ProjectID != nil {
+			project = fmt.Sprint(*t.ProjectID)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d%%\t%s\t%s\t%s\n", t.ID, t.Status, t.ProgressPercent, t.Category, project, t.Title)
+	}
+	return tw.Flush()
+}
+
+// printConfigResult writes the changes applying a configuration made, or
+// would make with a dry run, as lines of text or as JSON.
+func (o *options) printConfigResult(out io.Writer, result model.ApplyConfigResponse) error {
+	if o.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	verb := "applied"
+	if result.DryRun {
+		verb = "would apply"
+	}
+	for _, kind := range []struct {
+		name    string
+		changes model.ConfigChanges
+	}{
+		{"category", result.Categories},
+		{"project", result.Projects},
+	} {
+		for _, name := range kind.changes.Created {
+			fmt.Fprintf(out, "%s: create %s %q\n", verb, kind.name, name)
+		}
+		for _, name := range kind.changes.Updated {
+			fmt.Fprintf(out, "%s: update %s %q\n", verb, kind.name, name)
+		}
+		fmt.Fprintf(out, "%s: %d created, %d updated, %d unchanged\n", kind.name,
+			len(kind.changes.Created), len(kind.changes.Updated), len(kind.changes.Unchanged))
+	}
+	return nil
+}
+
+// printBenchResult writes a bench run's latencies as an aligned table or as
+// JSON.
+func (o *options) printBenchResult(out io.Writer, result benchResult) error {
+	if o.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if result.Populated > 0 {
+		fmt.Fprintf(out, "populated %d todos in %.2fs (%.0f/s)\n", result.Populated, result.PopulateSeconds, float64(result.Populated)/result.PopulateSeconds)
+	}
+	fmt.Fprintf(out, "ran %d requests over %d connections in %.2fs (%.0f/s)\n\n", result.Requests, result.Concurrency, result.Seconds, float64(result.Requests)/result.Seconds)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OP\tCOUNT\tERRORS\tP50 MS\tP95 MS\tP99 MS\tMAX MS\t")
+	for _, s := range result.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t\n", s.Name, s.Count, s.Errors, s.P50, s.P95, s.P99, s.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, s := range result.Operations {
+		if s.FirstError != "" {
+			fmt.Fprintf(out, "first %s error: %s\n", s.Name, s.FirstError)
+		}
+	}
+	return nil
+}
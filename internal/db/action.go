@@ -0,0 +1,221 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrActionExists is returned when an action name is already taken.
+var ErrActionExists = errors.New("action name already exists")
+
+// ActionSort describes the fields action lists can be sorted by.
+var ActionSort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"operation":  "operation",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// actionParams is how an action's operands are stored.
+type actionParams struct {
+	Filter *model.ViewFilter        `json:"filter,omitempty"`
+	Todo   *model.CreateTodoRequest `json:"todo,omitempty"`
+}
+
+// CreateAction inserts a new action and returns it.
+func (r *SQLite) CreateAction(req model.ActionRequest) (model.Action, error) {
+	params, err := json.Marshal(actionParams{Filter: req.Filter, Todo: req.Todo})
+	if err != nil {
+		return model.Action{}, fmt.Errorf("encode action params: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Action{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkActionName(tx, req.Name, 0); err != nil {
+		return model.Action{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO actions (name, operation, params) VALUES (?, ?, ?)`,
+		req.Name, string(req.Operation), string(params),
+	)
+	if err != nil {
+		return model.Action{}, fmt.Errorf("insert action: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Action{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	action, err := getAction(tx, id)
+	if err != nil {
+		return model.Action{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Action{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return action, nil
+}
+
+// GetAction retrieves a single action by ID.
+func (r *SQLite) GetAction(id int64) (model.Action, error) {
+	return getAction(r.db, id)
+}
+
+func getAction(q querier, id int64) (model.Action, error) {
+	row := q.QueryRow(actionSelect+` WHERE id = ?`, id)
+
+	a, err := scanAction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Action{}, ErrNotFound
+	}
+	return a, err
+}
+
+// ListActions retrieves actions sorted and paginated by opts.
+func (r *SQLite) ListActions(opts query.Options) ([]model.Action, error) {
+	q, args := opts.Apply(actionSelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query actions: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []model.Action{}
+	for rows.Next() {
+		a, err := scanAction(rows)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+
+	return actions, rows.Err()
+}
+
+// CountActions returns the number of actions.
+func (r *SQLite) CountActions() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM actions`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count actions: %w", err)
+	}
+	return count, nil
+}
+
+// ReplaceAction replaces every field of an action.
+func (r *SQLite) ReplaceAction(id int64, req model.ActionRequest) (model.Action, error) {
+	params, err := json.Marshal(actionParams{Filter: req.Filter, Todo: req.Todo})
+	if err != nil {
+		return model.Action{}, fmt.Errorf("encode action params: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Action{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getAction(tx, id); err != nil {
+		return model.Action{}, err
+	}
+	if err := checkActionName(tx, req.Name, id); err != nil {
+		return model.Action{}, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE actions SET name = ?, operation = ?, params = ?, updated_at = unixepoch() WHERE id = ?`,
+		req.Name, string(req.Operation), string(params), id,
+	)
+	if err != nil {
+		return model.Action{}, fmt.Errorf("update action: %w", err)
+	}
+
+	action, err := getAction(tx, id)
+	if err != nil {
+		return model.Action{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Action{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return action, nil
+}
+
+// DeleteAction deletes an action. TODOs it created or changed are not
+// affected.
+func (r *SQLite) DeleteAction(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM actions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete action: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkActionName returns ErrActionExists if an action other than id
+// already uses name.
+func checkActionName(q querier, name string, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM actions WHERE name = ? AND id != ?)`, name, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check action name: %w", err)
+	}
+	if exists {
+		return ErrActionExists
+	}
+	return nil
+}
+
+const actionSelect = `SELECT id, name, operation, params, created_at, updated_at FROM actions`
+
+// scanAction scans a single row selected with actionSelect into an Action.
+// sql.ErrNoRows is returned unwrapped.
+func scanAction(row rowScanner) (model.Action, error) {
+	var a model.Action
+	var operation, params string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&a.ID, &a.Name, &operation, &params, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Action{}, err
+	}
+	if err != nil {
+		return model.Action{}, fmt.Errorf("scan action: %w", err)
+	}
+	var p actionParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return model.Action{}, fmt.Errorf("decode params of action %d: %w", a.ID, err)
+	}
+
+	a.Operation = model.ActionOperation(operation)
+	a.Filter, a.Todo = p.Filter, p.Todo
+	a.CreatedAt = unixTime(createdAt)
+	a.UpdatedAt = unixTime(updatedAt)
+
+	return a, nil
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// AuditInfo identifies who performed a mutation and as part of which request
+// and operation. Every audit row written for one logical mutation, including
+// cascades such as an auto-archive run, shares the same OperationID.
+type AuditInfo struct {
+	Actor       string
+	RequestID   string
+	OperationID string
+	// SyncRef is the client's reference of the sync change making the
+	// mutation, or "" outside sync. See SyncedChange.
+	SyncRef string
+}
+
+// NewOperationID returns a random identifier for a logical mutation.
+func NewOperationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "op_" + hex.EncodeToString(b)
+}
+
+// AuditFilter narrows the entries returned by ListAudit. Nil fields are ignored.
+type AuditFilter struct {
+	TodoID      *int64
+	AfterID     *int64
+	Action      *model.AuditAction
+	OperationID *string
+	From        *time.Time
+	To          *time.Time
+	// StatusTo matches entries that set a TODO's status to this one,
+	// including creating it with this status.
+	StatusTo *model.Status
+}
+
+func (f AuditFilter) where() query.Where {
+	var w query.Where
+	if f.TodoID != nil {
+		w.Add("todo_id = ?", *f.TodoID)
+	}
+	if f.AfterID != nil {
+		w.Add("id > ?", *f.AfterID)
+	}
+	if f.Action != nil {
+		w.Add("action = ?", string(*f.Action))
+	}
+	if f.OperationID != nil {
+		w.Add("operation_id = ?", *f.OperationID)
+	}
+	if f.From != nil {
+		w.Add("created_at >= ?", f.From.Unix())
+	}
+	if f.To != nil {
+		w.Add("created_at <= ?", f.To.Unix())
+	}
+	if f.StatusTo != nil {
+		w.Add("json_extract(changes, '$.status.new') = ?", string(*f.StatusTo))
+	}
+	return w
+}
+
+// AuditSort describes the fields audit lists can be sorted by.
+var AuditSort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"todo_id":    "todo_id",
+		"action":     "action",
+		"actor":      "actor",
+		"created_at": "created_at",
+	},
+	Default: []query.Sort{{Field: "id", Desc: true}},
+}
+
+// ListAudit retrieves the audit entries matching filter, sorted and paginated by opts.
+func (r *SQLite) ListAudit(filter AuditFilter, opts query.Options) ([]model.AuditEntry, error) {
+	q := `SELECT id, todo_id, action, actor, request_id, operation_id, changes, created_at
+	FROM audit_log`
+	q, args := filter.where().Apply(q, nil)
+	q, args = opts.Apply(q, args)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		var action, changes string
+		var createdAt int64
+		if err := rows.Scan(&e.ID, &e.TodoID, &action, &e.Actor, &e.RequestID, &e.OperationID, &changes, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.Action = model.AuditAction(action)
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, fmt.Errorf("decode audit changes: %w", err)
+		}
+		e.CreatedAt = unixTime(createdAt)
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountAudit returns the number of audit entries matching filter.
+func (r *SQLite) CountAudit(filter AuditFilter) (int, error) {
+	q, args := filter.where().Apply(`SELECT COUNT(*) FROM audit_log`, nil)
+
+	var count int
+	if err := r.db.QueryRow(q, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count audit log: %w", err)
+	}
+	return count, nil
+}
+
+// writeAudit records a mutation of a todo within tx, in the audit log and in
+// the undo log. Only fields whose values differ between before and after are
+// stored in the audit log; a nil side means the todo did not exist on that
+// side of the mutation. The entry records the version after, or for a
+// deletion the version deleted.
+func writeAudit(tx *sql.Tx, action model.AuditAction, todoID int64, before, after *model.Todo, info AuditInfo) error {
+	if err := insertAudit(tx, action, todoID, before, after, info); err != nil {
+		return err
+	}
+	return writeUndo(tx, action, todoID, before, after, info)
+}
+
+// insertAudit records a mutation in the audit log only, as writeAudit does.
+func insertAudit(tx *sql.Tx, action model.AuditAction, todoID int64, before, after *model.Todo, info AuditInfo) error {
+	changes, err := json.Marshal(diffTodos(before, after))
+	if err != nil {
+		return fmt.Errorf("encode audit changes: %w", err)
+	}
+	version := after
+	if version == nil {
+		version = before
+	}
+	var syncRef any
+	if info.SyncRef != "" {
+		syncRef = info.SyncRef
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO audit_log (todo_id, action, actor, request_id, operation_id, changes, version, sync_ref) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		todoID, string(action), info.Actor, info.RequestID, info.OperationID, string(changes), version.Version, syncRef,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// auditFields returns the user-editable fields of t keyed by their JSON name.
+// Each custom field value is a field of its own, named custom_fields.<name>.
+func auditFields(t *model.Todo) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	fields := map[string]any{
+		"title":            t.Title,
+		"description":      t.Description,
+		"status":           string(t.Status),
+		"category":         string(t.Category),
+		"project_id":       ptrValue(t.ProjectID),
+		"progress_percent": t.ProgressPercent,
+		"estimate_minutes": t.EstimateMinutes,
+		"priority":         string(t.Priority),
+		"due_date":         ptrValue(t.DueDate),
+		"archived":         t.Archived,
+		"scheduled_for":    ptrValue(t.ScheduledFor),
+		"triage":           string(t.Triage),
+		"assignee":         t.Assignee,
+		"pinned":           t.Pinned,
+		"snoozed_until":    ptrValue(t.SnoozedUntil),
+		"location":         ptrValue(t.Location),
+		"color":            t.Color,
+		"icon":             t.Icon,
+		"position":         t.Position,
+	}
+	for name, v := range t.CustomFields {
+		fields["custom_fields."+name] = v
+	}
+	return fields
+}
+
+// ptrValue dereferences p so that equal optional values compare equal.
+func ptrValue[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func diffTodos(before, after *model.Todo) map[string]model.FieldChange {
+	old, cur := auditFields(before), auditFields(after)
+	changes := map[string]model.FieldChange{}
+
+	for name, v := range cur {
+		if ov, ok := old[name]; !ok || ov != v {
+			changes[name] = model.FieldChange{Old: old[name], New: v}
+		}
+	}
+	for name, ov := range old {
+		if _, ok := cur[name]; !ok {
+			changes[name] = model.FieldChange{Old: ov}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Backends a repository can store its data in.
+const (
+	// BackendSQLite stores data in a SQLite database file.
+	BackendSQLite = "sqlite"
+	// BackendMemory stores data in a SQLite database held in memory, which
+	// is lost when the repository is closed. It behaves exactly like
+	// BackendSQLite, so tests and demos can use it without a file.
+	BackendMemory = "memory"
+)
+
+// memoryDatabases numbers in-memory databases, so that each repository
+// opened by OpenMemory has its own.
+var memoryDatabases atomic.Int64
+
+// OpenBackend opens a repository on backend without touching its schema,
+// like Open: the SQLite database at path, or a new in-memory database, for
+// which path is ignored.
+func OpenBackend(backend, path string, logger *slog.Logger) (*SQLite, error) {
+	switch backend {
+	case BackendSQLite:
+		return Open(path, logger)
+	case BackendMemory:
+		return OpenMemory(logger)
+	}
+	return nil, fmt.Errorf("unknown database backend %q, want %s or %s", backend, BackendSQLite, BackendMemory)
+}
+
+// OpenMemory opens a repository on a new, empty database held in memory.
+// Callers must run Migrate before using it. The data is lost on Close.
+func OpenMemory(logger *slog.Logger) (*SQLite, error) {
+	// The repository's single connection is never closed while it is open,
+	// so the database lives as long as the repository.
+	name := fmt.Sprintf("memdb-%d", memoryDatabases.Add(1))
+	repo, err := open("file:"+name+"?mode=memory&cache=shared&_pragma=foreign_keys(1)", "", logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("database initialized in memory")
+	return repo, nil
+}
+
+// NewMemory opens a repository on a new in-memory database and applies the
+// migrations, for tests and demos.
+func NewMemory(logger *slog.Logger) (*SQLite, error) {
+	repo, err := OpenMemory(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.Migrate(); err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return repo, nil
+}
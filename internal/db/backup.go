@@ -0,0 +1,13 @@
+package db
+
+import "fmt"
+
+// Snapshot writes a consistent copy of the database to path, which must not
+// exist. Other statements wait until it finishes, since the repository uses
+// a single connection.
+func (r *SQLite) Snapshot(path string) error {
+	if _, err := r.db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("vacuum into %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// seedTodos inserts n pending TODOs into repo in one transaction, without
+// the audit entries CreateTodo would write.
+func seedTodos(b *testing.B, repo *SQLite, n int) {
+	b.Helper()
+	tx, err := repo.db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tx.Rollback()
+	for i := range n {
+		_, err := tx.Exec(`INSERT INTO todos (title, description, progress_percent) VALUES (?, ?, ?)`,
+			fmt.Sprintf("todo %d", i), "Milk, eggs, bread", i%100)
+		if err != nil {
+			b.Fatalf("seed todos: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("seed todos: %v", err)
+	}
+}
+
+func BenchmarkListTodos(b *testing.B) {
+	const rows = 10000
+	repo := newTestRepo(b)
+	seedTodos(b, repo, rows)
+
+	done := model.StatusDone
+	for _, bench := range []struct {
+		name   string
+		filter TodoFilter
+		limit  int
+		want   int
+	}{
+		{"page", TodoFilter{}, 20, 20},
+		{"max page", TodoFilter{}, query.MaxLimit, query.MaxLimit},
+		{"all", TodoFilter{}, 0, rows},
+		// A large limit that few rows meet should not allocate for the limit.
+		{"max page of none", TodoFilter{Status: &done}, query.MaxLimit, 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				todos, err := repo.ListTodos(bench.filter, query.Options{Limit: bench.limit})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(todos) != bench.want {
+					b.Fatalf("listed %d todos, want %d", len(todos), bench.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-service/internal/model"
+)
+
+// ErrTodoArchived is returned when a TODO that is archived is moved on the
+// board, which only shows active TODOs.
+var ErrTodoArchived = errors.New("todo is archived")
+
+// ErrWIPLimit is returned when a TODO is moved into a board column that
+// already holds as many TODOs as its WIP limit allows.
+var ErrWIPLimit = errors.New("column is at its WIP limit")
+
+// MoveCard moves an active TODO to the board column for req.Status and, if
+// req gives a place, to that place among the column's TODOs, in a single
+// transaction. A status change must be allowed by the repository's
+// transitions and, if limit is positive, leave the target column with at
+// most limit TODOs. Moving a TODO where it already is has no effect.
+func (r *SQLite) MoveCard(req model.MoveCardRequest, limit int, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, req.TodoID)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if before.Archived {
+		return model.Todo{}, ErrTodoArchived
+	}
+
+	var setClauses []string
+	var args []any
+	if req.Status != before.Status {
+		if !r.transitions.Allows(before.Status, req.Status) {
+			return model.Todo{}, &TransitionError{From: before.Status, To: req.Status}
+		}
+		if limit > 0 {
+			var n int
+			err := tx.QueryRow(
+				`SELECT COUNT(*) FROM todos WHERE status = ? AND archived = 0`,
+				string(req.Status),
+			).Scan(&n)
+			if err != nil {
+				return model.Todo{}, fmt.Errorf("count column todos: %w", err)
+			}
+			if n >= limit {
+				return model.Todo{}, ErrWIPLimit
+			}
+		}
+		setClauses = append(setClauses, statusClauses(req.Status)...)
+		args = append(args, string(req.Status))
+	}
+
+	if m, ok := req.Place(); ok {
+		moved := before
+		moved.Status = req.Status
+		position, changed, err := place(tx, moved, m, true)
+		if err != nil {
+			return model.Todo{}, err
+		}
+		if changed {
+			setClauses = append(setClauses, "position = ?")
+			args = append(args, position)
+		}
+	}
+	if len(setClauses) == 0 {
+		return before, nil
+	}
+
+	setClauses = append(setClauses, "updated_at = unixepoch()", "version = version + 1")
+	args = append(args, req.TodoID)
+
+	query := fmt.Sprintf("UPDATE todos SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return model.Todo{}, fmt.Errorf("move card: %w", err)
+	}
+
+	after, err := getTodo(tx, req.TodoID)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, req.TodoID, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"container/list"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// readCache keeps the results of recent TODO reads, evicting the least
+// recently used once it holds size of them. Each result is tagged with the
+// write generation it was read at and is ignored once any statement has
+// been executed since, so it never outlives a change made through the
+// repository. The TTL bounds how long a change made by another process, such
+// as a CLI command run against the same file, can go unnoticed.
+type readCache struct {
+	size int
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element
+	lru       list.List // of *readCacheEntry, most recently used first
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type readCacheEntry struct {
+	key        string
+	value      any
+	generation uint64
+	stored     time.Time
+}
+
+// SetReadCache caches up to size results of GetTodo, ListTodos, and
+// CountTodos for at most ttl each. A size of 0, the default, disables the
+// cache. It must be called before the repository is used.
+func (r *SQLite) SetReadCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		r.cache = nil
+		return
+	}
+	r.cache = &readCache{size: size, ttl: ttl, entries: map[string]*list.Element{}}
+}
+
+// CacheStats reports how well the read cache is doing.
+func (r *SQLite) CacheStats() model.CacheStats {
+	if r.cache == nil {
+		return model.CacheStats{}
+	}
+	return r.cache.stats()
+}
+
+// cached returns the value stored under key, or calls read and stores its
+// result. Values are copied on the way in and out with clone, so callers
+// may modify them.
+func cached[T any](r *SQLite, key string, clone func(T) T, read func() (T, error)) (T, error) {
+	if r.cache == nil {
+		return read()
+	}
+	generation := r.queries.writes.Load()
+	if v, ok := r.cache.get(key, generation); ok {
+		return clone(v.(T)), nil
+	}
+	v, err := read()
+	if err != nil {
+		return v, err
+	}
+	r.cache.put(key, clone(v), generation)
+	return v, nil
+}
+
+// cacheTodo stores todo, just committed, as GetTodo would read it. The
+// generation must be taken before the commit, while the transaction still
+// holds the only connection, so that any later write invalidates it.
+func (r *SQLite) cacheTodo(todo model.Todo, generation uint64) {
+	if r.cache != nil {
+		r.cache.put(todoCacheKey(todo.ID), cloneTodo(todo), generation)
+	}
+}
+
+func todoCacheKey(id int64) string {
+	return fmt.Sprintf("todo:%d", id)
+}
+
+// queryCacheKey identifies a query by its statement and arguments.
+func queryCacheKey(query string, args []any) string {
+	return fmt.Sprintf("query:%s\x00%v", query, args)
+}
+
+func sameCount(n int) int { return n }
+
+// cloneTodo copies t, including its custom field values.
+func cloneTodo(t model.Todo) model.Todo {
+	t.CustomFields = maps.Clone(t.CustomFields)
+	return t
+}
+
+func cloneTodos(t []model.Todo) []model.Todo {
+	clone := make([]model.Todo, len(t))
+	for i, todo := range t {
+		clone[i] = cloneTodo(todo)
+	}
+	return clone
+}
+
+func (c *readCache) get(key string, generation uint64) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	e := el.Value.(*readCacheEntry)
+	if e.generation != generation || (c.ttl > 0 && time.Since(e.stored) >= c.ttl) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		c.misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	c.hits++
+	return e.value, true
+}
+
+func (c *readCache) put(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &readCacheEntry{key: key, value: value, generation: generation, stored: time.Now()}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*readCacheEntry).key)
+		c.evictions++
+	}
+}
+
+func (c *readCache) stats() model.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return model.CacheStats{
+		Size:       c.lru.Len(),
+		Capacity:   c.size,
+		TTLSeconds: c.ttl.Seconds(),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+}
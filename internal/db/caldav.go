@@ -0,0 +1,124 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// CalDAVObject is the resource name and iCalendar UID a CalDAV client gave
+// a TODO it created.
+type CalDAVObject struct {
+	Name   string
+	UID    string
+	TodoID int64
+}
+
+// CalDAVObjects returns every recorded CalDAV object, by TODO ID, including
+// those of deleted TODOs.
+func (r *SQLite) CalDAVObjects() (map[int64]CalDAVObject, error) {
+	rows, err := r.db.Query(`SELECT name, uid, todo_id FROM caldav_objects`)
+	if err != nil {
+		return nil, fmt.Errorf("query caldav objects: %w", err)
+	}
+	defer rows.Close()
+
+	objects := map[int64]CalDAVObject{}
+	for rows.Next() {
+		var o CalDAVObject
+		if err := rows.Scan(&o.Name, &o.UID, &o.TodoID); err != nil {
+			return nil, fmt.Errorf("scan caldav object: %w", err)
+		}
+		objects[o.TodoID] = o
+	}
+
+	return objects, rows.Err()
+}
+
+// CalDAVObject returns the CalDAV object named name, or ErrNotFound.
+func (r *SQLite) CalDAVObject(name string) (CalDAVObject, error) {
+	o := CalDAVObject{Name: name}
+	err := r.db.QueryRow(`SELECT uid, todo_id FROM caldav_objects WHERE name = ?`, name).Scan(&o.UID, &o.TodoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CalDAVObject{}, ErrNotFound
+	}
+	if err != nil {
+		return CalDAVObject{}, fmt.Errorf("query caldav object: %w", err)
+	}
+	return o, nil
+}
+
+// CreateCalDAVTodo creates a TODO, scheduled for scheduledFor if not nil,
+// and records it as the CalDAV object obj, replacing any object of the same
+// name, in one transaction. If the TODO would break the repository's rules,
+// a *RuleError is returned and nothing is written.
+func (r *SQLite) CreateCalDAVTodo(obj CalDAVObject, req model.CreateTodoRequest, scheduledFor *string, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	todo, err := insertTodo(tx, req, model.TriageDone, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if scheduledFor != nil {
+		if todo, err = setSchedule(tx, todo.ID, scheduledFor, info); err != nil {
+			return model.Todo{}, err
+		}
+	}
+	if err := r.checkRules(todo); err != nil {
+		return model.Todo{}, err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO caldav_objects (name, uid, todo_id) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET uid = excluded.uid, todo_id = excluded.todo_id`,
+		obj.Name, obj.UID, todo.ID,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("save caldav object: %w", err)
+	}
+	generation := r.queries.writes.Load()
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.cacheTodo(todo, generation)
+
+	return todo, nil
+}
+
+// LatestAuditID returns the ID of the newest audit log entry, or 0 if the
+// log is empty. Every change to a TODO adds an entry, so it changes exactly
+// when some TODO has.
+func (r *SQLite) LatestAuditID() (int64, error) {
+	var id int64
+	if err := r.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM audit_log`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("query latest audit id: %w", err)
+	}
+	return id, nil
+}
+
+// TodosChangedSince returns the IDs of the TODOs created, updated, or
+// deleted after the audit log entry with ID auditID.
+func (r *SQLite) TodosChangedSince(auditID int64) ([]int64, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT todo_id FROM audit_log WHERE id > ? ORDER BY todo_id`, auditID)
+	if err != nil {
+		return nil, fmt.Errorf("query changed todos: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan changed todo: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// CalendarTodo is a TODO due or scheduled on a day, with just the fields
+// needed to count it. A TODO both due and scheduled is listed once for each.
+type CalendarTodo struct {
+	Date   string
+	ID     int64
+	Status model.Status
+	Due    bool
+}
+
+// ListCalendar returns the unarchived TODOs due or scheduled from one day to
+// another, inclusive, ordered by day and ID, with due dates before scheduled
+// days. Only the fields needed to count them are read, so it is cheap for
+// long ranges.
+func (r *SQLite) ListCalendar(from, to string) ([]CalendarTodo, error) {
+	rows, err := r.db.Query(`
+		SELECT due_date, id, status, 1 FROM todos
+		WHERE archived = 0 AND due_date >= ? AND due_date <= ?
+		UNION ALL
+		SELECT scheduled_for, id, status, 0 FROM todos
+		WHERE archived = 0 AND scheduled_for >= ? AND scheduled_for <= ?
+		ORDER BY 1, 2, 4 DESC`, from, to, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query calendar todos: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []CalendarTodo
+	for rows.Next() {
+		var t CalendarTodo
+		if err := rows.Scan(&t.Date, &t.ID, &t.Status, &t.Due); err != nil {
+			return nil, fmt.Errorf("scan calendar todo: %w", err)
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"todo-service/internal/model"
+)
+
+// Cascade rules
+//
+// Everything that refers to a TODO, a project or a category is kept
+// consistent here, in the transaction of the change that would otherwise
+// leave it dangling. Views and actions refer to projects and categories
+// inside their stored JSON, where no foreign key can follow them.
+//
+// TODOs:
+//   - Deleting a TODO moves it to the trash. Its audit entries, undo entries
+//     and GitHub issue link are kept, so that restoring it or undoing the
+//     deletion brings it back as it was, still mirroring its issue. Its
+//     share links and their accesses are deleted, through the foreign keys,
+//     and do not come back.
+//   - Purging a TODO from the trash, by hand or by retention, keeps its
+//     audit entries, so its history outlives it, and its CalDAV object, so
+//     sync reports can name the resource that was deleted. The undo entries
+//     of every operation that touched it are deleted, so an operation is
+//     never undone only in part, and its GitHub issue link is kept without
+//     the TODO, so the sync does not mirror the issue again.
+//   - Undoing a TODO's creation deletes it as above.
+//   - Archiving or unarchiving a TODO changes nothing that refers to it.
+//
+// Projects, whose TODOs are unassigned, reassigned or deleted as
+// DeleteProject is asked to:
+//   - Reassigned: views and actions that refer to the project refer to the
+//     new one instead.
+//   - Otherwise: views and actions filtered by the project are deleted, as
+//     they can no longer match anything, and create actions make their TODOs
+//     without a project.
+//
+// Categories, whose TODOs follow a rename through the foreign key and are
+// moved by DeleteCategory:
+//   - Renamed, or deleted with a category to reassign to: views and actions
+//     refer to the new name.
+//   - Deleted while unused: the category is dropped from view and action
+//     filters, filters left without a category are deleted as above, and
+//     create actions make their TODOs in the default category.
+
+// deleteTodo moves before to the trash within tx and records its deletion
+// in the audit log under info.
+func deleteTodo(tx *sql.Tx, before model.Todo, info AuditInfo) error {
+	if err := removeTodo(tx, before, info); err != nil {
+		return err
+	}
+	return writeAudit(tx, model.AuditActionDelete, before.ID, &before, nil, info)
+}
+
+// removeTodo moves t to the trash within tx, as deleted by info.Actor,
+// without recording the deletion, for callers that record it themselves.
+func removeTodo(tx *sql.Tx, t model.Todo, info AuditInfo) error {
+	if _, err := tx.Exec(`DELETE FROM todos WHERE id = ?`, t.ID); err != nil {
+		return fmt.Errorf("delete todo: %w", err)
+	}
+	return trashTodo(tx, t, info)
+}
+
+// purgeTodos removes the TODOs in the trash matching where, with args, for
+// good within tx, and returns how many it removed.
+func purgeTodos(tx *sql.Tx, where string, args ...any) (int64, error) {
+	purged := `SELECT todo_id FROM trash WHERE ` + where
+	_, err := tx.Exec(
+		`DELETE FROM undo_log WHERE operation_id IN (SELECT operation_id FROM undo_log WHERE todo_id IN (`+purged+`))`,
+		args...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete undo entries: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE github_issue_links SET todo_id = NULL WHERE todo_id IN (`+purged+`)`, args...); err != nil {
+		return 0, fmt.Errorf("unlink github issues: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM trash WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("delete from trash: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// cascadeProject applies the cascade rules for deleting project id. to is
+// the project its TODOs were reassigned to, or 0.
+func cascadeProject(tx *sql.Tx, id, to int64) error {
+	return rewriteReferences(tx,
+		func(f *model.ViewFilter) bool {
+			if f.ProjectID == nil || *f.ProjectID != id {
+				return true
+			}
+			if to == 0 {
+				return false
+			}
+			f.ProjectID = &to
+			return true
+		},
+		func(t *model.CreateTodoRequest) {
+			if t.ProjectID != nil && *t.ProjectID == id {
+				t.ProjectID = nil
+				if to != 0 {
+					t.ProjectID = &to
+				}
+			}
+		},
+	)
+}
+
+// cascadeCategory applies the cascade rules for renaming category from to
+// to, or for deleting it if to is empty.
+func cascadeCategory(tx *sql.Tx, from, to model.Category) error {
+	return rewriteReferences(tx,
+		func(f *model.ViewFilter) bool {
+			i := slices.Index(f.Categories, from)
+			if i < 0 {
+				return true
+			}
+			f.Categories = slices.Delete(slices.Clone(f.Categories), i, i+1)
+			if to != "" && !slices.Contains(f.Categories, to) {
+				f.Categories = slices.Insert(f.Categories, i, to)
+			}
+			return len(f.Categories) > 0
+		},
+		func(t *model.CreateTodoRequest) {
+			if t.Category == from {
+				t.Category = to
+			}
+		},
+	)
+}
+
+// rewriteReferences passes the filter of every view and action to filter,
+// and the template of every create action to template, saving those they
+// change. A view or action whose filter is rejected by filter is deleted.
+func rewriteReferences(tx *sql.Tx, filter func(*model.ViewFilter) bool, template func(*model.CreateTodoRequest)) error {
+	views, err := storedJSON[model.ViewFilter](tx, `SELECT id, filter FROM views`)
+	if err != nil {
+		return fmt.Errorf("select views: %w", err)
+	}
+	for id, f := range views {
+		if !filter(&f) {
+			if _, err := tx.Exec(`DELETE FROM views WHERE id = ?`, id); err != nil {
+				return fmt.Errorf("delete view: %w", err)
+			}
+			continue
+		}
+		if err := saveJSON(tx, `UPDATE views SET filter = ?, updated_at = unixepoch() WHERE id = ? AND filter != ?`, id, f); err != nil {
+			return fmt.Errorf("update view: %w", err)
+		}
+	}
+
+	actions, err := storedJSON[actionParams](tx, `SELECT id, params FROM actions`)
+	if err != nil {
+		return fmt.Errorf("select actions: %w", err)
+	}
+	for id, p := range actions {
+		if p.Filter != nil {
+			f := *p.Filter
+			if !filter(&f) {
+				if _, err := tx.Exec(`DELETE FROM actions WHERE id = ?`, id); err != nil {
+					return fmt.Errorf("delete action: %w", err)
+				}
+				continue
+			}
+			p.Filter = &f
+		}
+		if p.Todo != nil {
+			t := *p.Todo
+			template(&t)
+			p.Todo = &t
+		}
+		if err := saveJSON(tx, `UPDATE actions SET params = ?, updated_at = unixepoch() WHERE id = ? AND params != ?`, id, p); err != nil {
+			return fmt.Errorf("update action: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// storedJSON decodes the JSON column selected by query, keyed by the id
+// selected before it.
+func storedJSON[T any](tx *sql.Tx, query string) (map[int64]T, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[int64]T)
+	for rows.Next() {
+		var id int64
+		var raw string
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, err
+		}
+		var v T
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("decode %d: %w", id, err)
+		}
+		values[id] = v
+	}
+	return values, rows.Err()
+}
+
+// saveJSON encodes v and runs update with it, id and it again, so that the
+// update can skip rows whose JSON is unchanged.
+func saveJSON(tx *sql.Tx, update string, id int64, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(update, string(raw), id, string(raw))
+	return err
+}
@@ -0,0 +1,482 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// cascadeFixture is a repository with two projects, a custom category, a
+// TODO in each project, and views and actions that refer to them.
+type cascadeFixture struct {
+	repo             *SQLite
+	home, work       model.Project
+	todoHome         model.Todo
+	todoWork         model.Todo
+	viewHome         model.View
+	viewErrands      model.View
+	viewMixed        model.View
+	filterAction     model.Action
+	createAction     model.Action
+	untouchedView    model.View
+	untouchedActions []model.Action
+}
+
+func newCascadeFixture(t *testing.T) *cascadeFixture {
+	t.Helper()
+	repo := newTestRepo(t)
+	f := &cascadeFixture{repo: repo}
+
+	var err error
+	if f.home, err = repo.CreateProject(model.CreateProjectRequest{Name: "home"}); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if f.work, err = repo.CreateProject(model.CreateProjectRequest{Name: "work"}); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if _, err := repo.CreateCategory(model.CreateCategoryRequest{Name: "errands", SortOrder: 3}); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	if f.todoHome, err = repo.CreateTodo(model.CreateTodoRequest{Title: "paint", ProjectID: &f.home.ID}, AuditInfo{}); err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	if f.todoWork, err = repo.CreateTodo(model.CreateTodoRequest{Title: "report", ProjectID: &f.work.ID}, AuditInfo{}); err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+
+	view := func(name string, filter model.ViewFilter) model.View {
+		v, err := repo.CreateView(model.CreateViewRequest{Name: name, Filter: filter})
+		if err != nil {
+			t.Fatalf("CreateView: %v", err)
+		}
+		return v
+	}
+	f.viewHome = view("home", model.ViewFilter{ProjectID: &f.home.ID})
+	f.viewErrands = view("errands", model.ViewFilter{Categories: []model.Category{"errands"}})
+	f.viewMixed = view("mixed", model.ViewFilter{Categories: []model.Category{"work", "errands"}})
+	f.untouchedView = view("work", model.ViewFilter{ProjectID: &f.work.ID, Categories: []model.Category{"work"}})
+
+	action := func(req model.ActionRequest) model.Action {
+		a, err := repo.CreateAction(req)
+		if err != nil {
+			t.Fatalf("CreateAction: %v", err)
+		}
+		return a
+	}
+	f.filterAction = action(model.ActionRequest{
+		Name:      "finish home errand",
+		Operation: model.ActionCompleteFirst,
+		Filter:    &model.ViewFilter{ProjectID: &f.home.ID, Categories: []model.Category{"errands"}},
+	})
+	f.createAction = action(model.ActionRequest{
+		Name:      "new home errand",
+		Operation: model.ActionCreate,
+		Todo:      &model.CreateTodoRequest{Title: "errand", Category: "errands", ProjectID: &f.home.ID},
+	})
+	f.untouchedActions = []model.Action{
+		action(model.ActionRequest{
+			Name:      "start work",
+			Operation: model.ActionStartFirst,
+			Filter:    &model.ViewFilter{ProjectID: &f.work.ID},
+		}),
+		action(model.ActionRequest{
+			Name:      "new work item",
+			Operation: model.ActionCreate,
+			Todo:      &model.CreateTodoRequest{Title: "item", Category: "work", ProjectID: &f.work.ID},
+		}),
+	}
+	return f
+}
+
+// getView returns the view with id, or nil if it was deleted.
+func (f *cascadeFixture) getView(t *testing.T, id int64) *model.View {
+	t.Helper()
+	v, err := f.repo.GetView(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("GetView(%d): %v", id, err)
+	}
+	return &v
+}
+
+// getAction returns the action with id, or nil if it was deleted.
+func (f *cascadeFixture) getAction(t *testing.T, id int64) *model.Action {
+	t.Helper()
+	a, err := f.repo.GetAction(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("GetAction(%d): %v", id, err)
+	}
+	return &a
+}
+
+// checkUntouched fails if the view and actions that refer only to the work
+// project and category were changed.
+func (f *cascadeFixture) checkUntouched(t *testing.T) {
+	t.Helper()
+	if v := f.getView(t, f.untouchedView.ID); v == nil || v.UpdatedAt != f.untouchedView.UpdatedAt || !slices.Equal(v.Filter.Categories, f.untouchedView.Filter.Categories) {
+		t.Errorf("view %q changed to %+v", f.untouchedView.Name, v)
+	}
+	for _, want := range f.untouchedActions {
+		if a := f.getAction(t, want.ID); a == nil || a.UpdatedAt != want.UpdatedAt {
+			t.Errorf("action %q changed to %+v", want.Name, a)
+		}
+	}
+	if _, err := f.repo.GetTodo(f.todoWork.ID); err != nil {
+		t.Errorf("GetTodo(%d): %v", f.todoWork.ID, err)
+	}
+}
+
+func TestCascadeTodo(t *testing.T) {
+	t.Run("delete keeps audit entries", func(t *testing.T) {
+		f := newCascadeFixture(t)
+		if err := f.repo.DeleteTodo(f.todoHome.ID, 0, AuditInfo{}); err != nil {
+			t.Fatalf("DeleteTodo: %v", err)
+		}
+		entries, err := f.repo.ListAudit(AuditFilter{TodoID: &f.todoHome.ID}, testOptions(t, AuditSort))
+		if err != nil {
+			t.Fatalf("ListAudit: %v", err)
+		}
+		if len(entries) != 2 || entries[0].Action != model.AuditActionDelete {
+			t.Errorf("got %d audit entries, want the create and the delete", len(entries))
+		}
+		if v := f.getView(t, f.viewHome.ID); v == nil {
+			t.Errorf("view %q was deleted with a TODO it listed", f.viewHome.Name)
+		}
+		f.checkUntouched(t)
+	})
+
+	t.Run("archive and unarchive change nothing else", func(t *testing.T) {
+		f := newCascadeFixture(t)
+		for _, archived := range []bool{true, false} {
+			if _, err := f.repo.SetArchived(f.todoHome.ID, archived, AuditInfo{}); err != nil {
+				t.Fatalf("SetArchived(%v): %v", archived, err)
+			}
+		}
+		if v := f.getView(t, f.viewHome.ID); v == nil || v.UpdatedAt != f.viewHome.UpdatedAt {
+			t.Errorf("view %q changed to %+v", f.viewHome.Name, v)
+		}
+		if a := f.getAction(t, f.createAction.ID); a == nil || a.UpdatedAt != f.createAction.UpdatedAt {
+			t.Errorf("action %q changed to %+v", f.createAction.Name, a)
+		}
+		f.checkUntouched(t)
+	})
+}
+
+func TestCascadeProjectDelete(t *testing.T) {
+	tests := []struct {
+		todos ProjectTodos
+		// reassign says whether references move to the work project rather
+		// than being removed.
+		reassign bool
+	}{
+		{todos: ProjectTodosUnassign},
+		{todos: ProjectTodosReassign, reassign: true},
+		{todos: ProjectTodosDelete},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.todos), func(t *testing.T) {
+			f := newCascadeFixture(t)
+			var to int64
+			if tt.reassign {
+				to = f.work.ID
+			}
+			if _, err := f.repo.DeleteProject(f.home.ID, tt.todos, to, AuditInfo{}); err != nil {
+				t.Fatalf("DeleteProject: %v", err)
+			}
+
+			todo, err := f.repo.GetTodo(f.todoHome.ID)
+			switch {
+			case tt.todos == ProjectTodosDelete:
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("GetTodo of a deleted project's TODO: got %v, want ErrNotFound", err)
+				}
+			case err != nil:
+				t.Fatalf("GetTodo: %v", err)
+			case tt.reassign && (todo.ProjectID == nil || *todo.ProjectID != to):
+				t.Errorf("TODO project = %v, want %d", todo.ProjectID, to)
+			case !tt.reassign && todo.ProjectID != nil:
+				t.Errorf("TODO project = %d, want none", *todo.ProjectID)
+			}
+
+			v := f.getView(t, f.viewHome.ID)
+			a := f.getAction(t, f.filterAction.ID)
+			if tt.reassign {
+				if v == nil || *v.Filter.ProjectID != to {
+					t.Errorf("view filtered by the project = %+v, want it filtered by %d", v, to)
+				}
+				if a == nil || *a.Filter.ProjectID != to {
+					t.Errorf("action filtered by the project = %+v, want it filtered by %d", a, to)
+				}
+			} else {
+				if v != nil {
+					t.Errorf("view filtered by the project was kept: %+v", v)
+				}
+				if a != nil {
+					t.Errorf("action filtered by the project was kept: %+v", a)
+				}
+			}
+
+			c := f.getAction(t, f.createAction.ID)
+			switch {
+			case c == nil:
+				t.Errorf("create action was deleted")
+			case tt.reassign && (c.Todo.ProjectID == nil || *c.Todo.ProjectID != to):
+				t.Errorf("create action project = %v, want %d", c.Todo.ProjectID, to)
+			case !tt.reassign && c.Todo.ProjectID != nil:
+				t.Errorf("create action project = %d, want none", *c.Todo.ProjectID)
+			}
+			f.checkUntouched(t)
+		})
+	}
+}
+
+func TestCascadeCategory(t *testing.T) {
+	errands := func(t *testing.T, f *cascadeFixture) int64 {
+		t.Helper()
+		categories, err := f.repo.ListCategories(testOptions(t, CategorySort))
+		if err != nil {
+			t.Fatalf("ListCategories: %v", err)
+		}
+		for _, c := range categories {
+			if c.Name == "errands" {
+				return c.ID
+			}
+		}
+		t.Fatal("errands category not found")
+		return 0
+	}
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T, f *cascadeFixture, id int64)
+		// to is the category references are renamed to, or "" if the
+		// category is removed from them.
+		to model.Category
+	}{
+		{
+			name: "rename",
+			change: func(t *testing.T, f *cascadeFixture, id int64) {
+				name := model.Category("chores")
+				if _, err := f.repo.UpdateCategory(id, model.UpdateCategoryRequest{Name: &name}, AuditInfo{}); err != nil {
+					t.Fatalf("UpdateCategory: %v", err)
+				}
+			},
+			to: "chores",
+		},
+		{
+			name: "delete reassigning",
+			change: func(t *testing.T, f *cascadeFixture, id int64) {
+				if _, err := f.repo.DeleteCategory(id, "personal", AuditInfo{}); err != nil {
+					t.Fatalf("DeleteCategory: %v", err)
+				}
+			},
+			to: "personal",
+		},
+		{
+			name: "delete unused",
+			change: func(t *testing.T, f *cascadeFixture, id int64) {
+				if _, err := f.repo.DeleteCategory(id, "", AuditInfo{}); err != nil {
+					t.Fatalf("DeleteCategory: %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCascadeFixture(t)
+			tt.change(t, f, errands(t, f))
+
+			v := f.getView(t, f.viewErrands.ID)
+			a := f.getAction(t, f.filterAction.ID)
+			if tt.to == "" {
+				if v != nil {
+					t.Errorf("view filtered only by the category was kept: %+v", v)
+				}
+				if a != nil {
+					t.Errorf("action filtered only by the category was kept: %+v", a)
+				}
+			} else {
+				if v == nil || !slices.Equal(v.Filter.Categories, []model.Category{tt.to}) {
+					t.Errorf("view filtered by the category = %+v, want it filtered by %q", v, tt.to)
+				}
+				if a == nil || !slices.Equal(a.Filter.Categories, []model.Category{tt.to}) {
+					t.Errorf("action filtered by the category = %+v, want it filtered by %q", a, tt.to)
+				}
+			}
+
+			want := []model.Category{"work"}
+			if tt.to != "" {
+				want = append(want, tt.to)
+			}
+			if m := f.getView(t, f.viewMixed.ID); m == nil || !slices.Equal(m.Filter.Categories, want) {
+				t.Errorf("view filtered by several categories = %+v, want categories %v", m, want)
+			}
+
+			if c := f.getAction(t, f.createAction.ID); c == nil || c.Todo.Category != tt.to {
+				t.Errorf("create action = %+v, want category %q", c, tt.to)
+			}
+			f.checkUntouched(t)
+		})
+	}
+}
+
+func testOptions(t *testing.T, spec query.Spec) query.Options {
+	t.Helper()
+	opts, err := query.Params{}.Options(spec)
+	if err != nil {
+		t.Fatalf("Options: %v", err)
+	}
+	return opts
+}
+
+// related counts the rows that refer to a TODO, by table.
+type related struct {
+	shares, accesses, undo, trash, caldav int
+	// linked reports whether the TODO's GitHub issue link still points at it;
+	// unlinked whether the link is there without a TODO.
+	linked, unlinked bool
+}
+
+func relatedRows(t *testing.T, repo *SQLite, id int64) related {
+	t.Helper()
+	count := func(query string, args ...any) int {
+		t.Helper()
+		var n int
+		if err := repo.db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	return related{
+		shares:   count(`SELECT COUNT(*) FROM shares WHERE todo_id = ?`, id),
+		accesses: count(`SELECT COUNT(*) FROM share_accesses a JOIN shares s ON s.id = a.share_id WHERE s.todo_id = ?`, id) + count(`SELECT COUNT(*) FROM share_accesses WHERE share_id NOT IN (SELECT id FROM shares)`),
+		undo:     count(`SELECT COUNT(*) FROM undo_log WHERE todo_id = ?`, id),
+		trash:    count(`SELECT COUNT(*) FROM trash WHERE todo_id = ?`, id),
+		caldav:   count(`SELECT COUNT(*) FROM caldav_objects WHERE todo_id = ?`, id),
+		linked:   count(`SELECT COUNT(*) FROM github_issue_links WHERE todo_id = ?`, id) == 1,
+		unlinked: count(`SELECT COUNT(*) FROM github_issue_links WHERE todo_id IS NULL`) == 1,
+	}
+}
+
+// newRelatedTodo creates a TODO with a share that has been opened, a GitHub
+// issue link, and a CalDAV object.
+func newRelatedTodo(t *testing.T, repo *SQLite, info AuditInfo) model.Todo {
+	t.Helper()
+	todo, err := repo.CreateCalDAVTodo(CalDAVObject{Name: "groceries.ics", UID: "uid-1"}, model.CreateTodoRequest{Title: "Buy groceries"}, nil, info)
+	if err != nil {
+		t.Fatalf("CreateCalDAVTodo: %v", err)
+	}
+	share, err := repo.CreateShare(todo.ID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShare: %v", err)
+	}
+	if _, _, err := repo.OpenShare(share.Token, time.Now(), &model.ShareAccess{RemoteAddr: "127.0.0.1"}); err != nil {
+		t.Fatalf("OpenShare: %v", err)
+	}
+	link := GitHubLink{Repo: "octo/todo", Number: 7, TodoID: &todo.ID, State: "open", Title: todo.Title}
+	if err := repo.SaveGitHubLink(link); err != nil {
+		t.Fatalf("SaveGitHubLink: %v", err)
+	}
+	return todo
+}
+
+func TestCascadeTrash(t *testing.T) {
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "alice", OperationID: "create"}
+	todo := newRelatedTodo(t, repo, info)
+
+	got := relatedRows(t, repo, todo.ID)
+	want := related{shares: 1, accesses: 1, undo: 1, caldav: 1, linked: true}
+	if got != want {
+		t.Fatalf("after create: %+v, want %+v", got, want)
+	}
+
+	// Deleting moves the TODO to the trash. Its shares go with it, but what
+	// restoring or undoing needs stays.
+	info.OperationID = "delete"
+	if err := repo.DeleteTodo(todo.ID, todo.Version, info); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	got = relatedRows(t, repo, todo.ID)
+	want = related{undo: 2, trash: 1, caldav: 1, linked: true}
+	if got != want {
+		t.Fatalf("after delete: %+v, want %+v", got, want)
+	}
+
+	// Restoring brings it back linked to its issue.
+	info.OperationID = "restore"
+	todo, err := repo.RestoreTodo(todo.ID, info)
+	if err != nil {
+		t.Fatalf("RestoreTodo: %v", err)
+	}
+	got = relatedRows(t, repo, todo.ID)
+	want = related{undo: 3, caldav: 1, linked: true}
+	if got != want {
+		t.Fatalf("after restore: %+v, want %+v", got, want)
+	}
+
+	// Purging removes the undo entries and unlinks the issue. The CalDAV
+	// object stays for sync reports.
+	info.OperationID = "delete again"
+	if err := repo.DeleteTodo(todo.ID, todo.Version, info); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	if err := repo.PurgeTodo(todo.ID); err != nil {
+		t.Fatalf("PurgeTodo: %v", err)
+	}
+	got = relatedRows(t, repo, todo.ID)
+	want = related{caldav: 1, unlinked: true}
+	if got != want {
+		t.Fatalf("after purge: %+v, want %+v", got, want)
+	}
+	entries, err := repo.ListAudit(AuditFilter{TodoID: &todo.ID}, testOptions(t, AuditSort))
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("got %d audit entries after purge, want all 4 kept", len(entries))
+	}
+}
+
+// TestCascadePurgeKeepsOperationsWhole checks that purging one TODO removes
+// the undo entries of every TODO deleted in the same operation, so that
+// undoing it does not restore only some of them.
+func TestCascadePurgeKeepsOperationsWhole(t *testing.T) {
+	f := newCascadeFixture(t)
+	info := AuditInfo{Actor: "alice", OperationID: "delete project"}
+	paint := f.todoHome
+	clean, err := f.repo.CreateTodo(model.CreateTodoRequest{Title: "clean", ProjectID: &f.home.ID}, AuditInfo{Actor: "alice", OperationID: "create clean"})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+
+	if n, err := f.repo.DeleteProject(f.home.ID, ProjectTodosDelete, 0, info); err != nil || n != 2 {
+		t.Fatalf("DeleteProject = %d, %v; want 2 TODOs", n, err)
+	}
+	if err := f.repo.PurgeTodo(paint.ID); err != nil {
+		t.Fatalf("PurgeTodo: %v", err)
+	}
+	if got, want := relatedRows(t, f.repo, clean.ID), (related{undo: 1, trash: 1}); got != want {
+		t.Errorf("TODO deleted with the purged one: %+v, want %+v", got, want)
+	}
+
+	// The deletion is gone from the undo log, so undo reaches the creation
+	// of the TODO still in the trash, which it conflicts with.
+	var conflict *UndoConflictError
+	if _, err := f.repo.Undo(info, time.Now()); !errors.As(err, &conflict) || conflict.TodoID != clean.ID {
+		t.Errorf("Undo: got %v, want a conflict on TODO %d", err, clean.ID)
+	}
+	if _, err := f.repo.RestoreTodo(clean.ID, info); err != nil {
+		t.Errorf("RestoreTodo of the other TODO: %v", err)
+	}
+	f.checkUntouched(t)
+}
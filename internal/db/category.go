@@ -0,0 +1,386 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrCategoryExists is returned when a category name is already taken.
+var ErrCategoryExists = errors.New("category name already exists")
+
+// ErrCategoryNotFound is returned when a TODO is filed under a category that
+// does not exist, or when no category exists to default a new TODO to.
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrCategoryInUse is returned when deleting a category that TODOs are still
+// filed under without saying where to move them.
+var ErrCategoryInUse = errors.New("category in use")
+
+// CategorySort describes the fields category lists can be sorted by.
+var CategorySort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"sort_order": "sort_order",
+		"todos":      "todos",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Default: []query.Sort{{Field: "sort_order"}, {Field: "name"}},
+}
+
+// CreateCategory inserts a new category and returns it.
+func (r *SQLite) CreateCategory(req model.CreateCategoryRequest) (model.CategoryInfo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkCategoryName(tx, req.Name, 0); err != nil {
+		return model.CategoryInfo{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO categories (name, color, icon, sort_order) VALUES (?, ?, ?, ?)`,
+		string(req.Name), req.Color, req.Icon, req.SortOrder,
+	)
+	if err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("insert category: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	category, err := getCategory(tx, id)
+	if err != nil {
+		return model.CategoryInfo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetCategory retrieves a single category by ID with its TODO count.
+func (r *SQLite) GetCategory(id int64) (model.CategoryInfo, error) {
+	return getCategory(r.db, id)
+}
+
+func getCategory(q querier, id int64) (model.CategoryInfo, error) {
+	row := q.QueryRow(categorySelect+` WHERE id = ?`, id)
+
+	c, err := scanCategory(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CategoryInfo{}, ErrNotFound
+	}
+	return c, err
+}
+
+// ListCategories retrieves categories sorted and paginated by opts.
+func (r *SQLite) ListCategories(opts query.Options) ([]model.CategoryInfo, error) {
+	q, args := opts.Apply(categorySelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []model.CategoryInfo{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+// CountCategories returns the number of categories.
+func (r *SQLite) CountCategories() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM categories`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count categories: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateCategory updates only the provided fields of a category. Renaming a
+// category renames it on its TODOs through the foreign key, and each of them
+// is recorded in the audit log under info. Views and actions follow the
+// rename through the cascade rules.
+func (r *SQLite) UpdateCategory(id int64, req model.UpdateCategoryRequest, info AuditInfo) (model.CategoryInfo, error) {
+	var setClauses []string
+	var args []any
+
+	if req.Name != nil {
+		setClauses = append(setClauses, "name = ?")
+		args = append(args, string(*req.Name))
+	}
+	if req.Color != nil {
+		setClauses = append(setClauses, "color = ?")
+		args = append(args, *req.Color)
+	}
+	if req.Icon != nil {
+		setClauses = append(setClauses, "icon = ?")
+		args = append(args, *req.Icon)
+	}
+	if req.SortOrder != nil {
+		setClauses = append(setClauses, "sort_order = ?")
+		args = append(args, *req.SortOrder)
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetCategory(id)
+	}
+
+	setClauses = append(setClauses, "updated_at = unixepoch()")
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getCategory(tx, id)
+	if err != nil {
+		return model.CategoryInfo{}, err
+	}
+
+	var members []model.Todo
+	if req.Name != nil && *req.Name != before.Name {
+		if err := checkCategoryName(tx, *req.Name, id); err != nil {
+			return model.CategoryInfo{}, err
+		}
+		if members, err = selectTodos(tx, `category = ?`, string(before.Name)); err != nil {
+			return model.CategoryInfo{}, err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE categories SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("update category: %w", err)
+	}
+
+	for _, todo := range members {
+		if err := touchTodo(tx, todo, info); err != nil {
+			return model.CategoryInfo{}, err
+		}
+	}
+	if req.Name != nil && *req.Name != before.Name {
+		if err := cascadeCategory(tx, before.Name, *req.Name); err != nil {
+			return model.CategoryInfo{}, err
+		}
+	}
+
+	category, err := getCategory(tx, id)
+	if err != nil {
+		return model.CategoryInfo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return category, nil
+}
+
+// applyCategories makes the categories match specs by name within tx:
+// missing categories are created and existing ones get the given color,
+// icon, and sort order. Categories not in specs are left alone.
+func applyCategories(tx *sql.Tx, specs []model.CreateCategoryRequest) (model.ConfigChanges, error) {
+	changes := model.ConfigChanges{Created: []string{}, Updated: []string{}, Unchanged: []string{}}
+
+	for _, spec := range specs {
+		name := string(spec.Name)
+		var id int64
+		var color, icon string
+		var sortOrder int
+		err := tx.QueryRow(`SELECT id, color, icon, sort_order FROM categories WHERE name = ?`, name).Scan(&id, &color, &icon, &sortOrder)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			_, err := tx.Exec(`INSERT INTO categories (name, color, icon, sort_order) VALUES (?, ?, ?, ?)`, name, spec.Color, spec.Icon, spec.SortOrder)
+			if err != nil {
+				return changes, fmt.Errorf("insert category: %w", err)
+			}
+			changes.Created = append(changes.Created, name)
+		case err != nil:
+			return changes, fmt.Errorf("query category: %w", err)
+		case color != spec.Color || icon != spec.Icon || sortOrder != spec.SortOrder:
+			_, err := tx.Exec(
+				`UPDATE categories SET color = ?, icon = ?, sort_order = ?, updated_at = unixepoch() WHERE id = ?`,
+				spec.Color, spec.Icon, spec.SortOrder, id,
+			)
+			if err != nil {
+				return changes, fmt.Errorf("update category: %w", err)
+			}
+			changes.Updated = append(changes.Updated, name)
+		default:
+			changes.Unchanged = append(changes.Unchanged, name)
+		}
+	}
+
+	return changes, nil
+}
+
+// DeleteCategory deletes a category. If TODOs are filed under it, reassignTo
+// must name another existing category to move them to, otherwise
+// ErrCategoryInUse or ErrCategoryNotFound is returned. Every moved TODO is
+// recorded in the audit log under info, and views and actions follow the
+// cascade rules. It returns the number of TODOs moved.
+func (r *SQLite) DeleteCategory(id int64, reassignTo model.Category, info AuditInfo) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	category, err := getCategory(tx, id)
+	if err != nil {
+		return 0, err
+	}
+	if reassignTo != "" {
+		if reassignTo == category.Name {
+			return 0, ErrCategoryNotFound
+		}
+		if err := checkCategory(tx, reassignTo); err != nil {
+			return 0, err
+		}
+	}
+
+	members, err := selectTodos(tx, `category = ?`, string(category.Name))
+	if err != nil {
+		return 0, err
+	}
+	if len(members) > 0 && reassignTo == "" {
+		return 0, ErrCategoryInUse
+	}
+
+	for _, before := range members {
+		_, err := tx.Exec(
+			`UPDATE todos SET category = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+			string(reassignTo), before.ID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("move todo: %w", err)
+		}
+		after, err := getTodo(tx, before.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := cascadeCategory(tx, category.Name, reassignTo); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM categories WHERE id = ?`, id); err != nil {
+		return 0, fmt.Errorf("delete category: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return len(members), nil
+}
+
+// touchTodo bumps the version of a TODO whose row was changed through a
+// foreign key and records the change in the audit log.
+func touchTodo(tx *sql.Tx, before model.Todo, info AuditInfo) error {
+	_, err := tx.Exec(`UPDATE todos SET updated_at = unixepoch(), version = version + 1 WHERE id = ?`, before.ID)
+	if err != nil {
+		return fmt.Errorf("touch todo: %w", err)
+	}
+	after, err := getTodo(tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info)
+}
+
+// checkCategory returns ErrCategoryNotFound unless the category exists.
+func checkCategory(q querier, name model.Category) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM categories WHERE name = ?)`, string(name)).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check category: %w", err)
+	}
+	if !exists {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// defaultCategory returns the category new TODOs are filed under when none
+// is given: the first by sort order.
+func defaultCategory(q querier) (model.Category, error) {
+	var name string
+	err := q.QueryRow(`SELECT name FROM categories ORDER BY sort_order, name LIMIT 1`).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrCategoryNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("query default category: %w", err)
+	}
+	return model.Category(name), nil
+}
+
+// checkCategoryName returns ErrCategoryExists if a category other than id
+// already uses name.
+func checkCategoryName(q querier, name model.Category, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM categories WHERE name = ? AND id != ?)`, string(name), id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check category name: %w", err)
+	}
+	if exists {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+// categorySelect selects categories with the number of TODOs filed under
+// each as a derived table, so that ORDER BY can refer to the count by name.
+const categorySelect = `SELECT id, name, color, icon, sort_order, todos, created_at, updated_at
+FROM (
+	SELECT c.id, c.name, c.color, c.icon, c.sort_order, c.created_at, c.updated_at,
+		(SELECT COUNT(*) FROM todos t WHERE t.category = c.name) AS todos
+	FROM categories c
+)`
+
+// scanCategory scans a single row selected with categorySelect into a
+// CategoryInfo. sql.ErrNoRows is returned unwrapped.
+func scanCategory(row rowScanner) (model.CategoryInfo, error) {
+	var c model.CategoryInfo
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&c.ID, &c.Name, &c.Color, &c.Icon, &c.SortOrder, &c.Todos, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CategoryInfo{}, err
+	}
+	if err != nil {
+		return model.CategoryInfo{}, fmt.Errorf("scan category: %w", err)
+	}
+
+	c.CreatedAt = unixTime(createdAt)
+	c.UpdatedAt = unixTime(updatedAt)
+
+	return c, nil
+}
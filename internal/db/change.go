@@ -0,0 +1,75 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"todo-service/internal/model"
+)
+
+// Changes returns the change feed after the audit log entry with ID after:
+// every TODO created, updated, or deleted since, once, with its latest
+// entry and in the order of those entries, up to limit. It also returns
+// whether more changes are waiting. The audit log is the feed's change
+// log, so its entry IDs are the feed's sequence.
+func (r *SQLite) Changes(after int64, limit int) ([]model.Change, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT todo_id, MAX(id) AS seq, MAX(created_at) FROM audit_log WHERE id > ? GROUP BY todo_id ORDER BY seq LIMIT ?`,
+		after, limit+1,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("query changes: %w", err)
+	}
+	changes := []model.Change{}
+	for rows.Next() {
+		var c model.Change
+		var changedAt int64
+		if err := rows.Scan(&c.TodoID, &c.Seq, &changedAt); err != nil {
+			rows.Close()
+			return nil, false, fmt.Errorf("scan change: %w", err)
+		}
+		c.ChangedAt = unixTime(changedAt)
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("iterate changes: %w", err)
+	}
+
+	more := len(changes) > limit
+	if more {
+		changes = changes[:limit]
+	}
+	if len(changes) == 0 {
+		return changes, false, nil
+	}
+
+	ids := make([]any, len(changes))
+	for i, c := range changes {
+		ids[i] = c.TodoID
+	}
+	todos, err := selectTodos(tx, `id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, ids...)
+	if err != nil {
+		return nil, false, err
+	}
+	byID := make(map[int64]model.Todo, len(todos))
+	for _, t := range todos {
+		byID[t.ID] = t
+	}
+	for i, c := range changes {
+		if t, ok := byID[c.TodoID]; ok {
+			changes[i].Kind = model.ChangeUpsert
+			changes[i].Todo = &t
+		} else {
+			changes[i].Kind = model.ChangeDelete
+		}
+	}
+
+	return changes, more, nil
+}
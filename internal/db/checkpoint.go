@@ -0,0 +1,37 @@
+package db
+
+import (
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// Checkpoint copies every page in the write-ahead log into the database
+// file and truncates the log to nothing. SQLite checkpoints on its own as
+// the log grows but never shrinks the file, which keeps the size of the
+// largest burst of writes since the service started. If another connection
+// to the database is reading or writing, the checkpoint copies what it can
+// and reports that it was busy.
+func (r *SQLite) Checkpoint() (model.CheckpointResponse, error) {
+	var resp model.CheckpointResponse
+	var err error
+	if resp.WALBytesBefore, err = fileSize(r.path + "-wal"); err != nil {
+		return resp, err
+	}
+
+	var busy, logPages, copiedPages int
+	if err := r.db.QueryRow(`PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logPages, &copiedPages); err != nil {
+		return resp, fmt.Errorf("checkpoint: %w", err)
+	}
+	resp.Busy = busy != 0
+
+	if resp.WALBytesAfter, err = fileSize(r.path + "-wal"); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// WALSize returns the size of the write-ahead log file.
+func (r *SQLite) WALSize() (int64, error) {
+	return fileSize(r.path + "-wal")
+}
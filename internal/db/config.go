@@ -0,0 +1,35 @@
+package db
+
+import (
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// ApplyConfig makes the categories and projects match the given specs in one
+// transaction, creating and updating them by name. Entities not in the specs
+// are left alone. With dryRun, the changes are reported but rolled back.
+func (r *SQLite) ApplyConfig(categories []model.CreateCategoryRequest, projects []model.CreateProjectRequest, dryRun bool) (model.ApplyConfigResponse, error) {
+	result := model.ApplyConfigResponse{DryRun: dryRun}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if result.Categories, err = applyCategories(tx, categories); err != nil {
+		return result, err
+	}
+	if result.Projects, err = applyProjects(tx, projects); err != nil {
+		return result, err
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result, nil
+}
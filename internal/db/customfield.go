@@ -0,0 +1,365 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrCustomFieldExists is returned when a custom field name is already taken.
+var ErrCustomFieldExists = errors.New("custom field name already exists")
+
+// ErrCustomFieldNotEnum is returned when options are given for a custom field
+// that is not an enum.
+var ErrCustomFieldNotEnum = errors.New("custom field is not an enum")
+
+// ErrCustomFieldOptionInUse is returned when an update removes an enum option
+// that TODOs hold.
+var ErrCustomFieldOptionInUse = errors.New("custom field option in use")
+
+// CustomFieldError is returned when a TODO is given a value for a custom
+// field that does not exist, or a value the field does not accept.
+type CustomFieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *CustomFieldError) Error() string {
+	return fmt.Sprintf("custom field %s: %s", e.Field, e.Message)
+}
+
+// CustomFieldSort describes the fields custom field lists can be sorted by.
+var CustomFieldSort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"type":       "type",
+		"todos":      "todos",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// CreateCustomField defines a new custom field and returns it.
+func (r *SQLite) CreateCustomField(req model.CreateCustomFieldRequest) (model.CustomField, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM custom_fields WHERE name = ?)`, req.Name).Scan(&exists); err != nil {
+		return model.CustomField{}, fmt.Errorf("check custom field name: %w", err)
+	}
+	if exists {
+		return model.CustomField{}, ErrCustomFieldExists
+	}
+
+	options, err := encodeOptions(req.Options)
+	if err != nil {
+		return model.CustomField{}, err
+	}
+	result, err := tx.Exec(
+		`INSERT INTO custom_fields (name, type, options) VALUES (?, ?, ?)`,
+		req.Name, string(req.Type), options,
+	)
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("insert custom field: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	field, err := getCustomField(tx, id)
+	if err != nil {
+		return model.CustomField{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CustomField{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return field, nil
+}
+
+// GetCustomField retrieves a single custom field by ID with its TODO count.
+func (r *SQLite) GetCustomField(id int64) (model.CustomField, error) {
+	return getCustomField(r.db, id)
+}
+
+func getCustomField(q querier, id int64) (model.CustomField, error) {
+	row := q.QueryRow(customFieldSelect+` WHERE id = ?`, id)
+
+	f, err := scanCustomField(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CustomField{}, ErrNotFound
+	}
+	return f, err
+}
+
+// ListCustomFields retrieves custom fields sorted and paginated by opts.
+func (r *SQLite) ListCustomFields(opts query.Options) ([]model.CustomField, error) {
+	q, args := opts.Apply(customFieldSelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []model.CustomField{}
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, rows.Err()
+}
+
+// CountCustomFields returns the number of custom fields.
+func (r *SQLite) CountCustomFields() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM custom_fields`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count custom fields: %w", err)
+	}
+	return count, nil
+}
+
+// CustomFieldsByName returns every custom field keyed by name.
+func (r *SQLite) CustomFieldsByName() (map[string]model.CustomField, error) {
+	return customFieldsByName(r.db)
+}
+
+func customFieldsByName(q querier) (map[string]model.CustomField, error) {
+	rows, err := q.Query(customFieldSelect)
+	if err != nil {
+		return nil, fmt.Errorf("query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := map[string]model.CustomField{}
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields[f.Name] = f
+	}
+
+	return fields, rows.Err()
+}
+
+// UpdateCustomField updates only the provided fields of a custom field.
+// Options may only be given for enum fields, and options TODOs hold cannot
+// be removed.
+func (r *SQLite) UpdateCustomField(id int64, req model.UpdateCustomFieldRequest) (model.CustomField, error) {
+	if req.Options == nil {
+		return r.GetCustomField(id)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getCustomField(tx, id)
+	if err != nil {
+		return model.CustomField{}, err
+	}
+	if before.Type != model.CustomFieldEnum {
+		return model.CustomField{}, ErrCustomFieldNotEnum
+	}
+	for _, option := range before.Options {
+		if slices.Contains(*req.Options, option) {
+			continue
+		}
+		var used bool
+		err := tx.QueryRow(
+			`SELECT EXISTS (SELECT 1 FROM todos WHERE json_extract(custom_fields, ?) = ?)`,
+			customFieldPath(before.Name), option,
+		).Scan(&used)
+		if err != nil {
+			return model.CustomField{}, fmt.Errorf("check custom field option: %w", err)
+		}
+		if used {
+			return model.CustomField{}, ErrCustomFieldOptionInUse
+		}
+	}
+
+	options, err := encodeOptions(*req.Options)
+	if err != nil {
+		return model.CustomField{}, err
+	}
+	_, err = tx.Exec(`UPDATE custom_fields SET options = ?, updated_at = unixepoch() WHERE id = ?`, options, id)
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("update custom field: %w", err)
+	}
+
+	field, err := getCustomField(tx, id)
+	if err != nil {
+		return model.CustomField{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CustomField{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return field, nil
+}
+
+// DeleteCustomField deletes a custom field and removes its values from every
+// TODO, recording each of them in the audit log under info. It returns the
+// number of TODOs that held a value.
+func (r *SQLite) DeleteCustomField(id int64, info AuditInfo) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	field, err := getCustomField(tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	path := customFieldPath(field.Name)
+	holders, err := selectTodos(tx, `json_type(custom_fields, ?) IS NOT NULL`, path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, before := range holders {
+		_, err := tx.Exec(
+			`UPDATE todos SET custom_fields = json_remove(custom_fields, ?), updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+			path, before.ID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("remove custom field value: %w", err)
+		}
+		after, err := getTodo(tx, before.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM custom_fields WHERE id = ?`, id); err != nil {
+		return 0, fmt.Errorf("delete custom field: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return len(holders), nil
+}
+
+// mergeCustomFields returns values with patch applied, each value checked
+// against its field's definition. A nil value in patch removes the field's
+// value. A *CustomFieldError is returned for the first bad value by name.
+func mergeCustomFields(q querier, values, patch map[string]any) (map[string]any, error) {
+	merged := maps.Clone(values)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	if len(patch) == 0 {
+		return merged, nil
+	}
+
+	fields, err := customFieldsByName(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(patch)) {
+		v := patch[name]
+		if v == nil {
+			delete(merged, name)
+			continue
+		}
+		field, ok := fields[name]
+		if !ok {
+			return nil, &CustomFieldError{Field: name, Message: fmt.Sprintf("no custom field is named %q", name)}
+		}
+		if merged[name], err = field.Check(v); err != nil {
+			return nil, &CustomFieldError{Field: name, Message: err.Error()}
+		}
+	}
+	return merged, nil
+}
+
+// encodeCustomFields encodes values for the custom_fields column.
+func encodeCustomFields(values map[string]any) (string, error) {
+	if values == nil {
+		values = map[string]any{}
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("encode custom fields: %w", err)
+	}
+	return string(b), nil
+}
+
+// encodeOptions encodes enum options for the options column.
+func encodeOptions(options []string) (string, error) {
+	if options == nil {
+		options = []string{}
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("encode custom field options: %w", err)
+	}
+	return string(b), nil
+}
+
+// customFieldPath is the JSON path of a field's value in the custom_fields
+// column.
+func customFieldPath(name string) string {
+	return `$."` + name + `"`
+}
+
+const customFieldSelect = `SELECT id, name, type, options, todos, created_at, updated_at
+FROM (
+	SELECT f.id, f.name, f.type, f.options, f.created_at, f.updated_at,
+		(SELECT COUNT(*) FROM todos t WHERE json_type(t.custom_fields, '$."' || f.name || '"') IS NOT NULL) AS todos
+	FROM custom_fields f
+)`
+
+// scanCustomField scans a single row selected with customFieldSelect into a
+// CustomField. sql.ErrNoRows is returned unwrapped.
+func scanCustomField(row rowScanner) (model.CustomField, error) {
+	var f model.CustomField
+	var fieldType, options string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&f.ID, &f.Name, &fieldType, &options, &f.Todos, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CustomField{}, err
+	}
+	if err != nil {
+		return model.CustomField{}, fmt.Errorf("scan custom field: %w", err)
+	}
+
+	f.Type = model.CustomFieldType(fieldType)
+	if err := json.Unmarshal([]byte(options), &f.Options); err != nil {
+		return model.CustomField{}, fmt.Errorf("decode custom field options: %w", err)
+	}
+	f.CreatedAt = unixTime(createdAt)
+	f.UpdatedAt = unixTime(updatedAt)
+	return f, nil
+}
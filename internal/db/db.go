@@ -2,37 +2,123 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
 
 	"todo-service/internal/model"
+	"todo-service/internal/query"
 )
 
+// DefaultPath is where the service keeps its database unless told otherwise.
+const DefaultPath = "./data/todos.db"
+
 var ErrNotFound = errors.New("not found")
 
-// Repository provides CRUD operations for TODO items.
-type Repository struct {
-	db     *sql.DB
-	logger *slog.Logger
+// ErrProjectNotFound is returned when a TODO is assigned to a project that
+// does not exist.
+var ErrProjectNotFound = errors.New("project not found")
+
+// ErrVersionMismatch is returned when a conditional mutation targets a TODO
+// whose version has changed since the caller last read it.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// TransitionError is returned when an update would change a TODO's status in
+// a way the repository's transitions do not allow.
+type TransitionError struct {
+	From, To model.Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change status from %s to %s", e.From, e.To)
+}
+
+// RuleError is returned when a TODO would break the repository's rules.
+type RuleError struct {
+	Violations []model.RuleViolation
+}
+
+func (e *RuleError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// SQLite is the Repository backed by a SQLite database, on a file or in
+// memory.
+type SQLite struct {
+	db          *sql.DB
+	path        string
+	logger      *slog.Logger
+	transitions model.Transitions
+	rules       model.Rules
+	queries     *queryLog
+	cache       *readCache // nil unless SetReadCache enabled it
+	undoDepth   int
+	undoWindow  time.Duration
+	retention   time.Duration // how long the trash is kept; 0 keeps it
 }
 
-// New opens a SQLite database and runs migrations.
-func New(dbPath string, logger *slog.Logger) (*Repository, error) {
+// New opens a SQLite database and applies any pending migrations.
+func New(dbPath string, logger *slog.Logger) (*SQLite, error) {
+	repo, err := Open(dbPath, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.Migrate(); err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return repo, nil
+}
+
+// Open opens a SQLite database without touching its schema. Callers must run
+// Migrate or VerifyMigrations before using the repository.
+func Open(dbPath string, logger *slog.Logger) (*SQLite, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return nil, fmt.Errorf("create db directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", "file:"+dbPath+"?cache=shared&mode=rwc&_journal_mode=WAL")
+	repo, err := open("file:"+dbPath+"?cache=shared&mode=rwc&_journal_mode=WAL&_pragma=foreign_keys(1)", dbPath, logger)
 	if err != nil {
-		return nil, fmt.Errorf("open database: %w", err)
+		return nil, err
 	}
+	logger.Info("database initialized", slog.String("db_path", dbPath))
+	return repo, nil
+}
+
+// open opens the SQLite database dsn names, stored at path, or in memory if
+// path is empty.
+func open(dsn, path string, logger *slog.Logger) (*SQLite, error) {
+	// Foreign keys are off by default in SQLite and must be enabled on every
+	// connection; todos refer to categories through one.
+	// Every statement goes through queries, which times it.
+	queries := &queryLog{logger: logger}
+	db := sql.OpenDB(&loggingConnector{
+		dsn:    dsn,
+		driver: &sqlite.Driver{},
+		log:    queries,
+	})
 
 	db.SetMaxOpenConns(1) // SQLite doesn't support concurrent writers
 
@@ -41,104 +127,217 @@ func New(dbPath string, logger *slog.Logger) (*Repository, error) {
 		return nil, fmt.Errorf("enable WAL: %w", err)
 	}
 
-	repo := &Repository{db: db, logger: logger}
+	return &SQLite{
+		db:          db,
+		path:        path,
+		logger:      logger,
+		transitions: model.DefaultTransitions,
+		queries:     queries,
+		undoDepth:   DefaultUndoDepth,
+		undoWindow:  DefaultUndoWindow,
+	}, nil
+}
+
+// SetSlowQueryThreshold makes statements that take at least d log a warning
+// with the statement and its parameters, with text replaced by its length.
+// Zero, the default, disables the warning. It is safe to call at any time.
+func (r *SQLite) SetSlowQueryThreshold(d time.Duration) {
+	r.queries.threshold.Store(int64(d))
+}
 
-	if err := repo.Migrate(); err != nil {
-		return nil, fmt.Errorf("migrate: %w", err)
-	}
+// WriteGeneration returns a number that changes whenever data may have
+// changed, for caches of query results to check. It counts every statement
+// executed rather than queried, so it changes before the write is
+// committed and also for writes that are rolled back.
+func (r *SQLite) WriteGeneration() uint64 {
+	return r.queries.writes.Load()
+}
 
-	logger.Info("database initialized", slog.String("path", dbPath))
-	return repo, nil
+// QueryStats reports how many statements the repository has run and how long
+// they and the wait for the shared connection took.
+func (r *SQLite) QueryStats() model.QueryStats {
+	stats := r.queries.stats()
+	pool := r.db.Stats()
+	stats.ConnWaits = pool.WaitCount
+	stats.ConnWaitMS = millis(pool.WaitDuration)
+	return stats
+}
+
+// SetTransitions replaces the status changes UpdateTodo allows, which default
+// to model.DefaultTransitions. Call it before using the repository.
+func (r *SQLite) SetTransitions(t model.Transitions) {
+	r.transitions = t
+}
+
+// SetRules replaces the rules CreateTodo and UpdateTodo enforce, of which
+// there are none by default. Call it before using the repository.
+func (r *SQLite) SetRules(rules model.Rules) {
+	r.rules = rules
+}
+
+// checkRules returns a *RuleError if todo breaks the repository's rules.
+func (r *SQLite) checkRules(todo model.Todo) error {
+	if violations := r.rules.Check(todo); len(violations) > 0 {
+		return &RuleError{Violations: violations}
+	}
+	return nil
 }
 
 // Close closes the database connection.
-func (r *Repository) Close() error {
+func (r *SQLite) Close() error {
 	return r.db.Close()
 }
 
-// Migrate creates the todos table if it doesn't exist.
-func (r *Repository) Migrate() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS todos (
-		id               INTEGER PRIMARY KEY AUTOINCREMENT,
-		title            TEXT    NOT NULL,
-		description      TEXT    NOT NULL DEFAULT '',
-		status           TEXT    NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'in_progress', 'done')),
-		progress_percent INTEGER NOT NULL DEFAULT 0 CHECK(progress_percent >= 0 AND progress_percent <= 100),
-		created_at       DATETIME NOT NULL DEFAULT (datetime('now')),
-		updated_at       DATETIME NOT NULL DEFAULT (datetime('now'))
-	);
-	CREATE INDEX IF NOT EXISTS idx_todos_status ON todos(status);
-	`
-	if _, err := r.db.Exec(schema); err != nil {
-		return fmt.Errorf("execute schema: %w", err)
+// CreateTodo inserts a new TODO, records it in the audit log, and returns it.
+// If the TODO would break the repository's rules, a *RuleError is returned.
+func (r *SQLite) CreateTodo(req model.CreateTodoRequest, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if err := r.addCategoryColumn(); err != nil {
-		return fmt.Errorf("add category column: %w", err)
+	todo, err := r.createTodo(tx, req, info)
+	if err != nil {
+		return model.Todo{}, err
 	}
+	generation := r.queries.writes.Load()
 
-	r.logger.Info("database migration complete")
-	return nil
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.cacheTodo(todo, generation)
+
+	return todo, nil
 }
 
-// addCategoryColumn adds the category column if it doesn't already exist.
-func (r *Repository) addCategoryColumn() error {
-	rows, err := r.db.Query("PRAGMA table_info(todos)")
+// createTodo is CreateTodo within tx.
+func (r *SQLite) createTodo(tx *sql.Tx, req model.CreateTodoRequest, info AuditInfo) (model.Todo, error) {
+	if req.ProjectID != nil {
+		if err := checkProject(tx, *req.ProjectID); err != nil {
+			return model.Todo{}, err
+		}
+	}
+
+	todo, err := insertTodo(tx, req, model.TriageDone, info)
 	if err != nil {
-		return fmt.Errorf("query table info: %w", err)
+		return model.Todo{}, err
 	}
-	defer rows.Close()
+	if err := r.checkRules(todo); err != nil {
+		return model.Todo{}, err
+	}
+	return todo, nil
+}
 
-	for rows.Next() {
-		var cid int
-		var name, ctype string
-		var notnull int
-		var dfltValue sql.NullString
-		var pk int
-		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
-			return fmt.Errorf("scan table info: %w", err)
-		}
-		if name == "category" {
-			return nil
-		}
+// DuplicateTodo creates a copy of a TODO in the same project, with the same
+// estimate, custom field values, location, color, and icon, pending and with
+// no progress, and records it in the audit log as a create.
+func (r *SQLite) DuplicateTodo(id int64, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
 	}
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("iterate table info: %w", err)
+	defer tx.Rollback()
+
+	src, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	todo, err := insertTodo(tx, model.CreateTodoRequest{
+		Title:           src.Title,
+		Description:     src.Description,
+		Category:        src.Category,
+		ProjectID:       src.ProjectID,
+		Priority:        src.Priority,
+		DueDate:         src.DueDate,
+		EstimateMinutes: &src.EstimateMinutes,
+		CustomFields:    src.CustomFields,
+		Location:        src.Location,
+		Color:           src.Color,
+		Icon:            src.Icon,
+	}, model.TriageDone, info)
+	if err != nil {
+		return model.Todo{}, err
 	}
 
-	migration := `
-	ALTER TABLE todos ADD COLUMN category TEXT NOT NULL DEFAULT 'personal' CHECK(category IN ('personal', 'work', 'other'));
-	`
-	if _, err := r.db.Exec(migration); err != nil {
-		return fmt.Errorf("execute category migration: %w", err)
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
 	}
-	if _, err := r.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_category ON todos(category)`); err != nil {
-		return fmt.Errorf("create category index: %w", err)
+
+	return todo, nil
+}
+
+// CaptureTodo adds a TODO to the inbox, pending triage, with default status
+// and category, and records it in the audit log.
+func (r *SQLite) CaptureTodo(req model.CaptureTodoRequest, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	r.logger.Info("added category column to todos table")
-	return nil
+	todo, err := insertTodo(tx, model.CreateTodoRequest{
+		Title:       req.Title,
+		Description: req.Description,
+	}, model.TriagePending, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return todo, nil
 }
 
-// CreateTodo inserts a new TODO and returns it.
-func (r *Repository) CreateTodo(req model.CreateTodoRequest) (model.Todo, error) {
+// insertTodo inserts a TODO within tx, filling in defaults for unset fields,
+// and records its creation in the audit log. New TODOs are positioned last.
+func insertTodo(tx *sql.Tx, req model.CreateTodoRequest, triage model.Triage, info AuditInfo) (model.Todo, error) {
 	status := model.StatusPending
 	if req.Status != "" {
 		status = req.Status
 	}
-	category := model.CategoryPersonal
-	if req.Category != "" {
-		category = req.Category
+	category := req.Category
+	if category == "" {
+		var err error
+		if category, err = defaultCategory(tx); err != nil {
+			return model.Todo{}, err
+		}
+	} else if err := checkCategory(tx, category); err != nil {
+		return model.Todo{}, err
 	}
 	progress := 0
 	if req.ProgressPercent != nil {
 		progress = *req.ProgressPercent
 	}
+	if status == model.StatusDone {
+		progress = 100
+	}
+	priority := model.PriorityNone
+	if req.Priority != "" {
+		priority = req.Priority
+	}
 
-	result, err := r.db.Exec(
-		`INSERT INTO todos (title, description, status, category, progress_percent) VALUES (?, ?, ?, ?, ?)`,
-		req.Title, req.Description, string(status), string(category), progress,
+	estimate := 0
+	if req.EstimateMinutes != nil {
+		estimate = *req.EstimateMinutes
+	}
+	values, err := mergeCustomFields(tx, nil, req.CustomFields)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	customFields, err := encodeCustomFields(values)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	latitude, longitude, place := locationColumns(req.Location)
+
+	result, err := tx.Exec(
+		`INSERT INTO todos (title, description, status, category, project_id, progress_percent, priority, due_date, estimate_minutes, triage, custom_fields, latitude, longitude, place, color, icon, completed_at, position)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CASE WHEN ? THEN unixepoch() END, (SELECT COALESCE(MAX(position), 0) + ? FROM todos))`,
+		req.Title, req.Description, string(status), string(category), req.ProjectID, progress, string(priority), req.DueDate, estimate, string(triage), customFields, latitude, longitude, place, req.Color, req.Icon, status == model.StatusDone, positionGap,
 	)
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("insert todo: %w", err)
@@ -149,75 +348,730 @@ func (r *Repository) CreateTodo(req model.CreateTodoRequest) (model.Todo, error)
 		return model.Todo{}, fmt.Errorf("get last insert id: %w", err)
 	}
 
-	return r.GetTodo(id)
+	todo, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionCreate, id, nil, &todo, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	return todo, nil
 }
 
 // GetTodo retrieves a single TODO by ID.
-func (r *Repository) GetTodo(id int64) (model.Todo, error) {
-	row := r.db.QueryRow(
-		`SELECT id, title, description, status, category, progress_percent,
-			strftime('%Y-%m-%dT%H:%M:%SZ', created_at),
-			strftime('%Y-%m-%dT%H:%M:%SZ', updated_at)
-		FROM todos WHERE id = ?`,
-		id,
-	)
-	return scanTodo(row)
+func (r *SQLite) GetTodo(id int64) (model.Todo, error) {
+	return cached(r, todoCacheKey(id), cloneTodo, func() (model.Todo, error) {
+		return getTodo(r.db, id)
+	})
 }
 
-// ListTodos retrieves all TODOs, optionally filtered by status and/or category.
-func (r *Repository) ListTodos(status *model.Status, category *model.Category) ([]model.Todo, error) {
-	query := `SELECT id, title, description, status, category, progress_percent,
-		strftime('%Y-%m-%dT%H:%M:%SZ', created_at),
-		strftime('%Y-%m-%dT%H:%M:%SZ', updated_at)
-	FROM todos`
-	var conditions []string
-	var args []any
+func getTodo(q querier, id int64) (model.Todo, error) {
+	row := q.QueryRow(`SELECT `+todoColumns+` FROM todos WHERE id = ?`, id)
+
+	t, err := scanTodo(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Todo{}, ErrNotFound
+	}
+	return t, err
+}
 
-	if status != nil {
-		conditions = append(conditions, "status = ?")
-		args = append(args, string(*status))
+// TodoFilter narrows the TODOs returned by ListTodos. Nil fields are ignored.
+type TodoFilter struct {
+	Status    *model.Status
+	Category  *model.Category
+	ProjectID *int64
+	Archived  *bool
+	Priority  *model.Priority
+	Triage    *model.Triage
+	Assignee  *string
+	Pinned    *bool
+	// Snoozed matches TODOs that are snoozed if true, or not if false.
+	Snoozed *bool
+	// CustomFields matches TODOs holding each of the values, keyed by
+	// custom field name.
+	CustomFields map[string]any
+	// Near matches TODOs located within a radius of a point.
+	Near *Near
+	// Statuses, Categories and Priorities match TODOs with any of the given
+	// values, in addition to Status, Category and Priority. Nil slices are
+	// ignored.
+	Statuses   []model.Status
+	Categories []model.Category
+	Priorities []model.Priority
+	// DueFrom and DueTo bound due_date, inclusive. Either excludes TODOs
+	// without a due date.
+	DueFrom *string
+	DueTo   *string
+	// ScheduledFrom and ScheduledTo bound scheduled_for, inclusive. Either
+	// excludes unscheduled TODOs.
+	ScheduledFrom *string
+	ScheduledTo   *string
+	// CompletedFrom and CompletedTo bound completed_at, inclusive. Either
+	// excludes TODOs that are not done.
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+}
+
+func (f TodoFilter) where() query.Where {
+	var w query.Where
+	if f.Archived != nil {
+		w.Add("archived = ?", *f.Archived)
+	}
+	if f.Status != nil {
+		w.Add("status = ?", string(*f.Status))
 	}
-	if category != nil {
-		conditions = append(conditions, "category = ?")
-		args = append(args, string(*category))
+	if f.Category != nil {
+		w.Add("category = ?", string(*f.Category))
 	}
+	if f.Statuses != nil {
+		w.In("status", anys(f.Statuses)...)
+	}
+	if f.Categories != nil {
+		w.In("category", anys(f.Categories)...)
+	}
+	if f.ProjectID != nil {
+		w.Add("project_id = ?", *f.ProjectID)
+	}
+	if f.Priority != nil {
+		w.Add("priority = ?", string(*f.Priority))
+	}
+	if f.Priorities != nil {
+		w.In("priority", anys(f.Priorities)...)
+	}
+	if f.DueFrom != nil {
+		w.Add("due_date >= ?", *f.DueFrom)
+	}
+	if f.DueTo != nil {
+		w.Add("due_date <= ?", *f.DueTo)
+	}
+	if f.Triage != nil {
+		w.Add("triage = ?", string(*f.Triage))
+	}
+	if f.Assignee != nil {
+		w.Add("assignee = ?", *f.Assignee)
+	}
+	if f.Pinned != nil {
+		w.Add("pinned = ?", *f.Pinned)
+	}
+	for _, name := range slices.Sorted(maps.Keys(f.CustomFields)) {
+		w.Add("json_extract(custom_fields, ?) = ?", customFieldPath(name), f.CustomFields[name])
+	}
+	if f.Near != nil {
+		f.Near.where(&w)
+	}
+	if f.Snoozed != nil {
+		if *f.Snoozed {
+			w.Add("snoozed_until IS NOT NULL")
+		} else {
+			w.Add("snoozed_until IS NULL")
+		}
+	}
+	if f.ScheduledFrom != nil {
+		w.Add("scheduled_for >= ?", *f.ScheduledFrom)
+	}
+	if f.ScheduledTo != nil {
+		w.Add("scheduled_for <= ?", *f.ScheduledTo)
+	}
+	if f.CompletedFrom != nil {
+		w.Add("completed_at >= ?", f.CompletedFrom.Unix())
+	}
+	if f.CompletedTo != nil {
+		w.Add("completed_at <= ?", f.CompletedTo.Unix())
+	}
+	return w
+}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+// anys converts string-typed values to query arguments.
+func anys[T ~string](values []T) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = string(v)
 	}
+	return args
+}
 
-	query += ` ORDER BY id ASC`
+// TodoSort describes the fields TODO lists can be sorted by.
+var TodoSort = query.Spec{
+	Columns: map[string]string{
+		"id":               "id",
+		"title":            "title",
+		"status":           "status",
+		"category":         "category",
+		"project_id":       "project_id",
+		"progress_percent": "progress_percent",
+		"estimate_minutes": "estimate_minutes",
+		"priority":         priorityRank,
+		"due_date":         dueDateOrder,
+		"scheduled_for":    "scheduled_for",
+		"created_at":       "created_at",
+		"updated_at":       "updated_at",
+		"completed_at":     "completed_at",
+		"position":         "position",
+	},
+	Default: []query.Sort{{Field: "id"}},
+	Leading: []string{"pinned DESC"},
+}
+
+// priorityRank orders priorities from least to most important, so that
+// sorting by priority descending puts the most important TODOs first.
+const priorityRank = `CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 ELSE 0 END`
+
+// dueDateOrder sorts by due date, keeping TODOs without one last in either
+// direction.
+const dueDateOrder = `due_date IS NULL, due_date`
+
+// listPrealloc caps the capacity ListTodos preallocates.
+const listPrealloc = 64
+
+// ListTodos retrieves the TODOs matching filter, sorted and paginated by opts.
+func (r *SQLite) ListTodos(filter TodoFilter, opts query.Options) ([]model.Todo, error) {
+	q, args := filter.where().Apply(`SELECT `+todoColumns+` FROM todos`, nil)
+	q, args = opts.Apply(q, args)
+
+	return cached(r, queryCacheKey(q, args), cloneTodos, func() ([]model.Todo, error) {
+		return r.listTodos(q, args, opts.Limit)
+	})
+}
 
-	rows, err := r.db.Query(query, args...)
+func (r *SQLite) listTodos(q string, args []any, limit int) ([]model.Todo, error) {
+	rows, err := r.db.Query(q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query todos: %w", err)
 	}
 	defer rows.Close()
 
-	var todos []model.Todo
+	// A page may be far shorter than its limit, so preallocate no more than
+	// a typical page and let append grow the rest.
+	todos := make([]model.Todo, 0, min(limit, listPrealloc))
 	for rows.Next() {
-		var t model.Todo
-		var statusStr, categoryStr string
-		var createdAt, updatedAt string
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &statusStr, &categoryStr, &t.ProgressPercent, &createdAt, &updatedAt); err != nil {
-			return nil, fmt.Errorf("scan todo: %w", err)
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
 		}
-		t.Status = model.Status(statusStr)
-		t.Category = model.Category(categoryStr)
-		t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 		todos = append(todos, t)
 	}
 
-	if todos == nil {
-		todos = []model.Todo{}
+	return todos, rows.Err()
+}
+
+// CountTodos returns the number of TODOs matching filter.
+func (r *SQLite) CountTodos(filter TodoFilter) (int, error) {
+	q, args := filter.where().Apply(`SELECT COUNT(*) FROM todos`, nil)
+
+	return cached(r, queryCacheKey(q, args), sameCount, func() (int, error) {
+		var count int
+		if err := r.db.QueryRow(q, args...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("count todos: %w", err)
+		}
+		return count, nil
+	})
+}
+
+// SetArchived archives or unarchives a TODO and records the change in the
+// audit log. Setting the flag to its current value is a no-op.
+func (r *SQLite) SetArchived(id int64, archived bool, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if before.Archived == archived {
+		return before, nil
 	}
 
-	return todos, rows.Err()
+	after, err := setArchived(tx, before, archived, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// ArchiveDone archives every unarchived TODO completed before cutoff and
+// returns how many were archived.
+func (r *SQLite) ArchiveDone(cutoff time.Time, info AuditInfo) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	todos, err := selectTodos(tx,
+		`archived = 0 AND status = ? AND completed_at <= ?`,
+		string(model.StatusDone), cutoff.Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, t := range todos {
+		if _, err := setArchived(tx, t, true, info); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return len(todos), nil
+}
+
+// CountArchivable returns the number of TODOs ArchiveDone would archive for
+// cutoff.
+func (r *SQLite) CountArchivable(cutoff time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM todos WHERE archived = 0 AND status = ? AND completed_at <= ?`,
+		string(model.StatusDone), cutoff.Unix(),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count archivable todos: %w", err)
+	}
+	return count, nil
+}
+
+// SetSchedule plans a TODO for date, formatted as model.DateLayout, or
+// unschedules it if date is nil, and records the change in the audit log.
+// Setting the schedule to its current value is a no-op.
+func (r *SQLite) SetSchedule(id int64, date *string, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	after, err := setSchedule(tx, id, date, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// setSchedule is SetSchedule within tx.
+func setSchedule(tx *sql.Tx, id int64, date *string, info AuditInfo) (model.Todo, error) {
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if ptrValue(before.ScheduledFor) == ptrValue(date) {
+		return before, nil
+	}
+
+	_, err = tx.Exec(
+		`UPDATE todos SET scheduled_for = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		date, id,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("schedule todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+	return after, nil
 }
 
-// UpdateTodo updates only the provided fields of a TODO.
-func (r *Repository) UpdateTodo(id int64, req model.UpdateTodoRequest) (model.Todo, error) {
+// SetAssignee assigns a TODO to assignee, or unassigns it if assignee is
+// empty, and records the change in the audit log. Assigning a TODO to whom
+// it is already assigned is a no-op.
+func (r *SQLite) SetAssignee(id int64, assignee string, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if before.Assignee == assignee {
+		return before, nil
+	}
+
+	_, err = tx.Exec(
+		`UPDATE todos SET assignee = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		assignee, id,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("assign todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// SetPinned pins or unpins a TODO and records the change in the audit log.
+// Setting the flag to its current value is a no-op.
+func (r *SQLite) SetPinned(id int64, pinned bool, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if before.Pinned == pinned {
+		return before, nil
+	}
+
+	_, err = tx.Exec(
+		`UPDATE todos SET pinned = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		pinned, id,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("pin todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// SetLocation sets a TODO's location, or removes it if loc is nil, and
+// records the change in the audit log. Setting the location to its current
+// value is a no-op.
+func (r *SQLite) SetLocation(id int64, loc *model.Location, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	after, err := setLocation(tx, id, loc, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// setLocation is SetLocation within tx.
+func setLocation(tx *sql.Tx, id int64, loc *model.Location, info AuditInfo) (model.Todo, error) {
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if ptrValue(before.Location) == ptrValue(loc) {
+		return before, nil
+	}
+
+	latitude, longitude, place := locationColumns(loc)
+	_, err = tx.Exec(
+		`UPDATE todos SET latitude = ?, longitude = ?, place = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		latitude, longitude, place, id,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("locate todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+	return after, nil
+}
+
+// locationColumns returns the latitude, longitude, and place columns that
+// store loc.
+func locationColumns(loc *model.Location) (latitude, longitude sql.NullFloat64, place string) {
+	if loc == nil {
+		return latitude, longitude, ""
+	}
+	return sql.NullFloat64{Float64: loc.Latitude, Valid: true}, sql.NullFloat64{Float64: loc.Longitude, Valid: true}, loc.Place
+}
+
+// SetSnooze snoozes a TODO until the given time, or wakes it if until is
+// nil, and records the change in the audit log. Times are stored to the
+// second. Setting the snooze to its current value is a no-op.
+func (r *SQLite) SetSnooze(id int64, until *time.Time, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if until != nil {
+		u := unixTime(until.Unix())
+		until = &u
+	}
+	if ptrValue(before.SnoozedUntil) == ptrValue(until) {
+		return before, nil
+	}
+
+	after, err := setSnooze(tx, before, until, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// WakeSnoozed wakes every TODO whose snooze ran out at or before now,
+// recording each in the audit log, and returns them.
+func (r *SQLite) WakeSnoozed(now time.Time, info AuditInfo) ([]model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	todos, err := selectTodos(tx, `snoozed_until <= ?`, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	woken := make([]model.Todo, 0, len(todos))
+	for _, t := range todos {
+		after, err := setSnooze(tx, t, nil, info)
+		if err != nil {
+			return nil, err
+		}
+		woken = append(woken, after)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return woken, nil
+}
+
+// setSnooze sets the snooze of before within tx and records the change.
+func setSnooze(tx *sql.Tx, before model.Todo, until *time.Time, info AuditInfo) (model.Todo, error) {
+	var sec *int64
+	if until != nil {
+		u := until.Unix()
+		sec = &u
+	}
+	_, err := tx.Exec(
+		`UPDATE todos SET snoozed_until = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		sec, before.ID,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("snooze todo: %w", err)
+	}
+
+	after, err := getTodo(tx, before.ID)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	return after, nil
+}
+
+// TriageTodo files a TODO from the inbox: it sets the TODO's category and,
+// if given, its project, priority, due date, and scheduled day, marks it
+// triaged, and records the change in the audit log. A project ID of 0
+// removes the TODO from its project. TODOs that are already triaged are
+// updated the same way.
+func (r *SQLite) TriageTodo(id int64, req model.TriageTodoRequest, info AuditInfo) (model.Todo, error) {
+	setClauses := []string{"category = ?", "triage = ?"}
+	args := []any{string(req.Category), string(model.TriageDone)}
+	if req.ProjectID != nil {
+		setClauses = append(setClauses, "project_id = ?")
+		args = append(args, nullID(*req.ProjectID))
+	}
+	if req.Priority != "" {
+		setClauses = append(setClauses, "priority = ?")
+		args = append(args, string(req.Priority))
+	}
+	if req.DueDate != nil {
+		setClauses = append(setClauses, "due_date = ?")
+		args = append(args, *req.DueDate)
+	}
+	if req.ScheduledFor != nil {
+		setClauses = append(setClauses, "scheduled_for = ?")
+		args = append(args, *req.ScheduledFor)
+	}
+	setClauses = append(setClauses, "updated_at = unixepoch()", "version = version + 1")
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		if err := checkProject(tx, *req.ProjectID); err != nil {
+			return model.Todo{}, err
+		}
+	}
+	if err := checkCategory(tx, req.Category); err != nil {
+		return model.Todo{}, err
+	}
+
+	query := fmt.Sprintf("UPDATE todos SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return model.Todo{}, fmt.Errorf("triage todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+func setArchived(tx *sql.Tx, before model.Todo, archived bool, info AuditInfo) (model.Todo, error) {
+	_, err := tx.Exec(
+		`UPDATE todos SET archived = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		archived, before.ID,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("archive todo: %w", err)
+	}
+
+	after, err := getTodo(tx, before.ID)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	return after, nil
+}
+
+// TodosLastModified returns the time of the most recent change to any TODO,
+// including deletions recorded in the audit log. It returns the zero time if
+// nothing has been written yet.
+func (r *SQLite) TodosLastModified() (time.Time, error) {
+	var ts sql.NullInt64
+	err := r.db.QueryRow(`SELECT MAX(ts) FROM (
+		SELECT MAX(updated_at) AS ts FROM todos
+		UNION ALL
+		SELECT MAX(created_at) FROM audit_log
+	)`).Scan(&ts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query last modified: %w", err)
+	}
+	if !ts.Valid {
+		return time.Time{}, nil
+	}
+
+	return unixTime(ts.Int64), nil
+}
+
+// UpdateTodo updates only the provided fields of a TODO and records the
+// changed fields in the audit log. If version is non-zero and does not match
+// the TODO's current version, ErrVersionMismatch is returned, and if the
+// updated TODO would break the repository's rules, a *RuleError.
+func (r *SQLite) UpdateTodo(id, version int64, req model.UpdateTodoRequest, info AuditInfo) (model.Todo, error) {
+	if setClauses, _ := updateClauses(req); len(setClauses) == 0 && req.Status == nil && req.CustomFields == nil {
+		todo, err := r.GetTodo(id)
+		if err == nil && version != 0 && todo.Version != version {
+			return model.Todo{}, ErrVersionMismatch
+		}
+		return todo, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	after, err := r.updateTodo(tx, id, version, req, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	generation := r.queries.writes.Load()
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.cacheTodo(after, generation)
+
+	return after, nil
+}
+
+// updateClauses returns the SET clauses and arguments for the fields of req
+// that are set directly: all but the status and custom fields.
+func updateClauses(req model.UpdateTodoRequest) ([]string, []any) {
 	var setClauses []string
 	var args []any
 
@@ -229,71 +1083,252 @@ func (r *Repository) UpdateTodo(id int64, req model.UpdateTodoRequest) (model.To
 		setClauses = append(setClauses, "description = ?")
 		args = append(args, *req.Description)
 	}
-	if req.Status != nil {
-		setClauses = append(setClauses, "status = ?")
-		args = append(args, string(*req.Status))
-	}
 	if req.Category != nil {
 		setClauses = append(setClauses, "category = ?")
 		args = append(args, string(*req.Category))
 	}
+	if req.ProjectID != nil {
+		setClauses = append(setClauses, "project_id = ?")
+		args = append(args, nullID(*req.ProjectID))
+	}
 	if req.ProgressPercent != nil {
 		setClauses = append(setClauses, "progress_percent = ?")
 		args = append(args, *req.ProgressPercent)
 	}
+	if req.Priority != nil {
+		setClauses = append(setClauses, "priority = ?")
+		args = append(args, string(*req.Priority))
+	}
+	if req.DueDate != nil {
+		setClauses = append(setClauses, "due_date = ?")
+		args = append(args, nullString(*req.DueDate))
+	}
+	if req.EstimateMinutes != nil {
+		setClauses = append(setClauses, "estimate_minutes = ?")
+		args = append(args, *req.EstimateMinutes)
+	}
+	if req.Color != nil {
+		setClauses = append(setClauses, "color = ?")
+		args = append(args, *req.Color)
+	}
+	if req.Icon != nil {
+		setClauses = append(setClauses, "icon = ?")
+		args = append(args, *req.Icon)
+	}
 
+	return setClauses, args
+}
+
+// updateTodo is UpdateTodo within tx.
+func (r *SQLite) updateTodo(tx *sql.Tx, id, version int64, req model.UpdateTodoRequest, info AuditInfo) (model.Todo, error) {
+	setClauses, args := updateClauses(req)
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if version != 0 && before.Version != version {
+		return model.Todo{}, ErrVersionMismatch
+	}
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		if err := checkProject(tx, *req.ProjectID); err != nil {
+			return model.Todo{}, err
+		}
+	}
+	if req.Category != nil {
+		if err := checkCategory(tx, *req.Category); err != nil {
+			return model.Todo{}, err
+		}
+	}
+	if req.Status != nil && *req.Status != before.Status {
+		if !r.transitions.Allows(before.Status, *req.Status) {
+			return model.Todo{}, &TransitionError{From: before.Status, To: *req.Status}
+		}
+		setClauses = append(setClauses, statusClauses(*req.Status)...)
+		args = append(args, string(*req.Status))
+	}
+	if req.CustomFields != nil {
+		values, err := mergeCustomFields(tx, before.CustomFields, req.CustomFields)
+		if err != nil {
+			return model.Todo{}, err
+		}
+		if !maps.Equal(values, before.CustomFields) {
+			customFields, err := encodeCustomFields(values)
+			if err != nil {
+				return model.Todo{}, err
+			}
+			setClauses = append(setClauses, "custom_fields = ?")
+			args = append(args, customFields)
+		}
+	}
 	if len(setClauses) == 0 {
-		return r.GetTodo(id)
+		return before, nil
 	}
 
-	setClauses = append(setClauses, "updated_at = datetime('now')")
+	setClauses = append(setClauses, "updated_at = unixepoch()", "version = version + 1")
 	args = append(args, id)
 
 	query := fmt.Sprintf("UPDATE todos SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-
-	result, err := r.db.Exec(query, args...)
-	if err != nil {
+	if _, err := tx.Exec(query, args...); err != nil {
 		return model.Todo{}, fmt.Errorf("update todo: %w", err)
 	}
 
-	affected, err := result.RowsAffected()
+	after, err := getTodo(tx, id)
 	if err != nil {
-		return model.Todo{}, fmt.Errorf("rows affected: %w", err)
+		return model.Todo{}, err
 	}
-	if affected == 0 {
-		return model.Todo{}, ErrNotFound
+	if err := r.checkRules(after); err != nil {
+		return model.Todo{}, err
 	}
 
-	return r.GetTodo(id)
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+	return after, nil
 }
 
-// DeleteTodo deletes a TODO by ID.
-func (r *Repository) DeleteTodo(id int64) error {
-	result, err := r.db.Exec(`DELETE FROM todos WHERE id = ?`, id)
+// CompleteTodo marks a TODO done regardless of the configured transitions.
+// Completing a done TODO is a no-op.
+func (r *SQLite) CompleteTodo(id int64, info AuditInfo) (model.Todo, error) {
+	return r.changeStatus(id, model.StatusDone, info)
+}
+
+// ReopenTodo moves a done TODO back to pending regardless of the configured
+// transitions, clearing its completion time but keeping its progress.
+// Reopening a TODO that is not done is a no-op.
+func (r *SQLite) ReopenTodo(id int64, info AuditInfo) (model.Todo, error) {
+	return r.changeStatus(id, model.StatusPending, info, model.StatusDone)
+}
+
+// changeStatus sets a TODO's status to status if it is currently one of from,
+// or any other status if from is empty, and returns the TODO.
+func (r *SQLite) changeStatus(id int64, status model.Status, info AuditInfo, from ...model.Status) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
 	if err != nil {
-		return fmt.Errorf("delete todo: %w", err)
+		return model.Todo{}, err
 	}
+	if before.Status == status || (len(from) > 0 && !slices.Contains(from, before.Status)) {
+		return before, nil
+	}
+
+	setClauses := append(statusClauses(status), "updated_at = unixepoch()", "version = version + 1")
+	query := fmt.Sprintf("UPDATE todos SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, string(status), id); err != nil {
+		return model.Todo{}, fmt.Errorf("update todo status: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// statusClauses returns the SET clauses that change a TODO's status, taking
+// the status as their only argument. Completing a TODO sets its progress to
+// 100, overriding any progress set earlier in the same statement, and stamps
+// completed_at; any other status clears completed_at.
+func statusClauses(status model.Status) []string {
+	if status == model.StatusDone {
+		return []string{"status = ?", "progress_percent = 100", "completed_at = unixepoch()"}
+	}
+	return []string{"status = ?", "completed_at = NULL"}
+}
 
-	affected, err := result.RowsAffected()
+// DeleteTodo deletes a TODO by ID and records its final state in the audit
+// log. If version is non-zero and does not match the TODO's current version,
+// ErrVersionMismatch is returned.
+func (r *SQLite) DeleteTodo(id, version int64, info AuditInfo) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("rows affected: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
-	if affected == 0 {
-		return ErrNotFound
+	defer tx.Rollback()
+
+	if err := deleteTodoVersion(tx, id, version, info); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-// scanTodo scans a single row into a Todo.
-func scanTodo(row *sql.Row) (model.Todo, error) {
+// deleteTodoVersion is DeleteTodo within tx.
+func deleteTodoVersion(tx *sql.Tx, id, version int64, info AuditInfo) error {
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return err
+	}
+	if version != 0 && before.Version != version {
+		return ErrVersionMismatch
+	}
+
+	return deleteTodo(tx, before, info)
+}
+
+// selectTodos loads every TODO matching the where clause, fully reading the
+// result so that q can be used for further statements afterwards.
+func selectTodos(q querier, where string, args ...any) ([]model.Todo, error) {
+	rows, err := q.Query(`SELECT `+todoColumns+` FROM todos WHERE `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query todos: %w", err)
+	}
+	defer rows.Close()
+
+	var todos []model.Todo
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate todos: %w", err)
+	}
+	return todos, nil
+}
+
+// todoColumns is the column list scanTodo expects, in order.
+const todoColumns = `id, title, description, status, category, project_id, progress_percent, priority, due_date, estimate_minutes, archived, scheduled_for, triage, assignee, pinned, snoozed_until, custom_fields, latitude, longitude, place, color, icon, position, version, created_at, updated_at, completed_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTodo scans a single row selected with todoColumns into a Todo.
+// sql.ErrNoRows is returned unwrapped.
+func scanTodo(row rowScanner) (model.Todo, error) {
 	var t model.Todo
-	var statusStr, categoryStr string
-	var createdAt, updatedAt string
+	var statusStr, categoryStr, priorityStr, triageStr, customFields string
+	var projectID, snoozedUntil, completedAt sql.NullInt64
+	var dueDate, scheduledFor sql.NullString
+	var latitude, longitude sql.NullFloat64
+	var place string
+	var createdAt, updatedAt int64
 
-	err := row.Scan(&t.ID, &t.Title, &t.Description, &statusStr, &categoryStr, &t.ProgressPercent, &createdAt, &updatedAt)
+	err := row.Scan(&t.ID, &t.Title, &t.Description, &statusStr, &categoryStr, &projectID, &t.ProgressPercent, &priorityStr, &dueDate, &t.EstimateMinutes, &t.Archived, &scheduledFor, &triageStr, &t.Assignee, &t.Pinned, &snoozedUntil, &customFields, &latitude, &longitude, &place, &t.Color, &t.Icon, &t.Position, &t.Version, &createdAt, &updatedAt, &completedAt)
 	if errors.Is(err, sql.ErrNoRows) {
-		return model.Todo{}, ErrNotFound
+		return model.Todo{}, err
 	}
 	if err != nil {
 		return model.Todo{}, fmt.Errorf("scan todo: %w", err)
@@ -301,8 +1336,46 @@ func scanTodo(row *sql.Row) (model.Todo, error) {
 
 	t.Status = model.Status(statusStr)
 	t.Category = model.Category(categoryStr)
-	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	t.Triage = model.Triage(triageStr)
+	if projectID.Valid {
+		t.ProjectID = &projectID.Int64
+	}
+	t.Priority = model.Priority(priorityStr)
+	if dueDate.Valid {
+		t.DueDate = &dueDate.String
+	}
+	if scheduledFor.Valid {
+		t.ScheduledFor = &scheduledFor.String
+	}
+	if snoozedUntil.Valid {
+		u := unixTime(snoozedUntil.Int64)
+		t.SnoozedUntil = &u
+	}
+	if err := json.Unmarshal([]byte(customFields), &t.CustomFields); err != nil {
+		return model.Todo{}, fmt.Errorf("decode custom fields: %w", err)
+	}
+	if latitude.Valid && longitude.Valid {
+		t.Location = &model.Location{Latitude: latitude.Float64, Longitude: longitude.Float64, Place: place}
+	}
+	t.CreatedAt = unixTime(createdAt)
+	t.UpdatedAt = unixTime(updatedAt)
+	if completedAt.Valid {
+		c := unixTime(completedAt.Int64)
+		t.CompletedAt = &c
+	}
 
 	return t, nil
 }
+
+// unixTime converts a stored timestamp, in Unix seconds, to a UTC time.
+func unixTime(sec int64) time.Time {
+	return time.Unix(sec, 0).UTC()
+}
+
+// nullString maps the empty string to NULL, for clearing optional values.
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
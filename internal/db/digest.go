@@ -0,0 +1,36 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// LastDigestSentAt returns when the last email digest was sent, or nil if
+// none was.
+func (r *SQLite) LastDigestSentAt() (*time.Time, error) {
+	var sec sql.NullInt64
+	if err := r.db.QueryRow(`SELECT MAX(sent_at) FROM digest_sends`).Scan(&sec); err != nil {
+		return nil, fmt.Errorf("query last digest: %w", err)
+	}
+	if !sec.Valid {
+		return nil, nil
+	}
+	t := unixTime(sec.Int64)
+	return &t, nil
+}
+
+// RecordDigestSend records that a digest was emailed.
+func (r *SQLite) RecordDigestSend(send model.DigestSend) error {
+	_, err := r.db.Exec(
+		`INSERT INTO digest_sends (sent_at, recipients, overdue, due_soon, completed) VALUES (?, ?, ?, ?, ?)`,
+		send.SentAt.Unix(), strings.Join(send.To, ","), send.Overdue, send.DueSoon, send.Completed,
+	)
+	if err != nil {
+		return fmt.Errorf("record digest send: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// GitHubLink links a GitHub issue to the TODO that mirrors it, recording
+// the issue's state, title, and body as of the last sync. TodoID is nil once
+// the TODO has been purged from the trash.
+type GitHubLink struct {
+	Repo   string
+	Number int
+	TodoID *int64
+	State  string
+	Title  string
+	Body   string
+}
+
+// GitHubLinks returns the links of the issues of repo, by issue number.
+func (r *SQLite) GitHubLinks(repo string) (map[int]GitHubLink, error) {
+	rows, err := r.db.Query(
+		`SELECT repo, number, todo_id, state, title, body FROM github_issue_links WHERE repo = ?`,
+		repo,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query github issue links: %w", err)
+	}
+	defer rows.Close()
+
+	links := map[int]GitHubLink{}
+	for rows.Next() {
+		var l GitHubLink
+		var todoID sql.NullInt64
+		if err := rows.Scan(&l.Repo, &l.Number, &todoID, &l.State, &l.Title, &l.Body); err != nil {
+			return nil, fmt.Errorf("scan github issue link: %w", err)
+		}
+		if todoID.Valid {
+			l.TodoID = &todoID.Int64
+		}
+		links[l.Number] = l
+	}
+
+	return links, rows.Err()
+}
+
+// SaveGitHubLink records link as synced now, replacing any earlier link of
+// the same issue.
+func (r *SQLite) SaveGitHubLink(link GitHubLink) error {
+	return saveGitHubLink(r.db, link)
+}
+
+func saveGitHubLink(q querier, link GitHubLink) error {
+	var todoID any
+	if link.TodoID != nil {
+		todoID = *link.TodoID
+	}
+	_, err := q.Exec(
+		`INSERT INTO github_issue_links (repo, number, todo_id, state, title, body) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (repo, number) DO UPDATE SET todo_id = excluded.todo_id, state = excluded.state,
+			title = excluded.title, body = excluded.body, synced_at = unixepoch()`,
+		link.Repo, link.Number, todoID, link.State, link.Title, link.Body,
+	)
+	if err != nil {
+		return fmt.Errorf("save github issue link: %w", err)
+	}
+	return nil
+}
+
+// CreateGitHubTodo creates a TODO for a GitHub issue in the project named
+// project, creating the project if there is none, and links it to the
+// issue, in one transaction. If the TODO would break the repository's
+// rules, a *RuleError is returned and nothing is written.
+func (r *SQLite) CreateGitHubTodo(project string, req model.CreateTodoRequest, link GitHubLink, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	projectID, _, err := ensureProject(tx, project)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	req.ProjectID = &projectID
+
+	todo, err := insertTodo(tx, req, model.TriageDone, info)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if err := r.checkRules(todo); err != nil {
+		return model.Todo{}, err
+	}
+	link.TodoID = &todo.ID
+	if err := saveGitHubLink(tx, link); err != nil {
+		return model.Todo{}, err
+	}
+	generation := r.queries.writes.Load()
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.cacheTodo(todo, generation)
+
+	return todo, nil
+}
+
+// GitHubCursor returns when repo was last synced, or nil if it never was.
+func (r *SQLite) GitHubCursor(repo string) (*time.Time, error) {
+	var sec int64
+	err := r.db.QueryRow(`SELECT synced_at FROM github_sync_cursors WHERE repo = ?`, repo).Scan(&sec)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query github sync cursor: %w", err)
+	}
+	t := unixTime(sec)
+	return &t, nil
+}
+
+// SetGitHubCursor records that repo was synced at t.
+func (r *SQLite) SetGitHubCursor(repo string, t time.Time) error {
+	_, err := r.db.Exec(
+		`INSERT INTO github_sync_cursors (repo, synced_at) VALUES (?, ?)
+		ON CONFLICT (repo) DO UPDATE SET synced_at = excluded.synced_at`,
+		repo, t.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("save github sync cursor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// ImportTodo is a TODO to create in an import, with its project and
+// category given by name and created if missing.
+type ImportTodo struct {
+	Project string // "" for none
+	Todo    model.CreateTodoRequest
+}
+
+// ImportTodos creates todos in order in one transaction, positioned last,
+// and records them in the audit log under info. Categories are matched by
+// name regardless of case. A TODO whose title is already used by an
+// unarchived TODO in the same project, or the same category for TODOs
+// outside any project, including one created earlier in the import, is
+// skipped as a conflict, so importing the same data again creates nothing. With dryRun, the changes are
+// reported but rolled back. The result's Tasks count and warnings are left
+// to the caller.
+func (r *SQLite) ImportTodos(todos []ImportTodo, dryRun bool, info AuditInfo) (model.ImportResponse, error) {
+	result := model.ImportResponse{
+		DryRun:            dryRun,
+		ProjectsCreated:   []string{},
+		CategoriesCreated: []string{},
+		Conflicts:         []model.ImportIssue{},
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	projects := map[string]int64{}
+	categories := map[model.Category]model.Category{}
+	for _, it := range todos {
+		req := it.Todo
+		if it.Project != "" {
+			id, ok := projects[it.Project]
+			if !ok {
+				var created bool
+				if id, created, err = ensureProject(tx, it.Project); err != nil {
+					return result, err
+				}
+				if created {
+					result.ProjectsCreated = append(result.ProjectsCreated, it.Project)
+				}
+				projects[it.Project] = id
+			}
+			req.ProjectID = &id
+		}
+		if req.Category != "" {
+			name, ok := categories[req.Category]
+			if !ok {
+				var created bool
+				if name, created, err = ensureCategory(tx, req.Category); err != nil {
+					return result, err
+				}
+				if created {
+					result.CategoriesCreated = append(result.CategoriesCreated, string(name))
+				}
+				categories[req.Category] = name
+			}
+			req.Category = name
+		}
+
+		// TODOs outside any project conflict within their category.
+		where, cond := "project", `project_id = ?`
+		var scope any
+		if req.ProjectID != nil {
+			scope = *req.ProjectID
+		} else {
+			if req.Category == "" {
+				if req.Category, err = defaultCategory(tx); err != nil {
+					return result, err
+				}
+			}
+			where, cond = "category", `project_id IS NULL AND category = ?`
+			scope = string(req.Category)
+		}
+		var existing int64
+		err := tx.QueryRow(
+			`SELECT id FROM todos WHERE title = ? AND `+cond+` AND archived = 0 ORDER BY id LIMIT 1`,
+			req.Title, scope,
+		).Scan(&existing)
+		switch {
+		case err == nil:
+			result.Conflicts = append(result.Conflicts, model.ImportIssue{
+				Task:    req.Title,
+				Project: it.Project,
+				Reason:  "a TODO with this title already exists in the same " + where,
+				TodoID:  &existing,
+			})
+			continue
+		case !errors.Is(err, sql.ErrNoRows):
+			return result, fmt.Errorf("check for existing todo: %w", err)
+		}
+
+		if _, err := insertTodo(tx, req, model.TriageDone, info); err != nil {
+			return result, err
+		}
+		result.Imported++
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// ensureProject returns the ID of the project named name, creating it if
+// there is none, and whether it did.
+func ensureProject(tx *sql.Tx, name string) (int64, bool, error) {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM projects WHERE name = ?`, name).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("query project: %w", err)
+	}
+
+	result, err := tx.Exec(`INSERT INTO projects (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, false, fmt.Errorf("insert project: %w", err)
+	}
+	id, err = result.LastInsertId()
+	if err != nil {
+		return 0, false, fmt.Errorf("get last insert id: %w", err)
+	}
+	return id, true, nil
+}
+
+// ensureCategory returns the name of the category named name regardless of
+// case, creating it last in sort order if there is none, and whether it
+// did.
+func ensureCategory(tx *sql.Tx, name model.Category) (model.Category, bool, error) {
+	var existing string
+	err := tx.QueryRow(`SELECT name FROM categories WHERE name = ? COLLATE NOCASE ORDER BY name = ? DESC LIMIT 1`, string(name), string(name)).Scan(&existing)
+	if err == nil {
+		return model.Category(existing), false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", false, fmt.Errorf("query category: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO categories (name, sort_order) SELECT ?, COALESCE(MAX(sort_order), -1) + 1 FROM categories`,
+		string(name),
+	)
+	if err != nil {
+		return "", false, fmt.Errorf("insert category: %w", err)
+	}
+	return name, true, nil
+}
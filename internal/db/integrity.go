@@ -0,0 +1,79 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Vacuum rebuilds the database file, reclaiming the space of deleted rows
+// and defragmenting tables and indexes, and reports its size before and
+// after. Other statements wait until it finishes, since the repository uses
+// a single connection.
+func (r *SQLite) Vacuum() (model.VacuumResponse, error) {
+	var resp model.VacuumResponse
+	var err error
+	if resp.BytesBefore, err = r.pagesSize(); err != nil {
+		return resp, err
+	}
+
+	start := time.Now()
+	if _, err := r.db.Exec(`VACUUM`); err != nil {
+		return resp, fmt.Errorf("vacuum: %w", err)
+	}
+	resp.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	if resp.BytesAfter, err = r.pagesSize(); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// pagesSize returns the size of the database's pages, which is the size of
+// its file once the write-ahead log has been checkpointed.
+func (r *SQLite) pagesSize() (int64, error) {
+	var pageSize, pages int64
+	if err := r.db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("query page size: %w", err)
+	}
+	if err := r.db.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		return 0, fmt.Errorf("query page count: %w", err)
+	}
+	return pageSize * pages, nil
+}
+
+// maxIntegrityProblems is the most problems CheckIntegrity reports.
+const maxIntegrityProblems = 100
+
+// CheckIntegrity checks the whole database for corruption: malformed pages
+// and records, missing or extra index entries, and broken constraints. An
+// error is returned only if the check could not be run; corruption it finds
+// is reported as problems.
+func (r *SQLite) CheckIntegrity() (model.IntegrityReport, error) {
+	start := time.Now()
+	report := model.IntegrityReport{OK: true, Problems: []string{}, CheckedAt: start.UTC()}
+
+	rows, err := r.db.Query(fmt.Sprintf(`PRAGMA integrity_check(%d)`, maxIntegrityProblems))
+	if err != nil {
+		return report, fmt.Errorf("check integrity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return report, fmt.Errorf("scan integrity check: %w", err)
+		}
+		if line != "ok" {
+			report.OK = false
+			report.Problems = append(report.Problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("check integrity: %w", err)
+	}
+
+	report.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	return report, nil
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"math"
+
+	"todo-service/internal/query"
+)
+
+// earthRadiusKm is the Earth's mean radius.
+const earthRadiusKm = 6371.0088
+
+// kmPerDegree is the length of a degree of latitude.
+const kmPerDegree = earthRadiusKm * math.Pi / 180
+
+// Near matches TODOs located within RadiusKm of a point, measured along the
+// Earth's surface.
+type Near struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// where narrows w to TODOs within n. The latitude band lets the location
+// index skip most TODOs before the haversine distance is computed for the
+// rest.
+func (n Near) where(w *query.Where) {
+	band := n.RadiusKm / kmPerDegree
+	w.Add("latitude BETWEEN ? AND ?", n.Latitude-band, n.Latitude+band)
+	w.Add(
+		`2 * ? * asin(min(1, sqrt(
+			pow(sin(radians(latitude - ?) / 2), 2) +
+			cos(radians(?)) * cos(radians(latitude)) * pow(sin(radians(longitude - ?) / 2), 2)
+		))) <= ?`,
+		earthRadiusKm, n.Latitude, n.Latitude, n.Longitude, n.RadiusKm,
+	)
+}
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// ErrPendingMigrations is returned by VerifyMigrations when the database
+// schema is behind the migrations embedded in the binary.
+var ErrPendingMigrations = errors.New("pending migrations")
+
+// Migration is a numbered schema change with up and down SQL.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// loadMigrations reads the embedded migration files, named
+// NNNN_description.up.sql and NNNN_description.down.sql, in version order.
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		name := e.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: name must end in .up.sql or .down.sql", name)
+		}
+		num, desc, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(num)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive version number", name)
+		}
+
+		body, err := migrationFS.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: desc}
+			byVersion[version] = m
+		} else if m.Name != desc {
+			return nil, fmt.Errorf("migration %s: version %d is already used by %q", name, version, m.Name)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrate applies every pending migration in version order.
+func (r *SQLite) Migrate() error {
+	migrations, applied, err := r.migrationState()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := r.runMigration(m.Version, m.Name, m.Up, true); err != nil {
+			return err
+		}
+		r.logger.Info("applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	r.logger.Info("database migration complete")
+	return nil
+}
+
+// MigrateDown reverts applied migrations, newest first, until the schema is
+// at target. A target of 0 reverts every migration.
+func (r *SQLite) MigrateDown(target int) error {
+	migrations, applied, err := r.migrationState()
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= target || !applied[m.Version] {
+			continue
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+		if err := r.runMigration(m.Version, m.Name, m.Down, false); err != nil {
+			return err
+		}
+		r.logger.Info("reverted migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	return nil
+}
+
+// VerifyMigrations returns ErrPendingMigrations if any embedded migration has
+// not been applied. It never changes the schema beyond creating the
+// schema_migrations bookkeeping table.
+func (r *SQLite) VerifyMigrations() error {
+	status, err := r.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, s := range status {
+		if !s.Applied {
+			pending = append(pending, fmt.Sprintf("%d_%s", s.Version, s.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s", ErrPendingMigrations, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// MigrationStatus lists every embedded migration and whether it is applied.
+func (r *SQLite) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, applied, err := r.migrationState()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		status[i] = MigrationStatus{Migration: m, Applied: applied[m.Version]}
+	}
+	return status, nil
+}
+
+// migrationState loads the embedded migrations and the set of applied versions,
+// creating the schema_migrations table if needed.
+func (r *SQLite) migrationState() ([]Migration, map[int]bool, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.ensureMigrationsTable(); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query schema migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, nil, fmt.Errorf("scan schema migration: %w", err)
+		}
+		applied[version] = true
+	}
+
+	return migrations, applied, rows.Err()
+}
+
+// ensureMigrationsTable creates schema_migrations. Databases created before
+// versioned migrations existed are baselined by recording the migrations
+// whose changes are already present.
+func (r *SQLite) ensureMigrationsTable() error {
+	exists, err := r.hasTable("schema_migrations")
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+	CREATE TABLE schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT     NOT NULL,
+		applied_at DATETIME NOT NULL DEFAULT (datetime('now'))
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return r.baselineLegacySchema()
+}
+
+// baselineLegacySchema marks the migrations that correspond to the schema
+// changes previously made ad hoc at startup.
+func (r *SQLite) baselineLegacySchema() error {
+	legacy := []struct {
+		version int
+		name    string
+		present func() (bool, error)
+	}{
+		{1, "create_todos", func() (bool, error) { return r.hasTable("todos") }},
+		{2, "add_todo_category", func() (bool, error) { return r.hasColumn("todos", "category") }},
+		{3, "add_todo_version", func() (bool, error) { return r.hasColumn("todos", "version") }},
+		{4, "create_audit_log", func() (bool, error) { return r.hasTable("audit_log") }},
+	}
+
+	for _, m := range legacy {
+		present, err := m.present()
+		if err != nil {
+			return fmt.Errorf("inspect legacy schema: %w", err)
+		}
+		if !present {
+			return nil
+		}
+		if _, err := r.db.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.version, m.name); err != nil {
+			return fmt.Errorf("baseline migration %d: %w", m.version, err)
+		}
+		r.logger.Info("baselined existing schema", slog.Int("version", m.version), slog.String("name", m.name))
+	}
+	return nil
+}
+
+// runMigration executes one migration direction and records it atomically.
+func (r *SQLite) runMigration(version int, name, stmts string, up bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin migration %d: %w", version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(stmts); err != nil {
+		return fmt.Errorf("execute migration %d_%s: %w", version, name, err)
+	}
+
+	if up {
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, version, name)
+	} else {
+		_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, version)
+	}
+	if err != nil {
+		return fmt.Errorf("record migration %d: %w", version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d: %w", version, err)
+	}
+	return nil
+}
+
+// hasTable reports whether the named table exists.
+func (r *SQLite) hasTable(table string) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("query sqlite_master: %w", err)
+	}
+	return count > 0, nil
+}
+
+// hasColumn reports whether table already has the named column.
+func (r *SQLite) hasColumn(table, column string) (bool, error) {
+	rows, err := r.db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return false, fmt.Errorf("query table info: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		var name, ctype string
+		var notnull int
+		var dfltValue sql.NullString
+		var pk int
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			return false, fmt.Errorf("scan table info: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("iterate table info: %w", err)
+	}
+	return false, nil
+}
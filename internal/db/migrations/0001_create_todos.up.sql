@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS todos (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	title            TEXT    NOT NULL,
+	description      TEXT    NOT NULL DEFAULT '',
+	status           TEXT    NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'in_progress', 'done')),
+	progress_percent INTEGER NOT NULL DEFAULT 0 CHECK(progress_percent >= 0 AND progress_percent <= 100),
+	created_at       DATETIME NOT NULL DEFAULT (datetime('now')),
+	updated_at       DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+CREATE INDEX IF NOT EXISTS idx_todos_status ON todos(status);
@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN category TEXT NOT NULL DEFAULT 'personal' CHECK(category IN ('personal', 'work', 'other'));
+CREATE INDEX IF NOT EXISTS idx_todos_category ON todos(category);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	todo_id    INTEGER NOT NULL,
+	action     TEXT    NOT NULL CHECK(action IN ('create', 'update', 'delete')),
+	actor      TEXT    NOT NULL DEFAULT '',
+	request_id TEXT    NOT NULL DEFAULT '',
+	changes    TEXT    NOT NULL DEFAULT '{}',
+	created_at DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_todo_id ON audit_log(todo_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
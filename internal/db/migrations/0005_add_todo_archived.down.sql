@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_archived;
+ALTER TABLE todos DROP COLUMN archived;
@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN archived INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1));
+CREATE INDEX IF NOT EXISTS idx_todos_archived ON todos(archived);
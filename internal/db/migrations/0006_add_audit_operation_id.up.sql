@@ -0,0 +1,2 @@
+ALTER TABLE audit_log ADD COLUMN operation_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_audit_log_operation_id ON audit_log(operation_id);
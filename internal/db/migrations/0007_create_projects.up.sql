@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS projects (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	name        TEXT     NOT NULL UNIQUE,
+	description TEXT     NOT NULL DEFAULT '',
+	created_at  DATETIME NOT NULL DEFAULT (datetime('now')),
+	updated_at  DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+ALTER TABLE todos ADD COLUMN project_id INTEGER;
+CREATE INDEX IF NOT EXISTS idx_todos_project_id ON todos(project_id);
@@ -0,0 +1,60 @@
+CREATE TABLE todos_old (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	title            TEXT    NOT NULL,
+	description      TEXT    NOT NULL DEFAULT '',
+	status           TEXT    NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'in_progress', 'done')),
+	category         TEXT    NOT NULL DEFAULT 'personal' CHECK(category IN ('personal', 'work', 'other')),
+	project_id       INTEGER,
+	progress_percent INTEGER NOT NULL DEFAULT 0 CHECK(progress_percent >= 0 AND progress_percent <= 100),
+	archived         INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1)),
+	version          INTEGER NOT NULL DEFAULT 1,
+	created_at       DATETIME NOT NULL DEFAULT (datetime('now')),
+	updated_at       DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+INSERT INTO todos_old (id, title, description, status, category, project_id, progress_percent, archived, version, created_at, updated_at)
+	SELECT id, title, description, status, category, project_id, progress_percent, archived, version, datetime(created_at, 'unixepoch'), datetime(updated_at, 'unixepoch')
+	FROM todos;
+DELETE FROM sqlite_sequence WHERE name = 'todos_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'todos_old', seq FROM sqlite_sequence WHERE name = 'todos';
+DROP TABLE todos;
+ALTER TABLE todos_old RENAME TO todos;
+CREATE INDEX idx_todos_status ON todos(status);
+CREATE INDEX idx_todos_category ON todos(category);
+CREATE INDEX idx_todos_archived ON todos(archived);
+CREATE INDEX idx_todos_project_id ON todos(project_id);
+
+CREATE TABLE audit_log_old (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	todo_id      INTEGER NOT NULL,
+	action       TEXT    NOT NULL CHECK(action IN ('create', 'update', 'delete')),
+	actor        TEXT    NOT NULL DEFAULT '',
+	request_id   TEXT    NOT NULL DEFAULT '',
+	operation_id TEXT    NOT NULL DEFAULT '',
+	changes      TEXT    NOT NULL DEFAULT '{}',
+	created_at   DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+INSERT INTO audit_log_old (id, todo_id, action, actor, request_id, operation_id, changes, created_at)
+	SELECT id, todo_id, action, actor, request_id, operation_id, changes, datetime(created_at, 'unixepoch')
+	FROM audit_log;
+DELETE FROM sqlite_sequence WHERE name = 'audit_log_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'audit_log_old', seq FROM sqlite_sequence WHERE name = 'audit_log';
+DROP TABLE audit_log;
+ALTER TABLE audit_log_old RENAME TO audit_log;
+CREATE INDEX idx_audit_log_todo_id ON audit_log(todo_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_operation_id ON audit_log(operation_id);
+
+CREATE TABLE projects_old (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	name        TEXT     NOT NULL UNIQUE,
+	description TEXT     NOT NULL DEFAULT '',
+	created_at  DATETIME NOT NULL DEFAULT (datetime('now')),
+	updated_at  DATETIME NOT NULL DEFAULT (datetime('now'))
+);
+INSERT INTO projects_old (id, name, description, created_at, updated_at)
+	SELECT id, name, description, datetime(created_at, 'unixepoch'), datetime(updated_at, 'unixepoch')
+	FROM projects;
+DELETE FROM sqlite_sequence WHERE name = 'projects_old';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'projects_old', seq FROM sqlite_sequence WHERE name = 'projects';
+DROP TABLE projects;
+ALTER TABLE projects_old RENAME TO projects;
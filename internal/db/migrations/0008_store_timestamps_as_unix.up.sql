@@ -0,0 +1,65 @@
+-- SQLite cannot change a column's type in place, so each table is rebuilt
+-- with INTEGER Unix-second timestamps and its rows copied across. The
+-- AUTOINCREMENT counters are carried over so IDs of deleted rows are never
+-- reused.
+
+CREATE TABLE todos_new (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	title            TEXT    NOT NULL,
+	description      TEXT    NOT NULL DEFAULT '',
+	status           TEXT    NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'in_progress', 'done')),
+	category         TEXT    NOT NULL DEFAULT 'personal' CHECK(category IN ('personal', 'work', 'other')),
+	project_id       INTEGER,
+	progress_percent INTEGER NOT NULL DEFAULT 0 CHECK(progress_percent >= 0 AND progress_percent <= 100),
+	archived         INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1)),
+	version          INTEGER NOT NULL DEFAULT 1,
+	created_at       INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at       INTEGER NOT NULL DEFAULT (unixepoch())
+);
+INSERT INTO todos_new (id, title, description, status, category, project_id, progress_percent, archived, version, created_at, updated_at)
+	SELECT id, title, description, status, category, project_id, progress_percent, archived, version, unixepoch(created_at), unixepoch(updated_at)
+	FROM todos;
+DELETE FROM sqlite_sequence WHERE name = 'todos_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'todos_new', seq FROM sqlite_sequence WHERE name = 'todos';
+DROP TABLE todos;
+ALTER TABLE todos_new RENAME TO todos;
+CREATE INDEX idx_todos_status ON todos(status);
+CREATE INDEX idx_todos_category ON todos(category);
+CREATE INDEX idx_todos_archived ON todos(archived);
+CREATE INDEX idx_todos_project_id ON todos(project_id);
+
+CREATE TABLE audit_log_new (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	todo_id      INTEGER NOT NULL,
+	action       TEXT    NOT NULL CHECK(action IN ('create', 'update', 'delete')),
+	actor        TEXT    NOT NULL DEFAULT '',
+	request_id   TEXT    NOT NULL DEFAULT '',
+	operation_id TEXT    NOT NULL DEFAULT '',
+	changes      TEXT    NOT NULL DEFAULT '{}',
+	created_at   INTEGER NOT NULL DEFAULT (unixepoch())
+);
+INSERT INTO audit_log_new (id, todo_id, action, actor, request_id, operation_id, changes, created_at)
+	SELECT id, todo_id, action, actor, request_id, operation_id, changes, unixepoch(created_at)
+	FROM audit_log;
+DELETE FROM sqlite_sequence WHERE name = 'audit_log_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'audit_log_new', seq FROM sqlite_sequence WHERE name = 'audit_log';
+DROP TABLE audit_log;
+ALTER TABLE audit_log_new RENAME TO audit_log;
+CREATE INDEX idx_audit_log_todo_id ON audit_log(todo_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_operation_id ON audit_log(operation_id);
+
+CREATE TABLE projects_new (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	name        TEXT    NOT NULL UNIQUE,
+	description TEXT    NOT NULL DEFAULT '',
+	created_at  INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at  INTEGER NOT NULL DEFAULT (unixepoch())
+);
+INSERT INTO projects_new (id, name, description, created_at, updated_at)
+	SELECT id, name, description, unixepoch(created_at), unixepoch(updated_at)
+	FROM projects;
+DELETE FROM sqlite_sequence WHERE name = 'projects_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'projects_new', seq FROM sqlite_sequence WHERE name = 'projects';
+DROP TABLE projects;
+ALTER TABLE projects_new RENAME TO projects;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todos_due_date;
+ALTER TABLE todos DROP COLUMN due_date;
+ALTER TABLE todos DROP COLUMN priority;
@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN priority TEXT NOT NULL DEFAULT 'none' CHECK(priority IN ('none', 'low', 'medium', 'high', 'urgent'));
+ALTER TABLE todos ADD COLUMN due_date TEXT;
+CREATE INDEX IF NOT EXISTS idx_todos_due_date ON todos(due_date);
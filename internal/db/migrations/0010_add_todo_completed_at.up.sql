@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN completed_at INTEGER;
+UPDATE todos SET completed_at = updated_at WHERE status = 'done';
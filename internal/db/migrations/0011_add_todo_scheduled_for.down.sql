@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_scheduled_for;
+ALTER TABLE todos DROP COLUMN scheduled_for;
@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN scheduled_for TEXT;
+CREATE INDEX IF NOT EXISTS idx_todos_scheduled_for ON todos(scheduled_for);
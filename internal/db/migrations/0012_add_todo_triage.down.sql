@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_triage;
+ALTER TABLE todos DROP COLUMN triage;
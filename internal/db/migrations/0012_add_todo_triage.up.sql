@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN triage TEXT NOT NULL DEFAULT 'done' CHECK(triage IN ('pending', 'done'));
+CREATE INDEX IF NOT EXISTS idx_todos_triage ON todos(triage);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_todos_completed_at ON todos(completed_at);
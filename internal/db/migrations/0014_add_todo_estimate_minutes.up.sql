@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN estimate_minutes INTEGER NOT NULL DEFAULT 0 CHECK(estimate_minutes >= 0);
@@ -0,0 +1,49 @@
+-- Categories move from a CHECK constraint to their own table, seeded with the
+-- three the constraint allowed. SQLite cannot add a foreign key to an
+-- existing column, so todos is rebuilt the same way as in 0008. Todos refer
+-- to categories by name, and renaming a category renames it on its todos.
+
+CREATE TABLE IF NOT EXISTS categories (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT    NOT NULL UNIQUE,
+	color      TEXT    NOT NULL DEFAULT '',
+	sort_order INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+INSERT INTO categories (name, sort_order) VALUES ('personal', 0), ('work', 1), ('other', 2);
+
+CREATE TABLE todos_new (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	title            TEXT    NOT NULL,
+	description      TEXT    NOT NULL DEFAULT '',
+	status           TEXT    NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'in_progress', 'done')),
+	category         TEXT    NOT NULL REFERENCES categories(name) ON UPDATE CASCADE,
+	project_id       INTEGER,
+	progress_percent INTEGER NOT NULL DEFAULT 0 CHECK(progress_percent >= 0 AND progress_percent <= 100),
+	archived         INTEGER NOT NULL DEFAULT 0 CHECK(archived IN (0, 1)),
+	version          INTEGER NOT NULL DEFAULT 1,
+	created_at       INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at       INTEGER NOT NULL DEFAULT (unixepoch()),
+	completed_at     INTEGER,
+	priority         TEXT    NOT NULL DEFAULT 'none' CHECK(priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+	due_date         TEXT,
+	scheduled_for    TEXT,
+	triage           TEXT    NOT NULL DEFAULT 'done' CHECK(triage IN ('pending', 'done')),
+	estimate_minutes INTEGER NOT NULL DEFAULT 0 CHECK(estimate_minutes >= 0)
+);
+INSERT INTO todos_new (id, title, description, status, category, project_id, progress_percent, archived, version, created_at, updated_at, completed_at, priority, due_date, scheduled_for, triage, estimate_minutes)
+	SELECT id, title, description, status, category, project_id, progress_percent, archived, version, created_at, updated_at, completed_at, priority, due_date, scheduled_for, triage, estimate_minutes
+	FROM todos;
+DELETE FROM sqlite_sequence WHERE name = 'todos_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'todos_new', seq FROM sqlite_sequence WHERE name = 'todos';
+DROP TABLE todos;
+ALTER TABLE todos_new RENAME TO todos;
+CREATE INDEX idx_todos_status ON todos(status);
+CREATE INDEX idx_todos_category ON todos(category);
+CREATE INDEX idx_todos_archived ON todos(archived);
+CREATE INDEX idx_todos_project_id ON todos(project_id);
+CREATE INDEX idx_todos_completed_at ON todos(completed_at);
+CREATE INDEX idx_todos_due_date ON todos(due_date);
+CREATE INDEX idx_todos_scheduled_for ON todos(scheduled_for);
+CREATE INDEX idx_todos_triage ON todos(triage);
@@ -0,0 +1,10 @@
+-- Views are saved TODO queries. The filter is stored as JSON so that it can
+-- grow new fields without migrations.
+
+CREATE TABLE IF NOT EXISTS views (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT    NOT NULL UNIQUE,
+	filter     TEXT    NOT NULL DEFAULT '{}',
+	created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
@@ -0,0 +1,6 @@
+-- position orders TODOs manually. Positions are spaced 1024 apart so that a
+-- TODO can usually be moved by updating its own row alone; existing TODOs
+-- keep their creation order.
+ALTER TABLE todos ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+UPDATE todos SET position = id * 1024;
+CREATE INDEX IF NOT EXISTS idx_todos_position ON todos(position);
@@ -0,0 +1,12 @@
+-- Actions are named operations that a single POST can run, for hardware
+-- buttons and shortcuts. What they act on is stored as JSON, like a view's
+-- filter.
+
+CREATE TABLE IF NOT EXISTS actions (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT    NOT NULL UNIQUE,
+	operation  TEXT    NOT NULL,
+	params     TEXT    NOT NULL DEFAULT '{}',
+	created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
@@ -0,0 +1,19 @@
+-- Report subscriptions deliver a report to a webhook on a schedule. The
+-- delivery job picks up those whose next_run_at has passed.
+
+CREATE TABLE IF NOT EXISTS report_subscriptions (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	name         TEXT    NOT NULL UNIQUE,
+	report       TEXT    NOT NULL,
+	format       TEXT    NOT NULL,
+	group_by     TEXT    NOT NULL DEFAULT '',
+	schedule     TEXT    NOT NULL,
+	webhook_url  TEXT    NOT NULL,
+	next_run_at  INTEGER NOT NULL,
+	last_sent_at INTEGER,
+	last_error   TEXT    NOT NULL DEFAULT '',
+	created_at   INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at   INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_subscriptions_next_run_at ON report_subscriptions(next_run_at);
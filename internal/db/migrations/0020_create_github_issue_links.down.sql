@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS github_sync_cursors;
+DROP TABLE IF EXISTS github_issue_links;
@@ -0,0 +1,24 @@
+-- GitHub issue links map issues mirrored by the GitHub sync to their TODOs,
+-- with the state, title, and body the issue had when last synced, so a sync
+-- can tell which side changed. A link outlives its TODO, so the issue of a
+-- deleted TODO is not mirrored again.
+
+CREATE TABLE IF NOT EXISTS github_issue_links (
+	repo      TEXT    NOT NULL,
+	number    INTEGER NOT NULL,
+	todo_id   INTEGER REFERENCES todos(id) ON DELETE SET NULL,
+	state     TEXT    NOT NULL,
+	title     TEXT    NOT NULL,
+	body      TEXT    NOT NULL,
+	synced_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (repo, number)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_github_issue_links_todo_id ON github_issue_links(todo_id);
+
+-- The time each repository was last synced, to read only the issues that
+-- have changed since.
+CREATE TABLE IF NOT EXISTS github_sync_cursors (
+	repo      TEXT    PRIMARY KEY,
+	synced_at INTEGER NOT NULL
+);
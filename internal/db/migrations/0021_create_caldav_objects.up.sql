@@ -0,0 +1,10 @@
+-- CalDAV objects record the resource name and UID a CalDAV client gave a
+-- TODO it created, so the client finds it where it put it. TODOs without
+-- one are served as <id>.ics. Rows outlive their TODO so that sync reports
+-- can tell clients which resource was deleted; todo IDs are never reused.
+
+CREATE TABLE IF NOT EXISTS caldav_objects (
+	name    TEXT    PRIMARY KEY,
+	uid     TEXT    NOT NULL,
+	todo_id INTEGER NOT NULL UNIQUE
+);
@@ -0,0 +1,19 @@
+-- Notification routes post messages about TODO events to a Slack or
+-- Discord webhook. after_audit_id is the last audit entry the route has
+-- been notified of, and overdue_from the first day whose TODOs it has not
+-- yet been told are overdue.
+
+CREATE TABLE IF NOT EXISTS notification_routes (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	name           TEXT    NOT NULL UNIQUE,
+	channel        TEXT    NOT NULL,
+	webhook_url    TEXT    NOT NULL,
+	events         TEXT    NOT NULL DEFAULT '[]',
+	template       TEXT    NOT NULL DEFAULT '',
+	after_audit_id INTEGER NOT NULL DEFAULT 0,
+	overdue_from   TEXT    NOT NULL,
+	last_sent_at   INTEGER,
+	last_error     TEXT    NOT NULL DEFAULT '',
+	created_at     INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at     INTEGER NOT NULL DEFAULT (unixepoch())
+);
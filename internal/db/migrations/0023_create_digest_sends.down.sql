@@ -0,0 +1 @@
+DROP TABLE IF EXISTS digest_sends;
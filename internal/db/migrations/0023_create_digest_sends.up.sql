@@ -0,0 +1,11 @@
+-- Digest sends record every email digest sent, so the next one can list the
+-- TODOs completed since.
+
+CREATE TABLE IF NOT EXISTS digest_sends (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	sent_at    INTEGER NOT NULL,
+	recipients TEXT    NOT NULL,
+	overdue    INTEGER NOT NULL,
+	due_soon   INTEGER NOT NULL,
+	completed  INTEGER NOT NULL
+);
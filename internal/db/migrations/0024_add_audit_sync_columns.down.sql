@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_log_sync_ref;
+ALTER TABLE audit_log DROP COLUMN sync_ref;
+ALTER TABLE audit_log DROP COLUMN version;
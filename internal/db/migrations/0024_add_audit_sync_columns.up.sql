@@ -0,0 +1,12 @@
+-- The version of the TODO each audit entry produced, so that sync can tell
+-- which fields changed after the version a client last saw. Entries written
+-- before this migration have none.
+
+ALTER TABLE audit_log ADD COLUMN version INTEGER;
+
+-- The client's reference of the sync change that made each entry, so that a
+-- retried upload does not apply the same change twice.
+
+ALTER TABLE audit_log ADD COLUMN sync_ref TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_sync_ref ON audit_log(actor, sync_ref) WHERE sync_ref IS NOT NULL;
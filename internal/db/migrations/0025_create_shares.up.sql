@@ -0,0 +1,25 @@
+-- Shares are read-only public links to a TODO. Only a SHA-256 hash of each
+-- link's token is stored, so the database does not hold working links.
+-- Revoked and expired shares are kept, with their accesses, for the
+-- record until their TODO is deleted.
+
+CREATE TABLE IF NOT EXISTS shares (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	todo_id    INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+	token_hash TEXT    NOT NULL UNIQUE,
+	created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	expires_at INTEGER NOT NULL,
+	revoked_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_todo_id ON shares(todo_id);
+
+CREATE TABLE IF NOT EXISTS share_accesses (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	share_id    INTEGER NOT NULL REFERENCES shares(id) ON DELETE CASCADE,
+	accessed_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	remote_addr TEXT    NOT NULL,
+	user_agent  TEXT    NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_accesses_share_id ON share_accesses(share_id);
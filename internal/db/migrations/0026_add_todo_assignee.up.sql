@@ -0,0 +1,4 @@
+-- assignee names who a TODO is assigned to, the way the audit log names
+-- actors; empty if no one.
+ALTER TABLE todos ADD COLUMN assignee TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_todos_assignee ON todos(assignee);
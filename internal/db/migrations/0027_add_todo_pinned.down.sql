@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_pinned;
+ALTER TABLE todos DROP COLUMN pinned;
@@ -0,0 +1,3 @@
+-- pinned TODOs sort before all others in lists and on the board.
+ALTER TABLE todos ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_todos_pinned ON todos(pinned);
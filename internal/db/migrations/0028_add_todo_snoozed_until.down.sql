@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_snoozed_until;
+ALTER TABLE todos DROP COLUMN snoozed_until;
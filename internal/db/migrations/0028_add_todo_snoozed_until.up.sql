@@ -0,0 +1,4 @@
+-- snoozed_until hides a TODO from default listings until it wakes up, in
+-- Unix seconds; NULL if it is not snoozed.
+ALTER TABLE todos ADD COLUMN snoozed_until INTEGER;
+CREATE INDEX IF NOT EXISTS idx_todos_snoozed_until ON todos(snoozed_until);
@@ -0,0 +1,14 @@
+-- Custom fields are user-defined metadata. Definitions live in their own
+-- table; each TODO holds its values in a JSON object keyed by field name,
+-- which is what the audit log diffs and the list endpoint filters on.
+
+CREATE TABLE IF NOT EXISTS custom_fields (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT    NOT NULL UNIQUE,
+	type       TEXT    NOT NULL CHECK(type IN ('text', 'number', 'date', 'enum')),
+	options    TEXT    NOT NULL DEFAULT '[]',
+	created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+ALTER TABLE todos ADD COLUMN custom_fields TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_todos_location;
+ALTER TABLE todos DROP COLUMN place;
+ALTER TABLE todos DROP COLUMN longitude;
+ALTER TABLE todos DROP COLUMN latitude;
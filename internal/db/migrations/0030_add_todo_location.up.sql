@@ -0,0 +1,6 @@
+-- A TODO's location: latitude and longitude in degrees, both NULL if it
+-- has none, and the name of the place, empty if unnamed.
+ALTER TABLE todos ADD COLUMN latitude REAL;
+ALTER TABLE todos ADD COLUMN longitude REAL;
+ALTER TABLE todos ADD COLUMN place TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_todos_location ON todos(latitude, longitude);
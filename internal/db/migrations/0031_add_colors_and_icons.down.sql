@@ -0,0 +1,5 @@
+ALTER TABLE projects DROP COLUMN icon;
+ALTER TABLE projects DROP COLUMN color;
+ALTER TABLE categories DROP COLUMN icon;
+ALTER TABLE todos DROP COLUMN icon;
+ALTER TABLE todos DROP COLUMN color;
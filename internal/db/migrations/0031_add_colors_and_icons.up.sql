@@ -0,0 +1,6 @@
+-- Display colors, as #rrggbb, and icon names for UI clients; empty for none.
+ALTER TABLE todos ADD COLUMN color TEXT NOT NULL DEFAULT '';
+ALTER TABLE todos ADD COLUMN icon TEXT NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN icon TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN color TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN icon TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+-- undo_log keeps, for a short while, what TODOs were like before each
+-- mutation, so that the actor who made it can reverse it. before is the
+-- TODO as JSON, or NULL if the mutation created it; version is the TODO's
+-- version after the mutation, or for a deletion the version deleted.
+
+CREATE TABLE IF NOT EXISTS undo_log (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	actor        TEXT    NOT NULL,
+	operation_id TEXT    NOT NULL,
+	todo_id      INTEGER NOT NULL,
+	action       TEXT    NOT NULL,
+	before       TEXT,
+	version      INTEGER NOT NULL,
+	created_at   INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX IF NOT EXISTS idx_undo_log_actor ON undo_log(actor, id);
+CREATE INDEX IF NOT EXISTS idx_undo_log_operation_id ON undo_log(operation_id);
+CREATE INDEX IF NOT EXISTS idx_undo_log_created_at ON undo_log(created_at);
@@ -0,0 +1,11 @@
+-- trash keeps deleted TODOs, as JSON, until they are restored or purged.
+-- The TODO's ID is kept so that restoring it brings it back under the same
+-- ID.
+
+CREATE TABLE IF NOT EXISTS trash (
+	todo_id    INTEGER PRIMARY KEY,
+	todo       TEXT    NOT NULL,
+	deleted_by TEXT    NOT NULL DEFAULT '',
+	deleted_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX IF NOT EXISTS idx_trash_deleted_at ON trash(deleted_at);
@@ -0,0 +1,15 @@
+CREATE TABLE github_issue_links_old (
+	repo      TEXT    NOT NULL,
+	number    INTEGER NOT NULL,
+	todo_id   INTEGER REFERENCES todos(id) ON DELETE SET NULL,
+	state     TEXT    NOT NULL,
+	title     TEXT    NOT NULL,
+	body      TEXT    NOT NULL,
+	synced_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (repo, number)
+);
+INSERT INTO github_issue_links_old (repo, number, todo_id, state, title, body, synced_at)
+	SELECT repo, number, (SELECT id FROM todos WHERE id = todo_id), state, title, body, synced_at FROM github_issue_links;
+DROP TABLE github_issue_links;
+ALTER TABLE github_issue_links_old RENAME TO github_issue_links;
+CREATE UNIQUE INDEX idx_github_issue_links_todo_id ON github_issue_links(todo_id);
@@ -0,0 +1,20 @@
+-- A GitHub issue link now keeps its TODO while the TODO is in the trash, so
+-- that restoring it resumes mirroring the issue; purging the TODO clears
+-- the link's todo_id instead. The foreign key, which cleared it on delete,
+-- is dropped, and the table is rebuilt as SQLite cannot drop a constraint.
+
+CREATE TABLE github_issue_links_new (
+	repo      TEXT    NOT NULL,
+	number    INTEGER NOT NULL,
+	todo_id   INTEGER,
+	state     TEXT    NOT NULL,
+	title     TEXT    NOT NULL,
+	body      TEXT    NOT NULL,
+	synced_at INTEGER NOT NULL DEFAULT (unixepoch()),
+	PRIMARY KEY (repo, number)
+);
+INSERT INTO github_issue_links_new (repo, number, todo_id, state, title, body, synced_at)
+	SELECT repo, number, todo_id, state, title, body, synced_at FROM github_issue_links;
+DROP TABLE github_issue_links;
+ALTER TABLE github_issue_links_new RENAME TO github_issue_links;
+CREATE UNIQUE INDEX idx_github_issue_links_todo_id ON github_issue_links(todo_id);
@@ -0,0 +1,11 @@
+-- request_counts counts the API requests each client has made per day
+-- (period YYYY-MM-DD) and per month (period YYYY-MM), in UTC, for quotas
+-- and usage reports.
+
+CREATE TABLE IF NOT EXISTS request_counts (
+	client TEXT    NOT NULL,
+	period TEXT    NOT NULL,
+	count  INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (client, period)
+);
+CREATE INDEX IF NOT EXISTS idx_request_counts_period ON request_counts(period);
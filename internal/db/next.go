@@ -0,0 +1,47 @@
+package db
+
+import (
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// actionable matches the TODOs someone could work on now: open, triaged, and
+// neither archived nor snoozed.
+var actionable = func() TodoFilter {
+	archived, snoozed, triage := false, false, model.TriageDone
+	return TodoFilter{
+		Archived: &archived,
+		Snoozed:  &snoozed,
+		Triage:   &triage,
+		Statuses: []model.Status{model.StatusPending, model.StatusInProgress},
+	}
+}()
+
+// nextOrder sorts actionable TODOs in the order NextTodo documents.
+const nextOrder = ` ORDER BY ` + dueDateOrder + `, ` + priorityRank + ` DESC, status = 'in_progress' DESC, position, id LIMIT 1`
+
+// NextTodo returns the actionable TODO to work on first and how many
+// actionable TODOs there are. TODOs due soonest come first, so overdue ones
+// lead and those without a due date come last; ties go to the highest
+// priority, then to TODOs in progress, then to manual order. ErrNotFound is
+// returned if nothing is actionable.
+func (r *SQLite) NextTodo() (model.Todo, int, error) {
+	w := actionable.where()
+
+	var count int
+	q, args := w.Apply(`SELECT COUNT(*) FROM todos`, nil)
+	if err := r.db.QueryRow(q, args...).Scan(&count); err != nil {
+		return model.Todo{}, 0, fmt.Errorf("count actionable todos: %w", err)
+	}
+	if count == 0 {
+		return model.Todo{}, 0, ErrNotFound
+	}
+
+	q, args = w.Apply(`SELECT `+todoColumns+` FROM todos`, nil)
+	todo, err := scanTodo(r.db.QueryRow(q+nextOrder, args...))
+	if err != nil {
+		return model.Todo{}, 0, fmt.Errorf("query next todo: %w", err)
+	}
+	return todo, count, nil
+}
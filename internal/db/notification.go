@@ -0,0 +1,272 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrNotificationRouteExists is returned when a notification route name is
+// already taken.
+var ErrNotificationRouteExists = errors.New("notification route name already exists")
+
+// NotificationRouteSort describes the fields notification route lists can be
+// sorted by.
+var NotificationRouteSort = query.Spec{
+	Columns: map[string]string{
+		"id":           "id",
+		"name":         "name",
+		"channel":      "channel",
+		"last_sent_at": "last_sent_at",
+		"created_at":   "created_at",
+		"updated_at":   "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// NotificationCursor records how far a notification route has been
+// notified: of audit entries up to AuditID, and of TODOs due before the day
+// OverdueFrom being overdue.
+type NotificationCursor struct {
+	AuditID     int64
+	OverdueFrom string
+}
+
+// CreateNotificationRoute inserts a new notification route and returns it.
+// It is notified of changes made from now on, and of TODOs becoming overdue
+// from tomorrow.
+func (r *SQLite) CreateNotificationRoute(req model.NotificationRouteRequest) (model.NotificationRoute, error) {
+	events, err := json.Marshal(req.Events)
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("encode notification events: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkNotificationRouteName(tx, req.Name, 0); err != nil {
+		return model.NotificationRoute{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO notification_routes (name, channel, webhook_url, events, template, after_audit_id, overdue_from)
+		 VALUES (?, ?, ?, ?, ?, (SELECT COALESCE(MAX(id), 0) FROM audit_log), ?)`,
+		req.Name, string(req.Channel), req.WebhookURL, string(events), req.Template, time.Now().UTC().Format(model.DateLayout),
+	)
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("insert notification route: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	route, err := getNotificationRoute(tx, id)
+	if err != nil {
+		return model.NotificationRoute{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return route, nil
+}
+
+// GetNotificationRoute retrieves a single notification route by ID.
+func (r *SQLite) GetNotificationRoute(id int64) (model.NotificationRoute, error) {
+	return getNotificationRoute(r.db, id)
+}
+
+func getNotificationRoute(q querier, id int64) (model.NotificationRoute, error) {
+	row := q.QueryRow(notificationRouteSelect+` WHERE id = ?`, id)
+
+	route, err := scanNotificationRoute(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotificationRoute{}, ErrNotFound
+	}
+	return route, err
+}
+
+// ListNotificationRoutes retrieves notification routes sorted and paginated
+// by opts.
+func (r *SQLite) ListNotificationRoutes(opts query.Options) ([]model.NotificationRoute, error) {
+	q, args := opts.Apply(notificationRouteSelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query notification routes: %w", err)
+	}
+	defer rows.Close()
+
+	routes := []model.NotificationRoute{}
+	for rows.Next() {
+		route, err := scanNotificationRoute(rows)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, rows.Err()
+}
+
+// CountNotificationRoutes returns the number of notification routes.
+func (r *SQLite) CountNotificationRoutes() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM notification_routes`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count notification routes: %w", err)
+	}
+	return count, nil
+}
+
+// ReplaceNotificationRoute replaces every field of a notification route.
+// Its cursor and delivery status are kept.
+func (r *SQLite) ReplaceNotificationRoute(id int64, req model.NotificationRouteRequest) (model.NotificationRoute, error) {
+	events, err := json.Marshal(req.Events)
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("encode notification events: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getNotificationRoute(tx, id); err != nil {
+		return model.NotificationRoute{}, err
+	}
+	if err := checkNotificationRouteName(tx, req.Name, id); err != nil {
+		return model.NotificationRoute{}, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE notification_routes SET name = ?, channel = ?, webhook_url = ?, events = ?, template = ?, updated_at = unixepoch() WHERE id = ?`,
+		req.Name, string(req.Channel), req.WebhookURL, string(events), req.Template, id,
+	)
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("update notification route: %w", err)
+	}
+
+	route, err := getNotificationRoute(tx, id)
+	if err != nil {
+		return model.NotificationRoute{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return route, nil
+}
+
+// DeleteNotificationRoute deletes a notification route.
+func (r *SQLite) DeleteNotificationRoute(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM notification_routes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete notification route: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// NotificationCursor returns how far the notification route id has been
+// notified.
+func (r *SQLite) NotificationCursor(id int64) (NotificationCursor, error) {
+	var c NotificationCursor
+	err := r.db.QueryRow(`SELECT after_audit_id, overdue_from FROM notification_routes WHERE id = ?`, id).Scan(&c.AuditID, &c.OverdueFrom)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrNotFound
+	}
+	if err != nil {
+		return c, fmt.Errorf("get notification cursor: %w", err)
+	}
+	return c, nil
+}
+
+// RecordNotification records an attempt to notify a route and, if it
+// succeeded or there was nothing to post, advances its cursor. sentAt is
+// nil unless a message was posted; postErr is the reason posting failed.
+func (r *SQLite) RecordNotification(id int64, cursor NotificationCursor, sentAt *time.Time, postErr error) error {
+	var err error
+	if postErr != nil {
+		_, err = r.db.Exec(`UPDATE notification_routes SET last_error = ? WHERE id = ?`, postErr.Error(), id)
+	} else {
+		var sent sql.NullInt64
+		if sentAt != nil {
+			sent = sql.NullInt64{Int64: sentAt.Unix(), Valid: true}
+		}
+		_, err = r.db.Exec(
+			`UPDATE notification_routes SET after_audit_id = ?, overdue_from = ?, last_sent_at = COALESCE(?, last_sent_at), last_error = '' WHERE id = ?`,
+			cursor.AuditID, cursor.OverdueFrom, sent, id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("record notification: %w", err)
+	}
+	return nil
+}
+
+// checkNotificationRouteName returns ErrNotificationRouteExists if a
+// notification route other than id already uses name.
+func checkNotificationRouteName(q querier, name string, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM notification_routes WHERE name = ? AND id != ?)`, name, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check notification route name: %w", err)
+	}
+	if exists {
+		return ErrNotificationRouteExists
+	}
+	return nil
+}
+
+const notificationRouteSelect = `SELECT id, name, channel, webhook_url, events, template, last_sent_at, last_error, created_at, updated_at FROM notification_routes`
+
+// scanNotificationRoute scans a single row selected with
+// notificationRouteSelect into a NotificationRoute. sql.ErrNoRows is
+// returned unwrapped.
+func scanNotificationRoute(row rowScanner) (model.NotificationRoute, error) {
+	var route model.NotificationRoute
+	var channel, events string
+	var createdAt, updatedAt int64
+	var lastSentAt sql.NullInt64
+
+	err := row.Scan(&route.ID, &route.Name, &channel, &route.WebhookURL, &events, &route.Template, &lastSentAt, &route.LastError, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotificationRoute{}, err
+	}
+	if err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("scan notification route: %w", err)
+	}
+
+	route.Channel = model.NotificationChannel(channel)
+	if err := json.Unmarshal([]byte(events), &route.Events); err != nil {
+		return model.NotificationRoute{}, fmt.Errorf("decode notification events: %w", err)
+	}
+	if lastSentAt.Valid {
+		t := unixTime(lastSentAt.Int64)
+		route.LastSentAt = &t
+	}
+	route.CreatedAt = unixTime(createdAt)
+	route.UpdatedAt = unixTime(updatedAt)
+
+	return route, nil
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrMoveTargetNotFound is returned when a TODO is moved before or after a
+// TODO that does not exist.
+var ErrMoveTargetNotFound = errors.New("move target not found")
+
+// positionGap is the space left between the positions of adjacent TODOs, so
+// that most moves only update the moved TODO.
+const positionGap = 1024
+
+// MoveTodo moves a TODO in the manual order, before or after another TODO or
+// to an index among the TODOs that are archived or not like it, and records
+// the change in the audit log. Moving a TODO where it already is has no
+// effect. When there is no room left between the new neighbours, every TODO
+// is renumbered first; renumbering does not change their versions, since
+// their order stays the same.
+func (r *SQLite) MoveTodo(id int64, req model.MoveTodoRequest, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	position, moved, err := place(tx, before, req, false)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if !moved {
+		return before, nil
+	}
+
+	_, err = tx.Exec(
+		`UPDATE todos SET position = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+		position, id,
+	)
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("move todo: %w", err)
+	}
+
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := writeAudit(tx, model.AuditActionUpdate, id, &before, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// place returns the position that moves todo as req asks, or false if
+// todo is already there. byStatus is passed to moveBounds. If there is no
+// room at the new place, all TODOs are renumbered first.
+func place(tx *sql.Tx, todo model.Todo, req model.MoveTodoRequest, byStatus bool) (int64, bool, error) {
+	prev, next, err := moveBounds(tx, todo, req, byStatus)
+	if err != nil {
+		return 0, false, err
+	}
+	if (prev == nil || todo.Position > *prev) && (next == nil || todo.Position < *next) {
+		return todo.Position, false, nil
+	}
+
+	position, ok := between(prev, next)
+	if !ok {
+		if err := renumberTodos(tx); err != nil {
+			return 0, false, err
+		}
+		if prev, next, err = moveBounds(tx, todo, req, byStatus); err != nil {
+			return 0, false, err
+		}
+		position, _ = between(prev, next)
+	}
+	return position, true, nil
+}
+
+// moveBounds returns the positions todo must be placed between to satisfy
+// req, ignoring todo itself. A nil bound is open. An index counts the TODOs
+// archived or not like todo, and with byStatus only those in its status.
+func moveBounds(q querier, todo model.Todo, req model.MoveTodoRequest, byStatus bool) (prev, next *int64, err error) {
+	anchor, placeBefore := int64(0), false
+	switch {
+	case req.Before != nil:
+		anchor, placeBefore = *req.Before, true
+	case req.After != nil:
+		anchor = *req.After
+	default:
+		// An index is a move before the TODO currently at it, or after the
+		// last one when it is past the end.
+		anchor, placeBefore, err = indexAnchor(q, todo, *req.Index, byStatus)
+		if err != nil || anchor == 0 {
+			return nil, nil, err
+		}
+	}
+
+	var position int64
+	err = q.QueryRow(`SELECT position FROM todos WHERE id = ?`, anchor).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrMoveTargetNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("query move target: %w", err)
+	}
+
+	if placeBefore {
+		prev, err = neighbour(q, `position < ? AND id != ? ORDER BY position DESC`, position, todo.ID)
+		return prev, &position, err
+	}
+	next, err = neighbour(q, `position > ? AND id != ? ORDER BY position`, position, todo.ID)
+	return &position, next, err
+}
+
+// indexAnchor returns the TODO to place todo before to move it to index
+// among the TODOs archived or not like it, and with byStatus in its status,
+// or the one to place it after if index is past the end. The anchor is 0 if
+// there are no such TODOs.
+func indexAnchor(q querier, todo model.Todo, index int, byStatus bool) (anchor int64, placeBefore bool, err error) {
+	var w query.Where
+	w.Add("archived = ?", todo.Archived)
+	w.Add("id != ?", todo.ID)
+	if byStatus {
+		w.Add("status = ?", string(todo.Status))
+	}
+
+	sel, args := w.Apply(`SELECT id FROM todos`, nil)
+	err = q.QueryRow(sel+` ORDER BY position, id LIMIT 1 OFFSET ?`, append(args, index)...).Scan(&anchor)
+	if err == nil {
+		return anchor, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("query todo at index: %w", err)
+	}
+
+	err = q.QueryRow(sel+` ORDER BY position DESC, id DESC LIMIT 1`, args...).Scan(&anchor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("query last todo: %w", err)
+	}
+	return anchor, false, nil
+}
+
+// neighbour returns the position of the first TODO matching the where and
+// order clause, or nil if there is none.
+func neighbour(q querier, where string, args ...any) (*int64, error) {
+	var position int64
+	err := q.QueryRow(`SELECT position FROM todos WHERE `+where+` LIMIT 1`, args...).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query neighbouring todo: %w", err)
+	}
+	return &position, nil
+}
+
+// between returns a position strictly between the bounds, or false if
+// there is none.
+func between(prev, next *int64) (int64, bool) {
+	switch {
+	case prev == nil && next == nil:
+		return positionGap, true
+	case prev == nil:
+		return *next - positionGap, true
+	case next == nil:
+		return *prev + positionGap, true
+	case *next-*prev > 1:
+		return *prev + (*next-*prev)/2, true
+	default:
+		return 0, false
+	}
+}
+
+// renumberTodos spaces the positions of all TODOs positionGap apart,
+// keeping their order.
+func renumberTodos(tx *sql.Tx) error {
+	_, err := tx.Exec(
+		`UPDATE todos SET position = ranked.n * ?
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS n FROM todos) AS ranked
+		WHERE todos.id = ranked.id`,
+		positionGap,
+	)
+	if err != nil {
+		return fmt.Errorf("renumber todos: %w", err)
+	}
+	return nil
+}
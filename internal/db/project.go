@@ -0,0 +1,408 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrProjectExists is returned when a project name is already taken.
+var ErrProjectExists = errors.New("project name already exists")
+
+// ProjectTodos selects what DeleteProject does with a project's TODOs.
+type ProjectTodos string
+
+const (
+	// ProjectTodosUnassign removes the TODOs from the project.
+	ProjectTodosUnassign ProjectTodos = "unassign"
+	// ProjectTodosReassign moves the TODOs to another project.
+	ProjectTodosReassign ProjectTodos = "reassign"
+	// ProjectTodosDelete deletes the TODOs along with the project.
+	ProjectTodosDelete ProjectTodos = "delete"
+)
+
+// ProjectSort describes the fields project lists can be sorted by.
+var ProjectSort = query.Spec{
+	Columns: map[string]string{
+		"id":               "id",
+		"name":             "name",
+		"progress_percent": "progress_percent",
+		"created_at":       "created_at",
+		"updated_at":       "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// CreateProject inserts a new project and returns it.
+func (r *SQLite) CreateProject(req model.CreateProjectRequest) (model.Project, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Project{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	project, err := createProject(tx, req)
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Project{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return project, nil
+}
+
+// createProject is CreateProject within tx.
+func createProject(tx *sql.Tx, req model.CreateProjectRequest) (model.Project, error) {
+	if err := checkProjectName(tx, req.Name, 0); err != nil {
+		return model.Project{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO projects (name, description, color, icon) VALUES (?, ?, ?, ?)`,
+		req.Name, req.Description, req.Color, req.Icon,
+	)
+	if err != nil {
+		return model.Project{}, fmt.Errorf("insert project: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Project{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	return getProject(tx, id)
+}
+
+// GetProject retrieves a single project by ID with its progress rollup.
+func (r *SQLite) GetProject(id int64) (model.Project, error) {
+	return getProject(r.db, id)
+}
+
+func getProject(q querier, id int64) (model.Project, error) {
+	row := q.QueryRow(projectSelect+` WHERE id = ?`, id)
+
+	p, err := scanProject(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Project{}, ErrNotFound
+	}
+	return p, err
+}
+
+// ListProjects retrieves projects sorted and paginated by opts.
+func (r *SQLite) ListProjects(opts query.Options) ([]model.Project, error) {
+	q, args := opts.Apply(projectSelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []model.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+// ProjectNames returns the name of every project by ID.
+func (r *SQLite) ProjectNames() (map[int64]string, error) {
+	rows, err := r.db.Query(`SELECT id, name FROM projects`)
+	if err != nil {
+		return nil, fmt.Errorf("query project names: %w", err)
+	}
+	defer rows.Close()
+
+	names := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("scan project name: %w", err)
+		}
+		names[id] = name
+	}
+
+	return names, rows.Err()
+}
+
+// CountProjects returns the number of projects.
+func (r *SQLite) CountProjects() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM projects`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count projects: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateProject updates only the provided fields of a project.
+func (r *SQLite) UpdateProject(id int64, req model.UpdateProjectRequest) (model.Project, error) {
+	var setClauses []string
+	var args []any
+
+	if req.Name != nil {
+		setClauses = append(setClauses, "name = ?")
+		args = append(args, *req.Name)
+	}
+	if req.Description != nil {
+		setClauses = append(setClauses, "description = ?")
+		args = append(args, *req.Description)
+	}
+	if req.Color != nil {
+		setClauses = append(setClauses, "color = ?")
+		args = append(args, *req.Color)
+	}
+	if req.Icon != nil {
+		setClauses = append(setClauses, "icon = ?")
+		args = append(args, *req.Icon)
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetProject(id)
+	}
+
+	setClauses = append(setClauses, "updated_at = unixepoch()")
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Project{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if exists, err := projectExists(tx, id); err != nil {
+		return model.Project{}, err
+	} else if !exists {
+		return model.Project{}, ErrNotFound
+	}
+	if req.Name != nil {
+		if err := checkProjectName(tx, *req.Name, id); err != nil {
+			return model.Project{}, err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE projects SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return model.Project{}, fmt.Errorf("update project: %w", err)
+	}
+
+	project, err := getProject(tx, id)
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Project{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return project, nil
+}
+
+// applyProjects makes the projects match specs by name within tx: missing
+// projects are created and existing ones get the given description, color,
+// and icon. Projects not in specs are left alone.
+func applyProjects(tx *sql.Tx, specs []model.CreateProjectRequest) (model.ConfigChanges, error) {
+	changes := model.ConfigChanges{Created: []string{}, Updated: []string{}, Unchanged: []string{}}
+
+	for _, spec := range specs {
+		var id int64
+		var description, color, icon string
+		err := tx.QueryRow(`SELECT id, description, color, icon FROM projects WHERE name = ?`, spec.Name).Scan(&id, &description, &color, &icon)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			_, err := tx.Exec(
+				`INSERT INTO projects (name, description, color, icon) VALUES (?, ?, ?, ?)`,
+				spec.Name, spec.Description, spec.Color, spec.Icon,
+			)
+			if err != nil {
+				return changes, fmt.Errorf("insert project: %w", err)
+			}
+			changes.Created = append(changes.Created, spec.Name)
+		case err != nil:
+			return changes, fmt.Errorf("query project: %w", err)
+		case description != spec.Description || color != spec.Color || icon != spec.Icon:
+			_, err := tx.Exec(
+				`UPDATE projects SET description = ?, color = ?, icon = ?, updated_at = unixepoch() WHERE id = ?`,
+				spec.Description, spec.Color, spec.Icon, id,
+			)
+			if err != nil {
+				return changes, fmt.Errorf("update project: %w", err)
+			}
+			changes.Updated = append(changes.Updated, spec.Name)
+		default:
+			changes.Unchanged = append(changes.Unchanged, spec.Name)
+		}
+	}
+
+	return changes, nil
+}
+
+// DeleteProject deletes a project and unassigns, reassigns, or deletes its
+// TODOs as selected by todos. reassignTo is only used with
+// ProjectTodosReassign and must name another existing project, otherwise
+// ErrProjectNotFound is returned. Every affected TODO is recorded in the audit
+// log under info. Views and actions that refer to the project follow the
+// cascade rules. It returns the number of TODOs affected.
+func (r *SQLite) DeleteProject(id int64, todos ProjectTodos, reassignTo int64, info AuditInfo) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if exists, err := projectExists(tx, id); err != nil {
+		return 0, err
+	} else if !exists {
+		return 0, ErrNotFound
+	}
+	if todos == ProjectTodosReassign {
+		if reassignTo == id {
+			return 0, ErrProjectNotFound
+		}
+		if err := checkProject(tx, reassignTo); err != nil {
+			return 0, err
+		}
+	}
+
+	members, err := selectTodos(tx, `project_id = ?`, id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, before := range members {
+		if todos == ProjectTodosDelete {
+			if err := deleteTodo(tx, before, info); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		var target any
+		if todos == ProjectTodosReassign {
+			target = reassignTo
+		}
+		_, err := tx.Exec(
+			`UPDATE todos SET project_id = ?, updated_at = unixepoch(), version = version + 1 WHERE id = ?`,
+			target, before.ID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("move todo: %w", err)
+		}
+		after, err := getTodo(tx, before.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := writeAudit(tx, model.AuditActionUpdate, before.ID, &before, &after, info); err != nil {
+			return 0, err
+		}
+	}
+
+	if todos != ProjectTodosReassign {
+		reassignTo = 0
+	}
+	if err := cascadeProject(tx, id, reassignTo); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM projects WHERE id = ?`, id); err != nil {
+		return 0, fmt.Errorf("delete project: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return len(members), nil
+}
+
+// checkProject returns ErrProjectNotFound unless the project exists.
+func checkProject(q querier, id int64) error {
+	exists, err := projectExists(q, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func projectExists(q querier, id int64) (bool, error) {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM projects WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check project: %w", err)
+	}
+	return exists, nil
+}
+
+// checkProjectName returns ErrProjectExists if a project other than id
+// already uses name.
+func checkProjectName(q querier, name string, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM projects WHERE name = ? AND id != ?)`, name, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check project name: %w", err)
+	}
+	if exists {
+		return ErrProjectExists
+	}
+	return nil
+}
+
+// nullID maps the zero ID to NULL, for clearing optional references.
+func nullID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// projectSelect selects projects with their progress rollup as a derived
+// table, so that filters and ORDER BY can refer to the rollup columns by name.
+// Done TODOs count as 100% towards progress_percent.
+const projectSelect = `SELECT id, name, description, color, icon, total, pending, in_progress, done, progress_percent, created_at, updated_at
+FROM (
+	SELECT p.id, p.name, p.description, p.color, p.icon, p.created_at, p.updated_at,
+		COUNT(t.id) AS total,
+		COALESCE(SUM(t.status = 'pending'), 0) AS pending,
+		COALESCE(SUM(t.status = 'in_progress'), 0) AS in_progress,
+		COALESCE(SUM(t.status = 'done'), 0) AS done,
+		CAST(COALESCE(ROUND(AVG(CASE WHEN t.status = 'done' THEN 100 ELSE t.progress_percent END)), 0) AS INTEGER) AS progress_percent
+	FROM projects p
+	LEFT JOIN todos t ON t.project_id = p.id
+	GROUP BY p.id
+)`
+
+// scanProject scans a single row selected with projectSelect into a Project.
+// sql.ErrNoRows is returned unwrapped.
+func scanProject(row rowScanner) (model.Project, error) {
+	var p model.Project
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.Color, &p.Icon,
+		&p.Progress.Total, &p.Progress.Pending, &p.Progress.InProgress, &p.Progress.Done, &p.Progress.ProgressPercent,
+		&createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Project{}, err
+	}
+	if err != nil {
+		return model.Project{}, fmt.Errorf("scan project: %w", err)
+	}
+
+	p.CreatedAt = unixTime(createdAt)
+	p.UpdatedAt = unixTime(updatedAt)
+
+	return p, nil
+}
@@ -0,0 +1,375 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+func newTestRepo(t testing.TB) *SQLite {
+	t.Helper()
+	repo, err := New(filepath.Join(t.TempDir(), "todos.db"), slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func newMemoryRepo(t *testing.T) *SQLite {
+	t.Helper()
+	repo, err := NewMemory(slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewMemory: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+var testStatuses = []model.Status{model.StatusPending, model.StatusInProgress, model.StatusDone}
+
+// todoOp is one randomly chosen repository operation. Pick chooses the
+// TODO it applies to among those that exist; Stale makes it send a version
+// other than the current one.
+type todoOp struct {
+	Kind   string
+	Pick   int
+	Title  string
+	Status model.Status
+	Stale  bool
+}
+
+type todoOps []todoOp
+
+// Generate implements quick.Generator.
+func (todoOps) Generate(rnd *rand.Rand, size int) reflect.Value {
+	kinds := []string{"create", "create", "update", "delete", "complete", "reopen", "get"}
+	ops := make(todoOps, rnd.Intn(size+1))
+	for i := range ops {
+		ops[i] = todoOp{
+			Kind:   kinds[rnd.Intn(len(kinds))],
+			Pick:   rnd.Intn(1 << 16),
+			Title:  fmt.Sprintf("todo %d", rnd.Intn(1000)),
+			Status: testStatuses[rnd.Intn(len(testStatuses))],
+			Stale:  rnd.Intn(4) == 0,
+		}
+	}
+	return reflect.ValueOf(ops)
+}
+
+// todoState is what the model expects the repository to hold for a TODO.
+type todoState struct {
+	title   string
+	status  model.Status
+	version int64
+}
+
+// TestTodoOperationsMatchModel applies random sequences of operations to a
+// repository and to a map modelling it, and checks after every one that
+// they agree.
+func TestTodoOperationsMatchModel(t *testing.T) {
+	check := func(ops todoOps) bool {
+		repo := newTestRepo(t)
+		want := map[int64]*todoState{}
+		for i, op := range ops {
+			if err := applyTodoOp(repo, want, op); err != nil {
+				t.Logf("op %d %+v: %v", i, op, err)
+				return false
+			}
+			if err := compareTodos(repo, want); err != nil {
+				t.Logf("after op %d %+v: %v", i, op, err)
+				return false
+			}
+		}
+		if err := checkIntegrity(repo); err != nil {
+			t.Log(err)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(check, &quick.Config{MaxCount: 30}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func applyTodoOp(repo *SQLite, want map[int64]*todoState, op todoOp) error {
+	info := AuditInfo{Actor: "test"}
+	if op.Kind == "create" {
+		todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: op.Title}, info)
+		if err != nil {
+			return err
+		}
+		if _, ok := want[todo.ID]; ok {
+			return fmt.Errorf("created id %d, which is already in use", todo.ID)
+		}
+		want[todo.ID] = &todoState{title: op.Title, status: model.StatusPending, version: 1}
+		return nil
+	}
+
+	ids := slices.Sorted(func(yield func(int64) bool) {
+		for id := range want {
+			if !yield(id) {
+				return
+			}
+		}
+	})
+	if len(ids) == 0 {
+		_, err := repo.GetTodo(int64(op.Pick) + 1)
+		if !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("get from an empty repository: got %v, want ErrNotFound", err)
+		}
+		return nil
+	}
+	id := ids[op.Pick%len(ids)]
+	state := want[id]
+	version := state.version
+	if op.Stale {
+		version++
+	}
+
+	var err error
+	switch op.Kind {
+	case "update":
+		_, err = repo.UpdateTodo(id, version, model.UpdateTodoRequest{Title: &op.Title, Status: &op.Status}, info)
+		if !op.Stale && !model.DefaultTransitions.Allows(state.status, op.Status) {
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) {
+				return fmt.Errorf("update from %s to %s: got %v, want a TransitionError", state.status, op.Status, err)
+			}
+			return nil
+		}
+		if err == nil {
+			state.title, state.status = op.Title, op.Status
+			state.version++
+		}
+	case "delete":
+		err = repo.DeleteTodo(id, version, info)
+		if err == nil {
+			delete(want, id)
+		}
+	case "complete":
+		_, err = repo.CompleteTodo(id, info)
+		if state.status != model.StatusDone {
+			state.status = model.StatusDone
+			state.version++
+		}
+	case "reopen":
+		_, err = repo.ReopenTodo(id, info)
+		if state.status == model.StatusDone {
+			state.status = model.StatusPending
+			state.version++
+		}
+	case "get":
+		_, err = repo.GetTodo(id)
+	}
+	if op.Stale && (op.Kind == "update" || op.Kind == "delete") {
+		if !errors.Is(err, ErrVersionMismatch) {
+			return fmt.Errorf("stale version: got %v, want ErrVersionMismatch", err)
+		}
+		return nil
+	}
+	return err
+}
+
+func compareTodos(repo *SQLite, want map[int64]*todoState) error {
+	count, err := repo.CountTodos(TodoFilter{})
+	if err != nil {
+		return err
+	}
+	if count != len(want) {
+		return fmt.Errorf("CountTodos = %d, want %d", count, len(want))
+	}
+
+	todos, err := repo.ListTodos(TodoFilter{}, query.Options{})
+	if err != nil {
+		return err
+	}
+	if len(todos) != count {
+		return fmt.Errorf("ListTodos returned %d todos, CountTodos %d", len(todos), count)
+	}
+	for _, todo := range todos {
+		state, ok := want[todo.ID]
+		if !ok {
+			return fmt.Errorf("todo %d is listed but was never created or was deleted", todo.ID)
+		}
+		if todo.Title != state.title || todo.Status != state.status || todo.Version != state.version {
+			return fmt.Errorf("todo %d is %q/%s/v%d, want %q/%s/v%d",
+				todo.ID, todo.Title, todo.Status, todo.Version, state.title, state.status, state.version)
+		}
+		if (todo.Status == model.StatusDone) != (todo.CompletedAt != nil) {
+			return fmt.Errorf("todo %d is %s with completed_at %v", todo.ID, todo.Status, todo.CompletedAt)
+		}
+		if todo.UpdatedAt.Before(todo.CreatedAt) {
+			return fmt.Errorf("todo %d was updated at %v, before it was created at %v", todo.ID, todo.UpdatedAt, todo.CreatedAt)
+		}
+	}
+	return nil
+}
+
+// checkIntegrity returns an error describing the problems CheckIntegrity
+// finds, if any.
+func checkIntegrity(repo *SQLite) error {
+	report, err := repo.CheckIntegrity()
+	if err != nil {
+		return err
+	}
+	if !report.OK {
+		return fmt.Errorf("integrity check failed: %v", report.Problems)
+	}
+	return nil
+}
+
+// TestConcurrentTodoOperations runs random operations on shared TODOs from
+// several goroutines, and checks that each sees versions and update times
+// only move forward and that the totals add up once they are done. Run it
+// with -race.
+func TestConcurrentTodoOperations(t *testing.T) {
+	const (
+		workers = 8
+		opsEach = 150
+	)
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "test"}
+
+	var created, deleted atomic.Int64
+	var mu sync.Mutex
+	var ids []int64
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := range workers {
+		wg.Go(func() {
+			rnd := rand.New(rand.NewSource(int64(w)))
+			// seen holds the latest version and update time this worker
+			// has read for each TODO.
+			seen := map[int64]model.Todo{}
+			observe := func(todo model.Todo) error {
+				last, ok := seen[todo.ID]
+				if ok && (todo.Version < last.Version || todo.UpdatedAt.Before(last.UpdatedAt)) {
+					return fmt.Errorf("todo %d went from v%d at %v to v%d at %v",
+						todo.ID, last.Version, last.UpdatedAt, todo.Version, todo.UpdatedAt)
+				}
+				seen[todo.ID] = todo
+				return nil
+			}
+
+			for range opsEach {
+				mu.Lock()
+				n := len(ids)
+				var id int64
+				if n > 0 {
+					id = ids[rnd.Intn(n)]
+				}
+				mu.Unlock()
+
+				var todo model.Todo
+				var err error
+				switch op := rnd.Intn(6); {
+				case op == 0 || n == 0:
+					todo, err = repo.CreateTodo(model.CreateTodoRequest{Title: fmt.Sprintf("worker %d", w)}, info)
+					if err == nil {
+						created.Add(1)
+						mu.Lock()
+						ids = append(ids, todo.ID)
+						mu.Unlock()
+					}
+				case op == 1:
+					if todo, err = repo.GetTodo(id); err == nil {
+						title := fmt.Sprintf("worker %d", w)
+						todo, err = repo.UpdateTodo(id, todo.Version, model.UpdateTodoRequest{Title: &title}, info)
+					}
+				case op == 2:
+					if todo, err = repo.GetTodo(id); err == nil {
+						if err = repo.DeleteTodo(id, todo.Version, info); err == nil {
+							deleted.Add(1)
+							continue
+						}
+					}
+				case op == 3:
+					todo, err = repo.CompleteTodo(id, info)
+				case op == 4:
+					todo, err = repo.ReopenTodo(id, info)
+				default:
+					todo, err = repo.GetTodo(id)
+				}
+				// Other workers may change or delete the TODO between a read
+				// and a write.
+				if errors.Is(err, ErrNotFound) || errors.Is(err, ErrVersionMismatch) {
+					continue
+				}
+				if err == nil {
+					err = observe(todo)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	count, err := repo.CountTodos(TodoFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := created.Load() - deleted.Load(); int64(count) != want {
+		t.Errorf("CountTodos = %d, want %d created - %d deleted = %d", count, created.Load(), deleted.Load(), want)
+	}
+
+	todos, err := repo.ListTodos(TodoFilter{}, query.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, todo := range todos {
+		if todo.Version < 1 || todo.UpdatedAt.Before(todo.CreatedAt) {
+			t.Errorf("todo %d has version %d, created at %v and updated at %v", todo.ID, todo.Version, todo.CreatedAt, todo.UpdatedAt)
+		}
+	}
+	if err := checkIntegrity(repo); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestTodoUpdatedAtMonotonic checks that updating a TODO never moves its
+// update time back, across a second boundary.
+func TestTodoUpdatedAtMonotonic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the clock to tick over a second")
+	}
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "test"}
+
+	todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: "tick"}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := todo.UpdatedAt
+	time.Sleep(1100 * time.Millisecond)
+	title := "tock"
+	if todo, err = repo.UpdateTodo(todo.ID, todo.Version, model.UpdateTodoRequest{Title: &title}, info); err != nil {
+		t.Fatal(err)
+	}
+	if !todo.UpdatedAt.After(created) {
+		t.Errorf("updated at %v, want after %v", todo.UpdatedAt, created)
+	}
+	if !todo.CreatedAt.Equal(created) {
+		t.Errorf("created at changed from %v to %v", created, todo.CreatedAt)
+	}
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// queryLog times every statement run on the database, keeps aggregate
+// statistics, and logs statements slower than its threshold.
+type queryLog struct {
+	logger    *slog.Logger
+	threshold atomic.Int64  // time.Duration; 0 disables slow-query logging
+	writes    atomic.Uint64 // statements executed, which may have changed data
+
+	mu    sync.Mutex
+	count int64
+	errs  int64
+	slow  int64
+	total time.Duration
+	max   time.Duration
+}
+
+func (l *queryLog) record(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	d := time.Since(start)
+	threshold := time.Duration(l.threshold.Load())
+	slow := threshold > 0 && d >= threshold
+
+	l.mu.Lock()
+	l.count++
+	if err != nil {
+		l.errs++
+	}
+	if slow {
+		l.slow++
+	}
+	l.total += d
+	l.max = max(l.max, d)
+	l.mu.Unlock()
+
+	if slow {
+		attrs := []any{
+			slog.String("statement", compactSQL(query)),
+			slog.Any("params", sanitizeArgs(args)),
+			slog.Float64("duration_ms", float64(d.Microseconds())/1000.0),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		l.logger.WarnContext(ctx, "slow query", attrs...)
+	}
+}
+
+func (l *queryLog) stats() model.QueryStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return model.QueryStats{
+		Count:           l.count,
+		Errors:          l.errs,
+		Slow:            l.slow,
+		SlowThresholdMS: millis(time.Duration(l.threshold.Load())),
+		TotalMS:         millis(l.total),
+		MaxMS:           millis(l.max),
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
+}
+
+// compactSQL drops the line comments of a statement and collapses its
+// whitespace onto one line.
+func compactSQL(query string) string {
+	lines := strings.Split(query, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(lines, "\n")), " ")
+}
+
+// sanitizeArgs describes statement parameters for the log. Numbers, booleans,
+// times, and NULLs are kept, since they are mostly IDs and timestamps useful
+// for reproducing a query; text and blobs, which hold TODO content, are
+// replaced by their length.
+func sanitizeArgs(args []driver.NamedValue) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		switch v := a.Value.(type) {
+		case string:
+			out[i] = fmt.Sprintf("<text len=%d>", len(v))
+		case []byte:
+			out[i] = fmt.Sprintf("<blob len=%d>", len(v))
+		case nil:
+			out[i] = "NULL"
+		case time.Time:
+			out[i] = v.UTC().Format(time.RFC3339)
+		default:
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// loggingConnector opens connections whose statements are timed by log.
+type loggingConnector struct {
+	dsn    string
+	driver driver.Driver
+	log    *queryLog
+}
+
+func (c *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{Conn: conn, log: c.log}, nil
+}
+
+func (c *loggingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// loggingConn wraps a driver connection, which must support contexts and
+// executing statements directly, as the SQLite driver does.
+type loggingConn struct {
+	driver.Conn
+	log *queryLog
+}
+
+func (c *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args)
+	if !errors.Is(err, driver.ErrSkip) {
+		c.log.record(ctx, query, args, start, err)
+	}
+	if err == nil {
+		c.log.writes.Add(1)
+	}
+	return res, err
+}
+
+// QueryContext times a query until its rows are closed, since SQLite steps
+// through a statement as its rows are read.
+func (c *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+	if err != nil {
+		c.log.record(ctx, query, args, start, err)
+		return nil, err
+	}
+	return &loggingRows{Rows: rows, ctx: ctx, query: query, args: args, start: start, log: c.log}, nil
+}
+
+func (c *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	b, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, errors.New("driver does not support BeginTx")
+	}
+	start := time.Now()
+	tx, err := b.BeginTx(ctx, opts)
+	c.log.record(ctx, "BEGIN", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingTx{Tx: tx, ctx: ctx, log: c.log}, nil
+}
+
+func (c *loggingConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+type loggingTx struct {
+	driver.Tx
+	ctx context.Context
+	log *queryLog
+}
+
+func (t *loggingTx) Commit() error {
+	start := time.Now()
+	err := t.Tx.Commit()
+	t.log.record(t.ctx, "COMMIT", nil, start, err)
+	return err
+}
+
+func (t *loggingTx) Rollback() error {
+	start := time.Now()
+	err := t.Tx.Rollback()
+	t.log.record(t.ctx, "ROLLBACK", nil, start, err)
+	return err
+}
+
+type loggingRows struct {
+	driver.Rows
+	ctx   context.Context
+	query string
+	args  []driver.NamedValue
+	start time.Time
+	log   *queryLog
+	err   error
+}
+
+func (r *loggingRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return err
+}
+
+func (r *loggingRows) Close() error {
+	err := r.Rows.Close()
+	r.log.record(r.ctx, r.query, r.args, r.start, errors.Join(r.err, err))
+	return err
+}
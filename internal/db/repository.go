@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// Repository is the storage the service runs on: the HTTP handlers, gRPC,
+// the background jobs and every other consumer take it, or a narrower
+// interface of their own, rather than a concrete store. SQLite implements
+// it, on a database file or, with NewMemory, in memory.
+//
+// Configuration, such as SetRules or SetUndo, is not part of it: whoever
+// opens a store configures it before handing it out.
+type Repository interface {
+	// Todos.
+	GetTodo(id int64) (model.Todo, error)
+	ListTodos(filter TodoFilter, opts query.Options) ([]model.Todo, error)
+	CountTodos(filter TodoFilter) (int, error)
+	TodosLastModified() (time.Time, error)
+	CreateTodo(req model.CreateTodoRequest, info AuditInfo) (model.Todo, error)
+	CaptureTodo(req model.CaptureTodoRequest, info AuditInfo) (model.Todo, error)
+	DuplicateTodo(id int64, info AuditInfo) (model.Todo, error)
+	UpdateTodo(id, version int64, req model.UpdateTodoRequest, info AuditInfo) (model.Todo, error)
+	DeleteTodo(id, version int64, info AuditInfo) error
+	CompleteTodo(id int64, info AuditInfo) (model.Todo, error)
+	ReopenTodo(id int64, info AuditInfo) (model.Todo, error)
+	TriageTodo(id int64, req model.TriageTodoRequest, info AuditInfo) (model.Todo, error)
+	MoveTodo(id int64, req model.MoveTodoRequest, info AuditInfo) (model.Todo, error)
+	MoveCard(req model.MoveCardRequest, limit int, info AuditInfo) (model.Todo, error)
+	SetArchived(id int64, archived bool, info AuditInfo) (model.Todo, error)
+	SetAssignee(id int64, assignee string, info AuditInfo) (model.Todo, error)
+	SetLocation(id int64, loc *model.Location, info AuditInfo) (model.Todo, error)
+	SetPinned(id int64, pinned bool, info AuditInfo) (model.Todo, error)
+	SetSchedule(id int64, date *string, info AuditInfo) (model.Todo, error)
+	SetSnooze(id int64, until *time.Time, info AuditInfo) (model.Todo, error)
+	WakeSnoozed(now time.Time, info AuditInfo) ([]model.Todo, error)
+	ArchiveDone(cutoff time.Time, info AuditInfo) (int, error)
+	CountArchivable(cutoff time.Time) (int, error)
+	NextTodo() (model.Todo, int, error)
+	ListCalendar(from, to string) ([]CalendarTodo, error)
+	ImportTodos(todos []ImportTodo, dryRun bool, info AuditInfo) (model.ImportResponse, error)
+	WithTx(ctx context.Context, fn func(tx RepositoryTx) error) error
+
+	// Trash and undo.
+	ListTrash(opts query.Options) ([]model.TrashedTodo, error)
+	CountTrash() (int, error)
+	CountTrashBefore(cutoff time.Time) (int, error)
+	RestoreTodo(id int64, info AuditInfo) (model.Todo, error)
+	PurgeTodo(id int64) error
+	PurgeTrash(cutoff time.Time) (int, error)
+	Undo(info AuditInfo, now time.Time) (model.UndoResponse, error)
+	PruneUndo(now time.Time) (int64, error)
+
+	// Changes, sync and audit.
+	Changes(after int64, limit int) ([]model.Change, bool, error)
+	FieldsChangedSince(id, from, to int64) (fields []string, ok bool, err error)
+	SyncedChange(actor, ref string) (todoID int64, ok bool, err error)
+	ListAudit(filter AuditFilter, opts query.Options) ([]model.AuditEntry, error)
+	CountAudit(filter AuditFilter) (int, error)
+	LatestAuditID() (int64, error)
+	TodosChangedSince(auditID int64) ([]int64, error)
+
+	// Projects and categories.
+	GetProject(id int64) (model.Project, error)
+	ListProjects(opts query.Options) ([]model.Project, error)
+	CountProjects() (int, error)
+	ProjectNames() (map[int64]string, error)
+	CreateProject(req model.CreateProjectRequest) (model.Project, error)
+	UpdateProject(id int64, req model.UpdateProjectRequest) (model.Project, error)
+	DeleteProject(id int64, todos ProjectTodos, reassignTo int64, info AuditInfo) (int, error)
+	GetCategory(id int64) (model.CategoryInfo, error)
+	ListCategories(opts query.Options) ([]model.CategoryInfo, error)
+	CountCategories() (int, error)
+	CreateCategory(req model.CreateCategoryRequest) (model.CategoryInfo, error)
+	UpdateCategory(id int64, req model.UpdateCategoryRequest, info AuditInfo) (model.CategoryInfo, error)
+	DeleteCategory(id int64, reassignTo model.Category, info AuditInfo) (int, error)
+	ApplyConfig(categories []model.CreateCategoryRequest, projects []model.CreateProjectRequest, dryRun bool) (model.ApplyConfigResponse, error)
+
+	// Custom fields and views.
+	GetCustomField(id int64) (model.CustomField, error)
+	ListCustomFields(opts query.Options) ([]model.CustomField, error)
+	CountCustomFields() (int, error)
+	CustomFieldsByName() (map[string]model.CustomField, error)
+	CreateCustomField(req model.CreateCustomFieldRequest) (model.CustomField, error)
+	UpdateCustomField(id int64, req model.UpdateCustomFieldRequest) (model.CustomField, error)
+	DeleteCustomField(id int64, info AuditInfo) (int, error)
+	GetView(id int64) (model.View, error)
+	ListViews(opts query.Options) ([]model.View, error)
+	CountViews() (int, error)
+	CreateView(req model.CreateViewRequest) (model.View, error)
+	UpdateView(id int64, req model.UpdateViewRequest) (model.View, error)
+	DeleteView(id int64) error
+
+	// Actions, notification routes and report subscriptions.
+	GetAction(id int64) (model.Action, error)
+	ListActions(opts query.Options) ([]model.Action, error)
+	CountActions() (int, error)
+	CreateAction(req model.ActionRequest) (model.Action, error)
+	ReplaceAction(id int64, req model.ActionRequest) (model.Action, error)
+	DeleteAction(id int64) error
+	GetNotificationRoute(id int64) (model.NotificationRoute, error)
+	ListNotificationRoutes(opts query.Options) ([]model.NotificationRoute, error)
+	CountNotificationRoutes() (int, error)
+	CreateNotificationRoute(req model.NotificationRouteRequest) (model.NotificationRoute, error)
+	ReplaceNotificationRoute(id int64, req model.NotificationRouteRequest) (model.NotificationRoute, error)
+	DeleteNotificationRoute(id int64) error
+	NotificationCursor(id int64) (NotificationCursor, error)
+	RecordNotification(id int64, cursor NotificationCursor, sentAt *time.Time, postErr error) error
+	GetReportSubscription(id int64) (model.ReportSubscription, error)
+	ListReportSubscriptions(opts query.Options) ([]model.ReportSubscription, error)
+	CountReportSubscriptions() (int, error)
+	DueReportSubscriptions(now time.Time) ([]model.ReportSubscription, error)
+	CreateReportSubscription(req model.ReportSubscriptionRequest) (model.ReportSubscription, error)
+	ReplaceReportSubscription(id int64, req model.ReportSubscriptionRequest) (model.ReportSubscription, error)
+	RecordReportDelivery(id int64, sentAt *time.Time, deliveryErr error, next time.Time) error
+	DeleteReportSubscription(id int64) error
+	LastDigestSentAt() (*time.Time, error)
+	RecordDigestSend(send model.DigestSend) error
+
+	// Shares.
+	GetShare(id int64) (model.Share, error)
+	ListShares(todoID int64) ([]model.Share, error)
+	CreateShare(todoID int64, expiresAt time.Time) (model.Share, error)
+	RevokeShare(id int64) (model.Share, error)
+	OpenShare(token string, now time.Time, access *model.ShareAccess) (model.Share, model.Todo, error)
+	ListShareAccesses(id int64, limit int) ([]model.ShareAccess, int, error)
+
+	// CalDAV and GitHub.
+	CalDAVObjects() (map[int64]CalDAVObject, error)
+	CalDAVObject(name string) (CalDAVObject, error)
+	CreateCalDAVTodo(obj CalDAVObject, req model.CreateTodoRequest, scheduledFor *string, info AuditInfo) (model.Todo, error)
+	GitHubLinks(repo string) (map[int]GitHubLink, error)
+	SaveGitHubLink(link GitHubLink) error
+	CreateGitHubTodo(project string, req model.CreateTodoRequest, link GitHubLink, info AuditInfo) (model.Todo, error)
+	GitHubCursor(repo string) (*time.Time, error)
+	SetGitHubCursor(repo string, t time.Time) error
+
+	// Request counts and usage.
+	AddRequestCounts(counts map[string]map[string]int64) error
+	RequestCounts(period string) (map[string]int64, error)
+	PruneRequestCounts(day, month string) (int64, error)
+	Usage(now time.Time) (model.UsageResponse, error)
+
+	// Maintenance.
+	Snapshot(path string) error
+	CompareSnapshot(path string) (int, []model.TableRestore, error)
+	Restore(path string) error
+	Checkpoint() (model.CheckpointResponse, error)
+	WALSize() (int64, error)
+	Vacuum() (model.VacuumResponse, error)
+	CheckIntegrity() (model.IntegrityReport, error)
+	Migrate() error
+	MigrateDown(target int) error
+	VerifyMigrations() error
+	MigrationStatus() ([]MigrationStatus, error)
+	WriteGeneration() uint64
+	QueryStats() model.QueryStats
+	CacheStats() model.CacheStats
+	Close() error
+}
+
+var _ Repository = (*SQLite)(nil)
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"fmt"
+)
+
+// AddRequestCounts adds counts, keyed by client and then period, to the
+// stored request counts in one transaction.
+func (r *SQLite) AddRequestCounts(counts map[string]map[string]int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO request_counts (client, period, count) VALUES (?, ?, ?)
+		ON CONFLICT(client, period) DO UPDATE SET count = count + excluded.count`,
+	)
+	if err != nil {
+		return fmt.Errorf("prepare request count: %w", err)
+	}
+	defer stmt.Close()
+
+	for client, periods := range counts {
+		for period, n := range periods {
+			if _, err := stmt.Exec(client, period, n); err != nil {
+				return fmt.Errorf("add request count: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RequestCounts returns the number of requests each client made in period.
+func (r *SQLite) RequestCounts(period string) (map[string]int64, error) {
+	rows, err := r.db.Query(`SELECT client, count FROM request_counts WHERE period = ?`, period)
+	if err != nil {
+		return nil, fmt.Errorf("query request counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var client string
+		var n int64
+		if err := rows.Scan(&client, &n); err != nil {
+			return nil, fmt.Errorf("scan request count: %w", err)
+		}
+		counts[client] = n
+	}
+	return counts, rows.Err()
+}
+
+// PruneRequestCounts deletes the daily counts of days before day and the
+// monthly counts of months before month, and returns how many were
+// deleted. Days are YYYY-MM-DD and months YYYY-MM, so each compares only
+// against periods of its own length.
+func (r *SQLite) PruneRequestCounts(day, month string) (int64, error) {
+	result, err := r.db.Exec(
+		`DELETE FROM request_counts WHERE (length(period) = 10 AND period < ?) OR (length(period) = 7 AND period < ?)`,
+		day, month,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune request counts: %w", err)
+	}
+	return result.RowsAffected()
+}
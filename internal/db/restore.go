@@ -0,0 +1,293 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"modernc.org/sqlite"
+
+	"todo-service/internal/model"
+)
+
+// ErrInvalidSnapshot is returned when a file is not a database the
+// repository can be restored from.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// CompareSnapshot checks that the database at path can be restored and
+// reports its schema version and, for every table in either database, how
+// many rows it has now and in the snapshot. The snapshot is not modified.
+func (r *SQLite) CompareSnapshot(path string) (int, []model.TableRestore, error) {
+	version, restored, err := r.inspectSnapshot(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	current, err := tableCounts(r.db)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var names []string
+	for name := range current {
+		names = append(names, name)
+	}
+	for name := range restored {
+		if _, ok := current[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	tables := make([]model.TableRestore, len(names))
+	for i, name := range names {
+		tables[i] = model.TableRestore{Name: name, CurrentRows: current[name], RestoredRows: restored[name]}
+	}
+	return version, tables, nil
+}
+
+// Restore replaces the contents of the database with the snapshot at path,
+// which is not modified. The snapshot is copied and migrated to the current
+// schema first, then copied into the live database with SQLite's backup API
+// while the repository's only connection is held, so other statements wait
+// and never see a partly restored or unmigrated database. IDs are never
+// reused: each table's AUTOINCREMENT counter keeps the higher of its current
+// and restored values, so audit log cursors remain valid.
+func (r *SQLite) Restore(path string) error {
+	if _, _, err := r.inspectSnapshot(path); err != nil {
+		return err
+	}
+
+	staged, err := r.stageSnapshot(path)
+	if err != nil {
+		return err
+	}
+	defer removeDatabase(staged)
+
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	sequences, err := readSequences(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	err = conn.Raw(func(dc any) error {
+		c, ok := dc.(*loggingConn)
+		if !ok {
+			return fmt.Errorf("unexpected connection type %T", dc)
+		}
+		restorer, ok := c.Conn.(interface {
+			NewRestore(string) (*sqlite.Backup, error)
+		})
+		if !ok {
+			return fmt.Errorf("driver connection %T cannot restore", c.Conn)
+		}
+
+		b, err := restorer.NewRestore(staged)
+		if err != nil {
+			return fmt.Errorf("start restore: %w", err)
+		}
+		if _, err := b.Step(-1); err != nil {
+			b.Finish()
+			return fmt.Errorf("copy snapshot: %w", err)
+		}
+		if err := b.Finish(); err != nil {
+			return fmt.Errorf("finish restore: %w", err)
+		}
+		return nil
+	})
+	r.queries.writes.Add(1)
+	if err != nil {
+		return err
+	}
+
+	for name, seq := range sequences {
+		if err := keepSequence(ctx, conn, name, seq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inspectSnapshot checks the database at path, opened read-only, and
+// returns its schema version and the row count of each table.
+func (r *SQLite) inspectSnapshot(path string) (int, map[string]int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, nil, fmt.Errorf("stat snapshot: %w", err)
+	}
+	ro, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, nil, fmt.Errorf("open snapshot: %w", err)
+	}
+	defer ro.Close()
+	snapshot := &SQLite{db: ro, logger: r.logger}
+
+	var check string
+	if err := ro.QueryRow(`PRAGMA integrity_check`).Scan(&check); err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if check != "ok" {
+		return 0, nil, fmt.Errorf("%w: integrity check failed: %s", ErrInvalidSnapshot, check)
+	}
+
+	if ok, err := snapshot.hasTable("todos"); err != nil {
+		return 0, nil, err
+	} else if !ok {
+		return 0, nil, fmt.Errorf("%w: not a todo-service database", ErrInvalidSnapshot)
+	}
+
+	var version int
+	if ok, err := snapshot.hasTable("schema_migrations"); err != nil {
+		return 0, nil, err
+	} else if ok {
+		if err := ro.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+			return 0, nil, fmt.Errorf("query snapshot schema version: %w", err)
+		}
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, nil, err
+	}
+	if latest := migrations[len(migrations)-1].Version; version > latest {
+		return 0, nil, fmt.Errorf("%w: schema version %d is newer than this server's %d", ErrInvalidSnapshot, version, latest)
+	}
+
+	counts, err := tableCounts(ro)
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, counts, nil
+}
+
+// stageSnapshot copies the snapshot at path next to the database, or to the
+// temporary directory for an in-memory one, and migrates the copy,
+// returning its path.
+func (r *SQLite) stageSnapshot(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open snapshot: %w", err)
+	}
+	defer src.Close()
+
+	dir := filepath.Dir(r.path)
+	if r.path == "" {
+		dir = os.TempDir() // the database is in memory
+	}
+	dst, err := os.CreateTemp(dir, "restore-*.db")
+	if err != nil {
+		return "", fmt.Errorf("create staging file: %w", err)
+	}
+	staged := dst.Name()
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		removeDatabase(staged)
+		return "", fmt.Errorf("copy snapshot: %w", err)
+	}
+
+	repo, err := Open(staged, r.logger)
+	if err == nil {
+		err = repo.Migrate()
+		if closeErr := repo.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		removeDatabase(staged)
+		return "", fmt.Errorf("migrate snapshot: %w", err)
+	}
+	return staged, nil
+}
+
+// removeDatabase deletes a database file and its write-ahead log.
+func removeDatabase(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		os.Remove(path + suffix)
+	}
+}
+
+// tableCounts returns the row count of every table.
+func tableCounts(q querier) (map[string]int, error) {
+	rows, err := q.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return nil, fmt.Errorf("query sqlite_master: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query sqlite_master: %w", err)
+	}
+
+	counts := make(map[string]int, len(tables))
+	for _, table := range tables {
+		var n int
+		if err := q.QueryRow(`SELECT COUNT(*) FROM "` + table + `"`).Scan(&n); err != nil {
+			return nil, fmt.Errorf("count %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
+
+// readSequences returns the AUTOINCREMENT counter of every table that has
+// one.
+func readSequences(ctx context.Context, conn *sql.Conn) (map[string]int64, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT name, seq FROM sqlite_sequence`)
+	if err != nil {
+		return nil, fmt.Errorf("query sqlite_sequence: %w", err)
+	}
+	defer rows.Close()
+
+	sequences := map[string]int64{}
+	for rows.Next() {
+		var name string
+		var seq int64
+		if err := rows.Scan(&name, &seq); err != nil {
+			return nil, fmt.Errorf("scan sqlite_sequence: %w", err)
+		}
+		sequences[name] = seq
+	}
+	return sequences, rows.Err()
+}
+
+// keepSequence raises the AUTOINCREMENT counter of the named table to at
+// least seq, if the table still exists.
+func keepSequence(ctx context.Context, conn *sql.Conn, name string, seq int64) error {
+	res, err := conn.ExecContext(ctx, `UPDATE sqlite_sequence SET seq = MAX(seq, ?) WHERE name = ?`, seq, name)
+	if err != nil {
+		return fmt.Errorf("update sequence of %s: %w", name, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	} else if n > 0 {
+		return nil
+	}
+
+	_, err = conn.ExecContext(ctx,
+		`INSERT INTO sqlite_sequence (name, seq) SELECT ?, ? WHERE EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)`,
+		name, seq, name,
+	)
+	if err != nil {
+		return fmt.Errorf("insert sequence of %s: %w", name, err)
+	}
+	return nil
+}
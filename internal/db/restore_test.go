@@ -0,0 +1,227 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// sequence returns the AUTOINCREMENT counter of table.
+func sequence(t *testing.T, repo *SQLite, table string) int64 {
+	t.Helper()
+	var seq int64
+	if err := repo.db.QueryRow(`SELECT seq FROM sqlite_sequence WHERE name = ?`, table).Scan(&seq); err != nil {
+		t.Fatalf("read sequence of %s: %v", table, err)
+	}
+	return seq
+}
+
+func createTestTodo(t *testing.T, repo *SQLite, title string) model.Todo {
+	t.Helper()
+	todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: title}, AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo(%q): %v", title, err)
+	}
+	return todo
+}
+
+// TestRestore restores a snapshot over a database file and over the memory
+// backend, whose snapshots are files all the same.
+func TestRestore(t *testing.T) {
+	for name, open := range map[string]func(t *testing.T) *SQLite{
+		BackendSQLite: func(t *testing.T) *SQLite { return newTestRepo(t) },
+		BackendMemory: newMemoryRepo,
+	} {
+		t.Run(name, func(t *testing.T) {
+			testRestore(t, open(t))
+		})
+	}
+}
+
+func testRestore(t *testing.T, repo *SQLite) {
+	kept := createTestTodo(t, repo, "kept")
+	deleted := createTestTodo(t, repo, "deleted after the snapshot")
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := repo.Snapshot(snapshot); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	created := createTestTodo(t, repo, "created after the snapshot")
+	if err := repo.DeleteTodo(deleted.ID, 0, AuditInfo{}); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	todoSeq, auditSeq := sequence(t, repo, "todos"), sequence(t, repo, "audit_log")
+
+	if err := repo.Restore(snapshot); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	for _, want := range []model.Todo{kept, deleted} {
+		got, err := repo.GetTodo(want.ID)
+		if err != nil {
+			t.Errorf("GetTodo(%d) after restore: %v", want.ID, err)
+		} else if got.Title != want.Title || got.Version != want.Version {
+			t.Errorf("GetTodo(%d) after restore = %q version %d, want %q version %d", want.ID, got.Title, got.Version, want.Title, want.Version)
+		}
+	}
+	if _, err := repo.GetTodo(created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo(%d) of a TODO created after the snapshot: got %v, want ErrNotFound", created.ID, err)
+	}
+
+	// The restored counters are lower; the current ones must win so that IDs
+	// are not reused.
+	if got := sequence(t, repo, "todos"); got != todoSeq {
+		t.Errorf("todos sequence after restore = %d, want %d", got, todoSeq)
+	}
+	if got := sequence(t, repo, "audit_log"); got != auditSeq {
+		t.Errorf("audit_log sequence after restore = %d, want %d", got, auditSeq)
+	}
+	if next := createTestTodo(t, repo, "next"); next.ID != todoSeq+1 {
+		t.Errorf("first TODO after restore has ID %d, want %d", next.ID, todoSeq+1)
+	}
+}
+
+func TestRestoreKeepsHigherSnapshotSequence(t *testing.T) {
+	repo := newTestRepo(t)
+	for _, title := range []string{"a", "b", "c"} {
+		createTestTodo(t, repo, title)
+	}
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := repo.Snapshot(snapshot); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	want := sequence(t, repo, "todos")
+
+	// Restore into a fresh database whose counters are behind the snapshot's.
+	fresh := newTestRepo(t)
+	if err := fresh.Restore(snapshot); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := sequence(t, fresh, "todos"); got != want {
+		t.Errorf("todos sequence after restore = %d, want the snapshot's %d", got, want)
+	}
+	todos, err := fresh.ListTodos(TodoFilter{}, testOptions(t, TodoSort))
+	if err != nil {
+		t.Fatalf("ListTodos: %v", err)
+	}
+	if len(todos) != 3 {
+		t.Errorf("got %d TODOs after restore, want 3", len(todos))
+	}
+}
+
+func TestRestoreRejectsInvalidSnapshot(t *testing.T) {
+	repo := newTestRepo(t)
+	todo := createTestTodo(t, repo, "survives")
+
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.db")
+	if err := os.WriteFile(garbage, []byte("not a database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	foreign := filepath.Join(dir, "foreign.db")
+	other, err := Open(foreign, repo.logger)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := other.db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+
+	for _, path := range []string{garbage, foreign} {
+		if _, _, err := repo.CompareSnapshot(path); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("CompareSnapshot(%s): got %v, want ErrInvalidSnapshot", filepath.Base(path), err)
+		}
+		if err := repo.Restore(path); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("Restore(%s): got %v, want ErrInvalidSnapshot", filepath.Base(path), err)
+		}
+	}
+	if _, err := repo.GetTodo(todo.ID); err != nil {
+		t.Errorf("GetTodo after rejected restores: %v", err)
+	}
+}
+
+func TestCompareSnapshot(t *testing.T) {
+	repo := newTestRepo(t)
+	createTestTodo(t, repo, "in the snapshot")
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := repo.Snapshot(snapshot); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	createTestTodo(t, repo, "not in the snapshot")
+
+	_, tables, err := repo.CompareSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("CompareSnapshot: %v", err)
+	}
+	for _, table := range tables {
+		if table.Name == "todos" {
+			if table.CurrentRows != 2 || table.RestoredRows != 1 {
+				t.Errorf("todos: %d current and %d restored rows, want 2 and 1", table.CurrentRows, table.RestoredRows)
+			}
+			return
+		}
+	}
+	t.Errorf("CompareSnapshot did not report the todos table: %+v", tables)
+}
+
+func TestRestoreInvalidatesReadCache(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.SetReadCache(100, time.Hour)
+	todo := createTestTodo(t, repo, "before the snapshot")
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := repo.Snapshot(snapshot); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	title := "after the snapshot"
+	if _, err := repo.UpdateTodo(todo.ID, 0, model.UpdateTodoRequest{Title: &title}, AuditInfo{}); err != nil {
+		t.Fatalf("UpdateTodo: %v", err)
+	}
+	createTestTodo(t, repo, "created after the snapshot")
+
+	// Fill the cache with what the restore is about to replace.
+	opts := testOptions(t, TodoSort)
+	for range 2 {
+		if _, err := repo.GetTodo(todo.ID); err != nil {
+			t.Fatalf("GetTodo: %v", err)
+		}
+		if _, err := repo.ListTodos(TodoFilter{}, opts); err != nil {
+			t.Fatalf("ListTodos: %v", err)
+		}
+		if _, err := repo.CountTodos(TodoFilter{}); err != nil {
+			t.Fatalf("CountTodos: %v", err)
+		}
+	}
+	if repo.CacheStats().Hits == 0 {
+		t.Fatal("reads were not cached")
+	}
+
+	if err := repo.Restore(snapshot); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	got, err := repo.GetTodo(todo.ID)
+	if err != nil {
+		t.Fatalf("GetTodo: %v", err)
+	}
+	if got.Title != todo.Title {
+		t.Errorf("GetTodo after restore = %q, want the restored %q", got.Title, todo.Title)
+	}
+	todos, err := repo.ListTodos(TodoFilter{}, opts)
+	if err != nil {
+		t.Fatalf("ListTodos: %v", err)
+	}
+	if len(todos) != 1 || todos[0].Title != todo.Title {
+		t.Errorf("ListTodos after restore = %+v, want only %q", todos, todo.Title)
+	}
+	if n, err := repo.CountTodos(TodoFilter{}); err != nil || n != 1 {
+		t.Errorf("CountTodos after restore = %d, %v; want 1", n, err)
+	}
+}
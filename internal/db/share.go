@@ -0,0 +1,226 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// CreateShare creates a link to the TODO todoID that works until
+// expiresAt. The returned share holds the link's token, which is not
+// stored and cannot be retrieved again.
+func (r *SQLite) CreateShare(todoID int64, expiresAt time.Time) (model.Share, error) {
+	b := make([]byte, 24)
+	rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Share{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getTodo(tx, todoID); err != nil {
+		return model.Share{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO shares (todo_id, token_hash, expires_at) VALUES (?, ?, ?)`,
+		todoID, hashToken(token), expiresAt.Unix(),
+	)
+	if err != nil {
+		return model.Share{}, fmt.Errorf("insert share: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Share{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	share, err := getShare(tx, `s.id = ?`, id)
+	if err != nil {
+		return model.Share{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Share{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	share.Token = token
+	return share, nil
+}
+
+// GetShare retrieves a single share by ID.
+func (r *SQLite) GetShare(id int64) (model.Share, error) {
+	return getShare(r.db, `s.id = ?`, id)
+}
+
+// ListShares retrieves the shares of the TODO todoID, newest first.
+func (r *SQLite) ListShares(todoID int64) ([]model.Share, error) {
+	if _, err := getTodo(r.db, todoID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(shareSelect+` WHERE s.todo_id = ? ORDER BY s.id DESC`, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("query shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []model.Share{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+// RevokeShare stops the link of share id from working. Revoking a share
+// again keeps when it was first revoked.
+func (r *SQLite) RevokeShare(id int64) (model.Share, error) {
+	result, err := r.db.Exec(`UPDATE shares SET revoked_at = COALESCE(revoked_at, unixepoch()) WHERE id = ?`, id)
+	if err != nil {
+		return model.Share{}, fmt.Errorf("revoke share: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return model.Share{}, fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return model.Share{}, ErrNotFound
+	}
+	return r.GetShare(id)
+}
+
+// OpenShare returns the share whose link has token, and its TODO, and
+// records access unless it is nil. ErrNotFound is returned if no share has
+// token, or if it has expired or was revoked at now.
+func (r *SQLite) OpenShare(token string, now time.Time, access *model.ShareAccess) (model.Share, model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Share{}, model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	share, err := getShare(tx, `s.token_hash = ?`, hashToken(token))
+	if err != nil {
+		return model.Share{}, model.Todo{}, err
+	}
+	if !share.Active(now) {
+		return model.Share{}, model.Todo{}, ErrNotFound
+	}
+
+	todo, err := getTodo(tx, share.TodoID)
+	if err != nil {
+		return model.Share{}, model.Todo{}, err
+	}
+
+	if access != nil {
+		_, err = tx.Exec(
+			`INSERT INTO share_accesses (share_id, accessed_at, remote_addr, user_agent) VALUES (?, ?, ?, ?)`,
+			share.ID, now.Unix(), access.RemoteAddr, access.UserAgent,
+		)
+		if err != nil {
+			return model.Share{}, model.Todo{}, fmt.Errorf("record share access: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Share{}, model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return share, todo, nil
+}
+
+// ListShareAccesses retrieves the latest limit accesses of share id, newest
+// first, and the number there are.
+func (r *SQLite) ListShareAccesses(id int64, limit int) ([]model.ShareAccess, int, error) {
+	share, err := r.GetShare(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, share_id, accessed_at, remote_addr, user_agent FROM share_accesses WHERE share_id = ? ORDER BY id DESC LIMIT ?`,
+		id, limit,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query share accesses: %w", err)
+	}
+	defer rows.Close()
+
+	accesses := []model.ShareAccess{}
+	for rows.Next() {
+		var a model.ShareAccess
+		var accessedAt int64
+		if err := rows.Scan(&a.ID, &a.ShareID, &accessedAt, &a.RemoteAddr, &a.UserAgent); err != nil {
+			return nil, 0, fmt.Errorf("scan share access: %w", err)
+		}
+		a.AccessedAt = unixTime(accessedAt)
+		accesses = append(accesses, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate share accesses: %w", err)
+	}
+
+	return accesses, share.Accesses, nil
+}
+
+// hashToken returns the hash a share's token is stored as.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+const shareSelect = `SELECT s.id, s.todo_id, s.created_at, s.expires_at, s.revoked_at,
+	(SELECT COUNT(*) FROM share_accesses a WHERE a.share_id = s.id),
+	(SELECT MAX(a.accessed_at) FROM share_accesses a WHERE a.share_id = s.id)
+	FROM shares s`
+
+func getShare(q querier, where string, arg any) (model.Share, error) {
+	share, err := scanShare(q.QueryRow(shareSelect+` WHERE `+where, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Share{}, ErrNotFound
+	}
+	return share, err
+}
+
+// scanShare scans a single row selected with shareSelect into a Share.
+// sql.ErrNoRows is returned unwrapped.
+func scanShare(row rowScanner) (model.Share, error) {
+	var s model.Share
+	var createdAt, expiresAt int64
+	var revokedAt, lastAccessedAt sql.NullInt64
+
+	err := row.Scan(&s.ID, &s.TodoID, &createdAt, &expiresAt, &revokedAt, &s.Accesses, &lastAccessedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Share{}, err
+	}
+	if err != nil {
+		return model.Share{}, fmt.Errorf("scan share: %w", err)
+	}
+
+	s.CreatedAt = unixTime(createdAt)
+	s.ExpiresAt = unixTime(expiresAt)
+	if revokedAt.Valid {
+		t := unixTime(revokedAt.Int64)
+		s.RevokedAt = &t
+	}
+	if lastAccessedAt.Valid {
+		t := unixTime(lastAccessedAt.Int64)
+		s.LastAccessedAt = &t
+	}
+	return s, nil
+}
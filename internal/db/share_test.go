@@ -0,0 +1,96 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+func TestOpenShare(t *testing.T) {
+	repo := newTestRepo(t)
+	todo := createTestTodo(t, repo, "shared")
+	now := time.Now()
+
+	share, err := repo.CreateShare(todo.ID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShare: %v", err)
+	}
+	if share.Token == "" {
+		t.Fatal("CreateShare returned no token")
+	}
+
+	access := &model.ShareAccess{RemoteAddr: "192.0.2.1", UserAgent: "test"}
+	got, gotTodo, err := repo.OpenShare(share.Token, now, access)
+	if err != nil {
+		t.Fatalf("OpenShare: %v", err)
+	}
+	if got.ID != share.ID || gotTodo.ID != todo.ID || gotTodo.Title != todo.Title {
+		t.Errorf("OpenShare = share %d and TODO %+v, want share %d and TODO %d", got.ID, gotTodo, share.ID, todo.ID)
+	}
+	if _, _, err := repo.OpenShare(share.Token+"x", now, access); !errors.Is(err, ErrNotFound) {
+		t.Errorf("OpenShare with an unknown token: got %v, want ErrNotFound", err)
+	}
+
+	// Read-only opens are not recorded.
+	if _, _, err := repo.OpenShare(share.Token, now, nil); err != nil {
+		t.Fatalf("OpenShare without recording: %v", err)
+	}
+	accesses, total, err := repo.ListShareAccesses(share.ID, 10)
+	if err != nil {
+		t.Fatalf("ListShareAccesses: %v", err)
+	}
+	if total != 1 || len(accesses) != 1 || accesses[0].RemoteAddr != access.RemoteAddr || accesses[0].UserAgent != access.UserAgent {
+		t.Errorf("got %d accesses %+v, want only the recorded one", total, accesses)
+	}
+}
+
+func TestOpenShareExpired(t *testing.T) {
+	repo := newTestRepo(t)
+	todo := createTestTodo(t, repo, "shared")
+	now := time.Now()
+
+	share, err := repo.CreateShare(todo.ID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShare: %v", err)
+	}
+	if _, _, err := repo.OpenShare(share.Token, now.Add(2*time.Hour), &model.ShareAccess{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("OpenShare after expiry: got %v, want ErrNotFound", err)
+	}
+	if got, err := repo.GetShare(share.ID); err != nil || got.Accesses != 0 {
+		t.Errorf("GetShare after an expired open = %+v, %v; want no accesses", got, err)
+	}
+}
+
+func TestOpenShareRevoked(t *testing.T) {
+	repo := newTestRepo(t)
+	todo := createTestTodo(t, repo, "shared")
+	now := time.Now()
+
+	share, err := repo.CreateShare(todo.ID, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShare: %v", err)
+	}
+	revoked, err := repo.RevokeShare(share.ID)
+	if err != nil {
+		t.Fatalf("RevokeShare: %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Fatal("RevokeShare did not set revoked_at")
+	}
+	again, err := repo.RevokeShare(share.ID)
+	if err != nil {
+		t.Fatalf("RevokeShare again: %v", err)
+	}
+	if !again.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Errorf("revoking again moved revoked_at from %v to %v", revoked.RevokedAt, again.RevokedAt)
+	}
+
+	if _, _, err := repo.OpenShare(share.Token, now, &model.ShareAccess{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("OpenShare after revocation: got %v, want ErrNotFound", err)
+	}
+	if _, err := repo.RevokeShare(share.ID + 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RevokeShare of an unknown share: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrSubscriptionExists is returned when a report subscription name is
+// already taken.
+var ErrSubscriptionExists = errors.New("report subscription name already exists")
+
+// SubscriptionSort describes the fields report subscription lists can be
+// sorted by.
+var SubscriptionSort = query.Spec{
+	Columns: map[string]string{
+		"id":           "id",
+		"name":         "name",
+		"report":       "report",
+		"next_run_at":  "next_run_at",
+		"last_sent_at": "last_sent_at",
+		"created_at":   "created_at",
+		"updated_at":   "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// subscriptionDefaults fills in the format and group a request may leave
+// empty.
+func subscriptionDefaults(req model.ReportSubscriptionRequest) model.ReportSubscriptionRequest {
+	if req.Format == "" {
+		req.Format = model.SubscriptionFormats[req.Report][0]
+	}
+	if req.Group == "" && req.Report == model.SubscriptionTodos {
+		req.Group = "project"
+	}
+	return req
+}
+
+// CreateReportSubscription inserts a new report subscription, first due on
+// its schedule's next delivery time, and returns it.
+func (r *SQLite) CreateReportSubscription(req model.ReportSubscriptionRequest) (model.ReportSubscription, error) {
+	req = subscriptionDefaults(req)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSubscriptionName(tx, req.Name, 0); err != nil {
+		return model.ReportSubscription{}, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO report_subscriptions (name, report, format, group_by, schedule, webhook_url, next_run_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		req.Name, string(req.Report), req.Format, req.Group, string(req.Schedule), req.WebhookURL, req.Schedule.Next(time.Now()).Unix(),
+	)
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("insert report subscription: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	sub, err := getSubscription(tx, id)
+	if err != nil {
+		return model.ReportSubscription{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return sub, nil
+}
+
+// GetReportSubscription retrieves a single report subscription by ID.
+func (r *SQLite) GetReportSubscription(id int64) (model.ReportSubscription, error) {
+	return getSubscription(r.db, id)
+}
+
+func getSubscription(q querier, id int64) (model.ReportSubscription, error) {
+	row := q.QueryRow(subscriptionSelect+` WHERE id = ?`, id)
+
+	s, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ReportSubscription{}, ErrNotFound
+	}
+	return s, err
+}
+
+// ListReportSubscriptions retrieves report subscriptions sorted and
+// paginated by opts.
+func (r *SQLite) ListReportSubscriptions(opts query.Options) ([]model.ReportSubscription, error) {
+	q, args := opts.Apply(subscriptionSelect, nil)
+	return r.querySubscriptions(q, args...)
+}
+
+// DueReportSubscriptions retrieves the report subscriptions due for
+// delivery at now, longest overdue first.
+func (r *SQLite) DueReportSubscriptions(now time.Time) ([]model.ReportSubscription, error) {
+	return r.querySubscriptions(subscriptionSelect+` WHERE next_run_at <= ? ORDER BY next_run_at, id`, now.Unix())
+}
+
+func (r *SQLite) querySubscriptions(q string, args ...any) ([]model.ReportSubscription, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query report subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []model.ReportSubscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+
+	return subs, rows.Err()
+}
+
+// CountReportSubscriptions returns the number of report subscriptions.
+func (r *SQLite) CountReportSubscriptions() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM report_subscriptions`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count report subscriptions: %w", err)
+	}
+	return count, nil
+}
+
+// ReplaceReportSubscription replaces every field of a report subscription
+// and reschedules it for its schedule's next delivery time. Its delivery
+// history is kept.
+func (r *SQLite) ReplaceReportSubscription(id int64, req model.ReportSubscriptionRequest) (model.ReportSubscription, error) {
+	req = subscriptionDefaults(req)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getSubscription(tx, id); err != nil {
+		return model.ReportSubscription{}, err
+	}
+	if err := checkSubscriptionName(tx, req.Name, id); err != nil {
+		return model.ReportSubscription{}, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE report_subscriptions SET name = ?, report = ?, format = ?, group_by = ?, schedule = ?, webhook_url = ?, next_run_at = ?, updated_at = unixepoch() WHERE id = ?`,
+		req.Name, string(req.Report), req.Format, req.Group, string(req.Schedule), req.WebhookURL, req.Schedule.Next(time.Now()).Unix(), id,
+	)
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("update report subscription: %w", err)
+	}
+
+	sub, err := getSubscription(tx, id)
+	if err != nil {
+		return model.ReportSubscription{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return sub, nil
+}
+
+// RecordReportDelivery records a delivery attempt and when the next one is
+// due. sentAt is nil if the attempt failed with deliveryErr.
+func (r *SQLite) RecordReportDelivery(id int64, sentAt *time.Time, deliveryErr error, next time.Time) error {
+	var sent sql.NullInt64
+	if sentAt != nil {
+		sent = sql.NullInt64{Int64: sentAt.Unix(), Valid: true}
+	}
+	lastError := ""
+	if deliveryErr != nil {
+		lastError = deliveryErr.Error()
+	}
+	_, err := r.db.Exec(
+		`UPDATE report_subscriptions SET last_sent_at = COALESCE(?, last_sent_at), last_error = ?, next_run_at = ? WHERE id = ?`,
+		sent, lastError, next.Unix(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("record report delivery: %w", err)
+	}
+	return nil
+}
+
+// DeleteReportSubscription deletes a report subscription.
+func (r *SQLite) DeleteReportSubscription(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM report_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete report subscription: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkSubscriptionName returns ErrSubscriptionExists if a report
+// subscription other than id already uses name.
+func checkSubscriptionName(q querier, name string, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM report_subscriptions WHERE name = ? AND id != ?)`, name, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check report subscription name: %w", err)
+	}
+	if exists {
+		return ErrSubscriptionExists
+	}
+	return nil
+}
+
+const subscriptionSelect = `SELECT id, name, report, format, group_by, schedule, webhook_url, next_run_at, last_sent_at, last_error, created_at, updated_at FROM report_subscriptions`
+
+// scanSubscription scans a single row selected with subscriptionSelect into
+// a ReportSubscription. sql.ErrNoRows is returned unwrapped.
+func scanSubscription(row rowScanner) (model.ReportSubscription, error) {
+	var s model.ReportSubscription
+	var report, schedule string
+	var nextRunAt, createdAt, updatedAt int64
+	var lastSentAt sql.NullInt64
+
+	err := row.Scan(&s.ID, &s.Name, &report, &s.Format, &s.Group, &schedule, &s.WebhookURL, &nextRunAt, &lastSentAt, &s.LastError, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ReportSubscription{}, err
+	}
+	if err != nil {
+		return model.ReportSubscription{}, fmt.Errorf("scan report subscription: %w", err)
+	}
+
+	s.Report = model.SubscriptionReport(report)
+	s.Schedule = model.SubscriptionSchedule(schedule)
+	s.NextRunAt = unixTime(nextRunAt)
+	if lastSentAt.Valid {
+		t := unixTime(lastSentAt.Int64)
+		s.LastSentAt = &t
+	}
+	s.CreatedAt = unixTime(createdAt)
+	s.UpdatedAt = unixTime(updatedAt)
+
+	return s, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"todo-service/internal/model"
+)
+
+// FieldsChangedSince returns the fields of the TODO id changed by the
+// versions after from, up to and including to, from the audit log, sorted
+// by name. ok is false if the log does not record every one of those
+// versions, such as for changes made before it recorded versions.
+func (r *SQLite) FieldsChangedSince(id, from, to int64) (fields []string, ok bool, err error) {
+	rows, err := r.db.Query(
+		`SELECT version, changes FROM audit_log WHERE todo_id = ? AND version > ? AND version <= ?`,
+		id, from, to,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("query changed fields: %w", err)
+	}
+	defer rows.Close()
+
+	versions := map[int64]bool{}
+	changed := map[string]bool{}
+	for rows.Next() {
+		var version int64
+		var data string
+		if err := rows.Scan(&version, &data); err != nil {
+			return nil, false, fmt.Errorf("scan changed fields: %w", err)
+		}
+		var changes map[string]model.FieldChange
+		if err := json.Unmarshal([]byte(data), &changes); err != nil {
+			return nil, false, fmt.Errorf("decode audit changes: %w", err)
+		}
+		versions[version] = true
+		for name := range changes {
+			changed[name] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("iterate changed fields: %w", err)
+	}
+
+	for name := range changed {
+		fields = append(fields, name)
+	}
+	slices.Sort(fields)
+	return fields, int64(len(versions)) == to-from, nil
+}
+
+// SyncedChange returns the TODO that the sync change ref, uploaded by actor,
+// was applied to, and whether it was applied. The change is recorded with
+// its audit entry, in the same transaction, so a retried upload finds every
+// change the first attempt made.
+func (r *SQLite) SyncedChange(actor, ref string) (todoID int64, ok bool, err error) {
+	err = r.db.QueryRow(
+		`SELECT todo_id FROM audit_log WHERE actor = ? AND sync_ref = ? ORDER BY id LIMIT 1`,
+		actor, ref,
+	).Scan(&todoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("query synced change: %w", err)
+	}
+	return todoID, true, nil
+}
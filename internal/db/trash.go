@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// TrashSort describes the fields trash listings can be sorted by.
+var TrashSort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"deleted_at": "deleted_at",
+		"deleted_by": "deleted_by",
+	},
+	Default: []query.Sort{{Field: "deleted_at", Desc: true}},
+}
+
+// SetTrashRetention sets how long deleted TODOs are kept in the trash before
+// PurgeTrash may purge them, which listings report as their purge time. A
+// retention of 0 keeps them until they are purged by hand. Call it before
+// using the repository.
+func (r *SQLite) SetTrashRetention(retention time.Duration) {
+	r.retention = retention
+}
+
+// trashTodo keeps a TODO being deleted within tx in the trash.
+func trashTodo(tx *sql.Tx, t model.Todo, info AuditInfo) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("encode trashed todo: %w", err)
+	}
+	_, err = tx.Exec(`INSERT OR REPLACE INTO trash (todo_id, todo, deleted_by) VALUES (?, ?, ?)`, t.ID, string(b), info.Actor)
+	if err != nil {
+		return fmt.Errorf("trash todo: %w", err)
+	}
+	return nil
+}
+
+// ListTrash returns a page of the trash.
+func (r *SQLite) ListTrash(opts query.Options) ([]model.TrashedTodo, error) {
+	q, args := opts.Apply(`SELECT todo_id AS id, todo, deleted_by, deleted_at FROM trash`, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query trash: %w", err)
+	}
+	defer rows.Close()
+
+	todos := []model.TrashedTodo{}
+	for rows.Next() {
+		var id, deletedAt int64
+		var state string
+		var t model.TrashedTodo
+		if err := rows.Scan(&id, &state, &t.DeletedBy, &deletedAt); err != nil {
+			return nil, fmt.Errorf("scan trashed todo: %w", err)
+		}
+		if err := json.Unmarshal([]byte(state), &t.Todo); err != nil {
+			return nil, fmt.Errorf("decode trashed todo: %w", err)
+		}
+		t.DeletedAt = time.Unix(deletedAt, 0).UTC()
+		if r.retention > 0 {
+			purgeAt := t.DeletedAt.Add(r.retention)
+			t.PurgeAt = &purgeAt
+		}
+		todos = append(todos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate trash: %w", err)
+	}
+	return todos, nil
+}
+
+// CountTrash returns the number of TODOs in the trash.
+func (r *SQLite) CountTrash() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM trash`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count trash: %w", err)
+	}
+	return count, nil
+}
+
+// RestoreTodo takes a TODO out of the trash, bringing it back under its ID as
+// it was when it was deleted, and records the restoration in the audit log
+// as a creation. A project that no longer exists is cleared, a category that
+// no longer exists is replaced with the default one, and values of custom
+// fields that no longer exist are dropped. ErrNotFound is returned if the
+// TODO is not in the trash.
+func (r *SQLite) RestoreTodo(id int64, info AuditInfo) (model.Todo, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var state string
+	err = tx.QueryRow(`SELECT todo FROM trash WHERE todo_id = ?`, id).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Todo{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Todo{}, fmt.Errorf("query trash: %w", err)
+	}
+	var t model.Todo
+	if err := json.Unmarshal([]byte(state), &t); err != nil {
+		return model.Todo{}, fmt.Errorf("decode trashed todo: %w", err)
+	}
+
+	if err := putTodo(tx, t, t.Version+1); err != nil {
+		return model.Todo{}, err
+	}
+	after, err := getTodo(tx, id)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if err := writeAudit(tx, model.AuditActionCreate, id, nil, &after, info); err != nil {
+		return model.Todo{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Todo{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// PurgeTodo removes a TODO from the trash for good. ErrNotFound is returned
+// if it is not in the trash.
+func (r *SQLite) PurgeTodo(id int64) error {
+	n, err := r.purge(`todo_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("purge todo: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// PurgeTrash removes every TODO deleted before cutoff from the trash for
+// good and returns how many it removed.
+func (r *SQLite) PurgeTrash(cutoff time.Time) (int, error) {
+	n, err := r.purge(`deleted_at < ?`, cutoff.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("purge trash: %w", err)
+	}
+	return int(n), nil
+}
+
+// purge purges the TODOs in the trash matching where, with args, in one
+// transaction, and returns how many it purged.
+func (r *SQLite) purge(where string, args ...any) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := purgeTodos(tx, where, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return n, nil
+}
+
+// CountTrashBefore returns the number of TODOs PurgeTrash would remove for
+// cutoff.
+func (r *SQLite) CountTrashBefore(cutoff time.Time) (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM trash WHERE deleted_at < ?`, cutoff.Unix()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count trash: %w", err)
+	}
+	return count, nil
+}
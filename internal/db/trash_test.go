@@ -0,0 +1,140 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+func TestTrash(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.SetTrashRetention(24 * time.Hour)
+	due := "2026-03-01"
+	todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: "trashed", Priority: model.PriorityHigh, DueDate: &due}, AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	kept := createTestTodo(t, repo, "kept")
+
+	if err := repo.DeleteTodo(todo.ID, 0, AuditInfo{Actor: "alice"}); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	if _, err := repo.GetTodo(todo.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo of a trashed TODO: got %v, want ErrNotFound", err)
+	}
+
+	trashed, err := repo.ListTrash(testOptions(t, TrashSort))
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(trashed) != 1 {
+		t.Fatalf("got %d TODOs in the trash, want 1", len(trashed))
+	}
+	got := trashed[0]
+	if got.Todo.ID != todo.ID || got.Todo.Title != todo.Title || got.DeletedBy != "alice" {
+		t.Errorf("trashed TODO = %+v, want %q deleted by alice", got, todo.Title)
+	}
+	if got.PurgeAt == nil || !got.PurgeAt.Equal(got.DeletedAt.Add(24*time.Hour)) {
+		t.Errorf("purge_at = %v, want a day after %v", got.PurgeAt, got.DeletedAt)
+	}
+	if n, err := repo.CountTrash(); err != nil || n != 1 {
+		t.Errorf("CountTrash = %d, %v; want 1", n, err)
+	}
+
+	// Restoring brings the TODO back under its ID, as it was.
+	restored, err := repo.RestoreTodo(todo.ID, AuditInfo{})
+	if err != nil {
+		t.Fatalf("RestoreTodo: %v", err)
+	}
+	if restored.ID != todo.ID || restored.Title != todo.Title || restored.Priority != todo.Priority || restored.DueDate == nil || *restored.DueDate != due {
+		t.Errorf("restored TODO = %+v, want %+v", restored, todo)
+	}
+	if n, err := repo.CountTrash(); err != nil || n != 0 {
+		t.Errorf("CountTrash after restore = %d, %v; want 0", n, err)
+	}
+	if _, err := repo.RestoreTodo(todo.ID, AuditInfo{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RestoreTodo of a TODO not in the trash: got %v, want ErrNotFound", err)
+	}
+
+	// Purging removes it for good.
+	if err := repo.DeleteTodo(todo.ID, 0, AuditInfo{}); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	if err := repo.PurgeTodo(todo.ID); err != nil {
+		t.Fatalf("PurgeTodo: %v", err)
+	}
+	if _, err := repo.RestoreTodo(todo.ID, AuditInfo{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RestoreTodo after purge: got %v, want ErrNotFound", err)
+	}
+	if err := repo.PurgeTodo(todo.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second PurgeTodo: got %v, want ErrNotFound", err)
+	}
+	if err := repo.PurgeTodo(kept.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PurgeTodo of a TODO not in the trash: got %v, want ErrNotFound", err)
+	}
+	if _, err := repo.GetTodo(kept.ID); err != nil {
+		t.Errorf("GetTodo(%d): %v", kept.ID, err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "alice", OperationID: "create"}
+	todo := newRelatedTodo(t, repo, info)
+
+	info.OperationID = "delete"
+	if err := repo.DeleteTodo(todo.ID, todo.Version, info); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+
+	// Nothing was deleted before the cutoff yet.
+	cutoff := time.Now().Add(-time.Hour)
+	if n, err := repo.CountTrashBefore(cutoff); err != nil || n != 0 {
+		t.Errorf("CountTrashBefore = %d, %v; want 0", n, err)
+	}
+	if n, err := repo.PurgeTrash(cutoff); err != nil || n != 0 {
+		t.Fatalf("PurgeTrash before the deletion = %d, %v; want 0", n, err)
+	}
+	if got, want := relatedRows(t, repo, todo.ID), (related{undo: 2, trash: 1, caldav: 1, linked: true}); got != want {
+		t.Fatalf("after purging nothing: %+v, want %+v", got, want)
+	}
+
+	// Once the retention has passed, the TODO is purged.
+	cutoff = time.Now().Add(time.Hour)
+	if n, err := repo.CountTrashBefore(cutoff); err != nil || n != 1 {
+		t.Errorf("CountTrashBefore = %d, %v; want 1", n, err)
+	}
+	if n, err := repo.PurgeTrash(cutoff); err != nil || n != 1 {
+		t.Fatalf("PurgeTrash after the deletion = %d, %v; want 1", n, err)
+	}
+	if got, want := relatedRows(t, repo, todo.ID), (related{caldav: 1, unlinked: true}); got != want {
+		t.Errorf("after purge: %+v, want %+v", got, want)
+	}
+	if n, err := repo.CountTrash(); err != nil || n != 0 {
+		t.Errorf("CountTrash after purge = %d, %v; want 0", n, err)
+	}
+}
+
+// TestGitHubLinkMigration checks that migrating down and up again keeps
+// GitHub issue links, clearing those of TODOs in the trash on the way down
+// as the foreign key requires.
+func TestGitHubLinkMigration(t *testing.T) {
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "alice"}
+	todo := newRelatedTodo(t, repo, info)
+	if err := repo.DeleteTodo(todo.ID, todo.Version, info); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+
+	if err := repo.MigrateDown(33); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if err := repo.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if got := relatedRows(t, repo, todo.ID); got.linked || !got.unlinked {
+		t.Errorf("after migrating down and up: %+v, want the link kept without its TODO", got)
+	}
+}
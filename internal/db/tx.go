@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"todo-service/internal/model"
+)
+
+// RepositoryTx makes repository changes within one transaction, for callers
+// that need several to succeed or fail together. Its methods behave like the
+// Repository methods of the same name. It is only valid within the function
+// passed to WithTx, which must not use the Repository itself meanwhile: the
+// transaction holds the repository's only connection.
+type RepositoryTx interface {
+	GetTodo(id int64) (model.Todo, error)
+	CreateTodo(req model.CreateTodoRequest, info AuditInfo) (model.Todo, error)
+	UpdateTodo(id, version int64, req model.UpdateTodoRequest, info AuditInfo) (model.Todo, error)
+	DeleteTodo(id, version int64, info AuditInfo) error
+	SetLocation(id int64, loc *model.Location, info AuditInfo) (model.Todo, error)
+	GetProject(id int64) (model.Project, error)
+	CreateProject(req model.CreateProjectRequest) (model.Project, error)
+}
+
+// sqliteTx is the RepositoryTx of a SQLite repository.
+type sqliteTx struct {
+	r  *SQLite
+	tx *sql.Tx
+}
+
+// WithTx runs fn in a transaction, committing the changes it makes through
+// tx if it returns nil and rolling them back if it returns an error or
+// panics. fn's error is returned as is. Changes made under one AuditInfo are
+// one operation in the audit log and are undone together.
+func (r *SQLite) WithTx(ctx context.Context, fn func(tx RepositoryTx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&sqliteTx{r: r, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetTodo retrieves a single TODO by ID, as changed so far in the
+// transaction.
+func (t *sqliteTx) GetTodo(id int64) (model.Todo, error) {
+	return getTodo(t.tx, id)
+}
+
+// CreateTodo inserts a new TODO and records it in the audit log.
+func (t *sqliteTx) CreateTodo(req model.CreateTodoRequest, info AuditInfo) (model.Todo, error) {
+	return t.r.createTodo(t.tx, req, info)
+}
+
+// UpdateTodo updates only the provided fields of a TODO and records the
+// changed fields in the audit log.
+func (t *sqliteTx) UpdateTodo(id, version int64, req model.UpdateTodoRequest, info AuditInfo) (model.Todo, error) {
+	return t.r.updateTodo(t.tx, id, version, req, info)
+}
+
+// DeleteTodo moves a TODO to the trash and records its final state in the
+// audit log.
+func (t *sqliteTx) DeleteTodo(id, version int64, info AuditInfo) error {
+	return deleteTodoVersion(t.tx, id, version, info)
+}
+
+// SetLocation sets a TODO's location, or removes it if loc is nil, and
+// records the change in the audit log.
+func (t *sqliteTx) SetLocation(id int64, loc *model.Location, info AuditInfo) (model.Todo, error) {
+	return setLocation(t.tx, id, loc, info)
+}
+
+// GetProject retrieves a single project by ID with its progress rollup.
+func (t *sqliteTx) GetProject(id int64) (model.Project, error) {
+	return getProject(t.tx, id)
+}
+
+// CreateProject inserts a new project and returns it.
+func (t *sqliteTx) CreateProject(req model.CreateProjectRequest) (model.Project, error) {
+	return createProject(t.tx, req)
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-service/internal/model"
+)
+
+func TestWithTx(t *testing.T) {
+	repo := newTestRepo(t)
+	info := AuditInfo{Actor: "alice", OperationID: NewOperationID()}
+
+	var todo model.Todo
+	err := repo.WithTx(context.Background(), func(tx RepositoryTx) error {
+		project, err := tx.CreateProject(model.CreateProjectRequest{Name: "committed"})
+		if err != nil {
+			return err
+		}
+		todo, err = tx.CreateTodo(model.CreateTodoRequest{Title: "committed", ProjectID: &project.ID}, info)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if got, err := repo.GetTodo(todo.ID); err != nil || got.ProjectID == nil {
+		t.Errorf("GetTodo after commit = %+v, %v; want the TODO in its project", got, err)
+	}
+
+	// An error rolls back every change made in the transaction.
+	failed := errors.New("failed")
+	err = repo.WithTx(context.Background(), func(tx RepositoryTx) error {
+		title := "rolled back"
+		if _, err := tx.UpdateTodo(todo.ID, 0, model.UpdateTodoRequest{Title: &title}, info); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTodo(model.CreateTodoRequest{Title: "rolled back"}, info); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTx: got %v, want %v", err, failed)
+	}
+	got, err := repo.GetTodo(todo.ID)
+	if err != nil {
+		t.Fatalf("GetTodo: %v", err)
+	}
+	if got.Title != todo.Title || got.Version != todo.Version {
+		t.Errorf("TODO after rollback = %q version %d, want %q version %d", got.Title, got.Version, todo.Title, todo.Version)
+	}
+	if n, err := repo.CountTodos(TodoFilter{}); err != nil || n != 1 {
+		t.Errorf("CountTodos after rollback = %d, %v; want 1", n, err)
+	}
+}
@@ -0,0 +1,319 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// ErrNothingToUndo is returned when an actor has made no operation recent
+// enough to undo.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// UndoConflictError is returned when a TODO an operation changed has been
+// changed again since, so that undoing the operation would lose the later
+// change.
+type UndoConflictError struct {
+	TodoID int64
+}
+
+func (e *UndoConflictError) Error() string {
+	return fmt.Sprintf("todo %d has changed since", e.TodoID)
+}
+
+// Defaults for SetUndo.
+const (
+	DefaultUndoDepth  = 20
+	DefaultUndoWindow = time.Hour
+)
+
+// SetUndo sets how many of each actor's most recent operations can be
+// undone, and for how long after they were made. A depth of 0 disables
+// undo. Call it before using the repository.
+func (r *SQLite) SetUndo(depth int, window time.Duration) {
+	r.undoDepth = depth
+	r.undoWindow = window
+}
+
+// writeUndo records what a todo was like before a mutation within tx, so
+// that the operation can be undone by the actor who made it.
+func writeUndo(tx *sql.Tx, action model.AuditAction, todoID int64, before, after *model.Todo, info AuditInfo) error {
+	var state sql.NullString
+	if before != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return fmt.Errorf("encode undo state: %w", err)
+		}
+		state = sql.NullString{String: string(b), Valid: true}
+	}
+	version := after
+	if version == nil {
+		version = before
+	}
+
+	_, err := tx.Exec(
+		`INSERT INTO undo_log (actor, operation_id, todo_id, action, before, version) VALUES (?, ?, ?, ?, ?, ?)`,
+		info.Actor, info.OperationID, todoID, string(action), state, version.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("insert undo entry: %w", err)
+	}
+	return nil
+}
+
+// undoStep is what undoing an operation does to one TODO: put it back as it
+// was before the operation, or delete it if before is nil, provided it is
+// still as the operation left it.
+type undoStep struct {
+	todoID  int64
+	before  *model.Todo
+	deleted bool  // the operation left the TODO deleted
+	version int64 // version the operation left, or deleted
+}
+
+// Undo reverses the most recent operation info.Actor made within the undo
+// window and depth that has not been undone yet, as of now: TODOs it created are
+// deleted, and TODOs it updated or deleted are put back as they were before
+// it. If any of them has changed since, an *UndoConflictError is returned
+// and nothing is undone. The reversal is recorded in the audit log under
+// info but not in the undo log, so undoing again reverses the operation
+// before.
+func (r *SQLite) Undo(info AuditInfo, now time.Time) (model.UndoResponse, error) {
+	if r.undoDepth <= 0 {
+		return model.UndoResponse{}, ErrNothingToUndo
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.UndoResponse{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Operations deeper than the undo depth stay out of reach even once
+	// those above them have been undone.
+	if _, err := pruneUndo(tx, &info.Actor, now.Add(-r.undoWindow), r.undoDepth); err != nil {
+		return model.UndoResponse{}, err
+	}
+
+	var operationID string
+	err = tx.QueryRow(
+		`SELECT operation_id FROM undo_log WHERE actor = ? AND created_at >= ? ORDER BY id DESC LIMIT 1`,
+		info.Actor, now.Add(-r.undoWindow).Unix(),
+	).Scan(&operationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.UndoResponse{}, ErrNothingToUndo
+	}
+	if err != nil {
+		return model.UndoResponse{}, fmt.Errorf("query undo log: %w", err)
+	}
+
+	steps, err := undoSteps(tx, info.Actor, operationID)
+	if err != nil {
+		return model.UndoResponse{}, err
+	}
+
+	resp := model.UndoResponse{OperationID: operationID, Restored: []model.Todo{}, Deleted: []int64{}}
+	for _, s := range steps {
+		current, err := getTodo(tx, s.todoID)
+		exists := err == nil
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return model.UndoResponse{}, err
+		}
+		if exists == s.deleted || exists && current.Version != s.version {
+			return model.UndoResponse{}, &UndoConflictError{TodoID: s.todoID}
+		}
+		var cur *model.Todo
+		if exists {
+			cur = &current
+		}
+
+		if s.before == nil {
+			if !exists {
+				continue // created and deleted by the same operation
+			}
+			if err := removeTodo(tx, current, info); err != nil {
+				return model.UndoResponse{}, err
+			}
+			if err := insertAudit(tx, model.AuditActionDelete, s.todoID, cur, nil, info); err != nil {
+				return model.UndoResponse{}, err
+			}
+			resp.Deleted = append(resp.Deleted, s.todoID)
+			continue
+		}
+
+		if err := putTodo(tx, *s.before, s.version+1); err != nil {
+			return model.UndoResponse{}, err
+		}
+		restored, err := getTodo(tx, s.todoID)
+		if err != nil {
+			return model.UndoResponse{}, err
+		}
+		action := model.AuditActionUpdate
+		if !exists {
+			action = model.AuditActionCreate
+		}
+		if err := insertAudit(tx, action, s.todoID, cur, &restored, info); err != nil {
+			return model.UndoResponse{}, err
+		}
+		// Earlier operations that left the TODO as it now is again can
+		// still be undone, though it has a new version.
+		_, err = tx.Exec(
+			`UPDATE undo_log SET version = ? WHERE todo_id = ? AND version = ? AND action != ? AND operation_id != ?`,
+			restored.Version, s.todoID, s.before.Version, string(model.AuditActionDelete), operationID,
+		)
+		if err != nil {
+			return model.UndoResponse{}, fmt.Errorf("update undo entries: %w", err)
+		}
+		resp.Restored = append(resp.Restored, restored)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM undo_log WHERE actor = ? AND operation_id = ?`, info.Actor, operationID); err != nil {
+		return model.UndoResponse{}, fmt.Errorf("delete undo entries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.UndoResponse{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// undoSteps loads the undo entries of an operation, combining those for the
+// same TODO: the first says what it was like before the operation, and the
+// last what the operation left. Steps are ordered by TODO ID.
+func undoSteps(q querier, actor, operationID string) ([]undoStep, error) {
+	rows, err := q.Query(
+		`SELECT todo_id, action, before, version FROM undo_log WHERE actor = ? AND operation_id = ? ORDER BY todo_id, id`,
+		actor, operationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query undo entries: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []undoStep
+	for rows.Next() {
+		var todoID, version int64
+		var action string
+		var state sql.NullString
+		if err := rows.Scan(&todoID, &action, &state, &version); err != nil {
+			return nil, fmt.Errorf("scan undo entry: %w", err)
+		}
+
+		if len(steps) == 0 || steps[len(steps)-1].todoID != todoID {
+			s := undoStep{todoID: todoID}
+			if state.Valid {
+				s.before = &model.Todo{}
+				if err := json.Unmarshal([]byte(state.String), s.before); err != nil {
+					return nil, fmt.Errorf("decode undo state: %w", err)
+				}
+			}
+			steps = append(steps, s)
+		}
+		last := &steps[len(steps)-1]
+		last.deleted = model.AuditAction(action) == model.AuditActionDelete
+		last.version = version
+	}
+
+	return steps, rows.Err()
+}
+
+// putTodo writes t back to its row within tx as version, recreating the row
+// and taking it out of the trash if it was deleted. A project that no longer
+// exists is cleared, a category that no longer exists is replaced with the
+// default one, and values of custom fields that no longer exist are dropped.
+func putTodo(tx *sql.Tx, t model.Todo, version int64) error {
+	if t.ProjectID != nil {
+		if err := checkProject(tx, *t.ProjectID); errors.Is(err, ErrProjectNotFound) {
+			t.ProjectID = nil
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := checkCategory(tx, t.Category); errors.Is(err, ErrCategoryNotFound) {
+		if t.Category, err = defaultCategory(tx); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	fields, err := customFieldsByName(tx)
+	if err != nil {
+		return err
+	}
+	values := maps.Clone(t.CustomFields)
+	maps.DeleteFunc(values, func(name string, _ any) bool {
+		_, ok := fields[name]
+		return !ok
+	})
+	customFields, err := encodeCustomFields(values)
+	if err != nil {
+		return err
+	}
+	latitude, longitude, place := locationColumns(t.Location)
+
+	_, err = tx.Exec(
+		`INSERT INTO todos (id, title, description, status, category, project_id, progress_percent, priority, due_date, estimate_minutes, archived, scheduled_for, triage, assignee, pinned, snoozed_until, custom_fields, latitude, longitude, place, color, icon, position, version, created_at, updated_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, unixepoch(), ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title, description = excluded.description, status = excluded.status,
+			category = excluded.category, project_id = excluded.project_id, progress_percent = excluded.progress_percent,
+			priority = excluded.priority, due_date = excluded.due_date,
+			estimate_minutes = excluded.estimate_minutes, archived = excluded.archived, scheduled_for = excluded.scheduled_for,
+			triage = excluded.triage, assignee = excluded.assignee, pinned = excluded.pinned,
+			snoozed_until = excluded.snoozed_until, custom_fields = excluded.custom_fields,
+			latitude = excluded.latitude, longitude = excluded.longitude, place = excluded.place,
+			color = excluded.color, icon = excluded.icon, position = excluded.position,
+			version = excluded.version, updated_at = excluded.updated_at, completed_at = excluded.completed_at`,
+		t.ID, t.Title, t.Description, string(t.Status), string(t.Category), t.ProjectID, t.ProgressPercent, string(t.Priority), t.DueDate, t.EstimateMinutes,
+		t.Archived, t.ScheduledFor, string(t.Triage), t.Assignee, t.Pinned, unixSeconds(t.SnoozedUntil), customFields,
+		latitude, longitude, place, t.Color, t.Icon, t.Position, version, t.CreatedAt.Unix(), unixSeconds(t.CompletedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("restore todo: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM trash WHERE todo_id = ?`, t.ID); err != nil {
+		return fmt.Errorf("untrash todo: %w", err)
+	}
+	return nil
+}
+
+// unixSeconds converts an optional time to a stored timestamp.
+func unixSeconds(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	sec := t.Unix()
+	return &sec
+}
+
+// PruneUndo forgets operations older than the undo window as of now, and
+// all but each actor's most recent ones up to the undo depth. It returns the
+// number of undo entries removed.
+func (r *SQLite) PruneUndo(now time.Time) (int64, error) {
+	return pruneUndo(r.db, nil, now.Add(-r.undoWindow), r.undoDepth)
+}
+
+// pruneUndo removes the undo entries made before since, and those of
+// operations deeper than depth in their actor's log, of actor only unless it
+// is nil. It returns the number of entries removed.
+func pruneUndo(q querier, actor *string, since time.Time, depth int) (int64, error) {
+	result, err := q.Exec(
+		`DELETE FROM undo_log WHERE (?1 IS NULL OR actor = ?1) AND (created_at < ?2 OR operation_id IN (
+			SELECT operation_id FROM (
+				SELECT operation_id, ROW_NUMBER() OVER (PARTITION BY actor ORDER BY MAX(id) DESC) AS n
+				FROM undo_log GROUP BY actor, operation_id
+			) WHERE n > ?3
+		))`,
+		actor, since.Unix(), depth,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune undo log: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// undoInfo returns the audit info of a new operation by alice.
+func undoInfo(t *testing.T) AuditInfo {
+	t.Helper()
+	return AuditInfo{Actor: "alice", OperationID: NewOperationID()}
+}
+
+func TestUndoDepth(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.SetUndo(2, time.Hour)
+
+	var todos []model.Todo
+	for _, title := range []string{"first", "second", "third"} {
+		todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: title}, undoInfo(t))
+		if err != nil {
+			t.Fatalf("CreateTodo: %v", err)
+		}
+		todos = append(todos, todo)
+	}
+
+	// Only the two most recent creations can be undone, newest first.
+	now := time.Now()
+	for _, want := range []model.Todo{todos[2], todos[1]} {
+		resp, err := repo.Undo(undoInfo(t), now)
+		if err != nil {
+			t.Fatalf("Undo: %v", err)
+		}
+		if len(resp.Deleted) != 1 || resp.Deleted[0] != want.ID {
+			t.Errorf("Undo deleted %v, want [%d]", resp.Deleted, want.ID)
+		}
+	}
+	if _, err := repo.Undo(undoInfo(t), now); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo past the depth: got %v, want ErrNothingToUndo", err)
+	}
+	if _, err := repo.GetTodo(todos[0].ID); err != nil {
+		t.Errorf("GetTodo of the TODO created past the depth: %v", err)
+	}
+}
+
+func TestUndoWindow(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.SetUndo(DefaultUndoDepth, time.Hour)
+	todo := createTestTodo(t, repo, "before")
+
+	title := "after"
+	if _, err := repo.UpdateTodo(todo.ID, 0, model.UpdateTodoRequest{Title: &title}, undoInfo(t)); err != nil {
+		t.Fatalf("UpdateTodo: %v", err)
+	}
+
+	if _, err := repo.Undo(undoInfo(t), time.Now().Add(2*time.Hour)); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Undo after the window: got %v, want ErrNothingToUndo", err)
+	}
+	got, err := repo.GetTodo(todo.ID)
+	if err != nil {
+		t.Fatalf("GetTodo: %v", err)
+	}
+	if got.Title != title {
+		t.Errorf("title = %q, want %q kept", got.Title, title)
+	}
+}
+
+func TestUndoDelete(t *testing.T) {
+	repo := newTestRepo(t)
+	due := "2026-03-01"
+	priority := model.PriorityHigh
+	todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: "deleted", Priority: priority, DueDate: &due}, AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	if err := repo.DeleteTodo(todo.ID, 0, undoInfo(t)); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+
+	resp, err := repo.Undo(undoInfo(t), time.Now())
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if len(resp.Restored) != 1 {
+		t.Fatalf("Undo restored %d TODOs, want 1", len(resp.Restored))
+	}
+	got, err := repo.GetTodo(todo.ID)
+	if err != nil {
+		t.Fatalf("GetTodo after undo: %v", err)
+	}
+	if got.Title != todo.Title || got.Priority != priority || got.DueDate == nil || *got.DueDate != due || got.Version != todo.Version+1 {
+		t.Errorf("restored TODO = %+v, want %+v back as version %d", got, todo, todo.Version+1)
+	}
+}
+
+func TestUndoDeleteAfterPurge(t *testing.T) {
+	repo := newTestRepo(t)
+	todo := createTestTodo(t, repo, "purged")
+	if err := repo.DeleteTodo(todo.ID, 0, undoInfo(t)); err != nil {
+		t.Fatalf("DeleteTodo: %v", err)
+	}
+	if err := repo.PurgeTodo(todo.ID); err != nil {
+		t.Fatalf("PurgeTodo: %v", err)
+	}
+
+	if _, err := repo.Undo(undoInfo(t), time.Now()); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo of a purged TODO's deletion: got %v, want ErrNothingToUndo", err)
+	}
+	if _, err := repo.GetTodo(todo.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTodo after undo: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Usage reports the row count and recent growth of every table and the size
+// of the database files and their pages. now anchors the growth windows.
+func (r *SQLite) Usage(now time.Time) (model.UsageResponse, error) {
+	usage := model.UsageResponse{Tables: []model.TableUsage{}, GeneratedAt: now.UTC()}
+
+	rows, err := r.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return usage, fmt.Errorf("query sqlite_master: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return usage, fmt.Errorf("scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return usage, fmt.Errorf("query sqlite_master: %w", err)
+	}
+
+	for _, table := range tables {
+		t, err := r.tableUsage(table, now)
+		if err != nil {
+			return usage, err
+		}
+		usage.Tables = append(usage.Tables, t)
+	}
+
+	if err := r.db.QueryRow(`PRAGMA page_size`).Scan(&usage.Pages.Size); err != nil {
+		return usage, fmt.Errorf("query page size: %w", err)
+	}
+	if err := r.db.QueryRow(`PRAGMA page_count`).Scan(&usage.Pages.Count); err != nil {
+		return usage, fmt.Errorf("query page count: %w", err)
+	}
+	if err := r.db.QueryRow(`PRAGMA freelist_count`).Scan(&usage.Pages.Free); err != nil {
+		return usage, fmt.Errorf("query freelist: %w", err)
+	}
+	usage.FreeBytes = usage.Pages.Size * usage.Pages.Free
+
+	if usage.DatabaseBytes, err = fileSize(r.path); err != nil {
+		return usage, err
+	}
+	if usage.WALBytes, err = fileSize(r.path + "-wal"); err != nil {
+		return usage, err
+	}
+
+	return usage, nil
+}
+
+// tableUsage counts the rows of table and, if it has a created_at column of
+// unix times, how many were created in the last 7 and 30 days.
+func (r *SQLite) tableUsage(table string, now time.Time) (model.TableUsage, error) {
+	t := model.TableUsage{Name: table}
+	quoted := `"` + table + `"`
+
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM ` + quoted).Scan(&t.Rows); err != nil {
+		return t, fmt.Errorf("count %s: %w", table, err)
+	}
+
+	timed, err := r.hasColumn(table, "created_at")
+	if err != nil {
+		return t, err
+	}
+	if !timed {
+		return t, nil
+	}
+
+	var g model.TableGrowth
+	err = r.db.QueryRow(
+		`SELECT COUNT(CASE WHEN created_at >= ? THEN 1 END), COUNT(*) FROM `+quoted+` WHERE created_at >= ?`,
+		now.AddDate(0, 0, -7).Unix(), now.AddDate(0, 0, -30).Unix(),
+	).Scan(&g.Last7Days, &g.Last30Days)
+	if err != nil {
+		return t, fmt.Errorf("count recent %s: %w", table, err)
+	}
+	g.PerDay = float64(g.Last30Days) / 30
+	t.Growth = &g
+
+	return t, nil
+}
+
+// fileSize returns the size of the file at path, or 0 if there is none.
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return info.Size(), nil
+}
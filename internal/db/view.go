@@ -0,0 +1,226 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// ErrViewExists is returned when a view name is already taken.
+var ErrViewExists = errors.New("view name already exists")
+
+// ViewSort describes the fields view lists can be sorted by.
+var ViewSort = query.Spec{
+	Columns: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+	Default: []query.Sort{{Field: "name"}},
+}
+
+// CreateView inserts a new view and returns it.
+func (r *SQLite) CreateView(req model.CreateViewRequest) (model.View, error) {
+	filter, err := json.Marshal(req.Filter)
+	if err != nil {
+		return model.View{}, fmt.Errorf("encode view filter: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.View{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkViewName(tx, req.Name, 0); err != nil {
+		return model.View{}, err
+	}
+
+	result, err := tx.Exec(`INSERT INTO views (name, filter) VALUES (?, ?)`, req.Name, string(filter))
+	if err != nil {
+		return model.View{}, fmt.Errorf("insert view: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.View{}, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	view, err := getView(tx, id)
+	if err != nil {
+		return model.View{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.View{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return view, nil
+}
+
+// GetView retrieves a single view by ID.
+func (r *SQLite) GetView(id int64) (model.View, error) {
+	return getView(r.db, id)
+}
+
+func getView(q querier, id int64) (model.View, error) {
+	row := q.QueryRow(viewSelect+` WHERE id = ?`, id)
+
+	v, err := scanView(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.View{}, ErrNotFound
+	}
+	return v, err
+}
+
+// ListViews retrieves views sorted and paginated by opts.
+func (r *SQLite) ListViews(opts query.Options) ([]model.View, error) {
+	q, args := opts.Apply(viewSelect, nil)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []model.View{}
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+
+	return views, rows.Err()
+}
+
+// CountViews returns the number of views.
+func (r *SQLite) CountViews() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM views`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count views: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateView updates only the provided fields of a view. A filter replaces
+// the view's filter as a whole.
+func (r *SQLite) UpdateView(id int64, req model.UpdateViewRequest) (model.View, error) {
+	var setClauses []string
+	var args []any
+
+	if req.Name != nil {
+		setClauses = append(setClauses, "name = ?")
+		args = append(args, *req.Name)
+	}
+	if req.Filter != nil {
+		filter, err := json.Marshal(*req.Filter)
+		if err != nil {
+			return model.View{}, fmt.Errorf("encode view filter: %w", err)
+		}
+		setClauses = append(setClauses, "filter = ?")
+		args = append(args, string(filter))
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetView(id)
+	}
+
+	setClauses = append(setClauses, "updated_at = unixepoch()")
+	args = append(args, id)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.View{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getView(tx, id); err != nil {
+		return model.View{}, err
+	}
+	if req.Name != nil {
+		if err := checkViewName(tx, *req.Name, id); err != nil {
+			return model.View{}, err
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE views SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return model.View{}, fmt.Errorf("update view: %w", err)
+	}
+
+	view, err := getView(tx, id)
+	if err != nil {
+		return model.View{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.View{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return view, nil
+}
+
+// DeleteView deletes a view. The TODOs it lists are not affected.
+func (r *SQLite) DeleteView(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM views WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete view: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkViewName returns ErrViewExists if a view other than id already uses
+// name.
+func checkViewName(q querier, name string, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM views WHERE name = ? AND id != ?)`, name, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check view name: %w", err)
+	}
+	if exists {
+		return ErrViewExists
+	}
+	return nil
+}
+
+const viewSelect = `SELECT id, name, filter, created_at, updated_at FROM views`
+
+// scanView scans a single row selected with viewSelect into a View.
+// sql.ErrNoRows is returned unwrapped.
+func scanView(row rowScanner) (model.View, error) {
+	var v model.View
+	var filter string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&v.ID, &v.Name, &filter, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.View{}, err
+	}
+	if err != nil {
+		return model.View{}, fmt.Errorf("scan view: %w", err)
+	}
+	if err := json.Unmarshal([]byte(filter), &v.Filter); err != nil {
+		return model.View{}, fmt.Errorf("decode filter of view %d: %w", v.ID, err)
+	}
+
+	v.CreatedAt = unixTime(createdAt)
+	v.UpdatedAt = unixTime(updatedAt)
+
+	return v, nil
+}
@@ -0,0 +1,133 @@
+// Package delivery renders subscribed reports and posts them to their
+// webhooks.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/report"
+)
+
+// timeout limits rendering and posting a single report.
+const timeout = 30 * time.Second
+
+// Deliverer delivers report subscriptions.
+type Deliverer struct {
+	repo   db.Repository
+	client *http.Client
+}
+
+// New creates a Deliverer that reads reports from repo.
+func New(repo db.Repository) *Deliverer {
+	return &Deliverer{repo: repo, client: &http.Client{Timeout: timeout}}
+}
+
+// Deliver renders sub's report as of now and posts it to sub's webhook,
+// with the document's media type and a filename in Content-Disposition.
+// Any response other than 2xx is an error. Callers record the outcome with
+// db.Repository.RecordReportDelivery.
+func (d *Deliverer) Deliver(ctx context.Context, sub model.ReportSubscription, now time.Time) error {
+	body, err := d.render(sub, now)
+	if err != nil {
+		return fmt.Errorf("render %s report: %w", sub.Report, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", report.ContentType(sub.Format))
+	req.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, report.Filename(string(sub.Report), sub.Format, now)))
+	req.Header.Set("User-Agent", "todo-service")
+	req.Header.Set("X-Report-Subscription", strconv.FormatInt(sub.ID, 10))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		// Errors from the client quote the URL, which may hold a secret.
+		return fmt.Errorf("post to %s: %w", redacted(sub.WebhookURL), unwrapURLError(err))
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded %s", redacted(sub.WebhookURL), resp.Status)
+	}
+	return nil
+}
+
+func (d *Deliverer) render(sub model.ReportSubscription, now time.Time) ([]byte, error) {
+	switch sub.Report {
+	case model.SubscriptionTodos:
+		archived := false
+		opts, err := query.Params{Sort: "position"}.Options(db.TodoSort)
+		if err != nil {
+			return nil, err
+		}
+		todos, err := d.repo.ListTodos(db.TodoFilter{Archived: &archived}, opts)
+		if err != nil {
+			return nil, err
+		}
+		names, err := d.repo.ProjectNames()
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		err = report.RenderTodos(&buf, report.GroupTodos(todos, sub.Group, names, now), sub.Format)
+		return buf.Bytes(), err
+
+	case model.SubscriptionBurndown:
+		// The two weeks ending the day before, the last complete day.
+		last := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+		var days []time.Time
+		for d := last.AddDate(0, 0, -13); !d.After(last); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+		end := last.AddDate(0, 0, 1).Add(-time.Second)
+		opts, err := query.Params{Sort: "id"}.Options(db.AuditSort)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := d.repo.ListAudit(db.AuditFilter{To: &end}, opts)
+		if err != nil {
+			return nil, err
+		}
+		points := report.Burndown(entries, days, nil)
+		return json.Marshal(model.BurndownReport{
+			From:   points[0].Date,
+			To:     points[len(points)-1].Date,
+			Points: points,
+		})
+	}
+	return nil, fmt.Errorf("unknown report %q", sub.Report)
+}
+
+// redacted returns rawURL without its credentials or query, which webhook
+// URLs often carry tokens in.
+func redacted(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "webhook"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
+
+func unwrapURLError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err
+	}
+	return err
+}
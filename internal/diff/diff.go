@@ -0,0 +1,196 @@
+// Package diff computes word-level differences between two texts.
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kind says whether an Op's text is kept, added, or removed.
+type Kind string
+
+const (
+	Equal  Kind = "equal"
+	Insert Kind = "insert"
+	Delete Kind = "delete"
+)
+
+// Op is a run of text that is unchanged, inserted, or deleted. Concatenating
+// the Equal and Delete ops of a diff yields the old text; concatenating the
+// Equal and Insert ops yields the new one.
+type Op struct {
+	Kind Kind   `json:"op" enum:"equal,insert,delete"`
+	Text string `json:"text"`
+}
+
+// MaxEdits bounds the work Words does. Texts that differ by more tokens than
+// this are reported as a single deletion followed by a single insertion.
+const MaxEdits = 500
+
+// Words returns the changes that turn a into b, splitting both into words and
+// the whitespace between them. Adjacent ops of the same kind are merged.
+func Words(a, b string) []Op {
+	x, y := tokenize(a), tokenize(b)
+
+	// Trim the common prefix and suffix; most edits touch a small part of
+	// the text and this keeps the search below cheap.
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+
+	var ops []Op
+	ops = appendOp(ops, Equal, x[:pre]...)
+	ops = append(ops, middle(x[pre:len(x)-suf], y[pre:len(y)-suf])...)
+	ops = appendOp(ops, Equal, x[len(x)-suf:]...)
+	return merge(ops)
+}
+
+// middle diffs x and y with Myers' algorithm, falling back to replacing x
+// with y when they differ by more than MaxEdits tokens.
+func middle(x, y []string) []Op {
+	n, m := len(x), len(y)
+	if n == 0 || m == 0 {
+		return replace(x, y)
+	}
+
+	limit := min(n+m, MaxEdits)
+	off := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		// Save the frontier reached with d-1 edits; backtrack only needs
+		// diagonals -d-1 through d+1.
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				i = v[off+k+1]
+			} else {
+				i = v[off+k-1] + 1
+			}
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[off+k] = i
+			if i >= n && j >= m {
+				return backtrack(x, y, trace, d)
+			}
+		}
+	}
+	return replace(x, y)
+}
+
+// backtrack walks the saved frontiers from the end of both texts back to the
+// start, emitting ops in reverse and then flipping them.
+func backtrack(x, y []string, trace [][]int, d int) []Op {
+	var rev []Op
+	i, j := len(x), len(y)
+	for ; d > 0; d-- {
+		v, base := trace[d], d+1
+		k := i - j
+		var prevK int
+		if k == -d || (k != d && v[base+k-1] < v[base+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevI := v[base+prevK]
+		prevJ := prevI - prevK
+		for i > prevI && j > prevJ {
+			i--
+			j--
+			rev = append(rev, Op{Kind: Equal, Text: x[i]})
+		}
+		if i == prevI {
+			j--
+			rev = append(rev, Op{Kind: Insert, Text: y[j]})
+		} else {
+			i--
+			rev = append(rev, Op{Kind: Delete, Text: x[i]})
+		}
+	}
+	for i > 0 {
+		i--
+		rev = append(rev, Op{Kind: Equal, Text: x[i]})
+	}
+
+	ops := make([]Op, len(rev))
+	for n, op := range rev {
+		ops[len(rev)-1-n] = op
+	}
+	return ops
+}
+
+func replace(x, y []string) []Op {
+	ops := appendOp(nil, Delete, x...)
+	return appendOp(ops, Insert, y...)
+}
+
+func appendOp(ops []Op, kind Kind, tokens ...string) []Op {
+	if len(tokens) == 0 {
+		return ops
+	}
+	return append(ops, Op{Kind: kind, Text: strings.Join(tokens, "")})
+}
+
+// merge joins adjacent ops of the same kind and drops empty ones. Between
+// two unchanged runs, all deletions are reported before all insertions.
+func merge(ops []Op) []Op {
+	out := []Op{}
+	var del, ins strings.Builder
+	flush := func() {
+		out = push(out, Op{Kind: Delete, Text: del.String()})
+		out = push(out, Op{Kind: Insert, Text: ins.String()})
+		del.Reset()
+		ins.Reset()
+	}
+	for _, op := range ops {
+		switch op.Kind {
+		case Delete:
+			del.WriteString(op.Text)
+		case Insert:
+			ins.WriteString(op.Text)
+		default:
+			flush()
+			out = push(out, op)
+		}
+	}
+	flush()
+	return out
+}
+
+func push(ops []Op, op Op) []Op {
+	if op.Text == "" {
+		return ops
+	}
+	if last := len(ops) - 1; last >= 0 && ops[last].Kind == op.Kind {
+		ops[last].Text += op.Text
+		return ops
+	}
+	return append(ops, op)
+}
+
+// tokenize splits s into alternating runs of whitespace and non-whitespace.
+func tokenize(s string) []string {
+	var tokens []string
+	start, space := 0, false
+	for i, r := range s {
+		if unicode.IsSpace(r) != space {
+			if i > start {
+				tokens = append(tokens, s[start:i])
+			}
+			start, space = i, !space
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
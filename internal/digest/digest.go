@@ -0,0 +1,243 @@
+// Package digest builds the email digest of overdue, soon due, and recently
+// completed TODOs and sends it over SMTP.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// timeout limits a whole SMTP conversation.
+const timeout = 30 * time.Second
+
+// ErrNotConfigured is returned by Send when no SMTP server or recipients
+// are configured.
+var ErrNotConfigured = errors.New("email digest is not configured")
+
+// Config configures the digest and the SMTP server it is sent through.
+// Until there are users, one digest goes to every address in To.
+type Config struct {
+	// Addr is the SMTP server's host:port. Port 465 is spoken over TLS;
+	// other ports are upgraded with STARTTLS when the server offers it.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+	Schedule model.SubscriptionSchedule
+	// DueSoonDays is how many days after today count as due soon.
+	DueSoonDays int
+}
+
+// Enabled reports whether digests can be sent.
+func (c Config) Enabled() bool {
+	return c.Addr != "" && len(c.To) > 0
+}
+
+// Mailer builds digests from a repository and sends them.
+type Mailer struct {
+	repo db.Repository
+	cfg  Config
+}
+
+// New creates a Mailer that reads TODOs from repo.
+func New(repo db.Repository, cfg Config) *Mailer {
+	return &Mailer{repo: repo, cfg: cfg}
+}
+
+// Config returns m's configuration.
+func (m *Mailer) Config() Config {
+	return m.cfg
+}
+
+// Build returns the digest as of now. Completed TODOs are those completed
+// since the last digest was sent, or within the last schedule period if
+// none was.
+func (m *Mailer) Build(now time.Time) (model.Digest, error) {
+	now = now.UTC().Truncate(time.Second)
+	since := now.AddDate(0, 0, -1)
+	if m.cfg.Schedule == model.ScheduleWeekly {
+		since = now.AddDate(0, 0, -7)
+	}
+	last, err := m.repo.LastDigestSentAt()
+	if err != nil {
+		return model.Digest{}, err
+	}
+	if last != nil {
+		since = last.UTC()
+	}
+
+	archived := false
+	open := []model.Status{model.StatusPending, model.StatusInProgress}
+	today := now.Format(model.DateLayout)
+	yesterday := now.AddDate(0, 0, -1).Format(model.DateLayout)
+	soon := now.AddDate(0, 0, m.cfg.DueSoonDays).Format(model.DateLayout)
+
+	d := model.Digest{GeneratedAt: now, Since: since}
+	if d.Overdue, err = m.list(db.TodoFilter{Archived: &archived, Statuses: open, DueTo: &yesterday}, "due_date,id"); err != nil {
+		return model.Digest{}, err
+	}
+	if d.DueSoon, err = m.list(db.TodoFilter{Archived: &archived, Statuses: open, DueFrom: &today, DueTo: &soon}, "due_date,id"); err != nil {
+		return model.Digest{}, err
+	}
+	if d.Completed, err = m.list(db.TodoFilter{CompletedFrom: &since, CompletedTo: &now}, "completed_at,id"); err != nil {
+		return model.Digest{}, err
+	}
+	d.Subject = fmt.Sprintf("TODO digest: %d overdue, %d due soon, %d completed", len(d.Overdue), len(d.DueSoon), len(d.Completed))
+	d.Text = text(d)
+	return d, nil
+}
+
+func (m *Mailer) list(filter db.TodoFilter, sort string) ([]model.Todo, error) {
+	opts, err := query.Params{Sort: sort}.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+	return m.repo.ListTodos(filter, opts)
+}
+
+// text renders d as the plain-text body of the email.
+func text(d model.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TODO digest for %s\n", d.GeneratedAt.Format("Monday, 2 January 2006"))
+
+	section := func(heading string, todos []model.Todo, day func(model.Todo) string) {
+		fmt.Fprintf(&b, "\n%s (%d)\n", heading, len(todos))
+		if len(todos) == 0 {
+			b.WriteString("  Nothing.\n")
+		}
+		for _, t := range todos {
+			fmt.Fprintf(&b, "  %s  %s (#%d)\n", day(t), t.Title, t.ID)
+		}
+	}
+	due := func(t model.Todo) string { return *t.DueDate }
+	section("OVERDUE", d.Overdue, due)
+	section("DUE SOON", d.DueSoon, due)
+	section("COMPLETED SINCE "+d.Since.Format("2006-01-02 15:04 MST"), d.Completed, func(t model.Todo) string {
+		return t.CompletedAt.UTC().Format(model.DateLayout)
+	})
+	return b.String()
+}
+
+// Send emails d to the configured recipients and records the send, so the
+// next digest lists TODOs completed after it.
+func (m *Mailer) Send(ctx context.Context, d model.Digest) error {
+	if !m.cfg.Enabled() {
+		return ErrNotConfigured
+	}
+	msg, err := m.message(d)
+	if err != nil {
+		return err
+	}
+	if err := m.deliver(ctx, msg); err != nil {
+		return err
+	}
+	return m.repo.RecordDigestSend(model.DigestSend{
+		SentAt:    d.GeneratedAt,
+		To:        m.cfg.To,
+		Overdue:   len(d.Overdue),
+		DueSoon:   len(d.DueSoon),
+		Completed: len(d.Completed),
+	})
+}
+
+// message returns d as an RFC 5322 message with a quoted-printable body.
+func (m *Mailer) message(d model.Digest) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.from())
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", d.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", d.GeneratedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(d.Text)); err != nil {
+		return nil, fmt.Errorf("encode digest: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encode digest: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+func (m *Mailer) from() string {
+	if m.cfg.From != "" {
+		return m.cfg.From
+	}
+	return m.cfg.To[0]
+}
+
+// deliver sends msg over SMTP, authenticating if a username is configured.
+func (m *Mailer) deliver(ctx context.Context, msg []byte) error {
+	host, port, err := net.SplitHostPort(m.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tlsCfg := &tls.Config{ServerName: host}
+	var conn net.Conn
+	if port == "465" {
+		conn, err = (&tls.Dialer{Config: tlsCfg}).DialContext(ctx, "tcp", m.cfg.Addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", m.cfg.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", m.cfg.Addr, err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("greet %s: %w", m.cfg.Addr, err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(tlsCfg); err != nil {
+			return fmt.Errorf("start TLS: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)); err != nil {
+			return fmt.Errorf("authenticate: %w", err)
+		}
+	}
+	if err := c.Mail(m.from()); err != nil {
+		return fmt.Errorf("set sender: %w", err)
+	}
+	for _, to := range m.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("add recipient %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("start message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,1821 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: todo/v1/todo.proto
+
+package todov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_PENDING     Status = 1
+	Status_STATUS_IN_PROGRESS Status = 2
+	Status_STATUS_DONE        Status = 3
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_PENDING",
+		2: "STATUS_IN_PROGRESS",
+		3: "STATUS_DONE",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_PENDING":     1,
+		"STATUS_IN_PROGRESS": 2,
+		"STATUS_DONE":        3,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+// Category covers only the categories a new database starts with; use the
+// category_name fields, which work with every category, instead.
+type Category int32
+
+const (
+	Category_CATEGORY_UNSPECIFIED Category = 0
+	Category_CATEGORY_PERSONAL    Category = 1
+	Category_CATEGORY_WORK        Category = 2
+	Category_CATEGORY_OTHER       Category = 3
+)
+
+// Enum value maps for Category.
+var (
+	Category_name = map[int32]string{
+		0: "CATEGORY_UNSPECIFIED",
+		1: "CATEGORY_PERSONAL",
+		2: "CATEGORY_WORK",
+		3: "CATEGORY_OTHER",
+	}
+	Category_value = map[string]int32{
+		"CATEGORY_UNSPECIFIED": 0,
+		"CATEGORY_PERSONAL":    1,
+		"CATEGORY_WORK":        2,
+		"CATEGORY_OTHER":       3,
+	}
+)
+
+func (x Category) Enum() *Category {
+	p := new(Category)
+	*p = x
+	return p
+}
+
+func (x Category) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Category) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (Category) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[1]
+}
+
+func (x Category) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Category.Descriptor instead.
+func (Category) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_NONE        Priority = 1
+	Priority_PRIORITY_LOW         Priority = 2
+	Priority_PRIORITY_MEDIUM      Priority = 3
+	Priority_PRIORITY_HIGH        Priority = 4
+	Priority_PRIORITY_URGENT      Priority = 5
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_NONE",
+		2: "PRIORITY_LOW",
+		3: "PRIORITY_MEDIUM",
+		4: "PRIORITY_HIGH",
+		5: "PRIORITY_URGENT",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_NONE":        1,
+		"PRIORITY_LOW":         2,
+		"PRIORITY_MEDIUM":      3,
+		"PRIORITY_HIGH":        4,
+		"PRIORITY_URGENT":      5,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[2]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
+type Triage int32
+
+const (
+	Triage_TRIAGE_UNSPECIFIED Triage = 0
+	// Captured to the inbox and waiting to be triaged.
+	Triage_TRIAGE_PENDING Triage = 1
+	Triage_TRIAGE_DONE    Triage = 2
+)
+
+// Enum value maps for Triage.
+var (
+	Triage_name = map[int32]string{
+		0: "TRIAGE_UNSPECIFIED",
+		1: "TRIAGE_PENDING",
+		2: "TRIAGE_DONE",
+	}
+	Triage_value = map[string]int32{
+		"TRIAGE_UNSPECIFIED": 0,
+		"TRIAGE_PENDING":     1,
+		"TRIAGE_DONE":        2,
+	}
+)
+
+func (x Triage) Enum() *Triage {
+	p := new(Triage)
+	*p = x
+	return p
+}
+
+func (x Triage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Triage) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (Triage) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[3]
+}
+
+func (x Triage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Triage.Descriptor instead.
+func (Triage) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_ACTION_CREATE      Action = 1
+	Action_ACTION_UPDATE      Action = 2
+	Action_ACTION_DELETE      Action = 3
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_CREATE",
+		2: "ACTION_UPDATE",
+		3: "ACTION_DELETE",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_CREATE":      1,
+		"ACTION_UPDATE":      2,
+		"ACTION_DELETE":      3,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[4].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[4]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+type Todo struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status      Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Set only for the built-in categories; see category_name.
+	//
+	// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+	Category        Category `protobuf:"varint,5,opt,name=category,proto3,enum=todo.v1.Category" json:"category,omitempty"`
+	ProjectId       *int64   `protobuf:"varint,6,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
+	ProgressPercent int32    `protobuf:"varint,7,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
+	Archived        bool     `protobuf:"varint,8,opt,name=archived,proto3" json:"archived,omitempty"`
+	// Incremented on every update; pass it back to UpdateTodo and DeleteTodo.
+	Version   int64                  `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Priority  Priority               `protobuf:"varint,12,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Day the TODO is due, formatted YYYY-MM-DD.
+	DueDate *string `protobuf:"bytes,13,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	// Unset unless the TODO is done.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// Day the TODO is planned for, as YYYY-MM-DD.
+	ScheduledFor *string `protobuf:"bytes,15,opt,name=scheduled_for,json=scheduledFor,proto3,oneof" json:"scheduled_for,omitempty"`
+	Triage       Triage  `protobuf:"varint,16,opt,name=triage,proto3,enum=todo.v1.Triage" json:"triage,omitempty"`
+	// Estimated effort in minutes; 0 if not estimated.
+	EstimateMinutes int32  `protobuf:"varint,17,opt,name=estimate_minutes,json=estimateMinutes,proto3" json:"estimate_minutes,omitempty"`
+	CategoryName    string `protobuf:"bytes,18,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	// Manual order; lower positions come first.
+	Position int64 `protobuf:"varint,19,opt,name=position,proto3" json:"position,omitempty"`
+	// Who the TODO is assigned to, named as the audit log names actors;
+	// empty if no one.
+	Assignee string `protobuf:"bytes,20,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	// Pinned TODOs are listed before all others, whatever the sort.
+	Pinned bool `protobuf:"varint,21,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	// Time the snoozed TODO wakes up, or unset if it is not snoozed.
+	SnoozedUntil *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=snoozed_until,json=snoozedUntil,proto3" json:"snoozed_until,omitempty"`
+	// Display color as #rrggbb; empty if none.
+	Color string `protobuf:"bytes,23,opt,name=color,proto3" json:"color,omitempty"`
+	// Name of the icon to show the TODO with; empty if none.
+	Icon string `protobuf:"bytes,24,opt,name=icon,proto3" json:"icon,omitempty"`
+	// Values of custom fields, keyed by field name: strings for text, date
+	// (YYYY-MM-DD), and enum fields, and numbers for number fields.
+	CustomFields map[string]*structpb.Value `protobuf:"bytes,25,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Where the TODO is to be done; unset if nowhere in particular.
+	Location      *Location `protobuf:"bytes,26,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Todo) Reset() {
+	*x = Todo{}
+	mi := &file_todo_v1_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Todo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Todo) ProtoMessage() {}
+
+func (x *Todo) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
+func (*Todo) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Todo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Todo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Todo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Todo) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+func (x *Todo) GetCategory() Category {
+	if x != nil {
+		return x.Category
+	}
+	return Category_CATEGORY_UNSPECIFIED
+}
+
+func (x *Todo) GetProjectId() int64 {
+	if x != nil && x.ProjectId != nil {
+		return *x.ProjectId
+	}
+	return 0
+}
+
+func (x *Todo) GetProgressPercent() int32 {
+	if x != nil {
+		return x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *Todo) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *Todo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Todo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Todo) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *Todo) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Todo) GetScheduledFor() string {
+	if x != nil && x.ScheduledFor != nil {
+		return *x.ScheduledFor
+	}
+	return ""
+}
+
+func (x *Todo) GetTriage() Triage {
+	if x != nil {
+		return x.Triage
+	}
+	return Triage_TRIAGE_UNSPECIFIED
+}
+
+func (x *Todo) GetEstimateMinutes() int32 {
+	if x != nil {
+		return x.EstimateMinutes
+	}
+	return 0
+}
+
+func (x *Todo) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *Todo) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Todo) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Todo) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Todo) GetSnoozedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnoozedUntil
+	}
+	return nil
+}
+
+func (x *Todo) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Todo) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Todo) GetCustomFields() map[string]*structpb.Value {
+	if x != nil {
+		return x.CustomFields
+	}
+	return nil
+}
+
+func (x *Todo) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+// Location is where a TODO is to be done, such as the shop for an errand.
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Degrees north of the equator, from -90 to 90.
+	Latitude float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	// Degrees east of the prime meridian, from -180 to 180.
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Name of the place; empty if unnamed.
+	Place         string `protobuf:"bytes,3,opt,name=place,proto3" json:"place,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_todo_v1_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Location) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+type CreateTodoRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Defaults to STATUS_PENDING.
+	Status Status `protobuf:"varint,3,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+	Category        Category `protobuf:"varint,4,opt,name=category,proto3,enum=todo.v1.Category" json:"category,omitempty"`
+	ProjectId       *int64   `protobuf:"varint,5,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
+	ProgressPercent *int32   `protobuf:"varint,6,opt,name=progress_percent,json=progressPercent,proto3,oneof" json:"progress_percent,omitempty"`
+	// Defaults to PRIORITY_NONE.
+	Priority Priority `protobuf:"varint,7,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Formatted YYYY-MM-DD.
+	DueDate         *string `protobuf:"bytes,8,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	EstimateMinutes *int32  `protobuf:"varint,9,opt,name=estimate_minutes,json=estimateMinutes,proto3,oneof" json:"estimate_minutes,omitempty"`
+	// Takes precedence over category. Defaults to the first category by sort
+	// order.
+	CategoryName string `protobuf:"bytes,10,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	// Values of custom fields, keyed by field name, as in Todo.
+	CustomFields  map[string]*structpb.Value `protobuf:"bytes,11,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Location      *Location                  `protobuf:"bytes,12,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTodoRequest) Reset() {
+	*x = CreateTodoRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoRequest) ProtoMessage() {}
+
+func (x *CreateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTodoRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+func (x *CreateTodoRequest) GetCategory() Category {
+	if x != nil {
+		return x.Category
+	}
+	return Category_CATEGORY_UNSPECIFIED
+}
+
+func (x *CreateTodoRequest) GetProjectId() int64 {
+	if x != nil && x.ProjectId != nil {
+		return *x.ProjectId
+	}
+	return 0
+}
+
+func (x *CreateTodoRequest) GetProgressPercent() int32 {
+	if x != nil && x.ProgressPercent != nil {
+		return *x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *CreateTodoRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateTodoRequest) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetEstimateMinutes() int32 {
+	if x != nil && x.EstimateMinutes != nil {
+		return *x.EstimateMinutes
+	}
+	return 0
+}
+
+func (x *CreateTodoRequest) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *CreateTodoRequest) GetCustomFields() map[string]*structpb.Value {
+	if x != nil {
+		return x.CustomFields
+	}
+	return nil
+}
+
+func (x *CreateTodoRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type GetTodoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoRequest) Reset() {
+	*x = GetTodoRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoRequest) ProtoMessage() {}
+
+func (x *GetTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTodoRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListTodosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of TODOs to return; 0 for all, if at most 5000 match.
+	Limit  int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort   string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	Status Status `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+	Category  Category `protobuf:"varint,5,opt,name=category,proto3,enum=todo.v1.Category" json:"category,omitempty"`
+	ProjectId int64    `protobuf:"varint,6,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// List archived TODOs instead of active ones.
+	Archived bool     `protobuf:"varint,7,opt,name=archived,proto3" json:"archived,omitempty"`
+	Priority Priority `protobuf:"varint,8,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Only TODOs due on or after, and on or before, these days, formatted
+	// YYYY-MM-DD.
+	DueFrom string `protobuf:"bytes,9,opt,name=due_from,json=dueFrom,proto3" json:"due_from,omitempty"`
+	DueTo   string `protobuf:"bytes,10,opt,name=due_to,json=dueTo,proto3" json:"due_to,omitempty"`
+	// Only TODOs completed at or after this time.
+	CompletedFrom *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=completed_from,json=completedFrom,proto3" json:"completed_from,omitempty"`
+	// Only TODOs completed at or before this time.
+	CompletedTo *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=completed_to,json=completedTo,proto3" json:"completed_to,omitempty"`
+	// Takes precedence over category.
+	CategoryName string `protobuf:"bytes,13,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	// Only TODOs located within a radius of a point.
+	Near          *Near `protobuf:"bytes,14,opt,name=near,proto3" json:"near,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodosRequest) Reset() {
+	*x = ListTodosRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodosRequest) ProtoMessage() {}
+
+func (x *ListTodosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodosRequest.ProtoReflect.Descriptor instead.
+func (*ListTodosRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTodosRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTodosRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTodosRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+func (x *ListTodosRequest) GetCategory() Category {
+	if x != nil {
+		return x.Category
+	}
+	return Category_CATEGORY_UNSPECIFIED
+}
+
+func (x *ListTodosRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *ListTodosRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ListTodosRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *ListTodosRequest) GetDueFrom() string {
+	if x != nil {
+		return x.DueFrom
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetDueTo() string {
+	if x != nil {
+		return x.DueTo
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetCompletedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedFrom
+	}
+	return nil
+}
+
+func (x *ListTodosRequest) GetCompletedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedTo
+	}
+	return nil
+}
+
+func (x *ListTodosRequest) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *ListTodosRequest) GetNear() *Near {
+	if x != nil {
+		return x.Near
+	}
+	return nil
+}
+
+// Near is a circle on the Earth's surface, measured along the surface.
+type Near struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Latitude  float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Radius in kilometres, up to 20038; 0 means 5.
+	RadiusKm      float64 `protobuf:"fixed64,3,opt,name=radius_km,json=radiusKm,proto3" json:"radius_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Near) Reset() {
+	*x = Near{}
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Near) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Near) ProtoMessage() {}
+
+func (x *Near) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Near.ProtoReflect.Descriptor instead.
+func (*Near) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Near) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Near) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Near) GetRadiusKm() float64 {
+	if x != nil {
+		return x.RadiusKm
+	}
+	return 0
+}
+
+type ListTodosResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodosResponse) Reset() {
+	*x = ListTodosResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodosResponse) ProtoMessage() {}
+
+func (x *ListTodosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodosResponse.ProtoReflect.Descriptor instead.
+func (*ListTodosResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTodosResponse) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+func (x *ListTodosResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ListTodosResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// UpdateTodoRequest changes only the fields that are set.
+type UpdateTodoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Version being updated, as returned by GetTodo; the update fails with
+	// ABORTED if the TODO has changed since. It is required, like the HTTP
+	// API's If-Match header: without it, or unconditional, the update fails
+	// with FAILED_PRECONDITION. Status changes the server does not allow fail
+	// with FAILED_PRECONDITION too.
+	Version     int64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Title       *string `protobuf:"bytes,3,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Description *string `protobuf:"bytes,4,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Status      Status  `protobuf:"varint,5,opt,name=status,proto3,enum=todo.v1.Status" json:"status,omitempty"`
+	// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+	Category Category `protobuf:"varint,6,opt,name=category,proto3,enum=todo.v1.Category" json:"category,omitempty"`
+	// 0 removes the TODO from its project.
+	ProjectId       *int64   `protobuf:"varint,7,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
+	ProgressPercent *int32   `protobuf:"varint,8,opt,name=progress_percent,json=progressPercent,proto3,oneof" json:"progress_percent,omitempty"`
+	Priority        Priority `protobuf:"varint,9,opt,name=priority,proto3,enum=todo.v1.Priority" json:"priority,omitempty"`
+	// Formatted YYYY-MM-DD; an empty string clears the due date.
+	DueDate *string `protobuf:"bytes,10,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	// 0 removes the estimate.
+	EstimateMinutes *int32 `protobuf:"varint,11,opt,name=estimate_minutes,json=estimateMinutes,proto3,oneof" json:"estimate_minutes,omitempty"`
+	// Takes precedence over category.
+	CategoryName *string `protobuf:"bytes,12,opt,name=category_name,json=categoryName,proto3,oneof" json:"category_name,omitempty"`
+	// Custom field values to set, keyed by field name; a null value removes
+	// one. Fields not named keep their values.
+	CustomFields map[string]*structpb.Value `protobuf:"bytes,13,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Replaces the TODO's location. The location change is recorded in the
+	// audit log as its own entry of the same operation.
+	Location *Location `protobuf:"bytes,14,opt,name=location,proto3" json:"location,omitempty"`
+	// Removes the TODO's location; cannot be combined with location.
+	ClearLocation bool `protobuf:"varint,15,opt,name=clear_location,json=clearLocation,proto3" json:"clear_location,omitempty"`
+	// Updates whatever the TODO's version, like If-Match: * over HTTP;
+	// version must then be 0.
+	Unconditional bool `protobuf:"varint,16,opt,name=unconditional,proto3" json:"unconditional,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoRequest) Reset() {
+	*x = UpdateTodoRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoRequest) ProtoMessage() {}
+
+func (x *UpdateTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateTodoRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in todo/v1/todo.proto.
+func (x *UpdateTodoRequest) GetCategory() Category {
+	if x != nil {
+		return x.Category
+	}
+	return Category_CATEGORY_UNSPECIFIED
+}
+
+func (x *UpdateTodoRequest) GetProjectId() int64 {
+	if x != nil && x.ProjectId != nil {
+		return *x.ProjectId
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetProgressPercent() int32 {
+	if x != nil && x.ProgressPercent != nil {
+		return *x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *UpdateTodoRequest) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetEstimateMinutes() int32 {
+	if x != nil && x.EstimateMinutes != nil {
+		return *x.EstimateMinutes
+	}
+	return 0
+}
+
+func (x *UpdateTodoRequest) GetCategoryName() string {
+	if x != nil && x.CategoryName != nil {
+		return *x.CategoryName
+	}
+	return ""
+}
+
+func (x *UpdateTodoRequest) GetCustomFields() map[string]*structpb.Value {
+	if x != nil {
+		return x.CustomFields
+	}
+	return nil
+}
+
+func (x *UpdateTodoRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *UpdateTodoRequest) GetClearLocation() bool {
+	if x != nil {
+		return x.ClearLocation
+	}
+	return false
+}
+
+func (x *UpdateTodoRequest) GetUnconditional() bool {
+	if x != nil {
+		return x.Unconditional
+	}
+	return false
+}
+
+type DeleteTodoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Version being deleted, as returned by GetTodo; the delete fails with
+	// ABORTED if the TODO has changed since. It is required: without it, or
+	// unconditional, the delete fails with FAILED_PRECONDITION.
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Deletes whatever the TODO's version, like If-Match: * over HTTP;
+	// version must then be 0.
+	Unconditional bool `protobuf:"varint,3,opt,name=unconditional,proto3" json:"unconditional,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoRequest) Reset() {
+	*x = DeleteTodoRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoRequest) ProtoMessage() {}
+
+func (x *DeleteTodoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteTodoRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteTodoRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DeleteTodoRequest) GetUnconditional() bool {
+	if x != nil {
+		return x.Unconditional
+	}
+	return false
+}
+
+type DeleteTodoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoResponse) Reset() {
+	*x = DeleteTodoResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoResponse) ProtoMessage() {}
+
+func (x *DeleteTodoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTodoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after this audit entry ID; 0 streams only changes made after the
+	// call starts.
+	AfterId int64 `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// Only stream changes to this TODO; 0 streams every TODO.
+	TodoId        int64 `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetTodoId() int64 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+// TodoEvent is a single audit log entry.
+type TodoEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Audit entry ID; pass the last one seen as after_id to resume.
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TodoId        int64                  `protobuf:"varint,2,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	Action        Action                 `protobuf:"varint,3,opt,name=action,proto3,enum=todo.v1.Action" json:"action,omitempty"`
+	ChangedFields []string               `protobuf:"bytes,4,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	Actor         string                 `protobuf:"bytes,5,opt,name=actor,proto3" json:"actor,omitempty"`
+	RequestId     string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	OperationId   string                 `protobuf:"bytes,7,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoEvent) Reset() {
+	*x = TodoEvent{}
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoEvent) ProtoMessage() {}
+
+func (x *TodoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoEvent.ProtoReflect.Descriptor instead.
+func (*TodoEvent) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TodoEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TodoEvent) GetTodoId() int64 {
+	if x != nil {
+		return x.TodoId
+	}
+	return 0
+}
+
+func (x *TodoEvent) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_UNSPECIFIED
+}
+
+func (x *TodoEvent) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+func (x *TodoEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *TodoEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *TodoEvent) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *TodoEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_todo_v1_todo_proto protoreflect.FileDescriptor
+
+const file_todo_v1_todo_proto_rawDesc = "" +
+	"\n" +
+	"\x12todo/v1/todo.proto\x12\atodo.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\t\n" +
+	"\x04Todo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12'\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x121\n" +
+	"\bcategory\x18\x05 \x01(\x0e2\x11.todo.v1.CategoryB\x02\x18\x01R\bcategory\x12\"\n" +
+	"\n" +
+	"project_id\x18\x06 \x01(\x03H\x00R\tprojectId\x88\x01\x01\x12)\n" +
+	"\x10progress_percent\x18\a \x01(\x05R\x0fprogressPercent\x12\x1a\n" +
+	"\barchived\x18\b \x01(\bR\barchived\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12-\n" +
+	"\bpriority\x18\f \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x1e\n" +
+	"\bdue_date\x18\r \x01(\tH\x01R\adueDate\x88\x01\x01\x12=\n" +
+	"\fcompleted_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12(\n" +
+	"\rscheduled_for\x18\x0f \x01(\tH\x02R\fscheduledFor\x88\x01\x01\x12'\n" +
+	"\x06triage\x18\x10 \x01(\x0e2\x0f.todo.v1.TriageR\x06triage\x12)\n" +
+	"\x10estimate_minutes\x18\x11 \x01(\x05R\x0festimateMinutes\x12#\n" +
+	"\rcategory_name\x18\x12 \x01(\tR\fcategoryName\x12\x1a\n" +
+	"\bposition\x18\x13 \x01(\x03R\bposition\x12\x1a\n" +
+	"\bassignee\x18\x14 \x01(\tR\bassignee\x12\x16\n" +
+	"\x06pinned\x18\x15 \x01(\bR\x06pinned\x12?\n" +
+	"\rsnoozed_until\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\fsnoozedUntil\x12\x14\n" +
+	"\x05color\x18\x17 \x01(\tR\x05color\x12\x12\n" +
+	"\x04icon\x18\x18 \x01(\tR\x04icon\x12D\n" +
+	"\rcustom_fields\x18\x19 \x03(\v2\x1f.todo.v1.Todo.CustomFieldsEntryR\fcustomFields\x12-\n" +
+	"\blocation\x18\x1a \x01(\v2\x11.todo.v1.LocationR\blocation\x1aW\n" +
+	"\x11CustomFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01B\r\n" +
+	"\v_project_idB\v\n" +
+	"\t_due_dateB\x10\n" +
+	"\x0e_scheduled_for\"Z\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x14\n" +
+	"\x05place\x18\x03 \x01(\tR\x05place\"\xc0\x05\n" +
+	"\x11CreateTodoRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12'\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x121\n" +
+	"\bcategory\x18\x04 \x01(\x0e2\x11.todo.v1.CategoryB\x02\x18\x01R\bcategory\x12\"\n" +
+	"\n" +
+	"project_id\x18\x05 \x01(\x03H\x00R\tprojectId\x88\x01\x01\x12.\n" +
+	"\x10progress_percent\x18\x06 \x01(\x05H\x01R\x0fprogressPercent\x88\x01\x01\x12-\n" +
+	"\bpriority\x18\a \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x1e\n" +
+	"\bdue_date\x18\b \x01(\tH\x02R\adueDate\x88\x01\x01\x12.\n" +
+	"\x10estimate_minutes\x18\t \x01(\x05H\x03R\x0festimateMinutes\x88\x01\x01\x12#\n" +
+	"\rcategory_name\x18\n" +
+	" \x01(\tR\fcategoryName\x12Q\n" +
+	"\rcustom_fields\x18\v \x03(\v2,.todo.v1.CreateTodoRequest.CustomFieldsEntryR\fcustomFields\x12-\n" +
+	"\blocation\x18\f \x01(\v2\x11.todo.v1.LocationR\blocation\x1aW\n" +
+	"\x11CustomFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01B\r\n" +
+	"\v_project_idB\x13\n" +
+	"\x11_progress_percentB\v\n" +
+	"\t_due_dateB\x13\n" +
+	"\x11_estimate_minutes\" \n" +
+	"\x0eGetTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x96\x04\n" +
+	"\x10ListTodosRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12'\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x121\n" +
+	"\bcategory\x18\x05 \x01(\x0e2\x11.todo.v1.CategoryB\x02\x18\x01R\bcategory\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x06 \x01(\x03R\tprojectId\x12\x1a\n" +
+	"\barchived\x18\a \x01(\bR\barchived\x12-\n" +
+	"\bpriority\x18\b \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x19\n" +
+	"\bdue_from\x18\t \x01(\tR\adueFrom\x12\x15\n" +
+	"\x06due_to\x18\n" +
+	" \x01(\tR\x05dueTo\x12A\n" +
+	"\x0ecompleted_from\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rcompletedFrom\x12=\n" +
+	"\fcompleted_to\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedTo\x12#\n" +
+	"\rcategory_name\x18\r \x01(\tR\fcategoryName\x12!\n" +
+	"\x04near\x18\x0e \x01(\v2\r.todo.v1.NearR\x04near\"]\n" +
+	"\x04Near\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1b\n" +
+	"\tradius_km\x18\x03 \x01(\x01R\bradiusKm\"d\n" +
+	"\x11ListTodosResponse\x12#\n" +
+	"\x05todos\x18\x01 \x03(\v2\r.todo.v1.TodoR\x05todos\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\"\xf2\x06\n" +
+	"\x11UpdateTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x19\n" +
+	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x04 \x01(\tH\x01R\vdescription\x88\x01\x01\x12'\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x0f.todo.v1.StatusR\x06status\x121\n" +
+	"\bcategory\x18\x06 \x01(\x0e2\x11.todo.v1.CategoryB\x02\x18\x01R\bcategory\x12\"\n" +
+	"\n" +
+	"project_id\x18\a \x01(\x03H\x02R\tprojectId\x88\x01\x01\x12.\n" +
+	"\x10progress_percent\x18\b \x01(\x05H\x03R\x0fprogressPercent\x88\x01\x01\x12-\n" +
+	"\bpriority\x18\t \x01(\x0e2\x11.todo.v1.PriorityR\bpriority\x12\x1e\n" +
+	"\bdue_date\x18\n" +
+	" \x01(\tH\x04R\adueDate\x88\x01\x01\x12.\n" +
+	"\x10estimate_minutes\x18\v \x01(\x05H\x05R\x0festimateMinutes\x88\x01\x01\x12(\n" +
+	"\rcategory_name\x18\f \x01(\tH\x06R\fcategoryName\x88\x01\x01\x12Q\n" +
+	"\rcustom_fields\x18\r \x03(\v2,.todo.v1.UpdateTodoRequest.CustomFieldsEntryR\fcustomFields\x12-\n" +
+	"\blocation\x18\x0e \x01(\v2\x11.todo.v1.LocationR\blocation\x12%\n" +
+	"\x0eclear_location\x18\x0f \x01(\bR\rclearLocation\x12$\n" +
+	"\runconditional\x18\x10 \x01(\bR\runconditional\x1aW\n" +
+	"\x11CustomFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01B\b\n" +
+	"\x06_titleB\x0e\n" +
+	"\f_descriptionB\r\n" +
+	"\v_project_idB\x13\n" +
+	"\x11_progress_percentB\v\n" +
+	"\t_due_dateB\x13\n" +
+	"\x11_estimate_minutesB\x10\n" +
+	"\x0e_category_name\"c\n" +
+	"\x11DeleteTodoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12$\n" +
+	"\runconditional\x18\x03 \x01(\bR\runconditional\"\x14\n" +
+	"\x12DeleteTodoResponse\"B\n" +
+	"\fWatchRequest\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x03R\aafterId\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\x03R\x06todoId\"\x97\x02\n" +
+	"\tTodoEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\atodo_id\x18\x02 \x01(\x03R\x06todoId\x12'\n" +
+	"\x06action\x18\x03 \x01(\x0e2\x0f.todo.v1.ActionR\x06action\x12%\n" +
+	"\x0echanged_fields\x18\x04 \x03(\tR\rchangedFields\x12\x14\n" +
+	"\x05actor\x18\x05 \x01(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12!\n" +
+	"\foperation_id\x18\a \x01(\tR\voperationId\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt*]\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSTATUS_PENDING\x10\x01\x12\x16\n" +
+	"\x12STATUS_IN_PROGRESS\x10\x02\x12\x0f\n" +
+	"\vSTATUS_DONE\x10\x03*b\n" +
+	"\bCategory\x12\x18\n" +
+	"\x14CATEGORY_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11CATEGORY_PERSONAL\x10\x01\x12\x11\n" +
+	"\rCATEGORY_WORK\x10\x02\x12\x12\n" +
+	"\x0eCATEGORY_OTHER\x10\x03*\x86\x01\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rPRIORITY_NONE\x10\x01\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x02\x12\x13\n" +
+	"\x0fPRIORITY_MEDIUM\x10\x03\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x04\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x05*E\n" +
+	"\x06Triage\x12\x16\n" +
+	"\x12TRIAGE_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eTRIAGE_PENDING\x10\x01\x12\x0f\n" +
+	"\vTRIAGE_DONE\x10\x02*Y\n" +
+	"\x06Action\x12\x16\n" +
+	"\x12ACTION_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rACTION_CREATE\x10\x01\x12\x11\n" +
+	"\rACTION_UPDATE\x10\x02\x12\x11\n" +
+	"\rACTION_DELETE\x10\x032\xf3\x02\n" +
+	"\vTodoService\x127\n" +
+	"\n" +
+	"CreateTodo\x12\x1a.todo.v1.CreateTodoRequest\x1a\r.todo.v1.Todo\x121\n" +
+	"\aGetTodo\x12\x17.todo.v1.GetTodoRequest\x1a\r.todo.v1.Todo\x12B\n" +
+	"\tListTodos\x12\x19.todo.v1.ListTodosRequest\x1a\x1a.todo.v1.ListTodosResponse\x127\n" +
+	"\n" +
+	"UpdateTodo\x12\x1a.todo.v1.UpdateTodoRequest\x1a\r.todo.v1.Todo\x12E\n" +
+	"\n" +
+	"DeleteTodo\x12\x1a.todo.v1.DeleteTodoRequest\x1a\x1b.todo.v1.DeleteTodoResponse\x124\n" +
+	"\x05Watch\x12\x15.todo.v1.WatchRequest\x1a\x12.todo.v1.TodoEvent0\x01B*Z(todo-service/internal/gen/todo/v1;todov1b\x06proto3"
+
+var (
+	file_todo_v1_todo_proto_rawDescOnce sync.Once
+	file_todo_v1_todo_proto_rawDescData []byte
+)
+
+func file_todo_v1_todo_proto_rawDescGZIP() []byte {
+	file_todo_v1_todo_proto_rawDescOnce.Do(func() {
+		file_todo_v1_todo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_v1_todo_proto_rawDesc), len(file_todo_v1_todo_proto_rawDesc)))
+	})
+	return file_todo_v1_todo_proto_rawDescData
+}
+
+var file_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_todo_v1_todo_proto_goTypes = []any{
+	(Status)(0),                   // 0: todo.v1.Status
+	(Category)(0),                 // 1: todo.v1.Category
+	(Priority)(0),                 // 2: todo.v1.Priority
+	(Triage)(0),                   // 3: todo.v1.Triage
+	(Action)(0),                   // 4: todo.v1.Action
+	(*Todo)(nil),                  // 5: todo.v1.Todo
+	(*Location)(nil),              // 6: todo.v1.Location
+	(*CreateTodoRequest)(nil),     // 7: todo.v1.CreateTodoRequest
+	(*GetTodoRequest)(nil),        // 8: todo.v1.GetTodoRequest
+	(*ListTodosRequest)(nil),      // 9: todo.v1.ListTodosRequest
+	(*Near)(nil),                  // 10: todo.v1.Near
+	(*ListTodosResponse)(nil),     // 11: todo.v1.ListTodosResponse
+	(*UpdateTodoRequest)(nil),     // 12: todo.v1.UpdateTodoRequest
+	(*DeleteTodoRequest)(nil),     // 13: todo.v1.DeleteTodoRequest
+	(*DeleteTodoResponse)(nil),    // 14: todo.v1.DeleteTodoResponse
+	(*WatchRequest)(nil),          // 15: todo.v1.WatchRequest
+	(*TodoEvent)(nil),             // 16: todo.v1.TodoEvent
+	nil,                           // 17: todo.v1.Todo.CustomFieldsEntry
+	nil,                           // 18: todo.v1.CreateTodoRequest.CustomFieldsEntry
+	nil,                           // 19: todo.v1.UpdateTodoRequest.CustomFieldsEntry
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*structpb.Value)(nil),        // 21: google.protobuf.Value
+}
+var file_todo_v1_todo_proto_depIdxs = []int32{
+	0,  // 0: todo.v1.Todo.status:type_name -> todo.v1.Status
+	1,  // 1: todo.v1.Todo.category:type_name -> todo.v1.Category
+	20, // 2: todo.v1.Todo.created_at:type_name -> google.protobuf.Timestamp
+	20, // 3: todo.v1.Todo.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 4: todo.v1.Todo.priority:type_name -> todo.v1.Priority
+	20, // 5: todo.v1.Todo.completed_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: todo.v1.Todo.triage:type_name -> todo.v1.Triage
+	20, // 7: todo.v1.Todo.snoozed_until:type_name -> google.protobuf.Timestamp
+	17, // 8: todo.v1.Todo.custom_fields:type_name -> todo.v1.Todo.CustomFieldsEntry
+	6,  // 9: todo.v1.Todo.location:type_name -> todo.v1.Location
+	0,  // 10: todo.v1.CreateTodoRequest.status:type_name -> todo.v1.Status
+	1,  // 11: todo.v1.CreateTodoRequest.category:type_name -> todo.v1.Category
+	2,  // 12: todo.v1.CreateTodoRequest.priority:type_name -> todo.v1.Priority
+	18, // 13: todo.v1.CreateTodoRequest.custom_fields:type_name -> todo.v1.CreateTodoRequest.CustomFieldsEntry
+	6,  // 14: todo.v1.CreateTodoRequest.location:type_name -> todo.v1.Location
+	0,  // 15: todo.v1.ListTodosRequest.status:type_name -> todo.v1.Status
+	1,  // 16: todo.v1.ListTodosRequest.category:type_name -> todo.v1.Category
+	2,  // 17: todo.v1.ListTodosRequest.priority:type_name -> todo.v1.Priority
+	20, // 18: todo.v1.ListTodosRequest.completed_from:type_name -> google.protobuf.Timestamp
+	20, // 19: todo.v1.ListTodosRequest.completed_to:type_name -> google.protobuf.Timestamp
+	10, // 20: todo.v1.ListTodosRequest.near:type_name -> todo.v1.Near
+	5,  // 21: todo.v1.ListTodosResponse.todos:type_name -> todo.v1.Todo
+	0,  // 22: todo.v1.UpdateTodoRequest.status:type_name -> todo.v1.Status
+	1,  // 23: todo.v1.UpdateTodoRequest.category:type_name -> todo.v1.Category
+	2,  // 24: todo.v1.UpdateTodoRequest.priority:type_name -> todo.v1.Priority
+	19, // 25: todo.v1.UpdateTodoRequest.custom_fields:type_name -> todo.v1.UpdateTodoRequest.CustomFieldsEntry
+	6,  // 26: todo.v1.UpdateTodoRequest.location:type_name -> todo.v1.Location
+	4,  // 27: todo.v1.TodoEvent.action:type_name -> todo.v1.Action
+	20, // 28: todo.v1.TodoEvent.created_at:type_name -> google.protobuf.Timestamp
+	21, // 29: todo.v1.Todo.CustomFieldsEntry.value:type_name -> google.protobuf.Value
+	21, // 30: todo.v1.CreateTodoRequest.CustomFieldsEntry.value:type_name -> google.protobuf.Value
+	21, // 31: todo.v1.UpdateTodoRequest.CustomFieldsEntry.value:type_name -> google.protobuf.Value
+	7,  // 32: todo.v1.TodoService.CreateTodo:input_type -> todo.v1.CreateTodoRequest
+	8,  // 33: todo.v1.TodoService.GetTodo:input_type -> todo.v1.GetTodoRequest
+	9,  // 34: todo.v1.TodoService.ListTodos:input_type -> todo.v1.ListTodosRequest
+	12, // 35: todo.v1.TodoService.UpdateTodo:input_type -> todo.v1.UpdateTodoRequest
+	13, // 36: todo.v1.TodoService.DeleteTodo:input_type -> todo.v1.DeleteTodoRequest
+	15, // 37: todo.v1.TodoService.Watch:input_type -> todo.v1.WatchRequest
+	5,  // 38: todo.v1.TodoService.CreateTodo:output_type -> todo.v1.Todo
+	5,  // 39: todo.v1.TodoService.GetTodo:output_type -> todo.v1.Todo
+	11, // 40: todo.v1.TodoService.ListTodos:output_type -> todo.v1.ListTodosResponse
+	5,  // 41: todo.v1.TodoService.UpdateTodo:output_type -> todo.v1.Todo
+	14, // 42: todo.v1.TodoService.DeleteTodo:output_type -> todo.v1.DeleteTodoResponse
+	16, // 43: todo.v1.TodoService.Watch:output_type -> todo.v1.TodoEvent
+	38, // [38:44] is the sub-list for method output_type
+	32, // [32:38] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_todo_v1_todo_proto_init() }
+func file_todo_v1_todo_proto_init() {
+	if File_todo_v1_todo_proto != nil {
+		return
+	}
+	file_todo_v1_todo_proto_msgTypes[0].OneofWrappers = []any{}
+	file_todo_v1_todo_proto_msgTypes[2].OneofWrappers = []any{}
+	file_todo_v1_todo_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v1_todo_proto_rawDesc), len(file_todo_v1_todo_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todo_v1_todo_proto_goTypes,
+		DependencyIndexes: file_todo_v1_todo_proto_depIdxs,
+		EnumInfos:         file_todo_v1_todo_proto_enumTypes,
+		MessageInfos:      file_todo_v1_todo_proto_msgTypes,
+	}.Build()
+	File_todo_v1_todo_proto = out.File
+	file_todo_v1_todo_proto_goTypes = nil
+	file_todo_v1_todo_proto_depIdxs = nil
+}
@@ -0,0 +1,158 @@
+// Package github mirrors GitHub issues into TODOs and pushes the TODOs'
+// status changes back to the issues.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// APIURL is the base URL of the GitHub REST API.
+const APIURL = "https://api.github.com"
+
+// ErrUnauthorized is returned when GitHub rejects the token.
+var ErrUnauthorized = errors.New("github rejected the token")
+
+// Issue states.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+// Issue is a GitHub issue.
+type Issue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	State     string    `json:"state"`
+	URL       string    `json:"html_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// PullRequest is set on pull requests, which the issues API lists too.
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// Client reads and updates issues through the GitHub REST API.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient creates a Client for the GitHub API at baseURL, normally
+// APIURL, that authenticates with token.
+func NewClient(baseURL, token string) *Client {
+	return &Client{baseURL: baseURL, token: token, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+var repoName = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+// ParseRepos parses a comma-separated list of owner/name repositories.
+func ParseRepos(s string) ([]string, error) {
+	var repos []string
+	for name := range strings.SplitSeq(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !repoName.MatchString(name) {
+			return nil, fmt.Errorf("repository %q is not of the form owner/name", name)
+		}
+		repos = append(repos, name)
+	}
+	return repos, nil
+}
+
+// Issues returns the issues of repo, not including pull requests: the open
+// ones if since is nil, or else those of any state updated at or after
+// since.
+func (c *Client) Issues(ctx context.Context, repo string, since *time.Time) ([]Issue, error) {
+	params := url.Values{"per_page": {"100"}, "state": {StateOpen}}
+	if since != nil {
+		params.Set("state", "all")
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	next := c.baseURL + "/repos/" + repo + "/issues?" + params.Encode()
+
+	var issues []Issue
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list github issues: %w", err)
+		}
+		var page []Issue
+		resp, err := c.do(req, &page)
+		if err != nil {
+			return nil, fmt.Errorf("list github issues: %w", err)
+		}
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		next = nextLink(resp.Header.Get("Link"))
+	}
+	return issues, nil
+}
+
+// SetState closes or reopens an issue of repo.
+func (c *Client) SetState(ctx context.Context, repo string, number int, state string) error {
+	body, _ := json.Marshal(map[string]string{"state": state})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch,
+		fmt.Sprintf("%s/repos/%s/issues/%d", c.baseURL, repo, number), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("update github issue: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := c.do(req, nil); err != nil {
+		return fmt.Errorf("update github issue: %w", err)
+	}
+	return nil
+}
+
+// do sends req and decodes a successful response's body into v, if not nil.
+func (c *Client) do(req *http.Request, v any) (*http.Response, error) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "todo-service")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, ErrUnauthorized
+	case resp.StatusCode/100 != 2:
+		return nil, fmt.Errorf("github responded %s", resp.Status)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// nextLink returns the URL of the next page from a Link header, or "" on
+// the last page.
+func nextLink(header string) string {
+	for link := range strings.SplitSeq(header, ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if ok && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
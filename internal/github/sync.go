@@ -0,0 +1,231 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+// Actor attributes changes made by the GitHub sync in the audit log.
+const Actor = "system:github-sync"
+
+// cursorOverlap is how far before the last sync issues are read again, so
+// that clock skew between the service and GitHub loses no changes.
+const cursorOverlap = 5 * time.Minute
+
+// Syncer keeps the TODOs mirroring the issues of a set of repositories in
+// sync with them. Each repository's issues go in a project named after it.
+//
+// A new open issue gets a pending TODO titled after it, with its body and
+// link as the description. Closing or reopening either side completes or
+// reopens the other. Issue title and body changes are copied to the TODO;
+// TODO edits are kept locally, and if both were edited since the last sync
+// the conflict rule decides which wins.
+type Syncer struct {
+	repo      db.Repository
+	client    *Client
+	repos     []string
+	conflicts model.GitHubConflicts
+
+	// mu keeps scheduled and manual syncs from running at once.
+	mu sync.Mutex
+}
+
+// NewSyncer creates a Syncer for repos, owner/name each, that reads GitHub
+// through client and settles conflicts by the conflicts rule.
+func NewSyncer(repo db.Repository, client *Client, repos []string, conflicts model.GitHubConflicts) *Syncer {
+	return &Syncer{repo: repo, client: client, repos: repos, conflicts: conflicts}
+}
+
+// Sync syncs every repository, waiting for any sync already running to
+// finish first. A repository that fails to sync does not stop the others;
+// its errors are reported in its summary.
+func (s *Syncer) Sync(ctx context.Context) model.GitHubSyncResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := db.AuditInfo{Actor: Actor, OperationID: db.NewOperationID()}
+	result := model.GitHubSyncResponse{Repos: []model.GitHubRepoSync{}}
+	for _, name := range s.repos {
+		result.Repos = append(result.Repos, s.syncRepo(ctx, name, info))
+	}
+	return result
+}
+
+// mirror is an issue as its TODO shows it.
+type mirror struct {
+	state       string
+	title       string
+	description string
+	updatedAt   time.Time
+}
+
+func mirrorOf(issue Issue) mirror {
+	title := strings.TrimSpace(issue.Title)
+	if utf8.RuneCountInString(title) > model.MaxTitleLength {
+		title = string([]rune(title)[:model.MaxTitleLength-1]) + "…"
+	}
+	body := strings.TrimSpace(issue.Body)
+	if limit := model.MaxDescriptionLength - utf8.RuneCountInString(issue.URL) - 2; utf8.RuneCountInString(body) > limit {
+		body = string([]rune(body)[:limit-1]) + "…"
+	}
+	return mirror{
+		state:       issue.State,
+		title:       title,
+		description: strings.TrimSpace(body + "\n\n" + issue.URL),
+		updatedAt:   issue.UpdatedAt,
+	}
+}
+
+func (s *Syncer) syncRepo(ctx context.Context, name string, info db.AuditInfo) model.GitHubRepoSync {
+	result := model.GitHubRepoSync{Repo: name, Conflicts: []model.GitHubConflict{}}
+	fail := func(number int, err error) {
+		msg := err.Error()
+		if number != 0 {
+			msg = fmt.Sprintf("issue %d: %s", number, msg)
+		}
+		result.Errors = append(result.Errors, msg)
+	}
+
+	start := time.Now()
+	since, err := s.repo.GitHubCursor(name)
+	if err != nil {
+		fail(0, err)
+		return result
+	}
+	if since != nil {
+		t := since.Add(-cursorOverlap)
+		since = &t
+	}
+	issues, err := s.client.Issues(ctx, name, since)
+	if err != nil {
+		fail(0, err)
+		return result
+	}
+	result.Issues = len(issues)
+	links, err := s.repo.GitHubLinks(name)
+	if err != nil {
+		fail(0, err)
+		return result
+	}
+
+	for _, issue := range issues {
+		m := mirrorOf(issue)
+		link, ok := links[issue.Number]
+		delete(links, issue.Number)
+		if ok {
+			if err := s.reconcile(ctx, &result, link, m, info); err != nil {
+				fail(issue.Number, err)
+			}
+			continue
+		}
+		// Issues closed before they were first seen are not mirrored.
+		if issue.State != StateOpen {
+			continue
+		}
+		req := model.CreateTodoRequest{Title: m.title, Description: m.description, Status: model.StatusPending}
+		link = db.GitHubLink{Repo: name, Number: issue.Number, State: m.state, Title: m.title, Body: m.description}
+		if _, err := s.repo.CreateGitHubTodo(name, req, link, info); err != nil {
+			fail(issue.Number, err)
+			continue
+		}
+		result.Created++
+	}
+
+	// Issues that have not changed since the last sync can still have TODOs
+	// whose status has.
+	for _, link := range links {
+		m := mirror{state: link.State, title: link.Title, description: link.Body}
+		if err := s.reconcile(ctx, &result, link, m, info); err != nil {
+			fail(link.Number, err)
+		}
+	}
+
+	if len(result.Errors) == 0 {
+		if err := s.repo.SetGitHubCursor(name, start); err != nil {
+			fail(0, err)
+		}
+	}
+	return result
+}
+
+// reconcile brings a linked issue, as m, and its TODO back in sync and
+// records the link as synced.
+func (s *Syncer) reconcile(ctx context.Context, result *model.GitHubRepoSync, link db.GitHubLink, m mirror, info db.AuditInfo) error {
+	if link.TodoID == nil {
+		return nil
+	}
+	todo, err := s.repo.GetTodo(*link.TodoID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	local := StateOpen
+	if todo.Status == model.StatusDone {
+		local = StateClosed
+	}
+	updated := false
+	switch {
+	case m.state != link.State && local == link.State:
+		if m.state == StateClosed {
+			todo, err = s.repo.CompleteTodo(todo.ID, info)
+		} else {
+			todo, err = s.repo.ReopenTodo(todo.ID, info)
+		}
+		if err != nil {
+			return err
+		}
+		updated = true
+	case local != link.State && m.state == link.State:
+		if err := s.client.SetState(ctx, link.Repo, link.Number, local); err != nil {
+			return err
+		}
+		m.state = local
+		result.Pushed++
+	}
+
+	remoteEdited := m.title != link.Title || m.description != link.Body
+	localEdited := todo.Title != link.Title || todo.Description != link.Body
+	if remoteEdited && (todo.Title != m.title || todo.Description != m.description) {
+		keepRemote := true
+		if localEdited {
+			switch s.conflicts {
+			case model.GitHubConflictsLocal:
+				keepRemote = false
+			case model.GitHubConflictsNewer:
+				keepRemote = m.updatedAt.After(todo.UpdatedAt)
+			}
+			kept := "github"
+			if !keepRemote {
+				kept = "local"
+			}
+			result.Conflicts = append(result.Conflicts, model.GitHubConflict{Issue: link.Number, TodoID: todo.ID, Kept: kept})
+		}
+		if keepRemote {
+			req := model.UpdateTodoRequest{Title: &m.title, Description: &m.description}
+			if _, err := s.repo.UpdateTodo(todo.ID, todo.Version, req, info); err != nil {
+				return err
+			}
+			updated = true
+		}
+	}
+	if updated {
+		result.Updated++
+	}
+
+	if m.state == link.State && m.title == link.Title && m.description == link.Body {
+		return nil
+	}
+	link.State, link.Title, link.Body = m.state, m.title, m.description
+	return s.repo.SaveGitHubLink(link)
+}
@@ -0,0 +1,209 @@
+package grpcapi
+
+import (
+	"slices"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "todo-service/internal/gen/todo/v1"
+	"todo-service/internal/model"
+)
+
+var statuses = map[todov1.Status]model.Status{
+	todov1.Status_STATUS_PENDING:     model.StatusPending,
+	todov1.Status_STATUS_IN_PROGRESS: model.StatusInProgress,
+	todov1.Status_STATUS_DONE:        model.StatusDone,
+}
+
+var categories = map[todov1.Category]model.Category{
+	todov1.Category_CATEGORY_PERSONAL: model.CategoryPersonal,
+	todov1.Category_CATEGORY_WORK:     model.CategoryWork,
+	todov1.Category_CATEGORY_OTHER:    model.CategoryOther,
+}
+
+var priorities = map[todov1.Priority]model.Priority{
+	todov1.Priority_PRIORITY_NONE:   model.PriorityNone,
+	todov1.Priority_PRIORITY_LOW:    model.PriorityLow,
+	todov1.Priority_PRIORITY_MEDIUM: model.PriorityMedium,
+	todov1.Priority_PRIORITY_HIGH:   model.PriorityHigh,
+	todov1.Priority_PRIORITY_URGENT: model.PriorityUrgent,
+}
+
+var triages = map[model.Triage]todov1.Triage{
+	model.TriagePending: todov1.Triage_TRIAGE_PENDING,
+	model.TriageDone:    todov1.Triage_TRIAGE_DONE,
+}
+
+var actions = map[model.AuditAction]todov1.Action{
+	model.AuditActionCreate: todov1.Action_ACTION_CREATE,
+	model.AuditActionUpdate: todov1.Action_ACTION_UPDATE,
+	model.AuditActionDelete: todov1.Action_ACTION_DELETE,
+}
+
+// statusFromProto returns "" for STATUS_UNSPECIFIED. Unknown values map to
+// an invalid status so that validation rejects them.
+func statusFromProto(s todov1.Status) model.Status {
+	if s == todov1.Status_STATUS_UNSPECIFIED {
+		return ""
+	}
+	if st, ok := statuses[s]; ok {
+		return st
+	}
+	return model.Status(s.String())
+}
+
+func statusToProto(s model.Status) todov1.Status {
+	for k, v := range statuses {
+		if v == s {
+			return k
+		}
+	}
+	return todov1.Status_STATUS_UNSPECIFIED
+}
+
+// categoryFromProto returns name if set, since it covers every category, and
+// otherwise the deprecated enum: "" for CATEGORY_UNSPECIFIED. Unknown enum
+// values map to a category that does not exist so that the repository
+// rejects them.
+func categoryFromProto(name string, c todov1.Category) model.Category {
+	if name != "" {
+		return model.Category(name)
+	}
+	if c == todov1.Category_CATEGORY_UNSPECIFIED {
+		return ""
+	}
+	if cat, ok := categories[c]; ok {
+		return cat
+	}
+	return model.Category(c.String())
+}
+
+func categoryToProto(c model.Category) todov1.Category {
+	for k, v := range categories {
+		if v == c {
+			return k
+		}
+	}
+	return todov1.Category_CATEGORY_UNSPECIFIED
+}
+
+// priorityFromProto returns "" for PRIORITY_UNSPECIFIED. Unknown values map
+// to an invalid priority so that validation rejects them.
+func priorityFromProto(p todov1.Priority) model.Priority {
+	if p == todov1.Priority_PRIORITY_UNSPECIFIED {
+		return ""
+	}
+	if pr, ok := priorities[p]; ok {
+		return pr
+	}
+	return model.Priority(p.String())
+}
+
+func priorityToProto(p model.Priority) todov1.Priority {
+	for k, v := range priorities {
+		if v == p {
+			return k
+		}
+	}
+	return todov1.Priority_PRIORITY_UNSPECIFIED
+}
+
+func todoToProto(t model.Todo) *todov1.Todo {
+	pb := &todov1.Todo{
+		Id:              t.ID,
+		Title:           t.Title,
+		Description:     t.Description,
+		Status:          statusToProto(t.Status),
+		Category:        categoryToProto(t.Category),
+		CategoryName:    string(t.Category),
+		ProjectId:       t.ProjectID,
+		ProgressPercent: int32(t.ProgressPercent),
+		EstimateMinutes: int32(t.EstimateMinutes),
+		Priority:        priorityToProto(t.Priority),
+		DueDate:         t.DueDate,
+		Archived:        t.Archived,
+		Version:         t.Version,
+		CreatedAt:       timestamppb.New(t.CreatedAt),
+		UpdatedAt:       timestamppb.New(t.UpdatedAt),
+		ScheduledFor:    t.ScheduledFor,
+		Triage:          triages[t.Triage],
+		Position:        t.Position,
+		Assignee:        t.Assignee,
+		Pinned:          t.Pinned,
+		Color:           t.Color,
+		Icon:            t.Icon,
+		CustomFields:    customFieldsToProto(t.CustomFields),
+		Location:        locationToProto(t.Location),
+	}
+	if t.CompletedAt != nil {
+		pb.CompletedAt = timestamppb.New(*t.CompletedAt)
+	}
+	if t.SnoozedUntil != nil {
+		pb.SnoozedUntil = timestamppb.New(*t.SnoozedUntil)
+	}
+	return pb
+}
+
+// customFieldsToProto converts custom field values, which are strings and
+// numbers, to protobuf values.
+func customFieldsToProto(values map[string]any) map[string]*structpb.Value {
+	if len(values) == 0 {
+		return nil
+	}
+	pb := make(map[string]*structpb.Value, len(values))
+	for name, v := range values {
+		if value, err := structpb.NewValue(v); err == nil {
+			pb[name] = value
+		}
+	}
+	return pb
+}
+
+// customFieldsFromProto converts protobuf values to custom field values,
+// with null values as nil, which removes a value in an update. The
+// repository checks them against the fields' types.
+func customFieldsFromProto(pb map[string]*structpb.Value) map[string]any {
+	if len(pb) == 0 {
+		return nil
+	}
+	values := make(map[string]any, len(pb))
+	for name, v := range pb {
+		values[name] = v.AsInterface()
+	}
+	return values
+}
+
+func locationToProto(loc *model.Location) *todov1.Location {
+	if loc == nil {
+		return nil
+	}
+	return &todov1.Location{Latitude: loc.Latitude, Longitude: loc.Longitude, Place: loc.Place}
+}
+
+// locationFromProto returns nil if pb is unset.
+func locationFromProto(pb *todov1.Location) *model.Location {
+	if pb == nil {
+		return nil
+	}
+	return &model.Location{Latitude: pb.GetLatitude(), Longitude: pb.GetLongitude(), Place: pb.GetPlace()}
+}
+
+func eventToProto(e model.AuditEntry) *todov1.TodoEvent {
+	fields := make([]string, 0, len(e.Changes))
+	for name := range e.Changes {
+		fields = append(fields, name)
+	}
+	slices.Sort(fields)
+
+	return &todov1.TodoEvent{
+		Id:            e.ID,
+		TodoId:        e.TodoID,
+		Action:        actions[e.Action],
+		ChangedFields: fields,
+		Actor:         e.Actor,
+		RequestId:     e.RequestID,
+		OperationId:   e.OperationID,
+		CreatedAt:     timestamppb.New(e.CreatedAt),
+	}
+}
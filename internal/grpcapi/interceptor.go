@@ -0,0 +1,188 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"todo-service/internal/db"
+	todov1 "todo-service/internal/gen/todo/v1"
+	"todo-service/internal/middleware"
+)
+
+// Metadata keys mirroring the X-Request-ID and X-Actor HTTP headers.
+const (
+	requestIDKey = "x-request-id"
+	actorKey     = "x-actor"
+)
+
+type requestIDCtxKey struct{}
+
+// unaryLogger assigns each call a request ID and logs it on completion, like
+// middleware.RequestLogger does for HTTP.
+func unaryLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, reqID := withRequestID(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, reqID, start, err)
+		return resp, err
+	}
+}
+
+func streamLogger(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, reqID := withRequestID(ss.Context())
+		start := time.Now()
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, reqID, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method, reqID string, start time.Time, err error) {
+	code := status.Code(err)
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	logger.Log(ctx, level, "grpc call completed",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000.0),
+		slog.String("request_id", reqID),
+		slog.String("remote_addr", peerAddr(ctx)),
+	)
+}
+
+// unaryRecovery converts panics into Internal errors and logs the stack.
+func unaryRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverPanic(logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func streamRecovery(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverPanic(logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+func recoverPanic(logger *slog.Logger, method string, err *error) {
+	if rvr := recover(); rvr != nil {
+		logger.Error("panic recovered",
+			slog.String("error", fmt.Sprintf("%v", rvr)),
+			slog.String("stack", string(debug.Stack())),
+			slog.String("method", method),
+		)
+		*err = status.Error(codes.Internal, "an unexpected error occurred")
+	}
+}
+
+// mutatingMethods are the calls read-only maintenance mode refuses.
+var mutatingMethods = []string{
+	todov1.TodoService_CreateTodo_FullMethodName,
+	todov1.TodoService_UpdateTodo_FullMethodName,
+	todov1.TodoService_DeleteTodo_FullMethodName,
+}
+
+// unaryReadOnly refuses calls that change data with Unavailable while m is
+// read-only, like middleware.ReadOnly does for HTTP. The Retry-After
+// header's delay, if the operator set one, is sent as RetryInfo.
+func unaryReadOnly(m *middleware.Maintenance) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !slices.Contains(mutatingMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		mode := m.Mode()
+		if !mode.ReadOnly {
+			return handler(ctx, req)
+		}
+
+		st := status.New(codes.Unavailable, middleware.ReadOnlyMessage(mode))
+		if mode.RetryAfterSeconds > 0 {
+			delay := time.Duration(mode.RetryAfterSeconds) * time.Second
+			if withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+				st = withRetry
+			}
+		}
+		return nil, st.Err()
+	}
+}
+
+// withRequestID stores the caller's x-request-id, or a new one, in ctx.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	reqID := firstMetadata(ctx, requestIDKey)
+	if reqID == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		reqID = "grpc/" + hex.EncodeToString(b)
+	}
+	return context.WithValue(ctx, requestIDCtxKey{}, reqID), reqID
+}
+
+// auditInfo builds the audit attribution for the call carried by ctx,
+// starting a new operation. The actor falls back to the client's IP, as it
+// does over HTTP.
+func auditInfo(ctx context.Context) db.AuditInfo {
+	actor := firstMetadata(ctx, actorKey)
+	if actor == "" {
+		actor = peerAddr(ctx)
+		if host, _, err := net.SplitHostPort(actor); err == nil {
+			actor = host
+		}
+	}
+	reqID, _ := ctx.Value(requestIDCtxKey{}).(string)
+
+	return db.AuditInfo{
+		Actor:       actor,
+		RequestID:   reqID,
+		OperationID: db.NewOperationID(),
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if vals := metadata.ValueFromIncomingContext(ctx, key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// contextStream overrides the context of a grpc.ServerStream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
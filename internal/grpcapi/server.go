@@ -0,0 +1,379 @@
+// Package grpcapi serves the TODO API over gRPC, sharing the repository and
+// validation with the HTTP handlers.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"todo-service/internal/db"
+	todov1 "todo-service/internal/gen/todo/v1"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/validate"
+)
+
+// watchInterval is how often Watch polls the audit log for new entries.
+const watchInterval = 500 * time.Millisecond
+
+// watchBatch is the most audit entries Watch reads per poll.
+const watchBatch = 100
+
+// defaultRadiusKm is the radius of a Near filter that sets none, as over
+// HTTP.
+const defaultRadiusKm = 5
+
+// Server implements todov1.TodoServiceServer.
+type Server struct {
+	todov1.UnimplementedTodoServiceServer
+
+	repo   db.Repository
+	logger *slog.Logger
+	grpc   *grpc.Server
+	done   chan struct{}
+}
+
+// New creates a Server with logging and panic recovery interceptors, which
+// refuses changes while maintenance is read-only.
+func New(repo db.Repository, maintenance *middleware.Maintenance, logger *slog.Logger) *Server {
+	s := &Server{repo: repo, logger: logger, done: make(chan struct{})}
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger(logger), unaryRecovery(logger), unaryReadOnly(maintenance)),
+		grpc.ChainStreamInterceptor(streamLogger(logger), streamRecovery(logger)),
+	)
+	todov1.RegisterTodoServiceServer(s.grpc, s)
+	return s
+}
+
+// Serve accepts connections on lis until Shutdown is called.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown ends open Watch streams and waits for in-flight calls to finish,
+// forcing the server to stop once ctx expires.
+func (s *Server) Shutdown(ctx context.Context) {
+	close(s.done)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpc.Stop()
+	}
+}
+
+func (s *Server) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.Todo, error) {
+	create := model.CreateTodoRequest{
+		Title:        req.GetTitle(),
+		Description:  req.GetDescription(),
+		Status:       statusFromProto(req.GetStatus()),
+		Category:     categoryFromProto(req.GetCategoryName(), req.GetCategory()),
+		ProjectID:    req.ProjectId,
+		Priority:     priorityFromProto(req.GetPriority()),
+		DueDate:      req.DueDate,
+		CustomFields: customFieldsFromProto(req.GetCustomFields()),
+		Location:     locationFromProto(req.GetLocation()),
+	}
+	if req.ProgressPercent != nil {
+		p := int(req.GetProgressPercent())
+		create.ProgressPercent = &p
+	}
+	if req.EstimateMinutes != nil {
+		m := int(req.GetEstimateMinutes())
+		create.EstimateMinutes = &m
+	}
+	if err := validate.CreateTodo(create); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	info := auditInfo(ctx)
+	todo, err := s.repo.CreateTodo(create, info)
+	if err != nil {
+		return nil, s.repoError(err, "create todo", info)
+	}
+	return todoToProto(todo), nil
+}
+
+func (s *Server) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.Todo, error) {
+	todo, err := s.repo.GetTodo(req.GetId())
+	if err != nil {
+		return nil, s.repoError(err, "get todo", db.AuditInfo{})
+	}
+	return todoToProto(todo), nil
+}
+
+func (s *Server) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
+	if err := validate.Page(int(req.GetLimit()), int(req.GetOffset())); err != nil {
+		return nil, invalidArgument(err)
+	}
+	st, c := statusFromProto(req.GetStatus()), categoryFromProto(req.GetCategoryName(), req.GetCategory())
+	if err := validate.Filter(st); err != nil {
+		return nil, invalidArgument(err)
+	}
+	pr := priorityFromProto(req.GetPriority())
+	if err := validate.Priority(pr); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if d := req.GetDueFrom(); d != "" {
+		if err := validate.Date("due_from", d); err != nil {
+			return nil, invalidArgument(err)
+		}
+	}
+	if d := req.GetDueTo(); d != "" {
+		if err := validate.Date("due_to", d); err != nil {
+			return nil, invalidArgument(err)
+		}
+	}
+
+	params := query.Params{Limit: int(req.GetLimit()), Offset: int(req.GetOffset()), Sort: req.GetSort()}
+	opts, err := params.Options(db.TodoSort)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	archived, snoozed := req.GetArchived(), false
+	filter := db.TodoFilter{Archived: &archived, Snoozed: &snoozed}
+	if st != "" {
+		filter.Status = &st
+	}
+	if c != "" {
+		filter.Category = &c
+	}
+	if id := req.GetProjectId(); id != 0 {
+		filter.ProjectID = &id
+	}
+	if pr != "" {
+		filter.Priority = &pr
+	}
+	if d := req.GetDueFrom(); d != "" {
+		filter.DueFrom = &d
+	}
+	if d := req.GetDueTo(); d != "" {
+		filter.DueTo = &d
+	}
+	if req.CompletedFrom != nil {
+		from := req.GetCompletedFrom().AsTime()
+		filter.CompletedFrom = &from
+	}
+	if req.CompletedTo != nil {
+		to := req.GetCompletedTo().AsTime()
+		filter.CompletedTo = &to
+	}
+	if near := req.GetNear(); near != nil {
+		point := model.Location{Latitude: near.GetLatitude(), Longitude: near.GetLongitude()}
+		if err := validate.Near(point); err != nil {
+			return nil, invalidArgument(err)
+		}
+		radius := near.GetRadiusKm()
+		if radius == 0 {
+			radius = defaultRadiusKm
+		}
+		if !(radius > 0 && radius <= model.MaxRadiusKm) {
+			return nil, status.Errorf(codes.InvalidArgument, "radius_km must be greater than 0 and at most %d", model.MaxRadiusKm)
+		}
+		filter.Near = &db.Near{Latitude: point.Latitude, Longitude: point.Longitude, RadiusKm: radius}
+	}
+
+	total, err := s.repo.CountTodos(filter)
+	if err != nil {
+		return nil, s.repoError(err, "count todos", db.AuditInfo{})
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	todos, err := s.repo.ListTodos(filter, opts)
+	if err != nil {
+		return nil, s.repoError(err, "list todos", db.AuditInfo{})
+	}
+
+	resp := &todov1.ListTodosResponse{
+		Todos: make([]*todov1.Todo, len(todos)),
+		Count: int32(len(todos)),
+		Total: int32(total),
+	}
+	for i, t := range todos {
+		resp.Todos[i] = todoToProto(t)
+	}
+	return resp, nil
+}
+
+func (s *Server) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest) (*todov1.Todo, error) {
+	if err := requireVersion(req.GetVersion(), req.GetUnconditional()); err != nil {
+		return nil, err
+	}
+	update := model.UpdateTodoRequest{
+		Title:        req.Title,
+		Description:  req.Description,
+		ProjectID:    req.ProjectId,
+		DueDate:      req.DueDate,
+		CustomFields: customFieldsFromProto(req.GetCustomFields()),
+	}
+	if st := statusFromProto(req.GetStatus()); st != "" {
+		update.Status = &st
+	}
+	if req.CategoryName != nil {
+		c := model.Category(req.GetCategoryName())
+		update.Category = &c
+	} else if c := categoryFromProto("", req.GetCategory()); c != "" {
+		update.Category = &c
+	}
+	if pr := priorityFromProto(req.GetPriority()); pr != "" {
+		update.Priority = &pr
+	}
+	if req.ProgressPercent != nil {
+		p := int(req.GetProgressPercent())
+		update.ProgressPercent = &p
+	}
+	if req.EstimateMinutes != nil {
+		m := int(req.GetEstimateMinutes())
+		update.EstimateMinutes = &m
+	}
+	if err := validate.UpdateTodo(update); err != nil {
+		return nil, invalidArgument(err)
+	}
+	loc := locationFromProto(req.GetLocation())
+	if loc != nil {
+		if req.GetClearLocation() {
+			return nil, status.Error(codes.InvalidArgument, "location and clear_location cannot both be set")
+		}
+		if err := validate.LocateTodo(*loc); err != nil {
+			return nil, invalidArgument(err)
+		}
+	}
+
+	// The location is changed in the same transaction, so that the version
+	// check covers it and a failure changes nothing.
+	info := auditInfo(ctx)
+	var todo model.Todo
+	err := s.repo.WithTx(ctx, func(tx db.RepositoryTx) error {
+		var err error
+		if todo, err = tx.UpdateTodo(req.GetId(), req.GetVersion(), update, info); err != nil {
+			return err
+		}
+		if loc != nil || req.GetClearLocation() {
+			todo, err = tx.SetLocation(todo.ID, loc, info)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, s.repoError(err, "update todo", info)
+	}
+	return todoToProto(todo), nil
+}
+
+func (s *Server) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
+	if err := requireVersion(req.GetVersion(), req.GetUnconditional()); err != nil {
+		return nil, err
+	}
+	info := auditInfo(ctx)
+	if err := s.repo.DeleteTodo(req.GetId(), req.GetVersion(), info); err != nil {
+		return nil, s.repoError(err, "delete todo", info)
+	}
+	return &todov1.DeleteTodoResponse{}, nil
+}
+
+func (s *Server) Watch(req *todov1.WatchRequest, stream grpc.ServerStreamingServer[todov1.TodoEvent]) error {
+	ctx := stream.Context()
+
+	after := req.GetAfterId()
+	if after == 0 {
+		// AuditSort lists newest first by default.
+		newest, _ := query.Params{Limit: 1}.Options(db.AuditSort)
+		latest, err := s.repo.ListAudit(db.AuditFilter{}, newest)
+		if err != nil {
+			return s.repoError(err, "watch todos", db.AuditInfo{})
+		}
+		if len(latest) > 0 {
+			after = latest[0].ID
+		}
+	}
+
+	filter := db.AuditFilter{AfterID: &after}
+	if id := req.GetTodoId(); id != 0 {
+		filter.TodoID = &id
+	}
+	opts, _ := query.Params{Limit: watchBatch, Sort: "id"}.Options(db.AuditSort)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		entries, err := s.repo.ListAudit(filter, opts)
+		if err != nil {
+			return s.repoError(err, "watch todos", db.AuditInfo{})
+		}
+		for _, e := range entries {
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+			after = e.ID
+		}
+		if len(entries) == watchBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// repoError maps repository errors to gRPC status errors, logging
+// unexpected ones.
+func (s *Server) repoError(err error, op string, info db.AuditInfo) error {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return status.Error(codes.NotFound, "todo not found")
+	case errors.Is(err, db.ErrVersionMismatch):
+		return status.Error(codes.Aborted, "todo has been modified; fetch it again and retry")
+	case errors.Is(err, db.ErrProjectNotFound):
+		return status.Error(codes.InvalidArgument, "project not found")
+	case errors.Is(err, db.ErrCategoryNotFound):
+		return status.Error(codes.InvalidArgument, "category not found")
+	case errors.As(err, new(*db.TransitionError)):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, new(*db.RuleError)):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, new(*db.CustomFieldError)):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.logger.Error("failed to "+op, slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+	return status.Error(codes.Internal, "failed to "+op)
+}
+
+// requireVersion checks that a change names the version it applies to, or
+// asks to apply to any, as the HTTP API requires If-Match. The repository
+// takes version 0 to mean any.
+func requireVersion(version int64, unconditional bool) error {
+	switch {
+	case unconditional && version != 0:
+		return status.Error(codes.InvalidArgument, "version and unconditional cannot both be set")
+	case !unconditional && version == 0:
+		return status.Error(codes.FailedPrecondition, "version is required; send the version from a previous GetTodo, or set unconditional to skip the check")
+	}
+	return nil
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
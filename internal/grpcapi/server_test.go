@@ -0,0 +1,76 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"todo-service/internal/db"
+	todov1 "todo-service/internal/gen/todo/v1"
+	"todo-service/internal/middleware"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	repo, err := db.NewMemory(logger)
+	if err != nil {
+		t.Fatalf("NewMemory: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return New(repo, &middleware.Maintenance{}, logger)
+}
+
+// TestChangesRequireVersion checks that updates and deletes need a version,
+// or unconditional, as HTTP ones need If-Match.
+func TestChangesRequireVersion(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+
+	tests := []struct {
+		name          string
+		version       func(current int64) int64
+		unconditional bool
+		want          codes.Code
+	}{
+		{"no version", func(int64) int64 { return 0 }, false, codes.FailedPrecondition},
+		{"stale version", func(v int64) int64 { return v + 1 }, false, codes.Aborted},
+		{"version and unconditional", func(v int64) int64 { return v }, true, codes.InvalidArgument},
+		{"current version", func(v int64) int64 { return v }, false, codes.OK},
+		{"unconditional", func(int64) int64 { return 0 }, true, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := s.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "Buy groceries"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = s.UpdateTodo(ctx, &todov1.UpdateTodoRequest{
+				Id:            todo.GetId(),
+				Version:       tt.version(todo.GetVersion()),
+				Unconditional: tt.unconditional,
+				Title:         proto.String("Buy more groceries"),
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("update: got %v (%v), want %v", got, err, tt.want)
+			}
+			if err == nil {
+				todo.Version++
+			}
+
+			_, err = s.DeleteTodo(ctx, &todov1.DeleteTodoRequest{
+				Id:            todo.GetId(),
+				Version:       tt.version(todo.GetVersion()),
+				Unconditional: tt.unconditional,
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("delete: got %v (%v), want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// ActionHandler handles HTTP requests for actions.
+type ActionHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewActionHandler creates a new ActionHandler.
+func NewActionHandler(repo db.Repository, logger *slog.Logger) *ActionHandler {
+	return &ActionHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListActionsInput struct {
+	query.Params
+}
+
+type ListActionsOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of actions"`
+	Body       model.ActionListResponse
+}
+
+type CreateActionInput struct {
+	Body model.ActionRequest
+}
+
+type ActionOutput struct {
+	Body model.Action
+}
+
+type GetActionInput struct {
+	ID int64 `path:"id" doc:"Action ID" example:"1"`
+}
+
+type ReplaceActionInput struct {
+	ID   int64 `path:"id" doc:"Action ID" example:"1"`
+	Body model.ActionRequest
+}
+
+type DeleteActionInput struct {
+	ID int64 `path:"id" doc:"Action ID" example:"1"`
+}
+
+type RunActionInput struct {
+	ID int64 `path:"id" doc:"Action ID" example:"1"`
+}
+
+type RunActionOutput struct {
+	Body model.ActionResult
+}
+
+// RegisterRoutes registers all action routes with the huma API.
+func (h *ActionHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-actions",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/actions",
+		Summary:     "List all actions",
+		Description: "Retrieve all actions, sorted by name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"actions"},
+		Errors:      []int{400},
+	}, h.ListActions)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-action",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/actions",
+		Summary:       "Create a new action",
+		Description:   "Save a named operation to run later with a single request, such as from a Stream Deck or other hardware button: complete_first completes the first TODO matching a filter that is not done, start_first starts the first pending one, and create creates a TODO from a template. Action names must be unique.",
+		Tags:          []string{"actions"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateAction)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-action",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/actions/{id}",
+		Summary:     "Get an action by ID",
+		Description: "Retrieve a single action.",
+		Tags:        []string{"actions"},
+		Errors:      []int{404},
+	}, h.GetAction)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "replace-action",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/actions/{id}",
+		Summary:     "Replace an action",
+		Description: "Replace an existing action as a whole, since its filter or todo only make sense with its operation.",
+		Tags:        []string{"actions"},
+		Errors:      []int{400, 404, 409},
+	}, h.ReplaceAction)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-action",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/actions/{id}",
+		Summary:       "Delete an action",
+		Description:   "Delete an action. TODOs it created or changed are not affected.",
+		Tags:          []string{"actions"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteAction)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "run-action",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/actions/{id}/run",
+		Summary:     "Run an action",
+		Description: "Run an action and return the TODO it created or changed. It takes no request body. Responds 409 if no TODO matches the action's filter or the status change is not allowed, and 422 if the category or project of a create action no longer exists or the TODO would break the server's cross-field rules.",
+		Tags:        []string{"actions"},
+		Errors:      []int{400, 404, 409, 422},
+	}, h.RunAction)
+}
+
+func (h *ActionHandler) ListActions(ctx context.Context, input *ListActionsInput) (*ListActionsOutput, error) {
+	opts, err := input.Options(db.ActionSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountActions()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count actions", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve actions")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	actions, err := h.repo.ListActions(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list actions", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve actions")
+	}
+
+	return &ListActionsOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.ActionListResponse{Actions: actions, Count: len(actions), Total: total},
+	}, nil
+}
+
+func (h *ActionHandler) CreateAction(ctx context.Context, input *CreateActionInput) (*ActionOutput, error) {
+	if err := validateAction(input.Body); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	action, err := h.repo.CreateAction(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrActionExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("action %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create action", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create action")
+	}
+
+	return &ActionOutput{Body: action}, nil
+}
+
+func (h *ActionHandler) GetAction(ctx context.Context, input *GetActionInput) (*ActionOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	action, err := h.repo.GetAction(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("action with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get action", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve action")
+	}
+
+	return &ActionOutput{Body: action}, nil
+}
+
+func (h *ActionHandler) ReplaceAction(ctx context.Context, input *ReplaceActionInput) (*ActionOutput, error) {
+	if err := validateAction(input.Body); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	action, err := h.repo.ReplaceAction(input.ID, input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("action with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrActionExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("action %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to replace action", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update action")
+	}
+
+	return &ActionOutput{Body: action}, nil
+}
+
+func (h *ActionHandler) DeleteAction(ctx context.Context, input *DeleteActionInput) (*struct{}, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err := h.repo.DeleteAction(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("action with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete action", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete action")
+	}
+
+	return nil, nil
+}
+
+func (h *ActionHandler) RunAction(ctx context.Context, input *RunActionInput) (*RunActionOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	action, err := h.repo.GetAction(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("action with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get action", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to run action")
+	}
+
+	info := auditInfo(ctx)
+	var todo model.Todo
+	switch action.Operation {
+	case model.ActionCreate:
+		stopDB := timing.Track(ctx, timing.StageDB)
+		todo, err = h.repo.CreateTodo(*action.Todo, info)
+		stopDB()
+		if errors.Is(err, db.ErrProjectNotFound) {
+			return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("project with id %d not found", *action.Todo.ProjectID))
+		}
+		if errors.Is(err, db.ErrCategoryNotFound) {
+			return nil, categoryNotFound(action.Todo.Category)
+		}
+	case model.ActionCompleteFirst, model.ActionStartFirst:
+		var first model.Todo
+		first, err = h.first(ctx, action)
+		if err != nil {
+			return nil, err
+		}
+		stopDB := timing.Track(ctx, timing.StageDB)
+		if action.Operation == model.ActionCompleteFirst {
+			todo, err = h.repo.CompleteTodo(first.ID, info)
+		} else {
+			status := model.StatusInProgress
+			todo, err = h.repo.UpdateTodo(first.ID, 0, model.UpdateTodoRequest{Status: &status}, info)
+		}
+		stopDB()
+		var transitionErr *db.TransitionError
+		if errors.As(err, &transitionErr) {
+			return nil, transitionConflict(transitionErr)
+		}
+	default:
+		err = fmt.Errorf("unknown operation %q", action.Operation)
+	}
+	var ruleErr *db.RuleError
+	if errors.As(err, &ruleErr) {
+		return nil, ruleViolation(ruleErr)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to run action", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to run action")
+	}
+
+	return &RunActionOutput{Body: model.ActionResult{ActionID: action.ID, Operation: action.Operation, Todo: todo}}, nil
+}
+
+// first returns the TODO a complete_first or start_first action applies to:
+// the first, in the filter's sort or else manual order, that matches the
+// filter and has a status the operation can change.
+func (h *ActionHandler) first(ctx context.Context, action model.Action) (model.Todo, error) {
+	var f model.ViewFilter
+	if action.Filter != nil {
+		f = *action.Filter
+	}
+
+	eligible := []model.Status{model.StatusPending, model.StatusInProgress}
+	if action.Operation == model.ActionStartFirst {
+		eligible = []model.Status{model.StatusPending}
+	}
+	if len(f.Statuses) > 0 {
+		eligible = slices.DeleteFunc(eligible, func(s model.Status) bool { return !slices.Contains(f.Statuses, s) })
+	}
+	f.Statuses = eligible
+
+	sort := f.Sort
+	if sort == "" {
+		sort = "position"
+	}
+	opts, err := query.Params{Limit: 1, Sort: sort}.Options(db.TodoSort)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "invalid action sort", slog.String("error", err.Error()), slog.Int64("id", action.ID))
+		return model.Todo{}, huma.Error500InternalServerError("failed to run action")
+	}
+
+	filter, err := viewTodoFilter(f, time.Now())
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to resolve action filter", slog.String("error", err.Error()), slog.Int64("id", action.ID))
+		return model.Todo{}, huma.Error500InternalServerError("failed to run action")
+	}
+
+	var todos []model.Todo
+	if len(eligible) > 0 {
+		stopDB := timing.Track(ctx, timing.StageDB)
+		todos, err = h.repo.ListTodos(filter, opts)
+		stopDB()
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to list todos", slog.String("error", err.Error()), slog.Int64("id", action.ID))
+			return model.Todo{}, huma.Error500InternalServerError("failed to run action")
+		}
+	}
+	if len(todos) == 0 {
+		return model.Todo{}, huma.Error409Conflict(fmt.Sprintf("no todo matches action %q", action.Name))
+	}
+	return todos[0], nil
+}
+
+// validateAction checks an action payload, including that its filter's sort
+// is one the TODO list could apply.
+func validateAction(req model.ActionRequest) error {
+	if err := badRequest(validate.Action(req)); err != nil {
+		return err
+	}
+	if req.Filter != nil {
+		return checkViewSort(req.Filter.Sort)
+	}
+	return nil
+}
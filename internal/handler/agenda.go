@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/agenda"
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/raster"
+	"todo-service/internal/timing"
+)
+
+// AgendaHandler handles HTTP requests for the plain-text agenda and its
+// rendering for e-paper displays.
+type AgendaHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewAgendaHandler creates a new AgendaHandler.
+func NewAgendaHandler(repo db.Repository, logger *slog.Logger) *AgendaHandler {
+	return &AgendaHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetAgendaInput struct {
+	Width  int    `query:"width" required:"false" minimum:"20" maximum:"200" default:"80" doc:"Line width in characters; longer titles are wrapped"`
+	Locale string `query:"locale" required:"false" enum:"de,en,es,fr" default:"en" doc:"Language of headings and dates"`
+	Days   int    `query:"days" required:"false" minimum:"0" maximum:"31" default:"7" doc:"Number of days after today to list as upcoming"`
+}
+
+type GetAgendaOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+type RenderEInkInput struct {
+	Width  int    `query:"width" required:"false" minimum:"64" maximum:"2048" default:"400" doc:"Image width in pixels"`
+	Height int    `query:"height" required:"false" minimum:"64" maximum:"2048" default:"300" doc:"Image height in pixels"`
+	Scale  int    `query:"scale" required:"false" minimum:"1" maximum:"4" default:"1" doc:"Size of each font pixel in image pixels; characters are 6x9 pixels at scale 1"`
+	Format string `query:"format" required:"false" enum:"png,bmp" default:"png" doc:"Image format, both 1 bit per pixel"`
+	Locale string `query:"locale" required:"false" enum:"de,en,es,fr" default:"en" doc:"Language of headings and dates"`
+	Days   int    `query:"days" required:"false" minimum:"0" maximum:"31" default:"0" doc:"Number of days after today to list as upcoming"`
+}
+
+type RenderEInkOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+// RegisterRoutes registers the agenda routes with the huma API.
+func (h *AgendaHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-agenda",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/agenda.txt",
+		Summary:     "Get a plain-text agenda",
+		Description: "Render the unarchived TODOs scheduled around today (UTC) as plain text, for terminals, screen readers, and small displays: open TODOs overdue from earlier days, all TODOs scheduled today, and TODOs scheduled in the next few days. Each section is in schedule and then manual order. Statuses are marked [ ] pending, [~] in progress, and [x] done.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Plain-text agenda",
+				Content:     map[string]*huma.MediaType{"text/plain": {Schema: &huma.Schema{Type: "string"}}},
+			},
+		},
+	}, h.GetAgenda)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "render-eink",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/render/eink",
+		Summary:     "Render the agenda for an e-paper display",
+		Description: "Render the agenda as a black-and-white image sized for an e-paper display, so a microcontroller can show today's TODOs with a single request and no font or layout code. The text is the same as agenda.txt, wrapped to the image width; if it does not fit, the last line is replaced with an ellipsis. Accented letters are drawn without their accents.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Monochrome image",
+				Content: map[string]*huma.MediaType{
+					"image/png": {Schema: &huma.Schema{Type: "string", Format: "binary"}},
+					"image/bmp": {Schema: &huma.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+		},
+	}, h.RenderEInk)
+}
+
+func (h *AgendaHandler) GetAgenda(ctx context.Context, input *GetAgendaInput) (*GetAgendaOutput, error) {
+	a, err := h.agenda(ctx, input.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := agenda.Render(&buf, a, input.Width, input.Locale); err != nil {
+		h.logger.ErrorContext(ctx, "failed to render agenda", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to render agenda")
+	}
+
+	return &GetAgendaOutput{ContentType: "text/plain; charset=utf-8", Body: buf.Bytes()}, nil
+}
+
+func (h *AgendaHandler) RenderEInk(ctx context.Context, input *RenderEInkInput) (*RenderEInkOutput, error) {
+	cols, rows := raster.Grid(input.Width, input.Height, input.Scale)
+	if cols < agenda.MinWidth || rows < 1 {
+		const msg = "image is too small for the scale; at least 20 characters must fit across"
+		return nil, huma.Error400BadRequest(msg,
+			&huma.ErrorDetail{Location: "query.width", Message: msg, Value: input.Width},
+			&huma.ErrorDetail{Location: "query.scale", Message: msg, Value: input.Scale},
+		)
+	}
+
+	a, err := h.agenda(ctx, input.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := agenda.Lines(a, min(cols, agenda.MaxWidth), input.Locale)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to render agenda", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to render agenda")
+	}
+	if len(lines) > rows {
+		lines = append(lines[:rows-1], "...")
+	}
+
+	img := raster.Text(lines, input.Width, input.Height, input.Scale)
+	var buf bytes.Buffer
+	encode, contentType := raster.EncodePNG, "image/png"
+	if input.Format == "bmp" {
+		encode, contentType = raster.EncodeBMP, "image/bmp"
+	}
+	if err := encode(&buf, img); err != nil {
+		h.logger.ErrorContext(ctx, "failed to encode agenda image", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to render agenda")
+	}
+
+	return &RenderEInkOutput{ContentType: contentType, Body: buf.Bytes()}, nil
+}
+
+// agenda gathers the TODOs overdue, scheduled today, and scheduled in the
+// given number of days after today, in UTC.
+func (h *AgendaHandler) agenda(ctx context.Context, days int) (agenda.Agenda, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	date := today.Format(model.DateLayout)
+	yesterday := today.AddDate(0, 0, -1).Format(model.DateLayout)
+	last := today.AddDate(0, 0, days).Format(model.DateLayout)
+
+	archived := false
+	overdue, err := h.scheduled(ctx, db.TodoFilter{
+		Archived:    &archived,
+		Statuses:    []model.Status{model.StatusPending, model.StatusInProgress},
+		ScheduledTo: &yesterday,
+	})
+	if err != nil {
+		return agenda.Agenda{}, err
+	}
+	upcoming, err := h.scheduled(ctx, db.TodoFilter{
+		Archived:      &archived,
+		ScheduledFrom: &date,
+		ScheduledTo:   &last,
+	})
+	if err != nil {
+		return agenda.Agenda{}, err
+	}
+
+	a := agenda.Agenda{Date: today, Overdue: overdue}
+	for _, t := range upcoming {
+		if *t.ScheduledFor == date {
+			a.Today = append(a.Today, t)
+		} else {
+			a.Upcoming = append(a.Upcoming, t)
+		}
+	}
+	return a, nil
+}
+
+// scheduled lists the TODOs matching filter in agenda order.
+func (h *AgendaHandler) scheduled(ctx context.Context, filter db.TodoFilter) ([]model.Todo, error) {
+	opts, err := query.Params{}.Options(agendaSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountTodos(filter)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count scheduled todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve agenda")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	todos, err := h.repo.ListTodos(filter, opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list scheduled todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve agenda")
+	}
+	return todos, nil
+}
+
+// agendaSort orders an agenda section by day, then by manual order.
+var agendaSort = query.Spec{
+	Columns: db.TodoSort.Columns,
+	Default: []query.Sort{{Field: "scheduled_for"}, {Field: "position"}},
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"todo-service/internal/db"
+	"todo-service/internal/diff"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+)
+
+// AuditHandler handles HTTP requests for the audit log.
+type AuditHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(repo db.Repository, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListAuditInput struct {
+	query.Params
+	Action      string    `query:"action" required:"false" enum:"create,update,delete" doc:"Filter by action"`
+	OperationID string    `query:"operation_id" required:"false" doc:"Only entries written by this operation"`
+	From        time.Time `query:"from" required:"false" doc:"Only entries at or after this time (RFC 3339)"`
+	To          time.Time `query:"to" required:"false" doc:"Only entries at or before this time (RFC 3339)"`
+}
+
+type ListAuditOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of entries matching the filters"`
+	Body       model.AuditListResponse
+}
+
+type GetTodoHistoryInput struct {
+	query.Params
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type GetTodoHistoryOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of entries for the TODO"`
+	Body       model.AuditListResponse
+}
+
+// RegisterRoutes registers all audit routes with the huma API.
+func (h *AuditHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-todo-history",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/todos/{id}/history",
+		Summary:     "Get a TODO's history",
+		Description: "Retrieve every recorded mutation of a TODO item, oldest first. History remains available after the TODO is deleted. Description updates are reported as a word-level diff. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404},
+	}, h.GetTodoHistory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-audit",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/audit",
+		Summary:     "List audit log entries",
+		Description: "Retrieve audit log entries for all TODOs, newest first, optionally filtered by action, operation, and time range. Entries written by the same logical mutation share an operation_id. Supports sorting and limit/offset pagination; unpaginated requests matching more than 5000 entries are rejected.",
+		Tags:        []string{"admin"},
+		Errors:      []int{400},
+	}, h.ListAudit)
+}
+
+func (h *AuditHandler) GetTodoHistory(ctx context.Context, input *GetTodoHistoryInput) (*GetTodoHistoryOutput, error) {
+	opts, err := input.Options(historySort)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := db.AuditFilter{TodoID: &input.ID}
+	entries, total, err := h.list(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if total == 0 {
+		return nil, todoNotFound(input.ID)
+	}
+
+	return &GetTodoHistoryOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.AuditListResponse{Entries: entries, Count: len(entries), Total: total},
+	}, nil
+}
+
+func (h *AuditHandler) ListAudit(ctx context.Context, input *ListAuditInput) (*ListAuditOutput, error) {
+	opts, err := input.Options(db.AuditSort)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter db.AuditFilter
+	if input.Action != "" {
+		a := model.AuditAction(input.Action)
+		filter.Action = &a
+	}
+	if input.OperationID != "" {
+		filter.OperationID = &input.OperationID
+	}
+	if !input.From.IsZero() {
+		filter.From = &input.From
+	}
+	if !input.To.IsZero() {
+		filter.To = &input.To
+	}
+
+	entries, total, err := h.list(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListAuditOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.AuditListResponse{Entries: entries, Count: len(entries), Total: total},
+	}, nil
+}
+
+// historySort lists a single TODO's history oldest first by default.
+var historySort = query.Spec{
+	Columns: db.AuditSort.Columns,
+	Default: []query.Sort{{Field: "id"}},
+}
+
+// list counts and retrieves the audit entries matching filter.
+func (h *AuditHandler) list(ctx context.Context, filter db.AuditFilter, opts query.Options) ([]model.AuditEntry, int, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountAudit(filter)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count audit log", slog.String("error", err.Error()))
+		return nil, 0, huma.Error500InternalServerError("failed to retrieve audit log")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := h.repo.ListAudit(filter, opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list audit log", slog.String("error", err.Error()))
+		return nil, 0, huma.Error500InternalServerError("failed to retrieve audit log")
+	}
+	for _, e := range entries {
+		diffTextChanges(e.Changes)
+	}
+
+	return entries, total, nil
+}
+
+// diffedFields are the text fields whose updates are reported as a diff
+// rather than as two full values.
+var diffedFields = []string{"description"}
+
+// diffTextChanges replaces the old and new values of each diffed field that
+// was changed, not set or cleared by a create or delete, with a word diff.
+func diffTextChanges(changes map[string]model.FieldChange) {
+	for _, name := range diffedFields {
+		old, ok1 := changes[name].Old.(string)
+		cur, ok2 := changes[name].New.(string)
+		if ok1 && ok2 {
+			changes[name] = model.FieldChange{Diff: diff.Words(old, cur)}
+		}
+	}
+}
+
+// auditInfo builds the audit attribution for the request carried by ctx,
+// starting a new operation. Call it once per logical mutation.
+func auditInfo(ctx context.Context) db.AuditInfo {
+	return db.AuditInfo{
+		Actor:       middleware.GetActor(ctx),
+		RequestID:   chimw.GetReqID(ctx),
+		OperationID: db.NewOperationID(),
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/backup"
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// BackupHandler handles HTTP requests for database backups.
+type BackupHandler struct {
+	store  *backup.Store
+	logger *slog.Logger
+}
+
+// NewBackupHandler creates a new BackupHandler.
+func NewBackupHandler(store *backup.Store, logger *slog.Logger) *BackupHandler {
+	return &BackupHandler{store: store, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type CreateBackupOutput struct {
+	Body model.Backup
+}
+
+type ListBackupsOutput struct {
+	Body model.BackupListResponse
+}
+
+type DownloadBackupInput struct {
+	Name string `path:"name" doc:"Backup file name"`
+}
+
+type DownloadBackupOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+type RestoreBackupInput struct {
+	DryRun bool `query:"dry_run" required:"false" doc:"Check the backup and report the changes without restoring it"`
+	Body   model.RestoreRequest
+}
+
+type RestoreBackupOutput struct {
+	Body model.RestoreResponse
+}
+
+// RegisterRoutes registers the backup routes with the huma API.
+func (h *BackupHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-backup",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/admin/backup",
+		Summary:       "Back up the database",
+		Description:   "Write a consistent snapshot of the database to the server's backup directory (-backup-dir) without stopping the service. Other requests wait while it is written.",
+		Tags:          []string{"admin"},
+		Errors:        []int{500},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateBackup)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-backups",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/backups",
+		Summary:     "List backups",
+		Description: "List the backups in the server's backup directory, newest first, both those made on request and those made on the server's schedule (-backup-interval).",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+	}, h.ListBackups)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "download-backup",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/backups/{name}",
+		Summary:     "Download a backup",
+		Description: "Download a backup as a SQLite database file, which can replace the service's database to restore it.",
+		Tags:        []string{"admin"},
+		Errors:      []int{404},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "SQLite database",
+				Content:     map[string]*huma.MediaType{"application/vnd.sqlite3": {Schema: &huma.Schema{Type: "string", Format: "binary"}}},
+			},
+		},
+	}, h.DownloadBackup)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-backup",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/admin/restore",
+		Summary:     "Restore a backup",
+		Description: "Replace the database with a backup without restarting the service. The backup is checked for integrity and migrated to the current schema, and the current database is backed up first so the restore can be undone. Other requests wait while the data is swapped and never see a partly restored database. IDs are not reused, so trigger cursors stay valid. With dry_run=true the backup is only checked, and the row count of each table now and in the backup is reported. Responds 422 if the backup is damaged, is not a database of this service, or comes from a newer version of it.",
+		Tags:        []string{"admin"},
+		Errors:      []int{404, 422},
+	}, h.RestoreBackup)
+}
+
+func (h *BackupHandler) CreateBackup(ctx context.Context, input *struct{}) (*CreateBackupOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	b, err := h.store.Create()
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to back up database", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to back up database")
+	}
+
+	h.logger.InfoContext(ctx, "backed up database", slog.String("name", b.Name), slog.Int64("size_bytes", b.SizeBytes))
+	return &CreateBackupOutput{Body: b}, nil
+}
+
+func (h *BackupHandler) ListBackups(ctx context.Context, input *struct{}) (*ListBackupsOutput, error) {
+	backups, err := h.store.List()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list backups", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to list backups")
+	}
+
+	return &ListBackupsOutput{Body: model.BackupListResponse{Backups: backups, Count: len(backups)}}, nil
+}
+
+func (h *BackupHandler) DownloadBackup(ctx context.Context, input *DownloadBackupInput) (*DownloadBackupOutput, error) {
+	path, err := h.store.Path(input.Name)
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(path)
+	}
+	if errors.Is(err, backup.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("backup %s not found", input.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to read backup", slog.String("error", err.Error()), slog.String("name", input.Name))
+		return nil, huma.Error500InternalServerError("failed to read backup")
+	}
+
+	return &DownloadBackupOutput{
+		ContentType:        "application/vnd.sqlite3",
+		ContentDisposition: fmt.Sprintf("attachment; filename=%q", input.Name),
+		Body:               data,
+	}, nil
+}
+
+func (h *BackupHandler) RestoreBackup(ctx context.Context, input *RestoreBackupInput) (*RestoreBackupOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	res, err := h.store.Restore(input.Body.Backup, input.DryRun)
+	stopDB()
+	if errors.Is(err, backup.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("backup %s not found", input.Body.Backup))
+	}
+	if errors.Is(err, db.ErrInvalidSnapshot) {
+		return nil, huma.Error422UnprocessableEntity(err.Error())
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to restore backup", slog.String("error", err.Error()), slog.String("name", input.Body.Backup))
+		return nil, huma.Error500InternalServerError("failed to restore backup")
+	}
+
+	if !res.DryRun {
+		h.logger.WarnContext(ctx, "restored database from backup", slog.String("name", res.Backup), slog.String("safety_backup", res.SafetyBackup))
+	}
+	return &RestoreBackupOutput{Body: res}, nil
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// BoardHandler handles HTTP requests for the kanban board.
+type BoardHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+	limits model.WIPLimits
+}
+
+// NewBoardHandler creates a new BoardHandler that caps its columns at
+// limits.
+func NewBoardHandler(repo db.Repository, logger *slog.Logger, limits model.WIPLimits) *BoardHandler {
+	return &BoardHandler{repo: repo, logger: logger, limits: limits}
+}
+
+// --- Input/Output types for huma ---
+
+type GetBoardInput struct {
+	Category  string `query:"category" required:"false" doc:"Only show TODOs in this category"`
+	ProjectID int64  `query:"project_id" required:"false" doc:"Only show TODOs in this project"`
+	Limit     int    `query:"limit" required:"false" minimum:"1" maximum:"1000" default:"100" doc:"Maximum number of TODOs to return per column; counts include the rest"`
+}
+
+type GetBoardOutput struct {
+	Body model.BoardResponse
+}
+
+type MoveCardInput struct {
+	Body model.MoveCardRequest
+}
+
+type MoveCardOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+// RegisterRoutes registers the board routes with the huma API.
+func (h *BoardHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-board",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/board",
+		Summary:     "Get the kanban board",
+		Description: "List active TODOs in one column per status, each in manual order, with the number of TODOs in the column and its WIP limit if the server sets one (-wip-limits). A column over its limit, which can happen if the limit was lowered, is flagged.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400},
+	}, h.GetBoard)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "move-card",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/board/move",
+		Summary:     "Move a card on the board",
+		Description: "Change an active TODO's status and its place in the target column in one step, for dragging a card between columns. The status change follows the same rules as an update. Responds 409 if the TODO is archived, the change is not allowed, or the target column is at its WIP limit, and 422 if the TODO to place it next to does not exist.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400, 404, 409, 422},
+	}, h.MoveCard)
+}
+
+func (h *BoardHandler) GetBoard(ctx context.Context, input *GetBoardInput) (*GetBoardOutput, error) {
+	opts, err := query.Params{Limit: input.Limit}.Options(boardSort)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, snoozed := false, false
+	filter := db.TodoFilter{Archived: &archived, Snoozed: &snoozed}
+	if input.Category != "" {
+		c := model.Category(input.Category)
+		filter.Category = &c
+	}
+	if input.ProjectID != 0 {
+		filter.ProjectID = &input.ProjectID
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	var board model.BoardResponse
+	for _, status := range []model.Status{model.StatusPending, model.StatusInProgress, model.StatusDone} {
+		filter.Status = &status
+		count, err := h.repo.CountTodos(filter)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to count board todos", slog.String("error", err.Error()))
+			return nil, huma.Error500InternalServerError("failed to retrieve board")
+		}
+		todos, err := h.repo.ListTodos(filter, opts)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to list board todos", slog.String("error", err.Error()))
+			return nil, huma.Error500InternalServerError("failed to retrieve board")
+		}
+
+		column := model.BoardColumn{Status: status, Count: count, Todos: todos}
+		if limit, ok := h.limits[status]; ok {
+			column.WIPLimit = &limit
+			column.OverLimit = count > limit
+		}
+		board.Columns = append(board.Columns, column)
+	}
+
+	return &GetBoardOutput{Body: board}, nil
+}
+
+func (h *BoardHandler) MoveCard(ctx context.Context, input *MoveCardInput) (*MoveCardOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.MoveCard(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+	place, _ := input.Body.Place()
+	target := moveTarget(place)
+	if target == input.Body.TodoID {
+		const msg = "a todo cannot be moved relative to itself"
+		return nil, huma.Error400BadRequest(msg, &huma.ErrorDetail{Location: "body", Message: msg, Value: target})
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.MoveCard(input.Body, h.limits[input.Body.Status], info)
+	stopDB()
+	var transitionErr *db.TransitionError
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, todoNotFound(input.Body.TodoID)
+	case errors.Is(err, db.ErrMoveTargetNotFound):
+		return nil, withCode(model.CodeTodoNotFound, huma.Error422UnprocessableEntity(fmt.Sprintf("todo with id %d not found", target)))
+	case errors.Is(err, db.ErrTodoArchived):
+		return nil, huma.Error409Conflict("todo is archived; unarchive it first")
+	case errors.Is(err, db.ErrWIPLimit):
+		return nil, huma.Error409Conflict(fmt.Sprintf("the %s column is at its WIP limit of %d", input.Body.Status, h.limits[input.Body.Status]))
+	case errors.As(err, &transitionErr):
+		return nil, transitionConflict(transitionErr)
+	case err != nil:
+		h.logger.ErrorContext(ctx, "failed to move card", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.Body.TodoID))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &MoveCardOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+// boardSort orders each board column by manual order, pinned TODOs first.
+var boardSort = query.Spec{
+	Columns: db.TodoSort.Columns,
+	Default: []query.Sort{{Field: "position"}},
+	Leading: db.TodoSort.Leading,
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// CalendarHandler handles HTTP requests for the monthly calendar view.
+type CalendarHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewCalendarHandler creates a new CalendarHandler.
+func NewCalendarHandler(repo db.Repository, logger *slog.Logger) *CalendarHandler {
+	return &CalendarHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetCalendarInput struct {
+	Month string `query:"month" required:"false" doc:"Month to summarize, formatted as YYYY-MM (defaults to the current month, UTC)" example:"2026-02"`
+}
+
+type GetCalendarOutput struct {
+	Body model.CalendarResponse
+}
+
+// RegisterRoutes registers the calendar routes with the huma API.
+func (h *CalendarHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-calendar",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/calendar",
+		Summary:     "Get a month of due and scheduled TODO counts",
+		Description: "Count the unarchived TODOs due and those scheduled on each day of a month, open and done, with their IDs, so calendar widgets can shade busy days without fetching every TODO. Fetch a day's TODOs with the week view or by ID.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400},
+	}, h.GetCalendar)
+}
+
+func (h *CalendarHandler) GetCalendar(ctx context.Context, input *GetCalendarInput) (*GetCalendarOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	month := input.Month
+	if month == "" {
+		month = time.Now().UTC().Format(model.MonthLayout)
+	}
+	err := invalidInput("query", validate.Month("month", month))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	first, _ := time.Parse(model.MonthLayout, month)
+	var days []model.CalendarDay
+	index := map[string]int{}
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		date := d.Format(model.DateLayout)
+		index[date] = len(days)
+		days = append(days, model.CalendarDay{
+			Date:      date,
+			Due:       model.CalendarCount{IDs: []int64{}},
+			Scheduled: model.CalendarCount{IDs: []int64{}},
+		})
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListCalendar(days[0].Date, days[len(days)-1].Date)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list calendar todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve calendar")
+	}
+
+	for _, t := range todos {
+		count := &days[index[t.Date]].Scheduled
+		if t.Due {
+			count = &days[index[t.Date]].Due
+		}
+		count.Total++
+		if t.Status == model.StatusDone {
+			count.Done++
+		} else {
+			count.Open++
+		}
+		count.IDs = append(count.IDs, t.ID)
+	}
+
+	return &GetCalendarOutput{Body: model.CalendarResponse{Month: month, Days: days}}, nil
+}
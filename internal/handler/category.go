@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// CategoryHandler handles HTTP requests for category operations.
+type CategoryHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(repo db.Repository, logger *slog.Logger) *CategoryHandler {
+	return &CategoryHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListCategoriesInput struct {
+	query.Params
+}
+
+type ListCategoriesOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of categories"`
+	Body       model.CategoryListResponse
+}
+
+type CreateCategoryInput struct {
+	Body model.CreateCategoryRequest
+}
+
+type CategoryOutput struct {
+	Body model.CategoryInfo
+}
+
+type GetCategoryInput struct {
+	ID int64 `path:"id" doc:"Category ID" example:"1"`
+}
+
+type UpdateCategoryInput struct {
+	ID   int64 `path:"id" doc:"Category ID" example:"1"`
+	Body model.UpdateCategoryRequest
+}
+
+type DeleteCategoryInput struct {
+	ID         int64  `path:"id" doc:"Category ID" example:"1"`
+	ReassignTo string `query:"reassign_to" required:"false" doc:"Name of the category to move the category's TODOs to; required if it has any"`
+}
+
+// RegisterRoutes registers all category routes with the huma API.
+func (h *CategoryHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-categories",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/categories",
+		Summary:     "List all categories",
+		Description: "Retrieve all categories with the number of TODOs in each, sorted by sort order and then name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"categories"},
+		Errors:      []int{400},
+	}, h.ListCategories)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-category",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/categories",
+		Summary:       "Create a new category",
+		Description:   "Create a new category TODOs can be filed under. Category names must be unique.",
+		Tags:          []string{"categories"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-category",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/categories/{id}",
+		Summary:     "Get a category by ID",
+		Description: "Retrieve a single category with the number of TODOs in it.",
+		Tags:        []string{"categories"},
+		Errors:      []int{404},
+	}, h.GetCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-category",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/categories/{id}",
+		Summary:     "Update a category",
+		Description: "Update an existing category. Only provided fields are changed. Renaming a category renames it on its TODOs, views, and actions, and each TODO is recorded in the audit log under one operation.",
+		Tags:        []string{"categories"},
+		Errors:      []int{400, 404, 409},
+	}, h.UpdateCategory)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-category",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/categories/{id}",
+		Summary:       "Delete a category",
+		Description:   "Delete a category. A category with TODOs can only be deleted by moving them to another category with reassign_to; every moved TODO is recorded in the audit log under one operation. Views and actions refer to reassign_to instead; without it, the category is dropped from their filters, filters left without a category are deleted, and create actions make their TODOs in the default category.",
+		Tags:          []string{"categories"},
+		Errors:        []int{404, 409, 422},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteCategory)
+}
+
+func (h *CategoryHandler) ListCategories(ctx context.Context, input *ListCategoriesInput) (*ListCategoriesOutput, error) {
+	opts, err := input.Options(db.CategorySort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountCategories()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count categories", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve categories")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	categories, err := h.repo.ListCategories(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list categories", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve categories")
+	}
+
+	return &ListCategoriesOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.CategoryListResponse{Categories: categories, Count: len(categories), Total: total},
+	}, nil
+}
+
+func (h *CategoryHandler) CreateCategory(ctx context.Context, input *CreateCategoryInput) (*CategoryOutput, error) {
+	if err := badRequest(validate.CreateCategory(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	category, err := h.repo.CreateCategory(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrCategoryExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("category %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create category")
+	}
+
+	return &CategoryOutput{Body: category}, nil
+}
+
+func (h *CategoryHandler) GetCategory(ctx context.Context, input *GetCategoryInput) (*CategoryOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	category, err := h.repo.GetCategory(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("category with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get category", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve category")
+	}
+
+	return &CategoryOutput{Body: category}, nil
+}
+
+func (h *CategoryHandler) UpdateCategory(ctx context.Context, input *UpdateCategoryInput) (*CategoryOutput, error) {
+	if err := badRequest(validate.UpdateCategory(input.Body)); err != nil {
+		return nil, err
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	category, err := h.repo.UpdateCategory(input.ID, input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("category with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrCategoryExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("category %q already exists", *input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update category")
+	}
+
+	return &CategoryOutput{Body: category}, nil
+}
+
+func (h *CategoryHandler) DeleteCategory(ctx context.Context, input *DeleteCategoryInput) (*struct{}, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	n, err := h.repo.DeleteCategory(input.ID, model.Category(input.ReassignTo), info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("category with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrCategoryInUse) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("category with id %d still has todos; move them with reassign_to", input.ID))
+	}
+	if errors.Is(err, db.ErrCategoryNotFound) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("cannot reassign todos to category %q", input.ReassignTo))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete category", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete category")
+	}
+
+	h.logger.InfoContext(ctx, "deleted category",
+		slog.Int64("id", input.ID),
+		slog.Int("moved", n),
+		slog.String("operation_id", info.OperationID),
+	)
+	return nil, nil
+}
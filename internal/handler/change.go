@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// ChangeHandler handles HTTP requests for the change feed.
+type ChangeHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewChangeHandler creates a new ChangeHandler.
+func NewChangeHandler(repo db.Repository, logger *slog.Logger) *ChangeHandler {
+	return &ChangeHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListChangesInput struct {
+	Since int64 `query:"since" required:"false" minimum:"0" doc:"Cursor of the last page read; omit or send 0 to read every TODO from the start"`
+	Limit int   `query:"limit" required:"false" minimum:"1" maximum:"1000" default:"500" doc:"Maximum number of changes to return"`
+}
+
+type ListChangesOutput struct {
+	Body model.ChangeFeedResponse
+}
+
+// RegisterRoutes registers the change feed routes with the huma API.
+func (h *ChangeHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-changes",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/changes",
+		Summary:     "List changes since a cursor",
+		Description: "Read the TODOs created, updated, or deleted after a cursor, for clients that keep a copy of the data and sync it incrementally. Each changed TODO appears once, at the sequence number of its latest change: an upsert with its current state, archived or not, or a delete tombstone. Changes are in sequence order, and the response's cursor is sent back as since to read the next page; when has_more is false the client is up to date. Reading from 0 returns every TODO, and the tombstones of deleted ones.",
+		Tags:        []string{"sync"},
+		Errors:      []int{400},
+	}, h.ListChanges)
+}
+
+func (h *ChangeHandler) ListChanges(ctx context.Context, input *ListChangesInput) (*ListChangesOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	changes, more, err := h.repo.Changes(input.Since, input.Limit)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list changes", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve changes")
+	}
+
+	cursor := input.Since
+	if len(changes) > 0 {
+		cursor = changes[len(changes)-1].Seq
+	}
+	return &ListChangesOutput{Body: model.ChangeFeedResponse{Changes: changes, Cursor: cursor, HasMore: more}}, nil
+}
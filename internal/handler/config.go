@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/manifest"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// ConfigHandler handles HTTP requests for exporting and applying the
+// service's configuration as a YAML manifest.
+type ConfigHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewConfigHandler creates a new ConfigHandler.
+func NewConfigHandler(repo db.Repository, logger *slog.Logger) *ConfigHandler {
+	return &ConfigHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ExportConfigOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+type ApplyConfigInput struct {
+	DryRun  bool   `query:"dry_run" required:"false" doc:"Report the changes without writing them"`
+	RawBody []byte `contentType:"application/yaml"`
+}
+
+type ApplyConfigOutput struct {
+	Body model.ApplyConfigResponse
+}
+
+// RegisterRoutes registers the configuration routes with the huma API.
+func (h *ConfigHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "export-config",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/config",
+		Summary:     "Export configuration",
+		Description: "Export the service's configuration, currently its projects, as a YAML manifest. TODO data is not included.",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "YAML manifest",
+				Content:     map[string]*huma.MediaType{"application/yaml": {Schema: &huma.Schema{Type: "string"}}},
+			},
+		},
+	}, h.ExportConfig)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "apply-config",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/admin/config",
+		Summary:     "Apply configuration",
+		Description: "Apply a YAML manifest in one transaction. Entities are matched by name: missing ones are created and existing ones updated to match. Entities absent from the manifest are left alone. With dry_run=true the changes are reported without being written.",
+		Tags:        []string{"admin"},
+		Errors:      []int{400},
+	}, h.ApplyConfig)
+}
+
+func (h *ConfigHandler) ExportConfig(ctx context.Context, input *struct{}) (*ExportConfigOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	m, err := manifest.Export(h.repo)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to export config", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to export config")
+	}
+
+	data, err := m.Marshal()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to encode config", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to export config")
+	}
+
+	return &ExportConfigOutput{ContentType: "application/yaml", Body: data}, nil
+}
+
+func (h *ConfigHandler) ApplyConfig(ctx context.Context, input *ApplyConfigInput) (*ApplyConfigOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	m, err := manifest.Parse(input.RawBody)
+	stopValidation()
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	result, err := manifest.Apply(h.repo, m, input.DryRun)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to apply config", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to apply config")
+	}
+
+	if !input.DryRun {
+		h.logger.InfoContext(ctx, "config applied",
+			slog.Int("projects_created", len(result.Projects.Created)),
+			slog.Int("projects_updated", len(result.Projects.Updated)),
+		)
+	}
+	return &ApplyConfigOutput{Body: result}, nil
+}
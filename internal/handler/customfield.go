@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// CustomFieldHandler handles HTTP requests for custom field definitions.
+type CustomFieldHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewCustomFieldHandler creates a new CustomFieldHandler.
+func NewCustomFieldHandler(repo db.Repository, logger *slog.Logger) *CustomFieldHandler {
+	return &CustomFieldHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListCustomFieldsInput struct {
+	query.Params
+}
+
+type ListCustomFieldsOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of custom fields"`
+	Body       model.CustomFieldListResponse
+}
+
+type CreateCustomFieldInput struct {
+	Body model.CreateCustomFieldRequest
+}
+
+type CustomFieldOutput struct {
+	Body model.CustomField
+}
+
+type CustomFieldIDInput struct {
+	ID int64 `path:"id" doc:"Custom field ID" example:"1"`
+}
+
+type UpdateCustomFieldInput struct {
+	ID   int64 `path:"id" doc:"Custom field ID" example:"1"`
+	Body model.UpdateCustomFieldRequest
+}
+
+// RegisterRoutes registers all custom field routes with the huma API.
+func (h *CustomFieldHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-custom-fields",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/custom-fields",
+		Summary:     "List all custom fields",
+		Description: "Retrieve every custom field definition with the number of TODOs holding a value for it, sorted by name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"custom-fields"},
+		Errors:      []int{400},
+	}, h.ListCustomFields)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-custom-field",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/custom-fields",
+		Summary:       "Define a custom field",
+		Description:   "Define a piece of metadata TODOs can carry: text, a number, a date, or one of an enum's options. TODOs set values in custom_fields, keyed by the field's name, when they are created or updated, and are listed by value with custom_fields[name]=value. Field names must be unique and cannot be changed.",
+		Tags:          []string{"custom-fields"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateCustomField)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-custom-field",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/custom-fields/{id}",
+		Summary:     "Get a custom field by ID",
+		Tags:        []string{"custom-fields"},
+		Errors:      []int{404},
+	}, h.GetCustomField)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-custom-field",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/custom-fields/{id}",
+		Summary:     "Update a custom field",
+		Description: "Change the options of an enum field. Responds 409 if an option TODOs hold would be removed, and 422 if the field is not an enum.",
+		Tags:        []string{"custom-fields"},
+		Errors:      []int{400, 404, 409, 422},
+	}, h.UpdateCustomField)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-custom-field",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/custom-fields/{id}",
+		Summary:       "Delete a custom field",
+		Description:   "Delete a custom field and remove its values from every TODO. Each TODO that held a value is recorded in the audit log under one operation.",
+		Tags:          []string{"custom-fields"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteCustomField)
+}
+
+func (h *CustomFieldHandler) ListCustomFields(ctx context.Context, input *ListCustomFieldsInput) (*ListCustomFieldsOutput, error) {
+	opts, err := input.Options(db.CustomFieldSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountCustomFields()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count custom fields", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve custom fields")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	fields, err := h.repo.ListCustomFields(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list custom fields", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve custom fields")
+	}
+
+	return &ListCustomFieldsOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.CustomFieldListResponse{CustomFields: fields, Count: len(fields), Total: total},
+	}, nil
+}
+
+func (h *CustomFieldHandler) CreateCustomField(ctx context.Context, input *CreateCustomFieldInput) (*CustomFieldOutput, error) {
+	if err := badRequest(validate.CreateCustomField(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	field, err := h.repo.CreateCustomField(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrCustomFieldExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("custom field %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create custom field", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create custom field")
+	}
+
+	return &CustomFieldOutput{Body: field}, nil
+}
+
+func (h *CustomFieldHandler) GetCustomField(ctx context.Context, input *CustomFieldIDInput) (*CustomFieldOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	field, err := h.repo.GetCustomField(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, customFieldNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get custom field", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve custom field")
+	}
+
+	return &CustomFieldOutput{Body: field}, nil
+}
+
+func (h *CustomFieldHandler) UpdateCustomField(ctx context.Context, input *UpdateCustomFieldInput) (*CustomFieldOutput, error) {
+	if err := badRequest(validate.UpdateCustomField(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	field, err := h.repo.UpdateCustomField(input.ID, input.Body)
+	stopDB()
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, customFieldNotFound(input.ID)
+	case errors.Is(err, db.ErrCustomFieldNotEnum):
+		return nil, huma.Error422UnprocessableEntity("only enum fields have options")
+	case errors.Is(err, db.ErrCustomFieldOptionInUse):
+		return nil, huma.Error409Conflict("an option being removed is held by todos; change their values first")
+	case err != nil:
+		h.logger.ErrorContext(ctx, "failed to update custom field", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update custom field")
+	}
+
+	return &CustomFieldOutput{Body: field}, nil
+}
+
+func (h *CustomFieldHandler) DeleteCustomField(ctx context.Context, input *CustomFieldIDInput) (*struct{}, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	n, err := h.repo.DeleteCustomField(input.ID, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, customFieldNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete custom field", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete custom field")
+	}
+
+	h.logger.InfoContext(ctx, "deleted custom field",
+		slog.Int64("id", input.ID),
+		slog.Int("todos", n),
+		slog.String("operation_id", info.OperationID),
+	)
+	return nil, nil
+}
+
+func customFieldNotFound(id int64) error {
+	return huma.Error404NotFound(fmt.Sprintf("custom field with id %d not found", id))
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// DatabaseHandler handles HTTP requests for database upkeep.
+type DatabaseHandler struct {
+	repo        db.Repository
+	maintenance *middleware.Maintenance
+	logger      *slog.Logger
+}
+
+// NewDatabaseHandler creates a new DatabaseHandler whose disruptive
+// operations require m to be read-only.
+func NewDatabaseHandler(repo db.Repository, m *middleware.Maintenance, logger *slog.Logger) *DatabaseHandler {
+	return &DatabaseHandler{repo: repo, maintenance: m, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type VacuumOutput struct {
+	Body model.VacuumResponse
+}
+
+type IntegrityOutput struct {
+	Body model.IntegrityReport
+}
+
+type CheckpointOutput struct {
+	Body model.CheckpointResponse
+}
+
+// RegisterRoutes registers the database routes with the huma API.
+func (h *DatabaseHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "vacuum-database",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/admin/db/vacuum",
+		Summary:     "Vacuum the database",
+		Description: "Rebuild the database file, reclaiming the space deleted rows leave behind (see free_bytes in the usage report) and defragmenting it, and report its size before and after. Every other request waits until it finishes, so it may only run in read-only maintenance mode; responds 409 otherwise.",
+		Tags:        []string{"admin"},
+		Errors:      []int{409},
+	}, h.Vacuum)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "check-database-integrity",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/db/integrity",
+		Summary:     "Check the database's integrity",
+		Description: "Scan the whole database for corruption: malformed pages and records, missing or extra index entries, and broken constraints. Corruption is reported in the response rather than as an error. The server also checks on a schedule (-integrity-check-interval), alerting notification routes notified of corruption events when a check fails.",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+	}, h.CheckIntegrity)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "checkpoint-database",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/admin/db/checkpoint",
+		Summary:     "Checkpoint the write-ahead log",
+		Description: "Copy the changes in the database's write-ahead log into the database file and truncate the log. The server also does this on its own once the log reaches -wal-checkpoint-size, checking every -wal-checkpoint-interval. If another process has the database open and is using it, the checkpoint does what it can and reports busy.",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+	}, h.Checkpoint)
+}
+
+func (h *DatabaseHandler) Vacuum(ctx context.Context, input *struct{}) (*VacuumOutput, error) {
+	if !h.maintenance.Mode().ReadOnly {
+		return nil, huma.Error409Conflict("vacuuming requires read-only maintenance mode; turn it on with PUT /api/v1/admin/maintenance")
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	resp, err := h.repo.Vacuum()
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to vacuum database", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to vacuum database")
+	}
+
+	h.logger.InfoContext(ctx, "vacuumed database",
+		slog.Int64("bytes_before", resp.BytesBefore),
+		slog.Int64("bytes_after", resp.BytesAfter),
+		slog.Float64("duration_ms", resp.DurationMs),
+	)
+	return &VacuumOutput{Body: resp}, nil
+}
+
+func (h *DatabaseHandler) Checkpoint(ctx context.Context, input *struct{}) (*CheckpointOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	resp, err := h.repo.Checkpoint()
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to checkpoint database", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to checkpoint database")
+	}
+
+	return &CheckpointOutput{Body: resp}, nil
+}
+
+func (h *DatabaseHandler) CheckIntegrity(ctx context.Context, input *struct{}) (*IntegrityOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	report, err := h.repo.CheckIntegrity()
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to check database integrity", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to check database integrity")
+	}
+	if !report.OK {
+		h.logger.ErrorContext(ctx, "database integrity check failed", slog.Int("problems", len(report.Problems)))
+	}
+
+	return &IntegrityOutput{Body: report}, nil
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/digest"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// DigestHandler handles HTTP requests for the email digest.
+type DigestHandler struct {
+	mailer *digest.Mailer
+	logger *slog.Logger
+}
+
+// NewDigestHandler creates a new DigestHandler.
+func NewDigestHandler(mailer *digest.Mailer, logger *slog.Logger) *DigestHandler {
+	return &DigestHandler{mailer: mailer, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type DigestOutput struct {
+	Body model.Digest
+}
+
+// RegisterRoutes registers all digest routes with the huma API.
+func (h *DigestHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-digest",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/reports/digest",
+		Summary:     "Preview the email digest",
+		Description: "Build the email digest as it would be sent now: open TODOs due before today (UTC), open TODOs due from today through the server's due-soon days, and TODOs completed since the last digest was sent, or within the last day or week if none was. It is returned with the subject and plain-text body of the email, and nothing is sent.",
+		Tags:        []string{"reports"},
+	}, h.GetDigest)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "send-digest",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/reports/digest/send",
+		Summary:     "Send the email digest now",
+		Description: "Email the digest to the server's recipients immediately and return it. The next digest lists TODOs completed after this one; its schedule is not affected. It takes no request body. Responds 404 if the server has no SMTP server or recipients configured, and 502 if the email cannot be sent.",
+		Tags:        []string{"reports"},
+		Errors:      []int{404, 502},
+	}, h.SendDigest)
+}
+
+func (h *DigestHandler) GetDigest(ctx context.Context, input *struct{}) (*DigestOutput, error) {
+	d, err := h.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DigestOutput{Body: d}, nil
+}
+
+func (h *DigestHandler) SendDigest(ctx context.Context, input *struct{}) (*DigestOutput, error) {
+	if !h.mailer.Config().Enabled() {
+		return nil, huma.Error404NotFound("email digest is not configured")
+	}
+	d, err := h.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.mailer.Send(ctx, d); err != nil {
+		h.logger.WarnContext(ctx, "failed to send digest", slog.String("error", err.Error()))
+		return nil, huma.Error502BadGateway(fmt.Sprintf("failed to send digest: %s", err))
+	}
+	return &DigestOutput{Body: d}, nil
+}
+
+func (h *DigestHandler) build(ctx context.Context) (model.Digest, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	d, err := h.mailer.Build(time.Now())
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to build digest", slog.String("error", err.Error()))
+		return model.Digest{}, huma.Error500InternalServerError("failed to build digest")
+	}
+	return d, nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/model"
+)
+
+// ErrorModel is the body of every error response: huma's problem details
+// with a code from the catalog in model.ErrorCodes.
+type ErrorModel struct {
+	huma.ErrorModel
+	Code model.ErrorCode `json:"code" example:"TODO_NOT_FOUND" doc:"Kind of problem, from the error code catalog in the API description"`
+}
+
+// TransformSchema documents the codes of the catalog as the code's enum, so
+// the schema cannot fall behind it.
+func (e *ErrorModel) TransformSchema(r huma.Registry, s *huma.Schema) *huma.Schema {
+	if code := s.Properties["code"]; code != nil {
+		code.Enum = nil
+		for _, c := range model.ErrorCodes {
+			code.Enum = append(code.Enum, string(c.Code))
+		}
+	}
+	return s
+}
+
+// humaNewError is huma's own error constructor, which NewError wraps.
+var humaNewError = huma.NewError
+
+// NewError creates the errors huma.ErrorXXX functions return, coded by
+// their status. Set huma.NewError to it before registering operations, so
+// their documented error responses include the code.
+func NewError(status int, msg string, errs ...error) huma.StatusError {
+	err := &ErrorModel{Code: model.StatusErrorCode(status)}
+	if base, ok := humaNewError(status, msg, errs...).(*huma.ErrorModel); ok {
+		err.ErrorModel = *base
+	}
+	return err
+}
+
+// withCode replaces the code of an error made by NewError with a more
+// specific one.
+func withCode(code model.ErrorCode, err error) error {
+	if e, ok := err.(*ErrorModel); ok {
+		e.Code = code
+	}
+	return err
+}
+
+// todoNotFound reports that the TODO with id, named in the request path,
+// does not exist.
+func todoNotFound(id int64) error {
+	return withCode(model.CodeTodoNotFound, huma.Error404NotFound(fmt.Sprintf("todo with id %d not found", id)))
+}
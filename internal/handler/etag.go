@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/model"
+)
+
+// etag returns the strong entity tag for the current version of t.
+func etag(t model.Todo) string {
+	return fmt.Sprintf(`"%d"`, t.Version)
+}
+
+// ifMatchVersion parses an If-Match header into the version it requires.
+// The wildcard * matches any version and yields 0. A missing header is
+// rejected with 428 so that clients cannot silently overwrite each other.
+func ifMatchVersion(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, huma.Error428PreconditionRequired("If-Match header is required; send the ETag from a previous GET, or * to skip the check")
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version <= 0 || strings.Contains(header, ",") {
+		const msg = "If-Match must be * or a single ETag returned by this API"
+		return 0, huma.Error400BadRequest(msg, &huma.ErrorDetail{Location: "header.If-Match", Message: msg, Value: header})
+	}
+	return version, nil
+}
+
+// listETag returns a weak entity tag identifying a page of todos by the IDs
+// and versions it contains and the total number of matches.
+func listETag(todos []model.Todo, total int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d;", total)
+	for _, t := range todos {
+		fmt.Fprintf(h, "%d:%d,", t.ID, t.Version)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum(nil)[:16])
+}
+
+// CacheParams are the conditional GET headers honored by read endpoints.
+type CacheParams struct {
+	IfNoneMatch     string    `header:"If-None-Match" doc:"Respond 304 Not Modified if the current ETag matches one of these values"`
+	IfModifiedSince time.Time `header:"If-Modified-Since" doc:"Respond 304 Not Modified if the resource has not changed since this time; ignored when If-None-Match is sent"`
+}
+
+// notModified returns a 304 response carrying the current validators if the
+// client's cached copy is still fresh, following RFC 9110 section 13.2.2:
+// If-None-Match takes precedence and uses weak comparison, and
+// If-Modified-Since is only evaluated when If-None-Match is absent.
+func (p CacheParams) notModified(etag string, modified time.Time) error {
+	fresh := false
+	if p.IfNoneMatch != "" {
+		for _, tag := range strings.Split(p.IfNoneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || weakETag(tag) == weakETag(etag) {
+				fresh = true
+				break
+			}
+		}
+	} else if !p.IfModifiedSince.IsZero() && !modified.IsZero() {
+		fresh = !modified.Truncate(time.Second).After(p.IfModifiedSince)
+	}
+
+	if !fresh {
+		return nil
+	}
+
+	headers := http.Header{}
+	headers.Set("ETag", etag)
+	if !modified.IsZero() {
+		headers.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	return huma.ErrorWithHeaders(huma.Status304NotModified(), headers)
+}
+
+// weakETag strips the weak indicator so tags can be compared weakly.
+func weakETag(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// StreamThreshold is the number of list items above which responses are
+// streamed rather than encoded in memory.
+const StreamThreshold = 500
+
+// StreamingJSONFormat returns a JSON format that behaves like
+// huma.DefaultJSONFormat, except that struct bodies holding more than
+// threshold slice elements are written one element at a time instead of
+// being encoded into a single in-memory buffer first. Building with the
+// jsoniter tag swaps encoding/json for json-iterator.
+func StreamingJSONFormat(threshold int) huma.Format {
+	return huma.Format{
+		Marshal: func(w io.Writer, v any) error {
+			rv := reflect.Indirect(reflect.ValueOf(v))
+			if rv.Kind() == reflect.Struct && streamable(rv.Type()) && sliceElements(rv) > threshold {
+				return streamStruct(w, rv)
+			}
+			enc := newJSONEncoder(w)
+			enc.SetEscapeHTML(false)
+			return enc.Encode(v)
+		},
+		Unmarshal: unmarshalJSON,
+	}
+}
+
+// streamable reports whether every field of t can be encoded by streamStruct.
+// Embedded fields are flattened by encoding/json and are not supported.
+func streamable(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			return false
+		}
+	}
+	return true
+}
+
+func sliceElements(rv reflect.Value) int {
+	n := 0
+	for i := 0; i < rv.NumField(); i++ {
+		if f := rv.Field(i); f.Kind() == reflect.Slice {
+			n += f.Len()
+		}
+	}
+	return n
+}
+
+// streamStruct writes rv as a JSON object, encoding slice fields element by
+// element through a buffered writer.
+func streamStruct(w io.Writer, rv reflect.Value) error {
+	bw := bufio.NewWriterSize(w, 32*1024)
+	enc := newElementEncoder()
+
+	bw.WriteByte('{')
+	first := true
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty := parseJSONTag(field)
+		if name == "-" {
+			continue
+		}
+		value := rv.Field(i)
+		if omitEmpty && isEmptyValue(value) {
+			continue
+		}
+
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+
+		key, err := enc.encode(name)
+		if err != nil {
+			return err
+		}
+		bw.Write(key)
+		bw.WriteByte(':')
+
+		if value.Kind() != reflect.Slice || value.IsNil() || value.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := enc.encode(value.Interface())
+			if err != nil {
+				return err
+			}
+			bw.Write(b)
+			continue
+		}
+
+		bw.WriteByte('[')
+		for j := 0; j < value.Len(); j++ {
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			b, err := enc.encode(value.Index(j).Interface())
+			if err != nil {
+				return err
+			}
+			if _, err := bw.Write(b); err != nil {
+				return err
+			}
+		}
+		bw.WriteByte(']')
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// elementEncoder encodes single values with the same settings as
+// huma.DefaultJSONFormat, reusing one buffer and encoder between calls.
+type elementEncoder struct {
+	buf *bytes.Buffer
+	enc jsonEncoder
+}
+
+func newElementEncoder() *elementEncoder {
+	buf := &bytes.Buffer{}
+	enc := newJSONEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return &elementEncoder{buf: buf, enc: enc}
+}
+
+func (e *elementEncoder) encode(v any) ([]byte, error) {
+	e.buf.Reset()
+	if err := e.enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(e.buf.Bytes(), []byte("\n")), nil
+}
+
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty bool) {
+	tag := field.Tag.Get("json")
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
+
+// isEmptyValue mirrors the omitempty rules of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
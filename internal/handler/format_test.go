@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// listResponse returns a TODO list response of n TODOs.
+func listResponse(n int) *model.TodoListResponse {
+	created := time.Date(2026, 2, 12, 15, 4, 5, 0, time.UTC)
+	todos := make([]model.Todo, n)
+	for i := range todos {
+		todos[i] = model.Todo{
+			ID:              int64(i + 1),
+			Title:           fmt.Sprintf("todo %d", i),
+			Description:     "Milk, eggs, bread <fresh> & cheap",
+			Status:          model.StatusInProgress,
+			Category:        model.CategoryWork,
+			ProgressPercent: i % 101,
+			Version:         1,
+			CreatedAt:       created,
+			UpdatedAt:       created.Add(time.Duration(i) * time.Second),
+		}
+	}
+	return &model.TodoListResponse{Todos: todos, Count: n, Total: n}
+}
+
+// TestStreamingJSONFormat checks that streaming a list encodes it exactly
+// as encoding it whole does.
+func TestStreamingJSONFormat(t *testing.T) {
+	resp := listResponse(StreamThreshold + 10)
+	var streamed, whole bytes.Buffer
+	if err := StreamingJSONFormat(0).Marshal(&streamed, resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := StreamingJSONFormat(math.MaxInt).Marshal(&whole, resp); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(streamed.Bytes(), whole.Bytes()) {
+		t.Errorf("streamed and whole encodings differ:\n%.300s\n%.300s", streamed.Bytes(), whole.Bytes())
+	}
+	if !json.Valid(streamed.Bytes()) {
+		t.Errorf("streamed encoding is not valid JSON")
+	}
+}
+
+func BenchmarkEncodeTodoList(b *testing.B) {
+	resp := listResponse(10000)
+	for _, bench := range []struct {
+		name      string
+		threshold int
+	}{
+		{"streamed", StreamThreshold},
+		{"whole", math.MaxInt},
+	} {
+		format := StreamingJSONFormat(bench.threshold)
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := format.Marshal(io.Discard, resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/github"
+	"todo-service/internal/model"
+)
+
+// GitHubHandler handles HTTP requests for the GitHub issues integration.
+type GitHubHandler struct {
+	syncer *github.Syncer
+	logger *slog.Logger
+}
+
+// NewGitHubHandler creates a new GitHubHandler. syncer is nil if the
+// integration is not configured.
+func NewGitHubHandler(syncer *github.Syncer, logger *slog.Logger) *GitHubHandler {
+	return &GitHubHandler{syncer: syncer, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GitHubSyncOutput struct {
+	Body model.GitHubSyncResponse
+}
+
+// RegisterRoutes registers all GitHub integration routes with the huma API.
+func (h *GitHubHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "sync-github",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/integrations/github/sync",
+		Summary:     "Sync with GitHub issues",
+		Description: "Sync the TODOs mirroring the issues of the repositories the server is configured with, as the scheduled sync does, and report what changed. Each repository's issues go in a project named after it. New open issues get TODOs; closing or reopening an issue or its TODO completes or reopens the other; issue title and body changes are copied to the TODO. If both were edited since the last sync, the server's conflict rule decides which is kept. Repositories or issues that fail to sync are listed with their errors rather than failing the request. Responds 404 if the server has no GitHub repositories configured.",
+		Tags:        []string{"integrations"},
+		Errors:      []int{404},
+	}, h.Sync)
+}
+
+func (h *GitHubHandler) Sync(ctx context.Context, input *struct{}) (*GitHubSyncOutput, error) {
+	if h.syncer == nil {
+		return nil, huma.Error404NotFound("GitHub sync is not configured")
+	}
+
+	result := h.syncer.Sync(ctx)
+	for _, repo := range result.Repos {
+		attrs := []any{
+			slog.String("repo", repo.Repo),
+			slog.Int("created", repo.Created),
+			slog.Int("updated", repo.Updated),
+			slog.Int("pushed", repo.Pushed),
+			slog.Int("conflicts", len(repo.Conflicts)),
+		}
+		if len(repo.Errors) > 0 {
+			h.logger.WarnContext(ctx, "failed to sync github issues", append(attrs, slog.Any("errors", repo.Errors))...)
+		} else {
+			h.logger.InfoContext(ctx, "synced github issues", attrs...)
+		}
+	}
+	return &GitHubSyncOutput{Body: result}, nil
+}
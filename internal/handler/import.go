@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+	"todo-service/internal/todoist"
+)
+
+// ImportHandler handles HTTP requests for importing TODOs from other
+// services.
+type ImportHandler struct {
+	repo    db.Repository
+	todoist *todoist.Client
+	logger  *slog.Logger
+}
+
+// NewImportHandler creates a new ImportHandler that reads from Todoist with
+// client.
+func NewImportHandler(repo db.Repository, client *todoist.Client, logger *slog.Logger) *ImportHandler {
+	return &ImportHandler{repo: repo, todoist: client, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type TodoistImportForm struct {
+	File  huma.FormFile `form:"file" contentType:"text/csv,application/zip,application/octet-stream" doc:"A Todoist export: one project's CSV export, named after the project, or a backup ZIP of every project's"`
+	Token string        `form:"token" doc:"A Todoist API token, from Settings > Integrations > Developer, to read the active tasks from Todoist instead"`
+}
+
+type ImportTodoistInput struct {
+	DryRun     bool   `query:"dry_run" required:"false" doc:"Report what would be imported without writing it"`
+	ProjectsAs string `query:"projects_as" required:"false" enum:"project,category" default:"project" doc:"Import Todoist projects as projects or as categories"`
+	RawBody    huma.MultipartFormFiles[TodoistImportForm]
+}
+
+type ImportOutput struct {
+	Body model.ImportResponse
+}
+
+// RegisterRoutes registers all import routes with the huma API.
+func (h *ImportHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "import-todoist",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/import/todoist",
+		Summary:     "Import tasks from Todoist",
+		Description: "Create TODOs from Todoist tasks, read from an uploaded export file or, with an API token, from Todoist itself, in one transaction. Projects become projects, or categories with projects_as=category, and are created if missing; inbox tasks get no project. Priorities p1 to p3 become urgent, high and medium, and p4 none. Due dates carry over, recurring ones as their next date only, and durations become estimates. Notes in a CSV export are appended to the description. Tasks whose title is already used by an unarchived TODO in the same project are skipped as conflicts, so an import can be repeated safely. Large backups may need a higher -max-body-bytes.",
+		Tags:        []string{"import"},
+		Errors:      []int{400, 502},
+	}, h.ImportTodoist)
+}
+
+func (h *ImportHandler) ImportTodoist(ctx context.Context, input *ImportTodoistInput) (*ImportOutput, error) {
+	form := input.RawBody.Data()
+	if form.File.IsSet == (form.Token != "") {
+		return nil, huma.Error400BadRequest("exactly one of file or token is required")
+	}
+
+	var tasks []todoist.Task
+	var err error
+	if form.File.IsSet {
+		stopValidation := timing.Track(ctx, timing.StageValidation)
+		var data []byte
+		data, err = io.ReadAll(form.File)
+		if err == nil {
+			tasks, err = todoist.ParseExport(form.File.Filename, data)
+		}
+		stopValidation()
+		if err != nil {
+			return nil, huma.Error400BadRequest("failed to read Todoist export: " + err.Error())
+		}
+	} else {
+		tasks, err = h.todoist.Fetch(ctx, form.Token)
+		if errors.Is(err, todoist.ErrUnauthorized) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		if err != nil {
+			h.logger.WarnContext(ctx, "failed to read from todoist", slog.String("error", err.Error()))
+			return nil, huma.Error502BadGateway("failed to read tasks from Todoist")
+		}
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	result, err := todoist.Import(h.repo, tasks, input.ProjectsAs, input.DryRun, auditInfo(ctx))
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to import todoist tasks", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to import tasks")
+	}
+
+	if !input.DryRun {
+		h.logger.InfoContext(ctx, "imported todoist tasks",
+			slog.Int("tasks", result.Tasks),
+			slog.Int("imported", result.Imported),
+			slog.Int("conflicts", len(result.Conflicts)),
+		)
+	}
+	return &ImportOutput{Body: result}, nil
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// InboxHandler handles HTTP requests for capturing and triaging TODOs.
+type InboxHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewInboxHandler creates a new InboxHandler.
+func NewInboxHandler(repo db.Repository, logger *slog.Logger) *InboxHandler {
+	return &InboxHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListInboxInput struct {
+	query.Params
+}
+
+type ListInboxOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of TODOs awaiting triage"`
+	Body       model.TodoListResponse
+}
+
+type CaptureTodoInput struct {
+	Body model.CaptureTodoRequest
+}
+
+type TriageTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.TriageTodoRequest
+}
+
+type TriageTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+// RegisterRoutes registers the inbox routes with the huma API.
+func (h *InboxHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-inbox",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/inbox",
+		Summary:     "List the inbox",
+		Description: "Retrieve the unarchived TODOs awaiting triage, oldest first. Supports sorting and limit/offset pagination; unpaginated requests matching more than 5000 items are rejected.",
+		Tags:        []string{"inbox"},
+		Errors:      []int{400},
+	}, h.ListInbox)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "capture-todo",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/inbox",
+		Summary:       "Capture a TODO",
+		Description:   "Quickly add a TODO to the inbox with just a title. It is created pending, with the default category, and stays in the inbox until triaged.",
+		Tags:          []string{"inbox"},
+		Errors:        []int{400, 422},
+		DefaultStatus: http.StatusCreated,
+	}, h.CaptureTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "triage-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/triage",
+		Summary:     "Triage a TODO",
+		Description: "Set a TODO item's category and, optionally, its project, priority, due date, and scheduled day in one call, removing it from the inbox.",
+		Tags:        []string{"inbox"},
+		Errors:      []int{400, 404, 422},
+	}, h.TriageTodo)
+}
+
+func (h *InboxHandler) ListInbox(ctx context.Context, input *ListInboxInput) (*ListInboxOutput, error) {
+	opts, err := input.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, snoozed, triage := false, false, model.TriagePending
+	filter := db.TodoFilter{Archived: &archived, Snoozed: &snoozed, Triage: &triage}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	total, err := h.repo.CountTodos(filter)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count inbox", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve inbox")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	stopDB = timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListTodos(filter, opts)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list inbox", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve inbox")
+	}
+
+	return &ListInboxOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.TodoListResponse{Todos: todos, Count: len(todos), Total: total},
+	}, nil
+}
+
+func (h *InboxHandler) CaptureTodo(ctx context.Context, input *CaptureTodoInput) (*CreateTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.CaptureTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.CaptureTodo(input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrCategoryNotFound) {
+		return nil, categoryNotFound("")
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to capture todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+		return nil, huma.Error500InternalServerError("failed to create todo")
+	}
+
+	return &CreateTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *InboxHandler) TriageTodo(ctx context.Context, input *TriageTodoInput) (*TriageTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.TriageTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.TriageTodo(input.ID, input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if errors.Is(err, db.ErrProjectNotFound) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("project with id %d not found", *input.Body.ProjectID))
+	}
+	if errors.Is(err, db.ErrCategoryNotFound) {
+		return nil, categoryNotFound(input.Body.Category)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to triage todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &TriageTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
@@ -0,0 +1,27 @@
+//go:build jsoniter
+
+package handler
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// jsonEncoder is the subset of *jsoniter.Encoder used by StreamingJSONFormat.
+type jsonEncoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+}
+
+// jsonAPI matches encoding/json's output, so clients see the same bytes
+// regardless of build tags.
+var jsonAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+func newJSONEncoder(w io.Writer) jsonEncoder {
+	return jsonAPI.NewEncoder(w)
+}
+
+func unmarshalJSON(data []byte, v any) error {
+	return jsonAPI.Unmarshal(data, v)
+}
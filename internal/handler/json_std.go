@@ -0,0 +1,22 @@
+//go:build !jsoniter
+
+package handler
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonEncoder is the subset of *json.Encoder used by StreamingJSONFormat.
+type jsonEncoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+}
+
+func newJSONEncoder(w io.Writer) jsonEncoder {
+	return json.NewEncoder(w)
+}
+
+func unmarshalJSON(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+)
+
+// MaintenanceHandler handles HTTP requests for read-only maintenance mode.
+type MaintenanceHandler struct {
+	maintenance *middleware.Maintenance
+	logger      *slog.Logger
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler that switches m.
+func NewMaintenanceHandler(m *middleware.Maintenance, logger *slog.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenance: m, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type MaintenanceOutput struct {
+	Body model.MaintenanceMode
+}
+
+type SetMaintenanceInput struct {
+	Body model.MaintenanceRequest
+}
+
+// RegisterRoutes registers the maintenance routes with the huma API.
+func (h *MaintenanceHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-maintenance",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/maintenance",
+		Summary:     "Get maintenance mode",
+		Description: "Report whether the API is read-only for maintenance, and since when.",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+	}, h.GetMaintenance)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-maintenance",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/admin/maintenance",
+		Summary:     "Set maintenance mode",
+		Description: "Turn read-only mode on or off, such as around backups, restores, and migrations. While it is on, reads succeed and every other request responds 503 with a Retry-After header, except admin operations, which stay available to carry out the maintenance. gRPC calls that change data fail with UNAVAILABLE and MQTT commands are refused likewise. The server can also start read-only with -read-only.",
+		Tags:        []string{"admin"},
+		Errors:      []int{422},
+	}, h.SetMaintenance)
+}
+
+func (h *MaintenanceHandler) GetMaintenance(ctx context.Context, input *struct{}) (*MaintenanceOutput, error) {
+	return &MaintenanceOutput{Body: h.maintenance.Mode()}, nil
+}
+
+func (h *MaintenanceHandler) SetMaintenance(ctx context.Context, input *SetMaintenanceInput) (*MaintenanceOutput, error) {
+	mode := h.maintenance.Set(input.Body)
+	if mode.ReadOnly {
+		h.logger.WarnContext(ctx, "read-only mode on", slog.String("message", mode.Message))
+	} else {
+		h.logger.WarnContext(ctx, "read-only mode off")
+	}
+	return &MaintenanceOutput{Body: mode}, nil
+}
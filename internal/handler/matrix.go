@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+)
+
+// MatrixHandler handles HTTP requests for the Eisenhower matrix view.
+type MatrixHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewMatrixHandler creates a new MatrixHandler.
+func NewMatrixHandler(repo db.Repository, logger *slog.Logger) *MatrixHandler {
+	return &MatrixHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetMatrixInput struct {
+	UrgentDays int   `query:"urgent_days" required:"false" minimum:"0" maximum:"365" default:"2" doc:"TODOs due within this many days, or overdue, count as urgent"`
+	ProjectID  int64 `query:"project_id" required:"false" doc:"Only include TODOs in this project"`
+}
+
+type GetMatrixOutput struct {
+	Body model.MatrixResponse
+}
+
+// RegisterRoutes registers the matrix routes with the huma API.
+func (h *MatrixHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-matrix",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/matrix",
+		Summary:     "Get the Eisenhower matrix",
+		Description: "Arrange the open (not done), unarchived TODOs into the four quadrants of an Eisenhower matrix. A TODO is urgent if it is overdue or due within urgent_days of today (UTC), and important if its priority is high or urgent. Each quadrant carries a label and a suggested color.",
+		Tags:        []string{"todos"},
+	}, h.GetMatrix)
+}
+
+func (h *MatrixHandler) GetMatrix(ctx context.Context, input *GetMatrixInput) (*GetMatrixOutput, error) {
+	today := time.Now().UTC()
+	urgentBy := today.AddDate(0, 0, input.UrgentDays).Format(model.DateLayout)
+
+	archived := false
+	filter := db.TodoFilter{
+		Archived: &archived,
+		Statuses: []model.Status{model.StatusPending, model.StatusInProgress},
+	}
+	if input.ProjectID != 0 {
+		filter.ProjectID = &input.ProjectID
+	}
+	opts, err := query.Params{}.Options(matrixSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	total, err := h.repo.CountTodos(filter)
+	stopDB()
+	if err != nil {
+		h.logger.Error("failed to count open todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve matrix")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	stopDB = timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListTodos(filter, opts)
+	stopDB()
+	if err != nil {
+		h.logger.Error("failed to list open todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve matrix")
+	}
+
+	return &GetMatrixOutput{Body: model.MatrixResponse{
+		Today:      today.Format(model.DateLayout),
+		UrgentDays: input.UrgentDays,
+		Quadrants:  arrangeMatrix(todos, urgentBy),
+	}}, nil
+}
+
+// matrixQuadrants are the quadrants of the matrix, in response order.
+var matrixQuadrants = []model.MatrixQuadrant{
+	{ID: model.QuadrantDoFirst, Label: "Do first", Color: "#d32f2f", Urgent: true, Important: true},
+	{ID: model.QuadrantSchedule, Label: "Schedule", Color: "#1976d2", Important: true},
+	{ID: model.QuadrantDelegate, Label: "Delegate", Color: "#f57c00", Urgent: true},
+	{ID: model.QuadrantEliminate, Label: "Eliminate", Color: "#757575"},
+}
+
+// matrixSort orders each quadrant's TODOs by due date, undated last, then
+// by priority, most important first.
+var matrixSort = query.Spec{
+	Columns: db.TodoSort.Columns,
+	Default: []query.Sort{{Field: "due_date"}, {Field: "priority", Desc: true}},
+}
+
+// arrangeMatrix sorts todos into the quadrants, keeping their order within
+// each. A TODO is urgent if it is due on or before urgentBy, formatted as
+// model.DateLayout.
+func arrangeMatrix(todos []model.Todo, urgentBy string) []model.MatrixQuadrant {
+	quadrants := make([]model.MatrixQuadrant, len(matrixQuadrants))
+	for i, q := range matrixQuadrants {
+		q.Todos = []model.Todo{}
+		quadrants[i] = q
+	}
+
+	for _, t := range todos {
+		urgent := t.DueDate != nil && *t.DueDate <= urgentBy
+		important := t.Priority == model.PriorityHigh || t.Priority == model.PriorityUrgent
+		for i := range quadrants {
+			if quadrants[i].Urgent == urgent && quadrants[i].Important == important {
+				quadrants[i].Todos = append(quadrants[i].Todos, t)
+				break
+			}
+		}
+	}
+	return quadrants
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"slices"
+	"testing"
+
+	"todo-service/internal/model"
+)
+
+func TestArrangeMatrix(t *testing.T) {
+	date := func(s string) *string { return &s }
+	todos := []model.Todo{
+		{ID: 1, Priority: model.PriorityUrgent, DueDate: date("2026-02-10")},
+		{ID: 2, Priority: model.PriorityHigh, DueDate: date("2026-02-18")},
+		{ID: 3, Priority: model.PriorityHigh, DueDate: date("2026-02-19")},
+		{ID: 4, Priority: model.PriorityUrgent},
+		{ID: 5, Priority: model.PriorityMedium, DueDate: date("2026-02-16")},
+		{ID: 6, Priority: model.PriorityNone, DueDate: date("2026-01-01")},
+		{ID: 7, Priority: model.PriorityLow, DueDate: date("2026-03-01")},
+		{ID: 8, Priority: model.PriorityNone},
+	}
+
+	// Today is 2026-02-16 and two days count as urgent.
+	quadrants := arrangeMatrix(todos, "2026-02-18")
+
+	want := map[model.Quadrant][]int64{
+		model.QuadrantDoFirst:   {1, 2},
+		model.QuadrantSchedule:  {3, 4},
+		model.QuadrantDelegate:  {5, 6},
+		model.QuadrantEliminate: {7, 8},
+	}
+	if len(quadrants) != len(want) {
+		t.Fatalf("got %d quadrants, want %d", len(quadrants), len(want))
+	}
+	for i, q := range quadrants {
+		if q.ID != matrixQuadrants[i].ID || q.Label == "" || q.Color == "" {
+			t.Errorf("quadrant %d is %q labelled %q in %q, want %q with a label and color", i, q.ID, q.Label, q.Color, matrixQuadrants[i].ID)
+		}
+		var ids []int64
+		for _, todo := range q.Todos {
+			ids = append(ids, todo.ID)
+		}
+		if !slices.Equal(ids, want[q.ID]) {
+			t.Errorf("%s: got TODOs %v, want %v", q.ID, ids, want[q.ID])
+		}
+	}
+}
+
+func TestArrangeMatrixEmpty(t *testing.T) {
+	for _, q := range arrangeMatrix(nil, "2026-02-18") {
+		if q.Todos == nil {
+			t.Errorf("%s: Todos is nil, want an empty slice so it encodes as []", q.ID)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// NextHandler handles HTTP requests for the next TODO to work on.
+type NextHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewNextHandler creates a new NextHandler.
+func NewNextHandler(repo db.Repository, logger *slog.Logger) *NextHandler {
+	return &NextHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetNextTodoOutput struct {
+	Body model.NextTodoResponse
+}
+
+// RegisterRoutes registers the next TODO route with the huma API.
+func (h *NextHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-next-todo",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/todos/next",
+		Summary:     "Get the next TODO to work on",
+		Description: "Pick the single TODO to work on now, for a one-button \"what now?\" client, and explain why it ranks first. Only open, triaged TODOs that are neither archived nor snoozed are considered. TODOs due soonest come first, so overdue TODOs lead, the longest overdue first, and TODOs without a due date come last. Ties go to the highest priority, then to TODOs in progress, then to manual order. Dates are UTC. Responds 404 if there is nothing to do.",
+		Tags:        []string{"planning"},
+		Errors:      []int{404},
+	}, h.GetNextTodo)
+}
+
+func (h *NextHandler) GetNextTodo(ctx context.Context, input *struct{}) (*GetNextTodoOutput, error) {
+	today := time.Now().UTC().Format(model.DateLayout)
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, candidates, err := h.repo.NextTodo()
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound("no actionable todos")
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get next todo", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to get next todo")
+	}
+
+	rank := model.RankNext(todo, today)
+	return &GetNextTodoOutput{Body: model.NextTodoResponse{
+		Todo:       todo,
+		Rank:       rank,
+		Factors:    rankingFactors(todo, rank, today),
+		Candidates: candidates,
+	}}, nil
+}
+
+// rankingFactors explains, in ranking order, what decided that todo is next.
+func rankingFactors(todo model.Todo, rank model.NextRank, today string) []model.RankingFactor {
+	due := model.RankingFactor{Factor: "due_date", Value: "none"}
+	if todo.DueDate != nil {
+		due.Value = *todo.DueDate
+	}
+	switch rank {
+	case model.NextRankOverdue:
+		due.Explanation = fmt.Sprintf("Overdue by %s; TODOs due soonest come first, so the longest overdue leads.", plural(daysBetween(*todo.DueDate, today), "day"))
+	case model.NextRankDueToday:
+		due.Explanation = "Due today, and nothing is overdue."
+	case model.NextRankDueLater:
+		due.Explanation = fmt.Sprintf("Due in %s, and nothing is due sooner.", plural(daysBetween(today, *todo.DueDate), "day"))
+	default:
+		due.Explanation = "No due date, and no open TODO has one."
+	}
+
+	priority := model.RankingFactor{
+		Factor:      "priority",
+		Value:       string(todo.Priority),
+		Explanation: "Highest priority among TODOs due the same day.",
+	}
+	if todo.DueDate == nil {
+		priority.Explanation = "Highest priority among TODOs without a due date."
+	}
+
+	status := model.RankingFactor{Factor: "status", Value: string(todo.Status)}
+	if todo.Status == model.StatusInProgress {
+		status.Explanation = fmt.Sprintf("Already started, %d%% done; TODOs in progress come before pending ones.", todo.ProgressPercent)
+	} else {
+		status.Explanation = "Not started, and no TODO tied on the factors above is in progress."
+	}
+
+	position := model.RankingFactor{
+		Factor:      "position",
+		Value:       strconv.FormatInt(todo.Position, 10),
+		Explanation: "First in manual order among TODOs tied on the factors above.",
+	}
+	return []model.RankingFactor{due, priority, status, position}
+}
+
+// daysBetween returns the number of days from one date in model.DateLayout
+// to a later one.
+func daysBetween(from, to string) int {
+	f, err1 := time.Parse(model.DateLayout, from)
+	t, err2 := time.Parse(model.DateLayout, to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(t.Sub(f).Hours() / 24)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
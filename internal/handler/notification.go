@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/notify"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// NotificationHandler handles HTTP requests for notification routes.
+type NotificationHandler struct {
+	repo     db.Repository
+	notifier *notify.Notifier
+	logger   *slog.Logger
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(repo db.Repository, notifier *notify.Notifier, logger *slog.Logger) *NotificationHandler {
+	return &NotificationHandler{repo: repo, notifier: notifier, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListNotificationRoutesInput struct {
+	query.Params
+}
+
+type ListNotificationRoutesOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of notification routes"`
+	Body       model.NotificationRouteListResponse
+}
+
+type CreateNotificationRouteInput struct {
+	Body model.NotificationRouteRequest
+}
+
+type NotificationRouteOutput struct {
+	Body model.NotificationRoute
+}
+
+type NotificationRouteIDInput struct {
+	ID int64 `path:"id" doc:"Notification route ID" example:"1"`
+}
+
+type ReplaceNotificationRouteInput struct {
+	ID   int64 `path:"id" doc:"Notification route ID" example:"1"`
+	Body model.NotificationRouteRequest
+}
+
+// RegisterRoutes registers all notification route routes with the huma API.
+func (h *NotificationHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-notification-routes",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/notifications/routes",
+		Summary:     "List all notification routes",
+		Description: "Retrieve all notification routes with their delivery status, sorted by name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"notifications"},
+		Errors:      []int{400},
+	}, h.ListRoutes)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-notification-route",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/notifications/routes",
+		Summary:       "Create a notification route",
+		Description:   "Post a message to a Slack incoming webhook or a Discord channel webhook whenever one of the route's events happens: a TODO being created, updated, completed, or deleted, or the due date of an open TODO passing (UTC), reported once just after midnight. Point routes for different events at different webhooks to send them to different channels, or to a direct message. Events are checked every few seconds, and those in the same check are posted together, one line each, up to 20; if the webhook fails they are posted when it works again. The template is a Go text/template executed with .Event, .Todo, .Actor, and .Changes, the audit log's changes; without one, each event has a default message. A new route hears of events from its creation on. Route names must be unique.",
+		Tags:          []string{"notifications"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateRoute)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-notification-route",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/notifications/routes/{id}",
+		Summary:     "Get a notification route by ID",
+		Description: "Retrieve a single notification route with its delivery status.",
+		Tags:        []string{"notifications"},
+		Errors:      []int{404},
+	}, h.GetRoute)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "replace-notification-route",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/notifications/routes/{id}",
+		Summary:     "Replace a notification route",
+		Description: "Replace an existing notification route as a whole. Events it has not been notified of yet are posted under its new settings.",
+		Tags:        []string{"notifications"},
+		Errors:      []int{400, 404, 409},
+	}, h.ReplaceRoute)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-notification-route",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/notifications/routes/{id}",
+		Summary:       "Delete a notification route",
+		Description:   "Stop posting messages to a webhook.",
+		Tags:          []string{"notifications"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteRoute)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "test-notification-route",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/notifications/routes/{id}/test",
+		Summary:     "Post a test message",
+		Description: "Post a message about a sample TODO to a notification route's webhook, rendered with its template for its first event, and return the route. It takes no request body and does not affect which events the route is notified of. Responds 502 if the webhook cannot be reached or does not respond with a 2xx status.",
+		Tags:        []string{"notifications"},
+		Errors:      []int{404, 502},
+	}, h.TestRoute)
+}
+
+func (h *NotificationHandler) ListRoutes(ctx context.Context, input *ListNotificationRoutesInput) (*ListNotificationRoutesOutput, error) {
+	opts, err := input.Options(db.NotificationRouteSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountNotificationRoutes()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count notification routes", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve notification routes")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	routes, err := h.repo.ListNotificationRoutes(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list notification routes", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve notification routes")
+	}
+
+	return &ListNotificationRoutesOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.NotificationRouteListResponse{Routes: routes, Count: len(routes), Total: total},
+	}, nil
+}
+
+func (h *NotificationHandler) CreateRoute(ctx context.Context, input *CreateNotificationRouteInput) (*NotificationRouteOutput, error) {
+	if err := badRequest(validate.NotificationRoute(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	route, err := h.repo.CreateNotificationRoute(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotificationRouteExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("notification route %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create notification route", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create notification route")
+	}
+
+	return &NotificationRouteOutput{Body: route}, nil
+}
+
+func (h *NotificationHandler) GetRoute(ctx context.Context, input *NotificationRouteIDInput) (*NotificationRouteOutput, error) {
+	route, err := h.getRoute(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationRouteOutput{Body: route}, nil
+}
+
+func (h *NotificationHandler) ReplaceRoute(ctx context.Context, input *ReplaceNotificationRouteInput) (*NotificationRouteOutput, error) {
+	if err := badRequest(validate.NotificationRoute(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	route, err := h.repo.ReplaceNotificationRoute(input.ID, input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("notification route with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrNotificationRouteExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("notification route %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to replace notification route", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update notification route")
+	}
+
+	return &NotificationRouteOutput{Body: route}, nil
+}
+
+func (h *NotificationHandler) DeleteRoute(ctx context.Context, input *NotificationRouteIDInput) (*struct{}, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err := h.repo.DeleteNotificationRoute(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("notification route with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete notification route", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete notification route")
+	}
+
+	return nil, nil
+}
+
+func (h *NotificationHandler) TestRoute(ctx context.Context, input *NotificationRouteIDInput) (*NotificationRouteOutput, error) {
+	route, err := h.getRoute(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	due := time.Now().UTC().AddDate(0, 0, -1).Format(model.DateLayout)
+	msg := model.NotificationMessage{
+		Event:    route.Events[0],
+		Todo:     model.Todo{ID: 1, Title: "Test notification from todo-service", Status: model.StatusDone, Category: model.CategoryWork, ProgressPercent: 100, DueDate: &due},
+		Actor:    middleware.GetActor(ctx),
+		Changes:  map[string]model.FieldChange{"status": {Old: string(model.StatusInProgress), New: string(model.StatusDone)}},
+		Problems: []string{"none; this is a test and the database is sound"},
+	}
+	if err := h.notifier.Post(ctx, route, []string{notify.Render(route, msg)}); err != nil {
+		h.logger.WarnContext(ctx, "failed to post test notification", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error502BadGateway(fmt.Sprintf("failed to post test notification: %s", err))
+	}
+	return &NotificationRouteOutput{Body: route}, nil
+}
+
+func (h *NotificationHandler) getRoute(ctx context.Context, id int64) (model.NotificationRoute, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	route, err := h.repo.GetNotificationRoute(id)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return model.NotificationRoute{}, huma.Error404NotFound(fmt.Sprintf("notification route with id %d not found", id))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get notification route", slog.String("error", err.Error()), slog.Int64("id", id))
+		return model.NotificationRoute{}, huma.Error500InternalServerError("failed to retrieve notification route")
+	}
+	return route, nil
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// ProjectHandler handles HTTP requests for project operations.
+type ProjectHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewProjectHandler creates a new ProjectHandler.
+func NewProjectHandler(repo db.Repository, logger *slog.Logger) *ProjectHandler {
+	return &ProjectHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListProjectsInput struct {
+	query.Params
+}
+
+type ListProjectsOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of projects"`
+	Body       model.ProjectListResponse
+}
+
+type CreateProjectInput struct {
+	Body model.CreateProjectRequest
+}
+
+type ProjectOutput struct {
+	Body model.Project
+}
+
+type GetProjectInput struct {
+	ID int64 `path:"id" doc:"Project ID" example:"1"`
+}
+
+type UpdateProjectInput struct {
+	ID   int64 `path:"id" doc:"Project ID" example:"1"`
+	Body model.UpdateProjectRequest
+}
+
+type DeleteProjectInput struct {
+	ID         int64  `path:"id" doc:"Project ID" example:"1"`
+	Todos      string `query:"todos" required:"false" enum:"unassign,reassign,delete" default:"unassign" doc:"What to do with the project's TODOs"`
+	ReassignTo int64  `query:"reassign_to" required:"false" doc:"Project to move the TODOs to when todos=reassign"`
+}
+
+// RegisterRoutes registers all project routes with the huma API.
+func (h *ProjectHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-projects",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/projects",
+		Summary:     "List all projects",
+		Description: "Retrieve all projects with their progress rollups, sorted by name by default. Supports sorting and limit/offset pagination. List a project's TODOs with GET /api/v1/todos?project_id=.",
+		Tags:        []string{"projects"},
+		Errors:      []int{400},
+	}, h.ListProjects)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-project",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/projects",
+		Summary:       "Create a new project",
+		Description:   "Create a new project. Project names must be unique.",
+		Tags:          []string{"projects"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateProject)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-project",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/projects/{id}",
+		Summary:     "Get a project by ID",
+		Description: "Retrieve a single project with counts of its TODOs by status and their average progress.",
+		Tags:        []string{"projects"},
+		Errors:      []int{404},
+	}, h.GetProject)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-project",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/projects/{id}",
+		Summary:     "Update a project",
+		Description: "Update an existing project. Only provided fields are changed.",
+		Tags:        []string{"projects"},
+		Errors:      []int{400, 404, 409},
+	}, h.UpdateProject)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-project",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/projects/{id}",
+		Summary:       "Delete a project",
+		Description:   "Delete a project. Its TODOs are removed from the project by default, moved to another project with todos=reassign and reassign_to, or deleted to the trash with todos=delete. Every affected TODO is recorded in the audit log under one operation. Views and actions that refer to the project refer to the new one when TODOs are reassigned; otherwise views and actions filtered by it are deleted, and create actions make their TODOs without a project.",
+		Tags:          []string{"projects"},
+		Errors:        []int{400, 404, 422},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteProject)
+}
+
+func (h *ProjectHandler) ListProjects(ctx context.Context, input *ListProjectsInput) (*ListProjectsOutput, error) {
+	opts, err := input.Options(db.ProjectSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountProjects()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count projects", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve projects")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	projects, err := h.repo.ListProjects(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list projects", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve projects")
+	}
+
+	return &ListProjectsOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.ProjectListResponse{Projects: projects, Count: len(projects), Total: total},
+	}, nil
+}
+
+func (h *ProjectHandler) CreateProject(ctx context.Context, input *CreateProjectInput) (*ProjectOutput, error) {
+	if err := badRequest(validate.CreateProject(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	project, err := h.repo.CreateProject(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrProjectExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("project %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create project", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create project")
+	}
+
+	return &ProjectOutput{Body: project}, nil
+}
+
+func (h *ProjectHandler) GetProject(ctx context.Context, input *GetProjectInput) (*ProjectOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	project, err := h.repo.GetProject(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("project with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get project", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve project")
+	}
+
+	return &ProjectOutput{Body: project}, nil
+}
+
+func (h *ProjectHandler) UpdateProject(ctx context.Context, input *UpdateProjectInput) (*ProjectOutput, error) {
+	if err := badRequest(validate.UpdateProject(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	project, err := h.repo.UpdateProject(input.ID, input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("project with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrProjectExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("project %q already exists", *input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update project", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update project")
+	}
+
+	return &ProjectOutput{Body: project}, nil
+}
+
+func (h *ProjectHandler) DeleteProject(ctx context.Context, input *DeleteProjectInput) (*struct{}, error) {
+	todos := db.ProjectTodos(input.Todos)
+	if todos == db.ProjectTodosReassign && input.ReassignTo == 0 {
+		const msg = "reassign_to is required when todos=reassign"
+		return nil, huma.Error400BadRequest(msg, &huma.ErrorDetail{Location: "query.reassign_to", Message: msg})
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	n, err := h.repo.DeleteProject(input.ID, todos, input.ReassignTo, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("project with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrProjectNotFound) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("cannot reassign todos to project %d", input.ReassignTo))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete project", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete project")
+	}
+
+	h.logger.InfoContext(ctx, "deleted project",
+		slog.Int64("id", input.ID),
+		slog.String("todos", string(todos)),
+		slog.Int("affected", n),
+		slog.String("operation_id", info.OperationID),
+	)
+	return nil, nil
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/report"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// maxReportDays is the longest date range a report may cover.
+const maxReportDays = 366
+
+// ReportHandler handles HTTP requests for reports.
+type ReportHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewReportHandler creates a new ReportHandler.
+func NewReportHandler(repo db.Repository, logger *slog.Logger) *ReportHandler {
+	return &ReportHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetBurndownInput struct {
+	Project int64  `query:"project" required:"false" doc:"Only count TODOs in this project" example:"1"`
+	From    string `query:"from" required:"false" format:"date" doc:"First day of the report (defaults to 13 days before to)" example:"2026-02-02"`
+	To      string `query:"to" required:"false" format:"date" doc:"Last day of the report (defaults to today, UTC)" example:"2026-02-15"`
+}
+
+type GetBurndownOutput struct {
+	Body model.BurndownReport
+}
+
+type GetTodoReportInput struct {
+	Format string `query:"format" required:"false" enum:"html,pdf" default:"html" doc:"Document format"`
+	Group  string `query:"group" required:"false" enum:"project,category" default:"project" doc:"Group TODOs by project or by category"`
+}
+
+type GetTodoReportOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+// RegisterRoutes registers all report routes with the huma API.
+func (h *ReportHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-burndown",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/reports/burndown",
+		Summary:     "Get a burndown report",
+		Description: fmt.Sprintf("Compute, for each day from from to to, the estimated minutes and number of TODOs still open at the end of that day (UTC), replayed from the audit log. Open TODOs are those not done, archived, or deleted. Ranges may span at most %d days.", maxReportDays),
+		Tags:        []string{"reports"},
+		Errors:      []int{400, 404},
+	}, h.GetBurndown)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-todo-report",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/reports/todos",
+		Summary:     "Get a printable TODO report",
+		Description: "Render the unarchived TODOs as a document for weekly reviews, grouped by project or category, with each group's share done and each TODO's priority, status, progress bar, due date, and estimate. Groups are sorted by name, with TODOs outside any project last, and TODOs are in manual order. HTML reports are self-contained and styled for printing; PDF reports are A4.",
+		Tags:        []string{"reports"},
+		Errors:      []int{400},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Report document",
+				Content: map[string]*huma.MediaType{
+					"text/html":       {Schema: &huma.Schema{Type: "string"}},
+					"application/pdf": {Schema: &huma.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+		},
+	}, h.GetTodoReport)
+}
+
+func (h *ReportHandler) GetBurndown(ctx context.Context, input *GetBurndownInput) (*GetBurndownOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	days, err := reportDays(input.From, input.To, time.Now())
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	var projectID *int64
+	if input.Project != 0 {
+		projectID = &input.Project
+		stopDB := timing.Track(ctx, timing.StageDB)
+		_, err := h.repo.GetProject(input.Project)
+		stopDB()
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("project with id %d not found", input.Project))
+		}
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get project", slog.String("error", err.Error()), slog.Int64("id", input.Project))
+			return nil, huma.Error500InternalServerError("failed to compute burndown")
+		}
+	}
+
+	// The replay needs every change up to the end of the last day.
+	end := days[len(days)-1].AddDate(0, 0, 1).Add(-time.Second)
+	opts, err := query.Params{Sort: "id"}.Options(db.AuditSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	entries, err := h.repo.ListAudit(db.AuditFilter{To: &end}, opts)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list audit log", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to compute burndown")
+	}
+
+	points := report.Burndown(entries, days, projectID)
+	return &GetBurndownOutput{Body: model.BurndownReport{
+		ProjectID: projectID,
+		From:      points[0].Date,
+		To:        points[len(points)-1].Date,
+		Points:    points,
+	}}, nil
+}
+
+func (h *ReportHandler) GetTodoReport(ctx context.Context, input *GetTodoReportInput) (*GetTodoReportOutput, error) {
+	archived := false
+	filter := db.TodoFilter{Archived: &archived}
+	opts, err := query.Params{Sort: "position"}.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	total, err := h.repo.CountTodos(filter)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to build report")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	stopDB = timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListTodos(filter, opts)
+	var names map[int64]string
+	if err == nil {
+		names, err = h.repo.ProjectNames()
+	}
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list todos for report", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to build report")
+	}
+
+	now := time.Now()
+	r := report.GroupTodos(todos, input.Group, names, now)
+
+	var buf bytes.Buffer
+	out := &GetTodoReportOutput{ContentType: report.ContentType(input.Format)}
+	err = report.RenderTodos(&buf, r, input.Format)
+	if input.Format == "pdf" {
+		out.ContentDisposition = fmt.Sprintf(`inline; filename="%s"`, report.Filename("todos", input.Format, now))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to render report", slog.String("error", err.Error()), slog.String("format", input.Format))
+		return nil, huma.Error500InternalServerError("failed to build report")
+	}
+	out.Body = buf.Bytes()
+	return out, nil
+}
+
+// reportDays returns the start of each day from from to to, inclusive,
+// defaulting to the two weeks ending today.
+func reportDays(from, to string, now time.Time) ([]time.Time, error) {
+	last := now.UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		if err := invalidInput("query", validate.Date("to", to)); err != nil {
+			return nil, err
+		}
+		last, _ = time.Parse(model.DateLayout, to)
+	}
+	first := last.AddDate(0, 0, -13)
+	if from != "" {
+		if err := invalidInput("query", validate.Date("from", from)); err != nil {
+			return nil, err
+		}
+		first, _ = time.Parse(model.DateLayout, from)
+	}
+
+	if first.After(last) {
+		const msg = "from must not be after to"
+		return nil, huma.Error400BadRequest(msg, &huma.ErrorDetail{Location: "query.from", Message: msg, Value: from})
+	}
+	if n := int(last.Sub(first)/(24*time.Hour)) + 1; n > maxReportDays {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("reports may span at most %d days", maxReportDays))
+	}
+
+	var days []time.Time
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days, nil
+}
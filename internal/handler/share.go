@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/report"
+	"todo-service/internal/timing"
+)
+
+// SharePath is where share links are served, followed by the token.
+const SharePath = "/share/"
+
+// ShareHandler handles HTTP requests for share links.
+type ShareHandler struct {
+	repo        db.Repository
+	maintenance *middleware.Maintenance
+	logger      *slog.Logger
+}
+
+// NewShareHandler creates a new ShareHandler. Links keep working while m is
+// read-only, but their accesses are not recorded.
+func NewShareHandler(repo db.Repository, m *middleware.Maintenance, logger *slog.Logger) *ShareHandler {
+	return &ShareHandler{repo: repo, maintenance: m, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type CreateShareInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.CreateShareRequest
+}
+
+type ShareOutput struct {
+	Body model.Share
+}
+
+type ListSharesInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type ListSharesOutput struct {
+	Body model.ShareListResponse
+}
+
+type ShareIDInput struct {
+	ID int64 `path:"id" doc:"Share ID" example:"1"`
+}
+
+type ListShareAccessesInput struct {
+	ID    int64 `path:"id" doc:"Share ID" example:"1"`
+	Limit int   `query:"limit" required:"false" minimum:"1" maximum:"1000" default:"100" doc:"Maximum number of accesses to return"`
+}
+
+type ListShareAccessesOutput struct {
+	Body model.ShareAccessListResponse
+}
+
+type OpenShareInput struct {
+	Token     string `path:"token" doc:"Token of the share link"`
+	Format    string `query:"format" required:"false" enum:"json,html" default:"html" doc:"Show the TODO as a web page or as JSON"`
+	UserAgent string `header:"User-Agent" required:"false"`
+
+	remoteAddr string
+}
+
+// Resolve captures the client's address for the access log.
+func (i *OpenShareInput) Resolve(ctx huma.Context) []error {
+	i.remoteAddr = ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(i.remoteAddr); err == nil {
+		i.remoteAddr = host
+	}
+	return nil
+}
+
+type OpenShareOutput struct {
+	ContentType    string `header:"Content-Type"`
+	CacheControl   string `header:"Cache-Control"`
+	ReferrerPolicy string `header:"Referrer-Policy"`
+	Body           []byte
+}
+
+// RegisterRoutes registers the share routes with the huma API.
+func (h *ShareHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-share",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/todos/{id}/share",
+		Summary:       "Share a TODO",
+		Description:   "Create a link that shows the TODO's title, description, status, progress, and planned day to anyone who has it, without access to the API, until it expires or is revoked. The link's token is unguessable and returned only now; the server keeps only a hash of it. A TODO may have several links, each revoked on its own, and its links stop working when it is deleted.",
+		Tags:          []string{"sharing"},
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{400, 404},
+	}, h.CreateShare)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-shares",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/todos/{id}/shares",
+		Summary:     "List a TODO's share links",
+		Description: "List every link the TODO was shared through, newest first, including expired and revoked ones, with how often and when they were last opened. Tokens are not returned.",
+		Tags:        []string{"sharing"},
+		Errors:      []int{404},
+	}, h.ListShares)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-share",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/shares/{id}",
+		Summary:     "Get a share link",
+		Tags:        []string{"sharing"},
+		Errors:      []int{404},
+	}, h.GetShare)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "revoke-share",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/shares/{id}/revoke",
+		Summary:     "Revoke a share link",
+		Description: "Stop the link from working before it expires. The share and its access log are kept.",
+		Tags:        []string{"sharing"},
+		Errors:      []int{404},
+	}, h.RevokeShare)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-share-accesses",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/shares/{id}/accesses",
+		Summary:     "List who opened a share link",
+		Description: "List the latest times the link was opened, newest first, with the client's address and user agent.",
+		Tags:        []string{"sharing"},
+		Errors:      []int{404},
+	}, h.ListShareAccesses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "open-share",
+		Method:      http.MethodGet,
+		Path:        SharePath + "{token}",
+		Summary:     "Open a share link",
+		Description: "Show the shared TODO as it is now, as a web page or as JSON, and record the access unless the service is read-only. Links that do not exist, have expired, or were revoked are not found alike.",
+		Tags:        []string{"sharing"},
+		Errors:      []int{404},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Shared TODO",
+				Content: map[string]*huma.MediaType{
+					"text/html":        {Schema: &huma.Schema{Type: "string"}},
+					"application/json": {Schema: api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(model.SharedTodo{}), true, "")},
+				},
+			},
+		},
+	}, h.OpenShare)
+}
+
+func (h *ShareHandler) CreateShare(ctx context.Context, input *CreateShareInput) (*ShareOutput, error) {
+	hours := input.Body.ExpiresInHours
+	if hours == 0 {
+		hours = model.DefaultShareHours
+	}
+	expiresAt := time.Now().Add(time.Duration(hours) * time.Hour)
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	share, err := h.repo.CreateShare(input.ID, expiresAt)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create share", slog.String("error", err.Error()), slog.Int64("todo_id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to create share")
+	}
+
+	share.URL = SharePath + share.Token
+	return &ShareOutput{Body: share}, nil
+}
+
+func (h *ShareHandler) ListShares(ctx context.Context, input *ListSharesInput) (*ListSharesOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	shares, err := h.repo.ListShares(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list shares", slog.String("error", err.Error()), slog.Int64("todo_id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve shares")
+	}
+
+	return &ListSharesOutput{Body: model.ShareListResponse{Shares: shares, Count: len(shares)}}, nil
+}
+
+func (h *ShareHandler) GetShare(ctx context.Context, input *ShareIDInput) (*ShareOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	share, err := h.repo.GetShare(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("share with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get share", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve share")
+	}
+
+	return &ShareOutput{Body: share}, nil
+}
+
+func (h *ShareHandler) RevokeShare(ctx context.Context, input *ShareIDInput) (*ShareOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	share, err := h.repo.RevokeShare(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("share with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to revoke share", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to revoke share")
+	}
+
+	return &ShareOutput{Body: share}, nil
+}
+
+func (h *ShareHandler) ListShareAccesses(ctx context.Context, input *ListShareAccessesInput) (*ListShareAccessesOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	accesses, total, err := h.repo.ListShareAccesses(input.ID, input.Limit)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("share with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list share accesses", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve share accesses")
+	}
+
+	return &ListShareAccessesOutput{Body: model.ShareAccessListResponse{Accesses: accesses, Count: len(accesses), Total: total}}, nil
+}
+
+func (h *ShareHandler) OpenShare(ctx context.Context, input *OpenShareInput) (*OpenShareOutput, error) {
+	var access *model.ShareAccess
+	if !h.maintenance.Mode().ReadOnly {
+		access = &model.ShareAccess{RemoteAddr: input.remoteAddr, UserAgent: input.UserAgent}
+	}
+	stopDB := timing.Track(ctx, timing.StageDB)
+	share, todo, err := h.repo.OpenShare(input.Token, time.Now(), access)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound("share link not found; it may have expired or been revoked")
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to open share", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to open share")
+	}
+
+	shared := model.NewSharedTodo(todo, share.ExpiresAt)
+	out := &OpenShareOutput{CacheControl: "no-store", ReferrerPolicy: "no-referrer"}
+	var buf bytes.Buffer
+	if input.Format == "json" {
+		out.ContentType = "application/json"
+		enc := newJSONEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		err = enc.Encode(shared)
+	} else {
+		out.ContentType = "text/html; charset=utf-8"
+		err = report.RenderSharedTodoHTML(&buf, shared)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to render share", slog.String("error", err.Error()), slog.Int64("id", share.ID))
+		return nil, huma.Error500InternalServerError("failed to open share")
+	}
+	out.Body = buf.Bytes()
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+)
+
+func TestOpenShareReadOnly(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	repo, err := db.New(filepath.Join(t.TempDir(), "todos.db"), logger)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	maintenance := &middleware.Maintenance{}
+	h := NewShareHandler(repo, maintenance, logger)
+
+	todo, err := repo.CreateTodo(model.CreateTodoRequest{Title: "shared"}, db.AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	share, err := repo.CreateShare(todo.ID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShare: %v", err)
+	}
+
+	open := func() {
+		t.Helper()
+		if _, err := h.OpenShare(context.Background(), &OpenShareInput{Token: share.Token, Format: "json"}); err != nil {
+			t.Fatalf("OpenShare: %v", err)
+		}
+	}
+	open()
+	maintenance.Set(model.MaintenanceRequest{ReadOnly: true})
+	open()
+
+	got, err := repo.GetShare(share.ID)
+	if err != nil {
+		t.Fatalf("GetShare: %v", err)
+	}
+	if got.Accesses != 1 {
+		t.Errorf("got %d accesses, want 1: the link works while read-only but is not recorded", got.Accesses)
+	}
+}
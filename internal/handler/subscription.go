@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/delivery"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// SubscriptionHandler handles HTTP requests for report subscriptions.
+type SubscriptionHandler struct {
+	repo      db.Repository
+	deliverer *delivery.Deliverer
+	logger    *slog.Logger
+}
+
+// NewSubscriptionHandler creates a new SubscriptionHandler.
+func NewSubscriptionHandler(repo db.Repository, deliverer *delivery.Deliverer, logger *slog.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{repo: repo, deliverer: deliverer, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListSubscriptionsInput struct {
+	query.Params
+}
+
+type ListSubscriptionsOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of report subscriptions"`
+	Body       model.ReportSubscriptionListResponse
+}
+
+type CreateSubscriptionInput struct {
+	Body model.ReportSubscriptionRequest
+}
+
+type SubscriptionOutput struct {
+	Body model.ReportSubscription
+}
+
+type SubscriptionIDInput struct {
+	ID int64 `path:"id" doc:"Report subscription ID" example:"1"`
+}
+
+type ReplaceSubscriptionInput struct {
+	ID   int64 `path:"id" doc:"Report subscription ID" example:"1"`
+	Body model.ReportSubscriptionRequest
+}
+
+// RegisterRoutes registers all report subscription routes with the huma
+// API.
+func (h *SubscriptionHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-report-subscriptions",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/reports/subscriptions",
+		Summary:     "List all report subscriptions",
+		Description: "Retrieve all report subscriptions with their delivery status, sorted by name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"reports"},
+		Errors:      []int{400},
+	}, h.ListSubscriptions)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-report-subscription",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/reports/subscriptions",
+		Summary:       "Subscribe to a report",
+		Description:   "Have a report delivered to a webhook on a schedule: daily at midnight UTC, or weekly at midnight UTC on Mondays. The todos report is the printable TODO report, in HTML or PDF, and the burndown report covers the two weeks ending the day before, in JSON. Each delivery POSTs the document with its media type, a filename in Content-Disposition, and the subscription's ID in X-Report-Subscription; a failed delivery is retried every 15 minutes until the next scheduled one. Subscription names must be unique.",
+		Tags:          []string{"reports"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateSubscription)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-report-subscription",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/reports/subscriptions/{id}",
+		Summary:     "Get a report subscription by ID",
+		Description: "Retrieve a single report subscription with its delivery status.",
+		Tags:        []string{"reports"},
+		Errors:      []int{404},
+	}, h.GetSubscription)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "replace-report-subscription",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/reports/subscriptions/{id}",
+		Summary:     "Replace a report subscription",
+		Description: "Replace an existing report subscription as a whole, since its format and group only make sense with its report. It is rescheduled for the next delivery time of its schedule.",
+		Tags:        []string{"reports"},
+		Errors:      []int{400, 404, 409},
+	}, h.ReplaceSubscription)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-report-subscription",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/reports/subscriptions/{id}",
+		Summary:       "Delete a report subscription",
+		Description:   "Stop delivering a report.",
+		Tags:          []string{"reports"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteSubscription)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "send-report-subscription",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/reports/subscriptions/{id}/send",
+		Summary:     "Send a subscribed report now",
+		Description: "Deliver a report subscription immediately, such as to test its webhook, and return it with its delivery status. Scheduled deliveries are not affected. It takes no request body. Responds 502 if the webhook cannot be reached or does not respond with a 2xx status.",
+		Tags:        []string{"reports"},
+		Errors:      []int{404, 502},
+	}, h.SendSubscription)
+}
+
+func (h *SubscriptionHandler) ListSubscriptions(ctx context.Context, input *ListSubscriptionsInput) (*ListSubscriptionsOutput, error) {
+	opts, err := input.Options(db.SubscriptionSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountReportSubscriptions()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count report subscriptions", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve report subscriptions")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	subs, err := h.repo.ListReportSubscriptions(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list report subscriptions", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve report subscriptions")
+	}
+
+	return &ListSubscriptionsOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.ReportSubscriptionListResponse{Subscriptions: subs, Count: len(subs), Total: total},
+	}, nil
+}
+
+func (h *SubscriptionHandler) CreateSubscription(ctx context.Context, input *CreateSubscriptionInput) (*SubscriptionOutput, error) {
+	if err := badRequest(validate.ReportSubscription(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	sub, err := h.repo.CreateReportSubscription(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrSubscriptionExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("report subscription %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create report subscription", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create report subscription")
+	}
+
+	return &SubscriptionOutput{Body: sub}, nil
+}
+
+func (h *SubscriptionHandler) GetSubscription(ctx context.Context, input *SubscriptionIDInput) (*SubscriptionOutput, error) {
+	sub, err := h.getSubscription(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &SubscriptionOutput{Body: sub}, nil
+}
+
+func (h *SubscriptionHandler) ReplaceSubscription(ctx context.Context, input *ReplaceSubscriptionInput) (*SubscriptionOutput, error) {
+	if err := badRequest(validate.ReportSubscription(input.Body)); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	sub, err := h.repo.ReplaceReportSubscription(input.ID, input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("report subscription with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrSubscriptionExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("report subscription %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to replace report subscription", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update report subscription")
+	}
+
+	return &SubscriptionOutput{Body: sub}, nil
+}
+
+func (h *SubscriptionHandler) DeleteSubscription(ctx context.Context, input *SubscriptionIDInput) (*struct{}, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err := h.repo.DeleteReportSubscription(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("report subscription with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete report subscription", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete report subscription")
+	}
+
+	return nil, nil
+}
+
+func (h *SubscriptionHandler) SendSubscription(ctx context.Context, input *SubscriptionIDInput) (*SubscriptionOutput, error) {
+	sub, err := h.getSubscription(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var sentAt *time.Time
+	deliveryErr := h.deliverer.Deliver(ctx, sub, now)
+	if deliveryErr == nil {
+		sentAt = &now
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err = h.repo.RecordReportDelivery(sub.ID, sentAt, deliveryErr, sub.NextRunAt)
+	if err == nil {
+		sub, err = h.repo.GetReportSubscription(sub.ID)
+	}
+	stopDB()
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		h.logger.ErrorContext(ctx, "failed to record report delivery", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to record report delivery")
+	}
+
+	if deliveryErr != nil {
+		h.logger.WarnContext(ctx, "failed to deliver report", slog.String("error", deliveryErr.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error502BadGateway(fmt.Sprintf("failed to deliver report: %s", deliveryErr))
+	}
+	return &SubscriptionOutput{Body: sub}, nil
+}
+
+func (h *SubscriptionHandler) getSubscription(ctx context.Context, id int64) (model.ReportSubscription, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	sub, err := h.repo.GetReportSubscription(id)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return model.ReportSubscription{}, huma.Error404NotFound(fmt.Sprintf("report subscription with id %d not found", id))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get report subscription", slog.String("error", err.Error()), slog.Int64("id", id))
+		return model.ReportSubscription{}, huma.Error500InternalServerError("failed to retrieve report subscription")
+	}
+	return sub, nil
+}
@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// SyncHandler handles HTTP requests for syncing offline changes.
+type SyncHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewSyncHandler creates a new SyncHandler.
+func NewSyncHandler(repo db.Repository, logger *slog.Logger) *SyncHandler {
+	return &SyncHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type SyncInput struct {
+	Body model.SyncRequest
+}
+
+type SyncOutput struct {
+	Body model.SyncResponse
+}
+
+// RegisterRoutes registers the sync routes with the huma API.
+func (h *SyncHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "sync",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/sync",
+		Summary:     "Upload offline changes",
+		Description: "Apply the TODOs a client created, updated, or deleted while offline, in order, and return the server's changes since the client's cursor, as the change feed does. Each change reports its outcome instead of failing the request. An update or delete made to the TODO's current version is applied. A change whose ref the caller has already synced is reported as a duplicate and not applied again, so an upload whose response was lost can be retried. One made to an older version conflicts with the server's changes since: with last_writer_wins, whichever side changed the TODO last, by the change's changed_at and the TODO's updated_at, wins it whole; with merge, the client's changes to fields the server has not changed since are applied, and for fields both changed the last writer wins. A change to a TODO deleted on the server conflicts, and a change the server does not allow, such as a status change, is rejected.",
+		Tags:        []string{"sync"},
+		Errors:      []int{400},
+	}, h.Sync)
+}
+
+func (h *SyncHandler) Sync(ctx context.Context, input *SyncInput) (*SyncOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.Sync(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+	strategy := input.Body.Strategy
+	if strategy == "" {
+		strategy = model.SyncLastWriterWins
+	}
+
+	info := auditInfo(ctx)
+	results := make([]model.SyncResult, len(input.Body.Changes))
+	for i, change := range input.Body.Changes {
+		stopDB := timing.Track(ctx, timing.StageDB)
+		changeInfo := info
+		changeInfo.SyncRef = change.Ref
+		result, err := h.apply(change, strategy, changeInfo)
+		stopDB()
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to sync change", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int("index", i))
+			return nil, huma.Error500InternalServerError("failed to sync changes")
+		}
+		result.Ref = change.Ref
+		results[i] = result
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	changes, more, err := h.repo.Changes(input.Body.Since, model.MaxSyncChanges)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list changes", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve changes")
+	}
+	cursor := input.Body.Since
+	if len(changes) > 0 {
+		cursor = changes[len(changes)-1].Seq
+	}
+
+	return &SyncOutput{Body: model.SyncResponse{Results: results, Changes: changes, Cursor: cursor, HasMore: more}}, nil
+}
+
+// apply makes one change, returning an error only if the repository fails.
+func (h *SyncHandler) apply(change model.SyncChange, strategy model.SyncStrategy, info db.AuditInfo) (model.SyncResult, error) {
+	if change.Ref != "" {
+		id, ok, err := h.repo.SyncedChange(info.Actor, change.Ref)
+		if err != nil {
+			return model.SyncResult{}, err
+		}
+		if ok {
+			return h.duplicate(id)
+		}
+	}
+
+	if change.Op == model.SyncCreate {
+		todo, err := h.repo.CreateTodo(*change.Create, info)
+		if err != nil {
+			return rejected(change.TodoID, err, change.Create.ProjectID, change.Create.Category)
+		}
+		return model.SyncResult{TodoID: todo.ID, Outcome: model.OutcomeApplied, Todo: &todo}, nil
+	}
+
+	current, err := h.repo.GetTodo(change.TodoID)
+	if errors.Is(err, db.ErrNotFound) {
+		if change.Op == model.SyncDelete {
+			return model.SyncResult{TodoID: change.TodoID, Outcome: model.OutcomeApplied}, nil
+		}
+		return model.SyncResult{
+			TodoID:   change.TodoID,
+			Outcome:  model.OutcomeConflict,
+			Conflict: &model.SyncConflict{Fields: updateFields(*change.Update), Winner: "server"},
+		}, nil
+	}
+	if err != nil {
+		return model.SyncResult{}, err
+	}
+
+	stale := change.BaseVersion != 0 && change.BaseVersion != current.Version
+	clientWins := change.ChangedAt.After(current.UpdatedAt)
+	if change.Op == model.SyncDelete {
+		return h.delete(current, stale, clientWins, info)
+	}
+
+	req := *change.Update
+	result := model.SyncResult{TodoID: current.ID, Outcome: model.OutcomeApplied}
+	if stale {
+		fields := updateFields(req)
+		overlap := fields
+		if strategy == model.SyncMerge {
+			changed, ok, err := h.repo.FieldsChangedSince(current.ID, change.BaseVersion, current.Version)
+			if err != nil {
+				return model.SyncResult{}, err
+			}
+			if ok {
+				overlap = slices.DeleteFunc(slices.Clone(fields), func(f string) bool { return !slices.Contains(changed, f) })
+			}
+			result.Outcome = model.OutcomeMerged
+		}
+
+		if len(overlap) > 0 {
+			result.Conflict = &model.SyncConflict{ServerVersion: current.Version, Fields: overlap, Winner: "client"}
+			if !clientWins {
+				result.Conflict.Winner = "server"
+				if strategy == model.SyncLastWriterWins {
+					return conflict(result, current), nil
+				}
+				req = withoutFields(req, overlap)
+				if len(updateFields(req)) == 0 {
+					return conflict(result, current), nil
+				}
+			}
+		}
+	}
+
+	todo, err := h.repo.UpdateTodo(current.ID, current.Version, req, info)
+	if errors.Is(err, db.ErrVersionMismatch) || errors.Is(err, db.ErrNotFound) {
+		return model.SyncResult{TodoID: current.ID, Outcome: model.OutcomeRejected, Error: "todo changed while syncing; sync again"}, nil
+	}
+	if err != nil {
+		var category model.Category
+		if req.Category != nil {
+			category = *req.Category
+		}
+		return rejected(current.ID, err, req.ProjectID, category)
+	}
+	result.Todo = &todo
+	return result, nil
+}
+
+// delete deletes current, unless the client deleted an older version and
+// the server changed it since.
+func (h *SyncHandler) delete(current model.Todo, stale, clientWins bool, info db.AuditInfo) (model.SyncResult, error) {
+	result := model.SyncResult{TodoID: current.ID, Outcome: model.OutcomeApplied}
+	if stale {
+		result.Conflict = &model.SyncConflict{ServerVersion: current.Version, Fields: []string{}, Winner: "client"}
+		if !clientWins {
+			result.Conflict.Winner = "server"
+			return conflict(result, current), nil
+		}
+	}
+
+	err := h.repo.DeleteTodo(current.ID, current.Version, info)
+	if errors.Is(err, db.ErrVersionMismatch) {
+		return model.SyncResult{TodoID: current.ID, Outcome: model.OutcomeRejected, Error: "todo changed while syncing; sync again"}, nil
+	}
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return model.SyncResult{}, err
+	}
+	return result, nil
+}
+
+// duplicate reports a change an earlier sync applied to the TODO id, with
+// the TODO as it is now unless it has been deleted.
+func (h *SyncHandler) duplicate(id int64) (model.SyncResult, error) {
+	result := model.SyncResult{TodoID: id, Outcome: model.OutcomeDuplicate}
+	todo, err := h.repo.GetTodo(id)
+	switch {
+	case err == nil:
+		result.Todo = &todo
+	case !errors.Is(err, db.ErrNotFound):
+		return model.SyncResult{}, err
+	}
+	return result, nil
+}
+
+// conflict marks result as lost to the server's version, current.
+func conflict(result model.SyncResult, current model.Todo) model.SyncResult {
+	result.Outcome = model.OutcomeConflict
+	result.Todo = &current
+	return result
+}
+
+// rejected reports a change the repository refused as rejected, with the
+// reason, or returns err if the repository failed.
+func rejected(id int64, err error, projectID *int64, category model.Category) (model.SyncResult, error) {
+	result := model.SyncResult{TodoID: id, Outcome: model.OutcomeRejected}
+	var transErr *db.TransitionError
+	var ruleErr *db.RuleError
+	var fieldErr *db.CustomFieldError
+	switch {
+	case errors.Is(err, db.ErrProjectNotFound):
+		result.Error = fmt.Sprintf("project with id %d not found", *projectID)
+	case errors.Is(err, db.ErrCategoryNotFound):
+		result.Error = categoryNotFound(category).Error()
+	case errors.As(err, &transErr), errors.As(err, &ruleErr), errors.As(err, &fieldErr):
+		result.Error = err.Error()
+	default:
+		return model.SyncResult{}, err
+	}
+	return result, nil
+}
+
+// updateFields returns the JSON names of the fields req sets.
+func updateFields(req model.UpdateTodoRequest) []string {
+	var fields []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"title", req.Title != nil},
+		{"description", req.Description != nil},
+		{"status", req.Status != nil},
+		{"category", req.Category != nil},
+		{"project_id", req.ProjectID != nil},
+		{"progress_percent", req.ProgressPercent != nil},
+		{"priority", req.Priority != nil},
+		{"due_date", req.DueDate != nil},
+		{"estimate_minutes", req.EstimateMinutes != nil},
+		{"color", req.Color != nil},
+		{"icon", req.Icon != nil},
+	} {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(req.CustomFields)) {
+		fields = append(fields, customFieldPrefix+name)
+	}
+	return fields
+}
+
+// customFieldPrefix starts the names the audit log gives custom field
+// values.
+const customFieldPrefix = "custom_fields."
+
+// withoutFields returns req without the fields named in fields.
+func withoutFields(req model.UpdateTodoRequest, fields []string) model.UpdateTodoRequest {
+	for _, f := range fields {
+		switch f {
+		case "title":
+			req.Title = nil
+		case "description":
+			req.Description = nil
+		case "status":
+			req.Status = nil
+		case "category":
+			req.Category = nil
+		case "project_id":
+			req.ProjectID = nil
+		case "progress_percent":
+			req.ProgressPercent = nil
+		case "priority":
+			req.Priority = nil
+		case "due_date":
+			req.DueDate = nil
+		case "estimate_minutes":
+			req.EstimateMinutes = nil
+		case "color":
+			req.Color = nil
+		case "icon":
+			req.Icon = nil
+		default:
+			if name, ok := strings.CutPrefix(f, customFieldPrefix); ok {
+				req.CustomFields = maps.Clone(req.CustomFields)
+				delete(req.CustomFields, name)
+				if len(req.CustomFields) == 0 {
+					req.CustomFields = nil
+				}
+			}
+		}
+	}
+	return req
+}
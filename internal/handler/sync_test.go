@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+func newTestSyncHandler(t *testing.T) (*SyncHandler, db.Repository) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	repo, err := db.New(filepath.Join(t.TempDir(), "todos.db"), logger)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return NewSyncHandler(repo, logger), repo
+}
+
+func syncChanges(t *testing.T, h *SyncHandler, strategy model.SyncStrategy, changes ...model.SyncChange) []model.SyncResult {
+	t.Helper()
+	out, err := h.Sync(context.Background(), &SyncInput{Body: model.SyncRequest{Strategy: strategy, Changes: changes}})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(out.Body.Results) != len(changes) {
+		t.Fatalf("got %d results for %d changes", len(out.Body.Results), len(changes))
+	}
+	return out.Body.Results
+}
+
+func TestSyncRetry(t *testing.T) {
+	h, repo := newTestSyncHandler(t)
+	existing, err := repo.CreateTodo(model.CreateTodoRequest{Title: "existing"}, db.AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	doomed, err := repo.CreateTodo(model.CreateTodoRequest{Title: "doomed"}, db.AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+
+	now := time.Now()
+	title := "renamed offline"
+	changes := []model.SyncChange{
+		{Op: model.SyncCreate, Ref: "create-1", ChangedAt: now, Create: &model.CreateTodoRequest{Title: "made offline"}},
+		{Op: model.SyncUpdate, Ref: "update-1", ChangedAt: now, TodoID: existing.ID, BaseVersion: existing.Version, Update: &model.UpdateTodoRequest{Title: &title}},
+		{Op: model.SyncDelete, Ref: "delete-1", ChangedAt: now, TodoID: doomed.ID, BaseVersion: doomed.Version},
+	}
+	first := syncChanges(t, h, "", changes...)
+	for i, r := range first {
+		if r.Outcome != model.OutcomeApplied {
+			t.Fatalf("first sync: change %d is %s (%s), want applied", i, r.Outcome, r.Error)
+		}
+	}
+
+	// The response was lost, so the client uploads the same changes again.
+	retry := syncChanges(t, h, "", changes...)
+	for i, r := range retry {
+		if r.Outcome != model.OutcomeDuplicate || r.TodoID != first[i].TodoID || r.Ref != changes[i].Ref {
+			t.Errorf("retry: change %d is %s of TODO %d ref %q, want duplicate of %d ref %q", i, r.Outcome, r.TodoID, r.Ref, first[i].TodoID, changes[i].Ref)
+		}
+	}
+	if retry[1].Todo == nil || retry[1].Todo.Version != first[1].Todo.Version {
+		t.Errorf("retry: updated TODO = %+v, want version %d unchanged", retry[1].Todo, first[1].Todo.Version)
+	}
+	if retry[2].Todo != nil {
+		t.Errorf("retry: deleted TODO = %+v, want none", retry[2].Todo)
+	}
+
+	n, err := repo.CountTodos(db.TodoFilter{})
+	if err != nil {
+		t.Fatalf("CountTodos: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("got %d TODOs after the retry, want 2: the existing one and the one created once", n)
+	}
+
+	// A new change is applied even alongside retried ones.
+	again := "renamed again"
+	results := syncChanges(t, h, "", changes[1], model.SyncChange{
+		Op: model.SyncUpdate, Ref: "update-2", ChangedAt: now, TodoID: existing.ID, Update: &model.UpdateTodoRequest{Title: &again},
+	})
+	if results[0].Outcome != model.OutcomeDuplicate || results[1].Outcome != model.OutcomeApplied {
+		t.Errorf("got outcomes %s and %s, want duplicate and applied", results[0].Outcome, results[1].Outcome)
+	}
+}
+
+func TestSyncLastWriterWins(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		changedAt time.Duration // relative to the server's change
+		want      string
+	}{
+		{name: "server changed last", changedAt: -time.Hour, want: "server"},
+		{name: "client changed last", changedAt: time.Hour, want: "client"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo := newTestSyncHandler(t)
+			base, err := repo.CreateTodo(model.CreateTodoRequest{Title: "base", Description: "base"}, db.AuditInfo{})
+			if err != nil {
+				t.Fatalf("CreateTodo: %v", err)
+			}
+			serverTitle := "server"
+			server, err := repo.UpdateTodo(base.ID, 0, model.UpdateTodoRequest{Title: &serverTitle}, db.AuditInfo{})
+			if err != nil {
+				t.Fatalf("UpdateTodo: %v", err)
+			}
+
+			// The client changed only the description, but last-writer-wins
+			// settles the whole TODO.
+			description := "client"
+			priority := model.PriorityHigh
+			r := syncChanges(t, h, model.SyncLastWriterWins, model.SyncChange{
+				Op: model.SyncUpdate, ChangedAt: server.UpdatedAt.Add(tt.changedAt), TodoID: base.ID, BaseVersion: base.Version,
+				Update: &model.UpdateTodoRequest{Description: &description, Priority: &priority},
+			})[0]
+
+			if r.Conflict == nil || r.Conflict.Winner != tt.want || r.Conflict.ServerVersion != server.Version {
+				t.Fatalf("conflict = %+v, want %s winning over version %d", r.Conflict, tt.want, server.Version)
+			}
+			if want := []string{"description", "priority"}; !slices.Equal(r.Conflict.Fields, want) {
+				t.Errorf("conflict fields = %v, want every field the client changed, %v", r.Conflict.Fields, want)
+			}
+			got, err := repo.GetTodo(base.ID)
+			if err != nil {
+				t.Fatalf("GetTodo: %v", err)
+			}
+			if tt.want == "server" {
+				if r.Outcome != model.OutcomeConflict || got.Version != server.Version || got.Description != "base" {
+					t.Errorf("got %s and TODO %+v, want a conflict leaving version %d", r.Outcome, got, server.Version)
+				}
+				return
+			}
+			if r.Outcome != model.OutcomeApplied || got.Description != description || got.Priority != priority || got.Title != serverTitle {
+				t.Errorf("got %s and TODO %+v, want the client's change applied on top of the server's title", r.Outcome, got)
+			}
+		})
+	}
+}
+
+func TestSyncMerge(t *testing.T) {
+	h, repo := newTestSyncHandler(t)
+	base, err := repo.CreateTodo(model.CreateTodoRequest{Title: "base", Description: "base"}, db.AuditInfo{})
+	if err != nil {
+		t.Fatalf("CreateTodo: %v", err)
+	}
+	serverTitle, serverDue := "server", "2026-03-01"
+	server, err := repo.UpdateTodo(base.ID, 0, model.UpdateTodoRequest{Title: &serverTitle, DueDate: &serverDue}, db.AuditInfo{})
+	if err != nil {
+		t.Fatalf("UpdateTodo: %v", err)
+	}
+
+	// Made before the server's change: the server keeps the fields both
+	// changed, and the client's other fields are merged in.
+	clientTitle, clientDue, description := "client", "2026-02-01", "client"
+	priority := model.PriorityUrgent
+	r := syncChanges(t, h, model.SyncMerge, model.SyncChange{
+		Op: model.SyncUpdate, ChangedAt: server.UpdatedAt.Add(-time.Hour), TodoID: base.ID, BaseVersion: base.Version,
+		Update: &model.UpdateTodoRequest{Title: &clientTitle, Description: &description, Priority: &priority, DueDate: &clientDue},
+	})[0]
+	if r.Outcome != model.OutcomeMerged {
+		t.Fatalf("outcome = %s (%s), want merged", r.Outcome, r.Error)
+	}
+	if r.Conflict == nil || r.Conflict.Winner != "server" || !slices.Equal(r.Conflict.Fields, []string{"title", "due_date"}) {
+		t.Errorf("conflict = %+v, want the server winning title and due_date", r.Conflict)
+	}
+	got := r.Todo
+	if got == nil || got.Title != serverTitle || got.DueDate == nil || *got.DueDate != serverDue || got.Description != description || got.Priority != priority {
+		t.Errorf("merged TODO = %+v, want the server's title and due date with the client's description and priority", got)
+	}
+
+	// Made after, to the same base: the client wins the field both changed.
+	clientTitle = "client again"
+	r = syncChanges(t, h, model.SyncMerge, model.SyncChange{
+		Op: model.SyncUpdate, ChangedAt: got.UpdatedAt.Add(time.Hour), TodoID: base.ID, BaseVersion: base.Version,
+		Update: &model.UpdateTodoRequest{Title: &clientTitle},
+	})[0]
+	if r.Outcome != model.OutcomeMerged || r.Conflict == nil || r.Conflict.Winner != "client" || r.Todo == nil || r.Todo.Title != clientTitle {
+		t.Errorf("got %s with conflict %+v and TODO %+v, want the client's title merged", r.Outcome, r.Conflict, r.Todo)
+	}
+
+	// Changes to fields the server left alone merge without a conflict.
+	progress := 40
+	r = syncChanges(t, h, model.SyncMerge, model.SyncChange{
+		Op: model.SyncUpdate, ChangedAt: time.Now().Add(-24 * time.Hour), TodoID: base.ID, BaseVersion: server.Version,
+		Update: &model.UpdateTodoRequest{ProgressPercent: &progress},
+	})[0]
+	if r.Outcome != model.OutcomeMerged || r.Conflict != nil || r.Todo == nil || r.Todo.ProgressPercent != progress {
+		t.Errorf("got %s with conflict %+v and TODO %+v, want progress merged without a conflict", r.Outcome, r.Conflict, r.Todo)
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import "github.com/danielgtaylor/huma/v2"
+
+// Tags describes the groups operations are tagged with, in the order the
+// docs list them. Every tag an operation uses should be listed here.
+var Tags = []*huma.Tag{
+	{Name: "todos", Description: "Create, change, and look up TODO items, and follow their history."},
+	{Name: "trash", Description: "Restore deleted TODOs, or purge them for good before the trash retention period does."},
+	{Name: "planning", Description: "Plan work across TODOs: the kanban board, the week and month views, the daily agenda, and the next TODO to work on."},
+	{Name: "inbox", Description: "Capture TODOs quickly and triage them into categories and projects later."},
+	{Name: "projects", Description: "Group TODOs into projects."},
+	{Name: "categories", Description: "Manage the categories TODOs are filed under."},
+	{Name: "custom-fields", Description: "Define custom fields that carry metadata of your own on TODOs."},
+	{Name: "views", Description: "Save filters and sort orders as named views of TODOs."},
+	{Name: "actions", Description: "Preconfigured operations, such as completing the first matching TODO, that run with a single POST."},
+	{Name: "triggers", Description: "Polling endpoints for automation services that react to new and completed TODOs."},
+	{Name: "sync", Description: "Keep a copy of the TODOs on a client in step with the server."},
+	{Name: "reports", Description: "Progress reports computed from TODO history, printable TODO reports, and subscriptions that deliver reports on a schedule."},
+	{Name: "import", Description: "Bring TODOs over from other services."},
+	{Name: "integrations", Description: "Keep TODOs in sync with other services."},
+	{Name: "sharing", Description: "Share a TODO with people without access to the API through expiring, read-only links."},
+	{Name: "notifications", Description: "Post messages about TODO events to Slack and Discord."},
+	{Name: "admin", Description: "Inspect the audit log and usage, back up the database, and export or apply server configuration."},
+}
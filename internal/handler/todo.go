@@ -5,34 +5,65 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 
 	"todo-service/internal/db"
+	"todo-service/internal/middleware"
 	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
 )
 
 // TodoHandler handles HTTP requests for TODO operations.
 type TodoHandler struct {
-	repo   *db.Repository
+	repo   db.Repository
 	logger *slog.Logger
 }
 
 // NewTodoHandler creates a new TodoHandler.
-func NewTodoHandler(repo *db.Repository, logger *slog.Logger) *TodoHandler {
+func NewTodoHandler(repo db.Repository, logger *slog.Logger) *TodoHandler {
 	return &TodoHandler{repo: repo, logger: logger}
 }
 
 // --- Input/Output types for huma ---
 
 type ListTodosInput struct {
-	Status   string `query:"status" required:"false" enum:"pending,in_progress,done" doc:"Filter by status"`
-	Category string `query:"category" required:"false" enum:"personal,work,other" doc:"Filter by category"`
+	query.Params
+	CacheParams
+	Status    string `query:"status" required:"false" enum:"pending,in_progress,done" doc:"Filter by status"`
+	Category  string `query:"category" required:"false" doc:"Filter by category name"`
+	ProjectID int64  `query:"project_id" required:"false" doc:"Filter by project"`
+	Priority  string `query:"priority" required:"false" enum:"none,low,medium,high,urgent" doc:"Filter by priority"`
+	DueFrom   string `query:"due_from" required:"false" format:"date" doc:"Only TODOs due on or after this day" example:"2026-02-16"`
+	DueTo     string `query:"due_to" required:"false" format:"date" doc:"Only TODOs due on or before this day" example:"2026-02-22"`
+	Assignee  string `query:"assignee" required:"false" maxLength:"100" doc:"Filter by assignee; me for the caller's TODOs"`
+	Archived  bool   `query:"archived" required:"false" doc:"List archived TODOs instead of active ones"`
+	Pinned    bool   `query:"pinned" required:"false" doc:"Only list pinned TODOs"`
+	Snoozed   bool   `query:"snoozed" required:"false" doc:"List snoozed TODOs instead of awake ones"`
+
+	CustomFields map[string]string `query:"custom_fields,deepObject" required:"false" doc:"Filter by custom field values, as custom_fields[name]=value; numbers match numerically"`
+
+	Near     string  `query:"near" required:"false" example:"51.5072,-0.1276" doc:"Only TODOs located within radius_km of this point, as latitude,longitude"`
+	RadiusKm float64 `query:"radius_km" required:"false" default:"5" exclusiveMinimum:"0" maximum:"20038" doc:"Radius around near, in kilometres"`
+
+	CompletedFrom time.Time `query:"completed_from" required:"false" doc:"Only TODOs completed at or after this time (RFC 3339)"`
+	CompletedTo   time.Time `query:"completed_to" required:"false" doc:"Only TODOs completed at or before this time (RFC 3339)"`
 }
 
 type ListTodosOutput struct {
-	Body model.TodoListResponse
+	ETag         string    `header:"ETag" doc:"Identifies this page of results for conditional requests"`
+	LastModified time.Time `header:"Last-Modified" doc:"Time of the most recent change to any TODO"`
+	Link         string    `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount   int       `header:"X-Total-Count" doc:"Number of TODOs matching the filters"`
+	Body         model.TodoListResponse
 }
 
 type CreateTodoInput struct {
@@ -40,30 +71,134 @@ type CreateTodoInput struct {
 }
 
 type CreateTodoOutput struct {
+	ETag string `header:"ETag" doc:"Current version of the TODO"`
 	Body model.Todo
 }
 
 type GetTodoInput struct {
+	CacheParams
 	ID int64 `path:"id" doc:"TODO ID" example:"1"`
 }
 
 type GetTodoOutput struct {
-	Body model.Todo
+	ETag         string    `header:"ETag" doc:"Current version of the TODO"`
+	LastModified time.Time `header:"Last-Modified" doc:"Time the TODO was last updated"`
+	Body         model.Todo
 }
 
 type UpdateTodoInput struct {
-	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
-	Body model.UpdateTodoRequest
+	ID      int64  `path:"id" doc:"TODO ID" example:"1"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being updated, or * to update unconditionally" example:"\"1\""`
+	Body    model.UpdateTodoRequest
 }
 
 type UpdateTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
 	Body model.Todo
 }
 
-type DeleteTodoInput struct {
+type ArchiveTodoInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type ArchiveTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type ChangeStatusInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type ChangeStatusOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type ScheduleTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.ScheduleTodoRequest
+}
+
+type UnscheduleTodoInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type ScheduleTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type AssignTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.AssignTodoRequest
+}
+
+type UnassignTodoInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type AssignTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type SnoozeTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.SnoozeTodoRequest
+}
+
+type UnsnoozeTodoInput struct {
 	ID int64 `path:"id" doc:"TODO ID" example:"1"`
 }
 
+type SnoozeTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type LocateTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.Location
+}
+
+type UnlocateTodoInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type LocateTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type PinTodoInput struct {
+	ID int64 `path:"id" doc:"TODO ID" example:"1"`
+}
+
+type PinTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type MoveTodoInput struct {
+	ID   int64 `path:"id" doc:"TODO ID" example:"1"`
+	Body model.MoveTodoRequest
+}
+
+type MoveTodoOutput struct {
+	ETag string `header:"ETag" doc:"New version of the TODO"`
+	Body model.Todo
+}
+
+type DuplicateTodoInput struct {
+	ID int64 `path:"id" doc:"ID of the TODO to copy" example:"1"`
+}
+
+type DeleteTodoInput struct {
+	ID      int64  `path:"id" doc:"TODO ID" example:"1"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being deleted, or * to delete unconditionally" example:"\"1\""`
+}
+
 // RegisterRoutes registers all TODO routes with the huma API.
 func (h *TodoHandler) RegisterRoutes(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -71,8 +206,9 @@ func (h *TodoHandler) RegisterRoutes(api huma.API) {
 		Method:      http.MethodGet,
 		Path:        "/api/v1/todos",
 		Summary:     "List all TODOs",
-		Description: "Retrieve all TODO items, optionally filtered by status and/or category.",
+		Description: "Retrieve all unarchived TODO items (or only archived ones with archived=true), optionally filtered by status, category, project, priority, due date range, and/or completion time. Supports sorting and limit/offset pagination; unpaginated requests matching more than 5000 items are rejected. Honors If-None-Match and If-Modified-Since with 304 Not Modified.",
 		Tags:        []string{"todos"},
+		Errors:      []int{400},
 	}, h.ListTodos)
 
 	huma.Register(api, huma.Operation{
@@ -80,8 +216,9 @@ func (h *TodoHandler) RegisterRoutes(api huma.API) {
 		Method:        http.MethodPost,
 		Path:          "/api/v1/todos",
 		Summary:       "Create a new TODO",
-		Description:   "Create a new TODO item with optional progress tracking.",
+		Description:   "Create a new TODO item with optional progress tracking. Responds 422, listing each broken rule, if the TODO breaks the cross-field rules the server is configured with, such as requiring an estimate in some category, or if a custom field value does not exist or is not a value of its field.",
 		Tags:          []string{"todos"},
+		Errors:        []int{400, 422},
 		DefaultStatus: http.StatusCreated,
 	}, h.CreateTodo)
 
@@ -90,8 +227,9 @@ func (h *TodoHandler) RegisterRoutes(api huma.API) {
 		Method:      http.MethodGet,
 		Path:        "/api/v1/todos/{id}",
 		Summary:     "Get a TODO by ID",
-		Description: "Retrieve a single TODO item by its ID.",
+		Description: "Retrieve a single TODO item by its ID. The ETag header carries the version required by If-Match on updates and deletes, and with Last-Modified can be sent back in If-None-Match or If-Modified-Since to receive 304 Not Modified.",
 		Tags:        []string{"todos"},
+		Errors:      []int{404},
 	}, h.GetTodo)
 
 	huma.Register(api, huma.Operation{
@@ -99,118 +237,791 @@ func (h *TodoHandler) RegisterRoutes(api huma.API) {
 		Method:      http.MethodPut,
 		Path:        "/api/v1/todos/{id}",
 		Summary:     "Update a TODO",
-		Description: "Update an existing TODO item. Only provided fields are changed.",
+		Description: "Update an existing TODO item. Only provided fields are changed. Setting status to done also sets progress to 100 and records completed_at. Status changes the server is not configured to allow, such as moving a done TODO back to pending, are rejected with 409; use the complete and reopen actions instead. Responds 422, listing each broken rule, if the updated TODO would break the cross-field rules the server is configured with, or if a custom field value does not exist or is not a value of its field. Only the custom field values named are changed, and null removes one. Requires an If-Match header; responds 412 if the TODO has changed since that ETag was issued.",
 		Tags:        []string{"todos"},
+		Errors:      []int{400, 404, 409, 412, 422, 428},
 	}, h.UpdateTodo)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "complete-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/complete",
+		Summary:     "Complete a TODO",
+		Description: "Mark a TODO item done from any status, setting its progress to 100 and recording completed_at. Completing a done TODO has no effect.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.CompleteTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "reopen-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/reopen",
+		Summary:     "Reopen a TODO",
+		Description: "Move a done TODO item back to pending and clear completed_at. Its progress is kept. Reopening a TODO that is not done has no effect.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.ReopenTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "archive-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/archive",
+		Summary:     "Archive a TODO",
+		Description: "Archive a TODO item, hiding it from default listings. Archived TODOs can be listed with archived=true.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.ArchiveTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unarchive-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unarchive",
+		Summary:     "Unarchive a TODO",
+		Description: "Restore an archived TODO item to default listings.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnarchiveTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "schedule-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/schedule",
+		Summary:     "Schedule a TODO",
+		Description: "Plan a TODO item for a day, replacing any earlier schedule. Scheduled TODOs appear in the week view.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404},
+	}, h.ScheduleTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unschedule-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unschedule",
+		Summary:     "Unschedule a TODO",
+		Description: "Remove a TODO item from the day it was planned for.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnscheduleTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "assign-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/assign",
+		Summary:     "Assign a TODO",
+		Description: "Assign a TODO item to someone, replacing any earlier assignee. There are no user accounts, so assignees are named the way the audit log names actors: by the X-Actor header they send, or their address if they send none. me assigns the TODO to the caller. List someone's TODOs with assignee, or the caller's with assignee=me; notification routes can post an assigned event.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404},
+	}, h.AssignTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unassign-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unassign",
+		Summary:     "Unassign a TODO",
+		Description: "Remove a TODO item's assignee.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnassignTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "snooze-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/snooze",
+		Summary:     "Snooze a TODO",
+		Description: "Hide a TODO item from the TODO list, the board, the inbox, and the next TODO to work on until a time, given as a number of minutes from now or as a timestamp, replacing any earlier snooze. The TODO wakes up within a minute of that time; notification routes can post a woke event. List snoozed TODOs with snoozed=true.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404},
+	}, h.SnoozeTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unsnooze-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unsnooze",
+		Summary:     "Unsnooze a TODO",
+		Description: "Wake a snoozed TODO item now.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnsnoozeTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "locate-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/locate",
+		Summary:     "Set a TODO's location",
+		Description: "Tie a TODO item to a place, replacing any earlier location, so that location-aware clients can list the TODOs near them with near and radius_km.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404},
+	}, h.LocateTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unlocate-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unlocate",
+		Summary:     "Remove a TODO's location",
+		Description: "Remove the place a TODO item is tied to.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnlocateTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "pin-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/pin",
+		Summary:     "Pin a TODO",
+		Description: "Pin a TODO item so that it is listed before all unpinned ones, in lists and on the board, whatever the sort. Pinned TODOs can be listed on their own with pinned=true.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.PinTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unpin-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/unpin",
+		Summary:     "Unpin a TODO",
+		Description: "Return a pinned TODO item to its place in the sort order.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404},
+	}, h.UnpinTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "move-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/todos/{id}/move",
+		Summary:     "Move a TODO",
+		Description: "Change a TODO item's place in the manual order, for drag-and-drop reordering: just before or after another TODO, or to an index among the other active TODOs (archived ones if it is archived). List TODOs with sort=position to see the order. Responds 422 if the other TODO does not exist.",
+		Tags:        []string{"todos"},
+		Errors:      []int{400, 404, 422},
+	}, h.MoveTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "duplicate-todo",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/todos/{id}/duplicate",
+		Summary:       "Duplicate a TODO",
+		Description:   "Create a copy of a TODO item with the same title, description, category, project, priority, and due date. The copy is pending, has no progress, and is not archived.",
+		Tags:          []string{"todos"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusCreated,
+	}, h.DuplicateTodo)
+
 	huma.Register(api, huma.Operation{
 		OperationID:   "delete-todo",
 		Method:        http.MethodDelete,
 		Path:          "/api/v1/todos/{id}",
 		Summary:       "Delete a TODO",
-		Description:   "Delete a TODO item by its ID.",
+		Description:   "Delete a TODO item by its ID, moving it to the trash, from which it can be restored until it is purged. Requires an If-Match header; responds 412 if the TODO has changed since that ETag was issued.",
 		Tags:          []string{"todos"},
+		Errors:        []int{400, 404, 412, 428},
 		DefaultStatus: http.StatusNoContent,
 	}, h.DeleteTodo)
 }
 
 func (h *TodoHandler) ListTodos(ctx context.Context, input *ListTodosInput) (*ListTodosOutput, error) {
-	var statusFilter *model.Status
+	opts, err := input.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := db.TodoFilter{Archived: &input.Archived, Snoozed: &input.Snoozed}
 	if input.Status != "" {
 		s := model.Status(input.Status)
-		statusFilter = &s
+		filter.Status = &s
 	}
-
-	var categoryFilter *model.Category
 	if input.Category != "" {
 		c := model.Category(input.Category)
-		categoryFilter = &c
+		filter.Category = &c
+	}
+	if input.ProjectID != 0 {
+		filter.ProjectID = &input.ProjectID
+	}
+	if input.Priority != "" {
+		p := model.Priority(input.Priority)
+		filter.Priority = &p
+	}
+	if input.DueFrom != "" {
+		filter.DueFrom = &input.DueFrom
+	}
+	if input.DueTo != "" {
+		filter.DueTo = &input.DueTo
+	}
+	if input.Assignee != "" {
+		a := assignee(ctx, input.Assignee)
+		filter.Assignee = &a
+	}
+	if input.Pinned {
+		filter.Pinned = &input.Pinned
+	}
+	if input.Near != "" {
+		if filter.Near, err = nearFilter(input.Near, input.RadiusKm); err != nil {
+			return nil, err
+		}
+	}
+	if len(input.CustomFields) > 0 {
+		stopDB := timing.Track(ctx, timing.StageDB)
+		fields, err := h.repo.CustomFieldsByName()
+		stopDB()
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get custom fields", slog.String("error", err.Error()))
+			return nil, huma.Error500InternalServerError("failed to retrieve todos")
+		}
+		if filter.CustomFields, err = customFieldFilter(fields, input.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+	if !input.CompletedFrom.IsZero() {
+		filter.CompletedFrom = &input.CompletedFrom
+	}
+	if !input.CompletedTo.IsZero() {
+		filter.CompletedTo = &input.CompletedTo
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	total, err := h.repo.CountTodos(filter)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve todos")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
 	}
 
-	todos, err := h.repo.ListTodos(statusFilter, categoryFilter)
+	stopDB = timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListTodos(filter, opts)
+	var modified time.Time
+	if err == nil {
+		modified, err = h.repo.TodosLastModified()
+	}
+	stopDB()
 	if err != nil {
-		h.logger.Error("failed to list todos", slog.String("error", err.Error()))
+		h.logger.ErrorContext(ctx, "failed to list todos", slog.String("error", err.Error()))
 		return nil, huma.Error500InternalServerError("failed to retrieve todos")
 	}
 
+	tag := listETag(todos, total)
+	if err := input.notModified(tag, modified); err != nil {
+		return nil, err
+	}
+
 	return &ListTodosOutput{
-		Body: model.TodoListResponse{Todos: todos, Count: len(todos)},
+		ETag:         tag,
+		LastModified: modified,
+		Link:         input.Links(total),
+		TotalCount:   total,
+		Body:         model.TodoListResponse{Todos: todos, Count: len(todos), Total: total},
 	}, nil
 }
 
 func (h *TodoHandler) CreateTodo(ctx context.Context, input *CreateTodoInput) (*CreateTodoOutput, error) {
-	if input.Body.Title == "" {
-		return nil, huma.Error400BadRequest("title is required")
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := validateCreateTodo(input.Body)
+	stopValidation()
+	if err != nil {
+		return nil, err
 	}
 
-	if input.Body.Status != "" && !model.ValidStatuses[input.Body.Status] {
-		return nil, huma.Error400BadRequest("status must be one of: pending, in_progress, done")
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.CreateTodo(input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrProjectNotFound) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("project with id %d not found", *input.Body.ProjectID))
 	}
-
-	if input.Body.Category != "" && !model.ValidCategories[input.Body.Category] {
-		return nil, huma.Error400BadRequest("category must be one of: personal, work, other")
+	if errors.Is(err, db.ErrCategoryNotFound) {
+		return nil, categoryNotFound(input.Body.Category)
 	}
-
-	if input.Body.ProgressPercent != nil && (*input.Body.ProgressPercent < 0 || *input.Body.ProgressPercent > 100) {
-		return nil, huma.Error400BadRequest("progress_percent must be between 0 and 100")
+	var ruleErr *db.RuleError
+	if errors.As(err, &ruleErr) {
+		return nil, ruleViolation(ruleErr)
+	}
+	var fieldErr *db.CustomFieldError
+	if errors.As(err, &fieldErr) {
+		return nil, customFieldInvalid(fieldErr)
 	}
-
-	todo, err := h.repo.CreateTodo(input.Body)
 	if err != nil {
-		h.logger.Error("failed to create todo", slog.String("error", err.Error()))
+		h.logger.ErrorContext(ctx, "failed to create todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
 		return nil, huma.Error500InternalServerError("failed to create todo")
 	}
 
-	return &CreateTodoOutput{Body: todo}, nil
+	return &CreateTodoOutput{ETag: etag(todo), Body: todo}, nil
 }
 
 func (h *TodoHandler) GetTodo(ctx context.Context, input *GetTodoInput) (*GetTodoOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
 	todo, err := h.repo.GetTodo(input.ID)
+	stopDB()
 	if errors.Is(err, db.ErrNotFound) {
-		return nil, huma.Error404NotFound(fmt.Sprintf("todo with id %d not found", input.ID))
+		return nil, todoNotFound(input.ID)
 	}
 	if err != nil {
-		h.logger.Error("failed to get todo", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		h.logger.ErrorContext(ctx, "failed to get todo", slog.String("error", err.Error()), slog.Int64("id", input.ID))
 		return nil, huma.Error500InternalServerError("failed to retrieve todo")
 	}
 
-	return &GetTodoOutput{Body: todo}, nil
+	if err := input.notModified(etag(todo), todo.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &GetTodoOutput{ETag: etag(todo), LastModified: todo.UpdatedAt, Body: todo}, nil
 }
 
 func (h *TodoHandler) UpdateTodo(ctx context.Context, input *UpdateTodoInput) (*UpdateTodoOutput, error) {
-	if input.Body.Status != nil && !model.ValidStatuses[*input.Body.Status] {
-		return nil, huma.Error400BadRequest("status must be one of: pending, in_progress, done")
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	version, err := ifMatchVersion(input.IfMatch)
+	if err == nil {
+		err = validateUpdateTodo(input.Body)
+	}
+	stopValidation()
+	if err != nil {
+		return nil, err
 	}
 
-	if input.Body.Category != nil && !model.ValidCategories[*input.Body.Category] {
-		return nil, huma.Error400BadRequest("category must be one of: personal, work, other")
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.UpdateTodo(input.ID, version, input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if errors.Is(err, db.ErrVersionMismatch) {
+		return nil, huma.Error412PreconditionFailed(fmt.Sprintf("todo with id %d has been modified; fetch it again and retry", input.ID))
+	}
+	if errors.Is(err, db.ErrProjectNotFound) {
+		return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("project with id %d not found", *input.Body.ProjectID))
+	}
+	if errors.Is(err, db.ErrCategoryNotFound) {
+		return nil, categoryNotFound(*input.Body.Category)
+	}
+	var transErr *db.TransitionError
+	if errors.As(err, &transErr) {
+		return nil, transitionConflict(transErr)
+	}
+	var ruleErr *db.RuleError
+	if errors.As(err, &ruleErr) {
+		return nil, ruleViolation(ruleErr)
 	}
+	var fieldErr *db.CustomFieldError
+	if errors.As(err, &fieldErr) {
+		return nil, customFieldInvalid(fieldErr)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &UpdateTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TodoHandler) CompleteTodo(ctx context.Context, input *ChangeStatusInput) (*ChangeStatusOutput, error) {
+	return h.changeStatus(ctx, input.ID, "complete", h.repo.CompleteTodo)
+}
+
+func (h *TodoHandler) ReopenTodo(ctx context.Context, input *ChangeStatusInput) (*ChangeStatusOutput, error) {
+	return h.changeStatus(ctx, input.ID, "reopen", h.repo.ReopenTodo)
+}
+
+func (h *TodoHandler) changeStatus(ctx context.Context, id int64, action string, change func(int64, db.AuditInfo) (model.Todo, error)) (*ChangeStatusOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := change(id, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to "+action+" todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &ChangeStatusOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+// transitionConflict explains a rejected status change, pointing at the
+// reopen action when the TODO is done.
+func transitionConflict(err *db.TransitionError) error {
+	msg := fmt.Sprintf("cannot change status from %s to %s", err.From, err.To)
+	if err.From == model.StatusDone {
+		msg += "; reopen the todo first"
+	}
+	return huma.Error409Conflict(msg)
+}
+
+// ruleViolation lists the rules a TODO would break, each located at the
+// field the rule requires something of.
+func ruleViolation(err *db.RuleError) error {
+	details := make([]error, len(err.Violations))
+	for i, v := range err.Violations {
+		details[i] = &huma.ErrorDetail{Location: "body." + v.Field, Message: fmt.Sprintf("%s (rule %q)", v.Message, v.Rule)}
+	}
+	return huma.Error422UnprocessableEntity("todo breaks the server's validation rules", details...)
+}
+
+// customFieldInvalid reports a custom field value the repository refused.
+func customFieldInvalid(err *db.CustomFieldError) error {
+	return huma.Error422UnprocessableEntity("invalid custom field value", &huma.ErrorDetail{
+		Location: "body.custom_fields." + err.Field,
+		Message:  err.Message,
+	})
+}
+
+// customFieldFilter converts the custom_fields query parameter into values
+// of the fields it names.
+func customFieldFilter(fields map[string]model.CustomField, params map[string]string) (map[string]any, error) {
+	values := make(map[string]any, len(params))
+	var details []error
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		location := fmt.Sprintf("query.custom_fields[%s]", name)
+		field, ok := fields[name]
+		if !ok {
+			details = append(details, &huma.ErrorDetail{Location: location, Message: fmt.Sprintf("no custom field is named %q", name)})
+			continue
+		}
+		v, err := field.Parse(params[name])
+		if err != nil {
+			details = append(details, &huma.ErrorDetail{Location: location, Message: err.Error()})
+			continue
+		}
+		values[name] = v
+	}
+	if len(details) > 0 {
+		return nil, huma.Error400BadRequest("invalid custom field filter", details...)
+	}
+	return values, nil
+}
+
+// nearFilter parses the point a TODO list is filtered by the distance from,
+// formatted as latitude,longitude.
+func nearFilter(near string, radiusKm float64) (*db.Near, error) {
+	lat, lng, ok := strings.Cut(near, ",")
+	var point model.Location
+	var latErr, lngErr error
+	point.Latitude, latErr = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	point.Longitude, lngErr = strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if !ok || latErr != nil || lngErr != nil {
+		return nil, huma.Error400BadRequest("invalid near filter", &huma.ErrorDetail{
+			Location: "query.near",
+			Message:  "near must be formatted as latitude,longitude",
+			Value:    near,
+		})
+	}
+	if err := invalidInput("query", validate.Near(point)); err != nil {
+		return nil, err
+	}
+	return &db.Near{Latitude: point.Latitude, Longitude: point.Longitude, RadiusKm: radiusKm}, nil
+}
+
+func (h *TodoHandler) ArchiveTodo(ctx context.Context, input *ArchiveTodoInput) (*ArchiveTodoOutput, error) {
+	return h.setArchived(ctx, input.ID, true)
+}
+
+func (h *TodoHandler) UnarchiveTodo(ctx context.Context, input *ArchiveTodoInput) (*ArchiveTodoOutput, error) {
+	return h.setArchived(ctx, input.ID, false)
+}
+
+func (h *TodoHandler) setArchived(ctx context.Context, id int64, archived bool) (*ArchiveTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetArchived(id, archived, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to archive todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id), slog.Bool("archived", archived))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &ArchiveTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TodoHandler) ScheduleTodo(ctx context.Context, input *ScheduleTodoInput) (*ScheduleTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.ScheduleTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.setSchedule(ctx, input.ID, &input.Body.Date)
+}
+
+func (h *TodoHandler) UnscheduleTodo(ctx context.Context, input *UnscheduleTodoInput) (*ScheduleTodoOutput, error) {
+	return h.setSchedule(ctx, input.ID, nil)
+}
+
+func (h *TodoHandler) setSchedule(ctx context.Context, id int64, date *string) (*ScheduleTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetSchedule(id, date, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to schedule todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &ScheduleTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TodoHandler) AssignTodo(ctx context.Context, input *AssignTodoInput) (*AssignTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.AssignTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.setAssignee(ctx, input.ID, assignee(ctx, strings.TrimSpace(input.Body.Assignee)))
+}
+
+func (h *TodoHandler) UnassignTodo(ctx context.Context, input *UnassignTodoInput) (*AssignTodoOutput, error) {
+	return h.setAssignee(ctx, input.ID, "")
+}
+
+func (h *TodoHandler) setAssignee(ctx context.Context, id int64, assignee string) (*AssignTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetAssignee(id, assignee, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to assign todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &AssignTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TodoHandler) SnoozeTodo(ctx context.Context, input *SnoozeTodoInput) (*SnoozeTodoOutput, error) {
+	now := time.Now()
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.SnoozeTodo(input.Body, now))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	until := input.Body.Until
+	if until == nil {
+		u := now.Add(time.Duration(input.Body.Minutes) * time.Minute)
+		until = &u
+	}
+	return h.setSnooze(ctx, input.ID, until)
+}
+
+func (h *TodoHandler) UnsnoozeTodo(ctx context.Context, input *UnsnoozeTodoInput) (*SnoozeTodoOutput, error) {
+	return h.setSnooze(ctx, input.ID, nil)
+}
+
+func (h *TodoHandler) setSnooze(ctx context.Context, id int64, until *time.Time) (*SnoozeTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetSnooze(id, until, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to snooze todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &SnoozeTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
 
-	if input.Body.ProgressPercent != nil && (*input.Body.ProgressPercent < 0 || *input.Body.ProgressPercent > 100) {
-		return nil, huma.Error400BadRequest("progress_percent must be between 0 and 100")
+func (h *TodoHandler) LocateTodo(ctx context.Context, input *LocateTodoInput) (*LocateTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.LocateTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
 	}
 
-	todo, err := h.repo.UpdateTodo(input.ID, input.Body)
+	return h.setLocation(ctx, input.ID, &input.Body)
+}
+
+func (h *TodoHandler) UnlocateTodo(ctx context.Context, input *UnlocateTodoInput) (*LocateTodoOutput, error) {
+	return h.setLocation(ctx, input.ID, nil)
+}
+
+func (h *TodoHandler) setLocation(ctx context.Context, id int64, loc *model.Location) (*LocateTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetLocation(id, loc, info)
+	stopDB()
 	if errors.Is(err, db.ErrNotFound) {
-		return nil, huma.Error404NotFound(fmt.Sprintf("todo with id %d not found", input.ID))
+		return nil, todoNotFound(id)
 	}
 	if err != nil {
-		h.logger.Error("failed to update todo", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		h.logger.ErrorContext(ctx, "failed to locate todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id))
 		return nil, huma.Error500InternalServerError("failed to update todo")
 	}
 
-	return &UpdateTodoOutput{Body: todo}, nil
+	return &LocateTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TodoHandler) PinTodo(ctx context.Context, input *PinTodoInput) (*PinTodoOutput, error) {
+	return h.setPinned(ctx, input.ID, true)
+}
+
+func (h *TodoHandler) UnpinTodo(ctx context.Context, input *PinTodoInput) (*PinTodoOutput, error) {
+	return h.setPinned(ctx, input.ID, false)
+}
+
+func (h *TodoHandler) setPinned(ctx context.Context, id int64, pinned bool) (*PinTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.SetPinned(id, pinned, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(id)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to pin todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", id), slog.Bool("pinned", pinned))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &PinTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+// assignee resolves the name me to the caller's actor name.
+func assignee(ctx context.Context, name string) string {
+	if name == "me" {
+		return middleware.GetActor(ctx)
+	}
+	return name
+}
+
+func (h *TodoHandler) MoveTodo(ctx context.Context, input *MoveTodoInput) (*MoveTodoOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	err := badRequest(validate.MoveTodo(input.Body))
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+	target := moveTarget(input.Body)
+	if target == input.ID {
+		const msg = "a todo cannot be moved relative to itself"
+		return nil, huma.Error400BadRequest(msg, &huma.ErrorDetail{Location: "body", Message: msg, Value: target})
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.MoveTodo(input.ID, input.Body, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if errors.Is(err, db.ErrMoveTargetNotFound) {
+		return nil, withCode(model.CodeTodoNotFound, huma.Error422UnprocessableEntity(fmt.Sprintf("todo with id %d not found", target)))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to move todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update todo")
+	}
+
+	return &MoveTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+// moveTarget returns the TODO a move is relative to, or 0 for a move to an
+// index.
+func moveTarget(req model.MoveTodoRequest) int64 {
+	switch {
+	case req.Before != nil:
+		return *req.Before
+	case req.After != nil:
+		return *req.After
+	}
+	return 0
+}
+
+func (h *TodoHandler) DuplicateTodo(ctx context.Context, input *DuplicateTodoInput) (*CreateTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.DuplicateTodo(input.ID, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, todoNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to duplicate todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to duplicate todo")
+	}
+
+	return &CreateTodoOutput{ETag: etag(todo), Body: todo}, nil
 }
 
 func (h *TodoHandler) DeleteTodo(ctx context.Context, input *DeleteTodoInput) (*struct{}, error) {
-	err := h.repo.DeleteTodo(input.ID)
+	version, err := ifMatchVersion(input.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err = h.repo.DeleteTodo(input.ID, version, info)
+	stopDB()
 	if errors.Is(err, db.ErrNotFound) {
-		return nil, huma.Error404NotFound(fmt.Sprintf("todo with id %d not found", input.ID))
+		return nil, todoNotFound(input.ID)
+	}
+	if errors.Is(err, db.ErrVersionMismatch) {
+		return nil, huma.Error412PreconditionFailed(fmt.Sprintf("todo with id %d has been modified; fetch it again and retry", input.ID))
 	}
 	if err != nil {
-		h.logger.Error("failed to delete todo", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		h.logger.ErrorContext(ctx, "failed to delete todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
 		return nil, huma.Error500InternalServerError("failed to delete todo")
 	}
 
 	return nil, nil
 }
+
+// validateCreateTodo checks a create payload beyond what the schema enforces.
+func validateCreateTodo(req model.CreateTodoRequest) error {
+	return badRequest(validate.CreateTodo(req))
+}
+
+// validateUpdateTodo checks an update payload beyond what the schema enforces.
+func validateUpdateTodo(req model.UpdateTodoRequest) error {
+	return badRequest(validate.UpdateTodo(req))
+}
+
+// categoryNotFound reports that a TODO cannot be filed under category,
+// or under any category when none was given because none exist.
+func categoryNotFound(category model.Category) error {
+	if category == "" {
+		return huma.Error422UnprocessableEntity("no categories exist; create one first")
+	}
+	return huma.Error422UnprocessableEntity(fmt.Sprintf("category %q not found", category))
+}
+
+// badRequest converts a validation error for a request body into a huma 400
+// error.
+func badRequest(err error) error {
+	return invalidInput("body", err)
+}
+
+// invalidInput converts a validation error into a huma 400 error listing
+// each invalid field, located within the request part in, such as body or
+// query, the way huma reports schema violations.
+func invalidInput(in string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		return huma.Error400BadRequest(err.Error())
+	}
+	details := make([]error, len(errs))
+	for i, e := range errs {
+		location := in
+		if e.Field != "" {
+			location += "." + e.Field
+		}
+		details[i] = &huma.ErrorDetail{Location: location, Message: e.Message}
+	}
+	return huma.Error400BadRequest(err.Error(), details...)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+func newTestAPI(t *testing.T) humatest.TestAPI {
+	t.Helper()
+	repo, err := db.NewMemory(slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("NewMemory: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	_, api := humatest.New(t)
+	NewTodoHandler(repo, slog.New(slog.DiscardHandler)).RegisterRoutes(api)
+	return api
+}
+
+func TestTodoCreateGetUpdateDelete(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp := api.Post("/api/v1/todos", map[string]any{"title": "Write tests", "description": "", "category": "work"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("create: got %d, want %d: %s", resp.Code, http.StatusCreated, resp.Body)
+	}
+	var created model.Todo
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created todo: %v", err)
+	}
+	if created.Title != "Write tests" || created.Category != model.CategoryWork {
+		t.Fatalf("created %+v, want title %q and category work", created, "Write tests")
+	}
+
+	path := fmt.Sprintf("/api/v1/todos/%d", created.ID)
+	resp = api.Get(path)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("get: got %d, want %d: %s", resp.Code, http.StatusOK, resp.Body)
+	}
+	etag := resp.Header().Get("ETag")
+
+	resp = api.Put(path, map[string]any{"title": "Write more tests"})
+	if resp.Code != http.StatusPreconditionRequired {
+		t.Fatalf("update without If-Match: got %d, want %d", resp.Code, http.StatusPreconditionRequired)
+	}
+	resp = api.Put(path, "If-Match: "+etag, map[string]any{"title": "Write more tests"})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("update: got %d, want %d: %s", resp.Code, http.StatusOK, resp.Body)
+	}
+	updated := resp.Header().Get("ETag")
+	resp = api.Put(path, "If-Match: "+etag, map[string]any{"title": "Stale"})
+	if resp.Code != http.StatusPreconditionFailed {
+		t.Fatalf("update with stale If-Match: got %d, want %d", resp.Code, http.StatusPreconditionFailed)
+	}
+
+	resp = api.Delete(path, "If-Match: "+updated)
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("delete: got %d, want %d: %s", resp.Code, http.StatusNoContent, resp.Body)
+	}
+	if resp = api.Get(path); resp.Code != http.StatusNotFound {
+		t.Fatalf("get after delete: got %d, want %d", resp.Code, http.StatusNotFound)
+	}
+}
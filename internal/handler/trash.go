@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+)
+
+// TrashHandler handles HTTP requests for deleted TODOs.
+type TrashHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewTrashHandler creates a new TrashHandler.
+func NewTrashHandler(repo db.Repository, logger *slog.Logger) *TrashHandler {
+	return &TrashHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListTrashInput struct {
+	query.Params
+}
+
+type ListTrashOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of TODOs in the trash"`
+	Body       model.TrashListResponse
+}
+
+type TrashIDInput struct {
+	ID int64 `path:"id" doc:"ID of the deleted TODO" example:"1"`
+}
+
+type RestoreTodoOutput struct {
+	ETag string `header:"ETag" doc:"Version of the restored TODO"`
+	Body model.Todo
+}
+
+// RegisterRoutes registers all trash routes with the huma API.
+func (h *TrashHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-trash",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/trash",
+		Summary:     "List deleted TODOs",
+		Description: "Retrieve the TODOs in the trash as they were when they were deleted, most recently deleted first by default, with who deleted them and when they will be purged. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"trash"},
+		Errors:      []int{400},
+	}, h.ListTrash)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-todo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/trash/{id}/restore",
+		Summary:     "Restore a deleted TODO",
+		Description: "Take a TODO out of the trash, bringing it back under its ID as it was when it was deleted. Its project is cleared if the project has been deleted since, its category replaced with the default one if the category has, and values of deleted custom fields are dropped. The restoration is recorded in the audit log as a creation.",
+		Tags:        []string{"trash"},
+		Errors:      []int{404},
+	}, h.RestoreTodo)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "purge-todo",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/trash/{id}",
+		Summary:       "Purge a deleted TODO",
+		Description:   "Remove a TODO from the trash for good, before the trash retention period does.",
+		Tags:          []string{"trash"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.PurgeTodo)
+}
+
+func (h *TrashHandler) ListTrash(ctx context.Context, input *ListTrashInput) (*ListTrashOutput, error) {
+	opts, err := input.Options(db.TrashSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountTrash()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count trash", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve trash")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	todos, err := h.repo.ListTrash(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list trash", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve trash")
+	}
+
+	return &ListTrashOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.TrashListResponse{Todos: todos, Count: len(todos), Total: total},
+	}, nil
+}
+
+func (h *TrashHandler) RestoreTodo(ctx context.Context, input *TrashIDInput) (*RestoreTodoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	todo, err := h.repo.RestoreTodo(input.ID, info)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, trashedTodoNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to restore todo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to restore todo")
+	}
+
+	h.logger.InfoContext(ctx, "restored todo", slog.Int64("id", todo.ID), slog.String("operation_id", info.OperationID))
+	return &RestoreTodoOutput{ETag: etag(todo), Body: todo}, nil
+}
+
+func (h *TrashHandler) PurgeTodo(ctx context.Context, input *TrashIDInput) (*struct{}, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err := h.repo.PurgeTodo(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, trashedTodoNotFound(input.ID)
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to purge todo", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to purge todo")
+	}
+
+	h.logger.InfoContext(ctx, "purged todo", slog.Int64("id", input.ID))
+	return nil, nil
+}
+
+func trashedTodoNotFound(id int64) error {
+	return huma.Error404NotFound(fmt.Sprintf("todo with id %d is not in the trash", id))
+}
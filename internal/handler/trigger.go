@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+)
+
+// TriggerHandler handles HTTP requests for polling triggers, which let
+// low-code platforms such as Zapier and n8n react to changes without
+// webhooks.
+type TriggerHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewTriggerHandler creates a new TriggerHandler.
+func NewTriggerHandler(repo db.Repository, logger *slog.Logger) *TriggerHandler {
+	return &TriggerHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type TriggerInput struct {
+	Cursor int64 `query:"cursor" required:"false" minimum:"0" doc:"Only return events after this cursor, the largest one seen so far; omit to get the latest events"`
+	Limit  int   `query:"limit" required:"false" minimum:"1" maximum:"100" default:"50" doc:"Maximum number of events to return"`
+}
+
+type TriggerOutput struct {
+	Body []model.TriggerEvent
+}
+
+// triggerDescription explains the contract shared by every trigger.
+const triggerDescription = " Events are returned newest first, each with a unique id to deduplicate on. Without a cursor, the latest events are returned; with one, the earliest events after it are, so that polling with the largest cursor seen never skips any. Events for TODOs that have since been deleted are left out."
+
+// RegisterRoutes registers the trigger routes with the huma API.
+func (h *TriggerHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "trigger-new-todos",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/triggers/new-todos",
+		Summary:     "Poll for new TODOs",
+		Description: "List TODOs as they are created, for a polling trigger." + triggerDescription,
+		Tags:        []string{"triggers"},
+		Errors:      []int{400},
+	}, h.NewTodos)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "trigger-completed-todos",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/triggers/completed-todos",
+		Summary:     "Poll for completed TODOs",
+		Description: "List TODOs as they are completed, for a polling trigger. A TODO that is reopened and completed again is reported again." + triggerDescription,
+		Tags:        []string{"triggers"},
+		Errors:      []int{400},
+	}, h.CompletedTodos)
+}
+
+func (h *TriggerHandler) NewTodos(ctx context.Context, input *TriggerInput) (*TriggerOutput, error) {
+	action := model.AuditActionCreate
+	return h.events(ctx, input, "todo_created", db.AuditFilter{Action: &action})
+}
+
+func (h *TriggerHandler) CompletedTodos(ctx context.Context, input *TriggerInput) (*TriggerOutput, error) {
+	done := model.StatusDone
+	return h.events(ctx, input, "todo_completed", db.AuditFilter{StatusTo: &done})
+}
+
+// events lists the audit entries matching filter as trigger events whose
+// IDs start with kind.
+func (h *TriggerHandler) events(ctx context.Context, input *TriggerInput, kind string, filter db.AuditFilter) (*TriggerOutput, error) {
+	params := query.Params{Limit: input.Limit, Sort: "-id"}
+	if input.Cursor > 0 {
+		filter.AfterID = &input.Cursor
+		params.Sort = "id"
+	}
+	opts, err := params.Options(db.AuditSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	entries, err := h.repo.ListAudit(filter, opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list trigger events", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve events")
+	}
+
+	events := []model.TriggerEvent{}
+	for _, e := range entries {
+		todo, err := h.repo.GetTodo(e.TodoID)
+		if errors.Is(err, db.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to get todo", slog.String("error", err.Error()), slog.Int64("id", e.TodoID))
+			return nil, huma.Error500InternalServerError("failed to retrieve events")
+		}
+		events = append(events, model.TriggerEvent{
+			ID:         fmt.Sprintf("%s_%d", kind, e.ID),
+			Cursor:     e.ID,
+			OccurredAt: e.CreatedAt,
+			Actor:      e.Actor,
+			Todo:       todo,
+		})
+	}
+	if input.Cursor > 0 {
+		slices.Reverse(events)
+	}
+
+	return &TriggerOutput{Body: events}, nil
+}
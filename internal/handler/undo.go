@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/timing"
+)
+
+// UndoHandler handles HTTP requests to undo recent TODO changes.
+type UndoHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewUndoHandler creates a new UndoHandler.
+func NewUndoHandler(repo db.Repository, logger *slog.Logger) *UndoHandler {
+	return &UndoHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type UndoOutput struct {
+	Body model.UndoResponse
+}
+
+// RegisterRoutes registers the undo route with the huma API.
+func (h *UndoHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "undo",
+		Method:      http.MethodPost,
+		Path:        "/api/v1/undo",
+		Summary:     "Undo the most recent change",
+		Description: "Reverse the most recent operation the caller made on TODOs: TODOs it updated or deleted are put back as they were before it, and TODOs it created are deleted to the trash. Callers are told apart as the audit log tells actors apart, by the X-Actor header or else the client address. Each call undoes one more operation, back to a configured number of operations made within a configured window. Responds 404 if there is nothing left to undo, and 409 if a TODO the operation changed has been changed again since, in which case nothing is undone.",
+		Tags:        []string{"todos"},
+		Errors:      []int{404, 409},
+	}, h.Undo)
+}
+
+func (h *UndoHandler) Undo(ctx context.Context, input *struct{}) (*UndoOutput, error) {
+	info := auditInfo(ctx)
+	stopDB := timing.Track(ctx, timing.StageDB)
+	resp, err := h.repo.Undo(info, time.Now())
+	stopDB()
+	var conflict *db.UndoConflictError
+	switch {
+	case errors.Is(err, db.ErrNothingToUndo):
+		return nil, huma.Error404NotFound("nothing to undo")
+	case errors.As(err, &conflict):
+		return nil, huma.Error409Conflict(fmt.Sprintf("todo %d has changed since; undo it by hand", conflict.TodoID))
+	case err != nil:
+		h.logger.ErrorContext(ctx, "failed to undo", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+		return nil, huma.Error500InternalServerError("failed to undo")
+	}
+
+	h.logger.InfoContext(ctx, "undid operation",
+		slog.String("undone", resp.OperationID),
+		slog.Int("restored", len(resp.Restored)),
+		slog.Int("deleted", len(resp.Deleted)),
+		slog.String("operation_id", info.OperationID),
+	)
+	return &UndoOutput{Body: resp}, nil
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/quota"
+	"todo-service/internal/timing"
+)
+
+// usageClients is the most clients the usage report lists.
+const usageClients = 100
+
+// UsageHandler handles HTTP requests for storage and request usage.
+type UsageHandler struct {
+	repo    db.Repository
+	tracker *quota.Tracker
+	logger  *slog.Logger
+}
+
+// NewUsageHandler creates a new UsageHandler reporting the requests tracker
+// has counted.
+func NewUsageHandler(repo db.Repository, tracker *quota.Tracker, logger *slog.Logger) *UsageHandler {
+	return &UsageHandler{repo: repo, tracker: tracker, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type UsageOutput struct {
+	Body model.UsageResponse
+}
+
+// RegisterRoutes registers the usage route with the huma API.
+func (h *UsageHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-usage",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/admin/usage",
+		Summary:     "Get storage and request usage",
+		Description: "Report the row count of every table, how many rows were created in the last 7 and 30 days, the size of the database and its write-ahead log, and how many pages it has and how many of them are free, for planning when to archive or move to a larger database. Also reports the request quotas and the clients that have made the most requests this month, with their counts today and this month in UTC, for reviewing consumption.",
+		Tags:        []string{"admin"},
+		Errors:      []int{500},
+	}, h.GetUsage)
+}
+
+func (h *UsageHandler) GetUsage(ctx context.Context, input *struct{}) (*UsageOutput, error) {
+	now := time.Now()
+	stopDB := timing.Track(ctx, timing.StageDB)
+	usage, err := h.repo.Usage(now)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get usage", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve usage")
+	}
+
+	usage.Quotas = h.tracker.Limits()
+	usage.Clients = h.tracker.Usage(now, usageClients)
+	return &UsageOutput{Body: usage}, nil
+}
@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// ViewHandler handles HTTP requests for saved views.
+type ViewHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewViewHandler creates a new ViewHandler.
+func NewViewHandler(repo db.Repository, logger *slog.Logger) *ViewHandler {
+	return &ViewHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type ListViewsInput struct {
+	query.Params
+}
+
+type ListViewsOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of views"`
+	Body       model.ViewListResponse
+}
+
+type CreateViewInput struct {
+	Body model.CreateViewRequest
+}
+
+type ViewOutput struct {
+	Body model.View
+}
+
+type GetViewInput struct {
+	ID int64 `path:"id" doc:"View ID" example:"1"`
+}
+
+type UpdateViewInput struct {
+	ID   int64 `path:"id" doc:"View ID" example:"1"`
+	Body model.UpdateViewRequest
+}
+
+type DeleteViewInput struct {
+	ID int64 `path:"id" doc:"View ID" example:"1"`
+}
+
+type ListViewTodosInput struct {
+	query.Params
+	ID int64 `path:"id" doc:"View ID" example:"1"`
+}
+
+type ListViewTodosOutput struct {
+	Link       string `header:"Link" doc:"First, previous, next, and last page links when paginated"`
+	TotalCount int    `header:"X-Total-Count" doc:"Number of TODOs matching the view"`
+	Body       model.TodoListResponse
+}
+
+// RegisterRoutes registers all view routes with the huma API.
+func (h *ViewHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-views",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/views",
+		Summary:     "List all views",
+		Description: "Retrieve all saved views, sorted by name by default. Supports sorting and limit/offset pagination.",
+		Tags:        []string{"views"},
+		Errors:      []int{400},
+	}, h.ListViews)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-view",
+		Method:        http.MethodPost,
+		Path:          "/api/v1/views",
+		Summary:       "Create a new view",
+		Description:   "Save a named TODO query. View names must be unique. Date ranges may be relative, such as week_start to week_end, and are resolved whenever the view is listed.",
+		Tags:          []string{"views"},
+		Errors:        []int{400, 409},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateView)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-view",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/views/{id}",
+		Summary:     "Get a view by ID",
+		Description: "Retrieve a single saved view.",
+		Tags:        []string{"views"},
+		Errors:      []int{404},
+	}, h.GetView)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-view",
+		Method:      http.MethodPut,
+		Path:        "/api/v1/views/{id}",
+		Summary:     "Update a view",
+		Description: "Update an existing view. Only provided fields are changed; a filter replaces the view's filter as a whole.",
+		Tags:        []string{"views"},
+		Errors:      []int{400, 404, 409},
+	}, h.UpdateView)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-view",
+		Method:        http.MethodDelete,
+		Path:          "/api/v1/views/{id}",
+		Summary:       "Delete a view",
+		Description:   "Delete a saved view. The TODOs it lists are not affected.",
+		Tags:          []string{"views"},
+		Errors:        []int{404},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteView)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-view-todos",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/views/{id}/todos",
+		Summary:     "List the TODOs in a view",
+		Description: "Retrieve the TODOs matching a view's filter, sorted by the view's sort unless the sort parameter overrides it. Supports limit/offset pagination.",
+		Tags:        []string{"views"},
+		Errors:      []int{400, 404},
+	}, h.ListViewTodos)
+}
+
+func (h *ViewHandler) ListViews(ctx context.Context, input *ListViewsInput) (*ListViewsOutput, error) {
+	opts, err := input.Options(db.ViewSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountViews()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count views", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve views")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	views, err := h.repo.ListViews(opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list views", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve views")
+	}
+
+	return &ListViewsOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.ViewListResponse{Views: views, Count: len(views), Total: total},
+	}, nil
+}
+
+func (h *ViewHandler) CreateView(ctx context.Context, input *CreateViewInput) (*ViewOutput, error) {
+	if err := badRequest(validate.CreateView(input.Body)); err != nil {
+		return nil, err
+	}
+	if err := checkViewSort(input.Body.Filter.Sort); err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	view, err := h.repo.CreateView(input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrViewExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("view %q already exists", input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create view", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to create view")
+	}
+
+	return &ViewOutput{Body: view}, nil
+}
+
+func (h *ViewHandler) GetView(ctx context.Context, input *GetViewInput) (*ViewOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	view, err := h.repo.GetView(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("view with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get view", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve view")
+	}
+
+	return &ViewOutput{Body: view}, nil
+}
+
+func (h *ViewHandler) UpdateView(ctx context.Context, input *UpdateViewInput) (*ViewOutput, error) {
+	if err := badRequest(validate.UpdateView(input.Body)); err != nil {
+		return nil, err
+	}
+	if input.Body.Filter != nil {
+		if err := checkViewSort(input.Body.Filter.Sort); err != nil {
+			return nil, err
+		}
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	view, err := h.repo.UpdateView(input.ID, input.Body)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("view with id %d not found", input.ID))
+	}
+	if errors.Is(err, db.ErrViewExists) {
+		return nil, huma.Error409Conflict(fmt.Sprintf("view %q already exists", *input.Body.Name))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update view", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to update view")
+	}
+
+	return &ViewOutput{Body: view}, nil
+}
+
+func (h *ViewHandler) DeleteView(ctx context.Context, input *DeleteViewInput) (*struct{}, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	err := h.repo.DeleteView(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("view with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete view", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to delete view")
+	}
+
+	return nil, nil
+}
+
+func (h *ViewHandler) ListViewTodos(ctx context.Context, input *ListViewTodosInput) (*ListViewTodosOutput, error) {
+	stopDB := timing.Track(ctx, timing.StageDB)
+	view, err := h.repo.GetView(input.ID)
+	stopDB()
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("view with id %d not found", input.ID))
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get view", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve todos")
+	}
+
+	params := input.Params
+	if params.Sort == "" {
+		params.Sort = view.Filter.Sort
+	}
+	opts, err := params.Options(db.TodoSort)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := viewTodoFilter(view.Filter, time.Now())
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to resolve view filter", slog.String("error", err.Error()), slog.Int64("id", input.ID))
+		return nil, huma.Error500InternalServerError("failed to retrieve todos")
+	}
+
+	stopDB = timing.Track(ctx, timing.StageDB)
+	defer stopDB()
+
+	total, err := h.repo.CountTodos(filter)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve todos")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	todos, err := h.repo.ListTodos(filter, opts)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve todos")
+	}
+
+	return &ListViewTodosOutput{
+		Link:       input.Links(total),
+		TotalCount: total,
+		Body:       model.TodoListResponse{Todos: todos, Count: len(todos), Total: total},
+	}, nil
+}
+
+// checkViewSort rejects a saved sort the TODO list could not apply.
+func checkViewSort(sort string) error {
+	if _, err := (query.Params{Sort: sort}).Options(db.TodoSort); err != nil {
+		return huma.Error400BadRequest(err.Error(), &huma.ErrorDetail{
+			Location: "body.filter.sort",
+			Message:  err.Error(),
+			Value:    sort,
+		})
+	}
+	return nil
+}
+
+// viewTodoFilter translates a view's filter into a TODO list filter,
+// resolving relative dates against now. Completion bounds cover whole days.
+func viewTodoFilter(f model.ViewFilter, now time.Time) (db.TodoFilter, error) {
+	filter := db.TodoFilter{Archived: &f.Archived, ProjectID: f.ProjectID}
+	if len(f.Statuses) > 0 {
+		filter.Statuses = f.Statuses
+	}
+	if len(f.Categories) > 0 {
+		filter.Categories = f.Categories
+	}
+	if len(f.Priorities) > 0 {
+		filter.Priorities = f.Priorities
+	}
+	if f.Triage != "" {
+		filter.Triage = &f.Triage
+	}
+
+	var err error
+	if filter.DueFrom, filter.DueTo, err = viewDays(f.Due, now); err != nil {
+		return db.TodoFilter{}, err
+	}
+	if filter.ScheduledFrom, filter.ScheduledTo, err = viewDays(f.Scheduled, now); err != nil {
+		return db.TodoFilter{}, err
+	}
+
+	if r := f.Completed; r != nil {
+		if r.From != "" {
+			from, err := model.ResolveDate(r.From, now)
+			if err != nil {
+				return db.TodoFilter{}, err
+			}
+			filter.CompletedFrom = &from
+		}
+		if r.To != "" {
+			d, err := model.ResolveDate(r.To, now)
+			if err != nil {
+				return db.TodoFilter{}, err
+			}
+			to := d.AddDate(0, 0, 1).Add(-time.Second)
+			filter.CompletedTo = &to
+		}
+	}
+
+	return filter, nil
+}
+
+// viewDays resolves a range of days against now, formatting its bounds as
+// model.DateLayout. Unset bounds are nil.
+func viewDays(r *model.DateRange, now time.Time) (from, to *string, err error) {
+	if r == nil {
+		return nil, nil, nil
+	}
+	resolve := func(s string) (*string, error) {
+		if s == "" {
+			return nil, nil
+		}
+		d, err := model.ResolveDate(s, now)
+		if err != nil {
+			return nil, err
+		}
+		day := d.Format(model.DateLayout)
+		return &day, nil
+	}
+	if from, err = resolve(r.From); err != nil {
+		return nil, nil, err
+	}
+	if to, err = resolve(r.To); err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/timing"
+	"todo-service/internal/validate"
+)
+
+// WeekHandler handles HTTP requests for the weekly planner view.
+type WeekHandler struct {
+	repo   db.Repository
+	logger *slog.Logger
+}
+
+// NewWeekHandler creates a new WeekHandler.
+func NewWeekHandler(repo db.Repository, logger *slog.Logger) *WeekHandler {
+	return &WeekHandler{repo: repo, logger: logger}
+}
+
+// --- Input/Output types for huma ---
+
+type GetWeekInput struct {
+	Start string `query:"start" required:"false" format:"date" doc:"First day of the week (defaults to the current week's Monday, UTC)" example:"2026-02-16"`
+}
+
+type GetWeekOutput struct {
+	Body model.WeekResponse
+}
+
+// RegisterRoutes registers the planner routes with the huma API.
+func (h *WeekHandler) RegisterRoutes(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-week",
+		Method:      http.MethodGet,
+		Path:        "/api/v1/week",
+		Summary:     "Get a week of scheduled TODOs",
+		Description: "Retrieve the unarchived TODOs scheduled for each of the seven days starting at start, bucketed by day. Within a day, TODOs are ordered by ID.",
+		Tags:        []string{"planning"},
+		Errors:      []int{400},
+	}, h.GetWeek)
+}
+
+func (h *WeekHandler) GetWeek(ctx context.Context, input *GetWeekInput) (*GetWeekOutput, error) {
+	stopValidation := timing.Track(ctx, timing.StageValidation)
+	start, err := weekStart(input.Start, time.Now())
+	stopValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]model.WeekDay, 7)
+	index := make(map[string]int, len(days))
+	for i := range days {
+		date := start.AddDate(0, 0, i).Format(model.DateLayout)
+		days[i] = model.WeekDay{Date: date, Todos: []model.Todo{}}
+		index[date] = i
+	}
+
+	archived := false
+	filter := db.TodoFilter{
+		Archived:      &archived,
+		ScheduledFrom: &days[0].Date,
+		ScheduledTo:   &days[len(days)-1].Date,
+	}
+	opts, err := query.Params{}.Options(weekSort)
+	if err != nil {
+		return nil, err
+	}
+
+	stopDB := timing.Track(ctx, timing.StageDB)
+	total, err := h.repo.CountTodos(filter)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count scheduled todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve week")
+	}
+	if err := opts.Check(total); err != nil {
+		return nil, err
+	}
+
+	stopDB = timing.Track(ctx, timing.StageDB)
+	todos, err := h.repo.ListTodos(filter, opts)
+	stopDB()
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list scheduled todos", slog.String("error", err.Error()))
+		return nil, huma.Error500InternalServerError("failed to retrieve week")
+	}
+
+	for _, t := range todos {
+		i := index[*t.ScheduledFor]
+		days[i].Todos = append(days[i].Todos, t)
+	}
+
+	return &GetWeekOutput{Body: model.WeekResponse{
+		Start: days[0].Date,
+		End:   days[len(days)-1].Date,
+		Days:  days,
+	}}, nil
+}
+
+// weekSort orders a week's TODOs by day, then by ID.
+var weekSort = query.Spec{
+	Columns: db.TodoSort.Columns,
+	Default: []query.Sort{{Field: "scheduled_for"}},
+}
+
+// weekStart returns the first day of the requested week: start if given,
+// otherwise the Monday of the UTC week containing now.
+func weekStart(start string, now time.Time) (time.Time, error) {
+	if start != "" {
+		if err := invalidInput("query", validate.Date("start", start)); err != nil {
+			return time.Time{}, err
+		}
+		return time.Parse(model.DateLayout, start)
+	}
+
+	return model.WeekStart(now), nil
+}
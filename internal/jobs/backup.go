@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/backup"
+)
+
+// AutoBackup periodically backs up the database and deletes old backups.
+type AutoBackup struct {
+	store    *backup.Store
+	logger   *slog.Logger
+	interval time.Duration
+	keep     int
+}
+
+// NewAutoBackup creates an AutoBackup that backs up every interval and keeps
+// the newest keep backups, or all of them if keep is 0. Manual backups count
+// towards keep.
+func NewAutoBackup(store *backup.Store, logger *slog.Logger, interval time.Duration, keep int) *AutoBackup {
+	return &AutoBackup{store: store, logger: logger, interval: interval, keep: keep}
+}
+
+// Run backs up on every tick of the interval aligned to the clock, such as
+// on the hour for 1h or at midnight UTC for 24h, until ctx is canceled.
+func (a *AutoBackup) Run(ctx context.Context) {
+	a.logger.Info("auto-backup started",
+		slog.Duration("interval", a.interval),
+		slog.Int("keep", a.keep),
+	)
+
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(a.interval).Add(a.interval).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		a.backup()
+	}
+}
+
+func (a *AutoBackup) backup() {
+	b, err := a.store.Create()
+	if err != nil {
+		a.logger.Error("auto-backup failed", slog.String("error", err.Error()))
+		return
+	}
+	a.logger.Info("backed up database", slog.String("name", b.Name), slog.Int64("size_bytes", b.SizeBytes))
+
+	if a.keep == 0 {
+		return
+	}
+	deleted, err := a.store.Prune(a.keep)
+	if err != nil {
+		a.logger.Error("failed to delete old backups", slog.String("error", err.Error()))
+	}
+	if len(deleted) > 0 {
+		a.logger.Info("deleted old backups", slog.Any("names", deleted))
+	}
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+)
+
+// WALCheckpointer periodically truncates the database's write-ahead log
+// once it has grown past a size, so that sustained writes do not leave it
+// growing without bound.
+type WALCheckpointer struct {
+	repo     db.Repository
+	logger   *slog.Logger
+	interval time.Duration
+	maxBytes int64
+}
+
+// NewWALCheckpointer creates a WALCheckpointer that checks the log every
+// interval and truncates it once it holds at least maxBytes.
+func NewWALCheckpointer(repo db.Repository, logger *slog.Logger, interval time.Duration, maxBytes int64) *WALCheckpointer {
+	return &WALCheckpointer{repo: repo, logger: logger, interval: interval, maxBytes: maxBytes}
+}
+
+// Run checks the log immediately and then on every tick until ctx is
+// canceled.
+func (c *WALCheckpointer) Run(ctx context.Context) {
+	c.logger.Info("wal checkpoints started",
+		slog.Duration("interval", c.interval),
+		slog.Int64("max_bytes", c.maxBytes),
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.checkpoint()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *WALCheckpointer) checkpoint() {
+	size, err := c.repo.WALSize()
+	if err != nil {
+		c.logger.Error("failed to check wal size", slog.String("error", err.Error()))
+		return
+	}
+	if size == 0 || size < c.maxBytes {
+		return
+	}
+
+	resp, err := c.repo.Checkpoint()
+	if err != nil {
+		c.logger.Error("wal checkpoint failed", slog.String("error", err.Error()))
+		return
+	}
+	attrs := []any{
+		slog.Int64("wal_bytes_before", resp.WALBytesBefore),
+		slog.Int64("wal_bytes_after", resp.WALBytesAfter),
+	}
+	if resp.Busy {
+		c.logger.Warn("wal checkpoint could not complete; another connection is using the database", attrs...)
+		return
+	}
+	c.logger.Info("truncated wal", attrs...)
+}
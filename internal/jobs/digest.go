@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/digest"
+)
+
+// digestRetryDelay is how long a digest that failed to send waits before it
+// is tried again, unless the next scheduled one comes first.
+const digestRetryDelay = 15 * time.Minute
+
+// Digest emails the digest on its schedule.
+type Digest struct {
+	repo   db.Repository
+	mailer *digest.Mailer
+	logger *slog.Logger
+}
+
+// NewDigest creates a Digest that sends mailer's digest on the schedule in
+// its configuration.
+func NewDigest(repo db.Repository, mailer *digest.Mailer, logger *slog.Logger) *Digest {
+	return &Digest{repo: repo, mailer: mailer, logger: logger}
+}
+
+// Run sends the digest whenever it is due until ctx is canceled. A digest
+// that came due while the service was down is sent when it starts, once.
+func (j *Digest) Run(ctx context.Context) {
+	schedule := j.mailer.Config().Schedule
+	next := schedule.Next(time.Now())
+	last, err := j.repo.LastDigestSentAt()
+	if err != nil {
+		j.logger.Error("failed to read last digest", slog.String("error", err.Error()))
+	} else if last != nil {
+		next = schedule.Next(*last)
+	}
+	j.logger.Info("email digest started", slog.String("schedule", string(schedule)), slog.Time("next_run_at", next))
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		next = schedule.Next(now)
+		if err := j.send(ctx, now); err != nil {
+			if retry := now.Add(digestRetryDelay); retry.Before(next) {
+				next = retry
+			}
+			j.logger.Error("failed to send digest", slog.String("error", err.Error()), slog.Time("retry_at", next))
+		}
+		timer.Reset(time.Until(next))
+	}
+}
+
+func (j *Digest) send(ctx context.Context, now time.Time) error {
+	d, err := j.mailer.Build(now)
+	if err != nil {
+		return err
+	}
+	if err := j.mailer.Send(ctx, d); err != nil {
+		return err
+	}
+	j.logger.Info("sent digest",
+		slog.Int("overdue", len(d.Overdue)),
+		slog.Int("due_soon", len(d.DueSoon)),
+		slog.Int("completed", len(d.Completed)),
+	)
+	return nil
+}
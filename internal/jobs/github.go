@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/github"
+)
+
+// GitHubSync periodically syncs TODOs with GitHub issues.
+type GitHubSync struct {
+	syncer   *github.Syncer
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewGitHubSync creates a GitHubSync that syncs every interval.
+func NewGitHubSync(syncer *github.Syncer, logger *slog.Logger, interval time.Duration) *GitHubSync {
+	return &GitHubSync{syncer: syncer, logger: logger, interval: interval}
+}
+
+// Run syncs immediately and then on every tick until ctx is canceled.
+func (g *GitHubSync) Run(ctx context.Context) {
+	g.logger.Info("github sync started", slog.Duration("interval", g.interval))
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, repo := range g.syncer.Sync(ctx).Repos {
+			attrs := []any{
+				slog.String("repo", repo.Repo),
+				slog.Int("created", repo.Created),
+				slog.Int("updated", repo.Updated),
+				slog.Int("pushed", repo.Pushed),
+				slog.Int("conflicts", len(repo.Conflicts)),
+			}
+			if len(repo.Errors) > 0 {
+				g.logger.Error("failed to sync github issues", append(attrs, slog.Any("errors", repo.Errors))...)
+			} else if repo.Created+repo.Updated+repo.Pushed > 0 {
+				g.logger.Info("synced github issues", attrs...)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
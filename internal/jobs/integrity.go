@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/notify"
+)
+
+// IntegrityChecker periodically checks the database for corruption and,
+// when it finds any, alerts the notification routes notified of corruption
+// events.
+type IntegrityChecker struct {
+	repo     db.Repository
+	notifier *notify.Notifier
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewIntegrityChecker creates an IntegrityChecker that checks every
+// interval.
+func NewIntegrityChecker(repo db.Repository, notifier *notify.Notifier, logger *slog.Logger, interval time.Duration) *IntegrityChecker {
+	return &IntegrityChecker{repo: repo, notifier: notifier, logger: logger, interval: interval}
+}
+
+// Run checks on every tick until ctx is canceled. The first check waits a
+// full interval, so that restarts do not each scan the whole database.
+func (c *IntegrityChecker) Run(ctx context.Context) {
+	c.logger.Info("integrity checks started", slog.Duration("interval", c.interval))
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.check(ctx)
+	}
+}
+
+func (c *IntegrityChecker) check(ctx context.Context) {
+	report, err := c.repo.CheckIntegrity()
+	if err != nil {
+		c.logger.Error("failed to check database integrity", slog.String("error", err.Error()))
+		return
+	}
+	if report.OK {
+		c.logger.Info("database integrity check passed", slog.Float64("duration_ms", report.DurationMs))
+		return
+	}
+
+	c.logger.Error("database integrity check failed",
+		slog.Int("problems", len(report.Problems)),
+		slog.String("first", report.Problems[0]),
+	)
+	msg := model.NotificationMessage{Event: model.EventCorruption, Problems: report.Problems}
+	if err := c.notifier.Broadcast(ctx, msg); err != nil {
+		c.logger.Error("failed to alert of database corruption", slog.String("error", err.Error()))
+	}
+}
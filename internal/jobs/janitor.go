@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+// AutoArchiveActor attributes automatic archiving in the audit log.
+const AutoArchiveActor = "system:auto-archive"
+
+// Retention says how long TODOs stay as they are before the janitor moves
+// them on. A zero duration disables that policy.
+type Retention struct {
+	ArchiveAfter time.Duration // archive TODOs that have been done this long
+	PurgeAfter   time.Duration // purge TODOs that have been in the trash this long
+}
+
+// Janitor periodically enforces retention policies: it archives TODOs that
+// have been done for too long and purges TODOs that have been in the trash
+// for too long. Runs are spread out by up to a tenth of the interval either
+// way, so that several instances sharing a database do not all run at once.
+// In dry-run mode it only logs what it would do.
+type Janitor struct {
+	repo      db.Repository
+	logger    *slog.Logger
+	retention Retention
+	interval  time.Duration
+	dryRun    bool
+
+	runs     atomic.Int64
+	errors   atomic.Int64
+	archived atomic.Int64
+	purged   atomic.Int64
+	lastRun  atomic.Pointer[time.Time]
+}
+
+// NewJanitor creates a Janitor that enforces retention about every interval.
+func NewJanitor(repo db.Repository, logger *slog.Logger, retention Retention, interval time.Duration, dryRun bool) *Janitor {
+	return &Janitor{repo: repo, logger: logger, retention: retention, interval: interval, dryRun: dryRun}
+}
+
+// Run enforces retention immediately and then about every interval until
+// ctx is canceled.
+func (j *Janitor) Run(ctx context.Context) {
+	j.logger.Info("janitor started",
+		slog.Duration("archive_after", j.retention.ArchiveAfter),
+		slog.Duration("purge_after", j.retention.PurgeAfter),
+		slog.Duration("interval", j.interval),
+		slog.Bool("dry_run", j.dryRun),
+	)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		j.run(time.Now())
+		timer.Reset(j.next())
+	}
+}
+
+// next returns how long to wait before the next run: the interval, moved by
+// a random amount of up to a tenth of it either way.
+func (j *Janitor) next() time.Duration {
+	jitter := int64(j.interval / 10)
+	if jitter <= 0 {
+		return j.interval
+	}
+	return j.interval + time.Duration(rand.Int64N(2*jitter+1)-jitter)
+}
+
+// Stats reports what the janitor has done since it was created.
+func (j *Janitor) Stats() model.JanitorStats {
+	return model.JanitorStats{
+		Runs:     j.runs.Load(),
+		Errors:   j.errors.Load(),
+		Archived: j.archived.Load(),
+		Purged:   j.purged.Load(),
+		DryRun:   j.dryRun,
+		LastRun:  j.lastRun.Load(),
+	}
+}
+
+func (j *Janitor) run(now time.Time) {
+	if j.retention.ArchiveAfter > 0 {
+		j.archive(now.Add(-j.retention.ArchiveAfter))
+	}
+	if j.retention.PurgeAfter > 0 {
+		j.purge(now.Add(-j.retention.PurgeAfter))
+	}
+	j.runs.Add(1)
+	j.lastRun.Store(&now)
+}
+
+func (j *Janitor) archive(cutoff time.Time) {
+	if j.dryRun {
+		n, err := j.repo.CountArchivable(cutoff)
+		if err != nil {
+			j.errors.Add(1)
+			j.logger.Error("failed to count archivable todos", slog.String("error", err.Error()))
+			return
+		}
+		j.archived.Add(int64(n))
+		if n > 0 {
+			j.logger.Info("would auto-archive done todos", slog.Int("count", n), slog.Time("cutoff", cutoff))
+		}
+		return
+	}
+
+	info := db.AuditInfo{Actor: AutoArchiveActor, OperationID: db.NewOperationID()}
+	n, err := j.repo.ArchiveDone(cutoff, info)
+	if err != nil {
+		j.errors.Add(1)
+		j.logger.Error("auto-archive failed", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+		return
+	}
+	j.archived.Add(int64(n))
+	if n > 0 {
+		j.logger.Info("auto-archived done todos",
+			slog.Int("count", n),
+			slog.Time("cutoff", cutoff),
+			slog.String("operation_id", info.OperationID),
+		)
+	}
+}
+
+func (j *Janitor) purge(cutoff time.Time) {
+	if j.dryRun {
+		n, err := j.repo.CountTrashBefore(cutoff)
+		if err != nil {
+			j.errors.Add(1)
+			j.logger.Error("failed to count trash", slog.String("error", err.Error()))
+			return
+		}
+		j.purged.Add(int64(n))
+		if n > 0 {
+			j.logger.Info("would purge trashed todos", slog.Int("count", n), slog.Time("cutoff", cutoff))
+		}
+		return
+	}
+
+	n, err := j.repo.PurgeTrash(cutoff)
+	if err != nil {
+		j.errors.Add(1)
+		j.logger.Error("trash purge failed", slog.String("error", err.Error()))
+		return
+	}
+	j.purged.Add(int64(n))
+	if n > 0 {
+		j.logger.Info("purged trashed todos", slog.Int("count", n), slog.Time("cutoff", cutoff))
+	}
+}
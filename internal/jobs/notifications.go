@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/notify"
+	"todo-service/internal/query"
+)
+
+// notificationRetryDelay is how long a route whose webhook failed waits
+// before it is tried again. Its events wait in the audit log meanwhile.
+const notificationRetryDelay = time.Minute
+
+// Notifications posts TODO events to notification routes.
+type Notifications struct {
+	repo     db.Repository
+	notifier *notify.Notifier
+	logger   *slog.Logger
+	interval time.Duration
+	retryAt  map[int64]time.Time
+}
+
+// NewNotifications creates a Notifications that checks for events every
+// interval.
+func NewNotifications(repo db.Repository, notifier *notify.Notifier, logger *slog.Logger, interval time.Duration) *Notifications {
+	return &Notifications{repo: repo, notifier: notifier, logger: logger, interval: interval, retryAt: map[int64]time.Time{}}
+}
+
+// Run notifies routes immediately and then on every tick until ctx is
+// canceled. Events that happened while the service was down are posted
+// when it starts.
+func (n *Notifications) Run(ctx context.Context) {
+	n.logger.Info("notifications started", slog.Duration("interval", n.interval))
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		n.notify(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifications) notify(ctx context.Context) {
+	opts, _ := query.Params{Sort: "id"}.Options(db.NotificationRouteSort)
+	routes, err := n.repo.ListNotificationRoutes(opts)
+	if err != nil {
+		n.logger.Error("failed to list notification routes", slog.String("error", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	for _, route := range routes {
+		if now.Before(n.retryAt[route.ID]) {
+			continue
+		}
+		for more := true; more && ctx.Err() == nil; {
+			more = n.notifyRoute(ctx, route, now)
+		}
+	}
+}
+
+// notifyRoute posts the messages route is due for, up to a batch, and
+// reports whether more are waiting.
+func (n *Notifications) notifyRoute(ctx context.Context, route model.NotificationRoute, now time.Time) bool {
+	cursor, err := n.repo.NotificationCursor(route.ID)
+	if err != nil {
+		n.logger.Error("failed to read notification cursor", slog.Int64("route_id", route.ID), slog.String("error", err.Error()))
+		return false
+	}
+	lines, next, more, err := n.notifier.Pending(route, cursor, now)
+	if err != nil {
+		n.logger.Error("failed to find notifications", slog.Int64("route_id", route.ID), slog.String("error", err.Error()))
+		return false
+	}
+	if next == cursor {
+		return false
+	}
+
+	var sentAt *time.Time
+	var postErr error
+	if len(lines) > 0 {
+		if postErr = n.notifier.Post(ctx, route, lines); postErr == nil {
+			sentAt = &now
+			delete(n.retryAt, route.ID)
+			n.logger.Info("posted notifications", slog.Int64("route_id", route.ID), slog.String("channel", string(route.Channel)), slog.Int("events", len(lines)))
+		} else {
+			n.retryAt[route.ID] = now.Add(notificationRetryDelay)
+			n.logger.Error("failed to post notifications",
+				slog.Int64("route_id", route.ID),
+				slog.String("error", postErr.Error()),
+				slog.Time("retry_at", n.retryAt[route.ID]),
+			)
+		}
+	}
+	if err := n.repo.RecordNotification(route.ID, next, sentAt, postErr); err != nil {
+		n.logger.Error("failed to record notification", slog.Int64("route_id", route.ID), slog.String("error", err.Error()))
+		return false
+	}
+	return more && postErr == nil
+}
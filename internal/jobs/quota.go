@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/quota"
+)
+
+// How long request counts are kept for reviewing past usage.
+const (
+	requestCountDays   = 31
+	requestCountMonths = 12
+)
+
+// QuotaFlusher periodically saves the request counts a quota tracker has
+// made, and once a day deletes old ones.
+type QuotaFlusher struct {
+	tracker  *quota.Tracker
+	logger   *slog.Logger
+	interval time.Duration
+	pruned   time.Time
+}
+
+// NewQuotaFlusher creates a QuotaFlusher that saves counts every interval.
+func NewQuotaFlusher(tracker *quota.Tracker, logger *slog.Logger, interval time.Duration) *QuotaFlusher {
+	return &QuotaFlusher{tracker: tracker, logger: logger, interval: interval}
+}
+
+// Run saves counts on every tick until ctx is canceled. Counts made after
+// the last tick are left for the caller to flush once requests have
+// stopped.
+func (f *QuotaFlusher) Run(ctx context.Context) {
+	f.logger.Info("request counting started", slog.Duration("flush_interval", f.interval))
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.prune()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := f.tracker.Flush(); err != nil {
+			f.logger.Error("failed to save request counts", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (f *QuotaFlusher) prune() {
+	now := time.Now()
+	if now.Sub(f.pruned) < 24*time.Hour {
+		return
+	}
+	f.pruned = now
+
+	n, err := f.tracker.Prune(now, requestCountDays, requestCountMonths)
+	if err != nil {
+		f.logger.Error("failed to prune request counts", slog.String("error", err.Error()))
+		return
+	}
+	if n > 0 {
+		f.logger.Debug("pruned request counts", slog.Int64("counts", n))
+	}
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/delivery"
+)
+
+// reportRetryDelay is how long a failed report delivery waits before it is
+// tried again, unless its next scheduled delivery comes first.
+const reportRetryDelay = 15 * time.Minute
+
+// ReportSender delivers report subscriptions when they are due.
+type ReportSender struct {
+	repo      db.Repository
+	deliverer *delivery.Deliverer
+	logger    *slog.Logger
+	interval  time.Duration
+}
+
+// NewReportSender creates a ReportSender that checks for due subscriptions
+// every interval.
+func NewReportSender(repo db.Repository, deliverer *delivery.Deliverer, logger *slog.Logger, interval time.Duration) *ReportSender {
+	return &ReportSender{repo: repo, deliverer: deliverer, logger: logger, interval: interval}
+}
+
+// Run delivers due subscriptions immediately and then on every tick until
+// ctx is canceled. Subscriptions that came due while the service was down
+// are delivered once, not once per missed run.
+func (s *ReportSender) Run(ctx context.Context) {
+	s.logger.Info("report subscriptions started", slog.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.send(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReportSender) send(ctx context.Context) {
+	now := time.Now()
+	subs, err := s.repo.DueReportSubscriptions(now)
+	if err != nil {
+		s.logger.Error("failed to list due report subscriptions", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+		next := sub.Schedule.Next(now)
+		var sentAt *time.Time
+		err := s.deliverer.Deliver(ctx, sub, now)
+		if err == nil {
+			sentAt = &now
+			s.logger.Info("delivered report",
+				slog.Int64("subscription_id", sub.ID),
+				slog.String("report", string(sub.Report)),
+				slog.String("format", sub.Format),
+			)
+		} else {
+			if retry := now.Add(reportRetryDelay); retry.Before(next) {
+				next = retry
+			}
+			s.logger.Error("failed to deliver report",
+				slog.Int64("subscription_id", sub.ID),
+				slog.String("error", err.Error()),
+				slog.Time("retry_at", next),
+			)
+		}
+		if err := s.repo.RecordReportDelivery(sub.ID, sentAt, err, next); err != nil {
+			s.logger.Error("failed to record report delivery", slog.Int64("subscription_id", sub.ID), slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+)
+
+// SnoozeActor attributes snoozed TODOs waking up in the audit log.
+const SnoozeActor = "system:snooze"
+
+// SnoozeWaker periodically wakes snoozed TODOs whose snooze has run out.
+// Each wake-up is recorded in the audit log, from which notification routes
+// hear of it as a woke event.
+type SnoozeWaker struct {
+	repo     db.Repository
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewSnoozeWaker creates a SnoozeWaker that checks every interval.
+func NewSnoozeWaker(repo db.Repository, logger *slog.Logger, interval time.Duration) *SnoozeWaker {
+	return &SnoozeWaker{repo: repo, logger: logger, interval: interval}
+}
+
+// Run wakes TODOs immediately and then on every tick until ctx is canceled.
+// TODOs whose snooze ran out while the service was down wake when it starts.
+func (w *SnoozeWaker) Run(ctx context.Context) {
+	w.logger.Info("snooze waker started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.wake()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SnoozeWaker) wake() {
+	info := db.AuditInfo{Actor: SnoozeActor, OperationID: db.NewOperationID()}
+	todos, err := w.repo.WakeSnoozed(time.Now(), info)
+	if err != nil {
+		w.logger.Error("failed to wake snoozed todos", slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+		return
+	}
+	for _, t := range todos {
+		w.logger.Info("snoozed todo woke up", slog.Int64("id", t.ID), slog.String("operation_id", info.OperationID))
+	}
+}
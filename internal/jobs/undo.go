@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todo-service/internal/db"
+)
+
+// UndoPruner periodically forgets operations that can no longer be undone,
+// because they are older than the undo window or deeper than the undo depth,
+// keeping the undo log short.
+type UndoPruner struct {
+	repo     db.Repository
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewUndoPruner creates an UndoPruner that prunes every interval.
+func NewUndoPruner(repo db.Repository, logger *slog.Logger, interval time.Duration) *UndoPruner {
+	return &UndoPruner{repo: repo, logger: logger, interval: interval}
+}
+
+// Run prunes the undo log immediately and then on every tick until ctx is
+// canceled.
+func (p *UndoPruner) Run(ctx context.Context) {
+	p.logger.Info("undo pruner started", slog.Duration("interval", p.interval))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.prune()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *UndoPruner) prune() {
+	n, err := p.repo.PruneUndo(time.Now())
+	if err != nil {
+		p.logger.Error("failed to prune undo log", slog.String("error", err.Error()))
+		return
+	}
+	if n > 0 {
+		p.logger.Debug("pruned undo log", slog.Int64("entries", n))
+	}
+}
@@ -0,0 +1,135 @@
+// Package listen opens the server's listeners from address strings, which
+// may name a TCP address, a Unix domain socket, or a socket passed in by
+// systemd socket activation.
+package listen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Listen opens a listener for addr, which is one of:
+//
+//   - host:port, a TCP address, such as :8080 or 127.0.0.1:8080
+//   - unix:PATH, a Unix domain socket created with permissions mode; a
+//     stale socket file left at PATH is replaced
+//   - systemd or systemd:NAME, the first socket systemd passed in, or the
+//     one whose FileDescriptorName is NAME
+//
+// Each systemd socket can be used only once.
+func Listen(addr string, mode fs.FileMode) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, "unix:"):
+		return listenUnix(strings.TrimPrefix(addr, "unix:"), mode)
+	case addr == "systemd" || strings.HasPrefix(addr, "systemd:"):
+		return listenSystemd(strings.TrimPrefix(strings.TrimPrefix(addr, "systemd"), ":"))
+	default:
+		return net.Listen("tcp", addr)
+	}
+}
+
+// IsTCP reports whether addr is a TCP address rather than a socket.
+func IsTCP(addr string) bool {
+	_, _, err := net.SplitHostPort(addr)
+	return err == nil && !strings.HasPrefix(addr, "unix:") && !strings.HasPrefix(addr, "systemd")
+}
+
+func listenUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("unix socket path is empty")
+	}
+	// A socket file outlives the process that made it; remove it unless
+	// another process is still listening on it.
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&fs.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale unix socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("set unix socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// systemdFirstFD is the first file descriptor systemd passes, after stdin,
+// stdout, and stderr.
+const systemdFirstFD = 3
+
+var (
+	systemdOnce  sync.Once
+	systemdMu    sync.Mutex
+	systemdFiles []*os.File
+	systemdNames []string
+)
+
+func listenSystemd(name string) (net.Listener, error) {
+	systemdOnce.Do(inheritSystemd)
+
+	systemdMu.Lock()
+	defer systemdMu.Unlock()
+	if len(systemdFiles) == 0 {
+		return nil, errors.New("no sockets were passed in by systemd (LISTEN_FDS is not set)")
+	}
+	for i, f := range systemdFiles {
+		if f == nil || (name != "" && systemdNames[i] != name) {
+			continue
+		}
+		systemdFiles[i] = nil
+		ln, err := net.FileListener(f)
+		f.Close() // FileListener holds its own copy
+		if err != nil {
+			return nil, fmt.Errorf("systemd socket %d: %w", systemdFirstFD+i, err)
+		}
+		return ln, nil
+	}
+	if name != "" {
+		return nil, fmt.Errorf("no unused systemd socket named %q", name)
+	}
+	return nil, errors.New("every systemd socket is already in use")
+}
+
+// inheritSystemd takes the sockets described by the LISTEN_PID, LISTEN_FDS,
+// and LISTEN_FDNAMES environment variables, if they were meant for this
+// process, and clears the variables so child processes do not take them.
+func inheritSystemd() {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for i := range n {
+		fd := systemdFirstFD + i
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		systemdFiles = append(systemdFiles, os.NewFile(uintptr(fd), name))
+		systemdNames = append(systemdNames, name)
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// File log formats.
+const (
+	// FormatJSON logs records as JSON with slog's and the service's own
+	// field names.
+	FormatJSON = "json"
+	// FormatECS logs records as JSON with Elastic Common Schema field names
+	// where there is one, so ECS pipelines parse them as they are.
+	FormatECS = "ecs"
+)
+
+// ECSVersion is the version of the Elastic Common Schema that FormatECS
+// follows, recorded in every record's ecs.version field.
+const ECSVersion = "8.11.0"
+
+// ecsFields maps the service's attribute names to their ECS equivalents.
+// Attributes without one keep their names.
+var ecsFields = map[string]string{
+	slog.TimeKey:    "@timestamp",
+	slog.LevelKey:   "log.level",
+	slog.MessageKey: "message",
+	"request_id":    "http.request.id",
+	"path":          "url.path",
+	"status":        "http.response.status_code",
+	"bytes":         "http.response.body.bytes",
+	"remote_addr":   "client.address",
+	"user_agent":    "user_agent.original",
+	"trace_id":      "trace.id",
+	"actor":         "user.name",
+	"error":         "error.message",
+	"stack":         "error.stack_trace",
+}
+
+// ecsReplaceAttr renames top-level attributes to their ECS fields and
+// converts values where ECS expects another unit or form.
+func ecsReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		return slog.String("log.level", strings.ToLower(a.Value.String()))
+	case "method":
+		// gRPC calls log their full method name, such as
+		// /todo.v1.TodoService/GetTodo, which is not an HTTP method.
+		if !strings.Contains(a.Value.String(), "/") {
+			a.Key = "http.request.method"
+		}
+		return a
+	case "duration_ms":
+		// ECS durations are in nanoseconds.
+		if a.Value.Kind() == slog.KindFloat64 {
+			return slog.Int64("event.duration", int64(a.Value.Float64()*1e6))
+		}
+		return a
+	}
+	if key, ok := ecsFields[a.Key]; ok {
+		a.Key = key
+	}
+	return a
+}
@@ -20,6 +20,10 @@ type Config struct {
 	MaxAgeDays int
 	DevMode    bool
 	Level      slog.Level
+	// Format is FormatJSON or FormatECS, and applies to the file and Ship.
+	Format string
+	// Ship, if set, receives every record the file does.
+	Ship *Shipper
 }
 
 // DefaultConfig returns sensible defaults.
@@ -32,6 +36,7 @@ func DefaultConfig() Config {
 		MaxAgeDays: 30,
 		DevMode:    true,
 		Level:      slog.LevelInfo,
+		Format:     FormatJSON,
 	}
 }
 
@@ -77,7 +82,8 @@ func (m *MultiHandler) WithGroup(name string) slog.Handler {
 }
 
 // New creates a dual-output logger: JSON rolling file + pretty/text console.
-// Returns the logger and a Closer for the file writer.
+// Records logged with a context get the attributes stored in it by
+// WithAttrs. Returns the logger and a Closer for the file writer.
 func New(cfg Config) (*slog.Logger, io.Closer) {
 	os.MkdirAll(cfg.LogDir, 0o755)
 
@@ -93,7 +99,7 @@ func New(cfg Config) (*slog.Logger, io.Closer) {
 	}
 
 	jsonOpts := &slog.HandlerOptions{Level: cfg.Level}
-	fileHandler := slog.NewJSONHandler(lj, jsonOpts)
+	fileHandler := jsonHandler(lj, cfg)
 
 	// Console handler
 	var consoleHandler slog.Handler
@@ -107,5 +113,32 @@ func New(cfg Config) (*slog.Logger, io.Closer) {
 	}
 
 	multi := &MultiHandler{handlers: []slog.Handler{fileHandler, consoleHandler}}
-	return slog.New(multi), lj
+	var closer io.Closer = lj
+	if cfg.Ship != nil {
+		multi.handlers = append(multi.handlers, jsonHandler(cfg.Ship, cfg))
+		closer = closers{cfg.Ship, lj}
+	}
+	return slog.New(ContextHandler{multi}), closer
+}
+
+// jsonHandler writes records to w as JSON in cfg.Format.
+func jsonHandler(w io.Writer, cfg Config) slog.Handler {
+	if cfg.Format == FormatECS {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level, ReplaceAttr: ecsReplaceAttr}).
+			WithAttrs([]slog.Attr{slog.String("ecs.version", ECSVersion)})
+	}
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level})
+}
+
+// closers closes each of its elements in order, returning the first error.
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var first error
+	for _, cl := range c {
+		if err := cl.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
 }
@@ -0,0 +1,394 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Log shipping protocols.
+const (
+	// ProtocolLoki pushes JSON lines to a Grafana Loki push endpoint, such
+	// as http://loki:3100/loki/api/v1/push.
+	ProtocolLoki = "loki"
+	// ProtocolOTLP exports records to an OpenTelemetry collector's OTLP/HTTP
+	// logs endpoint, such as http://collector:4318/v1/logs, in JSON.
+	ProtocolOTLP = "otlp"
+	// ProtocolSyslog sends RFC 5424 syslog messages, each carrying the JSON
+	// record, to a syslog server over UDP or TCP, such as udp://syslog:514.
+	// Over TCP, messages end with a newline.
+	ProtocolSyslog = "syslog"
+	// ProtocolTCP writes JSON records, one per line, to a TCP listener, such
+	// as tcp://fluentd:5170.
+	ProtocolTCP = "tcp"
+)
+
+// ServiceName identifies the service's records to log backends.
+const ServiceName = "todo-service"
+
+// Shipping limits. Records are sent in batches of up to shipBatchSize, at
+// least every shipFlushInterval; a batch that cannot be sent after
+// shipRetries attempts is dropped.
+const (
+	shipBatchSize     = 500
+	shipFlushInterval = time.Second
+	shipRetries       = 5
+	shipTimeout       = 10 * time.Second
+)
+
+// ShipConfig configures a Shipper.
+type ShipConfig struct {
+	URL      string
+	Protocol string // ProtocolLoki, ProtocolOTLP, ProtocolSyslog, or ProtocolTCP
+	// Buffer is the number of records held while they wait to be sent.
+	// Records logged while it is full are dropped.
+	Buffer int
+}
+
+// Shipper sends log records to a network log backend in the background.
+// Logging never waits for the network: records are buffered, sent in
+// batches with retries, and dropped, and counted, if the backend cannot
+// keep up. Add it to a logger with Config.Ship.
+type Shipper struct {
+	url       string
+	target    string // url without credentials, for errors
+	encode    func([]shipRecord) ([]byte, error)
+	deliver   func([]byte) (retry bool, err error)
+	client    *http.Client
+	transport *stream // nil for HTTP protocols
+
+	records chan []byte
+	stop    chan struct{}
+	done    chan struct{}
+	closing sync.Once
+	closed  atomic.Bool
+
+	sent, dropped, failures atomic.Int64
+	lastError               atomic.Value // string
+}
+
+// NewShipper starts a Shipper. HTTP URLs may carry basic auth credentials.
+func NewShipper(cfg ShipConfig) (*Shipper, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("log shipping URL %q: want a URL with a host", cfg.URL)
+	}
+	s := &Shipper{
+		url:     cfg.URL,
+		target:  u.Redacted(),
+		records: make(chan []byte, max(cfg.Buffer, 1)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	switch cfg.Protocol {
+	case ProtocolLoki, ProtocolOTLP:
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("log shipping URL %q: want an http or https URL for %s", cfg.URL, cfg.Protocol)
+		}
+		s.encode = encodeLoki
+		if cfg.Protocol == ProtocolOTLP {
+			s.encode = encodeOTLP
+		}
+		s.client = &http.Client{Timeout: shipTimeout}
+		s.deliver = s.post
+	case ProtocolSyslog:
+		if u.Scheme != "udp" && u.Scheme != "tcp" {
+			return nil, fmt.Errorf("log shipping URL %q: want a udp or tcp URL for syslog", cfg.URL)
+		}
+		s.encode = newSyslogEncoder()
+		s.transport = &stream{network: u.Scheme, addr: u.Host}
+		s.deliver = s.transport.write
+	case ProtocolTCP:
+		if u.Scheme != "tcp" {
+			return nil, fmt.Errorf("log shipping URL %q: want a tcp URL", cfg.URL)
+		}
+		s.encode = encodeLines
+		s.transport = &stream{network: u.Scheme, addr: u.Host}
+		s.deliver = s.transport.write
+	default:
+		return nil, fmt.Errorf("unknown log shipping protocol %q", cfg.Protocol)
+	}
+	s.lastError.Store("")
+
+	go s.run()
+	return s, nil
+}
+
+// Write queues one JSON log record, as written by slog.JSONHandler, to be
+// sent. It never blocks.
+func (s *Shipper) Write(p []byte) (int, error) {
+	if s.closed.Load() {
+		s.dropped.Add(1)
+		return len(p), nil
+	}
+	select {
+	case s.records <- bytes.Clone(p):
+	default:
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Stats reports how many records have been sent and dropped.
+func (s *Shipper) Stats() model.LogShipStats {
+	return model.LogShipStats{
+		Sent:      s.sent.Load(),
+		Dropped:   s.dropped.Load(),
+		Buffered:  len(s.records),
+		Failures:  s.failures.Load(),
+		LastError: s.lastError.Load().(string),
+	}
+}
+
+// Close sends the buffered records, trying each batch once, and stops the
+// Shipper. Records written afterwards are dropped.
+func (s *Shipper) Close() error {
+	s.closing.Do(func() {
+		s.closed.Store(true)
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
+
+func (s *Shipper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(shipFlushInterval)
+	defer ticker.Stop()
+
+	var batch [][]byte
+	for {
+		select {
+		case line := <-s.records:
+			batch = append(batch, line)
+			if len(batch) < shipBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-s.stop:
+			s.drain(batch)
+			if s.transport != nil {
+				s.transport.close()
+			}
+			return
+		}
+		s.send(batch, shipRetries)
+		batch = nil
+	}
+}
+
+// drain sends batch and the buffered records, trying each batch once.
+func (s *Shipper) drain(batch [][]byte) {
+	for {
+		select {
+		case line := <-s.records:
+			batch = append(batch, line)
+			if len(batch) == shipBatchSize {
+				s.send(batch, 1)
+				batch = nil
+			}
+		default:
+			s.send(batch, 1)
+			return
+		}
+	}
+}
+
+// send delivers batch, trying up to attempts times with exponential backoff
+// for network errors, 429, and 5xx responses.
+func (s *Shipper) send(batch [][]byte, attempts int) {
+	if len(batch) == 0 {
+		return
+	}
+	records := make([]shipRecord, 0, len(batch))
+	for _, line := range batch {
+		if r, err := parseRecord(line); err == nil {
+			records = append(records, r)
+		}
+	}
+	body, err := s.encode(records)
+	if err != nil {
+		s.fail(len(batch), err)
+		return
+	}
+
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		retry, err := s.deliver(body)
+		if err == nil {
+			s.sent.Add(int64(len(batch)))
+			return
+		}
+		s.failures.Add(1)
+		if !retry || attempt >= attempts {
+			s.fail(len(batch), err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.stop:
+			s.fail(len(batch), err)
+			return
+		}
+		backoff *= 2
+	}
+}
+
+func (s *Shipper) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("%s responded %s", s.target, resp.Status)
+	}
+	return false, nil
+}
+
+func (s *Shipper) fail(n int, err error) {
+	s.dropped.Add(int64(n))
+	s.lastError.Store(err.Error())
+}
+
+// shipRecord is a log record as written by slog.JSONHandler, in either file
+// format.
+type shipRecord struct {
+	line    string
+	time    time.Time
+	level   string
+	message string
+	attrs   map[string]any
+}
+
+func parseRecord(line []byte) (shipRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return shipRecord{}, err
+	}
+
+	r := shipRecord{line: strings.TrimSuffix(string(line), "\n"), time: time.Now()}
+	take := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := fields[k].(string); ok {
+				delete(fields, k)
+				return v
+			}
+		}
+		return ""
+	}
+	if t, err := time.Parse(time.RFC3339Nano, take("time", "@timestamp")); err == nil {
+		r.time = t
+	}
+	r.level = strings.ToLower(take("level", "log.level"))
+	r.message = take("msg", "message")
+	r.attrs = fields
+	return r, nil
+}
+
+// encodeLoki groups records into one stream per level, labeled with the
+// service name, each line being the whole JSON record.
+func encodeLoki(records []shipRecord) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	var streams []*stream
+	byLevel := map[string]*stream{}
+	for _, r := range records {
+		st, ok := byLevel[r.level]
+		if !ok {
+			st = &stream{Stream: map[string]string{"service_name": ServiceName, "level": r.level}}
+			byLevel[r.level] = st
+			streams = append(streams, st)
+		}
+		st.Values = append(st.Values, [2]string{strconv.FormatInt(r.time.UnixNano(), 10), r.line})
+	}
+	return json.Marshal(map[string]any{"streams": streams})
+}
+
+// encodeOTLP encodes records as an OTLP ExportLogsServiceRequest in the
+// protobuf JSON mapping, with the message as the body and the other fields
+// as attributes.
+func encodeOTLP(records []shipRecord) ([]byte, error) {
+	logRecords := make([]map[string]any, len(records))
+	for i, r := range records {
+		number, text := otlpSeverity(r.level)
+		attrs := make([]map[string]any, 0, len(r.attrs))
+		for k, v := range r.attrs {
+			attrs = append(attrs, map[string]any{"key": k, "value": otlpValue(v)})
+		}
+		logRecords[i] = map[string]any{
+			"timeUnixNano":   strconv.FormatInt(r.time.UnixNano(), 10),
+			"severityNumber": number,
+			"severityText":   text,
+			"body":           map[string]any{"stringValue": r.message},
+			"attributes":     attrs,
+		}
+	}
+	return json.Marshal(map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": []any{
+				map[string]any{"key": "service.name", "value": map[string]any{"stringValue": ServiceName}},
+			}},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": ServiceName},
+				"logRecords": logRecords,
+			}},
+		}},
+	})
+}
+
+// otlpSeverity maps a slog level to its OpenTelemetry severity number and
+// text.
+func otlpSeverity(level string) (int, string) {
+	switch level {
+	case "debug":
+		return 5, "DEBUG"
+	case "warn":
+		return 13, "WARN"
+	case "error":
+		return 17, "ERROR"
+	}
+	return 9, "INFO"
+}
+
+// otlpValue converts a decoded JSON value to an OTLP AnyValue. Objects and
+// arrays are kept as JSON text.
+func otlpValue(v any) map[string]any {
+	switch v := v.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case json.Number:
+		if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return map[string]any{"intValue": string(v)}
+		}
+		f, _ := v.Float64()
+		return map[string]any{"doubleValue": f}
+	case nil:
+		return map[string]any{}
+	}
+	b, _ := json.Marshal(v)
+	return map[string]any{"stringValue": string(b)}
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// stream is a connection to a syslog server or TCP log listener, dialed when
+// it is first written to and again after it fails. Only the Shipper's
+// sending goroutine uses it.
+type stream struct {
+	network string // "tcp" or "udp"
+	addr    string
+	conn    net.Conn
+}
+
+// write sends body, which holds newline-terminated messages. Over UDP each
+// message is a datagram of its own, without the newline. Every failure is
+// worth retrying on a new connection.
+func (s *stream) write(body []byte) (retry bool, err error) {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.addr, shipTimeout)
+		if err != nil {
+			return true, err
+		}
+		s.conn = conn
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(shipTimeout))
+
+	if s.network == "udp" {
+		for line := range bytes.Lines(body) {
+			if _, err := s.conn.Write(bytes.TrimSuffix(line, []byte("\n"))); err != nil {
+				s.close()
+				return true, err
+			}
+		}
+		return false, nil
+	}
+	if _, err := s.conn.Write(body); err != nil {
+		s.close()
+		return true, err
+	}
+	return false, nil
+}
+
+func (s *stream) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// encodeLines writes each record's JSON line followed by a newline.
+func encodeLines(records []shipRecord) ([]byte, error) {
+	var b bytes.Buffer
+	for _, r := range records {
+		b.WriteString(r.line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// syslogFacility is the facility of shipped syslog messages: user-level
+// messages.
+const syslogFacility = 1
+
+// newSyslogEncoder returns an encoder of records as RFC 5424 messages, one
+// per line, from this host and process, with the JSON record as the
+// message.
+func newSyslogEncoder() func([]shipRecord) ([]byte, error) {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "-"
+	}
+	pid := strconv.Itoa(os.Getpid())
+
+	return func(records []shipRecord) ([]byte, error) {
+		var b bytes.Buffer
+		for _, r := range records {
+			pri := syslogFacility*8 + syslogSeverity(r.level)
+			fmt.Fprintf(&b, "<%d>1 %s %s %s %s - - %s\n",
+				pri, r.time.UTC().Format(time.RFC3339Nano), host, ServiceName, pid, r.line)
+		}
+		return b.Bytes(), nil
+	}
+}
+
+// syslogSeverity maps a slog level to its syslog severity.
+func syslogSeverity(level string) int {
+	switch level {
+	case "debug":
+		return 7
+	case "warn":
+		return 4
+	case "error":
+		return 3
+	}
+	return 6
+}
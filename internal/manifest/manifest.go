@@ -0,0 +1,153 @@
+// Package manifest exports and applies the service's configuration, as
+// opposed to its TODO data, as a declarative YAML document that can be kept
+// in version control and applied to another instance.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/validate"
+)
+
+// Version is the manifest format this package reads and writes.
+const Version = 1
+
+// Manifest declares the configuration of an instance.
+type Manifest struct {
+	Version    int        `yaml:"version"`
+	Categories []Category `yaml:"categories"`
+	Projects   []Project  `yaml:"projects"`
+}
+
+// Category declares a category, identified by its name.
+type Category struct {
+	Name      string `yaml:"name"`
+	Color     string `yaml:"color,omitempty"`
+	Icon      string `yaml:"icon,omitempty"`
+	SortOrder int    `yaml:"sort_order,omitempty"`
+}
+
+// Project declares a project, identified by its name.
+type Project struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Color       string `yaml:"color,omitempty"`
+	Icon        string `yaml:"icon,omitempty"`
+}
+
+// Parse decodes and checks a YAML manifest. Unknown fields are rejected so
+// that typos do not silently drop configuration.
+func Parse(data []byte) (Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("decode manifest: %w", err)
+	}
+
+	if m.Version != Version {
+		return Manifest{}, fmt.Errorf("unsupported manifest version %d; want %d", m.Version, Version)
+	}
+
+	seen := map[string]bool{}
+	for i, c := range m.Categories {
+		if err := validate.CreateCategory(c.request()); err != nil {
+			return Manifest{}, fmt.Errorf("categories[%d]: %w", i, err)
+		}
+		if seen[c.Name] {
+			return Manifest{}, fmt.Errorf("categories[%d]: duplicate category %q", i, c.Name)
+		}
+		seen[c.Name] = true
+	}
+
+	seen = map[string]bool{}
+	for i, p := range m.Projects {
+		if err := validate.CreateProject(p.request()); err != nil {
+			return Manifest{}, fmt.Errorf("projects[%d]: %w", i, err)
+		}
+		if seen[p.Name] {
+			return Manifest{}, fmt.Errorf("projects[%d]: duplicate project %q", i, p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	return m, nil
+}
+
+// Marshal encodes m as YAML.
+func (m Manifest) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Export describes the current configuration of repo.
+func Export(repo db.Repository) (Manifest, error) {
+	opts, err := query.Params{}.Options(db.CategorySort)
+	if err != nil {
+		return Manifest{}, err
+	}
+	categories, err := repo.ListCategories(opts)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	opts, err = query.Params{}.Options(db.ProjectSort)
+	if err != nil {
+		return Manifest{}, err
+	}
+	projects, err := repo.ListProjects(opts)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{
+		Version:    Version,
+		Categories: make([]Category, len(categories)),
+		Projects:   make([]Project, len(projects)),
+	}
+	for i, c := range categories {
+		m.Categories[i] = Category{Name: string(c.Name), Color: c.Color, Icon: c.Icon, SortOrder: c.SortOrder}
+	}
+	for i, p := range projects {
+		m.Projects[i] = Project{Name: p.Name, Description: p.Description, Color: p.Color, Icon: p.Icon}
+	}
+	return m, nil
+}
+
+// Apply makes repo's configuration match m, creating and updating entities
+// by name. Entities missing from m are left alone. With dryRun, the changes
+// are reported but not written.
+func Apply(repo db.Repository, m Manifest, dryRun bool) (model.ApplyConfigResponse, error) {
+	categories := make([]model.CreateCategoryRequest, len(m.Categories))
+	for i, c := range m.Categories {
+		categories[i] = c.request()
+	}
+	projects := make([]model.CreateProjectRequest, len(m.Projects))
+	for i, p := range m.Projects {
+		projects[i] = p.request()
+	}
+
+	return repo.ApplyConfig(categories, projects, dryRun)
+}
+
+func (c Category) request() model.CreateCategoryRequest {
+	return model.CreateCategoryRequest{Name: model.Category(c.Name), Color: c.Color, Icon: c.Icon, SortOrder: c.SortOrder}
+}
+
+func (p Project) request() model.CreateProjectRequest {
+	return model.CreateProjectRequest{Name: p.Name, Description: p.Description, Color: p.Color, Icon: p.Icon}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ActorHeader lets clients name themselves in the audit log.
+const ActorHeader = "X-Actor"
+
+type actorKey struct{}
+
+// Actor stores the identity of the caller in the request context. The
+// X-Actor header is used when present; otherwise the remote host is used.
+// Register it after chimw.RealIP so the remote address is the client's.
+func Actor() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+			if actor == "" {
+				actor = ClientIP(r)
+			}
+			ctx := context.WithValue(r.Context(), actorKey{}, actor)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetActor returns the caller identity stored by Actor, or "" if none.
+func GetActor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
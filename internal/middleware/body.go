@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"todo-service/internal/model"
+)
+
+// MaxBodySize rejects requests whose body exceeds limit bytes with 413.
+// Requests that declare a Content-Length over the limit are rejected before
+// the handler runs; bodies of unknown length are cut off at the limit, which
+// surfaces as a read error in the handler.
+func MaxBodySize(limit int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				w.Header().Set("Connection", "close")
+				writeProblem(w, http.StatusRequestEntityTooLarge, model.CodeBodyTooLarge, fmt.Sprintf("request body must not exceed %d bytes", limit))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CacheRule caches responses to GET requests for paths starting with
+// Prefix for TTL.
+type CacheRule struct {
+	Prefix string
+	TTL    time.Duration
+}
+
+// ParseCacheRules parses comma-separated prefix=ttl pairs, such as
+// "/api/v1/board=10s,/api/v1/reports/=1m".
+func ParseCacheRules(s string) ([]CacheRule, error) {
+	var rules []CacheRule
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		prefix, ttl, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("cache rule %q: want prefix=ttl", pair)
+		}
+		prefix, ttl = strings.TrimSpace(prefix), strings.TrimSpace(ttl)
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("cache rule %q: prefix must start with /", pair)
+		}
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("cache rule %q: ttl must be a positive duration", pair)
+		}
+		rules = append(rules, CacheRule{Prefix: prefix, TTL: d})
+	}
+	return rules, nil
+}
+
+// maxCacheEntries bounds the cache; once it is full, new responses are not
+// cached until old ones expire.
+const maxCacheEntries = 1000
+
+type cacheEntry struct {
+	header       http.Header
+	body         []byte
+	generation   uint64
+	stored       time.Time
+	ttl          time.Duration
+	revalidating bool
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// Cache serves successful responses to GET requests matching rules from
+// memory. A response is fresh for its rule's TTL and may then be served
+// stale for up to stale longer while it is refreshed in the background.
+// Responses are discarded as soon as generation changes, which it must do
+// whenever the data they were built from may have changed, so clients never
+// see data older than their own writes. Requests with conditional headers
+// bypass the cache, and Cache-Control: no-cache forces a refresh. The
+// X-Cache response header tells HIT, STALE, and MISS apart.
+func Cache(rules []CacheRule, stale time.Duration, generation func() uint64) func(next http.Handler) http.Handler {
+	c := &responseCache{entries: map[string]*cacheEntry{}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rule, ok := matchCacheRule(rules, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := r.URL.RequestURI() + "\x00" + r.Header.Get("Accept")
+			if r.URL.Query().Get("assignee") == "me" {
+				// Who "me" is depends on the caller.
+				key += "\x00" + GetActor(r.Context())
+			}
+
+			if !strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+				if e, state := c.lookup(key, generation(), stale); e != nil {
+					if state == "STALE" {
+						go c.refresh(next, detach(r), key, rule, generation)
+					}
+					writeCached(w, e, state)
+					return
+				}
+			}
+
+			gen := generation()
+			rec := &recorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.status != http.StatusOK {
+				rec.writeTo(w)
+				return
+			}
+			e := rec.entry(gen, rule.TTL)
+			c.store(key, e)
+			writeCached(w, e, "MISS")
+		})
+	}
+}
+
+func matchCacheRule(rules []CacheRule, r *http.Request) (CacheRule, bool) {
+	if r.Method != http.MethodGet || r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+		return CacheRule{}, false
+	}
+	for _, rule := range rules {
+		if strings.HasPrefix(r.URL.Path, rule.Prefix) {
+			return rule, true
+		}
+	}
+	return CacheRule{}, false
+}
+
+// lookup returns the entry for key if it is still usable, and whether it is
+// fresh (HIT) or STALE. Only the first caller to find an entry stale is told
+// so, and is expected to refresh it; later ones get it as a hit meanwhile.
+func (c *responseCache) lookup(key string, generation uint64, stale time.Duration) (*cacheEntry, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.generation != generation {
+		return nil, ""
+	}
+	age := time.Since(e.stored)
+	switch {
+	case age < e.ttl || e.revalidating:
+		return e, "HIT"
+	case age < e.ttl+stale:
+		e.revalidating = true
+		return e, "STALE"
+	}
+	return nil, ""
+}
+
+// detach copies r for handling after it has been answered: its context is
+// not canceled with r's, and it has its own chi route context, since chi
+// reuses r's for another request once r is answered. The route path is kept
+// so that a copy of a request to a mounted router is routed the same way.
+func detach(r *http.Request) *http.Request {
+	ctx := context.WithoutCancel(r.Context())
+	rctx := chi.NewRouteContext()
+	if old := chi.RouteContext(r.Context()); old != nil {
+		rctx.RoutePath = old.RoutePath
+	}
+	return r.Clone(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+// refresh replaces a stale entry in the background. If the handler fails,
+// the stale entry is dropped, so the next request tries again.
+func (c *responseCache) refresh(next http.Handler, r *http.Request, key string, rule CacheRule, generation func() uint64) {
+	gen := generation()
+	rec := &recorder{header: http.Header{}, status: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	if rec.status != http.StatusOK {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return
+	}
+	c.store(key, rec.entry(gen, rule.TTL))
+}
+
+func (c *responseCache) store(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		for k, old := range c.entries {
+			if old.generation != e.generation || time.Since(old.stored) >= old.ttl {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = e
+}
+
+func writeCached(w http.ResponseWriter, e *cacheEntry, state string) {
+	for k, v := range e.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("X-Cache", state)
+	if state != "MISS" {
+		w.Header().Set("Age", strconv.Itoa(int(time.Since(e.stored).Seconds())))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(e.body)
+}
+
+// recorder buffers a response so that it can be cached.
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+func (r *recorder) entry(generation uint64, ttl time.Duration) *cacheEntry {
+	return &cacheEntry{header: r.header, body: r.body.Bytes(), generation: generation, stored: time.Now(), ttl: ttl}
+}
+
+// writeTo sends the recorded response to w uncached.
+func (r *recorder) writeTo(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestCacheStaleRefreshConcurrent serves many stale hits at once, so that
+// background refreshes run while chi reuses route contexts for new
+// requests. Run it with -race.
+func TestCacheStaleRefreshConcurrent(t *testing.T) {
+	rules := []CacheRule{{Prefix: "/board/", TTL: time.Millisecond}}
+	router := chi.NewMux()
+	router.Use(Cache(rules, time.Hour, func() uint64 { return 1 }))
+	router.Get("/board/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, chi.URLParam(r, "id"))
+	})
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	get := func(id int) (string, string, error) {
+		resp, err := http.Get(fmt.Sprintf("%s/board/%d", srv.URL, id))
+		if err != nil {
+			return "", "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), resp.Header.Get("X-Cache"), err
+	}
+
+	const ids = 20
+	for round := range 10 {
+		var wg sync.WaitGroup
+		for i := range ids * 5 {
+			wg.Go(func() {
+				id := i % ids
+				body, state, err := get(id)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if body != fmt.Sprint(id) {
+					t.Errorf("round %d: /board/%d answered %q (%s)", round, id, body, state)
+				}
+			})
+		}
+		wg.Wait()
+		time.Sleep(2 * time.Millisecond)
+	}
+}
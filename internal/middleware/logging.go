@@ -1,13 +1,20 @@
 package middleware
 
 import (
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	chimw "github.com/go-chi/chi/v5/middleware"
+
+	applog "todo-service/internal/logger"
+	"todo-service/internal/model"
 )
 
 // responseRecorder wraps http.ResponseWriter to capture status code and bytes written.
@@ -28,12 +35,113 @@ func (rr *responseRecorder) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// RequestLogger logs every HTTP request with structured attributes.
-func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
+// PreflightRoute names CORS preflight requests in access log sampling
+// rules.
+const PreflightRoute = "OPTIONS"
+
+// LogOption configures RequestLogger.
+type LogOption func(*accessLog)
+
+// accessLog decides which requests RequestLogger logs.
+type accessLog struct {
+	rules []*sampleRule
+}
+
+// sampleRule logs one of every n successful requests to a route.
+type sampleRule struct {
+	route string
+	n     uint64
+	seen  atomic.Uint64
+}
+
+// SampleAccessLog makes RequestLogger log only the first of every n
+// successful requests to route, such as a health check polled every few
+// seconds, or none of them if n is 0. Requests that fail with a 4xx or 5xx
+// status are always logged. The route is a path, matched exactly; a path
+// ending in /*, matching every path under it; or PreflightRoute. The first
+// rule matching a request applies.
+func SampleAccessLog(route string, n int) LogOption {
+	return func(l *accessLog) {
+		l.rules = append(l.rules, &sampleRule{route: route, n: uint64(max(n, 0))})
+	}
+}
+
+// ParseAccessLogSampling parses comma-separated route=n access log sampling
+// rules, such as "/healthz=0,OPTIONS=10", into options for RequestLogger.
+func ParseAccessLogSampling(s string) ([]LogOption, error) {
+	var opts []LogOption
+	for rule := range strings.SplitSeq(s, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(rule, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		route = strings.TrimSpace(route)
+		if !ok || err != nil || n < 0 {
+			return nil, fmt.Errorf("access log sampling rule %q: want route=n with n a whole number", rule)
+		}
+		if route != PreflightRoute && !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("access log sampling rule %q: route must be a path or %s", rule, PreflightRoute)
+		}
+		opts = append(opts, SampleAccessLog(route, n))
+	}
+	return opts, nil
+}
+
+// sample reports whether to log a request to r that responded status, and
+// if it is sampled, one of how many requests is logged.
+func (l *accessLog) sample(r *http.Request, status int) (keep bool, rate uint64) {
+	if status >= 400 {
+		return true, 0
+	}
+	for _, rule := range l.rules {
+		if !rule.matches(r) {
+			continue
+		}
+		if rule.n == 0 {
+			return false, 0
+		}
+		return (rule.seen.Add(1)-1)%rule.n == 0, rule.n
+	}
+	return true, 0
+}
+
+func (s *sampleRule) matches(r *http.Request) bool {
+	if s.route == PreflightRoute {
+		return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	}
+	if prefix, ok := strings.CutSuffix(s.route, "/*"); ok {
+		return r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, prefix+"/")
+	}
+	return r.URL.Path == s.route
+}
+
+// RequestLogger logs every HTTP request with structured attributes, or with
+// SampleAccessLog options, a sample of the successful requests to noisy
+// routes. It also stores the request ID, the actor, and the W3C trace ID,
+// if the client sent a traceparent header, in the request context, so that
+// every record logged with that context carries them. Register it after
+// chimw.RequestID and Actor.
+func RequestLogger(logger *slog.Logger, opts ...LogOption) func(next http.Handler) http.Handler {
+	access := &accessLog{}
+	for _, opt := range opts {
+		opt(access)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
+			attrs := []slog.Attr{
+				slog.String("request_id", chimw.GetReqID(r.Context())),
+				slog.String("actor", GetActor(r.Context())),
+			}
+			if traceID := TraceID(r); traceID != "" {
+				attrs = append(attrs, slog.String("trace_id", traceID))
+			}
+			r = r.WithContext(applog.WithAttrs(r.Context(), attrs...))
+
 			rec := &responseRecorder{
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
@@ -42,7 +150,11 @@ func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
 			next.ServeHTTP(rec, r)
 
 			duration := time.Since(start)
-			reqID := chimw.GetReqID(r.Context())
+
+			keep, rate := access.sample(r, rec.statusCode)
+			if !keep {
+				return
+			}
 
 			level := slog.LevelInfo
 			if rec.statusCode >= 500 {
@@ -51,36 +163,53 @@ func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
 				level = slog.LevelWarn
 			}
 
-			logger.Log(r.Context(), level, "request completed",
+			attrs = []slog.Attr{
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Int("status", rec.statusCode),
 				slog.Float64("duration_ms", float64(duration.Microseconds())/1000.0),
-				slog.String("request_id", reqID),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.Int("bytes", rec.bytesWritten),
-			)
+			}
+			if rate > 1 {
+				// Lets log queries scale counts back up.
+				attrs = append(attrs, slog.Uint64("sample_rate", rate))
+			}
+			logger.LogAttrs(r.Context(), level, "request completed", attrs...)
 		})
 	}
 }
 
+// TraceID returns the trace ID from r's W3C traceparent header, or "" if
+// there is none or it is malformed.
+func TraceID(r *http.Request) string {
+	// version-trace_id-parent_id-flags, such as
+	// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	parts := strings.Split(r.Header.Get("traceparent"), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
 // Recovery recovers from panics and logs the error with a stack trace.
 func Recovery(logger *slog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if rvr := recover(); rvr != nil {
-					logger.Error("panic recovered",
+					logger.ErrorContext(r.Context(), "panic recovered",
 						slog.String("error", fmt.Sprintf("%v", rvr)),
 						slog.String("stack", string(debug.Stack())),
 						slog.String("method", r.Method),
 						slog.String("path", r.URL.Path),
 					)
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					w.Write([]byte(`{"error":"internal server error","message":"an unexpected error occurred"}`))
+					writeProblem(w, http.StatusInternalServerError, model.CodeInternal, "an unexpected error occurred")
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -94,7 +223,9 @@ func CORS() func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID, X-Actor, X-Sandbox, traceparent, If-Match, If-None-Match, If-Modified-Since")
+			w.Header().Set("Access-Control-Expose-Headers", "Server-Timing, ETag, Last-Modified, Link, X-Total-Count, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Quota-Daily-Limit, X-Quota-Daily-Remaining, X-Quota-Monthly-Limit, X-Quota-Monthly-Remaining, X-Quota-Reset, Content-Disposition, X-Sandbox, X-Cache, Age")
+			w.Header().Set("Timing-Allow-Origin", "*")
 
 			if r.Method == http.MethodOptions {
 				w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Maintenance holds the API's read-only switch. It is safe for concurrent
+// use, and the zero value is writable.
+type Maintenance struct {
+	mu   sync.RWMutex
+	mode model.MaintenanceMode
+}
+
+// Mode returns the current mode.
+func (m *Maintenance) Mode() model.MaintenanceMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mode
+}
+
+// Set applies req and returns the resulting mode. Turning read-only mode on
+// again keeps the time it was first turned on.
+func (m *Maintenance) Set(req model.MaintenanceRequest) model.MaintenanceMode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !req.ReadOnly {
+		m.mode = model.MaintenanceMode{}
+		return m.mode
+	}
+	since := m.mode.Since
+	if since == nil {
+		now := time.Now().UTC()
+		since = &now
+	}
+	m.mode = model.MaintenanceMode{ReadOnly: true, Message: req.Message, RetryAfterSeconds: req.RetryAfterSeconds, Since: since}
+	return m.mode
+}
+
+// ReadOnlyMessage returns the message to refuse changes with while mode is
+// read-only: the operator's, or a default one.
+func ReadOnlyMessage(mode model.MaintenanceMode) string {
+	if mode.Message == "" {
+		return "the service is read-only for maintenance"
+	}
+	return mode.Message
+}
+
+// ReadOnly rejects requests other than GET, HEAD, and OPTIONS, and the
+// PROPFIND and REPORT reads of CalDAV clients, with 503 and a Retry-After
+// header while m is read-only. Paths starting with one of
+// exempt, such as the admin operations needed to finish maintenance, are
+// always served.
+func ReadOnly(m *Maintenance, exempt ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT":
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, prefix := range exempt {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			mode := m.Mode()
+			if !mode.ReadOnly {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if mode.RetryAfterSeconds > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(mode.RetryAfterSeconds))
+			}
+			writeProblem(w, http.StatusServiceUnavailable, model.CodeReadOnly, ReadOnlyMessage(mode))
+		})
+	}
+}
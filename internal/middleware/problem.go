@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"todo-service/internal/model"
+)
+
+// problem is the body of the error responses middleware writes, in the
+// shape of the API's own errors: RFC 9457 problem details, as huma writes
+// them, with a code from the catalog in model.ErrorCodes.
+type problem struct {
+	Title  string          `json:"title"`
+	Status int             `json:"status"`
+	Detail string          `json:"detail"`
+	Code   model.ErrorCode `json:"code"`
+}
+
+// writeProblem writes an application/problem+json error response with
+// status, code, and detail. Headers set before it are sent with it.
+func writeProblem(w http.ResponseWriter, status int, code model.ErrorCode, detail string) {
+	body, _ := json.Marshal(problem{Title: http.StatusText(status), Status: status, Detail: detail, Code: code})
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/quota"
+	"todo-service/internal/ratelimit"
+)
+
+// TestProblemResponses checks that the errors middleware writes itself are
+// problem details with a code, like the API's other errors.
+func TestProblemResponses(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	maintenance := &Maintenance{}
+	maintenance.Set(model.MaintenanceRequest{ReadOnly: true, Message: "Backing up", RetryAfterSeconds: 60})
+	logger := slog.New(slog.DiscardHandler)
+	repo, err := db.NewMemory(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	tracker, err := quota.New(repo, model.QuotaLimits{Daily: 1}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		req     *http.Request
+		status  int
+		code    model.ErrorCode
+	}{
+		{
+			name:    "body too large",
+			handler: MaxBodySize(4)(ok),
+			req:     httptest.NewRequest(http.MethodPost, "/api/v1/todos", strings.NewReader("12345")),
+			status:  http.StatusRequestEntityTooLarge,
+			code:    model.CodeBodyTooLarge,
+		},
+		{
+			name:    "rate limited",
+			handler: RateLimit(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 1}, ClientIP, logger)(ok),
+			req:     httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil),
+			status:  http.StatusTooManyRequests,
+			code:    model.CodeRateLimited,
+		},
+		{
+			name:    "quota used up",
+			handler: Quota(tracker, ClientIP)(ok),
+			req:     httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil),
+			status:  http.StatusTooManyRequests,
+			code:    model.CodeQuotaExceeded,
+		},
+		{
+			name:    "read-only",
+			handler: ReadOnly(maintenance)(ok),
+			req:     httptest.NewRequest(http.MethodPost, "/api/v1/todos", nil),
+			status:  http.StatusServiceUnavailable,
+			code:    model.CodeReadOnly,
+		},
+		{
+			name:    "panic",
+			handler: Recovery(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") })),
+			req:     httptest.NewRequest(http.MethodGet, "/api/v1/todos", nil),
+			status:  http.StatusInternalServerError,
+			code:    model.CodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Rate limits and quotas let the first request through.
+			tt.handler.ServeHTTP(httptest.NewRecorder(), tt.req.Clone(tt.req.Context()))
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, tt.req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type %q, want application/problem+json", ct)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			if body["title"] != http.StatusText(tt.status) || body["status"] != float64(tt.status) ||
+				body["code"] != string(tt.code) || body["detail"] == "" {
+				t.Errorf("body %s, want title, status, detail, and code %s", rec.Body, tt.code)
+			}
+			if len(body) != 4 {
+				t.Errorf("body %s has fields other than title, status, detail, and code", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/quota"
+)
+
+// ActorKey keys quotas by the caller's identity as stored by Actor: the
+// X-Actor header, or the client's address without one. Clients choose
+// their own X-Actor, so use it only where they are trusted to.
+func ActorKey(r *http.Request) string {
+	return GetActor(r.Context())
+}
+
+// ParseQuotaKey returns the key function a -quota-key value names: ip for
+// ClientIP, or actor for ActorKey.
+func ParseQuotaKey(s string) (RateLimitKeyFunc, error) {
+	switch s {
+	case "ip":
+		return ClientIP, nil
+	case "actor":
+		return ActorKey, nil
+	}
+	return nil, fmt.Errorf("unknown quota key %q, want ip or actor", s)
+}
+
+// Quota counts requests against the client's daily and monthly quotas and
+// rejects them with 429 once either is used up, reporting the quotas that
+// are set in X-Quota-* headers. Paths in exclude are neither counted nor
+// limited.
+func Quota(tracker *quota.Tracker, key RateLimitKeyFunc, exclude ...string) func(next http.Handler) http.Handler {
+	limits := tracker.Limits()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exclude, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			res := tracker.Take(key(r), now)
+			if limits.Daily > 0 {
+				w.Header().Set("X-Quota-Daily-Limit", strconv.FormatInt(limits.Daily, 10))
+				w.Header().Set("X-Quota-Daily-Remaining", strconv.FormatInt(res.DailyRemaining, 10))
+			}
+			if limits.Monthly > 0 {
+				w.Header().Set("X-Quota-Monthly-Limit", strconv.FormatInt(limits.Monthly, 10))
+				w.Header().Set("X-Quota-Monthly-Remaining", strconv.FormatInt(res.MonthlyRemaining, 10))
+			}
+			resetAfter := int(math.Ceil(res.Reset.Sub(now).Seconds()))
+			if limits.Daily > 0 || limits.Monthly > 0 {
+				w.Header().Set("X-Quota-Reset", strconv.Itoa(resetAfter))
+			}
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(resetAfter))
+				writeProblem(w, http.StatusTooManyRequests, model.CodeQuotaExceeded, fmt.Sprintf("request quota used up, renews in %d seconds", resetAfter))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"todo-service/internal/model"
+	"todo-service/internal/ratelimit"
+)
+
+// RateLimitKeyFunc derives the bucket key for a request.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// ClientIP keys rate limit buckets by the client's address. Register
+// RateLimit after chimw.RealIP so proxied clients are told apart.
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// RateLimit rejects requests with 429 once the client's token bucket is
+// empty, and reports the bucket state in X-RateLimit-* headers. Paths in
+// exclude are never limited. If the store fails, requests are allowed.
+func RateLimit(store ratelimit.Store, limit ratelimit.Limit, key RateLimitKeyFunc, logger *slog.Logger, exclude ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exclude, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := store.Take(r.Context(), key(r), limit)
+			if err != nil {
+				logger.ErrorContext(r.Context(), "rate limit store failed", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(res.ResetAfter.Seconds()))))
+
+			if !res.Allowed {
+				retryAfter := int(math.Ceil(res.RetryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				writeProblem(w, http.StatusTooManyRequests, model.CodeRateLimited, fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfter))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
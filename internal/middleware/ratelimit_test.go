@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-service/internal/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	limit := ratelimit.Limit{Rate: 0.5, Burst: 2}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := RateLimit(ratelimit.NewMemoryStore(), limit, ClientIP, slog.New(slog.DiscardHandler), "/healthz")(ok)
+
+	get := func(path, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, want := range []string{"1", "0"} {
+		rec := get("/api/v1/todos", "192.0.2.1:1234")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: got %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != want {
+			t.Errorf("request %d: X-RateLimit-Remaining %q, want %q", i+1, got, want)
+		}
+		if got := rec.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: X-RateLimit-Limit %q, want 2", i+1, got)
+		}
+	}
+
+	// The burst is spent; a token takes two seconds to come back.
+	rec := get("/api/v1/todos", "192.0.2.1:5678")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request past the burst: got %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After %q, want 2", got)
+	}
+	if got := rec.Header().Get("X-RateLimit-Reset"); got != "4" {
+		t.Errorf("X-RateLimit-Reset %q, want 4", got)
+	}
+
+	// Excluded paths and other clients are not limited.
+	if rec := get("/healthz", "192.0.2.1:1234"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+		t.Errorf("excluded path: got %d with limit %q, want 200 without rate limit headers", rec.Code, rec.Header().Get("X-RateLimit-Limit"))
+	}
+	if rec := get("/api/v1/todos", "192.0.2.2:1234"); rec.Code != http.StatusOK {
+		t.Errorf("another client: got %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"todo-service/internal/logger"
+)
+
+// SandboxHeader selects the sandbox dataset when set to true.
+const SandboxHeader = "X-Sandbox"
+
+// Sandbox serves requests whose X-Sandbox header is true with sandbox
+// instead of the next handler, and marks their responses and log records.
+// Register it after the other middleware so both datasets share them.
+func Sandbox(sandbox http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if on, _ := strconv.ParseBool(r.Header.Get(SandboxHeader)); !on {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(SandboxHeader, "true")
+			ctx := logger.WithAttrs(r.Context(), slog.Bool("sandbox", true))
+			sandbox.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"todo-service/internal/timing"
+)
+
+// timingWriter wraps http.ResponseWriter to emit the Server-Timing header
+// just before the response headers are sent.
+type timingWriter struct {
+	http.ResponseWriter
+	rec         *timing.Recorder
+	wroteHeader bool
+	headerAt    time.Time
+}
+
+func (tw *timingWriter) WriteHeader(code int) {
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		tw.headerAt = time.Now()
+		tw.Header().Set("Server-Timing", tw.rec.Header())
+	}
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timingWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// ServerTiming attaches a timing.Recorder to each request context and reports
+// the recorded stages in a Server-Timing response header. Time spent writing
+// the body after the headers are sent is recorded as the serialization stage,
+// which is only visible in the debug log.
+func ServerTiming(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := timing.NewRecorder()
+			tw := &timingWriter{ResponseWriter: w, rec: rec}
+
+			next.ServeHTTP(tw, r.WithContext(timing.WithRecorder(r.Context(), rec)))
+
+			if tw.wroteHeader {
+				rec.Add(timing.StageSerialization, time.Since(tw.headerAt))
+			}
+
+			if !logger.Enabled(r.Context(), slog.LevelDebug) {
+				return
+			}
+			attrs := []any{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("request_id", chimw.GetReqID(r.Context())),
+				slog.Float64("total_ms", float64(rec.Elapsed().Microseconds())/1000.0),
+			}
+			for _, s := range rec.Stages() {
+				attrs = append(attrs, slog.Float64(s.Name+"_ms", float64(s.Duration.Microseconds())/1000.0))
+			}
+			logger.Debug("request timing", attrs...)
+		})
+	}
+}
+
+// TimingCheckpoint records the time elapsed since ServerTiming started as the
+// middleware stage. Register it as the last middleware in the chain.
+func TimingCheckpoint() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := timing.FromContext(r.Context())
+			rec.Add(timing.StageMiddleware, rec.Elapsed())
+			next.ServeHTTP(w, r)
+		})
+	}
+}
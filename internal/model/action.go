@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// ActionOperation is what an action does when it is run.
+type ActionOperation string
+
+const (
+	// ActionCompleteFirst completes the first TODO matching the action's
+	// filter that is not done yet.
+	ActionCompleteFirst ActionOperation = "complete_first"
+	// ActionStartFirst moves the first pending TODO matching the action's
+	// filter to in progress.
+	ActionStartFirst ActionOperation = "start_first"
+	// ActionCreate creates a TODO from the action's template.
+	ActionCreate ActionOperation = "create"
+)
+
+// ValidActionOperations contains the operations an action may have.
+var ValidActionOperations = map[ActionOperation]bool{
+	ActionCompleteFirst: true,
+	ActionStartFirst:    true,
+	ActionCreate:        true,
+}
+
+// Action is a named, preconfigured operation that can be run with a single
+// request, such as from a hardware button.
+type Action struct {
+	ID        int64              `json:"id" example:"1"`
+	Name      string             `json:"name" example:"Finish next work item"`
+	Operation ActionOperation    `json:"operation" example:"complete_first" enums:"complete_first,start_first,create"`
+	Filter    *ViewFilter        `json:"filter,omitempty" doc:"TODOs a complete_first or start_first action picks from, first in the filter's sort or else manual order"`
+	Todo      *CreateTodoRequest `json:"todo,omitempty" doc:"TODO a create action creates"`
+	CreatedAt time.Time          `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt time.Time          `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// ActionRequest is the payload for creating an action or replacing one.
+// complete_first and start_first actions take a filter, which matches every
+// active TODO if omitted; create actions take a todo.
+type ActionRequest struct {
+	Name      string             `json:"name" example:"Finish next work item" maxLength:"100"`
+	Operation ActionOperation    `json:"operation" example:"complete_first" enums:"complete_first,start_first,create"`
+	Filter    *ViewFilter        `json:"filter,omitempty"`
+	Todo      *CreateTodoRequest `json:"todo,omitempty"`
+}
+
+// ActionListResponse wraps a page of actions.
+type ActionListResponse struct {
+	Actions []Action `json:"actions"`
+	Count   int      `json:"count" example:"2"`
+	Total   int      `json:"total" example:"2"`
+}
+
+// ActionResult reports the TODO an action created or changed.
+type ActionResult struct {
+	ActionID  int64           `json:"action_id" example:"1"`
+	Operation ActionOperation `json:"operation" example:"complete_first" enums:"complete_first,start_first,create"`
+	Todo      Todo            `json:"todo"`
+}
+
+// MaxActionNameLength limits action names, in characters. The maxLength
+// schema tag on ActionRequest must match it.
+const MaxActionNameLength = 100
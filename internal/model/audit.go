@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"todo-service/internal/diff"
+)
+
+// AuditAction is the kind of mutation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// FieldChange holds the old and new value of a single field. Changes to long
+// text fields are reported as a word-level Diff instead.
+type FieldChange struct {
+	Old  any       `json:"old" required:"false" doc:"Previous value; omitted when diff is set"`
+	New  any       `json:"new" required:"false" doc:"New value; omitted when diff is set"`
+	Diff []diff.Op `json:"diff,omitempty" doc:"Word-level changes from the old to the new value"`
+}
+
+// MarshalJSON writes either the diff or the old and new values, never both.
+func (c FieldChange) MarshalJSON() ([]byte, error) {
+	if c.Diff != nil {
+		return json.Marshal(struct {
+			Diff []diff.Op `json:"diff"`
+		}{c.Diff})
+	}
+	return json.Marshal(struct {
+		Old any `json:"old"`
+		New any `json:"new"`
+	}{c.Old, c.New})
+}
+
+// AuditEntry records a single mutation of a TODO item.
+type AuditEntry struct {
+	ID          int64                  `json:"id" example:"1"`
+	TodoID      int64                  `json:"todo_id" example:"1"`
+	Action      AuditAction            `json:"action" example:"update" enums:"create,update,delete"`
+	Actor       string                 `json:"actor" example:"127.0.0.1"`
+	RequestID   string                 `json:"request_id" example:"host/abc123-000001"`
+	OperationID string                 `json:"operation_id" example:"op_5f2c9a1e7b3d4c60"`
+	Changes     map[string]FieldChange `json:"changes"`
+	CreatedAt   time.Time              `json:"created_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// AuditListResponse wraps a page of audit entries.
+type AuditListResponse struct {
+	Entries []AuditEntry `json:"entries"`
+	Count   int          `json:"count" example:"5"`
+	Total   int          `json:"total" example:"42"`
+}
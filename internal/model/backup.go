@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Backup describes a snapshot of the database kept by the service.
+type Backup struct {
+	Name      string    `json:"name" example:"todos-20260212T020000.000Z.db" doc:"File name, used to download the backup"`
+	SizeBytes int64     `json:"size_bytes" example:"4096000"`
+	CreatedAt time.Time `json:"created_at" example:"2026-02-12T02:00:00Z"`
+}
+
+// BackupListResponse lists the backups kept by the service, newest first.
+type BackupListResponse struct {
+	Backups []Backup `json:"backups"`
+	Count   int      `json:"count" example:"7"`
+}
+
+// RestoreRequest names the backup to restore.
+type RestoreRequest struct {
+	Backup string `json:"backup" minLength:"1" example:"todos-20260212T020000.000Z.db" doc:"Name of the backup, as listed by list-backups"`
+}
+
+// RestoreResponse reports what a restore changed, or would change on a dry
+// run.
+type RestoreResponse struct {
+	Backup        string         `json:"backup" example:"todos-20260212T020000.000Z.db"`
+	DryRun        bool           `json:"dry_run"`
+	SafetyBackup  string         `json:"safety_backup,omitempty" example:"todos-20260213T091500.000Z.db" doc:"Backup of the database as it was before the restore, to undo it with; absent on dry runs"`
+	SchemaVersion int            `json:"schema_version" example:"17" doc:"Schema version of the backup; migrations newer than it are applied as part of the restore"`
+	Tables        []TableRestore `json:"tables"`
+}
+
+// TableRestore compares the row count of a table before and after a
+// restore. Tables missing on one side count 0 rows there.
+type TableRestore struct {
+	Name         string `json:"name" example:"todos"`
+	CurrentRows  int    `json:"current_rows" example:"1520"`
+	RestoredRows int    `json:"restored_rows" example:"1488"`
+}
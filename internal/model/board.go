@@ -0,0 +1,82 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WIPLimits caps how many active TODOs each board column, one per status,
+// may hold. Statuses without a limit are uncapped.
+type WIPLimits map[Status]int
+
+// String formats l as a comma-separated list of status=limit pairs, the
+// form ParseWIPLimits accepts.
+func (l WIPLimits) String() string {
+	var pairs []string
+	for _, status := range statusOrder {
+		if n, ok := l[status]; ok {
+			pairs = append(pairs, fmt.Sprintf("%s=%d", status, n))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseWIPLimits parses a comma-separated list of status=limit pairs, such
+// as "in_progress=3". An empty string sets no limits.
+func ParseWIPLimits(s string) (WIPLimits, error) {
+	l := WIPLimits{}
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		status, limit, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("WIP limit %q: want status=limit", pair)
+		}
+		status, limit = strings.TrimSpace(status), strings.TrimSpace(limit)
+		if !ValidStatuses[Status(status)] {
+			return nil, fmt.Errorf("WIP limit %q: unknown status %q", pair, status)
+		}
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("WIP limit %q: limit must be a positive number", pair)
+		}
+		l[Status(status)] = n
+	}
+	return l, nil
+}
+
+// BoardColumn is one status column of the board.
+type BoardColumn struct {
+	Status    Status `json:"status" example:"in_progress" enums:"pending,in_progress,done"`
+	Count     int    `json:"count" example:"4" doc:"Number of TODOs in the column, including any beyond limit"`
+	WIPLimit  *int   `json:"wip_limit" example:"3" doc:"Most TODOs the column may hold, or null if it is uncapped"`
+	OverLimit bool   `json:"over_limit" doc:"True if the column holds more TODOs than its WIP limit"`
+	Todos     []Todo `json:"todos"`
+}
+
+// BoardResponse is the kanban board: active TODOs in one column per status,
+// each in manual order.
+type BoardResponse struct {
+	Columns []BoardColumn `json:"columns"`
+}
+
+// MoveCardRequest is the payload for moving a TODO on the board: to a
+// status column and, optionally, to a place in it. Without a place, the
+// TODO keeps its position.
+type MoveCardRequest struct {
+	TodoID int64  `json:"todo_id" example:"1"`
+	Status Status `json:"status" example:"in_progress" enums:"pending,in_progress,done"`
+	Before *int64 `json:"before,omitempty" example:"3" doc:"Place the TODO just before the TODO with this ID"`
+	After  *int64 `json:"after,omitempty" example:"2" doc:"Place the TODO just after the TODO with this ID"`
+	Index  *int   `json:"index,omitempty" example:"0" minimum:"0" doc:"Place the TODO at this zero-based index in the column; past the end places it last"`
+}
+
+// Place returns the part of r that positions the TODO, and whether one was
+// given.
+func (r MoveCardRequest) Place() (MoveTodoRequest, bool) {
+	m := MoveTodoRequest{Before: r.Before, After: r.After, Index: r.Index}
+	return m, m.Before != nil || m.After != nil || m.Index != nil
+}
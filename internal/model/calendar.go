@@ -0,0 +1,23 @@
+package model
+
+// CalendarResponse summarizes a month of due and scheduled TODOs, one entry
+// per day.
+type CalendarResponse struct {
+	Month string        `json:"month" example:"2026-02"`
+	Days  []CalendarDay `json:"days" doc:"Every day of the month, in order"`
+}
+
+// CalendarDay counts the TODOs due or scheduled on a single day.
+type CalendarDay struct {
+	Date      string        `json:"date" format:"date" example:"2026-02-16"`
+	Due       CalendarCount `json:"due" doc:"TODOs due on the day; open ones on past days are overdue"`
+	Scheduled CalendarCount `json:"scheduled" doc:"TODOs scheduled for the day"`
+}
+
+// CalendarCount counts the TODOs due or scheduled on a day.
+type CalendarCount struct {
+	Total int     `json:"total" example:"3"`
+	Open  int     `json:"open" example:"2" doc:"TODOs that are pending or in progress"`
+	Done  int     `json:"done" example:"1"`
+	IDs   []int64 `json:"ids" example:"[4,7,9]" doc:"IDs of the TODOs, in ID order"`
+}
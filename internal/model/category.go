@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// CategoryInfo describes a category TODO items can be filed under. TODOs
+// refer to it by name.
+type CategoryInfo struct {
+	ID        int64     `json:"id" example:"1"`
+	Name      Category  `json:"name" example:"personal"`
+	Color     string    `json:"color" example:"#4caf50" doc:"Display color as #rrggbb, or empty for none"`
+	Icon      string    `json:"icon" example:"home" doc:"Name of the icon to show the category with, or empty for none"`
+	SortOrder int       `json:"sort_order" example:"0" doc:"Position in category lists, lowest first"`
+	Todos     int       `json:"todos" example:"12" doc:"Number of TODOs in the category, archived ones included"`
+	CreatedAt time.Time `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// Limits on category names and the format of their colors. The schema tags
+// on the request payloads must match them.
+const (
+	MaxCategoryNameLength = 50
+	ColorPattern          = "^#[0-9a-fA-F]{6}$"
+)
+
+// CreateCategoryRequest is the payload for creating a new category.
+type CreateCategoryRequest struct {
+	Name      Category `json:"name" example:"errands" maxLength:"50"`
+	Color     string   `json:"color,omitempty" example:"#ff9800" pattern:"^#[0-9a-fA-F]{6}$" doc:"Display color as #rrggbb"`
+	Icon      string   `json:"icon,omitempty" example:"cart" enum:"bag,book,briefcase,calendar,car,cart,code,flag,gift,heart,home,inbox,leaf,lightbulb,mail,money,music,phone,star,tag,tool,travel,users" doc:"Name of the icon to show the category with"`
+	SortOrder int      `json:"sort_order,omitempty" example:"3" doc:"Position in category lists, lowest first"`
+}
+
+// UpdateCategoryRequest is the payload for updating a category. All fields
+// are optional. Renaming a category renames it on its TODOs.
+type UpdateCategoryRequest struct {
+	Name      *Category `json:"name,omitempty" example:"errands" maxLength:"50"`
+	Color     *string   `json:"color,omitempty" example:"#ff9800" doc:"Display color as #rrggbb; empty removes it"`
+	Icon      *string   `json:"icon,omitempty" example:"cart" doc:"Name of the icon to show the category with; empty removes it"`
+	SortOrder *int      `json:"sort_order,omitempty" example:"3"`
+}
+
+// CategoryListResponse wraps a page of categories.
+type CategoryListResponse struct {
+	Categories []CategoryInfo `json:"categories"`
+	Count      int            `json:"count" example:"3"`
+	Total      int            `json:"total" example:"3"`
+}
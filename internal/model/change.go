@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ChangeKind is what happened to a TODO in the change feed.
+type ChangeKind string
+
+const (
+	// ChangeUpsert means the TODO was created or updated; the change
+	// carries its current state.
+	ChangeUpsert ChangeKind = "upsert"
+	// ChangeDelete is a tombstone: the TODO was deleted.
+	ChangeDelete ChangeKind = "delete"
+)
+
+// Change is an entry of the change feed: the latest change to a TODO.
+type Change struct {
+	Seq       int64      `json:"seq" example:"42" doc:"Position of the change in the feed; increases with every change"`
+	Kind      ChangeKind `json:"kind" enum:"upsert,delete" example:"upsert"`
+	TodoID    int64      `json:"todo_id" example:"1"`
+	Todo      *Todo      `json:"todo,omitempty" doc:"The TODO as it is now, for upserts"`
+	ChangedAt time.Time  `json:"changed_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// ChangeFeedResponse is a page of the change feed.
+type ChangeFeedResponse struct {
+	Changes []Change `json:"changes"`
+	Cursor  int64    `json:"cursor" example:"42" doc:"Pass as since to read the changes after these"`
+	HasMore bool     `json:"has_more" example:"false" doc:"Whether more changes are waiting after cursor"`
+}
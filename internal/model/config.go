@@ -0,0 +1,16 @@
+package model
+
+// ConfigChanges lists, by name, what applying a configuration did to one
+// kind of entity.
+type ConfigChanges struct {
+	Created   []string `json:"created" example:"Groceries"`
+	Updated   []string `json:"updated" example:"Home renovation"`
+	Unchanged []string `json:"unchanged" example:"Garden"`
+}
+
+// ApplyConfigResponse reports the outcome of applying a configuration.
+type ApplyConfigResponse struct {
+	DryRun     bool          `json:"dry_run" doc:"True if nothing was written"`
+	Categories ConfigChanges `json:"categories"`
+	Projects   ConfigChanges `json:"projects"`
+}
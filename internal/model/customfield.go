@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CustomFieldType is the kind of value a custom field holds.
+type CustomFieldType string
+
+const (
+	CustomFieldText   CustomFieldType = "text"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldDate   CustomFieldType = "date"
+	CustomFieldEnum   CustomFieldType = "enum"
+)
+
+// CustomFieldTypes lists every custom field type.
+var CustomFieldTypes = []CustomFieldType{CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldEnum}
+
+// Limits on custom field definitions and text values. The schema tags on the
+// request payloads must match them.
+const (
+	MaxCustomFieldNameLength   = 50
+	MaxCustomFieldOptions      = 50
+	MaxCustomFieldOptionLength = 100
+	MaxCustomFieldTextLength   = 1000
+	CustomFieldNamePattern     = "^[a-z][a-z0-9_]*$"
+)
+
+// CustomField defines a piece of metadata TODOs can carry beyond their
+// built-in fields. TODOs hold its values under its name.
+type CustomField struct {
+	ID        int64           `json:"id" example:"1"`
+	Name      string          `json:"name" example:"size"`
+	Type      CustomFieldType `json:"type" example:"enum" enums:"text,number,date,enum"`
+	Options   []string        `json:"options" example:"[\"small\",\"medium\",\"large\"]" doc:"Values an enum field may hold; empty for other types"`
+	Todos     int             `json:"todos" example:"12" doc:"Number of TODOs with a value for the field"`
+	CreatedAt time.Time       `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt time.Time       `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// Check returns v as the field stores it, or an error if v is not a value of
+// the field's type: a string for text, date (as DateLayout), and enum
+// fields, and a number for number fields.
+func (f CustomField) Check(v any) (any, error) {
+	if f.Type == CustomFieldNumber {
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		}
+		return nil, fmt.Errorf("%s must be a number", f.Name)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a string", f.Name)
+	}
+	switch f.Type {
+	case CustomFieldText:
+		if utf8.RuneCountInString(s) > MaxCustomFieldTextLength {
+			return nil, fmt.Errorf("%s must be at most %d characters", f.Name, MaxCustomFieldTextLength)
+		}
+	case CustomFieldDate:
+		if _, err := time.Parse(DateLayout, s); err != nil {
+			return nil, fmt.Errorf("%s must be a date formatted as YYYY-MM-DD", f.Name)
+		}
+	case CustomFieldEnum:
+		if !slices.Contains(f.Options, s) {
+			return nil, fmt.Errorf("%s must be one of its options: %s", f.Name, strings.Join(f.Options, ", "))
+		}
+	}
+	return s, nil
+}
+
+// Parse converts s, such as a query parameter, to a value of the field.
+func (f CustomField) Parse(s string) (any, error) {
+	if f.Type == CustomFieldNumber {
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", f.Name)
+		}
+		return n, nil
+	}
+	return f.Check(s)
+}
+
+// CreateCustomFieldRequest is the payload for defining a custom field.
+type CreateCustomFieldRequest struct {
+	Name    string          `json:"name" example:"size" maxLength:"50" pattern:"^[a-z][a-z0-9_]*$" doc:"Lowercase letters, digits, and underscores, starting with a letter"`
+	Type    CustomFieldType `json:"type" example:"enum" enums:"text,number,date,enum"`
+	Options []string        `json:"options,omitempty" example:"[\"small\",\"medium\",\"large\"]" maxItems:"50" doc:"Values an enum field may hold; required for enum fields and not allowed for others"`
+}
+
+// UpdateCustomFieldRequest is the payload for updating a custom field.
+// Fields cannot be renamed or change type.
+type UpdateCustomFieldRequest struct {
+	Options *[]string `json:"options,omitempty" example:"[\"small\",\"medium\",\"large\",\"huge\"]" maxItems:"50" doc:"Values the enum field may hold; options TODOs hold cannot be removed"`
+}
+
+// CustomFieldListResponse wraps a page of custom fields.
+type CustomFieldListResponse struct {
+	CustomFields []CustomField `json:"custom_fields"`
+	Count        int           `json:"count" example:"2"`
+	Total        int           `json:"total" example:"2"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Digest summarizes open and recently completed TODOs, for the email
+// digest.
+type Digest struct {
+	GeneratedAt time.Time `json:"generated_at" example:"2026-02-16T00:00:00Z"`
+	// Since is when the previous digest was sent, or one schedule period
+	// before GeneratedAt if none was.
+	Since     time.Time `json:"since" example:"2026-02-15T00:00:00Z" doc:"When the previous digest was sent; completed TODOs are those completed since"`
+	Overdue   []Todo    `json:"overdue" doc:"Open TODOs due before today (UTC), the longest overdue first"`
+	DueSoon   []Todo    `json:"due_soon" doc:"Open TODOs due today or in the following days, soonest first"`
+	Completed []Todo    `json:"completed" doc:"TODOs completed since the previous digest, in the order they were completed"`
+	Subject   string    `json:"subject" example:"TODO digest: 2 overdue, 3 due soon, 5 completed"`
+	Text      string    `json:"text" doc:"Plain-text body of the email"`
+}
+
+// DigestSend records a digest that was emailed.
+type DigestSend struct {
+	SentAt    time.Time
+	To        []string
+	Overdue   int
+	DueSoon   int
+	Completed int
+}
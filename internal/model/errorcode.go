@@ -0,0 +1,78 @@
+package model
+
+import "net/http"
+
+// ErrorCode identifies the kind of problem an error response reports, so
+// clients can act on it without parsing the English message. Codes are
+// stable; new ones may be added.
+type ErrorCode string
+
+const (
+	CodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	CodeTodoNotFound         ErrorCode = "TODO_NOT_FOUND"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeConflict             ErrorCode = "CONFLICT"
+	CodeConflictStale        ErrorCode = "CONFLICT_STALE"
+	CodePreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+	CodeBodyTooLarge         ErrorCode = "BODY_TOO_LARGE"
+	CodeRateLimited          ErrorCode = "RATE_LIMITED"
+	CodeQuotaExceeded        ErrorCode = "QUOTA_EXCEEDED"
+	CodeBadRequest           ErrorCode = "BAD_REQUEST"
+	CodeReadOnly             ErrorCode = "READ_ONLY"
+	CodeUpstreamFailed       ErrorCode = "UPSTREAM_FAILED"
+	CodeInternal             ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorCodeInfo documents an error code.
+type ErrorCodeInfo struct {
+	Code        ErrorCode
+	Status      string
+	Description string
+}
+
+// ErrorCodes is the catalog of error codes, in the order the docs list
+// them.
+var ErrorCodes = []ErrorCodeInfo{
+	{CodeValidationFailed, "400, 422", "The request is malformed, or breaks a validation rule; errors lists each problem."},
+	{CodeTodoNotFound, "404, 422", "The TODO the request names does not exist: in the path with 404, in the body with 422."},
+	{CodeNotFound, "404", "Some other resource the request names does not exist."},
+	{CodeConflict, "409", "The change conflicts with the current state, such as a name already in use or a status change the server does not allow."},
+	{CodeConflictStale, "412", "The resource has changed since the ETag sent in If-Match; fetch it again and retry."},
+	{CodePreconditionRequired, "428", "The change requires an If-Match header."},
+	{CodeBodyTooLarge, "413", "The request body is larger than the server accepts."},
+	{CodeRateLimited, "429", "The client has made too many requests; retry after the Retry-After header's seconds."},
+	{CodeQuotaExceeded, "429", "The client has used up its daily or monthly request quota; the quota renews after the Retry-After header's seconds."},
+	{CodeBadRequest, "4xx", "The request cannot be served for another reason, such as an unsupported method or media type."},
+	{CodeReadOnly, "503", "The server is read-only for maintenance; retry after the Retry-After header's seconds."},
+	{CodeUpstreamFailed, "502", "A service the server relies on, such as a webhook or an import source, failed."},
+	{CodeInternal, "500", "The server failed unexpectedly."},
+}
+
+// StatusErrorCode returns the code of an error response with status that
+// has no more specific code.
+func StatusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodeConflictStale
+	case http.StatusPreconditionRequired:
+		return CodePreconditionRequired
+	case http.StatusRequestEntityTooLarge:
+		return CodeBodyTooLarge
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeReadOnly
+	case http.StatusBadGateway:
+		return CodeUpstreamFailed
+	}
+	if status >= 400 && status < 500 {
+		return CodeBadRequest
+	}
+	return CodeInternal
+}
@@ -0,0 +1,45 @@
+package model
+
+// GitHubConflicts decides which side wins when an issue's title or body and
+// its TODO's title or description have both changed since the last sync.
+type GitHubConflicts string
+
+const (
+	// GitHubConflictsGitHub overwrites the TODO with the issue.
+	GitHubConflictsGitHub GitHubConflicts = "github"
+	// GitHubConflictsLocal keeps the TODO as it is.
+	GitHubConflictsLocal GitHubConflicts = "local"
+	// GitHubConflictsNewer keeps whichever was changed last.
+	GitHubConflictsNewer GitHubConflicts = "newer"
+)
+
+// ValidGitHubConflicts contains the conflict rules the GitHub sync may use.
+var ValidGitHubConflicts = map[GitHubConflicts]bool{
+	GitHubConflictsGitHub: true,
+	GitHubConflictsLocal:  true,
+	GitHubConflictsNewer:  true,
+}
+
+// GitHubConflict describes an issue and TODO that had both been edited,
+// and which of them was kept.
+type GitHubConflict struct {
+	Issue  int    `json:"issue" example:"42" doc:"Issue number"`
+	TodoID int64  `json:"todo_id" example:"7"`
+	Kept   string `json:"kept" example:"github" enums:"github,local" doc:"Side whose title and text were kept"`
+}
+
+// GitHubRepoSync summarizes the sync of one repository.
+type GitHubRepoSync struct {
+	Repo      string           `json:"repo" example:"octo-org/octo-repo"`
+	Issues    int              `json:"issues" example:"12" doc:"Number of issues read that changed since the last sync"`
+	Created   int              `json:"created" example:"2" doc:"Number of TODOs created for new open issues"`
+	Updated   int              `json:"updated" example:"3" doc:"Number of TODOs changed to match their issue"`
+	Pushed    int              `json:"pushed" example:"1" doc:"Number of issues closed or reopened to match their TODO"`
+	Conflicts []GitHubConflict `json:"conflicts"`
+	Errors    []string         `json:"errors,omitempty" example:"GitHub responded 404 Not Found" doc:"Why the repository, or some of its issues, could not be synced"`
+}
+
+// GitHubSyncResponse summarizes a sync with GitHub.
+type GitHubSyncResponse struct {
+	Repos []GitHubRepoSync `json:"repos"`
+}
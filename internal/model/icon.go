@@ -0,0 +1,10 @@
+package model
+
+// Icons are the names of the icons TODOs, categories, and projects can be
+// shown with. Clients map each name to artwork of their own. The enum schema
+// tags on the request payloads must list the same names.
+var Icons = []string{
+	"bag", "book", "briefcase", "calendar", "car", "cart", "code", "flag",
+	"gift", "heart", "home", "inbox", "leaf", "lightbulb", "mail", "money",
+	"music", "phone", "star", "tag", "tool", "travel", "users",
+}
@@ -0,0 +1,21 @@
+package model
+
+// ImportIssue describes a task an import skipped, or imported with some of
+// its data lost.
+type ImportIssue struct {
+	Task    string `json:"task" example:"Renew passport"`
+	Project string `json:"project,omitempty" example:"Errands" doc:"Project the task came from, if not the inbox"`
+	Reason  string `json:"reason" example:"a TODO with this title already exists in the same project"`
+	TodoID  *int64 `json:"todo_id,omitempty" example:"42" doc:"Existing TODO the task conflicts with"`
+}
+
+// ImportResponse summarizes an import of TODOs from another service.
+type ImportResponse struct {
+	DryRun            bool          `json:"dry_run" doc:"True if nothing was written"`
+	Tasks             int           `json:"tasks" example:"400" doc:"Number of tasks read"`
+	Imported          int           `json:"imported" example:"396" doc:"Number of TODOs created"`
+	ProjectsCreated   []string      `json:"projects_created" example:"Errands"`
+	CategoriesCreated []string      `json:"categories_created" example:"errands"`
+	Conflicts         []ImportIssue `json:"conflicts" doc:"Tasks that were skipped"`
+	Warnings          []ImportIssue `json:"warnings" doc:"Tasks that were imported without some of their data"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// VacuumResponse reports the database's size before and after vacuuming.
+type VacuumResponse struct {
+	BytesBefore int64   `json:"bytes_before" example:"4096000"`
+	BytesAfter  int64   `json:"bytes_after" example:"3584000"`
+	DurationMs  float64 `json:"duration_ms" example:"120.5"`
+}
+
+// IntegrityReport is the result of checking the database for corruption.
+type IntegrityReport struct {
+	OK         bool      `json:"ok" example:"true"`
+	Problems   []string  `json:"problems" doc:"What the check found wrong, as SQLite words it, up to 100; empty if the database is sound"`
+	CheckedAt  time.Time `json:"checked_at" example:"2026-02-12T15:04:05Z"`
+	DurationMs float64   `json:"duration_ms" example:"35.2"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// MaintenanceRequest turns read-only mode on or off.
+type MaintenanceRequest struct {
+	ReadOnly          bool   `json:"read_only" doc:"Refuse changes until turned off again"`
+	Message           string `json:"message,omitempty" maxLength:"500" example:"Backing up; back in 10 minutes" doc:"Told to clients whose changes are refused"`
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty" minimum:"1" maximum:"86400" default:"300" example:"600" doc:"Sent in the Retry-After header of refused changes"`
+}
+
+// MaintenanceMode reports whether the API is read-only.
+type MaintenanceMode struct {
+	ReadOnly          bool       `json:"read_only"`
+	Message           string     `json:"message,omitempty" example:"Backing up; back in 10 minutes"`
+	RetryAfterSeconds int        `json:"retry_after_seconds,omitempty" example:"600"`
+	Since             *time.Time `json:"since,omitempty" example:"2026-02-12T02:00:00Z" doc:"When read-only mode was turned on"`
+}
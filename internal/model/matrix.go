@@ -0,0 +1,30 @@
+package model
+
+// Quadrant identifies a cell of the Eisenhower matrix.
+type Quadrant string
+
+const (
+	QuadrantDoFirst   Quadrant = "do_first"
+	QuadrantSchedule  Quadrant = "schedule"
+	QuadrantDelegate  Quadrant = "delegate"
+	QuadrantEliminate Quadrant = "eliminate"
+)
+
+// MatrixResponse arranges open TODOs into the four quadrants of an
+// Eisenhower matrix.
+type MatrixResponse struct {
+	Today      string           `json:"today" format:"date" example:"2026-02-16" doc:"Day the matrix was computed for, UTC"`
+	UrgentDays int              `json:"urgent_days" example:"2" doc:"TODOs due within this many days of today, or overdue, are urgent"`
+	Quadrants  []MatrixQuadrant `json:"quadrants" doc:"The quadrants in order: do_first, schedule, delegate, eliminate"`
+}
+
+// MatrixQuadrant holds the TODOs in one quadrant, with a label and color
+// clients can render it with.
+type MatrixQuadrant struct {
+	ID        Quadrant `json:"id" example:"do_first" enums:"do_first,schedule,delegate,eliminate"`
+	Label     string   `json:"label" example:"Do first"`
+	Color     string   `json:"color" example:"#d32f2f" doc:"Suggested color for the quadrant, as a hex RGB value"`
+	Urgent    bool     `json:"urgent" example:"true"`
+	Important bool     `json:"important" example:"true"`
+	Todos     []Todo   `json:"todos" doc:"TODOs in the quadrant, soonest due first, then by priority"`
+}
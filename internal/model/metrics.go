@@ -0,0 +1,45 @@
+package model
+
+// MetricsResponse reports the service's runtime statistics.
+type MetricsResponse struct {
+	Queries QueryStats `json:"queries"`
+	Cache   CacheStats `json:"cache"`
+	// Logs is omitted unless logs are shipped to a network backend.
+	Logs *LogShipStats `json:"log_shipping,omitempty"`
+	// Janitor is omitted unless a retention policy is enforced.
+	Janitor *JanitorStats `json:"janitor,omitempty"`
+}
+
+// QueryStats aggregates the SQL statements the repository has run since the
+// service started. Statements include BEGIN, COMMIT, and ROLLBACK, so time
+// spent waiting for SQLite's write lock shows up here.
+type QueryStats struct {
+	Count           int64   `json:"count" example:"5230"`
+	Errors          int64   `json:"errors" example:"2"`
+	Slow            int64   `json:"slow" example:"14" doc:"Statements that took longer than the slow-query threshold"`
+	SlowThresholdMS float64 `json:"slow_threshold_ms" example:"100" doc:"0 if slow queries are not logged"`
+	TotalMS         float64 `json:"total_ms" example:"812.4"`
+	MaxMS           float64 `json:"max_ms" example:"310.2"`
+	ConnWaits       int64   `json:"conn_waits" example:"37" doc:"Times a statement had to wait for the database connection, which is shared by all requests"`
+	ConnWaitMS      float64 `json:"conn_wait_ms" example:"95.1"`
+}
+
+// CacheStats reports the repository's cache of TODO reads. All fields are 0
+// if the cache is disabled.
+type CacheStats struct {
+	Size       int     `json:"size" example:"212" doc:"Results currently cached"`
+	Capacity   int     `json:"capacity" example:"1000"`
+	TTLSeconds float64 `json:"ttl_seconds" example:"30"`
+	Hits       int64   `json:"hits" example:"8410"`
+	Misses     int64   `json:"misses" example:"1302" doc:"Reads that went to the database, including those of results invalidated by a write"`
+	Evictions  int64   `json:"evictions" example:"40" doc:"Results dropped to make room for newer ones"`
+}
+
+// LogShipStats reports log records shipped to a network backend.
+type LogShipStats struct {
+	Sent      int64  `json:"sent" example:"10230"`
+	Dropped   int64  `json:"dropped" example:"0" doc:"Records logged while the buffer was full, or in batches the backend did not accept"`
+	Buffered  int    `json:"buffered" example:"12" doc:"Records waiting to be sent"`
+	Failures  int64  `json:"failures" example:"1" doc:"Failed attempts to send a batch, including ones retried successfully"`
+	LastError string `json:"last_error,omitempty" example:"http://loki:3100/loki/api/v1/push responded 503 Service Unavailable"`
+}
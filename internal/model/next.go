@@ -0,0 +1,45 @@
+package model
+
+// NextRank says how soon the next TODO to work on is due, the first
+// criterion it is chosen by. Ranks are listed from highest to lowest.
+type NextRank string
+
+const (
+	// NextRankOverdue is a TODO due before today.
+	NextRankOverdue NextRank = "overdue"
+	// NextRankDueToday is a TODO due today.
+	NextRankDueToday NextRank = "due_today"
+	// NextRankDueLater is a TODO due after today.
+	NextRankDueLater NextRank = "due_later"
+	// NextRankUndated is a TODO without a due date.
+	NextRankUndated NextRank = "undated"
+)
+
+// RankNext returns the rank of an open TODO as of today, a date in
+// DateLayout.
+func RankNext(t Todo, today string) NextRank {
+	switch {
+	case t.DueDate == nil:
+		return NextRankUndated
+	case *t.DueDate < today:
+		return NextRankOverdue
+	case *t.DueDate == today:
+		return NextRankDueToday
+	}
+	return NextRankDueLater
+}
+
+// NextTodoResponse is the TODO to work on next and why it was chosen.
+type NextTodoResponse struct {
+	Todo       Todo            `json:"todo"`
+	Rank       NextRank        `json:"rank" enum:"overdue,due_today,due_later,undated" example:"overdue" doc:"overdue: due before today; due_today: due today; due_later: due after today; undated: no due date"`
+	Factors    []RankingFactor `json:"factors" doc:"What put the TODO ahead of the others, most significant first"`
+	Candidates int             `json:"candidates" example:"12" doc:"Number of actionable TODOs considered"`
+}
+
+// RankingFactor is one criterion that decided which TODO comes next.
+type RankingFactor struct {
+	Factor      string `json:"factor" enum:"due_date,priority,status,position" example:"due_date"`
+	Value       string `json:"value" example:"2026-02-10" doc:"The TODO's value for the factor"`
+	Explanation string `json:"explanation" example:"Overdue by 2 days; TODOs due soonest come first, so the longest overdue leads."`
+}
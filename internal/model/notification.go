@@ -0,0 +1,114 @@
+package model
+
+import "time"
+
+// NotificationChannel is the chat service a notification route posts to.
+type NotificationChannel string
+
+const (
+	// ChannelSlack posts to a Slack incoming webhook.
+	ChannelSlack NotificationChannel = "slack"
+	// ChannelDiscord posts to a Discord channel webhook.
+	ChannelDiscord NotificationChannel = "discord"
+)
+
+// ValidNotificationChannels contains the channels a route may post to.
+var ValidNotificationChannels = map[NotificationChannel]bool{
+	ChannelSlack:   true,
+	ChannelDiscord: true,
+}
+
+// NotificationEvent is a kind of TODO event a route can be notified of.
+type NotificationEvent string
+
+const (
+	// EventCreated is a TODO being created.
+	EventCreated NotificationEvent = "created"
+	// EventUpdated is a TODO being changed other than by completing or
+	// assigning it.
+	EventUpdated NotificationEvent = "updated"
+	// EventCompleted is a TODO being marked done.
+	EventCompleted NotificationEvent = "completed"
+	// EventAssigned is a TODO being assigned to someone, other than by
+	// completing it.
+	EventAssigned NotificationEvent = "assigned"
+	// EventWoke is a snoozed TODO waking up, when its snooze runs out or it
+	// is unsnoozed.
+	EventWoke NotificationEvent = "woke"
+	// EventDeleted is a TODO being deleted.
+	EventDeleted NotificationEvent = "deleted"
+	// EventOverdue is the due date of an open TODO passing (UTC).
+	EventOverdue NotificationEvent = "overdue"
+	// EventCorruption is a scheduled check finding the database corrupt.
+	// Its messages have no TODO.
+	EventCorruption NotificationEvent = "corruption"
+)
+
+// NotificationEvents lists every event a route can be notified of.
+var NotificationEvents = []NotificationEvent{EventCreated, EventUpdated, EventCompleted, EventAssigned, EventWoke, EventDeleted, EventOverdue, EventCorruption}
+
+// DefaultNotificationTemplates are the messages of routes without a
+// template of their own.
+var DefaultNotificationTemplates = map[NotificationEvent]string{
+	EventCreated:    `New TODO #{{.Todo.ID}}: {{.Todo.Title}}`,
+	EventUpdated:    `Updated TODO #{{.Todo.ID}}: {{.Todo.Title}}`,
+	EventCompleted:  `Completed TODO #{{.Todo.ID}}: {{.Todo.Title}}`,
+	EventAssigned:   `{{.Actor}} assigned TODO #{{.Todo.ID}} to {{.Changes.assignee.New}}: {{.Todo.Title}}`,
+	EventWoke:       `TODO #{{.Todo.ID}} woke up: {{.Todo.Title}}`,
+	EventDeleted:    `Deleted TODO #{{.Todo.ID}}: {{.Todo.Title}}`,
+	EventOverdue:    `Overdue since {{.Todo.DueDate}}: TODO #{{.Todo.ID}}: {{.Todo.Title}}`,
+	EventCorruption: `Database integrity check failed: {{range $i, $p := .Problems}}{{if $i}}; {{end}}{{$p}}{{end}}`,
+}
+
+// NotificationMessage is what a route's template is executed with.
+type NotificationMessage struct {
+	Event NotificationEvent
+	// Todo is the TODO as it is now, or as it was when it was deleted.
+	Todo Todo
+	// Actor made the change; it is empty for overdue events.
+	Actor string
+	// Changes holds the fields the change set, as in the audit log.
+	Changes map[string]FieldChange
+	// Problems lists what an integrity check found wrong with the
+	// database; it is empty for TODO events.
+	Problems []string
+}
+
+// NotificationRoute posts messages about some kinds of TODO events to a
+// Slack or Discord webhook.
+type NotificationRoute struct {
+	ID         int64               `json:"id" example:"1"`
+	Name       string              `json:"name" example:"Done in #tasks"`
+	Channel    NotificationChannel `json:"channel" example:"slack" enums:"slack,discord"`
+	WebhookURL string              `json:"webhook_url" example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	Events     []NotificationEvent `json:"events" example:"completed" enums:"created,updated,completed,assigned,woke,deleted,overdue,corruption"`
+	Template   string              `json:"template,omitempty" example:"{{.Actor}} finished {{.Todo.Title}}" doc:"Go text/template for the message, or empty for each event's default"`
+	LastSentAt *time.Time          `json:"last_sent_at" example:"2026-02-12T15:04:07Z" doc:"When a message was last posted, or null if none was"`
+	LastError  string              `json:"last_error,omitempty" example:"https://hooks.slack.com responded 404 Not Found" doc:"Why the last post failed, if it did"`
+	CreatedAt  time.Time           `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt  time.Time           `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// NotificationRouteRequest is the payload for creating a notification route
+// or replacing one.
+type NotificationRouteRequest struct {
+	Name       string              `json:"name" example:"Done in #tasks" maxLength:"100"`
+	Channel    NotificationChannel `json:"channel" example:"slack" enums:"slack,discord"`
+	WebhookURL string              `json:"webhook_url" example:"https://hooks.slack.com/services/T000/B000/XXXX" maxLength:"2000"`
+	Events     []NotificationEvent `json:"events" example:"completed" enums:"created,updated,completed,assigned,woke,deleted,overdue,corruption" minItems:"1" doc:"Events to post a message for"`
+	Template   string              `json:"template,omitempty" example:"{{.Actor}} finished {{.Todo.Title}}" maxLength:"2000" doc:"Go text/template for the message, executed with .Event, .Todo, .Actor, .Changes, and for corruption .Problems; empty for each event's default"`
+}
+
+// NotificationRouteListResponse wraps a page of notification routes.
+type NotificationRouteListResponse struct {
+	Routes []NotificationRoute `json:"routes"`
+	Count  int                 `json:"count" example:"1"`
+	Total  int                 `json:"total" example:"1"`
+}
+
+// Notification route limits. The maxLength schema tags on
+// NotificationRouteRequest must match them.
+const (
+	MaxNotificationRouteNameLength = 100
+	MaxNotificationTemplateLength  = 2000
+)
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Project groups related TODO items.
+type Project struct {
+	ID          int64           `json:"id" example:"1"`
+	Name        string          `json:"name" example:"Home renovation"`
+	Description string          `json:"description" example:"Kitchen and bathroom"`
+	Color       string          `json:"color" example:"#2196f3" doc:"Display color as #rrggbb, or empty for none"`
+	Icon        string          `json:"icon" example:"home" doc:"Name of the icon to show the project with, or empty for none"`
+	Progress    ProjectProgress `json:"progress"`
+	CreatedAt   time.Time       `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt   time.Time       `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// ProjectProgress rolls up the TODOs in a project, archived ones included.
+type ProjectProgress struct {
+	Total           int `json:"total" example:"4"`
+	Pending         int `json:"pending" example:"1"`
+	InProgress      int `json:"in_progress" example:"1"`
+	Done            int `json:"done" example:"2"`
+	ProgressPercent int `json:"progress_percent" example:"62" minimum:"0" maximum:"100" doc:"Average progress of the project's TODOs, counting done TODOs as 100"`
+}
+
+// CreateProjectRequest is the payload for creating a new project.
+type CreateProjectRequest struct {
+	Name        string `json:"name" example:"Home renovation"`
+	Description string `json:"description,omitempty" example:"Kitchen and bathroom"`
+	Color       string `json:"color,omitempty" example:"#2196f3" pattern:"^#[0-9a-fA-F]{6}$" doc:"Display color as #rrggbb"`
+	Icon        string `json:"icon,omitempty" example:"home" enum:"bag,book,briefcase,calendar,car,cart,code,flag,gift,heart,home,inbox,leaf,lightbulb,mail,money,music,phone,star,tag,tool,travel,users" doc:"Name of the icon to show the project with"`
+}
+
+// UpdateProjectRequest is the payload for updating a project. All fields are optional.
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty" example:"Home renovation"`
+	Description *string `json:"description,omitempty" example:"Kitchen, bathroom, and garden"`
+	Color       *string `json:"color,omitempty" example:"#2196f3" doc:"Display color as #rrggbb; empty removes it"`
+	Icon        *string `json:"icon,omitempty" example:"home" doc:"Name of the icon to show the project with; empty removes it"`
+}
+
+// ProjectListResponse wraps a page of projects.
+type ProjectListResponse struct {
+	Projects []Project `json:"projects"`
+	Count    int       `json:"count" example:"3"`
+	Total    int       `json:"total" example:"3"`
+}
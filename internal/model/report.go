@@ -0,0 +1,17 @@
+package model
+
+// BurndownReport is the estimated work left at the end of each day in a
+// date range.
+type BurndownReport struct {
+	ProjectID *int64          `json:"project_id" example:"1" doc:"Project the report covers, or null for every TODO"`
+	From      string          `json:"from" format:"date" example:"2026-02-02"`
+	To        string          `json:"to" format:"date" example:"2026-02-15"`
+	Points    []BurndownPoint `json:"points" doc:"One point per day from from to to, in order"`
+}
+
+// BurndownPoint is the state of the work at the end of a single day (UTC).
+type BurndownPoint struct {
+	Date             string `json:"date" format:"date" example:"2026-02-02"`
+	RemainingMinutes int    `json:"remaining_minutes" example:"240" doc:"Sum of the estimates of open TODOs"`
+	OpenTodos        int    `json:"open_todos" example:"6" doc:"TODOs that were neither done, archived, nor deleted"`
+}
@@ -0,0 +1,283 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Rule requires a TODO matching all of its When conditions to also
+// satisfy Require, such as an estimate for every TODO in the work category.
+type Rule struct {
+	When    []Condition
+	Require Condition
+}
+
+// A Condition compares one field of a TODO with a value. With no operator,
+// it holds if the field is set: not empty, not 0 for numbers, and not none
+// for priorities.
+type Condition struct {
+	Field string
+	Op    string // "", "!" for unset, "=", "!=", "<", "<=", ">", or ">="
+	Value string
+}
+
+// Rules are the cross-field rules TODOs must follow when they are created
+// or updated.
+type Rules []Rule
+
+// RuleViolation reports a rule a TODO breaks.
+type RuleViolation struct {
+	Rule    string `json:"rule" example:"category=work -> estimate_minutes"`
+	Field   string `json:"field" example:"estimate_minutes" doc:"Field the rule requires something of"`
+	Message string `json:"message" example:"estimate_minutes is required when category=work"`
+}
+
+// ruleKind says how a field is compared by rules.
+type ruleKind int
+
+const (
+	ruleText     ruleKind = iota // compared with = and != only
+	ruleNumber                   // whole numbers; unset is 0
+	ruleDate                     // dates formatted as DateLayout
+	rulePriority                 // priorities, ordered as Priorities; unset is none
+)
+
+// ruleFields lists the TODO fields rules may refer to, and how each is
+// compared.
+var ruleFields = map[string]ruleKind{
+	"title":            ruleText,
+	"description":      ruleText,
+	"status":           ruleText,
+	"category":         ruleText,
+	"project_id":       ruleNumber,
+	"progress_percent": ruleNumber,
+	"priority":         rulePriority,
+	"due_date":         ruleDate,
+	"estimate_minutes": ruleNumber,
+	"scheduled_for":    ruleDate,
+}
+
+// ruleOps lists the comparison operators, longest first so that parsing
+// finds "<=" before "<".
+var ruleOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// Check returns the violations of rs by t, in rule order.
+func (rs Rules) Check(t Todo) []RuleViolation {
+	var violations []RuleViolation
+	for _, r := range rs {
+		if !slices.ContainsFunc(r.When, func(c Condition) bool { return !c.Holds(t) }) && !r.Require.Holds(t) {
+			violations = append(violations, RuleViolation{Rule: r.String(), Field: r.Require.Field, Message: r.message()})
+		}
+	}
+	return violations
+}
+
+// Holds reports whether t satisfies c.
+func (c Condition) Holds(t Todo) bool {
+	v := ruleValue(t, c.Field)
+	kind := ruleFields[c.Field]
+	set := v != "" && !(kind == ruleNumber && v == "0") && !(kind == rulePriority && v == string(PriorityNone))
+	switch c.Op {
+	case "":
+		return set
+	case "!":
+		return !set
+	case "=":
+		return v == c.Value
+	case "!=":
+		return v != c.Value
+	}
+
+	var cmp int
+	switch kind {
+	case ruleNumber:
+		a, _ := strconv.ParseInt(v, 10, 64)
+		b, _ := strconv.ParseInt(c.Value, 10, 64)
+		cmp = int(min(max(a-b, -1), 1))
+	case rulePriority:
+		cmp = slices.Index(Priorities, Priority(v)) - slices.Index(Priorities, Priority(c.Value))
+	default:
+		if v == "" {
+			// An unset date is neither before nor after any date.
+			return false
+		}
+		cmp = strings.Compare(v, c.Value)
+	}
+	switch c.Op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
+
+func ruleValue(t Todo, field string) string {
+	switch field {
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	case "status":
+		return string(t.Status)
+	case "category":
+		return string(t.Category)
+	case "project_id":
+		if t.ProjectID == nil {
+			return "0"
+		}
+		return strconv.FormatInt(*t.ProjectID, 10)
+	case "progress_percent":
+		return strconv.Itoa(t.ProgressPercent)
+	case "priority":
+		return string(t.Priority)
+	case "due_date":
+		if t.DueDate == nil {
+			return ""
+		}
+		return *t.DueDate
+	case "estimate_minutes":
+		return strconv.Itoa(t.EstimateMinutes)
+	case "scheduled_for":
+		if t.ScheduledFor == nil {
+			return ""
+		}
+		return *t.ScheduledFor
+	}
+	return ""
+}
+
+// String formats c in the form ParseRules accepts.
+func (c Condition) String() string {
+	if c.Op == "!" {
+		return "!" + c.Field
+	}
+	return c.Field + c.Op + c.Value
+}
+
+// String formats r in the form ParseRules accepts.
+func (r Rule) String() string {
+	when := make([]string, len(r.When))
+	for i, c := range r.When {
+		when[i] = c.String()
+	}
+	return strings.Join(when, " & ") + " -> " + r.Require.String()
+}
+
+// String formats rs as a semicolon-separated list, the form ParseRules
+// accepts.
+func (rs Rules) String() string {
+	rules := make([]string, len(rs))
+	for i, r := range rs {
+		rules[i] = r.String()
+	}
+	return strings.Join(rules, "; ")
+}
+
+func (r Rule) message() string {
+	when := make([]string, len(r.When))
+	for i, c := range r.When {
+		when[i] = c.String()
+	}
+	req := r.Require
+	var need string
+	switch req.Op {
+	case "":
+		need = req.Field + " is required"
+	case "!":
+		need = req.Field + " must not be set"
+	case "=":
+		need = fmt.Sprintf("%s must be %s", req.Field, req.Value)
+	case "!=":
+		need = fmt.Sprintf("%s must not be %s", req.Field, req.Value)
+	default:
+		need = fmt.Sprintf("%s must be %s %s", req.Field, req.Op, req.Value)
+	}
+	return need + " when " + strings.Join(when, " and ")
+}
+
+// ParseRules parses a semicolon-separated list of rules, each a list of
+// conditions joined by & followed by -> and the condition they require,
+// such as "category=work -> due_date; priority>=high -> estimate_minutes".
+// A condition is a field, which holds if the field is set, !field, which
+// holds if it is not, or a field, an operator (=, !=, <, <=, >, or >=), and
+// a value. Fields are title, description, status, category, project_id,
+// progress_percent, priority, due_date, estimate_minutes, and
+// scheduled_for; an unset project_id or estimate_minutes is 0, and
+// priorities are ordered from none to urgent. An empty string has no rules.
+func ParseRules(s string) (Rules, error) {
+	var rs Rules
+	for text := range strings.SplitSeq(s, ";") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		when, require, ok := strings.Cut(text, "->")
+		if !ok {
+			return nil, fmt.Errorf("rule %q: want conditions -> requirement", text)
+		}
+		var r Rule
+		for part := range strings.SplitSeq(when, "&") {
+			c, err := parseCondition(part)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", text, err)
+			}
+			r.When = append(r.When, c)
+		}
+		c, err := parseCondition(require)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", text, err)
+		}
+		r.Require = c
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func parseCondition(s string) (Condition, error) {
+	s = strings.TrimSpace(s)
+	var c Condition
+	if field, ok := strings.CutPrefix(s, "!"); ok && !strings.ContainsAny(field, "=<>") {
+		c = Condition{Field: strings.TrimSpace(field), Op: "!"}
+	} else {
+		c.Field = s
+		for _, op := range ruleOps {
+			if field, value, ok := strings.Cut(s, op); ok {
+				c = Condition{Field: strings.TrimSpace(field), Op: op, Value: strings.TrimSpace(value)}
+				break
+			}
+		}
+	}
+
+	kind, ok := ruleFields[c.Field]
+	switch {
+	case c.Field == "":
+		return Condition{}, fmt.Errorf("empty condition")
+	case !ok:
+		return Condition{}, fmt.Errorf("unknown field %q", c.Field)
+	case c.Op == "" || c.Op == "!":
+	case kind == ruleNumber:
+		if _, err := strconv.ParseInt(c.Value, 10, 64); err != nil {
+			return Condition{}, fmt.Errorf("%s: %s is compared with a whole number", c, c.Field)
+		}
+	case kind == ruleDate:
+		if _, err := time.Parse(DateLayout, c.Value); err != nil {
+			return Condition{}, fmt.Errorf("%s: %s is compared with a date formatted as YYYY-MM-DD", c, c.Field)
+		}
+	case kind == rulePriority:
+		if !ValidPriorities[Priority(c.Value)] {
+			return Condition{}, fmt.Errorf("%s: unknown priority %q", c, c.Value)
+		}
+	case c.Field == "status" && !ValidStatuses[Status(c.Value)]:
+		return Condition{}, fmt.Errorf("%s: unknown status %q", c, c.Value)
+	case c.Op != "=" && c.Op != "!=":
+		return Condition{}, fmt.Errorf("%s: %s can only be compared with = and !=", c, c.Field)
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+// Share link limits. The schema tags on CreateShareRequest must match them.
+const (
+	DefaultShareHours = 7 * 24
+	MaxShareHours     = 365 * 24
+)
+
+// Share is a read-only public link to a TODO.
+type Share struct {
+	ID     int64 `json:"id" example:"1"`
+	TodoID int64 `json:"todo_id" example:"1"`
+	// Token is only known when the share is created; the server stores a
+	// hash of it.
+	Token          string     `json:"token,omitempty" example:"q3Xv1bWcN2kR8sT0yZ4aP7dE6fG9hJ5m" doc:"Secret part of the link, returned only when the share is created"`
+	URL            string     `json:"url,omitempty" example:"/share/q3Xv1bWcN2kR8sT0yZ4aP7dE6fG9hJ5m" doc:"Path of the link on this server, returned only when the share is created"`
+	CreatedAt      time.Time  `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	ExpiresAt      time.Time  `json:"expires_at" example:"2026-02-19T15:04:05Z"`
+	RevokedAt      *time.Time `json:"revoked_at" example:"2026-02-13T09:00:00Z" doc:"When the share was revoked, or null if it was not"`
+	Accesses       int        `json:"accesses" example:"3" doc:"Number of times the link was opened"`
+	LastAccessedAt *time.Time `json:"last_accessed_at" example:"2026-02-12T16:30:00Z" doc:"When the link was last opened, or null if it never was"`
+}
+
+// Active reports whether the link works at now.
+func (s Share) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// CreateShareRequest is the payload for sharing a TODO.
+type CreateShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty" minimum:"1" maximum:"8760" example:"168" doc:"How long the link works for; defaults to a week"`
+}
+
+// ShareListResponse lists the shares of a TODO.
+type ShareListResponse struct {
+	Shares []Share `json:"shares"`
+	Count  int     `json:"count" example:"1"`
+}
+
+// ShareAccess records a share link being opened.
+type ShareAccess struct {
+	ID         int64     `json:"id" example:"1"`
+	ShareID    int64     `json:"share_id" example:"1"`
+	AccessedAt time.Time `json:"accessed_at" example:"2026-02-12T16:30:00Z"`
+	RemoteAddr string    `json:"remote_addr" example:"203.0.113.7"`
+	UserAgent  string    `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+}
+
+// ShareAccessListResponse wraps the latest accesses of a share.
+type ShareAccessListResponse struct {
+	Accesses []ShareAccess `json:"accesses"`
+	Count    int           `json:"count" example:"1"`
+	Total    int           `json:"total" example:"3"`
+}
+
+// SharedTodo is the view of a TODO a share link shows: what it is and how
+// far along, without how it is organized on the server.
+type SharedTodo struct {
+	Title           string     `json:"title" example:"Buy groceries"`
+	Description     string     `json:"description" example:"Milk, eggs, bread, butter"`
+	Status          Status     `json:"status" example:"in_progress" enum:"pending,in_progress,done"`
+	ProgressPercent int        `json:"progress_percent" example:"50"`
+	ScheduledFor    *string    `json:"scheduled_for" format:"date" example:"2026-02-16"`
+	CompletedAt     *time.Time `json:"completed_at" example:"2026-02-12T16:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+	ExpiresAt       time.Time  `json:"expires_at" example:"2026-02-19T15:04:05Z" doc:"When the link stops working"`
+}
+
+// NewSharedTodo returns the view of t shown by a link that expires at
+// expiresAt.
+func NewSharedTodo(t Todo, expiresAt time.Time) SharedTodo {
+	return SharedTodo{
+		Title:           t.Title,
+		Description:     t.Description,
+		Status:          t.Status,
+		ProgressPercent: t.ProgressPercent,
+		ScheduledFor:    t.ScheduledFor,
+		CompletedAt:     t.CompletedAt,
+		UpdatedAt:       t.UpdatedAt,
+		ExpiresAt:       expiresAt,
+	}
+}
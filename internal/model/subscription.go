@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// SubscriptionReport is the report a subscription delivers.
+type SubscriptionReport string
+
+const (
+	// SubscriptionTodos delivers the printable TODO report, for weekly
+	// reviews, as HTML or PDF.
+	SubscriptionTodos SubscriptionReport = "todos"
+	// SubscriptionBurndown delivers the burndown report for the two weeks
+	// ending the day before delivery, as JSON.
+	SubscriptionBurndown SubscriptionReport = "burndown"
+)
+
+// SubscriptionFormats lists the formats each report can be delivered in.
+// The first is the default.
+var SubscriptionFormats = map[SubscriptionReport][]string{
+	SubscriptionTodos:    {"html", "pdf"},
+	SubscriptionBurndown: {"json"},
+}
+
+// SubscriptionSchedule is how often a subscription is delivered.
+type SubscriptionSchedule string
+
+const (
+	// ScheduleDaily delivers at midnight UTC.
+	ScheduleDaily SubscriptionSchedule = "daily"
+	// ScheduleWeekly delivers at midnight UTC on Mondays.
+	ScheduleWeekly SubscriptionSchedule = "weekly"
+)
+
+// ValidSubscriptionSchedules contains the schedules a subscription may have.
+var ValidSubscriptionSchedules = map[SubscriptionSchedule]bool{
+	ScheduleDaily:  true,
+	ScheduleWeekly: true,
+}
+
+// Next returns the first delivery time of s after t.
+func (s SubscriptionSchedule) Next(t time.Time) time.Time {
+	day := t.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if s == ScheduleWeekly {
+		day = day.AddDate(0, 0, (8-int(day.Weekday()))%7)
+	}
+	return day
+}
+
+// ReportSubscription delivers a report to a webhook on a schedule.
+type ReportSubscription struct {
+	ID         int64                `json:"id" example:"1"`
+	Name       string               `json:"name" example:"Weekly review"`
+	Report     SubscriptionReport   `json:"report" example:"todos" enums:"todos,burndown"`
+	Format     string               `json:"format" example:"pdf" enums:"html,pdf,json"`
+	Group      string               `json:"group,omitempty" example:"project" enums:"project,category" doc:"How a todos report groups TODOs"`
+	Schedule   SubscriptionSchedule `json:"schedule" example:"weekly" enums:"daily,weekly"`
+	WebhookURL string               `json:"webhook_url" example:"https://hooks.example.com/reports"`
+	NextRunAt  time.Time            `json:"next_run_at" example:"2026-02-16T00:00:00Z"`
+	LastSentAt *time.Time           `json:"last_sent_at" example:"2026-02-09T00:00:02Z" doc:"When the report was last delivered, or null if it never was"`
+	LastError  string               `json:"last_error,omitempty" example:"https://hooks.example.com/reports responded 503 Service Unavailable" doc:"Why the last delivery attempt failed, if it did"`
+	CreatedAt  time.Time            `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt  time.Time            `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// ReportSubscriptionRequest is the payload for creating a report
+// subscription or replacing one.
+type ReportSubscriptionRequest struct {
+	Name       string               `json:"name" example:"Weekly review" maxLength:"100"`
+	Report     SubscriptionReport   `json:"report" example:"todos" enums:"todos,burndown"`
+	Format     string               `json:"format,omitempty" example:"pdf" enums:"html,pdf,json" doc:"Document format: html (default) or pdf for todos, json for burndown"`
+	Group      string               `json:"group,omitempty" example:"project" enums:"project,category" doc:"How a todos report groups TODOs (default project)"`
+	Schedule   SubscriptionSchedule `json:"schedule" example:"weekly" enums:"daily,weekly"`
+	WebhookURL string               `json:"webhook_url" example:"https://hooks.example.com/reports" maxLength:"2000"`
+}
+
+// ReportSubscriptionListResponse wraps a page of report subscriptions.
+type ReportSubscriptionListResponse struct {
+	Subscriptions []ReportSubscription `json:"subscriptions"`
+	Count         int                  `json:"count" example:"1"`
+	Total         int                  `json:"total" example:"1"`
+}
+
+// Report subscription limits. The maxLength schema tags on
+// ReportSubscriptionRequest must match them.
+const (
+	MaxSubscriptionNameLength = 100
+	MaxWebhookURLLength       = 2000
+)
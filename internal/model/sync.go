@@ -0,0 +1,102 @@
+package model
+
+import "time"
+
+// SyncOp is the kind of change a client uploads to sync.
+type SyncOp string
+
+const (
+	SyncCreate SyncOp = "create"
+	SyncUpdate SyncOp = "update"
+	SyncDelete SyncOp = "delete"
+)
+
+// SyncStrategy decides how a client's change to a TODO that has also
+// changed on the server since the client last saw it is resolved.
+type SyncStrategy string
+
+const (
+	// SyncLastWriterWins keeps whichever side changed the TODO last, as a
+	// whole.
+	SyncLastWriterWins SyncStrategy = "last_writer_wins"
+	// SyncMerge keeps both sides' changes to different fields, and the
+	// last writer's to fields both changed.
+	SyncMerge SyncStrategy = "merge"
+)
+
+// ValidSyncStrategies contains the strategies a sync may use.
+var ValidSyncStrategies = map[SyncStrategy]bool{
+	SyncLastWriterWins: true,
+	SyncMerge:          true,
+}
+
+// SyncOutcome is what became of an uploaded change.
+type SyncOutcome string
+
+const (
+	// OutcomeApplied means the change was made as uploaded.
+	OutcomeApplied SyncOutcome = "applied"
+	// OutcomeMerged means the change was made alongside the server's own
+	// changes since the base version, less any fields the server won.
+	OutcomeMerged SyncOutcome = "merged"
+	// OutcomeConflict means the server's version won and the change was
+	// dropped.
+	OutcomeConflict SyncOutcome = "conflict"
+	// OutcomeRejected means the change is invalid or not allowed, such as a
+	// status change the server does not allow.
+	OutcomeRejected SyncOutcome = "rejected"
+	// OutcomeDuplicate means an earlier sync already applied the change,
+	// by its ref, so it was not applied again.
+	OutcomeDuplicate SyncOutcome = "duplicate"
+)
+
+// MaxSyncChanges is the most changes one sync may upload.
+const MaxSyncChanges = 500
+
+// MaxSyncRefLength is the longest client reference of a change.
+const MaxSyncRefLength = 100
+
+// SyncChange is a change a client made while offline.
+type SyncChange struct {
+	Op          SyncOp             `json:"op" enum:"create,update,delete" example:"update"`
+	Ref         string             `json:"ref,omitempty" maxLength:"100" example:"c5d1e0a2-7" doc:"The client's own reference for the change, unique among its changes and echoed in the result. A change whose ref the same actor has already synced is not applied again, so an upload can be retried safely"`
+	TodoID      int64              `json:"todo_id,omitempty" example:"1" doc:"TODO to update or delete"`
+	BaseVersion int64              `json:"base_version,omitempty" example:"3" doc:"Version of the TODO the change was made to; 0 applies it whatever the version"`
+	ChangedAt   time.Time          `json:"changed_at" example:"2026-02-12T15:04:05Z" doc:"When the change was made on the client, to compare with when the server's version was"`
+	Create      *CreateTodoRequest `json:"create,omitempty" doc:"The TODO to create"`
+	Update      *UpdateTodoRequest `json:"update,omitempty" doc:"The fields to change, and only those"`
+}
+
+// SyncRequest is the payload for syncing a client's changes.
+type SyncRequest struct {
+	Since    int64        `json:"since,omitempty" minimum:"0" example:"42" doc:"Cursor returned by the previous sync or change feed read; 0 for the first sync"`
+	Strategy SyncStrategy `json:"strategy,omitempty" enum:"last_writer_wins,merge" example:"merge" doc:"How to resolve conflicts; defaults to last_writer_wins"`
+	Changes  []SyncChange `json:"changes" maxItems:"500" doc:"The client's changes, applied in order"`
+}
+
+// SyncConflict reports that a TODO changed on both sides since the
+// client's base version.
+type SyncConflict struct {
+	ServerVersion int64    `json:"server_version" example:"5" doc:"Version of the TODO on the server when the change was uploaded"`
+	Fields        []string `json:"fields" example:"[\"title\"]" doc:"Fields both sides changed; with last_writer_wins, or if the server cannot tell, every field the client changed"`
+	Winner        string   `json:"winner" enum:"client,server" example:"server" doc:"Side whose values were kept for those fields"`
+}
+
+// SyncResult is what became of one uploaded change.
+type SyncResult struct {
+	Ref      string        `json:"ref,omitempty" example:"c5d1e0a2-7"`
+	TodoID   int64         `json:"todo_id,omitempty" example:"1" doc:"TODO the change applied to, including one it created"`
+	Outcome  SyncOutcome   `json:"outcome" enum:"applied,merged,conflict,rejected,duplicate" example:"merged"`
+	Conflict *SyncConflict `json:"conflict,omitempty"`
+	Error    string        `json:"error,omitempty" example:"status change from done to pending is not allowed" doc:"Why the change was rejected"`
+	Todo     *Todo         `json:"todo,omitempty" doc:"The TODO as it is now, unless the change was rejected or the TODO is deleted"`
+}
+
+// SyncResponse reports what became of each uploaded change and returns
+// the server's changes since the client's cursor.
+type SyncResponse struct {
+	Results []SyncResult `json:"results"`
+	Changes []Change     `json:"changes" doc:"Changes since the request's since, including those just applied, as the change feed returns them"`
+	Cursor  int64        `json:"cursor" example:"57" doc:"Pass as since to the next sync or change feed read"`
+	HasMore bool         `json:"has_more" example:"false" doc:"Whether more changes are waiting after cursor; read them from the change feed"`
+}
@@ -18,7 +18,8 @@ var ValidStatuses = map[Status]bool{
 	StatusDone:       true,
 }
 
-// Category represents the category of a TODO item.
+// Category is the name of a TODO item's category. Categories are managed
+// through the categories API; a new database starts with the three below.
 type Category string
 
 const (
@@ -27,47 +28,173 @@ const (
 	CategoryOther    Category = "other"
 )
 
-// ValidCategories contains all valid category values.
-var ValidCategories = map[Category]bool{
-	CategoryPersonal: true,
-	CategoryWork:     true,
-	CategoryOther:    true,
+// Priority represents how important a TODO item is.
+type Priority string
+
+const (
+	PriorityNone   Priority = "none"
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// Priorities lists the priority values from least to most important.
+var Priorities = []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// ValidPriorities contains all valid priority values.
+var ValidPriorities = map[Priority]bool{
+	PriorityNone:   true,
+	PriorityLow:    true,
+	PriorityMedium: true,
+	PriorityHigh:   true,
+	PriorityUrgent: true,
 }
 
+// Triage says whether a TODO captured to the inbox has been sorted yet.
+type Triage string
+
+const (
+	TriagePending Triage = "pending"
+	TriageDone    Triage = "done"
+)
+
+// DateLayout is the format of calendar dates, such as a TODO's due date.
+const DateLayout = time.DateOnly
+
+// MonthLayout is the format of calendar months.
+const MonthLayout = "2006-01"
+
 // Todo represents a TODO item with progress tracking.
 type Todo struct {
-	ID              int64     `json:"id" example:"1"`
-	Title           string    `json:"title" example:"Buy groceries"`
-	Description     string    `json:"description" example:"Milk, eggs, bread"`
-	Status          Status    `json:"status" example:"pending" enums:"pending,in_progress,done"`
-	Category        Category  `json:"category" example:"personal" enums:"personal,work,other"`
-	ProgressPercent int       `json:"progress_percent" example:"0" minimum:"0" maximum:"100"`
-	CreatedAt       time.Time `json:"created_at" example:"2026-02-12T15:04:05Z"`
-	UpdatedAt       time.Time `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+	ID              int64          `json:"id" example:"1"`
+	Title           string         `json:"title" example:"Buy groceries"`
+	Description     string         `json:"description" example:"Milk, eggs, bread"`
+	Status          Status         `json:"status" example:"pending" enums:"pending,in_progress,done"`
+	Category        Category       `json:"category" example:"personal"`
+	ProjectID       *int64         `json:"project_id" example:"1" doc:"Project the TODO belongs to, or null"`
+	ProgressPercent int            `json:"progress_percent" example:"0" minimum:"0" maximum:"100"`
+	EstimateMinutes int            `json:"estimate_minutes" example:"30" minimum:"0" doc:"Estimated effort in minutes; 0 if not estimated"`
+	Priority        Priority       `json:"priority" example:"none" enums:"none,low,medium,high,urgent"`
+	DueDate         *string        `json:"due_date" format:"date" example:"2026-02-20" doc:"Day the TODO is due, or null"`
+	Archived        bool           `json:"archived" example:"false" doc:"Archived TODOs are hidden from default listings"`
+	ScheduledFor    *string        `json:"scheduled_for" format:"date" example:"2026-02-16" doc:"Day the TODO is planned for, or null"`
+	Triage          Triage         `json:"triage" example:"done" enums:"pending,done" doc:"pending while the TODO waits in the inbox to be triaged"`
+	Assignee        string         `json:"assignee" example:"alice" doc:"Who the TODO is assigned to, named as the audit log names actors; empty if no one"`
+	Pinned          bool           `json:"pinned" example:"false" doc:"Pinned TODOs are listed before all others, whatever the sort"`
+	SnoozedUntil    *time.Time     `json:"snoozed_until" example:"2026-02-13T09:00:00Z" doc:"Time the snoozed TODO wakes up and returns to default listings, or null if it is not snoozed"`
+	CustomFields    map[string]any `json:"custom_fields" example:"{\"size\":\"large\"}" doc:"Values of custom fields, keyed by field name"`
+	Location        *Location      `json:"location" doc:"Where the TODO is to be done, or null"`
+	Color           string         `json:"color" example:"#ff9800" doc:"Display color as #rrggbb, or empty for none"`
+	Icon            string         `json:"icon" example:"cart" doc:"Name of the icon to show the TODO with, or empty for none"`
+	Position        int64          `json:"position" example:"1024" doc:"Manual order, set with the move action; sort by position to list TODOs in it"`
+	Version         int64          `json:"version" example:"1" doc:"Incremented on every update; sent as the ETag header"`
+	CreatedAt       time.Time      `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt       time.Time      `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+	CompletedAt     *time.Time     `json:"completed_at" example:"2026-02-12T15:04:05Z" doc:"Time the TODO was last marked done, or null if it is not done"`
+}
+
+// Limits on the length of a TODO's text, in characters. The maxLength schema
+// tags on the request payloads must match them.
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 10000
+	MaxAssigneeLength    = 100
+	MaxPlaceLength       = 200
+)
+
+// Location is where a TODO is to be done, such as the shop for an errand.
+type Location struct {
+	Latitude  float64 `json:"latitude" minimum:"-90" maximum:"90" example:"51.5072" doc:"Degrees north of the equator"`
+	Longitude float64 `json:"longitude" minimum:"-180" maximum:"180" example:"-0.1276" doc:"Degrees east of the prime meridian"`
+	Place     string  `json:"place,omitempty" maxLength:"200" example:"Corner shop" doc:"Name of the place"`
 }
 
+// MaxRadiusKm is the largest radius TODOs can be listed near a point within:
+// half of the Earth's circumference.
+const MaxRadiusKm = 20038
+
+// MaxSnooze is the longest a TODO can be snoozed for.
+const MaxSnooze = 365 * 24 * time.Hour
+
 // CreateTodoRequest is the payload for creating a new TODO.
 type CreateTodoRequest struct {
-	Title           string `json:"title" example:"Buy groceries"`
-	Description     string `json:"description" example:"Milk, eggs, bread"`
-	Status          Status   `json:"status,omitempty" example:"pending" enums:"pending,in_progress,done"`
-	Category        Category `json:"category,omitempty" example:"personal" enums:"personal,work,other"`
-	ProgressPercent *int     `json:"progress_percent,omitempty" example:"0" minimum:"0" maximum:"100"`
+	Title           string         `json:"title" example:"Buy groceries" maxLength:"200"`
+	Description     string         `json:"description" example:"Milk, eggs, bread" maxLength:"10000"`
+	Status          Status         `json:"status,omitempty" example:"pending" enums:"pending,in_progress,done"`
+	Category        Category       `json:"category,omitempty" example:"personal" doc:"Name of the category; defaults to the first category by sort order"`
+	ProjectID       *int64         `json:"project_id,omitempty" example:"1" doc:"Project to add the TODO to"`
+	ProgressPercent *int           `json:"progress_percent,omitempty" example:"0" minimum:"0" maximum:"100"`
+	EstimateMinutes *int           `json:"estimate_minutes,omitempty" example:"30" minimum:"0" doc:"Estimated effort in minutes"`
+	Priority        Priority       `json:"priority,omitempty" example:"none" enums:"none,low,medium,high,urgent"`
+	DueDate         *string        `json:"due_date,omitempty" format:"date" example:"2026-02-20" doc:"Day the TODO is due"`
+	CustomFields    map[string]any `json:"custom_fields,omitempty" example:"{\"size\":\"large\"}" doc:"Values of custom fields, keyed by field name"`
+	Location        *Location      `json:"location,omitempty" doc:"Where the TODO is to be done"`
+	Color           string         `json:"color,omitempty" example:"#ff9800" pattern:"^#[0-9a-fA-F]{6}$" doc:"Display color as #rrggbb"`
+	Icon            string         `json:"icon,omitempty" example:"cart" enum:"bag,book,briefcase,calendar,car,cart,code,flag,gift,heart,home,inbox,leaf,lightbulb,mail,money,music,phone,star,tag,tool,travel,users" doc:"Name of the icon to show the TODO with"`
 }
 
 // UpdateTodoRequest is the payload for updating a TODO. All fields are optional.
 type UpdateTodoRequest struct {
-	Title           *string `json:"title,omitempty" example:"Buy groceries"`
-	Description     *string `json:"description,omitempty" example:"Milk, eggs, bread, butter"`
-	Status          *Status   `json:"status,omitempty" example:"in_progress" enums:"pending,in_progress,done"`
-	Category        *Category `json:"category,omitempty" example:"work" enums:"personal,work,other"`
-	ProgressPercent *int      `json:"progress_percent,omitempty" example:"50" minimum:"0" maximum:"100"`
+	Title           *string        `json:"title,omitempty" example:"Buy groceries" maxLength:"200"`
+	Description     *string        `json:"description,omitempty" example:"Milk, eggs, bread, butter" maxLength:"10000"`
+	Status          *Status        `json:"status,omitempty" example:"in_progress" enums:"pending,in_progress,done"`
+	Category        *Category      `json:"category,omitempty" example:"work"`
+	ProjectID       *int64         `json:"project_id,omitempty" example:"1" doc:"Project to move the TODO to; 0 removes it from its project"`
+	ProgressPercent *int           `json:"progress_percent,omitempty" example:"50" minimum:"0" maximum:"100"`
+	EstimateMinutes *int           `json:"estimate_minutes,omitempty" example:"45" minimum:"0" doc:"Estimated effort in minutes; 0 removes the estimate"`
+	Priority        *Priority      `json:"priority,omitempty" example:"high" enums:"none,low,medium,high,urgent"`
+	DueDate         *string        `json:"due_date,omitempty" example:"2026-02-20" doc:"Day the TODO is due, formatted YYYY-MM-DD; an empty string clears it"`
+	CustomFields    map[string]any `json:"custom_fields,omitempty" example:"{\"size\":\"small\"}" doc:"Custom field values to set, keyed by field name; null removes a value. Fields not named keep their values"`
+	Color           *string        `json:"color,omitempty" example:"#ff9800" doc:"Display color as #rrggbb; empty removes it"`
+	Icon            *string        `json:"icon,omitempty" example:"cart" doc:"Name of the icon to show the TODO with; empty removes it"`
+}
+
+// ScheduleTodoRequest is the payload for scheduling a TODO.
+type ScheduleTodoRequest struct {
+	Date string `json:"date" format:"date" example:"2026-02-16" doc:"Day to plan the TODO for"`
+}
+
+// AssignTodoRequest is the payload for assigning a TODO.
+type AssignTodoRequest struct {
+	Assignee string `json:"assignee" example:"alice" maxLength:"100" doc:"Who to assign the TODO to, named as the audit log names actors, or me for the caller"`
+}
+
+// SnoozeTodoRequest is the payload for snoozing a TODO. Exactly one of
+// Minutes and Until is required.
+type SnoozeTodoRequest struct {
+	Minutes int        `json:"minutes,omitempty" minimum:"1" maximum:"525600" example:"60" doc:"How long to snooze the TODO for"`
+	Until   *time.Time `json:"until,omitempty" example:"2026-02-13T09:00:00Z" doc:"When the TODO wakes up; at most a year from now"`
+}
+
+// MoveTodoRequest is the payload for moving a TODO in the manual order.
+// Exactly one field must be set.
+type MoveTodoRequest struct {
+	Before *int64 `json:"before,omitempty" example:"3" doc:"Move the TODO just before the TODO with this ID"`
+	After  *int64 `json:"after,omitempty" example:"2" doc:"Move the TODO just after the TODO with this ID"`
+	Index  *int   `json:"index,omitempty" example:"0" minimum:"0" doc:"Move the TODO to this zero-based index among the other active TODOs, or among archived ones if it is archived; past the end moves it last"`
+}
+
+// CaptureTodoRequest is the payload for quickly adding a TODO to the inbox.
+type CaptureTodoRequest struct {
+	Title       string `json:"title" example:"Call the plumber" maxLength:"200"`
+	Description string `json:"description,omitempty" example:"About the kitchen sink" maxLength:"10000"`
+}
+
+// TriageTodoRequest is the payload for triaging a TODO out of the inbox.
+type TriageTodoRequest struct {
+	Category     Category `json:"category" example:"personal"`
+	ProjectID    *int64   `json:"project_id,omitempty" example:"1" doc:"Project to add the TODO to; 0 removes it from its project"`
+	Priority     Priority `json:"priority,omitempty" example:"high" enums:"none,low,medium,high,urgent"`
+	DueDate      *string  `json:"due_date,omitempty" format:"date" example:"2026-02-20" doc:"Day the TODO is due"`
+	ScheduledFor *string  `json:"scheduled_for,omitempty" format:"date" example:"2026-02-16" doc:"Day to plan the TODO for"`
 }
 
-// TodoListResponse wraps a list of todos.
+// TodoListResponse wraps a page of todos.
 type TodoListResponse struct {
 	Todos []Todo `json:"todos"`
 	Count int    `json:"count" example:"5"`
+	Total int    `json:"total" example:"42"`
 }
 
 // ErrorResponse represents an API error.
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Transitions maps each status to the statuses an update may move a TODO to.
+// Completing and reopening a TODO are explicit actions and are not governed
+// by it.
+type Transitions map[Status][]Status
+
+// DefaultTransitions lets updates move freely between pending and
+// in_progress and mark a TODO done, but not move it out of done; a done TODO
+// must be reopened instead.
+var DefaultTransitions = Transitions{
+	StatusPending:    {StatusInProgress, StatusDone},
+	StatusInProgress: {StatusPending, StatusDone},
+}
+
+// Allows reports whether an update may change a TODO's status from one
+// status to another. Keeping the same status is always allowed.
+func (t Transitions) Allows(from, to Status) bool {
+	return from == to || slices.Contains(t[from], to)
+}
+
+// statusOrder lists statuses in the order String writes them.
+var statusOrder = []Status{StatusPending, StatusInProgress, StatusDone}
+
+// String formats t as a comma-separated list of from->to pairs, the form
+// ParseTransitions accepts.
+func (t Transitions) String() string {
+	var pairs []string
+	for _, from := range statusOrder {
+		for _, to := range statusOrder {
+			if from != to && slices.Contains(t[from], to) {
+				pairs = append(pairs, string(from)+"->"+string(to))
+			}
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseTransitions parses a comma-separated list of from->to pairs, such as
+// "pending->done,in_progress->done". An empty string allows no status
+// changes through updates.
+func ParseTransitions(s string) (Transitions, error) {
+	t := Transitions{}
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "->")
+		if !ok {
+			return nil, fmt.Errorf("transition %q: want from->to", pair)
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		for _, status := range []string{from, to} {
+			if !ValidStatuses[Status(status)] {
+				return nil, fmt.Errorf("transition %q: unknown status %q", pair, status)
+			}
+		}
+		if !t.Allows(Status(from), Status(to)) {
+			t[Status(from)] = append(t[Status(from)], Status(to))
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// TrashedTodo is a deleted TODO kept in the trash.
+type TrashedTodo struct {
+	Todo      Todo       `json:"todo" doc:"The TODO as it was when it was deleted"`
+	DeletedBy string     `json:"deleted_by" example:"alice" doc:"Who deleted the TODO, named as the audit log names actors"`
+	DeletedAt time.Time  `json:"deleted_at" example:"2026-02-12T15:04:05Z"`
+	PurgeAt   *time.Time `json:"purge_at" example:"2026-03-14T15:04:05Z" doc:"Time the TODO will be purged for good, or null if the trash is kept forever"`
+}
+
+// TrashListResponse wraps a page of the trash.
+type TrashListResponse struct {
+	Todos []TrashedTodo `json:"todos"`
+	Count int           `json:"count" example:"1"`
+	Total int           `json:"total" example:"1"`
+}
+
+// JanitorStats counts what the janitor has done since the service started.
+type JanitorStats struct {
+	Runs     int64      `json:"runs" example:"24"`
+	Errors   int64      `json:"errors" example:"0"`
+	Archived int64      `json:"archived" example:"12" doc:"Done TODOs archived"`
+	Purged   int64      `json:"purged" example:"3" doc:"Trashed TODOs purged"`
+	DryRun   bool       `json:"dry_run" example:"false" doc:"Whether the janitor only logs what it would do, in which case archived and purged count what it would have done"`
+	LastRun  *time.Time `json:"last_run" example:"2026-02-12T15:04:05Z" doc:"Time of the last run, or null if it has not run yet"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// TriggerEvent is one item of a polling trigger, shaped for low-code
+// platforms such as Zapier and n8n: a flat object with a unique id.
+type TriggerEvent struct {
+	ID         string    `json:"id" example:"todo_completed_42" doc:"Unique key of the event, for deduplication"`
+	Cursor     int64     `json:"cursor" example:"42" doc:"Position of the event; pass the largest cursor seen as the cursor parameter to get only later events"`
+	OccurredAt time.Time `json:"occurred_at" example:"2026-02-12T15:04:05Z"`
+	Actor      string    `json:"actor" example:"127.0.0.1" doc:"Who caused the event"`
+	Todo       Todo      `json:"todo" doc:"The TODO as it is now"`
+}
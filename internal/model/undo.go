@@ -0,0 +1,8 @@
+package model
+
+// UndoResponse reports what undoing an operation changed.
+type UndoResponse struct {
+	OperationID string  `json:"operation_id" example:"op_5f2c9a1e7b3d4c60" doc:"Operation that was undone, as named in the audit log"`
+	Restored    []Todo  `json:"restored" doc:"TODOs the operation updated or deleted, as they were before it"`
+	Deleted     []int64 `json:"deleted" example:"[7]" doc:"IDs of TODOs the operation created, which are now deleted"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// UsageResponse reports how much the service stores, for capacity planning.
+type UsageResponse struct {
+	Tables        []TableUsage  `json:"tables"`
+	DatabaseBytes int64         `json:"database_bytes" example:"4096000" doc:"Size of the database file"`
+	WALBytes      int64         `json:"wal_bytes" example:"32768" doc:"Size of the write-ahead log, which is folded into the database file at checkpoints"`
+	FreeBytes     int64         `json:"free_bytes" example:"8192" doc:"Unused space in the database file that VACUUM would reclaim"`
+	Pages         PageStats     `json:"pages"`
+	Quotas        QuotaLimits   `json:"quotas"`
+	Clients       []ClientUsage `json:"clients" doc:"The clients that have made the most requests this month, up to 100, most first"`
+	GeneratedAt   time.Time     `json:"generated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// PageStats describes the pages the database is stored in.
+type PageStats struct {
+	Size  int64 `json:"size" example:"4096" doc:"Size of a page in bytes"`
+	Count int64 `json:"count" example:"1000" doc:"Pages in the database, including free ones"`
+	Free  int64 `json:"free" example:"2" doc:"Pages holding no data, which VACUUM would reclaim"`
+}
+
+// QuotaLimits are the number of requests each client may make per day and
+// per month, in UTC. Zero means unlimited.
+type QuotaLimits struct {
+	Daily   int64 `json:"daily" example:"10000"`
+	Monthly int64 `json:"monthly" example:"200000"`
+}
+
+// ClientUsage reports how many requests a client has made.
+type ClientUsage struct {
+	Client    string `json:"client" example:"192.0.2.10" doc:"Client address, or actor if the server counts requests by actor"`
+	Today     int64  `json:"today" example:"420"`
+	ThisMonth int64  `json:"this_month" example:"8150"`
+}
+
+// CheckpointResponse reports a checkpoint of the write-ahead log.
+type CheckpointResponse struct {
+	Busy           bool  `json:"busy" example:"false" doc:"Whether another connection to the database kept the checkpoint from completing, in which case the log could not be truncated"`
+	WALBytesBefore int64 `json:"wal_bytes_before" example:"495552"`
+	WALBytesAfter  int64 `json:"wal_bytes_after" example:"0"`
+}
+
+// TableUsage reports the size and growth of one table.
+type TableUsage struct {
+	Name   string       `json:"name" example:"todos"`
+	Rows   int          `json:"rows" example:"1520"`
+	Growth *TableGrowth `json:"growth,omitempty" doc:"Absent for tables that do not record when rows were created"`
+}
+
+// TableGrowth counts the rows of a table created recently. Rows deleted
+// since are not counted, so this is the growth of what is still stored.
+type TableGrowth struct {
+	Last7Days  int     `json:"last_7_days" example:"42"`
+	Last30Days int     `json:"last_30_days" example:"180"`
+	PerDay     float64 `json:"per_day" example:"6" doc:"Average rows created per day over the last 30 days"`
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// View is a named, saved TODO query.
+type View struct {
+	ID        int64      `json:"id" example:"1"`
+	Name      string     `json:"name" example:"Work in progress this week"`
+	Filter    ViewFilter `json:"filter"`
+	CreatedAt time.Time  `json:"created_at" example:"2026-02-12T15:04:05Z"`
+	UpdatedAt time.Time  `json:"updated_at" example:"2026-02-12T15:04:05Z"`
+}
+
+// ViewFilter selects the TODOs a view lists. Unset fields match everything;
+// set fields must all match.
+type ViewFilter struct {
+	Statuses   []Status   `json:"statuses,omitempty" example:"in_progress" doc:"Match TODOs with any of these statuses"`
+	Categories []Category `json:"categories,omitempty" example:"work" doc:"Match TODOs in any of these categories"`
+	Priorities []Priority `json:"priorities,omitempty" example:"high" doc:"Match TODOs with any of these priorities"`
+	ProjectID  *int64     `json:"project_id,omitempty" example:"1" doc:"Match TODOs in this project"`
+	Archived   bool       `json:"archived,omitempty" doc:"Match archived TODOs instead of active ones"`
+	Triage     Triage     `json:"triage,omitempty" example:"done" doc:"Match TODOs with this triage state"`
+	Due        *DateRange `json:"due,omitempty" doc:"Match TODOs due within this range"`
+	Scheduled  *DateRange `json:"scheduled,omitempty" doc:"Match TODOs scheduled within this range"`
+	Completed  *DateRange `json:"completed,omitempty" doc:"Match TODOs completed within this range"`
+	Sort       string     `json:"sort,omitempty" example:"scheduled_for,-progress_percent" doc:"Default sort, in the form of the sort query parameter"`
+}
+
+// DateRange bounds a date, inclusive. Each bound is a date formatted as
+// DateLayout or a date relative to when the view is listed: today, today+N
+// or today-N days, week_start (Monday), or week_end (Sunday). Weeks are UTC.
+type DateRange struct {
+	From string `json:"from,omitempty" example:"week_start"`
+	To   string `json:"to,omitempty" example:"week_end"`
+}
+
+// CreateViewRequest is the payload for saving a new view.
+type CreateViewRequest struct {
+	Name   string     `json:"name" example:"Work in progress this week" maxLength:"100"`
+	Filter ViewFilter `json:"filter"`
+}
+
+// UpdateViewRequest is the payload for updating a view. All fields are
+// optional; a filter replaces the view's filter as a whole.
+type UpdateViewRequest struct {
+	Name   *string     `json:"name,omitempty" example:"Work in progress this week" maxLength:"100"`
+	Filter *ViewFilter `json:"filter,omitempty"`
+}
+
+// ViewListResponse wraps a page of views.
+type ViewListResponse struct {
+	Views []View `json:"views"`
+	Count int    `json:"count" example:"2"`
+	Total int    `json:"total" example:"2"`
+}
+
+// MaxViewNameLength limits view names, in characters. The maxLength schema
+// tags on the request payloads must match it.
+const MaxViewNameLength = 100
+
+// WeekStart returns the Monday of the UTC week containing t.
+func WeekStart(t time.Time) time.Time {
+	today := t.UTC().Truncate(24 * time.Hour)
+	offset := (int(today.Weekday()) + 6) % 7 // days since Monday
+	return today.AddDate(0, 0, -offset)
+}
+
+// ResolveDate returns the day s refers to, relative to now if it is one of
+// the relative forms DateRange accepts.
+func ResolveDate(s string, now time.Time) (time.Time, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	switch s {
+	case "today":
+		return today, nil
+	case "week_start":
+		return WeekStart(now), nil
+	case "week_end":
+		return WeekStart(now).AddDate(0, 0, 6), nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "today"); ok && len(rest) > 1 && (rest[0] == '+' || rest[0] == '-') {
+		days, err := strconv.Atoi(rest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative date %q", s)
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	d, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q; want YYYY-MM-DD, today, today+N, today-N, week_start, or week_end", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,14 @@
+package model
+
+// WeekResponse is a week of scheduled TODOs, one bucket per day.
+type WeekResponse struct {
+	Start string    `json:"start" format:"date" example:"2026-02-16" doc:"First day of the week"`
+	End   string    `json:"end" format:"date" example:"2026-02-22" doc:"Last day of the week"`
+	Days  []WeekDay `json:"days" doc:"The seven days of the week, in order"`
+}
+
+// WeekDay holds the TODOs scheduled for a single day.
+type WeekDay struct {
+	Date  string `json:"date" format:"date" example:"2026-02-16"`
+	Todos []Todo `json:"todos"`
+}
@@ -0,0 +1,232 @@
+// Package mqttbridge connects the service to an MQTT broker, for home
+// automation systems that speak MQTT rather than HTTP. It publishes every
+// change to a TODO as an event and carries out simple commands received on
+// a command topic, sharing the repository and validation with the HTTP
+// handlers. Anyone who can publish to the command topic can create and
+// complete TODOs, so restrict it with the broker's access control.
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"todo-service/internal/db"
+	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+	"todo-service/internal/validate"
+)
+
+// Actor attributes changes made by MQTT commands in the audit log.
+const Actor = "system:mqtt"
+
+// pollInterval is how often the audit log is checked for changes to publish.
+const pollInterval = time.Second
+
+// pollBatch is the most audit entries published per poll.
+const pollBatch = 100
+
+// Config configures the connection to the broker and the topics used.
+type Config struct {
+	// Broker is the broker URL, such as tcp://localhost:1883 or
+	// ssl://broker:8883.
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	// Topic is the prefix of every topic: events are published to
+	// Topic/events/ACTION, where ACTION is create, update, or delete;
+	// commands are read from Topic/commands; and their results are
+	// published to Topic/commands/results.
+	Topic string
+}
+
+// Command is a message on the command topic.
+type Command struct {
+	// Action is create or complete.
+	Action string `json:"action"`
+	// Ref is echoed in the result so the sender can match it up.
+	Ref string `json:"ref,omitempty"`
+	// ID is the TODO to complete.
+	ID int64 `json:"id,omitempty"`
+
+	model.CreateTodoRequest
+}
+
+// Result is published on the results topic for every command.
+type Result struct {
+	Ref   string      `json:"ref,omitempty"`
+	OK    bool        `json:"ok"`
+	Error string      `json:"error,omitempty"`
+	Todo  *model.Todo `json:"todo,omitempty"`
+}
+
+// Bridge publishes TODO events to and takes commands from an MQTT broker.
+type Bridge struct {
+	repo        db.Repository
+	maintenance *middleware.Maintenance
+	logger      *slog.Logger
+	cfg         Config
+	client      mqtt.Client
+}
+
+// New creates a Bridge that refuses commands while maintenance is
+// read-only; call Run to connect it.
+func New(repo db.Repository, maintenance *middleware.Maintenance, logger *slog.Logger, cfg Config) *Bridge {
+	b := &Bridge{repo: repo, maintenance: maintenance, logger: logger, cfg: cfg}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(b.onConnect).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("MQTT connection lost", slog.String("error", err.Error()))
+		})
+	b.client = mqtt.NewClient(opts)
+	return b
+}
+
+// Run connects to the broker, retrying until it succeeds, and publishes
+// changes made from then on until ctx is canceled.
+func (b *Bridge) Run(ctx context.Context) {
+	b.logger.Info("MQTT bridge started", slog.String("broker", b.cfg.Broker), slog.String("topic", b.cfg.Topic))
+	b.client.Connect()
+	defer b.client.Disconnect(250)
+
+	// Changes made before the bridge started are not published.
+	var after int64
+	newest, _ := query.Params{Limit: 1}.Options(db.AuditSort)
+	latest, err := b.repo.ListAudit(db.AuditFilter{}, newest)
+	if err != nil {
+		b.logger.Error("MQTT bridge failed to read the audit log", slog.String("error", err.Error()))
+		return
+	}
+	if len(latest) > 0 {
+		after = latest[0].ID
+	}
+
+	filter := db.AuditFilter{AfterID: &after}
+	opts, _ := query.Params{Limit: pollBatch, Sort: "id"}.Options(db.AuditSort)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Events wait in the audit log while the broker is unreachable.
+		if b.client.IsConnectionOpen() {
+			n, err := b.publishEvents(filter, opts)
+			if err != nil {
+				b.logger.Error("MQTT bridge failed to publish events", slog.String("error", err.Error()))
+			}
+			if n == pollBatch {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishEvents publishes the audit entries matching filter, advancing its
+// AfterID past each one the broker accepts, and returns how many there were.
+func (b *Bridge) publishEvents(filter db.AuditFilter, opts query.Options) (int, error) {
+	entries, err := b.repo.ListAudit(filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return 0, fmt.Errorf("encode event: %w", err)
+		}
+		token := b.client.Publish(b.cfg.Topic+"/events/"+string(e.Action), 1, false, payload)
+		if token.Wait(); token.Error() != nil {
+			return 0, fmt.Errorf("publish event %d: %w", e.ID, token.Error())
+		}
+		*filter.AfterID = e.ID
+	}
+	return len(entries), nil
+}
+
+// onConnect subscribes to the command topic on every connection, since the
+// broker may not keep subscriptions across reconnects.
+func (b *Bridge) onConnect(client mqtt.Client) {
+	b.logger.Info("MQTT connected", slog.String("broker", b.cfg.Broker))
+	token := client.Subscribe(b.cfg.Topic+"/commands", 1, b.onCommand)
+	if token.Wait(); token.Error() != nil {
+		b.logger.Error("MQTT subscribe failed", slog.String("error", token.Error().Error()))
+	}
+}
+
+func (b *Bridge) onCommand(client mqtt.Client, msg mqtt.Message) {
+	var cmd Command
+	var res Result
+	if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+		res.Error = "command must be a JSON object: " + err.Error()
+	} else {
+		res = b.run(cmd)
+	}
+
+	payload, err := json.Marshal(res)
+	if err != nil {
+		b.logger.Error("failed to encode MQTT command result", slog.String("error", err.Error()))
+		return
+	}
+	client.Publish(b.cfg.Topic+"/commands/results", 1, false, payload)
+}
+
+// run carries out cmd and reports the outcome.
+func (b *Bridge) run(cmd Command) Result {
+	res := Result{Ref: cmd.Ref}
+	if mode := b.maintenance.Mode(); mode.ReadOnly {
+		res.Error = middleware.ReadOnlyMessage(mode)
+		return res
+	}
+	info := db.AuditInfo{Actor: Actor, OperationID: db.NewOperationID()}
+
+	var todo model.Todo
+	var err error
+	switch cmd.Action {
+	case "create":
+		if err = validate.CreateTodo(cmd.CreateTodoRequest); err == nil {
+			todo, err = b.repo.CreateTodo(cmd.CreateTodoRequest, info)
+		}
+	case "complete":
+		todo, err = b.repo.CompleteTodo(cmd.ID, info)
+	default:
+		res.Error = fmt.Sprintf("unknown action %q; action must be create or complete", cmd.Action)
+		return res
+	}
+
+	var errs validate.Errors
+	switch {
+	case err == nil:
+		res.OK, res.Todo = true, &todo
+	case errors.As(err, &errs):
+		res.Error = err.Error()
+	case errors.Is(err, db.ErrNotFound):
+		res.Error = fmt.Sprintf("todo with id %d not found", cmd.ID)
+	case errors.Is(err, db.ErrProjectNotFound):
+		res.Error = "project not found"
+	case errors.Is(err, db.ErrCategoryNotFound):
+		res.Error = "category not found"
+	default:
+		b.logger.Error("MQTT command failed", slog.String("action", cmd.Action), slog.String("error", err.Error()), slog.String("operation_id", info.OperationID))
+		res.Error = "failed to " + cmd.Action + " todo"
+	}
+	return res
+}
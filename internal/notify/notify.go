@@ -0,0 +1,279 @@
+// Package notify posts messages about TODO events to Slack and Discord
+// webhooks, as configured by notification routes.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// timeout limits posting a single message.
+const timeout = 30 * time.Second
+
+// auditBatch is the most audit entries Pending reads at once.
+const auditBatch = 500
+
+// maxLines is the most events one post lists; the rest are counted.
+const maxLines = 20
+
+// discordLimit is the longest message Discord accepts, in characters.
+const discordLimit = 2000
+
+// Notifier finds the events notification routes are due to hear of and
+// posts them.
+type Notifier struct {
+	repo   db.Repository
+	client *http.Client
+}
+
+// New creates a Notifier that reads events from repo.
+func New(repo db.Repository) *Notifier {
+	return &Notifier{repo: repo, client: &http.Client{Timeout: timeout}}
+}
+
+// Pending returns the messages route is due for after cursor, as of now:
+// one for each audit entry of an event it routes, up to auditBatch entries,
+// and, once a day, one for each open TODO whose due date has passed.
+// It also returns the cursor past them and whether more audit entries are
+// waiting.
+func (n *Notifier) Pending(route model.NotificationRoute, cursor db.NotificationCursor, now time.Time) ([]string, db.NotificationCursor, bool, error) {
+	var lines []string
+
+	filter := db.AuditFilter{AfterID: &cursor.AuditID}
+	opts, err := query.Params{Limit: auditBatch, Sort: "id"}.Options(db.AuditSort)
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	entries, err := n.repo.ListAudit(filter, opts)
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	for _, e := range entries {
+		cursor.AuditID = e.ID
+		event := Event(e)
+		if !slices.Contains(route.Events, event) {
+			continue
+		}
+		todo, err := n.repo.GetTodo(e.TodoID)
+		if errors.Is(err, db.ErrNotFound) {
+			todo, err = n.deletedTodo(e)
+		}
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		lines = append(lines, Render(route, model.NotificationMessage{Event: event, Todo: todo, Actor: e.Actor, Changes: e.Changes}))
+	}
+	more := len(entries) == auditBatch
+
+	today := now.UTC().Format(model.DateLayout)
+	if more || cursor.OverdueFrom >= today {
+		return lines, cursor, more, nil
+	}
+	if slices.Contains(route.Events, model.EventOverdue) {
+		archived := false
+		yesterday := now.UTC().AddDate(0, 0, -1).Format(model.DateLayout)
+		opts, err := query.Params{Sort: "due_date,id"}.Options(db.TodoSort)
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		todos, err := n.repo.ListTodos(db.TodoFilter{
+			Archived: &archived,
+			Statuses: []model.Status{model.StatusPending, model.StatusInProgress},
+			DueFrom:  &cursor.OverdueFrom,
+			DueTo:    &yesterday,
+		}, opts)
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		for _, t := range todos {
+			lines = append(lines, Render(route, model.NotificationMessage{Event: model.EventOverdue, Todo: t}))
+		}
+	}
+	cursor.OverdueFrom = today
+	return lines, cursor, false, nil
+}
+
+// Event returns the kind of event the audit entry e records.
+func Event(e model.AuditEntry) model.NotificationEvent {
+	switch e.Action {
+	case model.AuditActionCreate:
+		return model.EventCreated
+	case model.AuditActionDelete:
+		return model.EventDeleted
+	}
+	if c, ok := e.Changes["status"]; ok && c.New == string(model.StatusDone) {
+		return model.EventCompleted
+	}
+	if c, ok := e.Changes["assignee"]; ok && c.New != "" {
+		return model.EventAssigned
+	}
+	if c, ok := e.Changes["snoozed_until"]; ok && c.Old != nil && c.New == nil {
+		return model.EventWoke
+	}
+	return model.EventUpdated
+}
+
+// deletedTodo reconstructs what it can of the TODO of audit entry e, which
+// no longer exists, from the entry recording its deletion.
+func (n *Notifier) deletedTodo(e model.AuditEntry) (model.Todo, error) {
+	if e.Action != model.AuditActionDelete {
+		deleted := model.AuditActionDelete
+		opts, err := query.Params{Limit: 1}.Options(db.AuditSort)
+		if err != nil {
+			return model.Todo{}, err
+		}
+		entries, err := n.repo.ListAudit(db.AuditFilter{TodoID: &e.TodoID, Action: &deleted}, opts)
+		if err != nil {
+			return model.Todo{}, err
+		}
+		if len(entries) > 0 {
+			e = entries[0]
+		}
+	}
+
+	value := func(field string) string {
+		c := e.Changes[field]
+		if s, ok := c.New.(string); ok {
+			return s
+		}
+		s, _ := c.Old.(string)
+		return s
+	}
+	return model.Todo{
+		ID:          e.TodoID,
+		Title:       value("title"),
+		Description: value("description"),
+		Status:      model.Status(value("status")),
+		Category:    model.Category(value("category")),
+		Assignee:    value("assignee"),
+	}, nil
+}
+
+// Render executes route's template, or the event's default template, with
+// msg. If the route's template fails, the default one is used instead.
+func Render(route model.NotificationRoute, msg model.NotificationMessage) string {
+	if route.Template != "" {
+		if text, err := execute(route.Template, msg); err == nil {
+			return text
+		}
+	}
+	text, _ := execute(model.DefaultNotificationTemplates[msg.Event], msg)
+	return text
+}
+
+func execute(text string, msg model.NotificationMessage) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Post posts lines to route's webhook as a single message, one per line.
+// Lines past maxLines are counted rather than listed. Any response other
+// than 2xx is an error.
+func (n *Notifier) Post(ctx context.Context, route model.NotificationRoute, lines []string) error {
+	if len(lines) > maxLines {
+		rest := len(lines) - maxLines
+		lines = append(lines[:maxLines:maxLines], fmt.Sprintf("…and %d more", rest))
+	}
+	text := strings.Join(lines, "\n")
+
+	var payload any
+	switch route.Channel {
+	case model.ChannelSlack:
+		payload = map[string]string{"text": text}
+	case model.ChannelDiscord:
+		if r := []rune(text); len(r) > discordLimit {
+			text = string(r[:discordLimit-1]) + "…"
+		}
+		// TODO titles must not ping anyone.
+		payload = map[string]any{"content": text, "allowed_mentions": map[string][]string{"parse": {}}}
+	default:
+		return fmt.Errorf("unknown channel %q", route.Channel)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-service")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// Errors from the client quote the URL, which holds the secret.
+		return fmt.Errorf("post to %s: %w", redacted(route.WebhookURL), unwrapURLError(err))
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded %s", redacted(route.WebhookURL), resp.Status)
+	}
+	return nil
+}
+
+// Broadcast posts msg, as each route renders it, to every route notified of
+// its event. It tries every route and returns the errors of those that
+// failed, joined.
+func (n *Notifier) Broadcast(ctx context.Context, msg model.NotificationMessage) error {
+	opts, err := query.Params{Sort: "id"}.Options(db.NotificationRouteSort)
+	if err != nil {
+		return err
+	}
+	routes, err := n.repo.ListNotificationRoutes(opts)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, route := range routes {
+		if !slices.Contains(route.Events, msg.Event) {
+			continue
+		}
+		if err := n.Post(ctx, route, []string{Render(route, msg)}); err != nil {
+			errs = append(errs, fmt.Errorf("route %d: %w", route.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// redacted returns rawURL reduced to its host. Slack and Discord webhook
+// URLs carry their token in the path.
+func redacted(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func unwrapURLError(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MaxLimit is the largest number of items a single list response may hold.
+// Unpaginated requests that would exceed it are rejected by Options.Check.
+const MaxLimit = 5000
+
+// Params are the pagination and sorting query parameters shared by every
+// list endpoint. Embed it in a huma input struct.
+type Params struct {
+	Limit  int    `query:"limit" required:"false" minimum:"0" maximum:"5000" doc:"Maximum number of items to return (0 for all, if at most 5000 match)"`
+	Offset int    `query:"offset" required:"false" minimum:"0" doc:"Number of items to skip"`
+	Sort   string `query:"sort" required:"false" doc:"Comma-separated fields to sort by; prefix a field with - for descending order" example:"-created_at,id"`
+
+	url url.URL
+}
+
+// Resolve captures the request URL so Links can build page URLs.
+func (p *Params) Resolve(ctx huma.Context) []error {
+	p.url = ctx.URL()
+	return nil
+}
+
+// Sort orders results by a single field.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Spec describes how a collection can be sorted.
+type Spec struct {
+	// Columns maps API field names accepted in the sort parameter to SQL columns.
+	Columns map[string]string
+	// Default is used when the request does not specify a sort.
+	Default []Sort
+	// Leading are SQL ORDER BY terms that come before the requested or
+	// default sort, for rows that always sort first.
+	Leading []string
+}
+
+// Options are validated pagination and sorting options for a repository query.
+type Options struct {
+	Limit  int
+	Offset int
+	Sort   []Sort
+
+	columns map[string]string
+	leading []string
+}
+
+// Options validates p against spec. The returned error is a huma 400 error
+// suitable for returning from a handler.
+func (p Params) Options(spec Spec) (Options, error) {
+	opts := Options{Limit: p.Limit, Offset: p.Offset, columns: spec.Columns, leading: spec.Leading}
+
+	if p.Sort == "" {
+		opts.Sort = spec.Default
+		return opts, nil
+	}
+
+	for _, field := range strings.Split(p.Sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if _, ok := spec.Columns[field]; !ok {
+			return Options{}, huma.Error400BadRequest(fmt.Sprintf(
+				"cannot sort by %q; sort must be a comma-separated list of: %s", field, spec.fieldNames(),
+			))
+		}
+		opts.Sort = append(opts.Sort, Sort{Field: field, Desc: desc})
+	}
+
+	return opts, nil
+}
+
+func (s Spec) fieldNames() string {
+	names := make([]string, 0, len(s.Columns))
+	for name := range s.Columns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// Check rejects unpaginated requests whose result would exceed MaxLimit.
+// total is the number of matching items before offset is applied.
+func (o Options) Check(total int) error {
+	if o.Limit > 0 || total-o.Offset <= MaxLimit {
+		return nil
+	}
+	return huma.Error400BadRequest(fmt.Sprintf(
+		"%d items match this request, more than the maximum of %d per response; use the limit and offset query parameters to paginate",
+		total-o.Offset, MaxLimit,
+	))
+}
+
+// Apply appends ORDER BY, LIMIT, and OFFSET clauses to query. Results are
+// always tie-broken by id so that pages are stable.
+func (o Options) Apply(query string, args []any) (string, []any) {
+	order := slices.Clone(o.leading)
+	byID := false
+	for _, s := range o.Sort {
+		col := o.columns[s.Field]
+		if col == "" {
+			continue
+		}
+		if col == "id" {
+			byID = true
+		}
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		order = append(order, col+" "+dir)
+	}
+	if !byID {
+		order = append(order, "id ASC")
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+
+	if o.Limit > 0 || o.Offset > 0 {
+		limit := o.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite requires a LIMIT before OFFSET; -1 means no limit
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, o.Offset)
+	}
+
+	return query, args
+}
+
+// Where accumulates SQL filter conditions joined with AND.
+type Where struct {
+	conditions []string
+	args       []any
+}
+
+// Add appends a condition and its arguments.
+func (w *Where) Add(condition string, args ...any) {
+	w.conditions = append(w.conditions, condition)
+	w.args = append(w.args, args...)
+}
+
+// In appends a condition matching rows whose column equals any of values.
+// No values match no rows.
+func (w *Where) In(column string, values ...any) {
+	if len(values) == 0 {
+		w.Add("0")
+		return
+	}
+	w.Add(column+" IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")+")", values...)
+}
+
+// Apply appends the WHERE clause, if any, to query.
+func (w Where) Apply(query string, args []any) (string, []any) {
+	if len(w.conditions) == 0 {
+		return query, args
+	}
+	return query + " WHERE " + strings.Join(w.conditions, " AND "), append(args, w.args...)
+}
+
+// Links returns an RFC 8288 Link header value with first, prev, next, and
+// last page URLs, or "" if the request was not paginated.
+func (p Params) Links(total int) string {
+	if p.Limit <= 0 {
+		return ""
+	}
+
+	var links []string
+	add := func(rel string, offset int) {
+		u := p.url
+		q := u.Query()
+		q.Set("limit", strconv.Itoa(p.Limit))
+		q.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / p.Limit * p.Limit
+	}
+
+	add("first", 0)
+	if p.Offset > 0 {
+		add("prev", max(p.Offset-p.Limit, 0))
+	}
+	if p.Offset+p.Limit < total {
+		add("next", p.Offset+p.Limit)
+	}
+	add("last", last)
+
+	return strings.Join(links, ", ")
+}
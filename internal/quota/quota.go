@@ -0,0 +1,187 @@
+// Package quota counts the requests each client makes per day and per
+// month, in UTC, and holds clients to daily and monthly quotas. Counts are
+// kept in memory and saved to the database by Flush, so that they survive
+// restarts without a write for every request.
+package quota
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+// Period formats, which sort in time order.
+const (
+	dayFormat   = "2006-01-02"
+	monthFormat = "2006-01"
+)
+
+// Result is the outcome of counting a request.
+type Result struct {
+	Allowed bool
+	// DailyRemaining and MonthlyRemaining are the requests the client may
+	// still make today and this month; meaningful only for a nonzero quota.
+	DailyRemaining   int64
+	MonthlyRemaining int64
+	// Reset is when the quota that is used up first renews: the next day,
+	// or the next month once the monthly quota is used up.
+	Reset time.Time
+}
+
+// Tracker counts requests against quotas. It is safe for concurrent use.
+type Tracker struct {
+	repo   db.Repository
+	limits model.QuotaLimits
+
+	mu      sync.Mutex
+	day     string
+	month   string
+	daily   map[string]int64
+	monthly map[string]int64
+	// pending holds the counts not yet flushed, by client and period.
+	pending map[string]map[string]int64
+}
+
+// New creates a Tracker holding clients to limits, starting from the counts
+// stored for the current day and month as of now.
+func New(repo db.Repository, limits model.QuotaLimits, now time.Time) (*Tracker, error) {
+	now = now.UTC()
+	t := &Tracker{
+		repo:    repo,
+		limits:  limits,
+		day:     now.Format(dayFormat),
+		month:   now.Format(monthFormat),
+		pending: map[string]map[string]int64{},
+	}
+	var err error
+	if t.daily, err = repo.RequestCounts(t.day); err != nil {
+		return nil, err
+	}
+	if t.monthly, err = repo.RequestCounts(t.month); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Limits returns the quotas the tracker enforces.
+func (t *Tracker) Limits() model.QuotaLimits {
+	return t.limits
+}
+
+// Take counts a request by client made at now, unless the client has used
+// up its daily or monthly quota, in which case the request is refused and
+// not counted.
+func (t *Tracker) Take(client string, now time.Time) Result {
+	now = now.UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover(now)
+
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	res := Result{
+		DailyRemaining:   t.limits.Daily - t.daily[client],
+		MonthlyRemaining: t.limits.Monthly - t.monthly[client],
+		Reset:            tomorrow,
+	}
+	monthlyOut := t.limits.Monthly > 0 && res.MonthlyRemaining <= 0
+	if monthlyOut {
+		res.Reset = nextMonth
+	}
+	if monthlyOut || t.limits.Daily > 0 && res.DailyRemaining <= 0 {
+		res.DailyRemaining = max(res.DailyRemaining, 0)
+		res.MonthlyRemaining = max(res.MonthlyRemaining, 0)
+		return res
+	}
+
+	res.Allowed = true
+	res.DailyRemaining--
+	res.MonthlyRemaining--
+	t.daily[client]++
+	t.monthly[client]++
+	if t.pending[client] == nil {
+		t.pending[client] = map[string]int64{}
+	}
+	t.pending[client][t.day]++
+	t.pending[client][t.month]++
+	return res
+}
+
+// rollover starts counting afresh once now is in a new day or month.
+func (t *Tracker) rollover(now time.Time) {
+	if day := now.Format(dayFormat); day != t.day {
+		t.day = day
+		t.daily = map[string]int64{}
+	}
+	if month := now.Format(monthFormat); month != t.month {
+		t.month = month
+		t.monthly = map[string]int64{}
+	}
+}
+
+// Flush saves the counts made since the last flush. If saving fails they
+// are kept for the next one.
+func (t *Tracker) Flush() error {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = map[string]map[string]int64{}
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := t.repo.AddRequestCounts(pending); err != nil {
+		t.mu.Lock()
+		for client, periods := range pending {
+			if t.pending[client] == nil {
+				t.pending[client] = map[string]int64{}
+			}
+			for period, n := range periods {
+				t.pending[client][period] += n
+			}
+		}
+		t.mu.Unlock()
+		return fmt.Errorf("flush request counts: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes stored daily counts more than keepDays days before now,
+// and monthly counts more than keepMonths months before it.
+func (t *Tracker) Prune(now time.Time, keepDays, keepMonths int) (int64, error) {
+	now = now.UTC()
+	day := now.AddDate(0, 0, -keepDays).Format(dayFormat)
+	month := time.Date(now.Year(), now.Month()-time.Month(keepMonths), 1, 0, 0, 0, 0, time.UTC).Format(monthFormat)
+	return t.repo.PruneRequestCounts(day, month)
+}
+
+// Usage returns the requests made today and this month, as of now, by the
+// limit clients that have made the most this month, most first.
+func (t *Tracker) Usage(now time.Time, limit int) []model.ClientUsage {
+	t.mu.Lock()
+	t.rollover(now.UTC())
+	clients := make([]model.ClientUsage, 0, len(t.monthly))
+	for _, client := range slices.Sorted(maps.Keys(t.monthly)) {
+		clients = append(clients, model.ClientUsage{
+			Client:    client,
+			Today:     t.daily[client],
+			ThisMonth: t.monthly[client],
+		})
+	}
+	t.mu.Unlock()
+
+	slices.SortStableFunc(clients, func(a, b model.ClientUsage) int {
+		return cmp.Compare(b.ThisMonth, a.ThisMonth)
+	})
+	if len(clients) > limit {
+		clients = clients[:limit]
+	}
+	return clients
+}
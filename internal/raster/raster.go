@@ -0,0 +1,151 @@
+// Package raster draws text onto monochrome images for e-paper displays. It
+// has a built-in bitmap font, so no font files are needed, and encodes 1-bit
+// PNG and BMP files that small devices can decode.
+package raster
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A character cell is the 5x7 glyph plus one column and two rows of
+// spacing, in pixels at scale 1.
+const (
+	cellWidth  = 6
+	cellHeight = 9
+)
+
+// palette maps index 0 to white paper and 1 to black ink.
+var palette = color.Palette{color.White, color.Black}
+
+// Grid returns how many characters fit across and down a width x height
+// image at scale, inside a margin of one cell.
+func Grid(width, height, scale int) (cols, rows int) {
+	cols = width/(cellWidth*scale) - 2
+	rows = height/(cellHeight*scale) - 2
+	return max(cols, 0), max(rows, 0)
+}
+
+// Text draws lines, one per row of the Grid, onto a new width x height
+// image, with each glyph pixel scaled to a scale x scale square. Rows and
+// characters that do not fit are dropped. Characters outside printable
+// ASCII are drawn without accents where possible and as ? otherwise.
+func Text(lines []string, width, height, scale int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	cols, rows := Grid(width, height, scale)
+
+	for row, line := range lines[:min(len(lines), rows)] {
+		y := (row + 1) * cellHeight * scale
+		col := 0
+		for _, r := range line {
+			if col == cols {
+				break
+			}
+			drawGlyph(img, (col+1)*cellWidth*scale, y, scale, fold(r))
+			col++
+		}
+	}
+	return img
+}
+
+func drawGlyph(img *image.Paletted, x, y, scale int, r rune) {
+	glyph := font[r-' ']
+	for gx, column := range glyph {
+		for gy := 0; gy < 7; gy++ {
+			if column&(1<<gy) == 0 {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(x+gx*scale+dx, y+gy*scale+dy, 1)
+				}
+			}
+		}
+	}
+}
+
+// fold maps r to a printable ASCII rune, dropping accents and replacing
+// typographic punctuation with its plain counterpart.
+func fold(r rune) rune {
+	if r >= ' ' && r <= '~' {
+		return r
+	}
+	if p, ok := punctuation[r]; ok {
+		return p
+	}
+	for _, d := range norm.NFD.String(string(r)) {
+		if d >= ' ' && d <= '~' {
+			return d
+		}
+		if !unicode.Is(unicode.Mn, d) {
+			break
+		}
+	}
+	return '?'
+}
+
+var punctuation = map[rune]rune{
+	'\u00a0': ' ', // no-break space
+	'\u2010': '-', // hyphen
+	'\u2013': '-', // en dash
+	'\u2014': '-', // em dash
+	'\u2018': '\'',
+	'\u2019': '\'',
+	'\u201c': '"',
+	'\u201d': '"',
+	'\u00ab': '"', // guillemets
+	'\u00bb': '"',
+}
+
+// EncodePNG writes img as a 1-bit PNG.
+func EncodePNG(w io.Writer, img *image.Paletted) error {
+	return png.Encode(w, img)
+}
+
+// EncodeBMP writes img as an uncompressed 1-bit BMP, the format most
+// microcontroller display libraries draw directly.
+func EncodeBMP(w io.Writer, img *image.Paletted) error {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	stride := (width + 31) / 32 * 4 // rows are padded to 4 bytes
+	const headerSize = 14 + 40 + 2*4
+
+	header := make([]byte, headerSize)
+	// File header.
+	copy(header[0:], "BM")
+	binary.LittleEndian.PutUint32(header[2:], uint32(headerSize+stride*height))
+	binary.LittleEndian.PutUint32(header[10:], headerSize)
+	// Info header.
+	binary.LittleEndian.PutUint32(header[14:], 40)
+	binary.LittleEndian.PutUint32(header[18:], uint32(width))
+	binary.LittleEndian.PutUint32(header[22:], uint32(height)) // bottom-up
+	binary.LittleEndian.PutUint16(header[26:], 1)              // planes
+	binary.LittleEndian.PutUint16(header[28:], 1)              // bits per pixel
+	binary.LittleEndian.PutUint32(header[34:], uint32(stride*height))
+	binary.LittleEndian.PutUint32(header[46:], 2) // colors used
+	// Palette, as blue, green, red, and reserved bytes: white, then black.
+	copy(header[54:], []byte{0xff, 0xff, 0xff, 0, 0, 0, 0, 0})
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]byte, stride)
+	for y := height - 1; y >= 0; y-- {
+		clear(row)
+		for x := 0; x < width; x++ {
+			if img.ColorIndexAt(img.Rect.Min.X+x, img.Rect.Min.Y+y) == 1 {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit configures a token bucket: Burst tokens are available at once and
+// refill at Rate tokens per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left after this request.
+	Remaining int
+	// RetryAfter is how long to wait before a token is available; zero when allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Store tracks token buckets by key. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore is an in-process Store. Buckets that have refilled completely
+// are evicted periodically, so memory is bounded by the number of clients
+// active within a refill period.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// sweepInterval is how often idle buckets are evicted.
+const sweepInterval = time.Minute
+
+// Take removes one token from the bucket for key if one is available.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now, limit)
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b, now, limit)
+	b.last = now
+
+	res := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+
+	return res, nil
+}
+
+// sweep evicts buckets that would be full by now; recreating them later is
+// indistinguishable from keeping them.
+func (s *MemoryStore) sweep(now time.Time, limit Limit) {
+	for key, b := range s.buckets {
+		if refill(b, now, limit) >= float64(limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func refill(b *bucket, now time.Time, limit Limit) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	return math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
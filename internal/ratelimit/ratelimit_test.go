@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source for a MemoryStore.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{t: time.Date(2026, 2, 12, 15, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.now
+	return s, c
+}
+
+func take(t *testing.T, s *MemoryStore, key string, limit Limit) Result {
+	t.Helper()
+	res, err := s.Take(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("Take(%q): %v", key, err)
+	}
+	return res
+}
+
+func TestTakeExhaustsBurst(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := range limit.Burst {
+		res := take(t, s, "a", limit)
+		if !res.Allowed {
+			t.Fatalf("request %d: denied, want allowed", i+1)
+		}
+		if want := limit.Burst - i - 1; res.Remaining != want {
+			t.Errorf("request %d: %d remaining, want %d", i+1, res.Remaining, want)
+		}
+		if res.RetryAfter != 0 {
+			t.Errorf("request %d: retry after %v, want 0", i+1, res.RetryAfter)
+		}
+	}
+
+	res := take(t, s, "a", limit)
+	if res.Allowed {
+		t.Fatal("request past the burst: allowed, want denied")
+	}
+	if res.Remaining != 0 || res.RetryAfter != time.Second || res.ResetAfter != 3*time.Second {
+		t.Errorf("request past the burst: %+v, want 0 remaining, retry after 1s, reset after 3s", res)
+	}
+
+	// Other clients have buckets of their own.
+	if res := take(t, s, "b", limit); !res.Allowed {
+		t.Error("another client: denied, want allowed")
+	}
+}
+
+func TestTakeRefillsOverTime(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Rate: 2, Burst: 4}
+
+	for range limit.Burst {
+		take(t, s, "a", limit)
+	}
+	if res := take(t, s, "a", limit); res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("empty bucket: %+v, want denied with retry after 500ms", res)
+	}
+
+	// Half a token is not enough.
+	c.advance(250 * time.Millisecond)
+	if res := take(t, s, "a", limit); res.Allowed || res.RetryAfter != 250*time.Millisecond {
+		t.Fatalf("after 250ms: %+v, want denied with retry after 250ms", res)
+	}
+
+	c.advance(250 * time.Millisecond)
+	if res := take(t, s, "a", limit); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("after 500ms: %+v, want allowed with none remaining", res)
+	}
+
+	// A long wait refills the bucket only up to the burst.
+	c.advance(time.Hour)
+	for i := range limit.Burst {
+		if res := take(t, s, "a", limit); !res.Allowed {
+			t.Fatalf("after an hour, request %d: denied, want allowed", i+1)
+		}
+	}
+	if res := take(t, s, "a", limit); res.Allowed {
+		t.Fatal("after an hour, request past the burst: allowed, want denied")
+	}
+}
+
+func TestSweepEvictsFullBuckets(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Rate: 1, Burst: 10}
+
+	take(t, s, "idle", limit)
+	c.advance(sweepInterval - 2*time.Second)
+	for range 5 {
+		take(t, s, "busy", limit)
+	}
+
+	// At the sweep, the idle bucket has refilled and goes; the busy one is
+	// still short of tokens and stays.
+	c.advance(2 * time.Second)
+	take(t, s, "new", limit)
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("idle bucket was kept, want it evicted")
+	}
+	if b, ok := s.buckets["busy"]; !ok {
+		t.Error("busy bucket was evicted, want it kept")
+	} else if b.tokens >= float64(limit.Burst) {
+		t.Errorf("busy bucket has %v tokens, want fewer than %d", b.tokens, limit.Burst)
+	}
+
+	// No sweep runs again until the interval has passed.
+	c.advance(sweepInterval - time.Second)
+	take(t, s, "other", limit)
+	if _, ok := s.buckets["new"]; !ok {
+		t.Error("bucket evicted before the next sweep was due")
+	}
+	c.advance(time.Second)
+	take(t, s, "other", limit)
+	if _, ok := s.buckets["new"]; ok {
+		t.Error("full bucket kept after the next sweep")
+	}
+}
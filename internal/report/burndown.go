@@ -0,0 +1,83 @@
+// Package report derives time-series reports by replaying the audit log.
+package report
+
+import (
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// todoState is what a burndown needs to know about a TODO at a point in time.
+type todoState struct {
+	status    model.Status
+	estimate  int
+	projectID *int64
+	archived  bool
+}
+
+func (s todoState) open() bool {
+	return s.status != model.StatusDone && !s.archived
+}
+
+// Burndown replays entries, which must be sorted by ID, and returns the work
+// left open at the end of each of days, given as the start of each day in
+// UTC. If projectID is set, only TODOs in that project at the time count.
+func Burndown(entries []model.AuditEntry, days []time.Time, projectID *int64) []model.BurndownPoint {
+	todos := map[int64]*todoState{}
+	points := make([]model.BurndownPoint, 0, len(days))
+
+	next := 0
+	for _, day := range days {
+		end := day.AddDate(0, 0, 1)
+		for ; next < len(entries) && entries[next].CreatedAt.Before(end); next++ {
+			apply(todos, entries[next])
+		}
+
+		p := model.BurndownPoint{Date: day.Format(model.DateLayout)}
+		for _, t := range todos {
+			if !t.open() || (projectID != nil && (t.projectID == nil || *t.projectID != *projectID)) {
+				continue
+			}
+			p.RemainingMinutes += t.estimate
+			p.OpenTodos++
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// apply updates todos with the new field values recorded in e.
+func apply(todos map[int64]*todoState, e model.AuditEntry) {
+	if e.Action == model.AuditActionDelete {
+		delete(todos, e.TodoID)
+		return
+	}
+
+	t := todos[e.TodoID]
+	if t == nil {
+		t = &todoState{status: model.StatusPending}
+		todos[e.TodoID] = t
+	}
+	for name, c := range e.Changes {
+		switch name {
+		case "status":
+			if s, ok := c.New.(string); ok {
+				t.status = model.Status(s)
+			}
+		case "estimate_minutes":
+			if m, ok := c.New.(float64); ok {
+				t.estimate = int(m)
+			}
+		case "project_id":
+			t.projectID = nil
+			if id, ok := c.New.(float64); ok {
+				pid := int64(id)
+				t.projectID = &pid
+			}
+		case "archived":
+			if a, ok := c.New.(bool); ok {
+				t.archived = a
+			}
+		}
+	}
+}
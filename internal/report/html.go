@@ -0,0 +1,108 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+var todoReportTemplate = template.Must(template.New("todos").Funcs(template.FuncMap{
+	"progress": progress,
+	"priority": priorityLabel,
+	"status":   statusLabel,
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2 January 2006, 15:04 UTC")
+	},
+	"estimate": estimateLabel,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>TODO report</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; color: #222; margin: 2em; }
+h1 { margin-bottom: 0; }
+.meta { color: #666; margin-top: .25em; }
+h2 { border-bottom: 1px solid #ccc; padding-bottom: .2em; margin-top: 1.5em; page-break-after: avoid; }
+h2 small { font-weight: normal; color: #666; }
+table { border-collapse: collapse; width: 100%; }
+tr { page-break-inside: avoid; }
+th, td { text-align: left; padding: .3em .5em; border-bottom: 1px solid #eee; vertical-align: middle; }
+th { font-weight: 600; color: #555; }
+.bar { width: 8em; height: .7em; border: 1px solid #888; }
+.bar span { display: block; height: 100%; background: #4a8; -webkit-print-color-adjust: exact; print-color-adjust: exact; }
+.done td { color: #888; }
+.done .title { text-decoration: line-through; }
+</style>
+</head>
+<body>
+<h1>TODO report</h1>
+<p class="meta">{{.Done}} of {{.Total}} done &middot; generated {{date .Generated}}</p>
+{{range .Groups}}
+<h2>{{.Name}} <small>{{.Done}} of {{len .Todos}} done &middot; {{.Progress}}%</small></h2>
+<table>
+<tr><th>TODO</th><th>Priority</th><th>Status</th><th>Progress</th><th>Due</th><th>Estimate</th></tr>
+{{range .Todos}}<tr{{if eq .Status "done"}} class="done"{{end}}>
+<td class="title">{{.Title}}</td>
+<td>{{priority .Priority}}</td>
+<td>{{status .Status}}</td>
+<td><div class="bar"><span style="width: {{progress .}}%"></span></div></td>
+<td>{{with .DueDate}}{{.}}{{end}}</td>
+<td>{{estimate .EstimateMinutes}}</td>
+</tr>
+{{end}}</table>
+{{else}}
+<p>No TODOs.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// RenderTodosHTML writes r as a self-contained HTML page styled for
+// printing.
+func RenderTodosHTML(w io.Writer, r TodoReport) error {
+	return todoReportTemplate.Execute(w, r)
+}
+
+func statusLabel(s model.Status) string {
+	switch s {
+	case model.StatusInProgress:
+		return "In progress"
+	case model.StatusDone:
+		return "Done"
+	}
+	return "Pending"
+}
+
+// priorityLabel names a priority, or returns "" for none.
+func priorityLabel(p model.Priority) string {
+	switch p {
+	case model.PriorityLow:
+		return "Low"
+	case model.PriorityMedium:
+		return "Medium"
+	case model.PriorityHigh:
+		return "High"
+	case model.PriorityUrgent:
+		return "Urgent"
+	}
+	return ""
+}
+
+// estimateLabel formats an estimate in minutes as hours and minutes, or ""
+// if there is none.
+func estimateLabel(minutes int) string {
+	switch h, m := minutes/60, minutes%60; {
+	case minutes == 0:
+		return ""
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+}
@@ -0,0 +1,263 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"todo-service/internal/model"
+)
+
+// Page layout, in points, for A4 paper.
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 50
+	rowHeight    = 18
+	bodyFontSize = 10
+)
+
+// Table columns: the left edge of each, and the width of the title column.
+const (
+	colTitle    = pageMargin
+	colPriority = 255
+	colStatus   = 305
+	colProgress = 370
+	colDue      = 455
+	colEstimate = 520
+	titleWidth  = colPriority - colTitle - 10
+	barWidth    = 70
+)
+
+// RenderTodosPDF writes r as an A4 PDF document laid out like the HTML
+// report. It uses the standard Helvetica fonts, so text outside Windows-1252
+// is replaced by question marks and long titles are truncated.
+func RenderTodosPDF(w io.Writer, r TodoReport) error {
+	d := &pdfDoc{}
+	d.newPage()
+
+	d.text(pageMargin, d.y, 18, true, gray(0.13), "TODO report")
+	d.y -= 20
+	d.text(pageMargin, d.y, bodyFontSize, false, gray(0.4),
+		fmt.Sprintf("%d of %d done - generated %s", r.Done, r.Total, r.Generated.UTC().Format("2 January 2006, 15:04 UTC")))
+	d.y -= 14
+
+	if len(r.Groups) == 0 {
+		d.y -= rowHeight
+		d.text(pageMargin, d.y, bodyFontSize, false, gray(0.13), "No TODOs.")
+	}
+	for _, g := range r.Groups {
+		// Keep a heading with at least its column headers and first row.
+		d.need(28 + 2*rowHeight)
+		d.y -= 28
+		heading := truncate(g.Name, 13, true, 300)
+		d.text(pageMargin, d.y, 13, true, gray(0.13), heading)
+		d.text(pageMargin+textWidth(heading, 13, true)+8, d.y, 9, false, gray(0.4),
+			fmt.Sprintf("%d of %d done - %d%%", g.Done, len(g.Todos), g.Progress))
+		d.rule(d.y-5, 0.8)
+		d.y -= 6
+		d.columnHeaders()
+
+		for _, t := range g.Todos {
+			if d.need(rowHeight) {
+				d.columnHeaders()
+			}
+			d.row(t)
+		}
+	}
+
+	return d.write(w)
+}
+
+// gray is a fill color from black (0) to white (1).
+type gray float64
+
+// pdfDoc accumulates the content streams of a document's pages.
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64 // baseline of the last line drawn
+}
+
+func (d *pdfDoc) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - pageMargin
+	d.text(pageWidth-pageMargin-40, pageMargin/2, 8, false, gray(0.5), "Page "+strconv.Itoa(len(d.pages)))
+}
+
+// need starts a new page unless height points fit below the current line,
+// and reports whether it did.
+func (d *pdfDoc) need(height float64) bool {
+	if d.y-height >= pageMargin {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+func (d *pdfDoc) columnHeaders() {
+	d.y -= rowHeight
+	for _, c := range []struct {
+		x     float64
+		label string
+	}{{colTitle, "TODO"}, {colPriority, "Priority"}, {colStatus, "Status"}, {colProgress, "Progress"}, {colDue, "Due"}, {colEstimate, "Estimate"}} {
+		d.text(c.x, d.y, 9, true, gray(0.33), c.label)
+	}
+	d.rule(d.y-6, 0.9)
+}
+
+func (d *pdfDoc) row(t model.Todo) {
+	d.y -= rowHeight
+	color := gray(0.13)
+	if t.Status == model.StatusDone {
+		color = gray(0.53)
+	}
+
+	d.text(colTitle, d.y, bodyFontSize, false, color, truncate(t.Title, bodyFontSize, false, titleWidth))
+	d.text(colPriority, d.y, bodyFontSize, false, color, priorityLabel(t.Priority))
+	d.text(colStatus, d.y, bodyFontSize, false, color, statusLabel(t.Status))
+
+	fmt.Fprintf(d.page, "0.53 G 0.5 w %d %.1f %d 7 re S\n", colProgress, d.y-0.5, barWidth)
+	if p := progress(t); p > 0 {
+		fmt.Fprintf(d.page, "0.29 0.67 0.53 rg %d %.1f %.1f 7 re f\n", colProgress, d.y-0.5, float64(barWidth*p)/100)
+	}
+
+	if t.DueDate != nil {
+		d.text(colDue, d.y, bodyFontSize, false, color, *t.DueDate)
+	}
+	d.text(colEstimate, d.y, bodyFontSize, false, color, estimateLabel(t.EstimateMinutes))
+	d.rule(d.y-6, 0.93)
+}
+
+// rule draws a horizontal line across the page at y.
+func (d *pdfDoc) rule(y float64, shade gray) {
+	fmt.Fprintf(d.page, "%.2f G 0.5 w %d %.1f m %d %.1f l S\n", shade, pageMargin, y, pageWidth-pageMargin, y)
+}
+
+func (d *pdfDoc) text(x, y, size float64, bold bool, color gray, s string) {
+	if s == "" {
+		return
+	}
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT %.2f g /%s %g Tf %.1f %.1f Td (%s) Tj ET\n", color, font, size, x, y, pdfString(s))
+}
+
+// write serializes the document: the catalog, the page tree, two fonts, and
+// a page and content stream object for each page, followed by the
+// cross-reference table.
+func (d *pdfDoc) write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	const firstPage = 5
+	kids := ""
+	for i := range d.pages {
+		kids += fmt.Sprintf("%d 0 R ", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// pdfString encodes s in Windows-1252 and escapes it for a PDF literal
+// string.
+func pdfString(s string) string {
+	var b bytes.Buffer
+	for _, c := range encode(s) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 32 {
+				c = ' '
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// truncate shortens s with an ellipsis to fit within width points.
+func truncate(s string, size float64, bold bool, width float64) string {
+	if textWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"…", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// textWidth approximates the width of s in points. Bold text is taken to be
+// 5% wider than regular, which is close for Helvetica.
+func textWidth(s string, size float64, bold bool) float64 {
+	units := 0
+	for _, c := range encode(s) {
+		if c >= 32 && c <= 126 {
+			units += helveticaWidths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * size / 1000
+	if bold {
+		w *= 1.05
+	}
+	return w
+}
+
+// helveticaWidths are the advance widths of printable ASCII in Helvetica,
+// in thousandths of the font size, from its AFM metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+var sharedTodoTemplate = template.Must(template.New("share").Funcs(template.FuncMap{
+	"status": statusLabel,
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2 January 2006, 15:04 UTC")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font: 16px/1.5 system-ui, sans-serif; color: #222; max-width: 40em; margin: 2em auto; padding: 0 1em; }
+h1 { margin-bottom: .25em; }
+.status { display: inline-block; padding: .1em .6em; border-radius: 1em; background: #eee; font-size: .9em; }
+.done { background: #cfe9dc; }
+.bar { height: .6em; border: 1px solid #888; margin: 1em 0; }
+.bar span { display: block; height: 100%; background: #4a8; }
+.description { white-space: pre-wrap; }
+.meta { color: #666; font-size: .9em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><span class="status{{if eq .Status "done"}} done{{end}}">{{status .Status}}</span> {{.ProgressPercent}}% done{{with .ScheduledFor}} &middot; planned for {{.}}{{end}}{{with .CompletedAt}} &middot; completed {{date .}}{{end}}</p>
+<div class="bar"><span style="width: {{.ProgressPercent}}%"></span></div>
+{{with .Description}}<p class="description">{{.}}</p>{{end}}
+<p class="meta">Updated {{date .UpdatedAt}} &middot; this link expires {{date .ExpiresAt}}</p>
+</body>
+</html>
+`))
+
+// RenderSharedTodoHTML writes t as the self-contained HTML page a share
+// link shows.
+func RenderSharedTodoHTML(w io.Writer, t model.SharedTodo) error {
+	return sharedTodoTemplate.Execute(w, t)
+}
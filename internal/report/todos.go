@@ -0,0 +1,119 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Ways to group a TODO report.
+const (
+	GroupByProject  = "project"
+	GroupByCategory = "category"
+)
+
+// TodoReport is a printable summary of TODOs in groups, for weekly reviews.
+type TodoReport struct {
+	Generated time.Time
+	GroupBy   string
+	Groups    []TodoGroup
+	Total     int
+	Done      int
+}
+
+// TodoGroup holds the TODOs of one project or category.
+type TodoGroup struct {
+	Name  string
+	Todos []model.Todo
+	Done  int
+	// Progress is the mean progress of the group's TODOs, counting done
+	// ones as complete, in percent.
+	Progress int
+}
+
+// GroupTodos groups todos by project, named by projects, or by category.
+// Groups are sorted by name, with TODOs outside any project last; TODOs keep
+// their order within a group.
+func GroupTodos(todos []model.Todo, groupBy string, projects map[int64]string, now time.Time) TodoReport {
+	r := TodoReport{Generated: now, GroupBy: groupBy, Total: len(todos)}
+
+	byName := map[string]*TodoGroup{}
+	var names []string
+	for _, t := range todos {
+		name := string(t.Category)
+		if groupBy == GroupByProject {
+			name = ""
+			if t.ProjectID != nil {
+				name = projects[*t.ProjectID]
+			}
+		}
+		g, ok := byName[name]
+		if !ok {
+			g = &TodoGroup{Name: name}
+			byName[name] = g
+			names = append(names, name)
+		}
+		g.Todos = append(g.Todos, t)
+		if t.Status == model.StatusDone {
+			g.Done++
+			r.Done++
+		}
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if (a == "") != (b == "") {
+			return cmp.Compare(b, a) // "" last
+		}
+		return cmp.Compare(a, b)
+	})
+	for _, name := range names {
+		g := byName[name]
+		total := 0
+		for _, t := range g.Todos {
+			total += progress(t)
+		}
+		g.Progress = total / len(g.Todos)
+		if g.Name == "" {
+			g.Name = "No project"
+		}
+		r.Groups = append(r.Groups, *g)
+	}
+	return r
+}
+
+// RenderTodos writes r as an HTML or, if format is "pdf", a PDF document.
+func RenderTodos(w io.Writer, r TodoReport, format string) error {
+	if format == "pdf" {
+		return RenderTodosPDF(w, r)
+	}
+	return RenderTodosHTML(w, r)
+}
+
+// ContentType returns the media type of a report in format: html, pdf, or
+// json.
+func ContentType(format string) string {
+	switch format {
+	case "pdf":
+		return "application/pdf"
+	case "json":
+		return "application/json"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Filename names a report document generated at now, such as
+// todos-2026-02-16.pdf.
+func Filename(report, format string, now time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", report, now.UTC().Format(model.DateLayout), format)
+}
+
+func progress(t model.Todo) int {
+	if t.Status == model.StatusDone {
+		return 100
+	}
+	return t.ProgressPercent
+}
@@ -0,0 +1,115 @@
+// Package sandbox keeps a separate dataset that client developers can change
+// freely, reset to sample data on a schedule.
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+)
+
+// DefaultPath is where the sandbox database is kept unless told otherwise.
+const DefaultPath = "./data/sandbox.db"
+
+// Actor attributes the sample data in the audit log.
+const Actor = "system:sandbox"
+
+// Sandbox is a repository holding sample data that is reset periodically.
+type Sandbox struct {
+	repo   *db.SQLite
+	seed   string
+	logger *slog.Logger
+}
+
+// Open creates a fresh sandbox database at path, replacing any left from an
+// earlier run, fills it with sample data, and keeps a snapshot of that data
+// next to it to reset to.
+func Open(path string, logger *slog.Logger) (*Sandbox, error) {
+	seed := path + ".seed"
+	for _, p := range []string{path, path + "-wal", path + "-shm", seed} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("remove old sandbox: %w", err)
+		}
+	}
+
+	repo, err := db.New(path, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := fill(repo); err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("fill sandbox: %w", err)
+	}
+	if err := repo.Snapshot(seed); err != nil {
+		repo.Close()
+		return nil, err
+	}
+
+	return &Sandbox{repo: repo, seed: seed, logger: logger}, nil
+}
+
+// Repository returns the sandbox repository.
+func (s *Sandbox) Repository() *db.SQLite {
+	return s.repo
+}
+
+// Close closes the sandbox database. The files are kept until the next Open.
+func (s *Sandbox) Close() error {
+	return s.repo.Close()
+}
+
+// Reset discards every change made to the sandbox since it was opened.
+func (s *Sandbox) Reset() error {
+	return s.repo.Restore(s.seed)
+}
+
+// Run resets the sandbox on every tick of the interval aligned to the clock,
+// such as on the hour for 1h, until ctx is canceled.
+func (s *Sandbox) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("sandbox started", slog.Duration("reset_interval", interval))
+
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(interval).Add(interval).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.Reset(); err != nil {
+			s.logger.Error("failed to reset sandbox", slog.String("error", err.Error()))
+			continue
+		}
+		s.logger.Info("reset sandbox")
+	}
+}
+
+// fill adds a small project and a few TODOs in every status, so clients
+// have something to list and change right away.
+func fill(repo db.Repository) error {
+	project, err := repo.CreateProject(model.CreateProjectRequest{Name: "Sample project", Description: "Sandbox data; reset periodically"})
+	if err != nil {
+		return err
+	}
+
+	progress := 40
+	todos := []model.CreateTodoRequest{
+		{Title: "Try the API", Description: "Create, update, and delete TODOs here freely.", ProjectID: &project.ID},
+		{Title: "Read the docs", Status: model.StatusInProgress, ProgressPercent: &progress, ProjectID: &project.ID},
+		{Title: "Get a sandbox", Status: model.StatusDone, Category: "work"},
+	}
+	info := db.AuditInfo{Actor: Actor, OperationID: db.NewOperationID()}
+	for _, req := range todos {
+		if _, err := repo.CreateTodo(req, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
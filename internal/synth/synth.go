@@ -0,0 +1,177 @@
+// Package synth generates synthetic TODOs whose statuses, priorities, dates,
+// and text lengths are spread like real ones, to fill databases for
+// benchmarks.
+package synth
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"todo-service/internal/model"
+)
+
+// Todo is a synthetic TODO: the request creating it and, if it is planned,
+// the day to schedule it for.
+type Todo struct {
+	Request      model.CreateTodoRequest
+	ScheduledFor string
+}
+
+// Generator makes synthetic TODOs. The same seed and time give the same
+// TODOs. A Generator is not safe for concurrent use.
+type Generator struct {
+	rand *rand.Rand
+	now  time.Time
+}
+
+// New returns a generator seeded with seed that dates TODOs around now.
+func New(seed uint64, now time.Time) *Generator {
+	return &Generator{rand: rand.New(rand.NewPCG(seed, seed>>32|1)), now: now}
+}
+
+// Todo returns the next synthetic TODO. About half are pending, a fifth in
+// progress, and the rest done; most have a short title, many no
+// description, and a few a very long one. Most have no priority and few are
+// urgent. Done TODOs are due and planned in the past, open ones mostly in
+// the coming weeks, with some overdue.
+func (g *Generator) Todo() Todo {
+	var t Todo
+	req := &t.Request
+	req.Title = g.title()
+	req.Description = g.description()
+	req.Category = pick(g, categories, categoryWeights)
+	req.Priority = pick(g, priorities, priorityWeights)
+
+	req.Status = pick(g, statuses, statusWeights)
+	switch req.Status {
+	case model.StatusInProgress:
+		progress := 5 * (2 + g.rand.IntN(17))
+		req.ProgressPercent = &progress
+	case model.StatusDone:
+		progress := 100
+		req.ProgressPercent = &progress
+	}
+
+	if g.rand.IntN(100) < 60 {
+		estimate := pick(g, estimates, estimateWeights)
+		req.EstimateMinutes = &estimate
+	}
+
+	if g.rand.IntN(100) < 35 {
+		due := g.day(req.Status, 60)
+		req.DueDate = &due
+	}
+	if g.rand.IntN(100) < 30 {
+		t.ScheduledFor = g.day(req.Status, 30)
+	}
+	return t
+}
+
+// day returns a day for a TODO with status to be due or planned on: within
+// the last two months if it is done, and otherwise within the next span
+// days or, a fifth of the time, the last two weeks.
+func (g *Generator) day(status model.Status, span int) string {
+	days := g.rand.IntN(span)
+	if status == model.StatusDone {
+		days = -1 - g.rand.IntN(60)
+	} else if g.rand.IntN(100) < 20 {
+		days = -1 - g.rand.IntN(14)
+	}
+	return g.now.AddDate(0, 0, days).Format(time.DateOnly)
+}
+
+// Update returns a synthetic change to a TODO's text, category, priority,
+// or estimate. It leaves status and progress alone, so it applies under any
+// status rules.
+func (g *Generator) Update() model.UpdateTodoRequest {
+	var req model.UpdateTodoRequest
+	switch g.rand.IntN(5) {
+	case 0:
+		title := g.title()
+		req.Title = &title
+	case 1:
+		description := g.description()
+		req.Description = &description
+	case 2:
+		category := pick(g, categories, categoryWeights)
+		req.Category = &category
+	case 3:
+		estimate := pick(g, estimates, estimateWeights)
+		req.EstimateMinutes = &estimate
+	case 4:
+		priority := pick(g, priorities, priorityWeights)
+		req.Priority = &priority
+	}
+	return req
+}
+
+var (
+	statuses        = []model.Status{model.StatusPending, model.StatusInProgress, model.StatusDone}
+	statusWeights   = []int{45, 20, 35}
+	categories      = []model.Category{model.CategoryPersonal, model.CategoryWork, model.CategoryOther}
+	categoryWeights = []int{45, 45, 10}
+	priorities      = model.Priorities
+	priorityWeights = []int{45, 20, 20, 11, 4}
+	estimates       = []int{5, 10, 15, 30, 45, 60, 90, 120, 240, 480}
+	estimateWeights = []int{10, 15, 20, 20, 10, 10, 6, 5, 3, 1}
+
+	verbs   = []string{"Buy", "Call", "Email", "Fix", "Review", "Write", "Plan", "Book", "Clean", "Update", "Prepare", "Check", "Send", "Schedule", "Renew", "Read", "Draft", "Pay", "Cancel", "Organize"}
+	objects = []string{"groceries", "the dentist", "quarterly report", "kitchen sink", "pull request", "blog post", "team offsite", "flights", "garage", "dependencies", "slides", "tire pressure", "invoice", "one-on-one", "passport", "design doc", "proposal", "electricity bill", "gym membership", "bookshelf", "release notes", "budget", "birthday present", "car insurance", "backlog"}
+	tails   = []string{"before Friday", "for the client", "with Sam", "this weekend", "for next sprint", "at the office", "again", "before the trip", "for mom", "and follow up"}
+	words   = []string{"the", "a", "and", "to", "of", "with", "for", "on", "after", "before", "check", "make", "sure", "list", "notes", "meeting", "call", "team", "draft", "final", "version", "budget", "details", "remember", "ask", "about", "price", "options", "deadline", "friday", "morning", "store", "order", "account", "update", "email", "send", "review", "changes", "plan"}
+)
+
+// title returns a short imperative phrase, sometimes with a qualifier.
+func (g *Generator) title() string {
+	title := verbs[g.rand.IntN(len(verbs))] + " " + objects[g.rand.IntN(len(objects))]
+	if g.rand.IntN(100) < 35 {
+		title += " " + tails[g.rand.IntN(len(tails))]
+	}
+	return title
+}
+
+// description returns nothing 40% of the time and otherwise a few
+// sentences, their count log-normally spread so that some run long.
+func (g *Generator) description() string {
+	if g.rand.IntN(100) < 40 {
+		return ""
+	}
+	sentences := min(int(math.Exp(g.rand.NormFloat64()*1.1+0.7))+1, 80)
+
+	var b strings.Builder
+	for i := range sentences {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		n := 4 + g.rand.IntN(12)
+		for j := range n {
+			w := words[g.rand.IntN(len(words))]
+			if j == 0 {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			} else {
+				b.WriteByte(' ')
+			}
+			b.WriteString(w)
+		}
+		b.WriteByte('.')
+	}
+	return b.String()
+}
+
+// pick returns one of values, chosen with the matching relative weights.
+func pick[T any](g *Generator, values []T, weights []int) T {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := g.rand.IntN(total)
+	for i, w := range weights {
+		if n < w {
+			return values[i]
+		}
+		n -= w
+	}
+	return values[len(values)-1]
+}
@@ -0,0 +1,117 @@
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stage names recorded by the service.
+const (
+	StageMiddleware    = "middleware"
+	StageValidation    = "validation"
+	StageDB            = "db"
+	StageSerialization = "serialization"
+)
+
+// Stage is a named slice of time spent handling a request.
+type Stage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Recorder collects per-stage timings for a single request.
+type Recorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	stages []Stage
+}
+
+// NewRecorder creates a Recorder whose clock starts now.
+func NewRecorder() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+// Add records d against the named stage. Repeated stages are accumulated.
+func (r *Recorder) Add(name string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.stages {
+		if r.stages[i].Name == name {
+			r.stages[i].Duration += d
+			return
+		}
+	}
+	r.stages = append(r.stages, Stage{Name: name, Duration: d})
+}
+
+// Elapsed returns the time since the recorder was created.
+func (r *Recorder) Elapsed() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return time.Since(r.start)
+}
+
+// Stages returns a copy of the recorded stages in the order first seen.
+func (r *Recorder) Stages() []Stage {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stages := make([]Stage, len(r.stages))
+	copy(stages, r.stages)
+	return stages
+}
+
+// Header formats the recorded stages plus the elapsed total as a
+// Server-Timing header value.
+func (r *Recorder) Header() string {
+	stages := r.Stages()
+	parts := make([]string, 0, len(stages)+1)
+	for _, s := range stages {
+		parts = append(parts, formatMetric(s.Name, s.Duration))
+	}
+	parts = append(parts, formatMetric("total", r.Elapsed()))
+	return strings.Join(parts, ", ")
+}
+
+func formatMetric(name string, d time.Duration) string {
+	return fmt.Sprintf("%s;dur=%.3f", name, float64(d.Microseconds())/1000.0)
+}
+
+type contextKey struct{}
+
+// WithRecorder returns a copy of ctx carrying rec.
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, rec)
+}
+
+// FromContext returns the Recorder stored in ctx, or nil if there is none.
+// All Recorder methods are safe to call on a nil receiver.
+func FromContext(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(contextKey{}).(*Recorder)
+	return rec
+}
+
+// Track starts timing the named stage and returns a func that stops it.
+//
+//	defer timing.Track(ctx, timing.StageDB)()
+func Track(ctx context.Context, name string) func() {
+	rec := FromContext(ctx)
+	if rec == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		rec.Add(name, time.Since(start))
+	}
+}
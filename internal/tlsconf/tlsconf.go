@@ -0,0 +1,103 @@
+// Package tlsconf configures HTTPS for the server, from certificate files or
+// from certificates obtained automatically from Let's Encrypt over ACME.
+package tlsconf
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"todo-service/internal/listen"
+)
+
+// Config selects where the server's certificate comes from. At most one of
+// the certificate files and the autocert hosts may be set; if neither is,
+// the server speaks plain HTTP.
+type Config struct {
+	// CertFile and KeyFile are PEM files holding the certificate chain and
+	// its private key. They are read once at startup.
+	CertFile string
+	KeyFile  string
+
+	// Hosts are the hostnames to obtain certificates for automatically.
+	// The ACME challenge is answered on the HTTPS listener when it is on
+	// port 443, and on the plain HTTP listener when that is on port 80.
+	Hosts []string
+	// CacheDir is where automatic certificates are kept across restarts.
+	CacheDir string
+	// Email is given to the certificate authority for expiry notices. It
+	// may be empty.
+	Email string
+}
+
+// Enabled reports whether c serves HTTPS.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || len(c.Hosts) > 0
+}
+
+// Setup returns the TLS configuration for the HTTPS server and the handler
+// for the plain HTTP listener, which redirects every request to the HTTPS
+// listener on httpsAddr and, with automatic certificates, answers ACME
+// challenges first. If httpsAddr is not a TCP address, such as a Unix
+// socket behind a proxy, requests are redirected to port 443.
+func (c Config) Setup(httpsAddr string) (*tls.Config, http.Handler, error) {
+	port := "443"
+	if listen.IsTCP(httpsAddr) {
+		_, port, _ = net.SplitHostPort(httpsAddr)
+	}
+	redirect := Redirect(port)
+
+	switch {
+	case len(c.Hosts) > 0 && (c.CertFile != "" || c.KeyFile != ""):
+		return nil, nil, errors.New("use either certificate files or automatic certificates, not both")
+	case len(c.Hosts) > 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(c.Hosts...),
+			Cache:      autocert.DirCache(c.CacheDir),
+			Email:      c.Email,
+		}
+		cfg := m.TLSConfig()
+		cfg.MinVersion = tls.VersionTLS12
+		return cfg, m.HTTPHandler(redirect), nil
+	case c.CertFile == "" || c.KeyFile == "":
+		return nil, nil, errors.New("a certificate file and a key file are both required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	return cfg, redirect, nil
+}
+
+// Redirect returns a handler that permanently redirects requests to the
+// same host and path over HTTPS on port, which is left out of the URL if it
+// is the default 443. It keeps the method, so API clients that post to the
+// plain HTTP address are redirected too.
+func Redirect(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]") // a bare IPv6 address
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
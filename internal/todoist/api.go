@@ -0,0 +1,150 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// APIURL is the base URL of the Todoist API.
+const APIURL = "https://api.todoist.com/api/v1"
+
+// ErrUnauthorized is returned when Todoist rejects an API token.
+var ErrUnauthorized = errors.New("todoist rejected the API token")
+
+// Client reads tasks from the Todoist API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a Client for the Todoist API at baseURL, normally
+// APIURL.
+func NewClient(baseURL string) *Client {
+	return &Client{baseURL: baseURL, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type apiProject struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Inbox bool   `json:"inbox_project"`
+}
+
+type apiTask struct {
+	Content     string `json:"content"`
+	Description string `json:"description"`
+	ProjectID   string `json:"project_id"`
+	Priority    int    `json:"priority"`
+	Checked     bool   `json:"checked"`
+	Due         *struct {
+		Date        string `json:"date"`
+		String      string `json:"string"`
+		IsRecurring bool   `json:"is_recurring"`
+	} `json:"due"`
+	Duration *struct {
+		Amount int    `json:"amount"`
+		Unit   string `json:"unit"`
+	} `json:"duration"`
+}
+
+// Fetch reads the active tasks of the account token belongs to.
+func (c *Client) Fetch(ctx context.Context, token string) ([]Task, error) {
+	var projects []apiProject
+	if err := c.list(ctx, token, "projects", func(page json.RawMessage) error {
+		var p []apiProject
+		err := json.Unmarshal(page, &p)
+		projects = append(projects, p...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(projects))
+	for _, p := range projects {
+		if !p.Inbox {
+			names[p.ID] = p.Name
+		}
+	}
+
+	var tasks []Task
+	err := c.list(ctx, token, "tasks", func(page json.RawMessage) error {
+		var ts []apiTask
+		if err := json.Unmarshal(page, &ts); err != nil {
+			return err
+		}
+		for _, at := range ts {
+			t := Task{
+				Project:     names[at.ProjectID],
+				Content:     at.Content,
+				Description: at.Description,
+				Priority:    4,
+				Completed:   at.Checked,
+			}
+			// The API counts priorities the other way around: 4 is p1.
+			if at.Priority >= 1 && at.Priority <= 4 {
+				t.Priority = 5 - at.Priority
+			}
+			if at.Due != nil {
+				t.Due = at.Due.String
+				t.DueDate, _ = parseDue(at.Due.Date)
+				t.Recurring = at.Due.IsRecurring
+			}
+			if at.Duration != nil && at.Duration.Amount > 0 {
+				t.DurationMinutes = durationMinutes(at.Duration.Amount, at.Duration.Unit)
+			}
+			tasks = append(tasks, t)
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+// list calls page with every page of results of a paginated API resource.
+func (c *Client) list(ctx context.Context, token, resource string, page func(json.RawMessage) error) error {
+	cursor := ""
+	for {
+		q := url.Values{"limit": {"200"}}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+resource+"?"+q.Encode(), nil)
+		if err != nil {
+			return fmt.Errorf("create todoist request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("list todoist %s: %w", resource, err)
+		}
+		var body struct {
+			Results    json.RawMessage `json:"results"`
+			NextCursor *string         `json:"next_cursor"`
+		}
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			err = ErrUnauthorized
+		case resp.StatusCode != http.StatusOK:
+			err = fmt.Errorf("list todoist %s: todoist responded %s", resource, resp.Status)
+		default:
+			if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				err = fmt.Errorf("decode todoist %s: %w", resource, err)
+			}
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if err := page(body.Results); err != nil {
+			return fmt.Errorf("decode todoist %s: %w", resource, err)
+		}
+		if body.NextCursor == nil || *body.NextCursor == "" {
+			return nil
+		}
+		cursor = *body.NextCursor
+	}
+}
@@ -0,0 +1,94 @@
+package todoist
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"todo-service/internal/db"
+	"todo-service/internal/model"
+	"todo-service/internal/validate"
+)
+
+// What Todoist projects become in an import.
+const (
+	ProjectsAsProjects   = "project"
+	ProjectsAsCategories = "category"
+)
+
+// Import creates a TODO for each task in repo, as db.Repository.ImportTodos
+// does, with Todoist projects becoming projects or, with
+// ProjectsAsCategories, categories. Priorities, due dates and durations
+// carry over as priorities, due dates and estimates. Tasks that cannot be
+// imported are reported as conflicts, and data that is lost as warnings.
+func Import(repo db.Repository, tasks []Task, projectsAs string, dryRun bool, info db.AuditInfo) (model.ImportResponse, error) {
+	var todos []db.ImportTodo
+	var conflicts, warnings []model.ImportIssue
+	for _, t := range tasks {
+		issue := func(reason string) model.ImportIssue {
+			return model.ImportIssue{Task: t.Content, Project: t.Project, Reason: reason}
+		}
+
+		title, description := strings.TrimSpace(t.Content), t.Description
+		if utf8.RuneCountInString(title) > model.MaxTitleLength {
+			title = string([]rune(title)[:model.MaxTitleLength-1]) + "…"
+			description = strings.TrimSpace(t.Content + "\n\n" + description)
+			warnings = append(warnings, issue(fmt.Sprintf("the title was shortened to %d characters; the full text starts the description", model.MaxTitleLength)))
+		}
+		it := db.ImportTodo{Todo: model.CreateTodoRequest{Title: title, Description: description, Priority: priority(t.Priority)}}
+		if t.Completed {
+			it.Todo.Status = model.StatusDone
+		}
+		if t.DurationMinutes > 0 {
+			it.Todo.EstimateMinutes = &t.DurationMinutes
+		}
+		if projectsAs == ProjectsAsCategories && t.Project != "" {
+			it.Todo.Category = model.Category(t.Project)
+		} else {
+			it.Project = t.Project
+		}
+
+		err := validate.CreateTodo(it.Todo)
+		if err == nil && it.Todo.Category != "" {
+			err = validate.CreateCategory(model.CreateCategoryRequest{Name: it.Todo.Category})
+		}
+		if err != nil {
+			conflicts = append(conflicts, issue(err.Error()))
+			continue
+		}
+
+		switch {
+		case t.DueDate != "":
+			it.Todo.DueDate = &t.DueDate
+			if t.Recurring {
+				warnings = append(warnings, issue(fmt.Sprintf("the recurring due date %q was imported as its next date only", t.Due)))
+			}
+		case t.Due != "":
+			warnings = append(warnings, issue(fmt.Sprintf("the due date %q is not a date and was not imported", t.Due)))
+		}
+		todos = append(todos, it)
+	}
+
+	result, err := repo.ImportTodos(todos, dryRun, info)
+	if err != nil {
+		return result, err
+	}
+	result.Tasks = len(tasks)
+	result.Conflicts = append(append([]model.ImportIssue{}, conflicts...), result.Conflicts...)
+	result.Warnings = append([]model.ImportIssue{}, warnings...)
+	return result, nil
+}
+
+// priority maps a Todoist priority, from 1 (p1) to 4 (p4), to a TODO's.
+// Todoist's p4 means no priority, and it has nothing below p3 to map to low.
+func priority(p int) model.Priority {
+	switch p {
+	case 1:
+		return model.PriorityUrgent
+	case 2:
+		return model.PriorityHigh
+	case 3:
+		return model.PriorityMedium
+	}
+	return model.PriorityNone
+}
@@ -0,0 +1,191 @@
+// Package todoist reads tasks from Todoist, either from its CSV exports or
+// from its API, for importing them as TODOs.
+package todoist
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is a Todoist task, flattened: subtasks are tasks of their own.
+type Task struct {
+	// Project is the name of the task's project, or "" for the inbox.
+	Project     string
+	Content     string
+	Description string
+	// Priority is the task's priority as shown in Todoist, from 1 (p1,
+	// urgent) to 4 (p4, no priority).
+	Priority int
+	// Due is the task's due date as written in Todoist, which may be
+	// natural language such as "every monday", or "" if it has none.
+	Due string
+	// DueDate is Due as a date formatted as YYYY-MM-DD, or "" if it has
+	// none or it could not be understood.
+	DueDate   string
+	Recurring bool
+	// DurationMinutes is the task's duration, or 0 if it has none.
+	DurationMinutes int
+	Completed       bool
+}
+
+// inboxProject is the name Todoist gives the project tasks go to by
+// default. Its tasks are imported without a project.
+const inboxProject = "Inbox"
+
+// ParseExport reads the tasks in a Todoist export: either a single
+// project's CSV export, whose project is named by filename, or a backup,
+// which is a ZIP archive of the CSV exports of every project.
+func ParseExport(filename string, data []byte) ([]Task, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return ParseCSV(projectName(filename), bytes.NewReader(data))
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open backup: %w", err)
+	}
+	var tasks []Task
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".csv") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		projectTasks, err := ParseCSV(projectName(f.Name), rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		tasks = append(tasks, projectTasks...)
+	}
+	return tasks, nil
+}
+
+// backupSuffix is the project ID Todoist appends to the file names in a
+// backup, such as "Work [2203306141].csv".
+var backupSuffix = regexp.MustCompile(`\s*\[\d+\]$`)
+
+// projectName derives a project's name from the name of its CSV export.
+func projectName(filename string) string {
+	name := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	name = strings.TrimSpace(backupSuffix.ReplaceAllString(name, ""))
+	if name == "." || strings.EqualFold(name, inboxProject) {
+		return ""
+	}
+	return name
+}
+
+// ParseCSV reads the tasks of project from a Todoist CSV export. Sections
+// and metadata rows are skipped, and notes are appended to the description
+// of the task before them.
+func ParseCSV(project string, r io.Reader) ([]Task, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"TYPE", "CONTENT"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("not a Todoist CSV export: missing %s column", name)
+		}
+	}
+
+	var tasks []Task
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(field("TYPE")) {
+		case "task":
+			t := Task{
+				Project:     project,
+				Content:     field("CONTENT"),
+				Description: field("DESCRIPTION"),
+				Priority:    4,
+				Due:         field("DATE"),
+			}
+			if p, err := strconv.Atoi(field("PRIORITY")); err == nil && p >= 1 && p <= 4 {
+				t.Priority = p
+			}
+			t.DueDate, t.Recurring = parseDue(t.Due)
+			if n, err := strconv.Atoi(field("DURATION")); err == nil && n > 0 {
+				t.DurationMinutes = durationMinutes(n, field("DURATION_UNIT"))
+			}
+			tasks = append(tasks, t)
+		case "note":
+			if len(tasks) > 0 && field("CONTENT") != "" {
+				t := &tasks[len(tasks)-1]
+				t.Description = strings.TrimSpace(t.Description + "\n\n" + field("CONTENT"))
+			}
+		}
+	}
+}
+
+// dueLayouts are the date formats a due date written in Todoist is
+// recognized in, after any time of day.
+var dueLayouts = []string{
+	"2006-01-02",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// parseDue returns the date of a due date written in Todoist, or "" if it
+// is not an absolute date, and whether it recurs.
+func parseDue(due string) (date string, recurring bool) {
+	s := strings.ToLower(due)
+	recurring = strings.HasPrefix(s, "every ") || strings.HasPrefix(s, "every!")
+	if len(due) >= 10 {
+		if d, err := time.Parse(time.DateOnly, due[:10]); err == nil {
+			return d.Format(time.DateOnly), recurring
+		}
+	}
+	for _, layout := range dueLayouts {
+		if d, err := time.Parse(layout, due); err == nil {
+			return d.Format(time.DateOnly), recurring
+		}
+	}
+	return "", recurring
+}
+
+// durationMinutes converts a Todoist duration to minutes. Durations in
+// days count as 8 hours per day.
+func durationMinutes(amount int, unit string) int {
+	if strings.EqualFold(unit, "day") {
+		return amount * 8 * 60
+	}
+	return amount
+}
@@ -0,0 +1,741 @@
+// Package validate checks request payloads independently of the transport
+// they arrived over, so the HTTP and gRPC APIs accept exactly the same input.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// Error reports an invalid field. Transports map it to their own
+// invalid-argument error.
+type Error struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errors lists every invalid field of a payload, so that clients can fix
+// them all at once instead of one request at a time.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *Errors) add(field, message string) {
+	*e = append(*e, &Error{Field: field, Message: message})
+}
+
+// merge adds the fields reported by err, as returned by another check.
+func (e *Errors) merge(err error) {
+	var errs Errors
+	if errors.As(err, &errs) {
+		*e = append(*e, errs...)
+	}
+}
+
+// err returns e, or nil if no field is invalid.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// CreateTodo checks a create payload.
+func CreateTodo(req model.CreateTodoRequest) error {
+	var errs Errors
+	if req.Title == "" {
+		errs.add("title", "title is required")
+	}
+	errs.text("title", req.Title, model.MaxTitleLength)
+	errs.text("description", req.Description, model.MaxDescriptionLength)
+
+	errs.merge(Filter(req.Status))
+
+	if req.ProjectID != nil && *req.ProjectID <= 0 {
+		errs.add("project_id", "project_id must be a positive project ID")
+	}
+
+	errs.merge(Priority(req.Priority))
+	if req.DueDate != nil {
+		errs.merge(Date("due_date", *req.DueDate))
+	}
+
+	errs.estimate(req.EstimateMinutes)
+	errs.progress(req.ProgressPercent)
+	if req.Color != "" {
+		errs.color(req.Color)
+	}
+	if req.Icon != "" {
+		errs.icon(req.Icon)
+	}
+	if req.Location != nil {
+		errs.location("location.", *req.Location)
+	}
+	return errs.err()
+}
+
+// UpdateTodo checks an update payload.
+func UpdateTodo(req model.UpdateTodoRequest) error {
+	var errs Errors
+	if req.Title != nil {
+		errs.text("title", *req.Title, model.MaxTitleLength)
+	}
+	if req.Description != nil {
+		errs.text("description", *req.Description, model.MaxDescriptionLength)
+	}
+
+	if req.Status != nil && *req.Status == "" {
+		errs.add("status", statusMessage)
+	}
+	if req.Category != nil && *req.Category == "" {
+		errs.add("category", "category must not be empty")
+	}
+	errs.merge(Filter(deref(req.Status)))
+
+	if req.ProjectID != nil && *req.ProjectID < 0 {
+		errs.add("project_id", "project_id must be a project ID, or 0 to remove the TODO from its project")
+	}
+
+	if req.Priority != nil && *req.Priority == "" {
+		errs.add("priority", priorityMessage)
+	}
+	errs.merge(Priority(deref(req.Priority)))
+	if req.DueDate != nil && *req.DueDate != "" {
+		errs.merge(Date("due_date", *req.DueDate))
+	}
+
+	errs.estimate(req.EstimateMinutes)
+	errs.progress(req.ProgressPercent)
+	if req.Color != nil && *req.Color != "" {
+		errs.color(*req.Color)
+	}
+	if req.Icon != nil && *req.Icon != "" {
+		errs.icon(*req.Icon)
+	}
+	return errs.err()
+}
+
+// Priority checks a TODO's priority. An empty value means unset.
+func Priority(p model.Priority) error {
+	var errs Errors
+	if p != "" && !model.ValidPriorities[p] {
+		errs.add("priority", priorityMessage)
+	}
+	return errs.err()
+}
+
+// CaptureTodo checks an inbox capture payload.
+func CaptureTodo(req model.CaptureTodoRequest) error {
+	var errs Errors
+	if req.Title == "" {
+		errs.add("title", "title is required")
+	}
+	errs.text("title", req.Title, model.MaxTitleLength)
+	errs.text("description", req.Description, model.MaxDescriptionLength)
+	return errs.err()
+}
+
+// TriageTodo checks a triage payload.
+func TriageTodo(req model.TriageTodoRequest) error {
+	var errs Errors
+	if req.Category == "" {
+		errs.add("category", "category is required")
+	}
+
+	if req.ProjectID != nil && *req.ProjectID < 0 {
+		errs.add("project_id", "project_id must be a project ID, or 0 to remove the TODO from its project")
+	}
+
+	errs.merge(Priority(req.Priority))
+	if req.DueDate != nil {
+		errs.merge(Date("due_date", *req.DueDate))
+	}
+	if req.ScheduledFor != nil {
+		errs.merge(Date("scheduled_for", *req.ScheduledFor))
+	}
+	return errs.err()
+}
+
+// ScheduleTodo checks a schedule payload.
+func ScheduleTodo(req model.ScheduleTodoRequest) error {
+	return Date("date", req.Date)
+}
+
+// AssignTodo checks an assign payload.
+func AssignTodo(req model.AssignTodoRequest) error {
+	var errs Errors
+	if strings.TrimSpace(req.Assignee) == "" {
+		errs.add("assignee", "assignee is required")
+	}
+	errs.text("assignee", req.Assignee, model.MaxAssigneeLength)
+	return errs.err()
+}
+
+// SnoozeTodo checks a snooze payload as of now.
+func SnoozeTodo(req model.SnoozeTodoRequest, now time.Time) error {
+	var errs Errors
+	switch {
+	case (req.Minutes != 0) == (req.Until != nil):
+		errs.add("", "exactly one of minutes or until is required")
+	case req.Minutes < 0 || time.Duration(req.Minutes)*time.Minute > model.MaxSnooze:
+		errs.add("minutes", fmt.Sprintf("minutes must be between 1 and %d", int(model.MaxSnooze/time.Minute)))
+	case req.Until != nil && !req.Until.After(now):
+		errs.add("until", "until must be in the future")
+	case req.Until != nil && req.Until.Sub(now) > model.MaxSnooze:
+		errs.add("until", "until must be at most a year from now")
+	}
+	return errs.err()
+}
+
+// LocateTodo checks a locate payload.
+func LocateTodo(req model.Location) error {
+	var errs Errors
+	errs.location("", req)
+	return errs.err()
+}
+
+// Near checks the point a TODO list is filtered by the distance from.
+func Near(point model.Location) error {
+	var errs Errors
+	if !latitude(point.Latitude) || !longitude(point.Longitude) {
+		errs.add("near", "near must be a latitude between -90 and 90 and a longitude between -180 and 180")
+	}
+	return errs.err()
+}
+
+// MoveTodo checks a move payload.
+func MoveTodo(req model.MoveTodoRequest) error {
+	var errs Errors
+	set := 0
+	for _, ok := range []bool{req.Before != nil, req.After != nil, req.Index != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		errs.add("", "exactly one of before, after, or index is required")
+	}
+	if req.Index != nil && *req.Index < 0 {
+		errs.add("index", "index must not be negative")
+	}
+	return errs.err()
+}
+
+// MoveCard checks a board move payload.
+func MoveCard(req model.MoveCardRequest) error {
+	var errs Errors
+	if req.TodoID <= 0 {
+		errs.add("todo_id", "todo_id must be a positive TODO ID")
+	}
+	if !model.ValidStatuses[req.Status] {
+		errs.add("status", statusMessage)
+	}
+	if place, ok := req.Place(); ok {
+		errs.merge(MoveTodo(place))
+	}
+	return errs.err()
+}
+
+// Date checks that s is a calendar date formatted as model.DateLayout.
+func Date(field, s string) error {
+	var errs Errors
+	if _, err := time.Parse(model.DateLayout, s); err != nil {
+		errs.add(field, field+" must be a date formatted as YYYY-MM-DD")
+	}
+	return errs.err()
+}
+
+// Month checks that s is a month formatted as model.MonthLayout.
+func Month(field, s string) error {
+	var errs Errors
+	if _, err := time.Parse(model.MonthLayout, s); err != nil {
+		errs.add(field, field+" must be a month formatted as YYYY-MM")
+	}
+	return errs.err()
+}
+
+// CreateProject checks a project create payload.
+func CreateProject(req model.CreateProjectRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	if req.Color != "" {
+		errs.color(req.Color)
+	}
+	if req.Icon != "" {
+		errs.icon(req.Icon)
+	}
+	return errs.err()
+}
+
+// UpdateProject checks a project update payload.
+func UpdateProject(req model.UpdateProjectRequest) error {
+	var errs Errors
+	if req.Name != nil && *req.Name == "" {
+		errs.add("name", "name must not be empty")
+	}
+	if req.Color != nil && *req.Color != "" {
+		errs.color(*req.Color)
+	}
+	if req.Icon != nil && *req.Icon != "" {
+		errs.icon(*req.Icon)
+	}
+	return errs.err()
+}
+
+// CreateCategory checks a category create payload.
+func CreateCategory(req model.CreateCategoryRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	errs.text("name", string(req.Name), model.MaxCategoryNameLength)
+	if req.Color != "" {
+		errs.color(req.Color)
+	}
+	if req.Icon != "" {
+		errs.icon(req.Icon)
+	}
+	return errs.err()
+}
+
+// UpdateCategory checks a category update payload.
+func UpdateCategory(req model.UpdateCategoryRequest) error {
+	var errs Errors
+	if req.Name != nil {
+		if *req.Name == "" {
+			errs.add("name", "name must not be empty")
+		}
+		errs.text("name", string(*req.Name), model.MaxCategoryNameLength)
+	}
+	if req.Color != nil && *req.Color != "" {
+		errs.color(*req.Color)
+	}
+	if req.Icon != nil && *req.Icon != "" {
+		errs.icon(*req.Icon)
+	}
+	return errs.err()
+}
+
+// CreateCustomField checks a custom field create payload.
+func CreateCustomField(req model.CreateCustomFieldRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	} else if !customFieldNamePattern.MatchString(req.Name) {
+		errs.add("name", "name must be lowercase letters, digits, and underscores, starting with a letter")
+	}
+	errs.text("name", req.Name, model.MaxCustomFieldNameLength)
+	if !slices.Contains(model.CustomFieldTypes, req.Type) {
+		errs.add("type", "type must be one of: text, number, date, enum")
+	}
+	switch {
+	case req.Type == model.CustomFieldEnum:
+		errs.options(req.Options)
+	case len(req.Options) > 0:
+		errs.add("options", "only enum fields have options")
+	}
+	return errs.err()
+}
+
+// UpdateCustomField checks a custom field update payload.
+func UpdateCustomField(req model.UpdateCustomFieldRequest) error {
+	var errs Errors
+	if req.Options != nil {
+		errs.options(*req.Options)
+	}
+	return errs.err()
+}
+
+// CreateView checks a view create payload. The filter's sort is checked by
+// the caller, which knows the sortable fields.
+func CreateView(req model.CreateViewRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	errs.text("name", req.Name, model.MaxViewNameLength)
+	errs.viewFilter(req.Filter)
+	return errs.err()
+}
+
+// UpdateView checks a view update payload.
+func UpdateView(req model.UpdateViewRequest) error {
+	var errs Errors
+	if req.Name != nil {
+		if *req.Name == "" {
+			errs.add("name", "name must not be empty")
+		}
+		errs.text("name", *req.Name, model.MaxViewNameLength)
+	}
+	if req.Filter != nil {
+		errs.viewFilter(*req.Filter)
+	}
+	return errs.err()
+}
+
+// ReportSubscription checks a report subscription create or replace
+// payload. format and group may be empty for the report's defaults.
+func ReportSubscription(req model.ReportSubscriptionRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	errs.text("name", req.Name, model.MaxSubscriptionNameLength)
+
+	formats, ok := model.SubscriptionFormats[req.Report]
+	switch {
+	case !ok:
+		errs.add("report", "report must be one of: todos, burndown")
+	case req.Format != "" && !slices.Contains(formats, req.Format):
+		errs.add("format", fmt.Sprintf("format must be one of: %s", strings.Join(formats, ", ")))
+	}
+	if req.Group != "" {
+		if req.Report != model.SubscriptionTodos {
+			errs.add("group", fmt.Sprintf("group is only allowed with report %s", model.SubscriptionTodos))
+		} else if req.Group != "project" && req.Group != "category" {
+			errs.add("group", "group must be one of: project, category")
+		}
+	}
+	if !model.ValidSubscriptionSchedules[req.Schedule] {
+		errs.add("schedule", "schedule must be one of: daily, weekly")
+	}
+
+	errs.text("webhook_url", req.WebhookURL, model.MaxWebhookURLLength)
+	if u, err := url.Parse(req.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add("webhook_url", "webhook_url must be an absolute http or https URL")
+	}
+	return errs.err()
+}
+
+// NotificationRoute checks a notification route create or replace payload.
+// The template must execute against a sample message, so that mistakes such
+// as misspelled fields are reported now rather than on the first event.
+func NotificationRoute(req model.NotificationRouteRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	errs.text("name", req.Name, model.MaxNotificationRouteNameLength)
+
+	if !model.ValidNotificationChannels[req.Channel] {
+		errs.add("channel", "channel must be one of: slack, discord")
+	}
+
+	errs.text("webhook_url", req.WebhookURL, model.MaxWebhookURLLength)
+	if u, err := url.Parse(req.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add("webhook_url", "webhook_url must be an absolute http or https URL")
+	}
+
+	if len(req.Events) == 0 {
+		errs.add("events", "events must list at least one event")
+	}
+	for i, e := range req.Events {
+		switch {
+		case !slices.Contains(model.NotificationEvents, e):
+			errs.add(fmt.Sprintf("events[%d]", i), "events must be among: created, updated, completed, assigned, woke, deleted, overdue")
+		case slices.Contains(req.Events[:i], e):
+			errs.add(fmt.Sprintf("events[%d]", i), fmt.Sprintf("event %s is listed twice", e))
+		}
+	}
+
+	errs.text("template", req.Template, model.MaxNotificationTemplateLength)
+	if req.Template != "" {
+		if err := checkTemplate(req.Template); err != nil {
+			errs.add("template", "template is invalid: "+err.Error())
+		}
+	}
+	return errs.err()
+}
+
+// checkTemplate parses text and executes it with a sample message.
+func checkTemplate(text string) error {
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+	scheduled := "2026-02-16"
+	sample := model.NotificationMessage{
+		Event:   model.EventCompleted,
+		Todo:    model.Todo{ID: 1, Title: "Buy groceries", Status: model.StatusDone, ProgressPercent: 100, ScheduledFor: &scheduled},
+		Actor:   "127.0.0.1",
+		Changes: map[string]model.FieldChange{"status": {Old: "in_progress", New: "done"}},
+	}
+	return tmpl.Execute(io.Discard, sample)
+}
+
+// Action checks an action create or replace payload: a filter only goes
+// with an operation that picks a TODO, and a todo, which is checked as a
+// create payload, only with create.
+func Action(req model.ActionRequest) error {
+	var errs Errors
+	if req.Name == "" {
+		errs.add("name", "name is required")
+	}
+	errs.text("name", req.Name, model.MaxActionNameLength)
+
+	switch req.Operation {
+	case model.ActionCompleteFirst, model.ActionStartFirst:
+		if req.Todo != nil {
+			errs.add("todo", fmt.Sprintf("todo is only allowed with operation %s", model.ActionCreate))
+		}
+		if req.Filter != nil {
+			errs.viewFilter(*req.Filter)
+		}
+	case model.ActionCreate:
+		if req.Filter != nil {
+			errs.add("filter", fmt.Sprintf("filter is not allowed with operation %s", model.ActionCreate))
+		}
+		if req.Todo == nil {
+			errs.add("todo", fmt.Sprintf("todo is required with operation %s", model.ActionCreate))
+			break
+		}
+		var todo Errors
+		todo.merge(CreateTodo(*req.Todo))
+		for _, e := range todo {
+			errs.add("todo."+e.Field, e.Message)
+		}
+	default:
+		errs.add("operation", "operation must be one of: complete_first, start_first, create")
+	}
+	return errs.err()
+}
+
+// Sync checks a sync payload: each change carries the payload its op
+// needs, checked as a create or update payload, and nothing else.
+func Sync(req model.SyncRequest) error {
+	var errs Errors
+	if req.Strategy != "" && !model.ValidSyncStrategies[req.Strategy] {
+		errs.add("strategy", "strategy must be one of: last_writer_wins, merge")
+	}
+	refs := map[string]int{}
+	for i, c := range req.Changes {
+		field := fmt.Sprintf("changes[%d]", i)
+		errs.text(field+".ref", c.Ref, model.MaxSyncRefLength)
+		if j, ok := refs[c.Ref]; ok && c.Ref != "" {
+			errs.add(field+".ref", fmt.Sprintf("%s.ref repeats changes[%d].ref", field, j))
+		}
+		refs[c.Ref] = i
+		if c.ChangedAt.IsZero() {
+			errs.add(field+".changed_at", field+".changed_at is required")
+		}
+
+		var payload Errors
+		switch c.Op {
+		case model.SyncCreate:
+			if c.TodoID != 0 || c.BaseVersion != 0 || c.Update != nil {
+				errs.add(field, fmt.Sprintf("%s: only create is allowed with op %s", field, model.SyncCreate))
+			}
+			if c.Create == nil {
+				errs.add(field+".create", fmt.Sprintf("%s.create is required with op %s", field, model.SyncCreate))
+				continue
+			}
+			payload.merge(CreateTodo(*c.Create))
+			for _, e := range payload {
+				errs.add(field+".create."+e.Field, e.Message)
+			}
+		case model.SyncUpdate, model.SyncDelete:
+			if c.TodoID <= 0 {
+				errs.add(field+".todo_id", fmt.Sprintf("%s.todo_id is required with op %s", field, c.Op))
+			}
+			if c.BaseVersion < 0 {
+				errs.add(field+".base_version", fmt.Sprintf("%s.base_version must not be negative", field))
+			}
+			if c.Create != nil {
+				errs.add(field+".create", fmt.Sprintf("%s.create is only allowed with op %s", field, model.SyncCreate))
+			}
+			if c.Op == model.SyncDelete {
+				if c.Update != nil {
+					errs.add(field+".update", fmt.Sprintf("%s.update is only allowed with op %s", field, model.SyncUpdate))
+				}
+				continue
+			}
+			if c.Update == nil {
+				errs.add(field+".update", fmt.Sprintf("%s.update is required with op %s", field, model.SyncUpdate))
+				continue
+			}
+			payload.merge(UpdateTodo(*c.Update))
+			for _, e := range payload {
+				errs.add(field+".update."+e.Field, e.Message)
+			}
+		default:
+			errs.add(field+".op", field+".op must be one of: create, update, delete")
+		}
+	}
+	return errs.err()
+}
+
+// Filter checks the status a TODO list is filtered by. An empty status means
+// no filter. Categories are not checked: filtering by one that does not exist
+// matches nothing.
+func Filter(status model.Status) error {
+	var errs Errors
+	if status != "" && !model.ValidStatuses[status] {
+		errs.add("status", statusMessage)
+	}
+	return errs.err()
+}
+
+// Page checks pagination parameters. The HTTP API enforces the same bounds
+// through its schema.
+func Page(limit, offset int) error {
+	var errs Errors
+	if limit < 0 || limit > query.MaxLimit {
+		errs.add("limit", fmt.Sprintf("limit must be between 0 and %d", query.MaxLimit))
+	}
+	if offset < 0 {
+		errs.add("offset", "offset must not be negative")
+	}
+	return errs.err()
+}
+
+const (
+	statusMessage   = "status must be one of: pending, in_progress, done"
+	priorityMessage = "priority must be one of: none, low, medium, high, urgent"
+)
+
+var colorPattern = regexp.MustCompile(model.ColorPattern)
+
+var customFieldNamePattern = regexp.MustCompile(model.CustomFieldNamePattern)
+
+func (e *Errors) progress(p *int) {
+	if p != nil && (*p < 0 || *p > 100) {
+		e.add("progress_percent", "progress_percent must be between 0 and 100")
+	}
+}
+
+func (e *Errors) estimate(m *int) {
+	if m != nil && *m < 0 {
+		e.add("estimate_minutes", "estimate_minutes must not be negative")
+	}
+}
+
+func (e *Errors) color(c string) {
+	if !colorPattern.MatchString(c) {
+		e.add("color", "color must be formatted as #rrggbb")
+	}
+}
+
+func (e *Errors) icon(name string) {
+	if !slices.Contains(model.Icons, name) {
+		e.add("icon", "icon must be one of: "+strings.Join(model.Icons, ", "))
+	}
+}
+
+// location checks a TODO's location, naming its fields with prefix.
+func (e *Errors) location(prefix string, loc model.Location) {
+	if !latitude(loc.Latitude) {
+		e.add(prefix+"latitude", "latitude must be between -90 and 90")
+	}
+	if !longitude(loc.Longitude) {
+		e.add(prefix+"longitude", "longitude must be between -180 and 180")
+	}
+	e.text(prefix+"place", loc.Place, model.MaxPlaceLength)
+}
+
+// latitude and longitude report whether a coordinate is in range. NaN is
+// not.
+func latitude(deg float64) bool  { return deg >= -90 && deg <= 90 }
+func longitude(deg float64) bool { return deg >= -180 && deg <= 180 }
+
+// options checks the options of an enum custom field.
+func (e *Errors) options(options []string) {
+	if len(options) == 0 {
+		e.add("options", "enum fields need at least one option")
+	}
+	if len(options) > model.MaxCustomFieldOptions {
+		e.add("options", fmt.Sprintf("enum fields can have at most %d options", model.MaxCustomFieldOptions))
+	}
+	for i, option := range options {
+		field := fmt.Sprintf("options[%d]", i)
+		if option == "" {
+			e.add(field, "options must not be empty")
+		}
+		e.text(field, option, model.MaxCustomFieldOptionLength)
+		if slices.Index(options, option) < i {
+			e.add(field, fmt.Sprintf("option %q is listed twice", option))
+		}
+	}
+}
+
+func (e *Errors) viewFilter(f model.ViewFilter) {
+	for _, s := range f.Statuses {
+		if !model.ValidStatuses[s] {
+			e.add("filter.statuses", "filter.statuses must each be one of: pending, in_progress, done")
+			break
+		}
+	}
+	for _, c := range f.Categories {
+		if c == "" {
+			e.add("filter.categories", "filter.categories must not contain empty names")
+			break
+		}
+	}
+	for _, p := range f.Priorities {
+		if !model.ValidPriorities[p] {
+			e.add("filter.priorities", "filter.priorities must each be one of: none, low, medium, high, urgent")
+			break
+		}
+	}
+	if f.ProjectID != nil && *f.ProjectID <= 0 {
+		e.add("filter.project_id", "filter.project_id must be a positive project ID")
+	}
+	if f.Triage != "" && f.Triage != model.TriagePending && f.Triage != model.TriageDone {
+		e.add("filter.triage", "filter.triage must be one of: pending, done")
+	}
+	e.dateRange("filter.due", f.Due)
+	e.dateRange("filter.scheduled", f.Scheduled)
+	e.dateRange("filter.completed", f.Completed)
+}
+
+func (e *Errors) dateRange(field string, r *model.DateRange) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	for _, bound := range []struct{ name, value string }{{"from", r.From}, {"to", r.To}} {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := model.ResolveDate(bound.value, now); err != nil {
+			e.add(field+"."+bound.name, fmt.Sprintf("%s.%s: %v", field, bound.name, err))
+		}
+	}
+}
+
+// text checks that s is at most max characters long, counting runes as the
+// schema's maxLength does.
+func (e *Errors) text(field, s string, max int) {
+	if utf8.RuneCountInString(s) > max {
+		e.add(field, fmt.Sprintf("%s must be at most %d characters", field, max))
+	}
+}
+
+func deref[T ~string](p *T) T {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
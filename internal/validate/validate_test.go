@@ -0,0 +1,253 @@
+package validate
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-service/internal/model"
+	"todo-service/internal/query"
+)
+
+// fields returns the fields err reports as invalid, in order.
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %T %v, want Errors", err, err)
+	}
+	names := make([]string, len(errs))
+	for i, e := range errs {
+		names[i] = e.Field
+	}
+	return names
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCreateTodo(t *testing.T) {
+	valid := func() model.CreateTodoRequest { return model.CreateTodoRequest{Title: "Buy groceries"} }
+	tests := []struct {
+		name   string
+		modify func(*model.CreateTodoRequest)
+		want   []string
+	}{
+		{"minimal", func(*model.CreateTodoRequest) {}, nil},
+		{"all fields", func(r *model.CreateTodoRequest) {
+			r.Description = "Milk, eggs, bread"
+			r.Status = model.StatusInProgress
+			r.ProjectID = ptr(int64(1))
+			r.Priority = model.PriorityUrgent
+			r.DueDate = ptr("2026-02-28")
+			r.ProgressPercent = ptr(100)
+			r.EstimateMinutes = ptr(0)
+			r.Color = "#FF9800"
+			r.Icon = "cart"
+			r.Location = &model.Location{Latitude: -90, Longitude: 180, Place: "Market"}
+		}, nil},
+		{"empty title", func(r *model.CreateTodoRequest) { r.Title = "" }, []string{"title"}},
+		{"title at max length", func(r *model.CreateTodoRequest) { r.Title = strings.Repeat("a", model.MaxTitleLength) }, nil},
+		{"title over max length", func(r *model.CreateTodoRequest) { r.Title = strings.Repeat("a", model.MaxTitleLength+1) }, []string{"title"}},
+		{"multibyte title at max length", func(r *model.CreateTodoRequest) { r.Title = strings.Repeat("é", model.MaxTitleLength) }, nil},
+		{"description over max length", func(r *model.CreateTodoRequest) {
+			r.Description = strings.Repeat("a", model.MaxDescriptionLength+1)
+		}, []string{"description"}},
+		{"unknown status", func(r *model.CreateTodoRequest) { r.Status = "blocked" }, []string{"status"}},
+		{"zero project", func(r *model.CreateTodoRequest) { r.ProjectID = ptr(int64(0)) }, []string{"project_id"}},
+		{"unknown priority", func(r *model.CreateTodoRequest) { r.Priority = "critical" }, []string{"priority"}},
+		{"due date not in the calendar", func(r *model.CreateTodoRequest) { r.DueDate = ptr("2026-02-30") }, []string{"due_date"}},
+		{"empty due date", func(r *model.CreateTodoRequest) { r.DueDate = ptr("") }, []string{"due_date"}},
+		{"negative estimate", func(r *model.CreateTodoRequest) { r.EstimateMinutes = ptr(-1) }, []string{"estimate_minutes"}},
+		{"progress below 0", func(r *model.CreateTodoRequest) { r.ProgressPercent = ptr(-1) }, []string{"progress_percent"}},
+		{"progress over 100", func(r *model.CreateTodoRequest) { r.ProgressPercent = ptr(101) }, []string{"progress_percent"}},
+		{"short color", func(r *model.CreateTodoRequest) { r.Color = "#fff" }, []string{"color"}},
+		{"color without hash", func(r *model.CreateTodoRequest) { r.Color = "ff9800" }, []string{"color"}},
+		{"unknown icon", func(r *model.CreateTodoRequest) { r.Icon = "rocket" }, []string{"icon"}},
+		{"latitude out of range", func(r *model.CreateTodoRequest) {
+			r.Location = &model.Location{Latitude: 90.5}
+		}, []string{"location.latitude"}},
+		{"NaN longitude", func(r *model.CreateTodoRequest) {
+			r.Location = &model.Location{Longitude: math.NaN()}
+		}, []string{"location.longitude"}},
+		{"place over max length", func(r *model.CreateTodoRequest) {
+			r.Location = &model.Location{Place: strings.Repeat("a", model.MaxPlaceLength+1)}
+		}, []string{"location.place"}},
+		{"every field invalid", func(r *model.CreateTodoRequest) {
+			r.Title = ""
+			r.Status = "blocked"
+			r.Priority = "critical"
+			r.ProgressPercent = ptr(200)
+			r.Color = "red"
+		}, []string{"title", "status", "priority", "progress_percent", "color"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			if got := fields(t, CreateTodo(req)); !slices.Equal(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateTodo(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.UpdateTodoRequest
+		want []string
+	}{
+		{"empty", model.UpdateTodoRequest{}, nil},
+		{"empty title", model.UpdateTodoRequest{Title: ptr("")}, nil},
+		{"title over max length", model.UpdateTodoRequest{Title: ptr(strings.Repeat("a", model.MaxTitleLength+1))}, []string{"title"}},
+		{"empty status", model.UpdateTodoRequest{Status: ptr(model.Status(""))}, []string{"status"}},
+		{"unknown status", model.UpdateTodoRequest{Status: ptr(model.Status("blocked"))}, []string{"status"}},
+		{"empty category", model.UpdateTodoRequest{Category: ptr(model.Category(""))}, []string{"category"}},
+		{"remove from project", model.UpdateTodoRequest{ProjectID: ptr(int64(0))}, nil},
+		{"negative project", model.UpdateTodoRequest{ProjectID: ptr(int64(-1))}, []string{"project_id"}},
+		{"empty priority", model.UpdateTodoRequest{Priority: ptr(model.Priority(""))}, []string{"priority"}},
+		{"unknown priority", model.UpdateTodoRequest{Priority: ptr(model.Priority("critical"))}, []string{"priority"}},
+		{"set priority and due date", model.UpdateTodoRequest{Priority: ptr(model.PriorityNone), DueDate: ptr("2026-03-01")}, nil},
+		{"clear due date", model.UpdateTodoRequest{DueDate: ptr("")}, nil},
+		{"invalid due date", model.UpdateTodoRequest{DueDate: ptr("03/01/2026")}, []string{"due_date"}},
+		{"remove color and icon", model.UpdateTodoRequest{Color: ptr(""), Icon: ptr("")}, nil},
+		{"invalid color", model.UpdateTodoRequest{Color: ptr("#ggg000")}, []string{"color"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, UpdateTodo(tt.req)); !slices.Equal(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	tests := []struct {
+		date string
+		ok   bool
+	}{
+		{"2026-02-16", true},
+		{"2028-02-29", true},
+		{"2026-02-29", false},
+		{"2026-13-01", false},
+		{"2026-2-16", false},
+		{"16/02/2026", false},
+		{"2026-02-16T09:00:00Z", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			err := Date("scheduled_for", tt.date)
+			if (err == nil) != tt.ok {
+				t.Errorf("Date(%q) = %v, want ok %v", tt.date, err, tt.ok)
+			}
+			if err != nil && !slices.Equal(fields(t, err), []string{"scheduled_for"}) {
+				t.Errorf("Date(%q) reported fields %v", tt.date, fields(t, err))
+			}
+		})
+	}
+}
+
+func TestSnoozeTodo(t *testing.T) {
+	now := time.Date(2026, 2, 12, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		req  model.SnoozeTodoRequest
+		want []string
+	}{
+		{"minutes", model.SnoozeTodoRequest{Minutes: 30}, nil},
+		{"until", model.SnoozeTodoRequest{Until: ptr(now.Add(time.Hour))}, nil},
+		{"until a year away", model.SnoozeTodoRequest{Until: ptr(now.Add(model.MaxSnooze))}, nil},
+		{"neither", model.SnoozeTodoRequest{}, []string{""}},
+		{"both", model.SnoozeTodoRequest{Minutes: 30, Until: ptr(now.Add(time.Hour))}, []string{""}},
+		{"negative minutes", model.SnoozeTodoRequest{Minutes: -5}, []string{"minutes"}},
+		{"too many minutes", model.SnoozeTodoRequest{Minutes: int(model.MaxSnooze/time.Minute) + 1}, []string{"minutes"}},
+		{"until now", model.SnoozeTodoRequest{Until: ptr(now)}, []string{"until"}},
+		{"until over a year away", model.SnoozeTodoRequest{Until: ptr(now.Add(model.MaxSnooze + time.Second))}, []string{"until"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, SnoozeTodo(tt.req, now)); !slices.Equal(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveTodo(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.MoveTodoRequest
+		want []string
+	}{
+		{"before", model.MoveTodoRequest{Before: ptr(int64(2))}, nil},
+		{"first", model.MoveTodoRequest{Index: ptr(0)}, nil},
+		{"none", model.MoveTodoRequest{}, []string{""}},
+		{"before and after", model.MoveTodoRequest{Before: ptr(int64(2)), After: ptr(int64(3))}, []string{""}},
+		{"negative index", model.MoveTodoRequest{Index: ptr(-1)}, []string{"index"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, MoveTodo(tt.req)); !slices.Equal(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCustomField(t *testing.T) {
+	tooMany := make([]string, model.MaxCustomFieldOptions+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("x", i+1)
+	}
+	tests := []struct {
+		name string
+		req  model.CreateCustomFieldRequest
+		want []string
+	}{
+		{"text", model.CreateCustomFieldRequest{Name: "effort", Type: model.CustomFieldText}, nil},
+		{"enum", model.CreateCustomFieldRequest{Name: "priority", Type: model.CustomFieldEnum, Options: []string{"low", "high"}}, nil},
+		{"empty name", model.CreateCustomFieldRequest{Type: model.CustomFieldText}, []string{"name"}},
+		{"uppercase name", model.CreateCustomFieldRequest{Name: "Priority", Type: model.CustomFieldText}, []string{"name"}},
+		{"name starting with a digit", model.CreateCustomFieldRequest{Name: "1st", Type: model.CustomFieldText}, []string{"name"}},
+		{"unknown type", model.CreateCustomFieldRequest{Name: "due", Type: "datetime"}, []string{"type"}},
+		{"options on text", model.CreateCustomFieldRequest{Name: "effort", Type: model.CustomFieldText, Options: []string{"a"}}, []string{"options"}},
+		{"enum without options", model.CreateCustomFieldRequest{Name: "priority", Type: model.CustomFieldEnum}, []string{"options"}},
+		{"empty option", model.CreateCustomFieldRequest{Name: "priority", Type: model.CustomFieldEnum, Options: []string{"low", ""}}, []string{"options[1]"}},
+		{"duplicate option", model.CreateCustomFieldRequest{Name: "priority", Type: model.CustomFieldEnum, Options: []string{"low", "high", "low"}}, []string{"options[2]"}},
+		{"too many options", model.CreateCustomFieldRequest{Name: "priority", Type: model.CustomFieldEnum, Options: tooMany}, []string{"options"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, CreateCustomField(tt.req)); !slices.Equal(got, tt.want) {
+				t.Errorf("invalid fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		limit, offset int
+		want          []string
+	}{
+		{0, 0, nil},
+		{query.MaxLimit, 100, nil},
+		{-1, 0, []string{"limit"}},
+		{query.MaxLimit + 1, 0, []string{"limit"}},
+		{10, -1, []string{"offset"}},
+		{-1, -1, []string{"limit", "offset"}},
+	}
+	for _, tt := range tests {
+		if got := fields(t, Page(tt.limit, tt.offset)); !slices.Equal(got, tt.want) {
+			t.Errorf("Page(%d, %d) invalid fields %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
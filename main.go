@@ -2,10 +2,19 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/mail"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,70 +23,623 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 
+	"todo-service/internal/backup"
+	"todo-service/internal/caldav"
+	"todo-service/internal/cli"
 	"todo-service/internal/db"
+	"todo-service/internal/delivery"
+	"todo-service/internal/digest"
+	"todo-service/internal/github"
+	"todo-service/internal/grpcapi"
 	"todo-service/internal/handler"
+	"todo-service/internal/jobs"
+	"todo-service/internal/listen"
 	"todo-service/internal/logger"
 	"todo-service/internal/middleware"
+	"todo-service/internal/model"
+	"todo-service/internal/mqttbridge"
+	"todo-service/internal/notify"
+	"todo-service/internal/quota"
+	"todo-service/internal/ratelimit"
+	"todo-service/internal/sandbox"
+	"todo-service/internal/tlsconf"
+	"todo-service/internal/todoist"
 )
 
 func main() {
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch {
+	case cmd == "serve":
+		serve(args)
+	case cli.IsCommand(cmd):
+		if err := cli.Run(cmd, args, os.Stdout); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(2)
+			}
+			fmt.Fprintf(os.Stderr, "todo-service %s: %v\n", cmd, err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: todo-service [command] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	width := len("serve")
+	for _, c := range cli.Commands {
+		width = max(width, len(c.Name))
+	}
+	fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, "serve", "run the server (default)")
+	for _, c := range cli.Commands {
+		fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, c.Name, c.Summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run todo-service <command> -h for a command's flags.")
+}
+
+// serve runs the HTTP and gRPC servers until interrupted.
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrateMode := fs.String("migrate", "up", "migration mode: up (apply pending, then serve), verify (refuse to start with pending migrations), or down (revert to -migrate-to and exit)")
+	migrateTo := fs.Int("migrate-to", 0, "target schema version for -migrate=down")
+	dbBackend := fs.String("db-backend", db.BackendSQLite, "where to store data: sqlite, in "+db.DefaultPath+", or memory, which is lost when the server stops, for demos and tests")
+	rateLimit := fs.Float64("rate-limit", 10, "requests per second allowed per client (0 disables rate limiting)")
+	rateBurst := fs.Int("rate-burst", 20, "requests a client may make in a burst before being rate limited (at least 1)")
+	quotaDaily := fs.Int64("quota-daily", 0, "requests each client may make per UTC day (0 is unlimited)")
+	quotaMonthly := fs.Int64("quota-monthly", 0, "requests each client may make per UTC month (0 is unlimited)")
+	quotaKey := fs.String("quota-key", "ip", "how to tell clients apart when counting requests for quotas and the usage report: ip, by address, or actor, by X-Actor header and otherwise address; clients choose their own X-Actor, so use actor only if they are trusted")
+	archiveAfter := fs.Duration("archive-after", 30*24*time.Hour, "archive todos that have been done for this long (0 disables auto-archiving)")
+	trashRetention := fs.Duration("trash-retention", 30*24*time.Hour, "purge deleted todos from the trash after this long (0 keeps them until purged by hand)")
+	janitorInterval := fs.Duration("janitor-interval", time.Hour, "how often, give or take a tenth, to archive done todos and purge the trash")
+	janitorDryRun := fs.Bool("janitor-dry-run", false, "log the todos -archive-after and -trash-retention would archive and purge instead of doing it")
+	walCheckpointInterval := fs.Duration("wal-checkpoint-interval", time.Minute, "how often to check the size of the database's write-ahead log (0 leaves it to SQLite, which never shrinks it)")
+	walCheckpointSize := fs.Int64("wal-checkpoint-size", 4<<20, "truncate the write-ahead log once it reaches this many bytes, copying its changes into the database file")
+	integrityInterval := fs.Duration("integrity-check-interval", 24*time.Hour, "how often to check the database for corruption, alerting notification routes of corruption events if it is found (0 disables the checks)")
+	grpcAddr := fs.String("grpc-addr", ":9090", "address for the gRPC API, in the same forms as -addr (empty disables it)")
+	maxBodyBytes := fs.Int64("max-body-bytes", 1<<20, "largest request body accepted, in bytes")
+	transitions := fs.String("transitions", model.DefaultTransitions.String(), "comma-separated from->to status changes that updates may make; completing and reopening are always allowed")
+	rules := fs.String("rules", "", `semicolon-separated cross-field rules that created and updated todos must follow, each conditions joined by & then -> and the condition they require, such as "category=work -> due_date; priority>=high -> estimate_minutes"`)
+	wipLimits := fs.String("wip-limits", "", "comma-separated status=limit caps on board columns, such as in_progress=3")
+	addr := fs.String("addr", ":8080", "address for the HTTP API, or for redirecting to HTTPS when TLS is configured: host:port, unix:PATH for a Unix socket, or systemd[:NAME] for a socket-activated one (empty disables it)")
+	tlsAddr := fs.String("tls-addr", ":8443", "address for the HTTPS API when TLS is configured, in the same forms as -addr")
+	socketMode := fs.String("socket-mode", "0660", "permissions, in octal, for Unix sockets the server creates")
+	tlsCert := fs.String("tls-cert", "", "PEM certificate chain file to serve HTTPS with; requires -tls-key")
+	tlsKey := fs.String("tls-key", "", "PEM private key file for -tls-cert")
+	autocertHosts := fs.String("autocert-hosts", "", "comma-separated hostnames to get certificates for automatically from Let's Encrypt; needs -tls-addr on port 443 or -addr on port 80 reachable from the internet")
+	autocertDir := fs.String("autocert-dir", "./data/autocert", "directory to keep automatic certificates in")
+	autocertEmail := fs.String("autocert-email", "", "contact address for certificate expiry notices")
+	mqttBroker := fs.String("mqtt-broker", "", "MQTT broker URL, such as tcp://localhost:1883, to publish TODO events to and take commands from (empty disables MQTT); the password is read from MQTT_PASSWORD")
+	mqttTopic := fs.String("mqtt-topic", "todo-service", "prefix of the MQTT event and command topics")
+	mqttClientID := fs.String("mqtt-client-id", "todo-service", "MQTT client ID")
+	mqttUsername := fs.String("mqtt-username", "", "MQTT username")
+	caldavUser := fs.String("caldav-user", "", "username for the CalDAV server at /caldav/, which serves todos to native task apps, with the password read from CALDAV_PASSWORD (empty disables CalDAV)")
+	githubRepos := fs.String("github-repos", "", "comma-separated owner/name GitHub repositories whose issues to mirror as todos, with the token read from GITHUB_TOKEN (empty disables the GitHub sync)")
+	githubInterval := fs.Duration("github-sync-interval", 5*time.Minute, "how often to sync with GitHub (0 disables scheduled syncs, leaving the sync endpoint)")
+	githubConflicts := fs.String("github-conflicts", string(model.GitHubConflictsNewer), "which side wins when an issue and its todo were both edited since the last sync: github, local, or newer for the one edited last")
+	smtpAddr := fs.String("smtp-addr", "", "SMTP server host:port to email the digest of overdue, soon due, and completed todos through, with the password read from SMTP_PASSWORD (empty disables emailing the digest)")
+	smtpUsername := fs.String("smtp-username", "", "SMTP username (empty sends without authenticating)")
+	smtpFrom := fs.String("smtp-from", "", "sender address of the digest (empty uses the first -digest-to address)")
+	digestTo := fs.String("digest-to", "", "comma-separated addresses to email the digest to")
+	digestSchedule := fs.String("digest-schedule", string(model.ScheduleDaily), "how often to email the digest: daily, at midnight UTC, or weekly, at midnight UTC on Mondays")
+	digestDueSoon := fs.Int("digest-due-soon", 3, "days after today whose due todos the digest lists as due soon")
+	backupDir := fs.String("backup-dir", backup.DefaultDir, "directory to write database backups to")
+	backupInterval := fs.Duration("backup-interval", 0, "back up the database on this interval, aligned to the clock, such as 24h for midnight UTC (0 disables scheduled backups)")
+	backupKeep := fs.Int("backup-keep", 7, "number of newest backups to keep after a scheduled backup (0 keeps all)")
+	sandboxPath := fs.String("sandbox", "", "path of a sandbox database, reset to sample data on every start and every -sandbox-reset, that requests with the X-Sandbox: true header use instead of the real data (empty disables the sandbox)")
+	sandboxReset := fs.Duration("sandbox-reset", time.Hour, "how often to reset the sandbox, aligned to the clock")
+	readOnly := fs.Bool("read-only", false, "start in read-only maintenance mode, refusing changes except admin operations until it is turned off through the API")
+	cache := fs.String("cache", "/api/v1/board=10s,/api/v1/reports/=1m,/api/v1/week=30s", "comma-separated path-prefix=ttl pairs of reads to cache in memory until any change is made (empty disables caching)")
+	cacheStale := fs.Duration("cache-stale", time.Minute, "how long past its TTL a cached read may still be served while it is refreshed in the background")
+	readCacheSize := fs.Int("read-cache-size", 1000, "number of TODO reads, single TODOs and list pages, to keep in memory; any change discards them (0 disables the cache)")
+	readCacheTTL := fs.Duration("read-cache-ttl", 30*time.Second, "longest a TODO read is kept, bounding how long changes made by other processes to the database go unseen")
+	undoDepth := fs.Int("undo-depth", db.DefaultUndoDepth, "number of each actor's most recent operations POST /api/v1/undo can reverse (0 disables undo)")
+	undoWindow := fs.Duration("undo-window", db.DefaultUndoWindow, "how long after an operation it can still be undone")
+	logFormat := fs.String("log-format", logger.FormatJSON, "field names of the JSON log file: json, or ecs for Elastic Common Schema")
+	accessLogSample := fs.String("access-log-sample", "", `comma-separated route=n rules logging only one of every n successful requests to a route, or none if n is 0, such as "/healthz=0,/metrics=0,OPTIONS=100"; a route is a path, a path ending in /* for every path under it, or OPTIONS for CORS preflights, and failed requests are always logged`)
+	logShip := fs.String("log-ship", "", "URL to ship logs to as well as the log file, such as http://loki:3100/loki/api/v1/push, which may include basic auth credentials, udp://syslog:514, or tcp://fluentd:5170 (empty disables shipping)")
+	logShipProtocol := fs.String("log-ship-protocol", logger.ProtocolLoki, "protocol of -log-ship: loki for a Loki push endpoint, otlp for an OTLP/HTTP logs endpoint, syslog for a syslog server over udp or tcp, or tcp for JSON lines to a TCP listener")
+	logShipBuffer := fs.Int("log-ship-buffer", 10000, "log records to hold while -log-ship is slow or down; more are dropped")
+	slowQuery := fs.Duration("slow-query", 100*time.Millisecond, "log SQL statements that take at least this long as warnings (0 disables the warning)")
+	fs.Parse(args)
+
 	// Logger
 	logCfg := logger.DefaultConfig()
+	logCfg.Format = *logFormat
+	if *logShip != "" {
+		shipper, err := logger.NewShipper(logger.ShipConfig{URL: *logShip, Protocol: *logShipProtocol, Buffer: *logShipBuffer})
+		if err != nil {
+			slog.Error("invalid -log-ship", slog.String("error", err.Error()))
+			os.Exit(2)
+		}
+		logCfg.Ship = shipper
+	}
 	log, logCloser := logger.New(logCfg)
 	defer logCloser.Close()
 	slog.SetDefault(log)
+	if *logFormat != logger.FormatJSON && *logFormat != logger.FormatECS {
+		log.Error("invalid -log-format", slog.String("log_format", *logFormat))
+		os.Exit(2)
+	}
+
+	// A burst below 1 would reject every request rather than limit them.
+	if *rateLimit > 0 && *rateBurst < 1 {
+		log.Error("invalid -rate-burst", slog.Int("rate_burst", *rateBurst))
+		os.Exit(2)
+	}
+
+	allowed, err := model.ParseTransitions(*transitions)
+	if err != nil {
+		log.Error("invalid -transitions", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	todoRules, err := model.ParseRules(*rules)
+	if err != nil {
+		log.Error("invalid -rules", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	limits, err := model.ParseWIPLimits(*wipLimits)
+	if err != nil {
+		log.Error("invalid -wip-limits", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	accessLogSampling, err := middleware.ParseAccessLogSampling(*accessLogSample)
+	if err != nil {
+		log.Error("invalid -access-log-sample", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	quotaKeyFunc, err := middleware.ParseQuotaKey(*quotaKey)
+	if err != nil {
+		log.Error("invalid -quota-key", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	cacheRules, err := middleware.ParseCacheRules(*cache)
+	if err != nil {
+		log.Error("invalid -cache", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	caldavPassword := os.Getenv("CALDAV_PASSWORD")
+	if *caldavUser != "" && caldavPassword == "" {
+		log.Error("-caldav-user requires CALDAV_PASSWORD to be set")
+		os.Exit(2)
+	}
+	syncRepos, err := github.ParseRepos(*githubRepos)
+	if err != nil {
+		log.Error("invalid -github-repos", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+	if !model.ValidGitHubConflicts[model.GitHubConflicts(*githubConflicts)] {
+		log.Error("invalid -github-conflicts", slog.String("github_conflicts", *githubConflicts))
+		os.Exit(2)
+	}
+	digestCfg := digest.Config{
+		Addr:        *smtpAddr,
+		Username:    *smtpUsername,
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		Schedule:    model.SubscriptionSchedule(*digestSchedule),
+		DueSoonDays: *digestDueSoon,
+	}
+	if !model.ValidSubscriptionSchedules[digestCfg.Schedule] {
+		log.Error("invalid -digest-schedule", slog.String("digest_schedule", *digestSchedule))
+		os.Exit(2)
+	}
+	if *digestDueSoon < 0 {
+		log.Error("invalid -digest-due-soon", slog.Int("digest_due_soon", *digestDueSoon))
+		os.Exit(2)
+	}
+	if *smtpFrom != "" {
+		from, err := mail.ParseAddress(*smtpFrom)
+		if err != nil {
+			log.Error("invalid -smtp-from", slog.String("error", err.Error()))
+			os.Exit(2)
+		}
+		digestCfg.From = from.Address
+	}
+	if *digestTo != "" {
+		to, err := mail.ParseAddressList(*digestTo)
+		if err != nil {
+			log.Error("invalid -digest-to", slog.String("error", err.Error()))
+			os.Exit(2)
+		}
+		for _, a := range to {
+			digestCfg.To = append(digestCfg.To, a.Address)
+		}
+	}
+	if (*smtpAddr != "") != (len(digestCfg.To) > 0) {
+		log.Error("-smtp-addr and -digest-to must be set together")
+		os.Exit(2)
+	}
+	m, err := strconv.ParseUint(*socketMode, 8, 32)
+	if err != nil || m > 0o777 {
+		log.Error("invalid -socket-mode", slog.String("socket_mode", *socketMode))
+		os.Exit(2)
+	}
+	mode := os.FileMode(m)
+	tlsCfg := tlsconf.Config{
+		CertFile: *tlsCert,
+		KeyFile:  *tlsKey,
+		CacheDir: *autocertDir,
+		Email:    *autocertEmail,
+	}
+	for host := range strings.SplitSeq(*autocertHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			tlsCfg.Hosts = append(tlsCfg.Hosts, host)
+		}
+	}
 
 	// Database
-	repo, err := db.New("./data/todos.db", log)
+	repo, err := db.OpenBackend(*dbBackend, db.DefaultPath, log)
 	if err != nil {
 		log.Error("failed to initialize database", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 	defer repo.Close()
+	repo.SetTransitions(allowed)
+	repo.SetRules(todoRules)
+	repo.SetSlowQueryThreshold(*slowQuery)
+	repo.SetReadCache(*readCacheSize, *readCacheTTL)
+	repo.SetUndo(*undoDepth, *undoWindow)
+	repo.SetTrashRetention(*trashRetention)
+
+	switch *migrateMode {
+	case "up":
+		err = repo.Migrate()
+	case "verify":
+		err = repo.VerifyMigrations()
+	case "down":
+		if err := repo.MigrateDown(*migrateTo); err != nil {
+			log.Error("failed to revert migrations", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		log.Info("migrations reverted", slog.Int("version", *migrateTo))
+		return
+	default:
+		log.Error("invalid -migrate mode", slog.String("mode", *migrateMode))
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Error("failed to migrate database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Background jobs
+	backups := backup.NewStore(repo, *backupDir)
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var janitor *jobs.Janitor
+	if *archiveAfter > 0 || *trashRetention > 0 {
+		retention := jobs.Retention{ArchiveAfter: *archiveAfter, PurgeAfter: *trashRetention}
+		janitor = jobs.NewJanitor(repo, log, retention, *janitorInterval, *janitorDryRun)
+		go janitor.Run(jobCtx)
+	}
+	if *backupInterval > 0 {
+		go jobs.NewAutoBackup(backups, log, *backupInterval, *backupKeep).Run(jobCtx)
+	}
+	go jobs.NewSnoozeWaker(repo, log, time.Minute).Run(jobCtx)
+	go jobs.NewUndoPruner(repo, log, time.Minute).Run(jobCtx)
+	quotas, err := quota.New(repo, model.QuotaLimits{Daily: *quotaDaily, Monthly: *quotaMonthly}, time.Now())
+	if err != nil {
+		log.Error("failed to load request counts", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	go jobs.NewQuotaFlusher(quotas, log, 10*time.Second).Run(jobCtx)
+	deliverer := delivery.New(repo)
+	go jobs.NewReportSender(repo, deliverer, log, time.Minute).Run(jobCtx)
+	notifier := notify.New(repo)
+	go jobs.NewNotifications(repo, notifier, log, 10*time.Second).Run(jobCtx)
+	if *walCheckpointInterval > 0 {
+		go jobs.NewWALCheckpointer(repo, log, *walCheckpointInterval, *walCheckpointSize).Run(jobCtx)
+	}
+	if *integrityInterval > 0 {
+		go jobs.NewIntegrityChecker(repo, notifier, log, *integrityInterval).Run(jobCtx)
+	}
+	mailer := digest.New(repo, digestCfg)
+	if digestCfg.Enabled() {
+		go jobs.NewDigest(repo, mailer, log).Run(jobCtx)
+	}
+	var githubSyncer *github.Syncer
+	if len(syncRepos) > 0 {
+		client := github.NewClient(github.APIURL, os.Getenv("GITHUB_TOKEN"))
+		githubSyncer = github.NewSyncer(repo, client, syncRepos, model.GitHubConflicts(*githubConflicts))
+		if *githubInterval > 0 {
+			go jobs.NewGitHubSync(githubSyncer, log, *githubInterval).Run(jobCtx)
+		}
+	}
+	// Read-only maintenance mode covers HTTP, gRPC, and MQTT changes.
+	maintenance := &middleware.Maintenance{}
+	if *readOnly {
+		maintenance.Set(model.MaintenanceRequest{ReadOnly: true, RetryAfterSeconds: 300})
+	}
+
+	if *mqttBroker != "" {
+		go mqttbridge.New(repo, maintenance, log, mqttbridge.Config{
+			Broker:   *mqttBroker,
+			ClientID: *mqttClientID,
+			Username: *mqttUsername,
+			Password: os.Getenv("MQTT_PASSWORD"),
+			Topic:    *mqttTopic,
+		}).Run(jobCtx)
+	}
 
 	// Router with middleware
 	router := chi.NewMux()
 	router.Use(chimw.RequestID)
+	router.Use(middleware.ServerTiming(log))
 	router.Use(chimw.RealIP)
-	router.Use(middleware.RequestLogger(log))
+	router.Use(middleware.Actor())
+	router.Use(middleware.RequestLogger(log, accessLogSampling...))
 	router.Use(middleware.Recovery(log))
 	router.Use(middleware.CORS())
+	if *rateLimit > 0 {
+		limit := ratelimit.Limit{Rate: *rateLimit, Burst: *rateBurst}
+		router.Use(middleware.RateLimit(ratelimit.NewMemoryStore(), limit, middleware.ClientIP, log, "/healthz", "/metrics"))
+	}
+	router.Use(middleware.Quota(quotas, quotaKeyFunc, "/healthz", "/metrics"))
+	router.Use(middleware.MaxBodySize(*maxBodyBytes))
+	router.Use(chimw.Compress(5, "application/json", "application/problem+json", "application/openapi+yaml", "text/html", "text/plain"))
 	router.Use(chimw.Timeout(30 * time.Second))
+	router.Use(middleware.TimingCheckpoint())
+
+	// The sandbox serves the same API, less backups, configuration, usage,
+	// report subscriptions, the GitHub sync, notification routes, the email
+	// digest, and share links, on its own dataset.
+	if *sandboxPath != "" {
+		sb, err := sandbox.Open(*sandboxPath, log)
+		if err != nil {
+			log.Error("failed to open sandbox", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer sb.Close()
+		sb.Repository().SetTransitions(allowed)
+		sb.Repository().SetRules(todoRules)
+		sb.Repository().SetSlowQueryThreshold(*slowQuery)
+		sb.Repository().SetReadCache(*readCacheSize, *readCacheTTL)
+		sb.Repository().SetUndo(*undoDepth, *undoWindow)
+		go sb.Run(jobCtx, *sandboxReset)
+		go jobs.NewSnoozeWaker(sb.Repository(), log, time.Minute).Run(jobCtx)
+		go jobs.NewUndoPruner(sb.Repository(), log, time.Minute).Run(jobCtx)
+
+		sandboxRouter := chi.NewMux()
+		registerTodoRoutes(newAPI(sandboxRouter, *maxBodyBytes), sb.Repository(), log, limits)
+		router.Use(middleware.Sandbox(sandboxRouter))
+	}
+
+	// Maintenance mode protects the real data only, so it comes after the
+	// sandbox.
+	router.Use(middleware.ReadOnly(maintenance, "/api/v1/admin/"))
+
+	// Every write bumps the repository's generation, discarding the cache.
+	if len(cacheRules) > 0 {
+		router.Use(middleware.Cache(cacheRules, *cacheStale, repo.WriteGeneration))
+	}
 
 	// Health check (plain chi route, outside huma)
 	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"status":"ok"}`))
 	})
+	router.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		metrics := model.MetricsResponse{Queries: repo.QueryStats(), Cache: repo.CacheStats()}
+		if logCfg.Ship != nil {
+			stats := logCfg.Ship.Stats()
+			metrics.Logs = &stats
+		}
+		if janitor != nil {
+			stats := janitor.Stats()
+			metrics.Janitor = &stats
+		}
+		json.NewEncoder(w).Encode(metrics)
+	})
+
+	// CalDAV (plain chi routes, outside huma)
+	if *caldavUser != "" {
+		caldav.New(repo, log, *caldavUser, caldavPassword).RegisterRoutes(router)
+	}
 
 	// Huma API (OpenAPI 3.1)
-	config := huma.DefaultConfig("TODO Service API", "1.0.0")
-	config.Info.Description = "A local TODO API service with progress tracking."
-	api := humachi.New(router, config)
+	api := newAPI(router, *maxBodyBytes)
+	registerTodoRoutes(api, repo, log, limits)
+	configHandler := handler.NewConfigHandler(repo, log)
+	configHandler.RegisterRoutes(api)
+	usageHandler := handler.NewUsageHandler(repo, quotas, log)
+	usageHandler.RegisterRoutes(api)
+	backupHandler := handler.NewBackupHandler(backups, log)
+	backupHandler.RegisterRoutes(api)
+	subscriptionHandler := handler.NewSubscriptionHandler(repo, deliverer, log)
+	subscriptionHandler.RegisterRoutes(api)
+	githubHandler := handler.NewGitHubHandler(githubSyncer, log)
+	githubHandler.RegisterRoutes(api)
+	notificationHandler := handler.NewNotificationHandler(repo, notifier, log)
+	notificationHandler.RegisterRoutes(api)
+	digestHandler := handler.NewDigestHandler(mailer, log)
+	digestHandler.RegisterRoutes(api)
+	shareHandler := handler.NewShareHandler(repo, maintenance, log)
+	shareHandler.RegisterRoutes(api)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenance, log)
+	maintenanceHandler.RegisterRoutes(api)
+	databaseHandler := handler.NewDatabaseHandler(repo, maintenance, log)
+	databaseHandler.RegisterRoutes(api)
 
-	// Register routes
-	todoHandler := handler.NewTodoHandler(repo, log)
-	todoHandler.RegisterRoutes(api)
+	// Servers with graceful shutdown. With TLS, the API is served over
+	// HTTPS (and HTTP/2) and the plain HTTP address only redirects to it.
+	var servers []*http.Server
+	serveOn := func(addr string, handler http.Handler, cfg *tls.Config, msg string) {
+		ln, err := listen.Listen(addr, mode)
+		if err != nil {
+			log.Error("failed to listen", slog.String("addr", addr), slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		srv := &http.Server{Handler: handler, TLSConfig: cfg, ReadHeaderTimeout: 10 * time.Second}
+		servers = append(servers, srv)
 
-	// Server with graceful shutdown
-	addr := ":8080"
-	srv := &http.Server{Addr: addr, Handler: router}
+		attrs := []any{slog.String("addr", addr)}
+		if handler == router && listen.IsTCP(addr) {
+			scheme := "http"
+			if cfg != nil {
+				scheme = "https"
+			}
+			attrs = append(attrs, slog.String("docs", scheme+"://"+localAddr(addr)+"/docs"))
+		}
+		go func() {
+			log.Info(msg, attrs...)
+			var err error
+			if cfg != nil {
+				err = srv.ServeTLS(ln, "", "")
+			} else {
+				err = srv.Serve(ln)
+			}
+			if err != nil && err != http.ErrServerClosed {
+				log.Error("server error", slog.String("addr", addr), slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+		}()
+	}
+	if tlsCfg.Enabled() {
+		cfg, redirect, err := tlsCfg.Setup(*tlsAddr)
+		if err != nil {
+			log.Error("invalid TLS configuration", slog.String("error", err.Error()))
+			os.Exit(2)
+		}
+		serveOn(*tlsAddr, router, cfg, "server starting")
+		if *addr != "" {
+			serveOn(*addr, redirect, nil, "HTTPS redirect starting")
+		}
+	} else if *addr != "" {
+		serveOn(*addr, router, nil, "server starting")
+	}
 
-	go func() {
-		log.Info("server starting", slog.String("addr", addr), slog.String("docs", "http://localhost:8080/docs"))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error("server error", slog.String("error", err.Error()))
+	var grpcSrv *grpcapi.Server
+	if *grpcAddr != "" {
+		lis, err := listen.Listen(*grpcAddr, mode)
+		if err != nil {
+			log.Error("failed to listen for gRPC", slog.String("addr", *grpcAddr), slog.String("error", err.Error()))
 			os.Exit(1)
 		}
-	}()
+		grpcSrv = grpcapi.New(repo, maintenance, log)
+
+		go func() {
+			log.Info("gRPC server starting", slog.String("addr", *grpcAddr))
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Error("gRPC server error", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+		}()
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info("shutting down server")
+	stopJobs()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	srv.Shutdown(ctx)
+	for _, srv := range servers {
+		srv.Shutdown(ctx)
+	}
+	if grpcSrv != nil {
+		grpcSrv.Shutdown(ctx)
+	}
+	if err := quotas.Flush(); err != nil {
+		log.Error("failed to save request counts", slog.String("error", err.Error()))
+	}
 	log.Info("server stopped")
 }
+
+// newAPI mounts a huma API on router. Its operations accept bodies of up to
+// maxBodyBytes, matching the MaxBodySize middleware.
+func newAPI(router chi.Router, maxBodyBytes int64) huma.API {
+	huma.NewError = handler.NewError
+	config := huma.DefaultConfig("TODO Service API", "1.0.0")
+	config.Info.Description = "A local TODO API service with progress tracking.\n\nEvery operation may respond 429 Too Many Requests when the client exceeds the server's rate limit or uses up its daily or monthly request quota, if they are set, and 500 Internal Server Error on unexpected failures. Changes respond 503 Service Unavailable while the server is read-only for maintenance. Errors are returned as application/problem+json with a code, listed below, to branch on instead of the message." + errorCatalog() + "\n\nIf the server has a sandbox, requests with the X-Sandbox: true header work on sample data instead, which is reset periodically; the backup, configuration, usage, report subscription, GitHub sync, notification route, email digest, and sharing operations are not available there."
+	config.OpenAPI.Tags = handler.Tags
+	config.Formats = map[string]huma.Format{
+		"application/json": handler.StreamingJSONFormat(handler.StreamThreshold),
+		"json":             handler.StreamingJSONFormat(handler.StreamThreshold),
+	}
+	api := humachi.New(router, config)
+
+	routes := huma.NewGroup(api)
+	routes.UseModifier(func(op *huma.Operation, next func(*huma.Operation)) {
+		op.MaxBodyBytes = maxBodyBytes
+		next(op)
+	})
+	return routes
+}
+
+// errorCatalog documents the error codes as a Markdown table.
+func errorCatalog() string {
+	var b strings.Builder
+	b.WriteString("\n\n| Code | Status | Meaning |\n| --- | --- | --- |\n")
+	for _, c := range model.ErrorCodes {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", c.Code, c.Status, c.Description)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// registerTodoRoutes registers the operations on repo's data that the
+// sandbox serves too: all but backups, configuration, usage, report
+// subscriptions, the GitHub sync, notification routes, the email digest,
+// and share links, whose public URLs carry no sandbox header.
+func registerTodoRoutes(api huma.API, repo db.Repository, log *slog.Logger, limits model.WIPLimits) {
+	todoHandler := handler.NewTodoHandler(repo, log)
+	todoHandler.RegisterRoutes(api)
+	projectHandler := handler.NewProjectHandler(repo, log)
+	projectHandler.RegisterRoutes(api)
+	categoryHandler := handler.NewCategoryHandler(repo, log)
+	categoryHandler.RegisterRoutes(api)
+	customFieldHandler := handler.NewCustomFieldHandler(repo, log)
+	customFieldHandler.RegisterRoutes(api)
+	undoHandler := handler.NewUndoHandler(repo, log)
+	undoHandler.RegisterRoutes(api)
+	trashHandler := handler.NewTrashHandler(repo, log)
+	trashHandler.RegisterRoutes(api)
+	viewHandler := handler.NewViewHandler(repo, log)
+	viewHandler.RegisterRoutes(api)
+	boardHandler := handler.NewBoardHandler(repo, log, limits)
+	boardHandler.RegisterRoutes(api)
+	actionHandler := handler.NewActionHandler(repo, log)
+	actionHandler.RegisterRoutes(api)
+	triggerHandler := handler.NewTriggerHandler(repo, log)
+	triggerHandler.RegisterRoutes(api)
+	auditHandler := handler.NewAuditHandler(repo, log)
+	auditHandler.RegisterRoutes(api)
+	matrixHandler := handler.NewMatrixHandler(repo, log)
+	matrixHandler.RegisterRoutes(api)
+	changeHandler := handler.NewChangeHandler(repo, log)
+	changeHandler.RegisterRoutes(api)
+	syncHandler := handler.NewSyncHandler(repo, log)
+	syncHandler.RegisterRoutes(api)
+	weekHandler := handler.NewWeekHandler(repo, log)
+	weekHandler.RegisterRoutes(api)
+	calendarHandler := handler.NewCalendarHandler(repo, log)
+	calendarHandler.RegisterRoutes(api)
+	agendaHandler := handler.NewAgendaHandler(repo, log)
+	agendaHandler.RegisterRoutes(api)
+	nextHandler := handler.NewNextHandler(repo, log)
+	nextHandler.RegisterRoutes(api)
+	inboxHandler := handler.NewInboxHandler(repo, log)
+	inboxHandler.RegisterRoutes(api)
+	reportHandler := handler.NewReportHandler(repo, log)
+	reportHandler.RegisterRoutes(api)
+	importHandler := handler.NewImportHandler(repo, todoist.NewClient(todoist.APIURL), log)
+	importHandler.RegisterRoutes(api)
+}
+
+// localAddr returns addr with an empty host replaced by localhost, for
+// logging a URL to open.
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host != "" {
+		return addr
+	}
+	return net.JoinHostPort("localhost", port)
+}